@@ -237,10 +237,10 @@ func (b *hashBuilder) ftype(structName, s string, expr ast.Expr, directives map[
 		return "uint64(" + s + ".Uint8())", 5
 	case "GrindstoneAttachment":
 		return "uint64(" + s + ".Uint8())", 2
-	case "WoodType", "FlowerType", "DoubleFlowerType", "Colour":
+	case "WoodType", "FlowerType", "DoubleFlowerType", "Colour", "ButtonType", "PressurePlateType", "RailShape":
 		// Assuming these were all based on metadata, it should be safe to assume a bit size of 4 for this.
 		return "uint64(" + s + ".Uint8())", 4
-	case "CoralType":
+	case "CoralType", "StructureBlockMode":
 		return "uint64(" + s + ".Uint8())", 3
 	case "AnvilType", "SandstoneType", "PrismarineType", "StoneBricksType", "NetherBricksType", "FroglightType", "WallConnectionType", "BlackstoneType", "DeepslateType", "TallGrassType":
 		return "uint64(" + s + ".Uint8())", 2
@@ -9,6 +9,7 @@ require (
 	github.com/df-mc/worldupgrader v1.0.14
 	github.com/go-gl/mathgl v1.1.0
 	github.com/google/uuid v1.6.0
+	github.com/mattn/go-sqlite3 v1.14.22
 	github.com/pelletier/go-toml v1.9.5
 	github.com/rogpeppe/go-internal v1.12.0
 	github.com/sandertv/gophertunnel v1.37.0
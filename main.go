@@ -1,8 +1,12 @@
 package main
 
-import "github.com/stcraft/dragonfly/server"
+import (
+	"github.com/stcraft/dragonfly/server"
+	"github.com/stcraft/dragonfly/server/cmd/vanilla"
+)
 
 func main() {
 	srv, _ := server.New()
+	vanilla.Register(srv, srv.Permissions())
 	srv.Start()
 }
@@ -0,0 +1,92 @@
+package block
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// ActivatorRail is a rail that activates minecarts travelling along it while it is powered. Activated minecarts
+// eject their riders and TNT minecarts are ignited. Activator rails carry power to up to 8 connected activator
+// rails.
+type ActivatorRail struct {
+	transparent
+	empty
+
+	// Shape is the shape of the rail. Activator rails cannot be curved.
+	Shape RailShape
+	// Powered is true if the rail is powered by redstone.
+	Powered bool
+}
+
+// RailShape ...
+func (r ActivatorRail) RailShape() RailShape {
+	return r.Shape
+}
+
+// MinecartPosition ...
+func (r ActivatorRail) MinecartPosition(pos cube.Pos) mgl64.Vec3 {
+	return railMinecartPosition(pos, r.Shape)
+}
+
+// withRailShape ...
+func (r ActivatorRail) withRailShape(s RailShape) railBlock {
+	r.Shape = s
+	return r
+}
+
+// UseOnBlock ...
+func (r ActivatorRail) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) (used bool) {
+	pos, _, used = firstReplaceable(w, pos, face, r)
+	if !used || !railSupported(pos, w) {
+		return false
+	}
+	r.Shape = railShapeFor(pos, r, w)
+	r.Powered = railPowered(pos, r, w)
+	return placeRail(pos, r, w, user, ctx)
+}
+
+// NeighbourUpdateTick ...
+func (r ActivatorRail) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if breakUnsupportedRail(pos, r, w) {
+		return
+	}
+	if powered := railPowered(pos, r, w); powered != r.Powered {
+		r.Powered = powered
+		w.SetBlock(pos, r, nil)
+	}
+}
+
+// HasLiquidDrops ...
+func (ActivatorRail) HasLiquidDrops() bool {
+	return true
+}
+
+// SideClosed ...
+func (ActivatorRail) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
+	return false
+}
+
+// BreakInfo ...
+func (r ActivatorRail) BreakInfo() BreakInfo {
+	return newBreakInfo(0.7, alwaysHarvestable, pickaxeEffective, oneOf(ActivatorRail{}))
+}
+
+// EncodeItem ...
+func (ActivatorRail) EncodeItem() (name string, meta int16) {
+	return "minecraft:activator_rail", 0
+}
+
+// EncodeBlock ...
+func (r ActivatorRail) EncodeBlock() (string, map[string]any) {
+	return "minecraft:activator_rail", map[string]any{"rail_direction": int32(r.Shape.Uint8()), "rail_data_bit": boolByte(r.Powered)}
+}
+
+// allActivatorRails ...
+func allActivatorRails() (rails []world.Block) {
+	for _, s := range StraightRailShapes() {
+		rails = append(rails, ActivatorRail{Shape: s}, ActivatorRail{Shape: s, Powered: true})
+	}
+	return
+}
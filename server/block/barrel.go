@@ -56,6 +56,11 @@ func (b Barrel) Inventory() *inventory.Inventory {
 	return b.inventory
 }
 
+// Changed returns true if the inventory of the barrel changed since the last call to Changed.
+func (b Barrel) Changed() bool {
+	return b.inventory != nil && b.inventory.Changed()
+}
+
 // WithName returns the barrel after applying a specific name to the block.
 func (b Barrel) WithName(a ...any) world.Item {
 	b.CustomName = strings.TrimSuffix(fmt.Sprintln(a...), "\n")
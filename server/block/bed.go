@@ -0,0 +1,199 @@
+package block
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/google/uuid"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/block/model"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/particle"
+)
+
+// Bed is a two block long block that players can sleep in to skip the night and to set their spawn point.
+// Beds explode when used outside the overworld.
+type Bed struct {
+	transparent
+	sourceWaterDisplacer
+
+	// Colour is the colour of the bed.
+	Colour item.Colour
+	// Facing is the direction that the bed is facing, from the foot towards the head.
+	Facing cube.Direction
+	// Head specifies if the bed block is the head of the bed. If false, it is the foot.
+	Head bool
+	// Occupied specifies if an entity is currently sleeping in the bed.
+	Occupied bool
+}
+
+// bedSleeper represents an entity that can sleep in a bed.
+type bedSleeper interface {
+	world.Sleeper
+	UUID() uuid.UUID
+	Sleep(pos cube.Pos)
+	Message(a ...any)
+}
+
+const (
+	// bedNightStart and bedNightEnd are the times of the day between which it is possible to sleep in a bed in
+	// clear weather.
+	bedNightStart, bedNightEnd = 12542, 23459
+	// bedReach is the maximum distance from the head of a bed at which an entity may sleep in the bed.
+	bedReach = 3
+)
+
+// Model ...
+func (Bed) Model() world.BlockModel {
+	return model.Bed{}
+}
+
+// OtherHalf returns the position of the other half of the bed at the position passed. If b is the head of the
+// bed, the position of the foot is returned and vice versa.
+func (b Bed) OtherHalf(pos cube.Pos) cube.Pos {
+	if b.Head {
+		return pos.Side(b.Facing.Opposite().Face())
+	}
+	return pos.Side(b.Facing.Face())
+}
+
+// UseOnBlock ...
+func (b Bed) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) (used bool) {
+	if pos, _, used = firstReplaceable(w, pos, face, b); !used {
+		return
+	}
+	b.Facing, b.Head, b.Occupied = user.Rotation().Direction(), false, false
+	head := b.OtherHalf(pos)
+	if !replaceableWith(w, head, b) || !bedSupported(pos, w) || !bedSupported(head, w) {
+		return false
+	}
+
+	place(w, pos, b, user, ctx)
+	if placed(ctx) {
+		b.Head = true
+		w.SetBlock(head, b, nil)
+	}
+	return placed(ctx)
+}
+
+// bedSupported checks if a bed half may be placed at the position passed.
+func bedSupported(pos cube.Pos, w *world.World) bool {
+	below := pos.Side(cube.FaceDown)
+	return w.Block(below).Model().FaceSolid(below, cube.FaceUp, w)
+}
+
+// Activate ...
+func (b Bed) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.User, _ *item.UseContext) bool {
+	s, ok := u.(bedSleeper)
+	if !ok {
+		return false
+	}
+	head := pos
+	if !b.Head {
+		head = b.OtherHalf(pos)
+	}
+	headBed, ok := w.Block(head).(Bed)
+	if !ok {
+		return false
+	}
+	if w.Dimension() != world.Overworld {
+		// Beds explode when trying to sleep in them outside the overworld.
+		w.SetBlock(pos, nil, nil)
+		w.SetBlock(b.OtherHalf(pos), nil, nil)
+		ExplosionConfig{Size: 5, SpawnFire: true}.Explode(w, head.Vec3Centre())
+		return true
+	}
+	if _, sleeping := s.Sleeping(); sleeping {
+		return false
+	}
+	if s.Position().Sub(head.Vec3Middle()).Len() > bedReach {
+		s.Message("You may not rest now; the bed is too far away")
+		return true
+	}
+	w.SetPlayerSpawn(s.UUID(), head)
+	s.Message("Respawn point set")
+
+	if headBed.Occupied {
+		s.Message("This bed is occupied")
+		return true
+	}
+	if t := w.Time() % 24000; (t < bedNightStart || t > bedNightEnd) && !w.Thundering() {
+		s.Message("You can only sleep at night and during thunderstorms")
+		return true
+	}
+	if bedMonstersNearby(head, w) {
+		s.Message("You may not rest now; there are monsters nearby")
+		return true
+	}
+	s.Sleep(head)
+	return true
+}
+
+// bedMonstersNearby checks if there are any monsters near the bed at the position passed that prevent entities
+// from sleeping in it.
+func bedMonstersNearby(pos cube.Pos, w *world.World) bool {
+	box := cube.Box(-8, -5, -8, 9, 6, 9).Translate(pos.Vec3())
+	return len(w.EntitiesWithin(box, func(e world.Entity) bool {
+		n, ok := e.(world.NaturalEntity)
+		return !ok || n.SpawnCategory() != world.SpawnCategoryMonster
+	})) > 0
+}
+
+// EntityLand ...
+func (Bed) EntityLand(_ cube.Pos, _ *world.World, _ world.Entity, distance *float64) {
+	*distance *= 0.5
+}
+
+// NeighbourUpdateTick ...
+func (b Bed) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if _, ok := w.Block(b.OtherHalf(pos)).(Bed); !ok {
+		w.SetBlock(pos, nil, nil)
+		w.AddParticle(pos.Vec3Centre(), particle.BlockBreak{Block: b})
+	}
+}
+
+// BreakInfo ...
+func (b Bed) BreakInfo() BreakInfo {
+	return newBreakInfo(0.2, alwaysHarvestable, nothingEffective, oneOf(Bed{Colour: b.Colour}))
+}
+
+// PistonBreakable ...
+func (Bed) PistonBreakable() bool {
+	return true
+}
+
+// EncodeItem ...
+func (b Bed) EncodeItem() (name string, meta int16) {
+	return "minecraft:bed", int16(b.Colour.Uint8())
+}
+
+// EncodeBlock ...
+func (b Bed) EncodeBlock() (name string, properties map[string]any) {
+	return "minecraft:bed", map[string]any{
+		"direction":      int32(horizontalDirection(b.Facing)),
+		"head_piece_bit": b.Head,
+		"occupied_bit":   b.Occupied,
+	}
+}
+
+// EncodeNBT ...
+func (b Bed) EncodeNBT() map[string]any {
+	return map[string]any{"id": "Bed", "color": b.Colour.Uint8()}
+}
+
+// DecodeNBT ...
+func (b Bed) DecodeNBT(m map[string]any) any {
+	b.Colour = item.Colours()[nbtconv.Uint8(m, "color")%16]
+	return b
+}
+
+// allBeds ...
+func allBeds() (beds []world.Block) {
+	for _, d := range cube.Directions() {
+		beds = append(beds, Bed{Facing: d})
+		beds = append(beds, Bed{Facing: d, Head: true})
+		beds = append(beds, Bed{Facing: d, Occupied: true})
+		beds = append(beds, Bed{Facing: d, Head: true, Occupied: true})
+	}
+	return
+}
@@ -0,0 +1,212 @@
+package block
+
+import (
+	"sync"
+	"time"
+
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/event"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/item/inventory"
+	"github.com/stcraft/dragonfly/server/item/recipe"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/sound"
+)
+
+const (
+	// brewerIngredientSlot is the slot of the brewer inventory that holds the ingredient.
+	brewerIngredientSlot = 0
+	// brewerFuelSlot is the slot of the brewer inventory that holds the fuel. The slots between the ingredient
+	// slot and the fuel slot hold the potions being brewed.
+	brewerFuelSlot = 4
+
+	// brewerDuration is the time it takes for a brewer to brew potions.
+	brewerDuration = time.Second * 20
+	// brewerFuelAmount is the amount of brews that a single piece of fuel lasts for.
+	brewerFuelAmount = 20
+)
+
+// brewer is a struct that may be embedded by blocks that can brew potions, such as brewing stands.
+type brewer struct {
+	mu sync.Mutex
+
+	viewers map[ContainerViewer]struct{}
+	// synced holds the viewers that have been sent the brewing progress of the brewer.
+	synced    map[ContainerViewer]struct{}
+	inventory *inventory.Inventory
+
+	duration   time.Duration
+	fuelAmount int32
+	fuelTotal  int32
+	// ingredient is the ingredient that the brewer started brewing with. Brewing stops if it is changed.
+	ingredient item.Stack
+	// changed specifies if the duration or fuel of the brewer changed since Changed was last called.
+	changed bool
+}
+
+// newBrewer creates a new initialised brewer and returns it.
+func newBrewer() *brewer {
+	b := &brewer{viewers: make(map[ContainerViewer]struct{}), synced: make(map[ContainerViewer]struct{})}
+	b.inventory = inventory.New(5, func(slot int, _, item item.Stack) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for viewer := range b.viewers {
+			viewer.ViewSlotChange(slot, item)
+		}
+	})
+	return b
+}
+
+// Duration returns the remaining duration of brewing.
+func (b *brewer) Duration() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.duration
+}
+
+// Fuel returns the amount of brews left with the fuel currently in the brewer and the amount of brews that the
+// fuel lasted for in total.
+func (b *brewer) Fuel() (fuelAmount, fuelTotal int32) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.fuelAmount, b.fuelTotal
+}
+
+// Inventory returns the inventory of the brewer.
+func (b *brewer) Inventory() *inventory.Inventory {
+	return b.inventory
+}
+
+// Changed returns true if the inventory, duration or fuel of the brewer changed since the last call to Changed.
+func (b *brewer) Changed() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	changed := b.changed
+	b.changed = false
+	return b.inventory.Changed() || changed
+}
+
+// AddViewer adds a viewer to the brewer, so that it is updated whenever the inventory of the brewer is changed.
+func (b *brewer) AddViewer(v ContainerViewer, _ *world.World, _ cube.Pos) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.viewers[v] = struct{}{}
+}
+
+// RemoveViewer removes a viewer from the brewer, so that slot updates in the inventory are no longer sent to
+// it.
+func (b *brewer) RemoveViewer(v ContainerViewer, _ *world.World, _ cube.Pos) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.viewers, v)
+	delete(b.synced, v)
+}
+
+// setDuration sets the remaining brew duration of the brewer.
+func (b *brewer) setDuration(duration time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.duration = duration
+}
+
+// setFuel sets the fuel amount and fuel total of the brewer.
+func (b *brewer) setFuel(fuelAmount, fuelTotal int32) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.fuelAmount, b.fuelTotal = fuelAmount, fuelTotal
+}
+
+// tickBrewing ticks the brewer, refuelling it if needed and brewing the potions in it once the ingredient has been
+// brewed for long enough.
+func (b *brewer) tickBrewing(pos cube.Pos, w *world.World) {
+	b.mu.Lock()
+
+	// First keep track of our past durations and fuel, since if any of them change, we need to be able to tell
+	// they did and then update the viewers on the change.
+	prevDuration, prevFuelAmount, prevFuelTotal := b.duration, b.fuelAmount, b.fuelTotal
+
+	// Now get each item in the brewer. We don't need to validate errors here since we know the bounds of the
+	// brewer.
+	ingredient, _ := b.inventory.Item(brewerIngredientSlot)
+	fuel, _ := b.inventory.Item(brewerFuelSlot)
+
+	if _, ok := fuel.Item().(item.BlazePowder); ok && b.fuelAmount <= 0 {
+		b.fuelAmount, b.fuelTotal = brewerFuelAmount, brewerFuelAmount
+		defer b.inventory.SetItem(brewerFuelSlot, fuel.Grow(-1))
+	}
+
+	_, canBrew := b.results(ingredient)
+	finished := false
+	switch {
+	case b.duration > 0 && (!canBrew || !ingredient.Comparable(b.ingredient)):
+		// The ingredient or the potions were taken out of the brewer, so brewing stops.
+		b.duration = 0
+	case b.duration > 0:
+		b.duration -= time.Second / 20
+		finished = b.duration <= 0
+	case canBrew && b.fuelAmount > 0:
+		// We can start brewing the ingredient, which costs a single brew of fuel.
+		b.fuelAmount--
+		b.duration, b.ingredient = brewerDuration, ingredient
+	}
+
+	if b.duration != prevDuration || b.fuelAmount != prevFuelAmount || b.fuelTotal != prevFuelTotal {
+		b.changed = true
+	}
+
+	// Update the viewers on the new durations and fuel. Viewers that were added since the last tick are sent all
+	// values.
+	for v := range b.viewers {
+		if _, ok := b.synced[v]; !ok {
+			b.synced[v] = struct{}{}
+			v.ViewBrewingUpdate(-1, b.duration, -1, b.fuelAmount, -1, b.fuelTotal)
+			continue
+		}
+		v.ViewBrewingUpdate(prevDuration, b.duration, prevFuelAmount, b.fuelAmount, prevFuelTotal, b.fuelTotal)
+	}
+	b.mu.Unlock()
+
+	if finished {
+		b.brew(pos, w)
+	}
+}
+
+// brew brews the potions in the brewer using the ingredient in it. The ingredient is consumed and the potions are
+// replaced with the results of their recipes.
+func (b *brewer) brew(pos cube.Pos, w *world.World) {
+	ctx := event.C()
+	if w.Handler().HandleBrew(ctx, pos); ctx.Cancelled() {
+		return
+	}
+	ingredient, _ := b.inventory.Item(brewerIngredientSlot)
+	results, ok := b.results(ingredient)
+	if !ok {
+		return
+	}
+	for i, result := range results {
+		if !result.Empty() {
+			_ = b.inventory.SetItem(brewerIngredientSlot+1+i, result)
+		}
+	}
+	_ = b.inventory.SetItem(brewerIngredientSlot, ingredient.Grow(-1))
+	w.PlaySound(pos.Vec3Centre(), sound.PotionBrewed{})
+}
+
+// results returns the results of brewing each of the potions in the brewer with the ingredient passed. Potions
+// that cannot be brewed with the ingredient have an empty result. False is returned if none of the potions can be
+// brewed.
+func (b *brewer) results(ingredient item.Stack) (results [3]item.Stack, ok bool) {
+	if ingredient.Empty() {
+		return results, false
+	}
+	for i := range results {
+		input, _ := b.inventory.Item(brewerIngredientSlot + 1 + i)
+		if output, brewable := recipe.Brew(input, ingredient); brewable {
+			results[i], ok = output, true
+		}
+	}
+	return results, ok
+}
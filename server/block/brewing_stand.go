@@ -0,0 +1,139 @@
+package block
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/block/model"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// BrewingStand is a block used for brewing potions, splash potions, and lingering potions. It also serves as a
+// cleric's job site block.
+// The empty value of BrewingStand is not valid. It must be created using block.NewBrewingStand().
+type BrewingStand struct {
+	transparent
+	sourceWaterDisplacer
+	*brewer
+
+	// LeftSlot is true if the left slot of the brewing stand holds a potion.
+	LeftSlot bool
+	// MiddleSlot is true if the middle slot of the brewing stand holds a potion.
+	MiddleSlot bool
+	// RightSlot is true if the right slot of the brewing stand holds a potion.
+	RightSlot bool
+}
+
+// NewBrewingStand creates a new initialised brewing stand. The brewer is properly initialised.
+func NewBrewingStand() BrewingStand {
+	return BrewingStand{brewer: newBrewer()}
+}
+
+// Tick is called to brew the potions in the brewing stand and to update the potions displayed on it.
+func (b BrewingStand) Tick(_ int64, pos cube.Pos, w *world.World) {
+	b.tickBrewing(pos, w)
+
+	left, _ := b.inventory.Item(brewerIngredientSlot + 1)
+	middle, _ := b.inventory.Item(brewerIngredientSlot + 2)
+	right, _ := b.inventory.Item(brewerIngredientSlot + 3)
+	if b.LeftSlot != !left.Empty() || b.MiddleSlot != !middle.Empty() || b.RightSlot != !right.Empty() {
+		b.LeftSlot, b.MiddleSlot, b.RightSlot = !left.Empty(), !middle.Empty(), !right.Empty()
+		w.SetBlock(pos, b, nil)
+	}
+}
+
+// Model ...
+func (BrewingStand) Model() world.BlockModel {
+	return model.BrewingStand{}
+}
+
+// LightEmissionLevel ...
+func (BrewingStand) LightEmissionLevel() uint8 {
+	return 1
+}
+
+// UseOnBlock ...
+func (b BrewingStand) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) bool {
+	pos, _, used := firstReplaceable(w, pos, face, b)
+	if !used {
+		return false
+	}
+
+	place(w, pos, NewBrewingStand(), user, ctx)
+	return placed(ctx)
+}
+
+// BreakInfo ...
+func (b BrewingStand) BreakInfo() BreakInfo {
+	return newBreakInfo(0.5, pickaxeHarvestable, pickaxeEffective, oneOf(b))
+}
+
+// Activate ...
+func (BrewingStand) Activate(pos cube.Pos, _ cube.Face, _ *world.World, u item.User, _ *item.UseContext) bool {
+	if opener, ok := u.(ContainerOpener); ok {
+		opener.OpenBlockContainer(pos)
+		return true
+	}
+	return false
+}
+
+// EncodeItem ...
+func (BrewingStand) EncodeItem() (name string, meta int16) {
+	return "minecraft:brewing_stand", 0
+}
+
+// EncodeBlock ...
+func (b BrewingStand) EncodeBlock() (name string, properties map[string]any) {
+	return "minecraft:brewing_stand", map[string]any{
+		"brewing_stand_slot_a_bit": b.LeftSlot,
+		"brewing_stand_slot_b_bit": b.MiddleSlot,
+		"brewing_stand_slot_c_bit": b.RightSlot,
+	}
+}
+
+// EncodeNBT ...
+func (b BrewingStand) EncodeNBT() map[string]any {
+	if b.brewer == nil {
+		//noinspection GoAssignmentToReceiver
+		b = NewBrewingStand()
+	}
+	fuelAmount, fuelTotal := b.Fuel()
+	return map[string]any{
+		"CookTime":   int16(b.Duration().Milliseconds() / 50),
+		"FuelAmount": int16(fuelAmount),
+		"FuelTotal":  int16(fuelTotal),
+		"Items":      nbtconv.InvToNBT(b.Inventory()),
+		"id":         "BrewingStand",
+	}
+}
+
+// DecodeNBT ...
+func (b BrewingStand) DecodeNBT(data map[string]any) any {
+	duration := nbtconv.TickDuration[int16](data, "CookTime")
+	fuelAmount, fuelTotal := int32(nbtconv.Int16(data, "FuelAmount")), int32(nbtconv.Int16(data, "FuelTotal"))
+	left, middle, right := b.LeftSlot, b.MiddleSlot, b.RightSlot
+
+	//noinspection GoAssignmentToReceiver
+	b = NewBrewingStand()
+	b.LeftSlot, b.MiddleSlot, b.RightSlot = left, middle, right
+	b.setDuration(duration)
+	b.setFuel(fuelAmount, fuelTotal)
+	nbtconv.InvFromNBT(b.Inventory(), nbtconv.Slice(data, "Items"))
+	if duration > 0 {
+		b.ingredient, _ = b.inventory.Item(brewerIngredientSlot)
+	}
+	return b
+}
+
+// allBrewingStands ...
+func allBrewingStands() (stands []world.Block) {
+	for _, left := range []bool{false, true} {
+		for _, middle := range []bool{false, true} {
+			for _, right := range []bool{false, true} {
+				stands = append(stands, BrewingStand{LeftSlot: left, MiddleSlot: middle, RightSlot: right})
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,138 @@
+package block
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/sound"
+)
+
+// Button is a non-solid block that provides redstone power for a short time when pressed.
+type Button struct {
+	empty
+	transparent
+
+	// Type is the type of the button.
+	Type ButtonType
+	// Facing is the direction from the block the button is attached to towards the button.
+	Facing cube.Face
+	// Pressed is whether the button is pressed and emitting power.
+	Pressed bool
+}
+
+// BreakInfo ...
+func (b Button) BreakInfo() BreakInfo {
+	effectiveTool := axeEffective
+	if !b.Type.Wooden() {
+		effectiveTool = pickaxeEffective
+	}
+	return newBreakInfo(0.5, alwaysHarvestable, effectiveTool, oneOf(Button{Type: b.Type})).withBreakHandler(updateRedstoneOnBreak)
+}
+
+// FuelInfo ...
+func (b Button) FuelInfo() item.FuelInfo {
+	if !b.Type.Wooden() {
+		return item.FuelInfo{}
+	}
+	return newFuelInfo(time.Second * 5)
+}
+
+// HasLiquidDrops ...
+func (Button) HasLiquidDrops() bool {
+	return true
+}
+
+// UseOnBlock ...
+func (b Button) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) bool {
+	pos, face, used := firstReplaceable(w, pos, face, b)
+	if !used {
+		return false
+	}
+	if !attachedTo(pos, face.Opposite(), w) {
+		return false
+	}
+	b.Facing = face
+
+	place(w, pos, b, user, ctx)
+	return placed(ctx)
+}
+
+// Activate ...
+func (b Button) Activate(pos cube.Pos, _ cube.Face, w *world.World, _ item.User, _ *item.UseContext) bool {
+	if b.Pressed {
+		return true
+	}
+	b.Pressed = true
+	setRedstone(pos, b, w)
+	w.PlaySound(pos.Vec3Centre(), sound.PowerOn{Block: b})
+	w.ScheduleBlockUpdate(pos, b.pressDuration())
+	return true
+}
+
+// NeighbourUpdateTick ...
+func (b Button) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	breakUnattached(b, pos, b.Facing.Opposite(), w)
+}
+
+// ScheduledTick ...
+func (b Button) ScheduledTick(pos cube.Pos, w *world.World, _ *rand.Rand) {
+	if !b.Pressed {
+		return
+	}
+	b.Pressed = false
+	setRedstone(pos, b, w)
+	w.PlaySound(pos.Vec3Centre(), sound.PowerOff{Block: b})
+}
+
+// WeakPower ...
+func (b Button) WeakPower(cube.Pos, cube.Face, *world.World, bool) int {
+	if b.Pressed {
+		return 15
+	}
+	return 0
+}
+
+// StrongPower ...
+func (b Button) StrongPower(_ cube.Pos, face cube.Face, _ *world.World, _ bool) int {
+	if b.Pressed && face == b.Facing.Opposite() {
+		return 15
+	}
+	return 0
+}
+
+// EncodeItem ...
+func (b Button) EncodeItem() (name string, meta int16) {
+	name, _ = b.EncodeBlock()
+	return name, 0
+}
+
+// EncodeBlock ...
+func (b Button) EncodeBlock() (string, map[string]any) {
+	name := "minecraft:" + b.Type.String() + "_button"
+	if b.Type == WoodenButton(OakWood()) {
+		name = "minecraft:wooden_button"
+	}
+	return name, map[string]any{"facing_direction": int32(b.Facing), "button_pressed_bit": boolByte(b.Pressed)}
+}
+
+// pressDuration returns the duration that the button stays pressed for after being activated.
+func (b Button) pressDuration() time.Duration {
+	if b.Type.Wooden() {
+		return time.Millisecond * 1500
+	}
+	return time.Second
+}
+
+// allButtons ...
+func allButtons() (buttons []world.Block) {
+	for _, t := range ButtonTypes() {
+		for _, f := range cube.Faces() {
+			buttons = append(buttons, Button{Type: t, Facing: f}, Button{Type: t, Facing: f, Pressed: true})
+		}
+	}
+	return
+}
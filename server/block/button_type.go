@@ -0,0 +1,73 @@
+package block
+
+// ButtonType represents a type of button. Buttons may be made of stone, polished blackstone or any type of wood.
+type ButtonType struct {
+	button
+	// wood is the type of wood of the button. This field is only used if the button is a wooden button.
+	wood WoodType
+}
+
+type button uint8
+
+// StoneButton returns the stone button type.
+func StoneButton() ButtonType {
+	return ButtonType{button: 0}
+}
+
+// PolishedBlackstoneButton returns the polished blackstone button type.
+func PolishedBlackstoneButton() ButtonType {
+	return ButtonType{button: 1}
+}
+
+// WoodenButton returns the wooden button type with the wood type passed.
+func WoodenButton(w WoodType) ButtonType {
+	return ButtonType{button: 2, wood: w}
+}
+
+// Uint8 ...
+func (b ButtonType) Uint8() uint8 {
+	if b.button == 2 {
+		return 2 + b.wood.Uint8()
+	}
+	return uint8(b.button)
+}
+
+// Wooden checks if the button type is a wooden button.
+func (b ButtonType) Wooden() bool {
+	return b.button == 2
+}
+
+// Name ...
+func (b ButtonType) Name() string {
+	switch b.button {
+	case 0:
+		return "Stone Button"
+	case 1:
+		return "Polished Blackstone Button"
+	case 2:
+		return b.wood.Name() + " Button"
+	}
+	panic("unknown button type")
+}
+
+// String ...
+func (b ButtonType) String() string {
+	switch b.button {
+	case 0:
+		return "stone"
+	case 1:
+		return "polished_blackstone"
+	case 2:
+		return b.wood.String()
+	}
+	panic("unknown button type")
+}
+
+// ButtonTypes ...
+func ButtonTypes() []ButtonType {
+	types := []ButtonType{StoneButton(), PolishedBlackstoneButton()}
+	for _, w := range WoodTypes() {
+		types = append(types, WoodenButton(w))
+	}
+	return types
+}
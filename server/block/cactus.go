@@ -92,6 +92,11 @@ func (Cactus) CompostChance() float64 {
 	return 0.5
 }
 
+// PistonBreakable ...
+func (Cactus) PistonBreakable() bool {
+	return true
+}
+
 // EncodeItem ...
 func (c Cactus) EncodeItem() (name string, meta int16) {
 	return "minecraft:cactus", 0
@@ -70,6 +70,11 @@ func (c Cake) BreakInfo() BreakInfo {
 	return newBreakInfo(0.5, neverHarvestable, nothingEffective, simpleDrops())
 }
 
+// PistonBreakable ...
+func (Cake) PistonBreakable() bool {
+	return true
+}
+
 // EncodeItem ...
 func (c Cake) EncodeItem() (name string, meta int16) {
 	return "minecraft:cake", 0
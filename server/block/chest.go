@@ -77,6 +77,11 @@ func (c Chest) Inventory() *inventory.Inventory {
 	return c.inventory
 }
 
+// Changed returns true if the inventory of the chest changed since the last call to Changed.
+func (c Chest) Changed() bool {
+	return c.inventory != nil && c.inventory.Changed()
+}
+
 // WithName returns the chest after applying a specific name to the block.
 func (c Chest) WithName(a ...any) world.Item {
 	c.CustomName = strings.TrimSuffix(fmt.Sprintln(a...), "\n")
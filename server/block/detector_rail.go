@@ -0,0 +1,133 @@
+package block
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// DetectorRail is a rail that emits redstone power while a minecart is on top of it.
+type DetectorRail struct {
+	transparent
+	empty
+
+	// Shape is the shape of the rail. Detector rails cannot be curved.
+	Shape RailShape
+	// Powered is true if a minecart is on the rail, making it emit redstone power.
+	Powered bool
+}
+
+// RailShape ...
+func (r DetectorRail) RailShape() RailShape {
+	return r.Shape
+}
+
+// MinecartPosition ...
+func (r DetectorRail) MinecartPosition(pos cube.Pos) mgl64.Vec3 {
+	return railMinecartPosition(pos, r.Shape)
+}
+
+// withRailShape ...
+func (r DetectorRail) withRailShape(s RailShape) railBlock {
+	r.Shape = s
+	return r
+}
+
+// UseOnBlock ...
+func (r DetectorRail) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) (used bool) {
+	pos, _, used = firstReplaceable(w, pos, face, r)
+	if !used || !railSupported(pos, w) {
+		return false
+	}
+	r.Shape = railShapeFor(pos, r, w)
+	return placeRail(pos, r, w, user, ctx)
+}
+
+// EntityInside ...
+func (r DetectorRail) EntityInside(pos cube.Pos, w *world.World, e world.Entity) {
+	if _, ok := e.(RailTraveller); ok && !r.Powered {
+		// The minecarts on the rail are checked in the scheduled tick, so that the rail is only powered by
+		// minecarts that are actually travelling along it.
+		w.ScheduleBlockUpdate(pos, 0)
+	}
+}
+
+// ScheduledTick ...
+func (r DetectorRail) ScheduledTick(pos cube.Pos, w *world.World, _ *rand.Rand) {
+	powered := len(w.EntitiesWithin(cube.Box(0, 0, 0, 1, 1, 1).Translate(pos.Vec3()), func(e world.Entity) bool {
+		t, ok := e.(RailTraveller)
+		if !ok {
+			return true
+		}
+		railPos, onRail := t.Rail()
+		return !onRail || railPos != pos
+	})) > 0
+	if powered != r.Powered {
+		r.Powered = powered
+		setRedstone(pos, r, w)
+	}
+	if powered {
+		// Keep checking if minecarts are still on the rail as long as it is powered.
+		w.ScheduleBlockUpdate(pos, time.Second)
+	}
+}
+
+// NeighbourUpdateTick ...
+func (r DetectorRail) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if breakUnsupportedRail(pos, r, w) {
+		w.UpdateRedstone(pos)
+	}
+}
+
+// WeakPower ...
+func (r DetectorRail) WeakPower(cube.Pos, cube.Face, *world.World, bool) int {
+	if r.Powered {
+		return 15
+	}
+	return 0
+}
+
+// StrongPower ...
+func (r DetectorRail) StrongPower(_ cube.Pos, face cube.Face, _ *world.World, _ bool) int {
+	if r.Powered && face == cube.FaceDown {
+		return 15
+	}
+	return 0
+}
+
+// HasLiquidDrops ...
+func (DetectorRail) HasLiquidDrops() bool {
+	return true
+}
+
+// SideClosed ...
+func (DetectorRail) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
+	return false
+}
+
+// BreakInfo ...
+func (r DetectorRail) BreakInfo() BreakInfo {
+	return newBreakInfo(0.7, alwaysHarvestable, pickaxeEffective, oneOf(DetectorRail{})).withBreakHandler(updateRedstoneOnBreak)
+}
+
+// EncodeItem ...
+func (DetectorRail) EncodeItem() (name string, meta int16) {
+	return "minecraft:detector_rail", 0
+}
+
+// EncodeBlock ...
+func (r DetectorRail) EncodeBlock() (string, map[string]any) {
+	return "minecraft:detector_rail", map[string]any{"rail_direction": int32(r.Shape.Uint8()), "rail_data_bit": boolByte(r.Powered)}
+}
+
+// allDetectorRails ...
+func allDetectorRails() (rails []world.Block) {
+	for _, s := range StraightRailShapes() {
+		rails = append(rails, DetectorRail{Shape: s}, DetectorRail{Shape: s, Powered: true})
+	}
+	return
+}
@@ -0,0 +1,292 @@
+package block
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/item/inventory"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/particle"
+	"github.com/stcraft/dragonfly/server/world/portal"
+	"github.com/stcraft/dragonfly/server/world/sound"
+)
+
+// DispenseBehaviour represents the behaviour of an item when it is dispensed by a Dispenser. A DispenseBehaviour
+// may be registered for an item type using RegisterDispenseBehaviour.
+type DispenseBehaviour interface {
+	// Dispense dispenses the item stack passed from the dispenser at the position passed, which faces the face
+	// passed. The item stack that remains in the dispenser after dispensing is returned. If the item could not
+	// be dispensed, false is returned and the dispenser plays a failure sound.
+	Dispense(pos cube.Pos, facing cube.Face, it item.Stack, w *world.World) (item.Stack, bool)
+}
+
+// DispenseFunc is a function that implements DispenseBehaviour.
+type DispenseFunc func(pos cube.Pos, facing cube.Face, it item.Stack, w *world.World) (item.Stack, bool)
+
+// Dispense ...
+func (f DispenseFunc) Dispense(pos cube.Pos, facing cube.Face, it item.Stack, w *world.World) (item.Stack, bool) {
+	return f(pos, facing, it, w)
+}
+
+var (
+	dispenseMu sync.RWMutex
+	// dispenseBehaviours holds the DispenseBehaviours registered for item types, indexed by the type of the item.
+	dispenseBehaviours = map[reflect.Type]DispenseBehaviour{}
+)
+
+// RegisterDispenseBehaviour registers a DispenseBehaviour for the type of the world.Item passed. A dispenser
+// dispensing an item of the same type uses the DispenseBehaviour registered. Registering a DispenseBehaviour for
+// an item type that already has one overwrites the existing DispenseBehaviour.
+func RegisterDispenseBehaviour(it world.Item, b DispenseBehaviour) {
+	dispenseMu.Lock()
+	defer dispenseMu.Unlock()
+	dispenseBehaviours[reflect.TypeOf(it)] = b
+}
+
+// DispenseBehaviourFor returns the DispenseBehaviour registered for the type of the world.Item passed. If no
+// DispenseBehaviour was registered for it, DefaultDispenseBehaviour is returned.
+func DispenseBehaviourFor(it world.Item) DispenseBehaviour {
+	dispenseMu.RLock()
+	defer dispenseMu.RUnlock()
+	if b, ok := dispenseBehaviours[reflect.TypeOf(it)]; ok {
+		return b
+	}
+	return DefaultDispenseBehaviour{}
+}
+
+// DefaultDispenseBehaviour is the DispenseBehaviour of items without a specific DispenseBehaviour. It ejects a
+// single item out of the dispenser as an item entity. It is also the behaviour of all items dropped by a Dropper.
+type DefaultDispenseBehaviour struct{}
+
+// Dispense ...
+func (DefaultDispenseBehaviour) Dispense(pos cube.Pos, facing cube.Face, it item.Stack, w *world.World) (item.Stack, bool) {
+	spawnPos := dispensePosition(pos, facing)
+	if facing.Axis() == cube.Y {
+		spawnPos[1] -= 0.125
+	} else {
+		spawnPos[1] -= 0.15625
+	}
+	speed := rand.Float64()*0.1 + 0.2
+	vel := cube.Pos{}.Side(facing).Vec3().Mul(speed).Add(mgl64.Vec3{rand.NormFloat64() * 0.045, rand.NormFloat64()*0.045 + 0.2, rand.NormFloat64() * 0.045})
+
+	create := w.EntityRegistry().Config().Item
+	w.AddEntity(create(it.Grow(1-it.Count()), spawnPos, vel))
+	w.PlaySound(pos.Vec3Centre(), sound.Click{})
+	return it.Grow(-1), true
+}
+
+// projectileDispenseBehaviour is a DispenseBehaviour that launches an item as a projectile entity.
+type projectileDispenseBehaviour struct {
+	// power is the speed of the projectile launched.
+	power float64
+	// uncertainty is the inaccuracy of the direction of the projectile launched.
+	uncertainty float64
+	// create creates the projectile entity with the position, velocity and item passed.
+	create func(w *world.World, pos, vel mgl64.Vec3, it item.Stack) world.Entity
+}
+
+// Dispense ...
+func (p projectileDispenseBehaviour) Dispense(pos cube.Pos, facing cube.Face, it item.Stack, w *world.World) (item.Stack, bool) {
+	dir := cube.Pos{}.Side(facing).Vec3()
+	dir[1] += 0.1
+	dir = dir.Normalize().Add(mgl64.Vec3{rand.NormFloat64(), rand.NormFloat64(), rand.NormFloat64()}.Mul(0.0075 * p.uncertainty))
+
+	w.AddEntity(p.create(w, dispensePosition(pos, facing), dir.Mul(p.power), it))
+	w.PlaySound(pos.Vec3Centre(), sound.Launch{})
+	return it.Grow(-1), true
+}
+
+// bucketDispenseBehaviour is the DispenseBehaviour of buckets. Filled buckets place their liquid in front of the
+// dispenser, while empty buckets pick up the liquid source in front of it.
+type bucketDispenseBehaviour struct{}
+
+// Dispense ...
+func (bucketDispenseBehaviour) Dispense(pos cube.Pos, facing cube.Face, it item.Stack, w *world.World) (item.Stack, bool) {
+	b := it.Item().(item.Bucket)
+	front := pos.Side(facing)
+	if b.Empty() {
+		liq, ok := w.Liquid(front)
+		if !ok || liq.LiquidDepth() != 8 || liq.LiquidFalling() {
+			return DefaultDispenseBehaviour{}.Dispense(pos, facing, it, w)
+		}
+		w.SetLiquid(front, nil)
+		w.PlaySound(front.Vec3Centre(), sound.BucketFill{Liquid: liq})
+		return filledBucketResult(pos, facing, it, item.NewStack(item.Bucket{Content: item.LiquidBucketContent(liq)}, 1), w), true
+	}
+	liq, ok := b.Content.Liquid()
+	if !ok {
+		return DefaultDispenseBehaviour{}.Dispense(pos, facing, it, w)
+	}
+	if !replaceableWith(w, front, liq) {
+		return it, false
+	}
+	w.SetLiquid(front, liq.WithDepth(8, false))
+	w.PlaySound(front.Vec3Centre(), sound.BucketEmpty{Liquid: liq})
+	return item.NewStack(item.Bucket{}, 1), true
+}
+
+// filledBucketResult returns the item stack left in a dispenser after filling one of the empty buckets in the
+// stack passed. If the stack held a single bucket, the filled bucket replaces it. Otherwise, the filled bucket is
+// added to the dispenser's inventory or ejected if it does not fit.
+func filledBucketResult(pos cube.Pos, facing cube.Face, it, filled item.Stack, w *world.World) item.Stack {
+	if it.Count() == 1 {
+		return filled
+	}
+	if d, ok := w.Block(pos).(Dispenser); ok && d.inventory != nil {
+		if _, err := d.inventory.AddItem(filled); err == nil {
+			return it.Grow(-1)
+		}
+	}
+	DefaultDispenseBehaviour{}.Dispense(pos, facing, filled, w)
+	return it.Grow(-1)
+}
+
+// flintAndSteelDispenseBehaviour is the DispenseBehaviour of flint and steel. It lights the block in front of the
+// dispenser on fire, or ignites it if it can be ignited, such as TNT.
+type flintAndSteelDispenseBehaviour struct{}
+
+// Dispense ...
+func (flintAndSteelDispenseBehaviour) Dispense(pos cube.Pos, facing cube.Face, it item.Stack, w *world.World) (item.Stack, bool) {
+	front := pos.Side(facing)
+	switch b := w.Block(front).(type) {
+	case Air:
+		w.PlaySound(front.Vec3Centre(), sound.Ignite{})
+		if p, ok := portal.NetherPortalFromPos(w, front); ok && !p.Activated() {
+			p.Activate()
+			break
+		}
+		w.SetBlock(front, Fire{Type: NormalFire()}, nil)
+		w.ScheduleBlockUpdate(front, time.Duration(30+rand.Intn(10))*time.Second/20)
+	case interface {
+		Ignite(pos cube.Pos, w *world.World, igniter world.Entity) bool
+	}:
+		if !b.Ignite(front, w, nil) {
+			return it, false
+		}
+	default:
+		return it, false
+	}
+	return it.Damage(1), true
+}
+
+// tntDispenseBehaviour is the DispenseBehaviour of TNT. It spawns primed TNT in front of the dispenser.
+type tntDispenseBehaviour struct{}
+
+// Dispense ...
+func (tntDispenseBehaviour) Dispense(pos cube.Pos, facing cube.Face, it item.Stack, w *world.World) (item.Stack, bool) {
+	front := pos.Side(facing)
+	w.PlaySound(front.Vec3Centre(), sound.TNT{})
+	w.AddEntity(w.EntityRegistry().Config().TNT(front.Vec3Middle(), time.Second*4, nil))
+	return it.Grow(-1), true
+}
+
+// boneMealDispenseBehaviour is the DispenseBehaviour of bone meal. It applies the bone meal to the block in front
+// of the dispenser.
+type boneMealDispenseBehaviour struct{}
+
+// Dispense ...
+func (boneMealDispenseBehaviour) Dispense(pos cube.Pos, facing cube.Face, it item.Stack, w *world.World) (item.Stack, bool) {
+	front := pos.Side(facing)
+	if bm, ok := w.Block(front).(item.BoneMealAffected); ok && bm.BoneMeal(front, w) {
+		w.AddParticle(front.Vec3(), particle.BoneMeal{})
+		return it.Grow(-1), true
+	}
+	return it, false
+}
+
+// armourDispenseBehaviour is the DispenseBehaviour of armour. It equips the armour on the first entity in front
+// of the dispenser that is able to wear it and does not yet wear armour in the same slot. If no such entity is
+// found, the armour is ejected.
+type armourDispenseBehaviour struct{}
+
+// Dispense ...
+func (armourDispenseBehaviour) Dispense(pos cube.Pos, facing cube.Face, it item.Stack, w *world.World) (item.Stack, bool) {
+	box := cube.Box(0, 0, 0, 1, 1, 1).Translate(pos.Side(facing).Vec3())
+	for _, e := range w.EntitiesWithin(box, nil) {
+		a, ok := e.(interface{ Armour() *inventory.Armour })
+		if !ok {
+			continue
+		}
+		inv := a.Armour()
+		var current item.Stack
+		var set func(item.Stack)
+		switch it.Item().(type) {
+		case item.HelmetType:
+			current, set = inv.Helmet(), inv.SetHelmet
+		case item.ChestplateType:
+			current, set = inv.Chestplate(), inv.SetChestplate
+		case item.LeggingsType:
+			current, set = inv.Leggings(), inv.SetLeggings
+		case item.BootsType:
+			current, set = inv.Boots(), inv.SetBoots
+		default:
+			return DefaultDispenseBehaviour{}.Dispense(pos, facing, it, w)
+		}
+		if !current.Empty() {
+			continue
+		}
+		set(it.Grow(1 - it.Count()))
+		w.PlaySound(pos.Vec3Centre(), sound.Click{})
+		return it.Grow(-1), true
+	}
+	return DefaultDispenseBehaviour{}.Dispense(pos, facing, it, w)
+}
+
+// dispensePosition returns the position in front of the dispenser at the position passed that items and
+// projectiles are dispensed from.
+func dispensePosition(pos cube.Pos, facing cube.Face) mgl64.Vec3 {
+	return pos.Vec3Centre().Add(cube.Pos{}.Side(facing).Vec3().Mul(0.7))
+}
+
+// dispenseRotation returns the rotation of a projectile moving with the velocity passed.
+func dispenseRotation(vel mgl64.Vec3) cube.Rotation {
+	return cube.Rotation{
+		mgl64.RadToDeg(math.Atan2(vel[0], vel[2])),
+		mgl64.RadToDeg(math.Atan2(vel[1], math.Hypot(vel[0], vel[2]))),
+	}
+}
+
+func init() {
+	conf := func(w *world.World) world.EntityRegistryConfig {
+		return w.EntityRegistry().Config()
+	}
+	throwable := func(create func(w *world.World, pos, vel mgl64.Vec3, it item.Stack) world.Entity) projectileDispenseBehaviour {
+		return projectileDispenseBehaviour{power: 1.1, uncertainty: 6, create: create}
+	}
+	potion := func(create func(w *world.World, pos, vel mgl64.Vec3, it item.Stack) world.Entity) projectileDispenseBehaviour {
+		return projectileDispenseBehaviour{power: 1.375, uncertainty: 3, create: create}
+	}
+
+	RegisterDispenseBehaviour(item.Arrow{}, throwable(func(w *world.World, pos, vel mgl64.Vec3, it item.Stack) world.Entity {
+		return conf(w).Arrow(pos, vel, dispenseRotation(vel), 2, nil, false, false, true, 0, 0, it.Item().(item.Arrow).Tip)
+	}))
+	RegisterDispenseBehaviour(item.Snowball{}, throwable(func(w *world.World, pos, vel mgl64.Vec3, _ item.Stack) world.Entity {
+		return conf(w).Snowball(pos, vel, nil)
+	}))
+	RegisterDispenseBehaviour(item.Egg{}, throwable(func(w *world.World, pos, vel mgl64.Vec3, _ item.Stack) world.Entity {
+		return conf(w).Egg(pos, vel, nil)
+	}))
+	RegisterDispenseBehaviour(item.SplashPotion{}, potion(func(w *world.World, pos, vel mgl64.Vec3, it item.Stack) world.Entity {
+		return conf(w).SplashPotion(pos, vel, it.Item().(item.SplashPotion).Type, nil)
+	}))
+	RegisterDispenseBehaviour(item.LingeringPotion{}, potion(func(w *world.World, pos, vel mgl64.Vec3, it item.Stack) world.Entity {
+		return conf(w).LingeringPotion(pos, vel, it.Item().(item.LingeringPotion).Type, nil)
+	}))
+	RegisterDispenseBehaviour(item.BottleOfEnchanting{}, potion(func(w *world.World, pos, vel mgl64.Vec3, _ item.Stack) world.Entity {
+		return conf(w).BottleOfEnchanting(pos, vel, nil)
+	}))
+	RegisterDispenseBehaviour(item.Bucket{}, bucketDispenseBehaviour{})
+	RegisterDispenseBehaviour(item.FlintAndSteel{}, flintAndSteelDispenseBehaviour{})
+	RegisterDispenseBehaviour(item.BoneMeal{}, boneMealDispenseBehaviour{})
+	RegisterDispenseBehaviour(TNT{}, tntDispenseBehaviour{})
+	RegisterDispenseBehaviour(item.Helmet{}, armourDispenseBehaviour{})
+	RegisterDispenseBehaviour(item.Chestplate{}, armourDispenseBehaviour{})
+	RegisterDispenseBehaviour(item.Leggings{}, armourDispenseBehaviour{})
+	RegisterDispenseBehaviour(item.Boots{}, armourDispenseBehaviour{})
+}
@@ -1,7 +1,9 @@
 package block
 
 import (
+	"math/rand"
 	"sync"
+	"time"
 
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/stcraft/dragonfly/server/block/cube"
@@ -9,6 +11,7 @@ import (
 	"github.com/stcraft/dragonfly/server/item"
 	"github.com/stcraft/dragonfly/server/item/inventory"
 	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/sound"
 )
 
 // Dispenser is a low-capacity storage block that can fire projectiles, use
@@ -83,6 +86,11 @@ func (d Dispenser) Inventory() *inventory.Inventory {
 	return d.inventory
 }
 
+// Changed returns true if the inventory of the dispenser changed since the last call to Changed.
+func (d Dispenser) Changed() bool {
+	return d.inventory != nil && d.inventory.Changed()
+}
+
 // AddViewer adds a viewer to the dispenser, so that it is updated whenever the inventory of the dispenser is changed.
 func (d Dispenser) AddViewer(v ContainerViewer, w *world.World, pos cube.Pos) {
 	d.viewerMu.Lock()
@@ -112,6 +120,41 @@ func (d Dispenser) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.Us
 	return false
 }
 
+// NeighbourUpdateTick ...
+func (d Dispenser) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if powered := w.ReceivedRedstonePower(pos, true) > 0; powered != d.Triggered {
+		d.Triggered = powered
+		w.SetBlock(pos, d, &world.SetOpts{DisableBlockUpdates: true})
+		if powered {
+			w.ScheduleBlockUpdate(pos, time.Second/5)
+		}
+	}
+}
+
+// ScheduledTick ...
+func (d Dispenser) ScheduledTick(pos cube.Pos, w *world.World, r *rand.Rand) {
+	if d.inventory == nil {
+		return
+	}
+	slot, ok := randomFilledSlot(d.inventory, r)
+	if !ok {
+		w.PlaySound(pos.Vec3Centre(), sound.ClickFail{})
+		return
+	}
+	it, _ := d.inventory.Item(slot)
+	left, ok := DispenseBehaviourFor(it.Item()).Dispense(pos, d.Facing, it, w)
+	if !ok {
+		w.PlaySound(pos.Vec3Centre(), sound.ClickFail{})
+		return
+	}
+	_ = d.inventory.SetItem(slot, left)
+}
+
+// BreakInfo ...
+func (d Dispenser) BreakInfo() BreakInfo {
+	return newBreakInfo(3.5, pickaxeHarvestable, pickaxeEffective, oneOf(Dispenser{}))
+}
+
 // UseOnBlock ...
 func (d Dispenser) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) (used bool) {
 	pos, _, used = firstReplaceable(w, pos, face, d)
@@ -121,7 +164,7 @@ func (d Dispenser) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *wor
 
 	//noinspection GoAssignmentToReceiver
 	d = NewDispenser()
-	d.Facing = user.Rotation().Opposite().Direction().Face()
+	d.Facing = calculateFace(user, pos)
 
 	place(w, pos, d, user, ctx)
 	return placed(ctx)
@@ -129,11 +172,11 @@ func (d Dispenser) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *wor
 
 // DecodeNBT ...
 func (d Dispenser) DecodeNBT(data map[string]any) any {
-	facing := d.Facing
+	facing, triggered := d.Facing, d.Triggered
 	//noinspection GoAssignmentToReceiver
 	d = NewDispenser()
 
-	d.Facing = facing
+	d.Facing, d.Triggered = facing, triggered
 	d.CustomName = nbtconv.String(data, "CustomName")
 	nbtconv.InvFromNBT(d.inventory, nbtconv.Slice(data, "Items"))
 
@@ -158,6 +201,21 @@ func (d Dispenser) EncodeNBT() map[string]any {
 	return m
 }
 
+// randomFilledSlot returns a random slot of the inventory passed that holds an item. If the inventory is empty,
+// false is returned.
+func randomFilledSlot(inv *inventory.Inventory, r *rand.Rand) (int, bool) {
+	var slots []int
+	for slot, it := range inv.Slots() {
+		if !it.Empty() {
+			slots = append(slots, slot)
+		}
+	}
+	if len(slots) == 0 {
+		return 0, false
+	}
+	return slots[r.Intn(len(slots))], true
+}
+
 // allDispensers ...
 func allDispensers() (Dispensers []world.Block) {
 	for _, face := range cube.Faces() {
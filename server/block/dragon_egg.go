@@ -65,6 +65,11 @@ func (d DragonEgg) BreakInfo() BreakInfo {
 	return newBreakInfo(3, pickaxeHarvestable, pickaxeEffective, oneOf(d))
 }
 
+// PistonBreakable ...
+func (DragonEgg) PistonBreakable() bool {
+	return true
+}
+
 // EncodeItem ...
 func (DragonEgg) EncodeItem() (name string, meta int16) {
 	return "minecraft:dragon_egg", 0
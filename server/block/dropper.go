@@ -0,0 +1,195 @@
+package block
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/item/inventory"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/sound"
+)
+
+// Dropper is a low-capacity storage block that ejects a single item when given a redstone signal. Unlike a
+// Dispenser, a dropper does not use the items it holds: If the dropper is facing a container, the item is
+// moved into the container instead.
+type Dropper struct {
+	solid
+
+	CustomName string
+	Facing     cube.Face
+	Triggered  bool
+
+	inventory *inventory.Inventory
+	viewerMu  *sync.RWMutex
+	viewers   map[ContainerViewer]struct{}
+}
+
+// NewDropper creates a new initialised dropper. The inventory of the dropper
+// is properly initialised. You still need to set the dropper's facing direction
+// etc.
+func NewDropper() Dropper {
+	m := &sync.RWMutex{}
+	v := map[ContainerViewer]struct{}{}
+
+	return Dropper{
+		inventory: inventory.New(9, func(slot int, _, item item.Stack) {
+			m.RLock()
+			defer m.RUnlock()
+			for viewer := range v {
+				viewer.ViewSlotChange(slot, item)
+			}
+		}),
+		viewerMu: m,
+		viewers:  v,
+	}
+}
+
+// EncodeItem ...
+func (d Dropper) EncodeItem() (name string, meta int16) {
+	return "minecraft:dropper", 0
+}
+
+// EncodeBlock ...
+func (d Dropper) EncodeBlock() (string, map[string]any) {
+	return "minecraft:dropper", map[string]any{
+		"facing_direction": int32(d.Facing),
+		"triggered_bit":    d.Triggered,
+	}
+}
+
+// Inventory returns the inventory of the dropper. The size of the inventory will
+// always be 9.
+func (d Dropper) Inventory() *inventory.Inventory {
+	return d.inventory
+}
+
+// Changed returns true if the inventory of the dropper changed since the last call to Changed.
+func (d Dropper) Changed() bool {
+	return d.inventory != nil && d.inventory.Changed()
+}
+
+// AddViewer adds a viewer to the dropper, so that it is updated whenever the inventory of the dropper is changed.
+func (d Dropper) AddViewer(v ContainerViewer, w *world.World, pos cube.Pos) {
+	d.viewerMu.Lock()
+	defer d.viewerMu.Unlock()
+	d.viewers[v] = struct{}{}
+}
+
+// RemoveViewer removes a viewer from the dropper, so that slot updates in the inventory are no longer sent to
+// it.
+func (d Dropper) RemoveViewer(v ContainerViewer, w *world.World, pos cube.Pos) {
+	d.viewerMu.Lock()
+	defer d.viewerMu.Unlock()
+	if len(d.viewers) == 0 {
+		return
+	}
+	delete(d.viewers, v)
+}
+
+// Activate ...
+func (d Dropper) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.User, _ *item.UseContext) bool {
+	if opener, ok := u.(ContainerOpener); ok {
+		opener.OpenBlockContainer(pos)
+		return true
+	}
+	return false
+}
+
+// NeighbourUpdateTick ...
+func (d Dropper) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if powered := w.ReceivedRedstonePower(pos, true) > 0; powered != d.Triggered {
+		d.Triggered = powered
+		w.SetBlock(pos, d, &world.SetOpts{DisableBlockUpdates: true})
+		if powered {
+			w.ScheduleBlockUpdate(pos, time.Second/5)
+		}
+	}
+}
+
+// ScheduledTick ...
+func (d Dropper) ScheduledTick(pos cube.Pos, w *world.World, r *rand.Rand) {
+	if d.inventory == nil {
+		return
+	}
+	slot, ok := randomFilledSlot(d.inventory, r)
+	if !ok {
+		w.PlaySound(pos.Vec3Centre(), sound.ClickFail{})
+		return
+	}
+	if dest, ok := w.Block(pos.Side(d.Facing)).(Container); ok {
+		it, _ := d.inventory.Item(slot)
+		if transferItem(d.inventory, slot, dest.Inventory(), insertionSlots(dest, d.Facing.Opposite(), it)) {
+			w.PlaySound(pos.Vec3Centre(), sound.Click{})
+		} else {
+			w.PlaySound(pos.Vec3Centre(), sound.ClickFail{})
+		}
+		return
+	}
+	it, _ := d.inventory.Item(slot)
+	left, _ := DefaultDispenseBehaviour{}.Dispense(pos, d.Facing, it, w)
+	_ = d.inventory.SetItem(slot, left)
+}
+
+// UseOnBlock ...
+func (d Dropper) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) (used bool) {
+	pos, _, used = firstReplaceable(w, pos, face, d)
+	if !used {
+		return
+	}
+
+	//noinspection GoAssignmentToReceiver
+	d = NewDropper()
+	d.Facing = calculateFace(user, pos)
+
+	place(w, pos, d, user, ctx)
+	return placed(ctx)
+}
+
+// BreakInfo ...
+func (d Dropper) BreakInfo() BreakInfo {
+	return newBreakInfo(3.5, pickaxeHarvestable, pickaxeEffective, oneOf(Dropper{}))
+}
+
+// DecodeNBT ...
+func (d Dropper) DecodeNBT(data map[string]any) any {
+	facing, triggered := d.Facing, d.Triggered
+	//noinspection GoAssignmentToReceiver
+	d = NewDropper()
+
+	d.Facing, d.Triggered = facing, triggered
+	d.CustomName = nbtconv.String(data, "CustomName")
+	nbtconv.InvFromNBT(d.inventory, nbtconv.Slice(data, "Items"))
+
+	return d
+}
+
+// EncodeNBT ...
+func (d Dropper) EncodeNBT() map[string]any {
+	if d.inventory == nil {
+		facing, customName := d.Facing, d.CustomName
+		//noinspection GoAssignmentToReceiver
+		d = NewDropper()
+		d.Facing, d.CustomName = facing, customName
+	}
+	m := map[string]any{
+		"Items": nbtconv.InvToNBT(d.inventory),
+		"id":    "Dropper",
+	}
+	if d.CustomName != "" {
+		m["CustomName"] = d.CustomName
+	}
+	return m
+}
+
+// allDroppers ...
+func allDroppers() (droppers []world.Block) {
+	for _, face := range cube.Faces() {
+		droppers = append(droppers, Dropper{Facing: face}, Dropper{Facing: face, Triggered: true})
+	}
+	return
+}
@@ -0,0 +1,66 @@
+package block
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/portal"
+)
+
+// EndGateway is a block found in the end that transports entities that enter
+// it between the central island and the outer islands of the end.
+type EndGateway struct {
+	transparent
+	empty
+
+	// Age is the age of the gateway in ticks. Gateways emit a purple beam for
+	// a short time after being created.
+	Age int
+}
+
+// LightEmissionLevel ...
+func (EndGateway) LightEmissionLevel() uint8 {
+	return 15
+}
+
+// EntityInside ...
+func (e EndGateway) EntityInside(pos cube.Pos, _ *world.World, ent world.Entity) {
+	enterPortal(e, pos, ent)
+}
+
+// PortalDestination always returns the world the gateway is in.
+func (EndGateway) PortalDestination(_ cube.Pos, w *world.World) *world.World {
+	return w
+}
+
+// PortalTarget returns the position that the gateway leads to if dst is the
+// world the gateway is in. For other worlds, the spawn of the entity is
+// returned.
+func (EndGateway) PortalTarget(pos cube.Pos, w, dst *world.World, e world.Entity) mgl64.Vec3 {
+	if dst == w {
+		return portal.GatewayDestination(w, pos)
+	}
+	return spawnOf(dst, e)
+}
+
+// EncodeNBT ...
+func (e EndGateway) EncodeNBT() map[string]any {
+	return map[string]any{"id": "EndGateway", "Age": int32(e.Age)}
+}
+
+// DecodeNBT ...
+func (e EndGateway) DecodeNBT(m map[string]any) any {
+	e.Age = int(nbtconv.Int32(m, "Age"))
+	return e
+}
+
+// PistonImmovable ...
+func (EndGateway) PistonImmovable() bool {
+	return true
+}
+
+// EncodeBlock ...
+func (EndGateway) EncodeBlock() (string, map[string]any) {
+	return "minecraft:end_gateway", nil
+}
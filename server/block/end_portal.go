@@ -0,0 +1,63 @@
+package block
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/portal"
+)
+
+// EndPortal is the block that fills an activated end portal. Entities that
+// enter it are instantly transported to the end, or from the end back to the
+// overworld.
+type EndPortal struct {
+	transparent
+	empty
+}
+
+// LightEmissionLevel ...
+func (EndPortal) LightEmissionLevel() uint8 {
+	return 15
+}
+
+// EntityInside ...
+func (e EndPortal) EntityInside(pos cube.Pos, _ *world.World, ent world.Entity) {
+	enterPortal(e, pos, ent)
+}
+
+// PortalDestination ...
+func (EndPortal) PortalDestination(_ cube.Pos, w *world.World) *world.World {
+	if w.Dimension() == world.End {
+		return w.PortalDestination(world.Overworld)
+	}
+	return w.PortalDestination(world.End)
+}
+
+// PortalTarget returns the obsidian platform in the end if dst is an end
+// world. In other worlds, the spawn of the entity is returned.
+func (EndPortal) PortalTarget(_ cube.Pos, _, dst *world.World, e world.Entity) mgl64.Vec3 {
+	if dst.Dimension() == world.End {
+		return portal.EndPlatform(dst)
+	}
+	return spawnOf(dst, e)
+}
+
+// EncodeNBT ...
+func (EndPortal) EncodeNBT() map[string]any {
+	return map[string]any{"id": "EndPortal"}
+}
+
+// DecodeNBT ...
+func (e EndPortal) DecodeNBT(map[string]any) any {
+	return e
+}
+
+// PistonImmovable ...
+func (EndPortal) PistonImmovable() bool {
+	return true
+}
+
+// EncodeBlock ...
+func (EndPortal) EncodeBlock() (string, map[string]any) {
+	return "minecraft:end_portal", nil
+}
@@ -0,0 +1,115 @@
+package block
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/block/model"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/sound"
+)
+
+// EndPortalFrame is a block that makes up the frame of an end portal. Once
+// all twelve frames around a 3x3 area are filled with an eye of ender, the
+// area is filled with end portal blocks.
+type EndPortalFrame struct {
+	transparent
+
+	// Facing is the direction the frame is facing.
+	Facing cube.Direction
+	// Eye specifies if an eye of ender is placed in the frame.
+	Eye bool
+}
+
+// Model ...
+func (f EndPortalFrame) Model() world.BlockModel {
+	return model.EndPortalFrame{Eye: f.Eye}
+}
+
+// LightEmissionLevel ...
+func (EndPortalFrame) LightEmissionLevel() uint8 {
+	return 1
+}
+
+// UseOnBlock ...
+func (f EndPortalFrame) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) (used bool) {
+	pos, _, used = firstReplaceable(w, pos, face, f)
+	if !used {
+		return
+	}
+	f.Facing = user.Rotation().Direction().Opposite()
+
+	place(w, pos, f, user, ctx)
+	return placed(ctx)
+}
+
+// Activate ...
+func (f EndPortalFrame) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.User, ctx *item.UseContext) bool {
+	if f.Eye {
+		return false
+	}
+	held, _ := u.HeldItems()
+	if _, ok := held.Item().(item.EnderEye); !ok {
+		return false
+	}
+	f.Eye = true
+	w.SetBlock(pos, f, nil)
+	ctx.SubtractFromCount(1)
+
+	for _, off := range endPortalFrameOffsets {
+		if centre := pos.Sub(off); completeEndPortalFrame(w, centre) {
+			for x := -1; x <= 1; x++ {
+				for z := -1; z <= 1; z++ {
+					w.SetBlock(centre.Add(cube.Pos{x, 0, z}), EndPortal{}, nil)
+				}
+			}
+			w.PlaySound(centre.Vec3Centre(), sound.EndPortalCreated{})
+			break
+		}
+	}
+	return true
+}
+
+// endPortalFrameOffsets holds the offsets of the twelve end portal frames
+// around the centre of an end portal.
+var endPortalFrameOffsets = func() (offsets []cube.Pos) {
+	for i := -1; i <= 1; i++ {
+		offsets = append(offsets, cube.Pos{-2, 0, i}, cube.Pos{2, 0, i}, cube.Pos{i, 0, -2}, cube.Pos{i, 0, 2})
+	}
+	return
+}()
+
+// completeEndPortalFrame checks if the end portal with the centre passed is
+// surrounded by end portal frames that are all filled with an eye of ender.
+func completeEndPortalFrame(w *world.World, centre cube.Pos) bool {
+	for _, off := range endPortalFrameOffsets {
+		if f, ok := w.Block(centre.Add(off)).(EndPortalFrame); !ok || !f.Eye {
+			return false
+		}
+	}
+	return true
+}
+
+// PistonImmovable ...
+func (EndPortalFrame) PistonImmovable() bool {
+	return true
+}
+
+// EncodeItem ...
+func (EndPortalFrame) EncodeItem() (name string, meta int16) {
+	return "minecraft:end_portal_frame", 0
+}
+
+// EncodeBlock ...
+func (f EndPortalFrame) EncodeBlock() (string, map[string]any) {
+	return "minecraft:end_portal_frame", map[string]any{"minecraft:cardinal_direction": f.Facing.String(), "end_portal_eye_bit": f.Eye}
+}
+
+// allEndPortalFrames ...
+func allEndPortalFrames() (frames []world.Block) {
+	for _, d := range cube.Directions() {
+		frames = append(frames, EndPortalFrame{Facing: d})
+		frames = append(frames, EndPortalFrame{Facing: d, Eye: true})
+	}
+	return
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/stcraft/dragonfly/server/item"
 	"github.com/stcraft/dragonfly/server/item/enchantment"
 	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/portal"
 )
 
 // Fire is a non-solid block that can spread to nearby flammable blocks.
@@ -270,6 +271,10 @@ func (f Fire) Start(w *world.World, pos cube.Pos) {
 	_, air := b.(Air)
 	_, tallGrass := b.(TallGrass)
 	if air || tallGrass {
+		if p, ok := portal.NetherPortalFromPos(w, pos); ok && !p.Activated() {
+			p.Activate()
+			return
+		}
 		below := w.Block(pos.Side(cube.FaceDown))
 		if below.Model().FaceSolid(pos, cube.FaceUp, w) || neighboursFlammable(pos, w) {
 			w.SetBlock(pos, Fire{}, nil)
@@ -3,7 +3,8 @@
 package block
 
 const (
-	hashAir = iota
+	hashActivatorRail = iota
+	hashAir
 	hashAmethyst
 	hashAncientDebris
 	hashAndesite
@@ -13,6 +14,7 @@ const (
 	hashBarrier
 	hashBasalt
 	hashBeacon
+	hashBed
 	hashBedrock
 	hashBeetrootSeeds
 	hashBlackstone
@@ -20,7 +22,9 @@ const (
 	hashBlueIce
 	hashBone
 	hashBookshelf
+	hashBrewingStand
 	hashBricks
+	hashButton
 	hashCactus
 	hashCake
 	hashCalcite
@@ -46,6 +50,7 @@ const (
 	hashDeepslate
 	hashDeepslateBricks
 	hashDeepslateTiles
+	hashDetectorRail
 	hashDiamond
 	hashDiamondOre
 	hashDiorite
@@ -57,10 +62,14 @@ const (
 	hashDragonEgg
 	hashDriedKelp
 	hashDripstone
+	hashDropper
 	hashEmerald
 	hashEmeraldOre
 	hashEnchantingTable
 	hashEndBricks
+	hashEndGateway
+	hashEndPortal
+	hashEndPortalFrame
 	hashEndStone
 	hashEnderChest
 	hashFarmland
@@ -96,6 +105,7 @@ const (
 	hashLava
 	hashLeaves
 	hashLectern
+	hashLever
 	hashLight
 	hashLitPumpkin
 	hashLog
@@ -103,12 +113,14 @@ const (
 	hashMelon
 	hashMelonSeeds
 	hashMossCarpet
+	hashMoving
 	hashMud
 	hashMudBricks
 	hashMuddyMangroveRoots
 	hashNetherBrickFence
 	hashNetherBricks
 	hashNetherGoldOre
+	hashNetherPortal
 	hashNetherQuartzOre
 	hashNetherSprouts
 	hashNetherWart
@@ -119,10 +131,14 @@ const (
 	hashObsidian
 	hashPackedIce
 	hashPackedMud
+	hashPiston
+	hashPistonArmCollision
 	hashPlanks
 	hashPodzol
 	hashPolishedBlackstoneBrick
 	hashPotato
+	hashPoweredRail
+	hashPressurePlate
 	hashPrismarine
 	hashPumpkin
 	hashPumpkinSeeds
@@ -131,9 +147,16 @@ const (
 	hashQuartz
 	hashQuartzBricks
 	hashQuartzPillar
+	hashRail
 	hashRawCopper
 	hashRawGold
 	hashRawIron
+	hashRedstoneBlock
+	hashRedstoneComparator
+	hashRedstoneLamp
+	hashRedstoneRepeater
+	hashRedstoneTorch
+	hashRedstoneWire
 	hashReinforcedDeepslate
 	hashSand
 	hashSandstone
@@ -185,6 +208,11 @@ func NextHash() uint64 {
 	return customBlockBase
 }
 
+// Hash ...
+func (r ActivatorRail) Hash() uint64 {
+	return hashActivatorRail | uint64(r.Shape.Uint8())<<8 | uint64(boolByte(r.Powered))<<12
+}
+
 // Hash ...
 func (Air) Hash() uint64 {
 	return hashAir
@@ -235,6 +263,11 @@ func (Beacon) Hash() uint64 {
 	return hashBeacon
 }
 
+// Hash ...
+func (b Bed) Hash() uint64 {
+	return hashBed | uint64(b.Facing)<<8 | uint64(boolByte(b.Head))<<10 | uint64(boolByte(b.Occupied))<<11
+}
+
 // Hash ...
 func (b Bedrock) Hash() uint64 {
 	return hashBedrock | uint64(boolByte(b.InfiniteBurning))<<8
@@ -270,11 +303,21 @@ func (Bookshelf) Hash() uint64 {
 	return hashBookshelf
 }
 
+// Hash ...
+func (b BrewingStand) Hash() uint64 {
+	return hashBrewingStand | uint64(boolByte(b.LeftSlot))<<8 | uint64(boolByte(b.MiddleSlot))<<9 | uint64(boolByte(b.RightSlot))<<10
+}
+
 // Hash ...
 func (Bricks) Hash() uint64 {
 	return hashBricks
 }
 
+// Hash ...
+func (b Button) Hash() uint64 {
+	return hashButton | uint64(b.Type.Uint8())<<8 | uint64(b.Facing)<<12 | uint64(boolByte(b.Pressed))<<15
+}
+
 // Hash ...
 func (c Cactus) Hash() uint64 {
 	return hashCactus | uint64(c.Age)<<8
@@ -400,6 +443,11 @@ func (d DeepslateTiles) Hash() uint64 {
 	return hashDeepslateTiles | uint64(boolByte(d.Cracked))<<8
 }
 
+// Hash ...
+func (r DetectorRail) Hash() uint64 {
+	return hashDetectorRail | uint64(r.Shape.Uint8())<<8 | uint64(boolByte(r.Powered))<<12
+}
+
 // Hash ...
 func (Diamond) Hash() uint64 {
 	return hashDiamond
@@ -455,6 +503,11 @@ func (Dripstone) Hash() uint64 {
 	return hashDripstone
 }
 
+// Hash ...
+func (d Dropper) Hash() uint64 {
+	return hashDropper | uint64(d.Facing)<<8 | uint64(boolByte(d.Triggered))<<11
+}
+
 // Hash ...
 func (Emerald) Hash() uint64 {
 	return hashEmerald
@@ -475,6 +528,21 @@ func (EndBricks) Hash() uint64 {
 	return hashEndBricks
 }
 
+// Hash ...
+func (EndGateway) Hash() uint64 {
+	return hashEndGateway
+}
+
+// Hash ...
+func (EndPortal) Hash() uint64 {
+	return hashEndPortal
+}
+
+// Hash ...
+func (f EndPortalFrame) Hash() uint64 {
+	return hashEndPortalFrame | uint64(f.Facing)<<8 | uint64(boolByte(f.Eye))<<10
+}
+
 // Hash ...
 func (EndStone) Hash() uint64 {
 	return hashEndStone
@@ -650,6 +718,11 @@ func (l Lectern) Hash() uint64 {
 	return hashLectern | uint64(l.Facing)<<8
 }
 
+// Hash ...
+func (l Lever) Hash() uint64 {
+	return hashLever | uint64(l.Facing)<<8 | uint64(l.Axis)<<11 | uint64(boolByte(l.Powered))<<13
+}
+
 // Hash ...
 func (l Light) Hash() uint64 {
 	return hashLight | uint64(l.Level)<<8
@@ -685,6 +758,11 @@ func (MossCarpet) Hash() uint64 {
 	return hashMossCarpet
 }
 
+// Hash ...
+func (Moving) Hash() uint64 {
+	return hashMoving
+}
+
 // Hash ...
 func (Mud) Hash() uint64 {
 	return hashMud
@@ -715,6 +793,11 @@ func (NetherGoldOre) Hash() uint64 {
 	return hashNetherGoldOre
 }
 
+// Hash ...
+func (n NetherPortal) Hash() uint64 {
+	return hashNetherPortal | uint64(n.Axis)<<8
+}
+
 // Hash ...
 func (NetherQuartzOre) Hash() uint64 {
 	return hashNetherQuartzOre
@@ -765,6 +848,16 @@ func (PackedMud) Hash() uint64 {
 	return hashPackedMud
 }
 
+// Hash ...
+func (p Piston) Hash() uint64 {
+	return hashPiston | uint64(p.Facing)<<8 | uint64(boolByte(p.Sticky))<<11
+}
+
+// Hash ...
+func (c PistonArmCollision) Hash() uint64 {
+	return hashPistonArmCollision | uint64(c.Facing)<<8 | uint64(boolByte(c.Sticky))<<11
+}
+
 // Hash ...
 func (p Planks) Hash() uint64 {
 	return hashPlanks | uint64(p.Wood.Uint8())<<8
@@ -785,6 +878,16 @@ func (p Potato) Hash() uint64 {
 	return hashPotato | uint64(p.Growth)<<8
 }
 
+// Hash ...
+func (r PoweredRail) Hash() uint64 {
+	return hashPoweredRail | uint64(r.Shape.Uint8())<<8 | uint64(boolByte(r.Powered))<<12
+}
+
+// Hash ...
+func (p PressurePlate) Hash() uint64 {
+	return hashPressurePlate | uint64(p.Type.Uint8())<<8 | uint64(p.Power)<<12
+}
+
 // Hash ...
 func (p Prismarine) Hash() uint64 {
 	return hashPrismarine | uint64(p.Type.Uint8())<<8
@@ -825,6 +928,11 @@ func (q QuartzPillar) Hash() uint64 {
 	return hashQuartzPillar | uint64(q.Axis)<<8
 }
 
+// Hash ...
+func (r Rail) Hash() uint64 {
+	return hashRail | uint64(r.Shape.Uint8())<<8
+}
+
 // Hash ...
 func (RawCopper) Hash() uint64 {
 	return hashRawCopper
@@ -840,6 +948,36 @@ func (RawIron) Hash() uint64 {
 	return hashRawIron
 }
 
+// Hash ...
+func (RedstoneBlock) Hash() uint64 {
+	return hashRedstoneBlock
+}
+
+// Hash ...
+func (c RedstoneComparator) Hash() uint64 {
+	return hashRedstoneComparator | uint64(c.Facing)<<8 | uint64(boolByte(c.Subtract))<<10 | uint64(boolByte(c.Powered))<<11
+}
+
+// Hash ...
+func (l RedstoneLamp) Hash() uint64 {
+	return hashRedstoneLamp | uint64(boolByte(l.Lit))<<8
+}
+
+// Hash ...
+func (r RedstoneRepeater) Hash() uint64 {
+	return hashRedstoneRepeater | uint64(r.Facing)<<8 | uint64(r.Delay)<<10 | uint64(boolByte(r.Powered))<<18
+}
+
+// Hash ...
+func (t RedstoneTorch) Hash() uint64 {
+	return hashRedstoneTorch | uint64(t.Facing)<<8 | uint64(boolByte(t.Lit))<<11
+}
+
+// Hash ...
+func (r RedstoneWire) Hash() uint64 {
+	return hashRedstoneWire | uint64(r.Power)<<8
+}
+
 // Hash ...
 func (ReinforcedDeepslate) Hash() uint64 {
 	return hashReinforcedDeepslate
@@ -956,8 +1094,8 @@ func (s Stonecutter) Hash() uint64 {
 }
 
 // Hash ...
-func (StructureBlock) Hash() uint64 {
-	return hashStructureBlock
+func (s StructureBlock) Hash() uint64 {
+	return hashStructureBlock | uint64(s.Mode.Uint8())<<8
 }
 
 // Hash ...
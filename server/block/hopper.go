@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/event"
 	"github.com/stcraft/dragonfly/server/internal/nbtconv"
 	"github.com/stcraft/dragonfly/server/item"
 	"github.com/stcraft/dragonfly/server/item/inventory"
@@ -25,8 +26,15 @@ type Hopper struct {
 	inventory *inventory.Inventory
 	viewerMu  *sync.RWMutex
 	viewers   map[ContainerViewer]struct{}
+
+	// lastTransfer is the world tick at which the hopper last transferred an item.
+	lastTransfer int64
 }
 
+// hopperTransferCooldown is the amount of ticks a hopper waits after transferring an item before it transfers
+// another item.
+const hopperTransferCooldown = 8
+
 // NewHopper creates a new initialised hopper. The inventory of the hopper
 // is properly initialised. You still need to set the hopper's facing direction
 // etc.
@@ -83,6 +91,11 @@ func (h Hopper) Inventory() *inventory.Inventory {
 	return h.inventory
 }
 
+// Changed returns true if the inventory of the hopper changed since the last call to Changed.
+func (h Hopper) Changed() bool {
+	return h.inventory != nil && h.inventory.Changed()
+}
+
 // AddViewer adds a viewer to the hopper, so that it is updated whenever the inventory of the hopper is changed.
 func (h Hopper) AddViewer(v ContainerViewer, w *world.World, pos cube.Pos) {
 	h.viewerMu.Lock()
@@ -112,28 +125,203 @@ func (h Hopper) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.User,
 	return false
 }
 
+// BreakInfo ...
+func (h Hopper) BreakInfo() BreakInfo {
+	return newBreakInfo(3, pickaxeHarvestable, pickaxeEffective, oneOf(Hopper{}))
+}
+
 // UseOnBlock ...
 func (h Hopper) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) (used bool) {
-	pos, _, used = firstReplaceable(w, pos, face, h)
+	pos, face, used = firstReplaceable(w, pos, face, h)
 	if !used {
 		return
 	}
 
 	//noinspection GoAssignmentToReceiver
 	h = NewHopper()
-	h.Facing = user.Rotation().Direction().Face()
+	h.Facing = cube.FaceDown
+	if face.Axis() != cube.Y {
+		h.Facing = face.Opposite()
+	}
 
 	place(w, pos, h, user, ctx)
 	return placed(ctx)
 }
 
+// NeighbourUpdateTick ...
+func (h Hopper) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if powered := w.ReceivedRedstonePower(pos, true) > 0; powered != h.Triggered {
+		h.Triggered = powered
+		w.SetBlock(pos, h, &world.SetOpts{DisableBlockUpdates: true})
+	}
+}
+
+// Tick ...
+func (h Hopper) Tick(currentTick int64, pos cube.Pos, w *world.World) {
+	if h.Triggered || h.inventory == nil || currentTick-h.lastTransfer < hopperTransferCooldown {
+		return
+	}
+	inserted := h.insertItem(pos, w)
+	extracted := h.extractItem(pos, w)
+	if inserted || extracted {
+		h.lastTransfer = currentTick
+		w.SetBlock(pos, h, &world.SetOpts{DisableBlockUpdates: true})
+	}
+}
+
+// CollectItem collects as much of the item stack passed into the inventory of the hopper as possible. It is
+// called for item entities lying on top of the hopper. The amount of items collected is returned. Hoppers locked
+// by redstone power do not collect any items.
+func (h Hopper) CollectItem(it item.Stack) (n int) {
+	if h.Triggered || h.inventory == nil {
+		return 0
+	}
+	for n < it.Count() {
+		slot, ok := acceptingSlot(h.inventory, inventorySlots(h.inventory), it)
+		if !ok {
+			break
+		}
+		existing, _ := h.inventory.Item(slot)
+		add := min(it.Count()-n, it.MaxCount()-existing.Count())
+
+		ctx := event.C()
+		if h.inventory.Handler().HandlePlace(ctx, slot, it.Grow(add-it.Count())); ctx.Cancelled() {
+			break
+		}
+		_ = h.inventory.SetItem(slot, it.Grow(existing.Count()+add-it.Count()))
+		n += add
+	}
+	return n
+}
+
+// insertItem moves a single item from the hopper into the container that the hopper is facing. True is returned
+// if an item was moved.
+func (h Hopper) insertItem(pos cube.Pos, w *world.World) bool {
+	dest, ok := w.Block(pos.Side(h.Facing)).(Container)
+	if !ok {
+		return false
+	}
+	for slot, it := range h.inventory.Slots() {
+		if it.Empty() {
+			continue
+		}
+		if transferItem(h.inventory, slot, dest.Inventory(), insertionSlots(dest, h.Facing.Opposite(), it)) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractItem moves a single item from the container above the hopper into the hopper. True is returned if an
+// item was moved.
+func (h Hopper) extractItem(pos cube.Pos, w *world.World) bool {
+	src, ok := w.Block(pos.Side(cube.FaceUp)).(Container)
+	if !ok {
+		return false
+	}
+	inv := src.Inventory()
+	for _, slot := range extractionSlots(src) {
+		if it, _ := inv.Item(slot); it.Empty() {
+			continue
+		}
+		if transferItem(inv, slot, h.inventory, inventorySlots(h.inventory)) {
+			return true
+		}
+	}
+	return false
+}
+
+// transferItem moves a single item from a slot in the inventory src to one of the slots passed in the inventory
+// dst. The Handlers of both inventories are called, and the item is not moved if either cancels the transfer.
+// True is returned if the item was moved.
+func transferItem(src *inventory.Inventory, srcSlot int, dst *inventory.Inventory, dstSlots []int) bool {
+	it, _ := src.Item(srcSlot)
+	if it.Empty() {
+		return false
+	}
+	single := it.Grow(1 - it.Count())
+	slot, ok := acceptingSlot(dst, dstSlots, single)
+	if !ok {
+		return false
+	}
+	ctx := event.C()
+	if src.Handler().HandleTake(ctx, srcSlot, single); ctx.Cancelled() {
+		return false
+	}
+	if dst.Handler().HandlePlace(ctx, slot, single); ctx.Cancelled() {
+		return false
+	}
+	existing, _ := dst.Item(slot)
+	_ = src.SetItem(srcSlot, it.Grow(-1))
+	_ = dst.SetItem(slot, single.Grow(existing.Count()))
+	return true
+}
+
+// acceptingSlot returns the first of the slots passed in the inventory that can accept the item stack passed. Slots
+// holding a stack of the same item are preferred over empty slots.
+func acceptingSlot(inv *inventory.Inventory, slots []int, it item.Stack) (int, bool) {
+	empty := -1
+	for _, slot := range slots {
+		existing, err := inv.Item(slot)
+		if err != nil {
+			continue
+		}
+		if existing.Empty() {
+			if empty == -1 {
+				empty = slot
+			}
+			continue
+		}
+		if existing.Comparable(it) && existing.Count() < existing.MaxCount() {
+			return slot, true
+		}
+	}
+	return empty, empty != -1
+}
+
+// insertionSlots returns the slots of the Container passed that a hopper may insert the item stack passed into
+// through the face of the container passed. Smelters only accept items to smelt from above and fuel from their
+// sides.
+func insertionSlots(c Container, face cube.Face, it item.Stack) []int {
+	switch c.(type) {
+	case Furnace, BlastFurnace, Smoker:
+		if face == cube.FaceUp {
+			return []int{0}
+		}
+		if f, ok := it.Item().(item.Fuel); ok && f.FuelInfo().Duration > 0 {
+			return []int{1}
+		}
+		return nil
+	}
+	return inventorySlots(c.Inventory())
+}
+
+// extractionSlots returns the slots of the Container passed that a hopper below it may extract items from. Hoppers
+// only extract the products of smelters.
+func extractionSlots(c Container) []int {
+	switch c.(type) {
+	case Furnace, BlastFurnace, Smoker:
+		return []int{2}
+	}
+	return inventorySlots(c.Inventory())
+}
+
+// inventorySlots returns the indices of all slots of the inventory passed.
+func inventorySlots(inv *inventory.Inventory) []int {
+	slots := make([]int, inv.Size())
+	for i := range slots {
+		slots[i] = i
+	}
+	return slots
+}
+
 // DecodeNBT ...
 func (h Hopper) DecodeNBT(data map[string]any) any {
-	facing := h.Facing
+	facing, triggered := h.Facing, h.Triggered
 	//noinspection GoAssignmentToReceiver
 	h = NewHopper()
 
-	h.Facing = facing
+	h.Facing, h.Triggered = facing, triggered
 	h.CustomName = nbtconv.String(data, "CustomName")
 	nbtconv.InvFromNBT(h.inventory, nbtconv.Slice(data, "Items"))
 
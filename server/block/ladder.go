@@ -80,6 +80,11 @@ func (Ladder) FuelInfo() item.FuelInfo {
 	return newFuelInfo(time.Second * 15)
 }
 
+// PistonBreakable ...
+func (Ladder) PistonBreakable() bool {
+	return true
+}
+
 // EncodeItem ...
 func (l Ladder) EncodeItem() (name string, meta int16) {
 	return "minecraft:ladder", 0
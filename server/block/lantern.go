@@ -80,6 +80,11 @@ func (l Lantern) BreakInfo() BreakInfo {
 	return newBreakInfo(5, pickaxeHarvestable, pickaxeEffective, oneOf(l))
 }
 
+// PistonBreakable ...
+func (Lantern) PistonBreakable() bool {
+	return true
+}
+
 // EncodeItem ...
 func (l Lantern) EncodeItem() (name string, meta int16) {
 	switch l.Type {
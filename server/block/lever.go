@@ -0,0 +1,116 @@
+package block
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/sound"
+)
+
+// Lever is a non-solid block that can provide switchable redstone power.
+type Lever struct {
+	empty
+	transparent
+
+	// Facing is the direction from the block the lever is attached to towards the lever.
+	Facing cube.Face
+	// Axis is the axis the lever is aligned along if it is placed on the top or bottom of a block. It is
+	// either cube.X or cube.Z.
+	Axis cube.Axis
+	// Powered is whether the lever is switched on and emitting power.
+	Powered bool
+}
+
+// BreakInfo ...
+func (l Lever) BreakInfo() BreakInfo {
+	return newBreakInfo(0.5, alwaysHarvestable, nothingEffective, oneOf(Lever{})).withBreakHandler(updateRedstoneOnBreak)
+}
+
+// HasLiquidDrops ...
+func (Lever) HasLiquidDrops() bool {
+	return true
+}
+
+// UseOnBlock ...
+func (l Lever) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) bool {
+	pos, face, used := firstReplaceable(w, pos, face, l)
+	if !used {
+		return false
+	}
+	if !attachedTo(pos, face.Opposite(), w) {
+		return false
+	}
+	l.Facing, l.Axis = face, cube.X
+	if face.Axis() == cube.Y && user.Rotation().Direction().Face().Axis() == cube.Z {
+		l.Axis = cube.Z
+	}
+
+	place(w, pos, l, user, ctx)
+	return placed(ctx)
+}
+
+// Activate ...
+func (l Lever) Activate(pos cube.Pos, _ cube.Face, w *world.World, _ item.User, _ *item.UseContext) bool {
+	l.Powered = !l.Powered
+	setRedstone(pos, l, w)
+	if l.Powered {
+		w.PlaySound(pos.Vec3Centre(), sound.PowerOn{Block: l})
+	} else {
+		w.PlaySound(pos.Vec3Centre(), sound.PowerOff{Block: l})
+	}
+	return true
+}
+
+// NeighbourUpdateTick ...
+func (l Lever) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	breakUnattached(l, pos, l.Facing.Opposite(), w)
+}
+
+// WeakPower ...
+func (l Lever) WeakPower(cube.Pos, cube.Face, *world.World, bool) int {
+	if l.Powered {
+		return 15
+	}
+	return 0
+}
+
+// StrongPower ...
+func (l Lever) StrongPower(_ cube.Pos, face cube.Face, _ *world.World, _ bool) int {
+	if l.Powered && face == l.Facing.Opposite() {
+		return 15
+	}
+	return 0
+}
+
+// EncodeItem ...
+func (Lever) EncodeItem() (name string, meta int16) {
+	return "minecraft:lever", 0
+}
+
+// EncodeBlock ...
+func (l Lever) EncodeBlock() (string, map[string]any) {
+	direction := l.Facing.String()
+	if l.Facing.Axis() == cube.Y {
+		if l.Axis == cube.Z {
+			direction += "_north_south"
+		} else {
+			direction += "_east_west"
+		}
+	}
+	return "minecraft:lever", map[string]any{"lever_direction": direction, "open_bit": boolByte(l.Powered)}
+}
+
+// allLevers ...
+func allLevers() (levers []world.Block) {
+	for _, f := range cube.Faces() {
+		axes := []cube.Axis{cube.X}
+		if f.Axis() == cube.Y {
+			axes = append(axes, cube.Z)
+		}
+		for _, a := range axes {
+			levers = append(levers, Lever{Facing: f, Axis: a}, Lever{Facing: f, Axis: a, Powered: true})
+		}
+	}
+	return
+}
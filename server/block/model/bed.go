@@ -0,0 +1,19 @@
+package model
+
+import (
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// Bed is a model used by beds.
+type Bed struct{}
+
+// BBox ...
+func (Bed) BBox(cube.Pos, *world.World) []cube.BBox {
+	return []cube.BBox{cube.Box(0, 0, 0, 1, 0.5625, 1)}
+}
+
+// FaceSolid ...
+func (Bed) FaceSolid(cube.Pos, cube.Face, *world.World) bool {
+	return false
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// Diode is a model for diode-like flat blocks, such as redstone repeaters and comparators.
+type Diode struct{}
+
+// BBox returns a flat BBox with a height of 0.125.
+func (Diode) BBox(cube.Pos, *world.World) []cube.BBox {
+	return []cube.BBox{cube.Box(0, 0, 0, 1, 0.125, 1)}
+}
+
+// FaceSolid only returns true for the bottom face of the Diode.
+func (Diode) FaceSolid(_ cube.Pos, face cube.Face, _ *world.World) bool {
+	return face == cube.FaceDown
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// Piston is the model of a piston base. When the arm of the piston is extended, the base no longer occupies the
+// part of the block that the arm retracts into.
+type Piston struct {
+	// Facing is the face that the piston pushes blocks towards.
+	Facing cube.Face
+	// Extended specifies if the arm of the piston is extended.
+	Extended bool
+}
+
+// BBox ...
+func (p Piston) BBox(cube.Pos, *world.World) []cube.BBox {
+	if p.Extended {
+		return []cube.BBox{full.ExtendTowards(p.Facing, -0.25)}
+	}
+	return []cube.BBox{full}
+}
+
+// FaceSolid returns true for all faces if the piston is not extended. If it is, only the face opposite to the
+// arm is solid.
+func (p Piston) FaceSolid(_ cube.Pos, face cube.Face, _ *world.World) bool {
+	return !p.Extended || face == p.Facing.Opposite()
+}
+
+// PistonArm is the model of the extended arm of a piston, which consists of a flat head and a rod that connects
+// it to the piston base.
+type PistonArm struct {
+	// Facing is the face that the arm is facing.
+	Facing cube.Face
+}
+
+// BBox ...
+func (p PistonArm) BBox(cube.Pos, *world.World) []cube.BBox {
+	return []cube.BBox{
+		full.ExtendTowards(p.Facing.Opposite(), -0.75),
+		cube.Box(0.375, 0.375, 0.375, 0.625, 0.625, 0.625).Stretch(p.Facing.Axis(), 0.375),
+	}
+}
+
+// FaceSolid only returns true for the face of the head of the arm.
+func (p PistonArm) FaceSolid(_ cube.Pos, face cube.Face, _ *world.World) bool {
+	return face == p.Facing
+}
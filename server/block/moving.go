@@ -0,0 +1,84 @@
+package block
+
+import (
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// Moving is a block that takes the place of a block while it is being moved by a piston. Once the piston has
+// finished moving, the Moving block is replaced with the block it was moving.
+type Moving struct {
+	transparent
+	empty
+
+	// Moving is the block that is being moved.
+	Moving world.Block
+	// Piston is the position of the piston that is moving the block.
+	Piston cube.Pos
+	// Expanding specifies if the block is being pushed by an extending piston. If false, the block is being
+	// pulled by a retracting sticky piston.
+	Expanding bool
+}
+
+// PistonImmovable ...
+func (Moving) PistonImmovable() bool {
+	return true
+}
+
+// Tick ...
+func (m Moving) Tick(_ int64, pos cube.Pos, w *world.World) {
+	if p, ok := w.Block(m.Piston).(Piston); !ok || !p.moving() {
+		// The piston moving the block no longer exists or has stopped moving without placing the block, for
+		// example because it was destroyed.
+		m.finish(pos, w)
+	}
+}
+
+// finish replaces the Moving block at the position passed with the block it is moving.
+func (m Moving) finish(pos cube.Pos, w *world.World) {
+	if m.Moving == nil {
+		w.SetBlock(pos, nil, nil)
+		return
+	}
+	w.SetBlock(pos, m.Moving, nil)
+}
+
+// EncodeBlock ...
+func (Moving) EncodeBlock() (string, map[string]any) {
+	return "minecraft:moving_block", nil
+}
+
+// EncodeNBT ...
+func (m Moving) EncodeNBT() map[string]any {
+	moving := m.Moving
+	if moving == nil {
+		moving = Air{}
+	}
+	data := map[string]any{
+		"id":               "MovingBlock",
+		"movingBlock":      nbtconv.WriteBlock(moving),
+		"movingBlockExtra": nbtconv.WriteBlock(Air{}),
+		"pistonPosX":       int32(m.Piston.X()),
+		"pistonPosY":       int32(m.Piston.Y()),
+		"pistonPosZ":       int32(m.Piston.Z()),
+		"expanding":        m.Expanding,
+	}
+	if nbt, ok := moving.(world.NBTer); ok {
+		data["movingEntity"] = nbt.EncodeNBT()
+	}
+	return data
+}
+
+// DecodeNBT ...
+func (m Moving) DecodeNBT(data map[string]any) any {
+	m.Moving = nbtconv.Block(data, "movingBlock")
+	if nbt, ok := m.Moving.(world.NBTer); ok {
+		if entity, ok := data["movingEntity"].(map[string]any); ok {
+			m.Moving = nbt.DecodeNBT(entity).(world.Block)
+		}
+	}
+	m.Piston = cube.Pos{int(nbtconv.Int32(data, "pistonPosX")), int(nbtconv.Int32(data, "pistonPosY")), int(nbtconv.Int32(data, "pistonPosZ"))}
+	m.Expanding = nbtconv.Bool(data, "expanding")
+	return m
+}
@@ -0,0 +1,68 @@
+package block
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/portal"
+)
+
+// NetherPortal is the block that fills the interior of an activated nether
+// portal frame. Entities that stand in it for long enough are transported
+// between the overworld and the nether.
+type NetherPortal struct {
+	transparent
+	empty
+
+	// Axis is the axis along which the portal is oriented. It is either cube.X
+	// or cube.Z.
+	Axis cube.Axis
+}
+
+// LightEmissionLevel ...
+func (NetherPortal) LightEmissionLevel() uint8 {
+	return 11
+}
+
+// EntityInside ...
+func (n NetherPortal) EntityInside(pos cube.Pos, _ *world.World, e world.Entity) {
+	enterPortal(n, pos, e)
+}
+
+// PortalDestination ...
+func (NetherPortal) PortalDestination(_ cube.Pos, w *world.World) *world.World {
+	if w.Dimension() == world.Nether {
+		return w.PortalDestination(world.Overworld)
+	}
+	return w.PortalDestination(world.Nether)
+}
+
+// PortalTarget finds the nether portal closest to the position in dst that
+// corresponds to the position of the entity, creating a new portal if none
+// exists nearby.
+func (n NetherPortal) PortalTarget(_ cube.Pos, w, dst *world.World, e world.Entity) mgl64.Vec3 {
+	target := portal.NetherPosition(e.Position(), w.Dimension(), dst.Dimension())
+	return portal.FindOrCreateNetherPortal(dst, cube.PosFromVec3(target), n.Axis).Spawn()
+}
+
+// NeighbourUpdateTick ...
+func (n NetherPortal) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if _, ok := portal.NetherPortalFromPos(w, pos); !ok {
+		w.SetBlock(pos, nil, nil)
+	}
+}
+
+// PistonImmovable ...
+func (NetherPortal) PistonImmovable() bool {
+	return true
+}
+
+// EncodeBlock ...
+func (n NetherPortal) EncodeBlock() (string, map[string]any) {
+	return "minecraft:portal", map[string]any{"portal_axis": n.Axis.String()}
+}
+
+// allNetherPortals ...
+func allNetherPortals() []world.Block {
+	return []world.Block{NetherPortal{Axis: cube.X}, NetherPortal{Axis: cube.Z}}
+}
@@ -21,6 +21,11 @@ func (o Obsidian) LightEmissionLevel() uint8 {
 	return 0
 }
 
+// PistonImmovable ...
+func (Obsidian) PistonImmovable() bool {
+	return true
+}
+
 // EncodeItem ...
 func (o Obsidian) EncodeItem() (name string, meta int16) {
 	if o.Crying {
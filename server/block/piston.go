@@ -0,0 +1,372 @@
+package block
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/block/model"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/sound"
+)
+
+// Piston is a block that pushes the blocks in front of it when it is powered by redstone. Sticky pistons
+// additionally pull the block in front of their arm back when they retract.
+type Piston struct {
+	// Facing is the face that the piston pushes blocks towards.
+	Facing cube.Face
+	// Sticky specifies if the piston is a sticky piston.
+	Sticky bool
+
+	// Extended specifies if the arm of the piston is extended or currently extending.
+	Extended bool
+	// Progress is the progress of the arm of the piston, ranging from 0 when it is fully retracted to 1 when it
+	// is fully extended.
+	Progress float64
+}
+
+// PistonImmovable represents a block that cannot be pushed or pulled by a piston. Blocks that hold a block
+// entity, such as chests, and blocks that cannot be broken are immovable unless they implement this interface.
+type PistonImmovable interface {
+	// PistonImmovable returns true if the block cannot be moved by a piston.
+	PistonImmovable() bool
+}
+
+// PistonBreakable represents a block that is broken when a piston pushes it, rather than being moved. Blocks
+// without a collision box, such as torches and flowers, are broken by pistons unless they implement this
+// interface.
+type PistonBreakable interface {
+	// PistonBreakable returns true if the block is broken when pushed by a piston.
+	PistonBreakable() bool
+}
+
+// pistonPushLimit is the maximum amount of blocks that a piston is able to push at once.
+const pistonPushLimit = 12
+
+// NewPiston creates a new initialised piston. If sticky is true, the piston returned is a sticky piston.
+func NewPiston(sticky bool) Piston {
+	return Piston{Facing: cube.FaceUp, Sticky: sticky}
+}
+
+// Model ...
+func (p Piston) Model() world.BlockModel {
+	return model.Piston{Facing: p.Facing, Extended: p.Extended || p.Progress > 0}
+}
+
+// BreakInfo ...
+func (p Piston) BreakInfo() BreakInfo {
+	return newBreakInfo(1.5, alwaysHarvestable, pickaxeEffective, oneOf(Piston{Sticky: p.Sticky}))
+}
+
+// PistonImmovable ...
+func (p Piston) PistonImmovable() bool {
+	return p.Extended || p.Progress > 0
+}
+
+// UseOnBlock ...
+func (p Piston) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) (used bool) {
+	pos, _, used = firstReplaceable(w, pos, face, p)
+	if !used {
+		return
+	}
+	//noinspection GoAssignmentToReceiver
+	p = NewPiston(p.Sticky)
+	p.Facing = calculateFace(user, pos)
+
+	place(w, pos, p, user, ctx)
+	return placed(ctx)
+}
+
+// NeighbourUpdateTick ...
+func (p Piston) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if p.moving() {
+		// The arm is still moving. The power is checked again once it has finished.
+		return
+	}
+	if powered := p.powered(pos, w); powered && !p.Extended {
+		p.extend(pos, w)
+	} else if !powered && p.Extended {
+		p.retract(pos, w)
+	}
+}
+
+// Tick ...
+func (p Piston) Tick(_ int64, pos cube.Pos, w *world.World) {
+	if !p.moving() {
+		return
+	}
+	if p.Extended {
+		p.Progress = math.Min(p.Progress+0.5, 1)
+		p.pushEntities(pos, w)
+	} else {
+		p.Progress = math.Max(p.Progress-0.5, 0)
+	}
+	w.SetBlock(pos, p, &world.SetOpts{DisableBlockUpdates: true})
+	if !p.moving() {
+		p.finishMovement(pos, w)
+		p.NeighbourUpdateTick(pos, pos, w)
+	}
+}
+
+// moving checks if the arm of the piston is currently extending or retracting.
+func (p Piston) moving() bool {
+	return (p.Extended && p.Progress < 1) || (!p.Extended && p.Progress > 0)
+}
+
+// powered checks if the piston at the position passed receives redstone power. Pistons are not powered by the
+// block directly in front of them.
+func (p Piston) powered(pos cube.Pos, w *world.World) bool {
+	for _, face := range cube.Faces() {
+		if face != p.Facing && w.RedstonePower(pos.Side(face), face.Opposite(), true) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// extend attempts to extend the arm of the piston at the position passed, moving the blocks in front of it. If
+// these blocks cannot be pushed, nothing happens.
+func (p Piston) extend(pos cube.Pos, w *world.World) {
+	pushed, broken, ok := p.pushedBlocks(pos, w)
+	if !ok {
+		return
+	}
+	if broken != nil {
+		breakPushed(*broken, w)
+	}
+	// Blocks are moved starting with the block furthest away from the piston, so that no block is overwritten
+	// before it is moved.
+	for i := len(pushed) - 1; i >= 0; i-- {
+		from := pushed[i]
+		w.SetBlock(from.Side(p.Facing), Moving{Moving: w.Block(from), Piston: pos, Expanding: true}, nil)
+	}
+	p.Extended, p.Progress = true, 0
+	w.SetBlock(pos, p, nil)
+	w.SetBlock(pos.Side(p.Facing), PistonArmCollision{Facing: p.Facing, Sticky: p.Sticky}, nil)
+	w.PlaySound(pos.Vec3Centre(), sound.PistonExtend{})
+}
+
+// retract retracts the arm of the piston at the position passed. Sticky pistons pull the block in front of the
+// arm back with them.
+func (p Piston) retract(pos cube.Pos, w *world.World) {
+	arm := pos.Side(p.Facing)
+	if _, ok := w.Block(arm).(PistonArmCollision); ok {
+		w.SetBlock(arm, nil, nil)
+	}
+	if pulled := arm.Side(p.Facing); p.Sticky && !pulled.OutOfBounds(w.Range()) {
+		if b := w.Block(pulled); pistonMovementOf(b) == pistonMovable {
+			w.SetBlock(pulled, nil, nil)
+			w.SetBlock(arm, Moving{Moving: b, Piston: pos}, nil)
+		}
+	}
+	p.Extended, p.Progress = false, 1
+	w.SetBlock(pos, p, nil)
+	w.PlaySound(pos.Vec3Centre(), sound.PistonRetract{})
+}
+
+// pushedBlocks returns the positions of the blocks that are pushed by the piston at the position passed when it
+// extends, ordered from the nearest block to the furthest. If the blocks are followed by a block that is broken
+// when pushed, its position is returned too. False is returned if the blocks cannot be pushed, either because
+// there are too many of them or because one of them is immovable.
+func (p Piston) pushedBlocks(pos cube.Pos, w *world.World) (pushed []cube.Pos, broken *cube.Pos, ok bool) {
+	current := pos
+	for {
+		current = current.Side(p.Facing)
+		if current.OutOfBounds(w.Range()) {
+			return nil, nil, false
+		}
+		switch pistonMovementOf(w.Block(current)) {
+		case pistonFree:
+			return pushed, nil, true
+		case pistonBreaks:
+			return pushed, &current, true
+		case pistonImmovable:
+			return nil, nil, false
+		}
+		if pushed = append(pushed, current); len(pushed) > pistonPushLimit {
+			return nil, nil, false
+		}
+	}
+}
+
+// finishMovement replaces the moving blocks of the piston at the position passed with the blocks that they were
+// moving, once the arm of the piston has stopped moving.
+func (p Piston) finishMovement(pos cube.Pos, w *world.World) {
+	current := pos.Side(p.Facing)
+	if p.Extended {
+		// The arm itself is in front of the piston, so the moved blocks start one block further.
+		current = current.Side(p.Facing)
+	}
+	for i := 0; i < pistonPushLimit && !current.OutOfBounds(w.Range()); i++ {
+		m, ok := w.Block(current).(Moving)
+		if !ok || m.Piston != pos {
+			return
+		}
+		m.finish(current, w)
+		current = current.Side(p.Facing)
+	}
+}
+
+// pushEntities pushes the entities in the way of the extending arm of the piston at the position passed, or the
+// blocks it moves, along with them.
+func (p Piston) pushEntities(pos cube.Pos, w *world.World) {
+	offset := cube.Pos{}.Side(p.Facing).Vec3().Mul(0.5)
+	current := pos.Side(p.Facing)
+	for i := 0; i <= pistonPushLimit; i++ {
+		if i > 0 {
+			if m, ok := w.Block(current).(Moving); !ok || m.Piston != pos {
+				return
+			}
+		}
+		box := cube.Box(0, 0, 0, 1, 1, 1).Translate(current.Vec3())
+		for _, e := range w.EntitiesWithin(box, nil) {
+			if t, ok := e.(interface{ Teleport(pos mgl64.Vec3) }); ok {
+				t.Teleport(e.Position().Add(offset))
+			} else if v, ok := e.(interface{ SetVelocity(v mgl64.Vec3) }); ok {
+				v.SetVelocity(offset)
+			}
+		}
+		current = current.Side(p.Facing)
+	}
+}
+
+// pistonMovement describes how a block reacts to being pushed by a piston.
+type pistonMovement int
+
+const (
+	// pistonFree is the movement of blocks that take up no space, such as air and liquids. Pushed blocks may
+	// be moved into them.
+	pistonFree pistonMovement = iota
+	// pistonMovable is the movement of blocks that can be pushed and pulled by pistons.
+	pistonMovable
+	// pistonBreaks is the movement of blocks that are broken when they are pushed.
+	pistonBreaks
+	// pistonImmovable is the movement of blocks that stop pistons from extending.
+	pistonImmovable
+)
+
+// pistonMovementOf returns the pistonMovement of the block passed.
+func pistonMovementOf(b world.Block) pistonMovement {
+	if _, ok := b.(Air); ok {
+		return pistonFree
+	}
+	if _, ok := b.(world.Liquid); ok {
+		return pistonFree
+	}
+	if i, ok := b.(PistonImmovable); ok && i.PistonImmovable() {
+		return pistonImmovable
+	}
+	if br, ok := b.(PistonBreakable); ok {
+		if br.PistonBreakable() {
+			return pistonBreaks
+		}
+	} else if _, ok := b.Model().(model.Empty); ok {
+		return pistonBreaks
+	}
+	if _, ok := b.(PistonImmovable); !ok {
+		if _, ok := b.(world.NBTer); ok {
+			return pistonImmovable
+		}
+	}
+	if br, ok := b.(Breakable); !ok || br.BreakInfo().Hardness < 0 {
+		return pistonImmovable
+	}
+	return pistonMovable
+}
+
+// breakPushed breaks the block at the position passed as a result of a piston pushing it, dropping its drops.
+func breakPushed(pos cube.Pos, w *world.World) {
+	b := w.Block(pos)
+	w.SetBlock(pos, nil, nil)
+	if br, ok := b.(Breakable); ok {
+		for _, drop := range br.BreakInfo().Drops(item.ToolNone{}, nil) {
+			dropItem(w, drop, pos.Vec3Centre())
+		}
+	}
+}
+
+// EncodeItem ...
+func (p Piston) EncodeItem() (name string, meta int16) {
+	if p.Sticky {
+		return "minecraft:sticky_piston", 0
+	}
+	return "minecraft:piston", 0
+}
+
+// EncodeBlock ...
+func (p Piston) EncodeBlock() (string, map[string]any) {
+	if p.Sticky {
+		return "minecraft:sticky_piston", map[string]any{"facing_direction": pistonFacingDirection(p.Facing)}
+	}
+	return "minecraft:piston", map[string]any{"facing_direction": pistonFacingDirection(p.Facing)}
+}
+
+// pistonFacingDirection returns the facing direction value used to encode pistons and their arms facing the face
+// passed. Unlike other blocks, the values of horizontal faces point in the opposite direction of the arm.
+func pistonFacingDirection(f cube.Face) int32 {
+	if f == cube.FaceUp || f == cube.FaceDown {
+		return int32(f)
+	}
+	return int32(f.Opposite())
+}
+
+// Piston arm states as used in the NBT of pistons.
+const (
+	pistonStateRetracted byte = iota
+	pistonStateExtending
+	pistonStateExtended
+	pistonStateRetracting
+)
+
+// state returns the state of the arm of the piston as used in its NBT.
+func (p Piston) state() byte {
+	switch {
+	case p.Extended && p.Progress < 1:
+		return pistonStateExtending
+	case p.Extended:
+		return pistonStateExtended
+	case p.Progress > 0:
+		return pistonStateRetracting
+	}
+	return pistonStateRetracted
+}
+
+// EncodeNBT ...
+func (p Piston) EncodeNBT() map[string]any {
+	last := p.Progress
+	switch p.state() {
+	case pistonStateExtending:
+		last = math.Max(p.Progress-0.5, 0)
+	case pistonStateRetracting:
+		last = math.Min(p.Progress+0.5, 1)
+	}
+	return map[string]any{
+		"id":             "PistonArm",
+		"Progress":       float32(p.Progress),
+		"LastProgress":   float32(last),
+		"State":          p.state(),
+		"NewState":       p.state(),
+		"Sticky":         p.Sticky,
+		"AttachedBlocks": []int32{},
+		"BreakBlocks":    []int32{},
+	}
+}
+
+// DecodeNBT ...
+func (p Piston) DecodeNBT(data map[string]any) any {
+	state := nbtconv.Uint8(data, "State")
+	p.Extended = state == pistonStateExtending || state == pistonStateExtended
+	p.Progress = float64(nbtconv.Float32(data, "Progress"))
+	return p
+}
+
+// allPistons ...
+func allPistons() (pistons []world.Block) {
+	for _, f := range cube.Faces() {
+		pistons = append(pistons, Piston{Facing: f})
+		pistons = append(pistons, Piston{Facing: f, Sticky: true})
+	}
+	return
+}
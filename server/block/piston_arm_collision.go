@@ -0,0 +1,70 @@
+package block
+
+import (
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/block/model"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/particle"
+)
+
+// PistonArmCollision is the block placed in front of a piston when its arm is extended. Breaking it also breaks
+// the piston it belongs to.
+type PistonArmCollision struct {
+	transparent
+
+	// Facing is the face that the arm is facing.
+	Facing cube.Face
+	// Sticky specifies if the arm belongs to a sticky piston.
+	Sticky bool
+}
+
+// Model ...
+func (c PistonArmCollision) Model() world.BlockModel {
+	return model.PistonArm{Facing: c.Facing}
+}
+
+// BreakInfo ...
+func (c PistonArmCollision) BreakInfo() BreakInfo {
+	return newBreakInfo(1.5, alwaysHarvestable, pickaxeEffective, simpleDrops()).withBreakHandler(func(pos cube.Pos, w *world.World, u item.User) {
+		base := pos.Side(c.Facing.Opposite())
+		p, ok := w.Block(base).(Piston)
+		if !ok || p.Facing != c.Facing {
+			return
+		}
+		w.SetBlock(base, nil, nil)
+		w.AddParticle(base.Vec3Centre(), particle.BlockBreak{Block: p})
+		if g, ok := u.(interface{ GameMode() world.GameMode }); !ok || !g.GameMode().CreativeInventory() {
+			dropItem(w, item.NewStack(Piston{Sticky: p.Sticky}, 1), base.Vec3Centre())
+		}
+	})
+}
+
+// PistonImmovable ...
+func (PistonArmCollision) PistonImmovable() bool {
+	return true
+}
+
+// NeighbourUpdateTick ...
+func (c PistonArmCollision) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if p, ok := w.Block(pos.Side(c.Facing.Opposite())).(Piston); !ok || p.Facing != c.Facing || !p.Extended {
+		w.SetBlock(pos, nil, nil)
+	}
+}
+
+// EncodeBlock ...
+func (c PistonArmCollision) EncodeBlock() (string, map[string]any) {
+	if c.Sticky {
+		return "minecraft:sticky_piston_arm_collision", map[string]any{"facing_direction": pistonFacingDirection(c.Facing)}
+	}
+	return "minecraft:piston_arm_collision", map[string]any{"facing_direction": pistonFacingDirection(c.Facing)}
+}
+
+// allPistonArmCollisions ...
+func allPistonArmCollisions() (arms []world.Block) {
+	for _, f := range cube.Faces() {
+		arms = append(arms, PistonArmCollision{Facing: f})
+		arms = append(arms, PistonArmCollision{Facing: f, Sticky: true})
+	}
+	return
+}
@@ -0,0 +1,49 @@
+package block
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/google/uuid"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// Portal represents a block that transports entities that enter it to another
+// location, often in a different world. Entities that are able to travel
+// through portals are notified when they enter a Portal and decide themselves
+// when to travel to its destination.
+type Portal interface {
+	world.Block
+	// PortalDestination returns the world that the portal at the position
+	// passed in the world w leads to.
+	PortalDestination(pos cube.Pos, w *world.World) *world.World
+	// PortalTarget returns the position in the world dst at which an entity
+	// that travelled through the portal at the position passed in the world w
+	// arrives. dst is not necessarily the world returned by
+	// PortalDestination. PortalTarget may change blocks in dst, for example to
+	// create a portal that entities arrive in.
+	PortalTarget(pos cube.Pos, w, dst *world.World, e world.Entity) mgl64.Vec3
+}
+
+// portalTraveller represents an entity that can travel through a Portal.
+type portalTraveller interface {
+	// EnterPortal is called every time the entity is inside a Portal at the
+	// position passed.
+	EnterPortal(p Portal, pos cube.Pos)
+}
+
+// enterPortal notifies the entity passed that it is inside the Portal at the
+// position passed, if the entity is able to travel through portals.
+func enterPortal(p Portal, pos cube.Pos, e world.Entity) {
+	if t, ok := e.(portalTraveller); ok {
+		t.EnterPortal(p, pos)
+	}
+}
+
+// spawnOf returns the position at which the entity passed spawns in the world
+// passed. For players this is their personal spawn point.
+func spawnOf(w *world.World, e world.Entity) mgl64.Vec3 {
+	if p, ok := e.(interface{ UUID() uuid.UUID }); ok {
+		return w.PlayerSpawn(p.UUID()).Vec3Middle()
+	}
+	return w.Spawn().Vec3Middle()
+}
@@ -0,0 +1,91 @@
+package block
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// PoweredRail is a rail that accelerates minecarts travelling along it while it is powered, and slows them down
+// while it is not. Powered rails carry power to up to 8 connected powered rails.
+type PoweredRail struct {
+	transparent
+	empty
+
+	// Shape is the shape of the rail. Powered rails cannot be curved.
+	Shape RailShape
+	// Powered is true if the rail is powered by redstone.
+	Powered bool
+}
+
+// RailShape ...
+func (r PoweredRail) RailShape() RailShape {
+	return r.Shape
+}
+
+// MinecartPosition ...
+func (r PoweredRail) MinecartPosition(pos cube.Pos) mgl64.Vec3 {
+	return railMinecartPosition(pos, r.Shape)
+}
+
+// withRailShape ...
+func (r PoweredRail) withRailShape(s RailShape) railBlock {
+	r.Shape = s
+	return r
+}
+
+// UseOnBlock ...
+func (r PoweredRail) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) (used bool) {
+	pos, _, used = firstReplaceable(w, pos, face, r)
+	if !used || !railSupported(pos, w) {
+		return false
+	}
+	r.Shape = railShapeFor(pos, r, w)
+	r.Powered = railPowered(pos, r, w)
+	return placeRail(pos, r, w, user, ctx)
+}
+
+// NeighbourUpdateTick ...
+func (r PoweredRail) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if breakUnsupportedRail(pos, r, w) {
+		return
+	}
+	if powered := railPowered(pos, r, w); powered != r.Powered {
+		r.Powered = powered
+		w.SetBlock(pos, r, nil)
+	}
+}
+
+// HasLiquidDrops ...
+func (PoweredRail) HasLiquidDrops() bool {
+	return true
+}
+
+// SideClosed ...
+func (PoweredRail) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
+	return false
+}
+
+// BreakInfo ...
+func (r PoweredRail) BreakInfo() BreakInfo {
+	return newBreakInfo(0.7, alwaysHarvestable, pickaxeEffective, oneOf(PoweredRail{}))
+}
+
+// EncodeItem ...
+func (PoweredRail) EncodeItem() (name string, meta int16) {
+	return "minecraft:golden_rail", 0
+}
+
+// EncodeBlock ...
+func (r PoweredRail) EncodeBlock() (string, map[string]any) {
+	return "minecraft:golden_rail", map[string]any{"rail_direction": int32(r.Shape.Uint8()), "rail_data_bit": boolByte(r.Powered)}
+}
+
+// allPoweredRails ...
+func allPoweredRails() (rails []world.Block) {
+	for _, s := range StraightRailShapes() {
+		rails = append(rails, PoweredRail{Shape: s}, PoweredRail{Shape: s, Powered: true})
+	}
+	return
+}
@@ -0,0 +1,163 @@
+package block
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/sound"
+)
+
+// PressurePlate is a non-solid block that emits redstone power when an entity stands on it. Stone pressure plates
+// are only activated by living entities, while other pressure plates are activated by any entity. Weighted
+// pressure plates emit more power as more entities stand on them.
+type PressurePlate struct {
+	empty
+	transparent
+
+	// Type is the type of the pressure plate.
+	Type PressurePlateType
+	// Power is the power level (0-15) the pressure plate currently emits.
+	Power int
+}
+
+// BreakInfo ...
+func (p PressurePlate) BreakInfo() BreakInfo {
+	effectiveTool := axeEffective
+	if !p.Type.Wooden() {
+		effectiveTool = pickaxeEffective
+	}
+	return newBreakInfo(0.5, alwaysHarvestable, effectiveTool, oneOf(PressurePlate{Type: p.Type})).withBreakHandler(updateRedstoneOnBreak)
+}
+
+// FuelInfo ...
+func (p PressurePlate) FuelInfo() item.FuelInfo {
+	if !p.Type.Wooden() {
+		return item.FuelInfo{}
+	}
+	return newFuelInfo(time.Second * 15)
+}
+
+// HasLiquidDrops ...
+func (PressurePlate) HasLiquidDrops() bool {
+	return true
+}
+
+// UseOnBlock ...
+func (p PressurePlate) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) bool {
+	pos, _, used := firstReplaceable(w, pos, face, p)
+	if !used {
+		return false
+	}
+	if !attachedTo(pos, cube.FaceDown, w) {
+		return false
+	}
+	place(w, pos, p, user, ctx)
+	return placed(ctx)
+}
+
+// EntityInside ...
+func (p PressurePlate) EntityInside(pos cube.Pos, w *world.World, _ world.Entity) {
+	if p.Power == 0 {
+		// The entities on the pressure plate are counted in the scheduled tick, as other entities may step on
+		// the plate in the same tick.
+		w.ScheduleBlockUpdate(pos, 0)
+	}
+}
+
+// NeighbourUpdateTick ...
+func (p PressurePlate) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	breakUnattached(p, pos, cube.FaceDown, w)
+}
+
+// ScheduledTick ...
+func (p PressurePlate) ScheduledTick(pos cube.Pos, w *world.World, _ *rand.Rand) {
+	power := p.calculatePower(pos, w)
+	if power != p.Power {
+		if p.Power == 0 {
+			w.PlaySound(pos.Vec3Centre(), sound.PowerOn{Block: p})
+		} else if power == 0 {
+			w.PlaySound(pos.Vec3Centre(), sound.PowerOff{Block: p})
+		}
+		p.Power = power
+		setRedstone(pos, p, w)
+	}
+	if power > 0 {
+		// Keep checking if entities are still on the pressure plate as long as it is powered.
+		delay := time.Second
+		if p.Type.Weighted() {
+			delay = time.Second / 2
+		}
+		w.ScheduleBlockUpdate(pos, delay)
+	}
+}
+
+// WeakPower ...
+func (p PressurePlate) WeakPower(cube.Pos, cube.Face, *world.World, bool) int {
+	return p.Power
+}
+
+// StrongPower ...
+func (p PressurePlate) StrongPower(_ cube.Pos, face cube.Face, _ *world.World, _ bool) int {
+	if face == cube.FaceDown {
+		return p.Power
+	}
+	return 0
+}
+
+// EncodeItem ...
+func (p PressurePlate) EncodeItem() (name string, meta int16) {
+	name, _ = p.EncodeBlock()
+	return name, 0
+}
+
+// EncodeBlock ...
+func (p PressurePlate) EncodeBlock() (string, map[string]any) {
+	name := "minecraft:" + p.Type.String() + "_pressure_plate"
+	if p.Type == WoodenPressurePlate(OakWood()) {
+		name = "minecraft:wooden_pressure_plate"
+	}
+	return name, map[string]any{"redstone_signal": int32(p.Power)}
+}
+
+// calculatePower returns the power level that the pressure plate at the position passed should emit, based on
+// the entities standing on it.
+func (p PressurePlate) calculatePower(pos cube.Pos, w *world.World) int {
+	box := cube.Box(0, 0, 0, 1, 0.25, 1).Translate(pos.Vec3())
+	entities := w.EntitiesWithin(box, func(e world.Entity) bool {
+		if p.Type == StonePressurePlate() || p.Type == PolishedBlackstonePressurePlate() {
+			// Stone pressure plates are only activated by living entities.
+			_, living := e.(interface{ Health() float64 })
+			return !living
+		}
+		return false
+	})
+	switch p.Type {
+	case LightWeightedPressurePlate():
+		return min(15, len(entities))
+	case HeavyWeightedPressurePlate():
+		return min(15, int(math.Ceil(float64(len(entities))/10)))
+	}
+	if len(entities) > 0 {
+		return 15
+	}
+	return 0
+}
+
+// allPressurePlates ...
+func allPressurePlates() (plates []world.Block) {
+	for _, t := range PressurePlateTypes() {
+		if !t.Weighted() {
+			plates = append(plates, PressurePlate{Type: t}, PressurePlate{Type: t, Power: 15})
+			continue
+		}
+		for power := 0; power <= 15; power++ {
+			plates = append(plates, PressurePlate{Type: t, Power: power})
+		}
+	}
+	return
+}
@@ -0,0 +1,99 @@
+package block
+
+// PressurePlateType represents a type of pressure plate. Pressure plates may be made of stone, polished
+// blackstone, gold, iron or any type of wood.
+type PressurePlateType struct {
+	pressurePlate
+	// wood is the type of wood of the pressure plate. This field is only used if the pressure plate is a
+	// wooden pressure plate.
+	wood WoodType
+}
+
+type pressurePlate uint8
+
+// StonePressurePlate returns the stone pressure plate type.
+func StonePressurePlate() PressurePlateType {
+	return PressurePlateType{pressurePlate: 0}
+}
+
+// PolishedBlackstonePressurePlate returns the polished blackstone pressure plate type.
+func PolishedBlackstonePressurePlate() PressurePlateType {
+	return PressurePlateType{pressurePlate: 1}
+}
+
+// LightWeightedPressurePlate returns the light weighted (gold) pressure plate type.
+func LightWeightedPressurePlate() PressurePlateType {
+	return PressurePlateType{pressurePlate: 2}
+}
+
+// HeavyWeightedPressurePlate returns the heavy weighted (iron) pressure plate type.
+func HeavyWeightedPressurePlate() PressurePlateType {
+	return PressurePlateType{pressurePlate: 3}
+}
+
+// WoodenPressurePlate returns the wooden pressure plate type with the wood type passed.
+func WoodenPressurePlate(w WoodType) PressurePlateType {
+	return PressurePlateType{pressurePlate: 4, wood: w}
+}
+
+// Uint8 ...
+func (p PressurePlateType) Uint8() uint8 {
+	if p.pressurePlate == 4 {
+		return 4 + p.wood.Uint8()
+	}
+	return uint8(p.pressurePlate)
+}
+
+// Wooden checks if the pressure plate type is a wooden pressure plate.
+func (p PressurePlateType) Wooden() bool {
+	return p.pressurePlate == 4
+}
+
+// Weighted checks if the pressure plate type is a weighted pressure plate, which emits power based on the
+// amount of entities on it.
+func (p PressurePlateType) Weighted() bool {
+	return p.pressurePlate == 2 || p.pressurePlate == 3
+}
+
+// Name ...
+func (p PressurePlateType) Name() string {
+	switch p.pressurePlate {
+	case 0:
+		return "Stone Pressure Plate"
+	case 1:
+		return "Polished Blackstone Pressure Plate"
+	case 2:
+		return "Light Weighted Pressure Plate"
+	case 3:
+		return "Heavy Weighted Pressure Plate"
+	case 4:
+		return p.wood.Name() + " Pressure Plate"
+	}
+	panic("unknown pressure plate type")
+}
+
+// String ...
+func (p PressurePlateType) String() string {
+	switch p.pressurePlate {
+	case 0:
+		return "stone"
+	case 1:
+		return "polished_blackstone"
+	case 2:
+		return "light_weighted"
+	case 3:
+		return "heavy_weighted"
+	case 4:
+		return p.wood.String()
+	}
+	panic("unknown pressure plate type")
+}
+
+// PressurePlateTypes ...
+func PressurePlateTypes() []PressurePlateType {
+	types := []PressurePlateType{StonePressurePlate(), PolishedBlackstonePressurePlate(), LightWeightedPressurePlate(), HeavyWeightedPressurePlate()}
+	for _, w := range WoodTypes() {
+		types = append(types, WoodenPressurePlate(w))
+	}
+	return types
+}
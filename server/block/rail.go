@@ -0,0 +1,314 @@
+package block
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// Rail is a block that minecarts travel along. Regular rails connect to the rails around them and may curve to
+// connect two rails that are not opposite of each other.
+type Rail struct {
+	transparent
+	empty
+
+	// Shape is the shape of the rail. It determines the directions that the rail connects to.
+	Shape RailShape
+}
+
+// MinecartRail represents a rail block that minecarts travel along.
+type MinecartRail interface {
+	world.Block
+	// RailShape returns the shape of the rail.
+	RailShape() RailShape
+	// MinecartPosition returns the position of a minecart that is placed in the middle of the rail at the
+	// position passed.
+	MinecartPosition(pos cube.Pos) mgl64.Vec3
+}
+
+// RailTraveller represents an entity that travels along rails, such as a minecart. DetectorRails are powered while
+// a RailTraveller is on top of them.
+type RailTraveller interface {
+	world.Entity
+	// Rail returns the position of the rail that the RailTraveller is currently on. If it is not on a rail, false
+	// is returned.
+	Rail() (cube.Pos, bool)
+}
+
+// RailShape ...
+func (r Rail) RailShape() RailShape {
+	return r.Shape
+}
+
+// MinecartPosition ...
+func (r Rail) MinecartPosition(pos cube.Pos) mgl64.Vec3 {
+	return railMinecartPosition(pos, r.Shape)
+}
+
+// withRailShape ...
+func (r Rail) withRailShape(s RailShape) railBlock {
+	r.Shape = s
+	return r
+}
+
+// UseOnBlock ...
+func (r Rail) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) (used bool) {
+	pos, _, used = firstReplaceable(w, pos, face, r)
+	if !used || !railSupported(pos, w) {
+		return false
+	}
+	r.Shape = railShapeFor(pos, r, w)
+	return placeRail(pos, r, w, user, ctx)
+}
+
+// NeighbourUpdateTick ...
+func (r Rail) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	breakUnsupportedRail(pos, r, w)
+}
+
+// HasLiquidDrops ...
+func (Rail) HasLiquidDrops() bool {
+	return true
+}
+
+// SideClosed ...
+func (Rail) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
+	return false
+}
+
+// BreakInfo ...
+func (r Rail) BreakInfo() BreakInfo {
+	return newBreakInfo(0.7, alwaysHarvestable, pickaxeEffective, oneOf(Rail{}))
+}
+
+// EncodeItem ...
+func (Rail) EncodeItem() (name string, meta int16) {
+	return "minecraft:rail", 0
+}
+
+// EncodeBlock ...
+func (r Rail) EncodeBlock() (string, map[string]any) {
+	return "minecraft:rail", map[string]any{"rail_direction": int32(r.Shape.Uint8())}
+}
+
+// allRails ...
+func allRails() (rails []world.Block) {
+	for _, s := range RailShapes() {
+		rails = append(rails, Rail{Shape: s})
+	}
+	return
+}
+
+// railBlock is a MinecartRail of which the shape can be changed, so that it can connect to the rails around it.
+type railBlock interface {
+	MinecartRail
+	withRailShape(s RailShape) railBlock
+}
+
+// railMinecartPosition returns the position of a minecart in the middle of a rail with the shape passed.
+func railMinecartPosition(pos cube.Pos, s RailShape) mgl64.Vec3 {
+	y := 0.0625
+	if _, ok := s.Ascending(); ok {
+		y += 0.5
+	}
+	return pos.Vec3Middle().Add(mgl64.Vec3{0, y})
+}
+
+// railSupported checks if a rail at the position passed is supported by the block below it.
+func railSupported(pos cube.Pos, w *world.World) bool {
+	below := pos.Side(cube.FaceDown)
+	return w.Block(below).Model().FaceSolid(below, cube.FaceUp, w)
+}
+
+// breakUnsupportedRail breaks the rail at the position passed if it is no longer supported by the block below it.
+// True is returned if the rail was broken.
+func breakUnsupportedRail(pos cube.Pos, r railBlock, w *world.World) bool {
+	if railSupported(pos, w) {
+		return false
+	}
+	w.SetBlock(pos, nil, nil)
+	if it, ok := r.(world.Item); ok {
+		dropItem(w, item.NewStack(it, 1), pos.Vec3Centre())
+	}
+	return true
+}
+
+// placeRail places the rail passed at the position passed and changes the shapes of the rails around it so that
+// they connect to it.
+func placeRail(pos cube.Pos, r railBlock, w *world.World, user item.User, ctx *item.UseContext) bool {
+	place(w, pos, r, user, ctx)
+	if !placed(ctx) {
+		return false
+	}
+	for _, d := range r.RailShape().Directions() {
+		np, nr, ok := railNeighbour(pos, d, w)
+		if !ok || railConnects(np, nr, pos) {
+			continue
+		}
+		connections := railConnections(np, nr, w)
+		if len(connections) >= 2 {
+			continue
+		}
+		w.SetBlock(np, nr.withRailShape(railShapeFrom(np, append(connections, d.Opposite()), railCurvable(nr), w)), nil)
+	}
+	return true
+}
+
+// railShapeFor returns the shape that the rail passed should have when placed at the position passed, so that it
+// connects to the rails around it that have not yet been connected to two other rails.
+func railShapeFor(pos cube.Pos, r railBlock, w *world.World) RailShape {
+	var directions []cube.Direction
+	for _, d := range cube.Directions() {
+		np, nr, ok := railNeighbour(pos, d, w)
+		if !ok {
+			continue
+		}
+		if railConnects(np, nr, pos) || len(railConnections(np, nr, w)) < 2 {
+			directions = append(directions, d)
+		}
+	}
+	return railShapeFrom(pos, directions, railCurvable(r), w)
+}
+
+// railShapeFrom returns the shape of a rail at the position passed that connects to rails in the directions
+// passed. If curvable is false, only straight shapes are returned. The rail ascends towards a direction if a rail
+// is found one block higher in that direction.
+func railShapeFrom(pos cube.Pos, directions []cube.Direction, curvable bool, w *world.World) RailShape {
+	var n, s, e, west bool
+	for _, d := range directions {
+		switch d {
+		case cube.North:
+			n = true
+		case cube.South:
+			s = true
+		case cube.East:
+			e = true
+		case cube.West:
+			west = true
+		}
+	}
+	shape := NorthSouthRail()
+	switch {
+	case (e || west) && !n && !s:
+		shape = EastWestRail()
+	case curvable && s && e:
+		shape = SouthEastRail()
+	case curvable && s && west:
+		shape = SouthWestRail()
+	case curvable && n && west:
+		shape = NorthWestRail()
+	case curvable && n && e:
+		shape = NorthEastRail()
+	}
+	if shape.Curved() {
+		return shape
+	}
+	dirs := shape.Directions()
+	if railAbove(pos, dirs[0], w) {
+		return ascendingRail(dirs[0])
+	} else if railAbove(pos, dirs[1], w) {
+		return ascendingRail(dirs[1])
+	}
+	return shape
+}
+
+// ascendingRail returns the rail shape that ascends towards the direction passed.
+func ascendingRail(d cube.Direction) RailShape {
+	switch d {
+	case cube.North:
+		return AscendingNorthRail()
+	case cube.South:
+		return AscendingSouthRail()
+	case cube.West:
+		return AscendingWestRail()
+	}
+	return AscendingEastRail()
+}
+
+// railAbove checks if a rail is found one block higher in the direction passed.
+func railAbove(pos cube.Pos, d cube.Direction, w *world.World) bool {
+	_, ok := w.Block(pos.Side(d.Face()).Side(cube.FaceUp)).(MinecartRail)
+	return ok
+}
+
+// railCurvable checks if the rail passed is able to curve. Only regular rails can curve.
+func railCurvable(r railBlock) bool {
+	_, ok := r.(Rail)
+	return ok
+}
+
+// railNeighbour returns the rail next to the position passed in the direction passed. The rail may be at the same
+// height, one block higher or one block lower than the position.
+func railNeighbour(pos cube.Pos, d cube.Direction, w *world.World) (cube.Pos, railBlock, bool) {
+	side := pos.Side(d.Face())
+	for _, p := range [...]cube.Pos{side, side.Side(cube.FaceUp), side.Side(cube.FaceDown)} {
+		if r, ok := w.Block(p).(railBlock); ok {
+			return p, r, true
+		}
+	}
+	return cube.Pos{}, nil, false
+}
+
+// railConnects checks if the rail passed at the position passed connects to the position target, which may be at
+// the same height or one block lower than the end of the rail.
+func railConnects(pos cube.Pos, r railBlock, target cube.Pos) bool {
+	up, ascending := r.RailShape().Ascending()
+	for _, d := range r.RailShape().Directions() {
+		end := pos.Side(d.Face())
+		if ascending && d == up {
+			end = end.Side(cube.FaceUp)
+		}
+		if end[0] == target[0] && end[2] == target[2] && (end[1] == target[1] || end[1]-1 == target[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// railConnections returns the directions in which the rail passed at the position passed is connected to another
+// rail that connects back to it.
+func railConnections(pos cube.Pos, r railBlock, w *world.World) []cube.Direction {
+	connections := make([]cube.Direction, 0, 2)
+	for _, d := range r.RailShape().Directions() {
+		if np, nr, ok := railNeighbour(pos, d, w); ok && railConnects(pos, r, np) && railConnects(np, nr, pos) {
+			connections = append(connections, d)
+		}
+	}
+	return connections
+}
+
+// railPowered checks if the rail passed at the position passed is powered, either directly by redstone or
+// through up to 8 rails of the same type that it is connected to in a straight line.
+func railPowered(pos cube.Pos, r railBlock, w *world.World) bool {
+	if w.ReceivedRedstonePower(pos, true) > 0 {
+		return true
+	}
+	for _, d := range r.RailShape().Directions() {
+		current, cr := pos, r
+		for i := 0; i < 8; i++ {
+			np, nr, ok := railNeighbour(current, d, w)
+			if !ok || !sameRailType(r, nr) || !railConnects(current, cr, np) || !railConnects(np, nr, current) {
+				break
+			}
+			if w.ReceivedRedstonePower(np, true) > 0 {
+				return true
+			}
+			current, cr = np, nr
+		}
+	}
+	return false
+}
+
+// sameRailType checks if the two rails passed are of the same type.
+func sameRailType(a, b railBlock) bool {
+	switch a.(type) {
+	case PoweredRail:
+		_, ok := b.(PoweredRail)
+		return ok
+	case ActivatorRail:
+		_, ok := b.(ActivatorRail)
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package block
+
+import (
+	"github.com/stcraft/dragonfly/server/block/cube"
+)
+
+// RailShape represents the shape of a rail block. The shape determines the two directions that the rail connects
+// to and whether the rail ascends towards one of them.
+type RailShape struct {
+	railShape
+}
+
+// NorthSouthRail returns a straight rail shape running from north to south.
+func NorthSouthRail() RailShape {
+	return RailShape{0}
+}
+
+// EastWestRail returns a straight rail shape running from east to west.
+func EastWestRail() RailShape {
+	return RailShape{1}
+}
+
+// AscendingEastRail returns a straight rail shape running from west to east that ascends towards the east.
+func AscendingEastRail() RailShape {
+	return RailShape{2}
+}
+
+// AscendingWestRail returns a straight rail shape running from east to west that ascends towards the west.
+func AscendingWestRail() RailShape {
+	return RailShape{3}
+}
+
+// AscendingNorthRail returns a straight rail shape running from south to north that ascends towards the north.
+func AscendingNorthRail() RailShape {
+	return RailShape{4}
+}
+
+// AscendingSouthRail returns a straight rail shape running from north to south that ascends towards the south.
+func AscendingSouthRail() RailShape {
+	return RailShape{5}
+}
+
+// SouthEastRail returns a curved rail shape connecting the south and the east.
+func SouthEastRail() RailShape {
+	return RailShape{6}
+}
+
+// SouthWestRail returns a curved rail shape connecting the south and the west.
+func SouthWestRail() RailShape {
+	return RailShape{7}
+}
+
+// NorthWestRail returns a curved rail shape connecting the north and the west.
+func NorthWestRail() RailShape {
+	return RailShape{8}
+}
+
+// NorthEastRail returns a curved rail shape connecting the north and the east.
+func NorthEastRail() RailShape {
+	return RailShape{9}
+}
+
+// RailShapes returns all possible rail shapes.
+func RailShapes() []RailShape {
+	return []RailShape{
+		NorthSouthRail(), EastWestRail(), AscendingEastRail(), AscendingWestRail(), AscendingNorthRail(),
+		AscendingSouthRail(), SouthEastRail(), SouthWestRail(), NorthWestRail(), NorthEastRail(),
+	}
+}
+
+// StraightRailShapes returns all rail shapes that are not curved. Powered, detector and activator rails can only
+// have one of these shapes.
+func StraightRailShapes() []RailShape {
+	return RailShapes()[:6]
+}
+
+type railShape uint8
+
+// Uint8 returns the rail shape as a uint8.
+func (s railShape) Uint8() uint8 {
+	return uint8(s)
+}
+
+// Curved checks if the rail shape is curved, connecting two directions that are not opposite.
+func (s railShape) Curved() bool {
+	return s >= 6
+}
+
+// Ascending returns the direction that the rail shape ascends towards. If the shape is flat, false is returned.
+func (s railShape) Ascending() (cube.Direction, bool) {
+	switch s {
+	case 2:
+		return cube.East, true
+	case 3:
+		return cube.West, true
+	case 4:
+		return cube.North, true
+	case 5:
+		return cube.South, true
+	}
+	return 0, false
+}
+
+// Directions returns the two directions that the rail shape connects to.
+func (s railShape) Directions() [2]cube.Direction {
+	switch s {
+	case 0, 4, 5:
+		return [2]cube.Direction{cube.North, cube.South}
+	case 1, 2, 3:
+		return [2]cube.Direction{cube.West, cube.East}
+	case 6:
+		return [2]cube.Direction{cube.South, cube.East}
+	case 7:
+		return [2]cube.Direction{cube.South, cube.West}
+	case 8:
+		return [2]cube.Direction{cube.North, cube.West}
+	case 9:
+		return [2]cube.Direction{cube.North, cube.East}
+	}
+	panic("unknown rail shape")
+}
+
+// String ...
+func (s railShape) String() string {
+	switch s {
+	case 0:
+		return "north_south"
+	case 1:
+		return "east_west"
+	case 2:
+		return "ascending_east"
+	case 3:
+		return "ascending_west"
+	case 4:
+		return "ascending_north"
+	case 5:
+		return "ascending_south"
+	case 6:
+		return "south_east"
+	case 7:
+		return "south_west"
+	case 8:
+		return "north_west"
+	case 9:
+		return "north_east"
+	}
+	panic("unknown rail shape")
+}
@@ -0,0 +1,87 @@
+package block
+
+import (
+	"time"
+
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// attachedTo checks if the block on the face passed of the position passed has a solid face towards the
+// position, so that a redstone component at the position can be attached to it.
+func attachedTo(pos cube.Pos, face cube.Face, w *world.World) bool {
+	side := pos.Side(face)
+	return w.Block(side).Model().FaceSolid(side, face.Opposite(), w)
+}
+
+// breakUnattached breaks the block at the position passed and drops it as an item if it is no longer attached to
+// the block on the face passed. True is returned if the block was broken.
+func breakUnattached(b world.Block, pos cube.Pos, face cube.Face, w *world.World) bool {
+	if attachedTo(pos, face, w) {
+		return false
+	}
+	w.SetBlock(pos, nil, nil)
+	if it, ok := b.(world.Item); ok {
+		dropItem(w, item.NewStack(it, 1), pos.Vec3Centre())
+	}
+	w.UpdateRedstone(pos)
+	return true
+}
+
+// setRedstone sets a redstone component to the world at the position passed and notifies the blocks around it
+// of the change in power.
+func setRedstone(pos cube.Pos, b world.Block, w *world.World) {
+	w.SetBlock(pos, b, &world.SetOpts{DisableBlockUpdates: true})
+	w.UpdateRedstone(pos)
+}
+
+// updateRedstoneOnBreak returns a BreakInfo BreakHandler that notifies the blocks around a redstone component of
+// the change in power when it is broken.
+func updateRedstoneOnBreak(pos cube.Pos, w *world.World, _ item.User) {
+	w.UpdateRedstone(pos)
+}
+
+// diodePowerSource is a redstone component that only accepts power from redstone dust, repeaters, comparators and
+// redstone blocks on its sides, such as a repeater or comparator.
+func diodePowerSource(b world.Block) bool {
+	switch b.(type) {
+	case RedstoneWire, RedstoneRepeater, RedstoneComparator, RedstoneBlock:
+		return true
+	}
+	return false
+}
+
+// diodeSidePower returns the highest power level received by a diode facing in the direction passed from the
+// blocks on its sides. Only power from blocks for which diodePowerSource returns true is considered.
+func diodeSidePower(pos cube.Pos, facing cube.Direction, w *world.World) int {
+	power := 0
+	for _, d := range []cube.Direction{facing.RotateLeft(), facing.RotateRight()} {
+		side := pos.Side(d.Face())
+		b := w.Block(side)
+		if !diodePowerSource(b) {
+			continue
+		}
+		power = max(power, b.(world.RedstonePowerSource).WeakPower(side, d.Opposite().Face(), w, true))
+	}
+	return power
+}
+
+const (
+	// torchBurnoutToggles is the amount of times a redstone torch may be turned off within torchBurnoutPeriod
+	// before it burns out.
+	torchBurnoutToggles = 8
+	// torchBurnoutPeriod is the period in which the amount of times a redstone torch was turned off is counted.
+	torchBurnoutPeriod = time.Second * 3
+)
+
+// recordTorchToggle records a redstone torch at a position being turned off and returns true if the torch has
+// burned out as a result.
+func recordTorchToggle(pos cube.Pos, w *world.World) bool {
+	return w.RecordRedstoneToggle(pos, torchBurnoutPeriod) >= torchBurnoutToggles
+}
+
+// torchBurntOut checks if the redstone torch at a position has recently burned out.
+func torchBurntOut(pos cube.Pos, w *world.World) bool {
+	return w.RedstoneToggles(pos) >= torchBurnoutToggles
+}
@@ -0,0 +1,52 @@
+package block
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// RedstoneBlock is a mineral block that acts as a permanent source of redstone power.
+type RedstoneBlock struct {
+	solid
+}
+
+// BreakInfo ...
+func (r RedstoneBlock) BreakInfo() BreakInfo {
+	return newBreakInfo(5, pickaxeHarvestable, pickaxeEffective, oneOf(r)).withBlastResistance(30).withBreakHandler(updateRedstoneOnBreak)
+}
+
+// UseOnBlock ...
+func (r RedstoneBlock) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) bool {
+	pos, _, used := firstReplaceable(w, pos, face, r)
+	if !used {
+		return false
+	}
+	place(w, pos, r, user, ctx)
+	if placed(ctx) {
+		w.UpdateRedstone(pos)
+		return true
+	}
+	return false
+}
+
+// WeakPower ...
+func (RedstoneBlock) WeakPower(cube.Pos, cube.Face, *world.World, bool) int {
+	return 15
+}
+
+// StrongPower ...
+func (RedstoneBlock) StrongPower(cube.Pos, cube.Face, *world.World, bool) int {
+	return 0
+}
+
+// EncodeItem ...
+func (RedstoneBlock) EncodeItem() (name string, meta int16) {
+	return "minecraft:redstone_block", 0
+}
+
+// EncodeBlock ...
+func (RedstoneBlock) EncodeBlock() (string, map[string]any) {
+	return "minecraft:redstone_block", nil
+}
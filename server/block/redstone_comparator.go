@@ -0,0 +1,218 @@
+package block
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/block/model"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/sound"
+)
+
+// RedstoneComparator is a redstone component used to maintain, compare or subtract signal strength, or to
+// measure the fullness of a container behind it.
+type RedstoneComparator struct {
+	transparent
+
+	// Facing is the direction the comparator is facing. The comparator receives its main input from the block
+	// in this direction and emits power towards the opposite direction.
+	Facing cube.Direction
+	// Subtract is true if the comparator is in subtraction mode. In subtraction mode, the power of the side
+	// inputs is subtracted from the power of the main input. Otherwise, the comparator only emits the power of
+	// its main input if it is not lower than the power of either side input.
+	Subtract bool
+	// Powered is whether the comparator is powered and emitting power.
+	Powered bool
+	// Output is the power level (0-15) that the comparator currently emits.
+	Output int
+}
+
+// Model ...
+func (RedstoneComparator) Model() world.BlockModel {
+	return model.Diode{}
+}
+
+// BreakInfo ...
+func (c RedstoneComparator) BreakInfo() BreakInfo {
+	return newBreakInfo(0, alwaysHarvestable, nothingEffective, oneOf(RedstoneComparator{})).withBreakHandler(updateRedstoneOnBreak)
+}
+
+// HasLiquidDrops ...
+func (RedstoneComparator) HasLiquidDrops() bool {
+	return true
+}
+
+// UseOnBlock ...
+func (c RedstoneComparator) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) bool {
+	pos, _, used := firstReplaceable(w, pos, face, c)
+	if !used {
+		return false
+	}
+	if !attachedTo(pos, cube.FaceDown, w) {
+		return false
+	}
+	c.Facing = user.Rotation().Direction().Opposite()
+
+	place(w, pos, c, user, ctx)
+	if placed(ctx) {
+		w.ScheduleBlockUpdate(pos, time.Second/10)
+		return true
+	}
+	return false
+}
+
+// Activate ...
+func (c RedstoneComparator) Activate(pos cube.Pos, _ cube.Face, w *world.World, _ item.User, _ *item.UseContext) bool {
+	c.Subtract = !c.Subtract
+	if c.Subtract {
+		w.PlaySound(pos.Vec3Centre(), sound.PowerOn{Block: c})
+	} else {
+		w.PlaySound(pos.Vec3Centre(), sound.PowerOff{Block: c})
+	}
+	w.SetBlock(pos, c, &world.SetOpts{DisableBlockUpdates: true})
+	w.ScheduleBlockUpdate(pos, time.Second/10)
+	return true
+}
+
+// NeighbourUpdateTick ...
+func (c RedstoneComparator) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if breakUnattached(c, pos, cube.FaceDown, w) {
+		return
+	}
+	if c.Output != c.calculateOutput(pos, w) {
+		w.ScheduleBlockUpdate(pos, time.Second/10)
+	}
+}
+
+// ScheduledTick ...
+func (c RedstoneComparator) ScheduledTick(pos cube.Pos, w *world.World, _ *rand.Rand) {
+	c.update(pos, w)
+}
+
+// Tick ...
+func (c RedstoneComparator) Tick(currentTick int64, pos cube.Pos, w *world.World) {
+	if currentTick%2 == 0 {
+		// The fullness of containers changes without updating the blocks around them, so the comparator must
+		// check its input periodically.
+		c.update(pos, w)
+	}
+}
+
+// WeakPower ...
+func (c RedstoneComparator) WeakPower(_ cube.Pos, face cube.Face, _ *world.World, _ bool) int {
+	if face == c.Facing.Opposite().Face() {
+		return c.Output
+	}
+	return 0
+}
+
+// StrongPower ...
+func (c RedstoneComparator) StrongPower(pos cube.Pos, face cube.Face, w *world.World, accountForDust bool) int {
+	return c.WeakPower(pos, face, w, accountForDust)
+}
+
+// DecodeNBT ...
+func (c RedstoneComparator) DecodeNBT(data map[string]any) any {
+	c.Output = int(nbtconv.Int32(data, "OutputSignal"))
+	return c
+}
+
+// EncodeNBT ...
+func (c RedstoneComparator) EncodeNBT() map[string]any {
+	return map[string]any{"id": "Comparator", "OutputSignal": int32(c.Output)}
+}
+
+// PistonBreakable ...
+func (RedstoneComparator) PistonBreakable() bool {
+	return true
+}
+
+// EncodeItem ...
+func (RedstoneComparator) EncodeItem() (name string, meta int16) {
+	return "minecraft:comparator", 0
+}
+
+// EncodeBlock ...
+func (c RedstoneComparator) EncodeBlock() (string, map[string]any) {
+	name := "minecraft:unpowered_comparator"
+	if c.Powered {
+		name = "minecraft:powered_comparator"
+	}
+	return name, map[string]any{
+		"minecraft:cardinal_direction": c.Facing.String(),
+		"output_lit_bit":               boolByte(c.Powered),
+		"output_subtract_bit":          boolByte(c.Subtract),
+	}
+}
+
+// update recalculates the output of the comparator at the position passed and updates it in the world if it
+// changed.
+func (c RedstoneComparator) update(pos cube.Pos, w *world.World) {
+	output := c.calculateOutput(pos, w)
+	if output == c.Output && c.Powered == (output > 0) {
+		return
+	}
+	c.Output, c.Powered = output, output > 0
+	setRedstone(pos, c, w)
+}
+
+// calculateOutput calculates the power level that the comparator at the position passed should emit, based on
+// its main input and side inputs.
+func (c RedstoneComparator) calculateOutput(pos cube.Pos, w *world.World) int {
+	rear, side := c.rearPower(pos, w), diodeSidePower(pos, c.Facing, w)
+	if c.Subtract {
+		return max(rear-side, 0)
+	}
+	if rear >= side {
+		return rear
+	}
+	return 0
+}
+
+// rearPower returns the power level of the main input of the comparator at the position passed. If the block
+// behind the comparator is a container, the power level is based on how full the container is. This also
+// applies to containers behind a block that conducts redstone directly behind the comparator.
+func (c RedstoneComparator) rearPower(pos cube.Pos, w *world.World) int {
+	rear := pos.Side(c.Facing.Face())
+	if container, ok := w.Block(rear).(Container); ok {
+		return containerSignal(container)
+	}
+	power := w.RedstonePower(rear, c.Facing.Opposite().Face(), true)
+	if power < 15 && w.ConductsRedstone(rear) {
+		if container, ok := w.Block(rear.Side(c.Facing.Face())).(Container); ok {
+			return max(power, containerSignal(container))
+		}
+	}
+	return power
+}
+
+// containerSignal returns the power level (0-15) that a comparator reading the Container passed emits, based on
+// how full the container is.
+func containerSignal(c Container) int {
+	inv := c.Inventory()
+	if inv.Empty() {
+		return 0
+	}
+	var fullness float64
+	for _, it := range inv.Slots() {
+		if !it.Empty() {
+			fullness += float64(it.Count()) / float64(it.MaxCount())
+		}
+	}
+	return int(math.Floor(1 + fullness/float64(inv.Size())*14))
+}
+
+// allRedstoneComparators ...
+func allRedstoneComparators() (comparators []world.Block) {
+	for _, d := range cube.Directions() {
+		for _, subtract := range []bool{false, true} {
+			comparators = append(comparators, RedstoneComparator{Facing: d, Subtract: subtract}, RedstoneComparator{Facing: d, Subtract: subtract, Powered: true})
+		}
+	}
+	return
+}
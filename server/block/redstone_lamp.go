@@ -0,0 +1,63 @@
+package block
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// RedstoneLamp is a block that produces light when it is powered by redstone.
+type RedstoneLamp struct {
+	solid
+
+	// Lit is whether the redstone lamp is lit.
+	Lit bool
+}
+
+// BreakInfo ...
+func (l RedstoneLamp) BreakInfo() BreakInfo {
+	return newBreakInfo(0.3, alwaysHarvestable, nothingEffective, oneOf(RedstoneLamp{}))
+}
+
+// LightEmissionLevel ...
+func (l RedstoneLamp) LightEmissionLevel() uint8 {
+	if l.Lit {
+		return 15
+	}
+	return 0
+}
+
+// NeighbourUpdateTick ...
+func (l RedstoneLamp) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	powered := w.ReceivedRedstonePower(pos, true) > 0
+	if powered && !l.Lit {
+		l.Lit = true
+		w.SetBlock(pos, l, &world.SetOpts{DisableBlockUpdates: true})
+	} else if !powered && l.Lit {
+		// Redstone lamps turn off with a delay, so that they do not flicker with short pulses.
+		w.ScheduleBlockUpdate(pos, time.Second/5)
+	}
+}
+
+// ScheduledTick ...
+func (l RedstoneLamp) ScheduledTick(pos cube.Pos, w *world.World, _ *rand.Rand) {
+	if l.Lit && w.ReceivedRedstonePower(pos, true) == 0 {
+		l.Lit = false
+		w.SetBlock(pos, l, &world.SetOpts{DisableBlockUpdates: true})
+	}
+}
+
+// EncodeItem ...
+func (RedstoneLamp) EncodeItem() (name string, meta int16) {
+	return "minecraft:redstone_lamp", 0
+}
+
+// EncodeBlock ...
+func (l RedstoneLamp) EncodeBlock() (string, map[string]any) {
+	if l.Lit {
+		return "minecraft:lit_redstone_lamp", nil
+	}
+	return "minecraft:redstone_lamp", nil
+}
@@ -0,0 +1,165 @@
+package block
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/block/model"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// RedstoneRepeater is a redstone component used to relay a redstone signal at full strength in one direction,
+// after a configurable delay. A repeater that is powered from its side by another repeater or comparator is
+// locked and keeps its current output.
+type RedstoneRepeater struct {
+	transparent
+
+	// Facing is the direction the repeater is facing. The repeater receives power from the block in this
+	// direction and emits power towards the opposite direction.
+	Facing cube.Direction
+	// Delay is the delay of the repeater, ranging from 0 to 3. A delay of 0 corresponds to one redstone tick
+	// (0.1 seconds), while a delay of 3 corresponds to four redstone ticks (0.4 seconds).
+	Delay int
+	// Powered is whether the repeater is powered and emitting power.
+	Powered bool
+}
+
+// Model ...
+func (RedstoneRepeater) Model() world.BlockModel {
+	return model.Diode{}
+}
+
+// BreakInfo ...
+func (r RedstoneRepeater) BreakInfo() BreakInfo {
+	return newBreakInfo(0, alwaysHarvestable, nothingEffective, oneOf(RedstoneRepeater{})).withBreakHandler(updateRedstoneOnBreak)
+}
+
+// HasLiquidDrops ...
+func (RedstoneRepeater) HasLiquidDrops() bool {
+	return true
+}
+
+// UseOnBlock ...
+func (r RedstoneRepeater) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) bool {
+	pos, _, used := firstReplaceable(w, pos, face, r)
+	if !used {
+		return false
+	}
+	if !attachedTo(pos, cube.FaceDown, w) {
+		return false
+	}
+	r.Facing = user.Rotation().Direction().Opposite()
+
+	place(w, pos, r, user, ctx)
+	return placed(ctx)
+}
+
+// Activate ...
+func (r RedstoneRepeater) Activate(pos cube.Pos, _ cube.Face, w *world.World, _ item.User, _ *item.UseContext) bool {
+	r.Delay = (r.Delay + 1) % 4
+	w.SetBlock(pos, r, &world.SetOpts{DisableBlockUpdates: true})
+	return true
+}
+
+// NeighbourUpdateTick ...
+func (r RedstoneRepeater) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if breakUnattached(r, pos, cube.FaceDown, w) || r.locked(pos, w) {
+		return
+	}
+	if r.Powered != r.inputPowered(pos, w) {
+		w.ScheduleBlockUpdate(pos, r.delay())
+	}
+}
+
+// ScheduledTick ...
+func (r RedstoneRepeater) ScheduledTick(pos cube.Pos, w *world.World, _ *rand.Rand) {
+	if r.locked(pos, w) {
+		return
+	}
+	powered := r.inputPowered(pos, w)
+	if r.Powered && !powered {
+		r.Powered = false
+		setRedstone(pos, r, w)
+	} else if !r.Powered {
+		r.Powered = true
+		setRedstone(pos, r, w)
+		if !powered {
+			// The input was only powered briefly: Extend the pulse to last at least as long as the delay.
+			w.ScheduleBlockUpdate(pos, r.delay())
+		}
+	}
+}
+
+// WeakPower ...
+func (r RedstoneRepeater) WeakPower(_ cube.Pos, face cube.Face, _ *world.World, _ bool) int {
+	if r.Powered && face == r.Facing.Opposite().Face() {
+		return 15
+	}
+	return 0
+}
+
+// StrongPower ...
+func (r RedstoneRepeater) StrongPower(pos cube.Pos, face cube.Face, w *world.World, accountForDust bool) int {
+	return r.WeakPower(pos, face, w, accountForDust)
+}
+
+// PistonBreakable ...
+func (RedstoneRepeater) PistonBreakable() bool {
+	return true
+}
+
+// EncodeItem ...
+func (RedstoneRepeater) EncodeItem() (name string, meta int16) {
+	return "minecraft:repeater", 0
+}
+
+// EncodeBlock ...
+func (r RedstoneRepeater) EncodeBlock() (string, map[string]any) {
+	name := "minecraft:unpowered_repeater"
+	if r.Powered {
+		name = "minecraft:powered_repeater"
+	}
+	return name, map[string]any{"minecraft:cardinal_direction": r.Facing.String(), "repeater_delay": int32(r.Delay)}
+}
+
+// delay returns the delay of the repeater as a time.Duration.
+func (r RedstoneRepeater) delay() time.Duration {
+	return time.Duration(r.Delay+1) * time.Second / 10
+}
+
+// inputPowered checks if the repeater at the position passed receives power from the block behind it.
+func (r RedstoneRepeater) inputPowered(pos cube.Pos, w *world.World) bool {
+	return w.RedstonePower(pos.Side(r.Facing.Face()), r.Facing.Opposite().Face(), true) > 0
+}
+
+// locked checks if the repeater at the position passed is locked by a powered repeater or comparator facing into
+// one of its sides.
+func (r RedstoneRepeater) locked(pos cube.Pos, w *world.World) bool {
+	for _, d := range []cube.Direction{r.Facing.RotateLeft(), r.Facing.RotateRight()} {
+		side := pos.Side(d.Face())
+		switch b := w.Block(side).(type) {
+		case RedstoneRepeater:
+			if b.WeakPower(side, d.Opposite().Face(), w, true) > 0 {
+				return true
+			}
+		case RedstoneComparator:
+			if b.WeakPower(side, d.Opposite().Face(), w, true) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allRedstoneRepeaters ...
+func allRedstoneRepeaters() (repeaters []world.Block) {
+	for _, d := range cube.Directions() {
+		for delay := 0; delay < 4; delay++ {
+			repeaters = append(repeaters, RedstoneRepeater{Facing: d, Delay: delay}, RedstoneRepeater{Facing: d, Delay: delay, Powered: true})
+		}
+	}
+	return
+}
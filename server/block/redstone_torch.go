@@ -0,0 +1,158 @@
+package block
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/sound"
+)
+
+// RedstoneTorch is a non-solid block that emits redstone power and a small amount of light. A redstone torch is
+// turned off when the block it is attached to is powered, making it usable as a NOT gate. A torch that is turned
+// off too often in a short period of time burns out for a while.
+type RedstoneTorch struct {
+	transparent
+	empty
+
+	// Facing is the direction from the torch to the block it is attached to.
+	Facing cube.Face
+	// Lit is whether the torch is lit and emitting power.
+	Lit bool
+}
+
+// BreakInfo ...
+func (t RedstoneTorch) BreakInfo() BreakInfo {
+	return newBreakInfo(0, alwaysHarvestable, nothingEffective, oneOf(RedstoneTorch{})).withBreakHandler(updateRedstoneOnBreak)
+}
+
+// LightEmissionLevel ...
+func (t RedstoneTorch) LightEmissionLevel() uint8 {
+	if t.Lit {
+		return 7
+	}
+	return 0
+}
+
+// UseOnBlock ...
+func (t RedstoneTorch) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) bool {
+	pos, face, used := firstReplaceable(w, pos, face, t)
+	if !used {
+		return false
+	}
+	if face == cube.FaceDown {
+		return false
+	}
+	if _, ok := w.Block(pos).(world.Liquid); ok {
+		return false
+	}
+	if !attachedTo(pos, face.Opposite(), w) {
+		found := false
+		for _, i := range []cube.Face{cube.FaceSouth, cube.FaceWest, cube.FaceNorth, cube.FaceEast, cube.FaceDown} {
+			if attachedTo(pos, i, w) {
+				found = true
+				face = i.Opposite()
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	t.Facing, t.Lit = face.Opposite(), true
+
+	place(w, pos, t, user, ctx)
+	if placed(ctx) {
+		w.UpdateRedstone(pos)
+		return true
+	}
+	return false
+}
+
+// NeighbourUpdateTick ...
+func (t RedstoneTorch) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if breakUnattached(t, pos, t.Facing, w) {
+		return
+	}
+	if t.Lit == t.powered(pos, w) {
+		w.ScheduleBlockUpdate(pos, time.Second/10)
+	}
+}
+
+// ScheduledTick ...
+func (t RedstoneTorch) ScheduledTick(pos cube.Pos, w *world.World, _ *rand.Rand) {
+	powered := t.powered(pos, w)
+	switch {
+	case t.Lit && powered:
+		t.Lit = false
+		setRedstone(pos, t, w)
+		if recordTorchToggle(pos, w) {
+			w.PlaySound(pos.Vec3Centre(), sound.Fizz{})
+			w.ScheduleBlockUpdate(pos, time.Second*8)
+		}
+	case !t.Lit && !powered:
+		if torchBurntOut(pos, w) {
+			w.ScheduleBlockUpdate(pos, time.Second)
+			return
+		}
+		t.Lit = true
+		setRedstone(pos, t, w)
+	}
+}
+
+// WeakPower ...
+func (t RedstoneTorch) WeakPower(_ cube.Pos, face cube.Face, _ *world.World, _ bool) int {
+	if !t.Lit || face == t.Facing {
+		return 0
+	}
+	return 15
+}
+
+// StrongPower ...
+func (t RedstoneTorch) StrongPower(_ cube.Pos, face cube.Face, _ *world.World, _ bool) int {
+	if !t.Lit || face != cube.FaceUp {
+		return 0
+	}
+	return 15
+}
+
+// HasLiquidDrops ...
+func (t RedstoneTorch) HasLiquidDrops() bool {
+	return true
+}
+
+// EncodeItem ...
+func (t RedstoneTorch) EncodeItem() (name string, meta int16) {
+	return "minecraft:redstone_torch", 0
+}
+
+// EncodeBlock ...
+func (t RedstoneTorch) EncodeBlock() (name string, properties map[string]any) {
+	face := t.Facing.String()
+	if t.Facing == cube.FaceDown {
+		face = "top"
+	}
+	if t.Lit {
+		return "minecraft:redstone_torch", map[string]any{"torch_facing_direction": face}
+	}
+	return "minecraft:unlit_redstone_torch", map[string]any{"torch_facing_direction": face}
+}
+
+// powered checks if the block that the torch at the position passed is attached to is powered.
+func (t RedstoneTorch) powered(pos cube.Pos, w *world.World) bool {
+	return w.RedstonePower(pos.Side(t.Facing), t.Facing.Opposite(), true) > 0
+}
+
+// allRedstoneTorches ...
+func allRedstoneTorches() (torches []world.Block) {
+	for _, f := range cube.Faces() {
+		if f == cube.FaceUp {
+			continue
+		}
+		torches = append(torches, RedstoneTorch{Facing: f, Lit: true}, RedstoneTorch{Facing: f})
+	}
+	return
+}
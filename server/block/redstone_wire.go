@@ -0,0 +1,249 @@
+package block
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// RedstoneWire is a block that is used to transfer a redstone signal. A redstone signal loses one level of power
+// for every block of redstone wire it passes through. Redstone wire is placed using redstone dust.
+type RedstoneWire struct {
+	empty
+	transparent
+
+	// Power is the current power level of the redstone wire. It ranges from 0 to 15.
+	Power int
+}
+
+// maxWireNetwork is the maximum amount of redstone wire that is updated at once when the power of a wire network
+// changes.
+const maxWireNetwork = 4096
+
+// HasLiquidDrops ...
+func (RedstoneWire) HasLiquidDrops() bool {
+	return true
+}
+
+// BreakInfo ...
+func (r RedstoneWire) BreakInfo() BreakInfo {
+	return newBreakInfo(0, alwaysHarvestable, nothingEffective, oneOf(RedstoneWire{})).withBreakHandler(func(pos cube.Pos, w *world.World, _ item.User) {
+		updateWireNetwork(pos, w)
+		w.UpdateRedstone(pos)
+	})
+}
+
+// UseOnBlock ...
+func (r RedstoneWire) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user item.User, ctx *item.UseContext) bool {
+	pos, _, used := firstReplaceable(w, pos, face, r)
+	if !used {
+		return false
+	}
+	if !attachedTo(pos, cube.FaceDown, w) {
+		return false
+	}
+	place(w, pos, RedstoneWire{}, user, ctx)
+	return placed(ctx)
+}
+
+// NeighbourUpdateTick ...
+func (r RedstoneWire) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if breakUnattached(r, pos, cube.FaceDown, w) {
+		updateWireNetwork(pos, w)
+		return
+	}
+	if r.Power != r.expectedPower(pos, w) {
+		updateWireNetwork(pos, w)
+	}
+}
+
+// WeakPower ...
+func (r RedstoneWire) WeakPower(pos cube.Pos, face cube.Face, w *world.World, accountForDust bool) int {
+	if !accountForDust || r.Power == 0 || face == cube.FaceUp {
+		return 0
+	}
+	if face == cube.FaceDown || r.points(pos, face, w) {
+		return r.Power
+	}
+	return 0
+}
+
+// StrongPower ...
+func (r RedstoneWire) StrongPower(pos cube.Pos, face cube.Face, w *world.World, accountForDust bool) int {
+	return r.WeakPower(pos, face, w, accountForDust)
+}
+
+// EncodeItem ...
+func (RedstoneWire) EncodeItem() (name string, meta int16) {
+	return "minecraft:redstone", 0
+}
+
+// EncodeBlock ...
+func (r RedstoneWire) EncodeBlock() (string, map[string]any) {
+	return "minecraft:redstone_wire", map[string]any{"redstone_signal": int32(r.Power)}
+}
+
+// points checks if the redstone wire at the position passed points towards the horizontal face passed, meaning it
+// powers the block on that side. Wire that is not connected to anything points in all directions, and wire that
+// is connected on one side also points towards the opposite side.
+func (r RedstoneWire) points(pos cube.Pos, face cube.Face, w *world.World) bool {
+	var connected []cube.Face
+	for _, f := range cube.HorizontalFaces() {
+		if r.connects(pos, f, w) {
+			connected = append(connected, f)
+		}
+	}
+	switch len(connected) {
+	case 0:
+		return true
+	case 1:
+		return connected[0] == face || connected[0] == face.Opposite()
+	}
+	for _, f := range connected {
+		if f == face {
+			return true
+		}
+	}
+	return false
+}
+
+// connects checks if the redstone wire at the position passed is connected to a block on the horizontal face
+// passed. Wire connects to other wire, either directly or one block up or down, and to redstone power sources.
+func (r RedstoneWire) connects(pos cube.Pos, face cube.Face, w *world.World) bool {
+	side := pos.Side(face)
+	switch b := w.Block(side).(type) {
+	case RedstoneWire:
+		return true
+	case RedstoneRepeater:
+		return b.Facing.Face().Axis() == face.Axis()
+	case world.RedstonePowerSource:
+		return true
+	}
+	for _, p := range r.steps(pos, face, w) {
+		if _, ok := w.Block(p).(RedstoneWire); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// steps returns the positions of redstone wire that the wire at the position passed may connect to one block up
+// or one block down on the face passed. Wire may connect to wire one block down if the block on its side does not
+// conduct redstone, and to wire one block up if the block above it does not conduct redstone.
+func (RedstoneWire) steps(pos cube.Pos, face cube.Face, w *world.World) []cube.Pos {
+	steps := make([]cube.Pos, 0, 2)
+	side := pos.Side(face)
+	if !w.ConductsRedstone(side) {
+		steps = append(steps, side.Side(cube.FaceDown))
+	}
+	if !w.ConductsRedstone(pos.Side(cube.FaceUp)) {
+		steps = append(steps, side.Side(cube.FaceUp))
+	}
+	return steps
+}
+
+// wireNeighbours returns the positions of all redstone wire connected to the redstone wire at the position
+// passed.
+func (r RedstoneWire) wireNeighbours(pos cube.Pos, w *world.World) []cube.Pos {
+	neighbours := make([]cube.Pos, 0, 4)
+	for _, f := range cube.HorizontalFaces() {
+		for _, p := range append(r.steps(pos, f, w), pos.Side(f)) {
+			if _, ok := w.Block(p).(RedstoneWire); ok {
+				neighbours = append(neighbours, p)
+			}
+		}
+	}
+	return neighbours
+}
+
+// inputPower returns the power that the redstone wire at the position passed receives from blocks other than
+// redstone wire.
+func (RedstoneWire) inputPower(pos cube.Pos, w *world.World) int {
+	return w.ReceivedRedstonePower(pos, false)
+}
+
+// expectedPower returns the power that the redstone wire at the position passed should have, based on the power
+// it receives from other blocks and the power of the wire connected to it.
+func (r RedstoneWire) expectedPower(pos cube.Pos, w *world.World) int {
+	power := r.inputPower(pos, w)
+	for _, p := range r.wireNeighbours(pos, w) {
+		if wire, ok := w.Block(p).(RedstoneWire); ok {
+			power = max(power, wire.Power-1)
+		}
+	}
+	return power
+}
+
+// updateWireNetwork recalculates the power of all redstone wire connected to the redstone wire at the position
+// passed. All wire that changed power is updated in the world at once, so that a signal travels through a wire
+// network within a single tick.
+func updateWireNetwork(start cube.Pos, w *world.World) {
+	var (
+		network = make(map[cube.Pos]int)
+		queue   = make([]cube.Pos, 0, 16)
+		wire    RedstoneWire
+	)
+	if _, ok := w.Block(start).(RedstoneWire); ok {
+		queue = append(queue, start)
+	} else {
+		// The wire at the start position was broken: Update all wire networks that were connected to it.
+		for _, f := range cube.HorizontalFaces() {
+			for _, p := range append(wire.steps(start, f, w), start.Side(f)) {
+				if _, ok := w.Block(p).(RedstoneWire); ok {
+					queue = append(queue, p)
+				}
+			}
+		}
+	}
+	for _, p := range queue {
+		network[p] = -1
+	}
+	// Collect the full network of wire and the power each wire receives from outside the network.
+	levels := make([][]cube.Pos, 16)
+	for len(queue) > 0 && len(network) < maxWireNetwork {
+		pos := queue[0]
+		queue = queue[1:]
+
+		power := wire.inputPower(pos, w)
+		network[pos] = power
+		levels[power] = append(levels[power], pos)
+		for _, p := range wire.wireNeighbours(pos, w) {
+			if _, ok := network[p]; !ok {
+				network[p] = -1
+				queue = append(queue, p)
+			}
+		}
+	}
+	// Spread power through the network from the highest power level down, reducing it by one for every wire.
+	for level := 15; level > 0; level-- {
+		for _, pos := range levels[level] {
+			if network[pos] != level {
+				continue
+			}
+			for _, p := range wire.wireNeighbours(pos, w) {
+				if current, ok := network[p]; ok && current >= 0 && current < level-1 {
+					network[p] = level - 1
+					levels[level-1] = append(levels[level-1], p)
+				}
+			}
+		}
+	}
+	for pos, power := range network {
+		if power < 0 {
+			// Wire beyond the maximum network size: Leave it to be updated by a later neighbour update.
+			continue
+		}
+		if b, ok := w.Block(pos).(RedstoneWire); ok && b.Power != power {
+			setRedstone(pos, RedstoneWire{Power: power}, w)
+		}
+	}
+}
+
+// allRedstoneWires ...
+func allRedstoneWires() (all []world.Block) {
+	for i := 0; i <= 15; i++ {
+		all = append(all, RedstoneWire{Power: i})
+	}
+	return
+}
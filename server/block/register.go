@@ -44,6 +44,8 @@ func init() {
 	world.RegisterBlock(Emerald{})
 	world.RegisterBlock(EnchantingTable{})
 	world.RegisterBlock(EndBricks{})
+	world.RegisterBlock(EndGateway{})
+	world.RegisterBlock(EndPortal{})
 	world.RegisterBlock(EndStone{})
 	world.RegisterBlock(FletchingTable{})
 	world.RegisterBlock(GlassPane{})
@@ -62,6 +64,7 @@ func init() {
 	world.RegisterBlock(Lapis{})
 	world.RegisterBlock(Melon{})
 	world.RegisterBlock(MossCarpet{})
+	world.RegisterBlock(Moving{})
 	world.RegisterBlock(MudBricks{})
 	world.RegisterBlock(Mud{})
 	world.RegisterBlock(NetherBrickFence{})
@@ -84,6 +87,9 @@ func init() {
 	world.RegisterBlock(RawCopper{})
 	world.RegisterBlock(RawGold{})
 	world.RegisterBlock(RawIron{})
+	world.RegisterBlock(RedstoneBlock{})
+	world.RegisterBlock(RedstoneLamp{Lit: true})
+	world.RegisterBlock(RedstoneLamp{})
 	world.RegisterBlock(ReinforcedDeepslate{})
 	world.RegisterBlock(Sand{Red: true})
 	world.RegisterBlock(Sand{})
@@ -98,7 +104,6 @@ func init() {
 	world.RegisterBlock(SporeBlossom{})
 	world.RegisterBlock(Stone{Smooth: true})
 	world.RegisterBlock(Stone{})
-	world.RegisterBlock(StructureBlock{})
 	world.RegisterBlock(TNT{})
 	world.RegisterBlock(Terracotta{})
 	world.RegisterBlock(Tuff{})
@@ -113,14 +118,18 @@ func init() {
 		world.RegisterBlock(LapisOre{Type: ore})
 	}
 
+	registerAll(allActivatorRails())
 	registerAll(allAnvils())
 	registerAll(allBanners())
 	registerAll(allBarrels())
 	registerAll(allBasalt())
+	registerAll(allBeds())
 	registerAll(allBeetroot())
 	registerAll(allBlackstone())
 	registerAll(allBlastFurnaces())
 	registerAll(allBoneBlock())
+	registerAll(allBrewingStands())
+	registerAll(allButtons())
 	registerAll(allCactus())
 	registerAll(allCake())
 	registerAll(allCarpet())
@@ -134,10 +143,13 @@ func init() {
 	registerAll(allCoral())
 	registerAll(allCoralBlocks())
 	registerAll(allDeepslate())
+	registerAll(allDetectorRails())
 	registerAll(allDoors())
 	registerAll(allDoubleFlowers())
 	registerAll(allDoubleTallGrass())
 	registerAll(allDispensers())
+	registerAll(allDroppers())
+	registerAll(allEndPortalFrames())
 	registerAll(allEnderChests())
 	registerAll(allFarmland())
 	registerAll(allFence())
@@ -157,6 +169,7 @@ func init() {
 	registerAll(allLava())
 	registerAll(allLeaves())
 	registerAll(allLecterns())
+	registerAll(allLevers())
 	registerAll(allLight())
 	registerAll(allLitPumpkins())
 	registerAll(allLogs())
@@ -164,14 +177,24 @@ func init() {
 	registerAll(allMelonStems())
 	registerAll(allMuddyMangroveRoots())
 	registerAll(allNetherBricks())
+	registerAll(allNetherPortals())
 	registerAll(allNetherWart())
+	registerAll(allPistonArmCollisions())
+	registerAll(allPistons())
 	registerAll(allPlanks())
 	registerAll(allPotato())
+	registerAll(allPoweredRails())
+	registerAll(allPressurePlates())
 	registerAll(allPrismarine())
 	registerAll(allPumpkinStems())
 	registerAll(allPumpkins())
 	registerAll(allPurpurs())
 	registerAll(allQuartz())
+	registerAll(allRails())
+	registerAll(allRedstoneComparators())
+	registerAll(allRedstoneRepeaters())
+	registerAll(allRedstoneTorches())
+	registerAll(allRedstoneWires())
 	registerAll(allSandstones())
 	registerAll(allSeaPickles())
 	registerAll(allSigns())
@@ -184,6 +207,7 @@ func init() {
 	registerAll(allStairs())
 	registerAll(allStoneBricks())
 	registerAll(allStonecutters())
+	registerAll(allStructureBlocks())
 	registerAll(allSugarCane())
 	registerAll(allTallGrass())
 	registerAll(allTorches())
@@ -197,6 +221,7 @@ func init() {
 }
 
 func init() {
+	world.RegisterItem(ActivatorRail{})
 	world.RegisterItem(Air{})
 	world.RegisterItem(Amethyst{})
 	world.RegisterItem(AncientDebris{})
@@ -213,6 +238,7 @@ func init() {
 	world.RegisterItem(BlueIce{})
 	world.RegisterItem(Bone{})
 	world.RegisterItem(Bookshelf{})
+	world.RegisterItem(BrewingStand{})
 	world.RegisterItem(Bricks{})
 	world.RegisterItem(Cactus{})
 	world.RegisterItem(Cake{})
@@ -233,6 +259,7 @@ func init() {
 	world.RegisterItem(DeepslateBricks{})
 	world.RegisterItem(DeepslateTiles{Cracked: true})
 	world.RegisterItem(DeepslateTiles{})
+	world.RegisterItem(DetectorRail{})
 	world.RegisterItem(Diamond{})
 	world.RegisterItem(Diorite{Polished: true})
 	world.RegisterItem(Diorite{})
@@ -240,12 +267,14 @@ func init() {
 	world.RegisterItem(Dirt{Coarse: true})
 	world.RegisterItem(Dirt{})
 	world.RegisterItem(Dispenser{})
+	world.RegisterItem(Dropper{})
 	world.RegisterItem(DragonEgg{})
 	world.RegisterItem(DriedKelp{})
 	world.RegisterItem(Dripstone{})
 	world.RegisterItem(Emerald{})
 	world.RegisterItem(EnchantingTable{})
 	world.RegisterItem(EndBricks{})
+	world.RegisterItem(EndPortalFrame{})
 	world.RegisterItem(EndStone{})
 	world.RegisterItem(EnderChest{})
 	world.RegisterItem(Farmland{})
@@ -272,6 +301,7 @@ func init() {
 	world.RegisterItem(Ladder{})
 	world.RegisterItem(Lapis{})
 	world.RegisterItem(Lectern{})
+	world.RegisterItem(Lever{})
 	world.RegisterItem(LitPumpkin{})
 	world.RegisterItem(Loom{})
 	world.RegisterItem(MelonSeeds{})
@@ -294,10 +324,13 @@ func init() {
 	world.RegisterItem(Obsidian{})
 	world.RegisterItem(PackedIce{})
 	world.RegisterItem(PackedMud{})
+	world.RegisterItem(Piston{Sticky: true})
+	world.RegisterItem(Piston{})
 	world.RegisterItem(Podzol{})
 	world.RegisterItem(PolishedBlackstoneBrick{Cracked: true})
 	world.RegisterItem(PolishedBlackstoneBrick{})
 	world.RegisterItem(Potato{})
+	world.RegisterItem(PoweredRail{})
 	world.RegisterItem(PumpkinSeeds{})
 	world.RegisterItem(Pumpkin{Carved: true})
 	world.RegisterItem(Pumpkin{})
@@ -307,9 +340,16 @@ func init() {
 	world.RegisterItem(QuartzPillar{})
 	world.RegisterItem(Quartz{Smooth: true})
 	world.RegisterItem(Quartz{})
+	world.RegisterItem(Rail{})
 	world.RegisterItem(RawCopper{})
 	world.RegisterItem(RawGold{})
 	world.RegisterItem(RawIron{})
+	world.RegisterItem(RedstoneBlock{})
+	world.RegisterItem(RedstoneComparator{})
+	world.RegisterItem(RedstoneLamp{})
+	world.RegisterItem(RedstoneRepeater{})
+	world.RegisterItem(RedstoneTorch{})
+	world.RegisterItem(RedstoneWire{})
 	world.RegisterItem(ReinforcedDeepslate{})
 	world.RegisterItem(Sand{Red: true})
 	world.RegisterItem(Sand{})
@@ -359,6 +399,7 @@ func init() {
 	}
 	for _, c := range item.Colours() {
 		world.RegisterItem(Banner{Colour: c})
+		world.RegisterItem(Bed{Colour: c})
 		world.RegisterItem(Carpet{Colour: c})
 		world.RegisterItem(ConcretePowder{Colour: c})
 		world.RegisterItem(Concrete{Colour: c})
@@ -396,6 +437,12 @@ func init() {
 		world.RegisterItem(Lantern{Type: f})
 		world.RegisterItem(Torch{Type: f})
 	}
+	for _, t := range ButtonTypes() {
+		world.RegisterItem(Button{Type: t})
+	}
+	for _, t := range PressurePlateTypes() {
+		world.RegisterItem(PressurePlate{Type: t})
+	}
 	for _, f := range FlowerTypes() {
 		world.RegisterItem(Flower{Type: f})
 	}
@@ -24,6 +24,8 @@ type smelter struct {
 	cookDuration      time.Duration
 	maxDuration       time.Duration
 	experience        int
+	// changed specifies if the durations or experience of the smelter changed since Changed was last called.
+	changed bool
 }
 
 // newSmelter initializes a new smelter with the given remaining, maximum, and cook durations and XP, and returns it.
@@ -59,6 +61,7 @@ func (s *smelter) ResetExperience() int {
 	defer s.mu.Unlock()
 	xp := s.experience
 	s.experience = 0
+	s.changed = s.changed || xp != 0
 	return xp
 }
 
@@ -67,6 +70,19 @@ func (s *smelter) Inventory() *inventory.Inventory {
 	return s.inventory
 }
 
+// Changed returns true if the inventory, durations or experience of the smelter changed since the last call to
+// Changed.
+func (s *smelter) Changed() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := s.changed
+	s.changed = false
+	return s.inventory.Changed() || changed
+}
+
 // AddViewer adds a viewer to the furnace, so that it is updated whenever the inventory of the furnace is changed.
 func (s *smelter) AddViewer(v ContainerViewer, _ *world.World, _ cube.Pos) {
 	s.mu.Lock()
@@ -110,6 +126,7 @@ func (s *smelter) tickSmelting(requirement, decrement time.Duration, lit bool, s
 	prevCookDuration := s.cookDuration
 	prevRemainingDuration := s.remainingDuration
 	prevMaxDuration := s.maxDuration
+	prevExperience := s.experience
 
 	// Now get each item in the smelter. We don't need to validate errors here since we know the bounds of the smelter.
 	input, _ := s.inventory.Item(0)
@@ -188,6 +205,10 @@ func (s *smelter) tickSmelting(requirement, decrement time.Duration, lit bool, s
 		s.cookDuration -= decrement
 	}
 
+	if s.cookDuration != prevCookDuration || s.remainingDuration != prevRemainingDuration || s.maxDuration != prevMaxDuration || s.experience != prevExperience {
+		s.changed = true
+	}
+
 	// Update the viewers on the new durations.
 	for v := range s.viewers {
 		v.ViewFurnaceUpdate(prevCookDuration, s.cookDuration, prevRemainingDuration, s.remainingDuration, prevMaxDuration, s.maxDuration)
@@ -1,6 +1,15 @@
 package block
 
-import "github.com/stcraft/dragonfly/server/internal/nbtconv"
+import (
+	"fmt"
+
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/permission"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/structure"
+)
 
 // StructureBlock is used to generate structures manually. They can also
 // be used to save and load structures, alongside structure void blocks.
@@ -10,6 +19,97 @@ type StructureBlock struct {
 	X int32
 	Y int32
 	Z int32
+
+	// Mode is the mode of the StructureBlock, which determines what happens
+	// when the StructureBlock is triggered.
+	Mode StructureBlockMode
+	// Name is the name of the structure that is saved or loaded by the
+	// StructureBlock.
+	Name string
+	// DataField is the custom data of a StructureBlock in data mode.
+	DataField string
+	// Offset is the offset of the region that the StructureBlock saves or
+	// loads a structure in, relative to the position of the StructureBlock.
+	Offset cube.Pos
+	// Size is the size of the region that the StructureBlock saves or loads
+	// a structure in.
+	Size cube.Pos
+	// Rotation is the number of times a loaded structure is rotated clockwise
+	// by 90 degrees. It ranges from 0 to 3.
+	Rotation int
+	// MirrorX and MirrorZ specify if a loaded structure is mirrored along the
+	// X and Z axis respectively.
+	MirrorX, MirrorZ bool
+	// IncludeEntities specifies if entities are included when saving or
+	// loading a structure.
+	IncludeEntities bool
+	// ShowBoundingBox specifies if the bounding box of the region of the
+	// StructureBlock is shown to players editing it.
+	ShowBoundingBox bool
+}
+
+// Activate ...
+func (s StructureBlock) Activate(pos cube.Pos, _ cube.Face, _ *world.World, u item.User, _ *item.UseContext) bool {
+	if g, ok := u.(interface{ GameMode() world.GameMode }); !ok || !g.GameMode().CreativeInventory() {
+		// Structure blocks can only be edited by players in creative mode.
+		return false
+	}
+	if h, ok := u.(permission.Holder); !ok || !h.Permissions().Operator() {
+		// Like in vanilla, structure blocks can only be edited by operators.
+		return false
+	}
+	if opener, ok := u.(ContainerOpener); ok {
+		opener.OpenBlockContainer(pos)
+		return true
+	}
+	return false
+}
+
+// Save saves the region of a StructureBlock in save mode at the position
+// passed as a structure to the structure.Storage passed, under the Name of
+// the StructureBlock.
+func (s StructureBlock) Save(pos cube.Pos, w *world.World, storage structure.Storage) error {
+	if s.Mode != StructureSaveMode() {
+		return fmt.Errorf("save structure: structure block is in %v mode", s.Mode)
+	} else if s.Name == "" {
+		return fmt.Errorf("save structure: structure block has no structure name")
+	} else if s.Size[0] <= 0 || s.Size[1] <= 0 || s.Size[2] <= 0 {
+		return fmt.Errorf("save structure: invalid size %v", s.Size)
+	}
+	origin := pos.Add(s.Offset)
+	return storage.Save(s.Name, structure.Capture(w, origin, origin.Add(s.Size).Sub(cube.Pos{1, 1, 1}), s.IncludeEntities))
+}
+
+// Load loads the structure with the Name of a StructureBlock in load mode at
+// the position passed from the structure.Storage passed, and places it in
+// the region of the StructureBlock after mirroring and rotating it. The Size
+// of the StructureBlock is updated to the size of the structure placed.
+func (s StructureBlock) Load(pos cube.Pos, w *world.World, storage structure.Storage) error {
+	if s.Mode != StructureLoadMode() {
+		return fmt.Errorf("load structure: structure block is in %v mode", s.Mode)
+	} else if s.Name == "" {
+		return fmt.Errorf("load structure: structure block has no structure name")
+	}
+	st, err := storage.Load(s.Name)
+	if err != nil {
+		return err
+	}
+	if !s.IncludeEntities {
+		st = st.WithoutEntities()
+	}
+	if s.MirrorX {
+		st = st.Mirror(cube.X)
+	}
+	if s.MirrorZ {
+		st = st.Mirror(cube.Z)
+	}
+	st = st.Rotate(s.Rotation)
+	st.Place(w, pos.Add(s.Offset))
+
+	dim := st.Dimensions()
+	s.Size = cube.Pos{dim[0], dim[1], dim[2]}
+	w.SetBlock(pos, s, nil)
+	return nil
 }
 
 // EncodeItem ...
@@ -20,7 +120,7 @@ func (s StructureBlock) EncodeItem() (name string, meta int16) {
 // EncodeBlock ...
 func (s StructureBlock) EncodeBlock() (string, map[string]any) {
 	return "minecraft:structure_block", map[string]any{
-		"structure_block_type": "data",
+		"structure_block_type": s.Mode.String(),
 	}
 }
 
@@ -30,34 +130,58 @@ func (s StructureBlock) DecodeNBT(data map[string]any) any {
 	s.Y = nbtconv.Int32(data, "y")
 	s.Z = nbtconv.Int32(data, "z")
 
+	s.Name = nbtconv.String(data, "structureName")
+	s.DataField = nbtconv.String(data, "dataField")
+	s.Offset = cube.Pos{int(nbtconv.Int32(data, "xStructureOffset")), int(nbtconv.Int32(data, "yStructureOffset")), int(nbtconv.Int32(data, "zStructureOffset"))}
+	s.Size = cube.Pos{int(nbtconv.Int32(data, "xStructureSize")), int(nbtconv.Int32(data, "yStructureSize")), int(nbtconv.Int32(data, "zStructureSize"))}
+	s.Rotation = int(nbtconv.Uint8(data, "rotation"))
+	mirror := nbtconv.Uint8(data, "mirror")
+	s.MirrorX, s.MirrorZ = mirror&1 != 0, mirror&2 != 0
+	s.IncludeEntities = nbtconv.Bool(data, "includeEntities")
+	s.ShowBoundingBox = nbtconv.Bool(data, "showBoundingBox")
 	return s
 }
 
 // EncodeNBT ...
 func (s StructureBlock) EncodeNBT() map[string]any {
+	var mirror byte
+	if s.MirrorX {
+		mirror |= 1
+	}
+	if s.MirrorZ {
+		mirror |= 2
+	}
 	return map[string]any{
 		"id":               "StructureBlock",
 		"x":                s.X,
 		"y":                s.Y,
 		"z":                s.Z,
-		"xStructureOffset": int32(0),
-		"yStructureOffset": int32(2),
-		"zStructureOffset": int32(0),
-		"xStructureSize":   int32(16),
-		"yStructureSize":   int32(256),
-		"zStructureSize":   int32(16),
-		"structureName":    "Chunk Border",
-		"showBoundingBox":  byte(1),
+		"xStructureOffset": int32(s.Offset[0]),
+		"yStructureOffset": int32(s.Offset[1]),
+		"zStructureOffset": int32(s.Offset[2]),
+		"xStructureSize":   int32(s.Size[0]),
+		"yStructureSize":   int32(s.Size[1]),
+		"zStructureSize":   int32(s.Size[2]),
+		"structureName":    s.Name,
+		"showBoundingBox":  boolByte(s.ShowBoundingBox),
 		"seed":             int64(0),
-		"rotation":         byte(0),
+		"rotation":         byte(s.Rotation),
 		"removeBlocks":     byte(0),
-		"mirror":           byte(0),
-		"isPowered":        byte(1),
+		"mirror":           mirror,
+		"isPowered":        byte(0),
 		"isMovable":        byte(0),
 		"integrity":        float32(1.0),
-		"includePlayers":   byte(1),
-		"includeEntities":  byte(0),
-		"dataField":        "",
-		"data":             int32(5),
+		"includePlayers":   byte(0),
+		"includeEntities":  boolByte(s.IncludeEntities),
+		"dataField":        s.DataField,
+		"data":             int32(s.Mode.Uint8()),
+	}
+}
+
+// allStructureBlocks returns all possible structure blocks.
+func allStructureBlocks() (blocks []world.Block) {
+	for _, m := range StructureBlockModes() {
+		blocks = append(blocks, StructureBlock{Mode: m})
 	}
+	return
 }
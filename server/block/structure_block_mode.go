@@ -0,0 +1,66 @@
+package block
+
+// StructureBlockMode represents the mode of a StructureBlock, which determines what the StructureBlock does
+// when it is triggered.
+type StructureBlockMode struct {
+	structureBlockMode
+}
+
+// StructureDataMode returns the data mode of a StructureBlock. Structure blocks in this mode mark positions
+// in a structure using their data field and do nothing when triggered.
+func StructureDataMode() StructureBlockMode {
+	return StructureBlockMode{0}
+}
+
+// StructureSaveMode returns the save mode of a StructureBlock. Structure blocks in this mode save the region
+// they enclose as a structure when triggered.
+func StructureSaveMode() StructureBlockMode {
+	return StructureBlockMode{1}
+}
+
+// StructureLoadMode returns the load mode of a StructureBlock. Structure blocks in this mode place a saved
+// structure when triggered.
+func StructureLoadMode() StructureBlockMode {
+	return StructureBlockMode{2}
+}
+
+// StructureCornerMode returns the corner mode of a StructureBlock. Structure blocks in this mode mark a corner
+// of the region that a StructureBlock in save mode with the same structure name encloses.
+func StructureCornerMode() StructureBlockMode {
+	return StructureBlockMode{3}
+}
+
+// StructureExportMode returns the export mode of a StructureBlock. Structure blocks in this mode allow the
+// client to export the region they enclose to a file and do nothing when triggered.
+func StructureExportMode() StructureBlockMode {
+	return StructureBlockMode{5}
+}
+
+// StructureBlockModes returns all structure block modes.
+func StructureBlockModes() []StructureBlockMode {
+	return []StructureBlockMode{StructureDataMode(), StructureSaveMode(), StructureLoadMode(), StructureCornerMode(), StructureExportMode()}
+}
+
+type structureBlockMode uint8
+
+// Uint8 returns the structure block mode as a uint8.
+func (m structureBlockMode) Uint8() uint8 {
+	return uint8(m)
+}
+
+// String returns the structure block mode as a string.
+func (m structureBlockMode) String() string {
+	switch m {
+	case 0:
+		return "data"
+	case 1:
+		return "save"
+	case 2:
+		return "load"
+	case 3:
+		return "corner"
+	case 5:
+		return "export"
+	}
+	panic("should never happen")
+}
@@ -36,6 +36,13 @@ func (t TNT) Ignite(pos cube.Pos, w *world.World, igniter world.Entity) bool {
 	return true
 }
 
+// NeighbourUpdateTick ...
+func (t TNT) NeighbourUpdateTick(pos, _ cube.Pos, w *world.World) {
+	if w.ReceivedRedstonePower(pos, true) > 0 {
+		t.Ignite(pos, w, nil)
+	}
+}
+
 // Igniter returns the entity that ignited the TNT.
 // It is nil if ignited by a world source like fire.
 func (t TNT) Igniter() world.Entity {
@@ -134,6 +134,11 @@ func (d WoodDoor) SideClosed(cube.Pos, cube.Pos, *world.World) bool {
 	return false
 }
 
+// PistonBreakable ...
+func (WoodDoor) PistonBreakable() bool {
+	return true
+}
+
 // EncodeItem ...
 func (d WoodDoor) EncodeItem() (name string, meta int16) {
 	if d.Wood == OakWood() {
@@ -0,0 +1,83 @@
+package vanilla
+
+import (
+	"github.com/stcraft/dragonfly/server/cmd"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// Difficulty implements the /difficulty command using the name of a difficulty, such as /difficulty hard.
+type Difficulty struct {
+	permission
+	Difficulty difficulty `cmd:"difficulty"`
+}
+
+// Run ...
+func (d Difficulty) Run(src cmd.Source, o *cmd.Output) {
+	var diff world.Difficulty
+	switch d.Difficulty {
+	case "peaceful", "p":
+		diff = world.DifficultyPeaceful
+	case "easy", "e":
+		diff = world.DifficultyEasy
+	case "hard", "h":
+		diff = world.DifficultyHard
+	default:
+		diff = world.DifficultyNormal
+	}
+	setDifficulty(src, diff, o)
+}
+
+// DifficultyID implements the /difficulty command using the numerical ID of a difficulty, such as
+// /difficulty 3.
+type DifficultyID struct {
+	permission
+	Difficulty int `cmd:"difficulty"`
+}
+
+// Run ...
+func (d DifficultyID) Run(src cmd.Source, o *cmd.Output) {
+	diff, ok := world.DifficultyByID(d.Difficulty)
+	if !ok {
+		o.Errorf("Unknown difficulty ID %v.", d.Difficulty)
+		return
+	}
+	setDifficulty(src, diff, o)
+}
+
+// setDifficulty sets the difficulty of the world of the source to the world.Difficulty passed.
+func setDifficulty(src cmd.Source, diff world.Difficulty, o *cmd.Output) {
+	w := src.World()
+	if w == nil {
+		o.Errorf("This command can only be run from within a world.")
+		return
+	}
+	w.SetDifficulty(diff)
+	o.Printf("Set the difficulty to %v.", difficultyName(diff))
+}
+
+// difficultyName returns a readable name of the world.Difficulty passed.
+func difficultyName(diff world.Difficulty) string {
+	switch diff {
+	case world.DifficultyPeaceful:
+		return "Peaceful"
+	case world.DifficultyEasy:
+		return "Easy"
+	case world.DifficultyHard:
+		return "Hard"
+	default:
+		return "Normal"
+	}
+}
+
+// difficulty is a cmd.Enum holding the names of the difficulties available.
+type difficulty string
+
+// Type ...
+func (difficulty) Type() string {
+	return "Difficulty"
+}
+
+// Options ...
+func (difficulty) Options(cmd.Source) []string {
+	return []string{"peaceful", "p", "easy", "e", "normal", "n", "hard", "h"}
+}
@@ -0,0 +1,7 @@
+// Package vanilla implements a set of commands found in vanilla Minecraft, such as /gamemode, /tp and /give,
+// built on top of the cmd package. None of the commands are registered by default: Register must be called
+// to make them available to players and the console.
+//
+// Every command in this package is gated by a permission of the form 'minecraft.command.<name>', which is
+// checked through the Permissions implementation passed to Register.
+package vanilla
@@ -0,0 +1,137 @@
+package vanilla
+
+import (
+	"time"
+
+	"github.com/stcraft/dragonfly/server/cmd"
+	"github.com/stcraft/dragonfly/server/entity/effect"
+)
+
+// EffectGive implements the /effect <player> <effect> command, adding a status effect to one or more
+// players.
+type EffectGive struct {
+	permission
+	Targets       []cmd.Target       `cmd:"player"`
+	Effect        effectName         `cmd:"effect"`
+	Seconds       cmd.Optional[int]  `cmd:"seconds"`
+	Amplifier     cmd.Optional[int]  `cmd:"amplifier"`
+	HideParticles cmd.Optional[bool] `cmd:"hideParticles"`
+}
+
+// Run ...
+func (e EffectGive) Run(_ cmd.Source, o *cmd.Output) {
+	pl, ok := targetPlayers(e.Targets, o)
+	if !ok {
+		return
+	}
+	t, ok := effect.ByID(effectIDs[string(e.Effect)])
+	if !ok {
+		o.Errorf("Unknown effect '%v'.", e.Effect)
+		return
+	}
+	seconds, amplifier := e.Seconds.LoadOr(30), e.Amplifier.LoadOr(0)
+	if seconds < 0 || seconds > 1000000 {
+		o.Errorf("The duration must be between 0 and 1000000 seconds, but %v was given.", seconds)
+		return
+	}
+	if amplifier < 0 || amplifier > 255 {
+		o.Errorf("The amplifier must be between 0 and 255, but %v was given.", amplifier)
+		return
+	}
+
+	var eff effect.Effect
+	if lasting, ok := t.(effect.LastingType); ok {
+		if seconds == 0 {
+			for _, p := range pl {
+				p.RemoveEffect(t)
+			}
+			o.Printf("Took %v from %v.", e.Effect, names(pl))
+			return
+		}
+		eff = effect.New(lasting, amplifier+1, time.Duration(seconds)*time.Second)
+	} else {
+		eff = effect.NewInstant(t, amplifier+1)
+	}
+	if e.HideParticles.LoadOr(false) {
+		eff = eff.WithoutParticles()
+	}
+	for _, p := range pl {
+		p.AddEffect(eff)
+	}
+	o.Printf("Gave %v * %v to %v for %v seconds.", e.Effect, amplifier+1, names(pl), seconds)
+}
+
+// EffectClear implements the /effect <player> clear command, removing all status effects from one or more
+// players.
+type EffectClear struct {
+	permission
+	Targets []cmd.Target   `cmd:"player"`
+	Clear   cmd.SubCommand `cmd:"clear"`
+}
+
+// Run ...
+func (e EffectClear) Run(_ cmd.Source, o *cmd.Output) {
+	pl, ok := targetPlayers(e.Targets, o)
+	if !ok {
+		return
+	}
+	for _, p := range pl {
+		for _, eff := range p.Effects() {
+			p.RemoveEffect(eff.Type())
+		}
+	}
+	o.Printf("Took all effects from %v.", names(pl))
+}
+
+// effectName is a cmd.Enum holding the vanilla names of all effects.
+type effectName string
+
+// Type ...
+func (effectName) Type() string {
+	return "Effect"
+}
+
+// Options ...
+func (effectName) Options(cmd.Source) []string {
+	options := make([]string, 0, len(effectIDs))
+	for name, id := range effectIDs {
+		if _, ok := effect.ByID(id); ok {
+			options = append(options, name)
+		}
+	}
+	return sorted(options)
+}
+
+// effectIDs maps the vanilla names of effects to the IDs that they are registered with.
+var effectIDs = map[string]int{
+	"speed":           1,
+	"slowness":        2,
+	"haste":           3,
+	"mining_fatigue":  4,
+	"strength":        5,
+	"instant_health":  6,
+	"instant_damage":  7,
+	"jump_boost":      8,
+	"nausea":          9,
+	"regeneration":    10,
+	"resistance":      11,
+	"fire_resistance": 12,
+	"water_breathing": 13,
+	"invisibility":    14,
+	"blindness":       15,
+	"night_vision":    16,
+	"hunger":          17,
+	"weakness":        18,
+	"poison":          19,
+	"wither":          20,
+	"health_boost":    21,
+	"absorption":      22,
+	"saturation":      23,
+	"levitation":      24,
+	"fatal_poison":    25,
+	"conduit_power":   26,
+	"slow_falling":    27,
+	"bad_omen":        28,
+	"village_hero":    29,
+	"darkness":        30,
+}
@@ -0,0 +1,122 @@
+package vanilla
+
+import (
+	"github.com/stcraft/dragonfly/server/cmd"
+	"github.com/stcraft/dragonfly/server/item"
+)
+
+// Enchant implements the /enchant command, adding an enchantment to the item held in the main hand of one
+// or more players.
+type Enchant struct {
+	permission
+	Targets     []cmd.Target      `cmd:"player"`
+	Enchantment enchantmentName   `cmd:"enchantmentName"`
+	Level       cmd.Optional[int] `cmd:"level"`
+}
+
+// Run ...
+func (e Enchant) Run(_ cmd.Source, o *cmd.Output) {
+	pl, ok := targetPlayers(e.Targets, o)
+	if !ok {
+		return
+	}
+	t, ok := item.EnchantmentByID(enchantmentIDs[string(e.Enchantment)])
+	if !ok {
+		o.Errorf("Unknown enchantment '%v'.", e.Enchantment)
+		return
+	}
+	lvl := e.Level.LoadOr(1)
+	if lvl < 1 || lvl > t.MaxLevel() {
+		o.Errorf("%v is not a valid level for %v. The level must be between 1 and %v.", lvl, t.Name(), t.MaxLevel())
+		return
+	}
+	n := 0
+	for _, p := range pl {
+		held, off := p.HeldItems()
+		if held.Empty() {
+			o.Errorf("%v is not holding an item.", p.Name())
+			continue
+		}
+		if !t.CompatibleWithItem(held.Item()) {
+			o.Errorf("%v cannot be applied to the item held by %v.", t.Name(), p.Name())
+			continue
+		}
+		compatible := true
+		for _, other := range held.Enchantments() {
+			if other.Type() != t && !t.CompatibleWithEnchantment(other.Type()) {
+				compatible = false
+				break
+			}
+		}
+		if !compatible {
+			o.Errorf("%v is not compatible with the enchantments of the item held by %v.", t.Name(), p.Name())
+			continue
+		}
+		p.SetHeldItems(held.WithEnchantments(item.NewEnchantment(t, lvl)), off)
+		n++
+	}
+	if n > 0 {
+		o.Printf("Enchanting succeeded for %v players.", n)
+	}
+}
+
+// enchantmentName is a cmd.Enum holding the vanilla names of all enchantments.
+type enchantmentName string
+
+// Type ...
+func (enchantmentName) Type() string {
+	return "Enchant"
+}
+
+// Options ...
+func (enchantmentName) Options(cmd.Source) []string {
+	options := make([]string, 0, len(enchantmentIDs))
+	for name, id := range enchantmentIDs {
+		if _, ok := item.EnchantmentByID(id); ok {
+			options = append(options, name)
+		}
+	}
+	return sorted(options)
+}
+
+// enchantmentIDs maps the vanilla names of enchantments to the IDs that they are registered with.
+var enchantmentIDs = map[string]int{
+	"protection":            0,
+	"fire_protection":       1,
+	"feather_falling":       2,
+	"blast_protection":      3,
+	"projectile_protection": 4,
+	"thorns":                5,
+	"respiration":           6,
+	"depth_strider":         7,
+	"aqua_affinity":         8,
+	"sharpness":             9,
+	"smite":                 10,
+	"bane_of_arthropods":    11,
+	"knockback":             12,
+	"fire_aspect":           13,
+	"looting":               14,
+	"efficiency":            15,
+	"silk_touch":            16,
+	"unbreaking":            17,
+	"fortune":               18,
+	"power":                 19,
+	"punch":                 20,
+	"flame":                 21,
+	"infinity":              22,
+	"luck_of_the_sea":       23,
+	"lure":                  24,
+	"frost_walker":          25,
+	"mending":               26,
+	"binding":               27,
+	"vanishing":             28,
+	"impaling":              29,
+	"riptide":               30,
+	"loyalty":               31,
+	"channeling":            32,
+	"multishot":             33,
+	"piercing":              34,
+	"quick_charge":          35,
+	"soul_speed":            36,
+	"swift_sneak":           37,
+}
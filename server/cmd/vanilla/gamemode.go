@@ -0,0 +1,116 @@
+package vanilla
+
+import (
+	"github.com/stcraft/dragonfly/server/cmd"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// GameMode implements the /gamemode command using the name of a game mode, such as /gamemode creative.
+type GameMode struct {
+	permission
+	Mode    gameMode                   `cmd:"gameMode"`
+	Targets cmd.Optional[[]cmd.Target] `cmd:"player"`
+}
+
+// Run ...
+func (g GameMode) Run(src cmd.Source, o *cmd.Output) {
+	mode, _ := g.Mode.GameMode()
+	setGameMode(src, mode, g.Targets, o)
+}
+
+// GameModeID implements the /gamemode command using the numerical ID of a game mode, such as /gamemode 1.
+type GameModeID struct {
+	permission
+	Mode    int                        `cmd:"gameMode"`
+	Targets cmd.Optional[[]cmd.Target] `cmd:"player"`
+}
+
+// Run ...
+func (g GameModeID) Run(src cmd.Source, o *cmd.Output) {
+	mode, ok := world.GameModeByID(g.Mode)
+	if !ok {
+		o.Errorf("Unknown game mode ID %v.", g.Mode)
+		return
+	}
+	setGameMode(src, mode, g.Targets, o)
+}
+
+// DefaultGameMode implements the /defaultgamemode command, changing the game mode that new players of the
+// world of the source are given.
+type DefaultGameMode struct {
+	permission
+	Mode gameMode `cmd:"gameMode"`
+}
+
+// Run ...
+func (d DefaultGameMode) Run(src cmd.Source, o *cmd.Output) {
+	w := src.World()
+	if w == nil {
+		o.Errorf("This command can only be run from within a world.")
+		return
+	}
+	mode, name := d.Mode.GameMode()
+	w.SetDefaultGameMode(mode)
+	o.Printf("The default game mode is now %v.", name)
+}
+
+// setGameMode sets the game mode of all players targeted to the world.GameMode passed.
+func setGameMode(src cmd.Source, mode world.GameMode, targets cmd.Optional[[]cmd.Target], o *cmd.Output) {
+	pl, ok := players(src, targets, o)
+	if !ok {
+		return
+	}
+	name := gameModeName(mode)
+	for _, p := range pl {
+		p.SetGameMode(mode)
+		if p == src {
+			o.Printf("Set own game mode to %v.", name)
+			continue
+		}
+		p.Messagef("Your game mode has been updated to %v.", name)
+		o.Printf("Set %v's game mode to %v.", p.Name(), name)
+	}
+}
+
+// gameModeName returns a readable name for the world.GameMode passed.
+func gameModeName(mode world.GameMode) string {
+	switch mode {
+	case world.GameModeCreative:
+		return "Creative Mode"
+	case world.GameModeAdventure:
+		return "Adventure Mode"
+	case world.GameModeSpectator:
+		return "Spectator Mode"
+	default:
+		return "Survival Mode"
+	}
+}
+
+// gameMode is a cmd.Enum holding the names of the game modes available.
+type gameMode string
+
+// Type ...
+func (gameMode) Type() string {
+	return "GameMode"
+}
+
+// Options ...
+func (gameMode) Options(cmd.Source) []string {
+	return []string{"survival", "s", "creative", "c", "adventure", "a", "spectator"}
+}
+
+// GameMode returns the world.GameMode that the gameMode represents and its readable name.
+func (g gameMode) GameMode() (world.GameMode, string) {
+	var mode world.GameMode
+	switch g {
+	case "creative", "c":
+		mode = world.GameModeCreative
+	case "adventure", "a":
+		mode = world.GameModeAdventure
+	case "spectator":
+		mode = world.GameModeSpectator
+	default:
+		mode = world.GameModeSurvival
+	}
+	return mode, gameModeName(mode)
+}
@@ -0,0 +1,123 @@
+package vanilla
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/stcraft/dragonfly/server/cmd"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/item/inventory"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// Give implements the /give command, adding an item to the inventory of one or more players. Items that do
+// not fit in the inventory are dropped at the feet of the player.
+type Give struct {
+	permission
+	Targets []cmd.Target      `cmd:"player"`
+	Item    itemName          `cmd:"itemName"`
+	Amount  cmd.Optional[int] `cmd:"amount"`
+}
+
+// Run ...
+func (g Give) Run(src cmd.Source, o *cmd.Output) {
+	pl, ok := targetPlayers(g.Targets, o)
+	if !ok {
+		return
+	}
+	it, ok := world.ItemByName("minecraft:"+string(g.Item), 0)
+	if !ok {
+		o.Errorf("Unknown item '%v'.", g.Item)
+		return
+	}
+	count := g.Amount.LoadOr(1)
+	if count < 1 || count > 32767 {
+		o.Errorf("The amount must be between 1 and 32767, but %v was given.", count)
+		return
+	}
+	for _, p := range pl {
+		for left := count; left > 0; {
+			n := min(left, item.NewStack(it, 1).MaxCount())
+			s := item.NewStack(it, n)
+			if added, _ := p.Inventory().AddItem(s); added < n {
+				p.Drop(s.Grow(-added))
+			}
+			left -= n
+		}
+	}
+	o.Printf("Gave %v * %v to %v.", g.Item, count, names(pl))
+}
+
+// Clear implements the /clear command, removing all items or items of a specific type from the inventories
+// of one or more players.
+type Clear struct {
+	permission
+	Targets cmd.Optional[[]cmd.Target] `cmd:"player"`
+	Item    cmd.Optional[itemName]     `cmd:"itemName"`
+}
+
+// Run ...
+func (c Clear) Run(src cmd.Source, o *cmd.Output) {
+	pl, ok := players(src, c.Targets, o)
+	if !ok {
+		return
+	}
+	name, filtered := c.Item.Load()
+	n := 0
+	for _, p := range pl {
+		for _, inv := range []*inventory.Inventory{p.Inventory(), p.Armour().Inventory()} {
+			for slot, s := range inv.Slots() {
+				if s.Empty() {
+					continue
+				}
+				if id, _ := s.Item().EncodeItem(); filtered && id != "minecraft:"+string(name) {
+					continue
+				}
+				n += s.Count()
+				_ = inv.SetItem(slot, item.Stack{})
+			}
+		}
+		if mainHand, offHand := p.HeldItems(); !offHand.Empty() {
+			if id, _ := offHand.Item().EncodeItem(); !filtered || id == "minecraft:"+string(name) {
+				n += offHand.Count()
+				p.SetHeldItems(mainHand, item.Stack{})
+			}
+		}
+	}
+	if n == 0 {
+		o.Errorf("Could not clear the inventory of %v, no items to remove.", names(pl))
+		return
+	}
+	o.Printf("Cleared the inventory of %v, removing %v items.", names(pl), n)
+}
+
+// itemName is a cmd.Enum holding the names of all items registered, without the 'minecraft:' prefix.
+type itemName string
+
+// Type ...
+func (itemName) Type() string {
+	return "Item"
+}
+
+// Options ...
+func (itemName) Options(cmd.Source) []string {
+	itemNamesOnce.Do(func() {
+		for _, it := range world.Items() {
+			name, meta := it.EncodeItem()
+			if meta != 0 || !strings.HasPrefix(name, "minecraft:") {
+				continue
+			}
+			itemNames = append(itemNames, strings.TrimPrefix(name, "minecraft:"))
+		}
+		sort.Strings(itemNames)
+	})
+	return itemNames
+}
+
+var (
+	// itemNamesOnce and itemNames are used to build the list of item names returned by itemName.Options once,
+	// as all items are registered by the time the first command is run.
+	itemNamesOnce sync.Once
+	itemNames     []string
+)
@@ -0,0 +1,54 @@
+package vanilla
+
+import (
+	"math"
+
+	"github.com/stcraft/dragonfly/server/cmd"
+	"github.com/stcraft/dragonfly/server/entity"
+	"github.com/stcraft/dragonfly/server/player"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// Kill implements the /kill command. Players targeted are killed immediately, other living entities are
+// dealt lethal damage and any other entities are removed from their world.
+type Kill struct {
+	permission
+	Targets cmd.Optional[[]cmd.Target] `cmd:"target"`
+}
+
+// Run ...
+func (k Kill) Run(src cmd.Source, o *cmd.Output) {
+	targets, ok := k.Targets.Load()
+	if !ok {
+		if _, ok := src.(*player.Player); !ok {
+			o.Errorf("A target must be specified when running this command from the console.")
+			return
+		}
+		targets = []cmd.Target{src}
+	}
+	n := 0
+	for _, t := range targets {
+		switch e := t.(type) {
+		case *player.Player:
+			if e.Dead() {
+				continue
+			}
+			e.Kill(entity.VoidDamageSource{}, e.Name()+" was killed.")
+		case entity.Living:
+			if e.Dead() {
+				continue
+			}
+			e.Hurt(math.MaxFloat64, entity.VoidDamageSource{})
+		case world.Entity:
+			_ = e.Close()
+		default:
+			continue
+		}
+		n++
+	}
+	if n == 0 {
+		o.Errorf("No targets matched selector.")
+		return
+	}
+	o.Printf("Killed %v entities.", n)
+}
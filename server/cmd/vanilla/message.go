@@ -0,0 +1,73 @@
+package vanilla
+
+import (
+	"strings"
+
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"github.com/stcraft/dragonfly/server"
+	"github.com/stcraft/dragonfly/server/cmd"
+)
+
+// Say implements the /say command, broadcasting a message to all players on the server.
+type Say struct {
+	permission
+	srv     *server.Server
+	Message cmd.Varargs `cmd:"message"`
+}
+
+// Run ...
+func (s Say) Run(src cmd.Source, o *cmd.Output) {
+	if strings.TrimSpace(string(s.Message)) == "" {
+		o.Errorf("The message must not be empty.")
+		return
+	}
+	s.srv.Broadcast("[%v] %v", sourceName(src), s.Message)
+}
+
+// Me implements the /me command, broadcasting a message about the source to all players on the server.
+type Me struct {
+	permission
+	srv    *server.Server
+	Action cmd.Varargs `cmd:"message"`
+}
+
+// Run ...
+func (m Me) Run(src cmd.Source, o *cmd.Output) {
+	if strings.TrimSpace(string(m.Action)) == "" {
+		o.Errorf("The message must not be empty.")
+		return
+	}
+	m.srv.Broadcast("* %v %v", sourceName(src), m.Action)
+}
+
+// Tell implements the /tell command, sending a private message to one or more players.
+type Tell struct {
+	permission
+	Targets []cmd.Target `cmd:"target"`
+	Message cmd.Varargs  `cmd:"message"`
+}
+
+// Run ...
+func (t Tell) Run(src cmd.Source, o *cmd.Output) {
+	pl, ok := targetPlayers(t.Targets, o)
+	if !ok {
+		return
+	}
+	if strings.TrimSpace(string(t.Message)) == "" {
+		o.Errorf("The message must not be empty.")
+		return
+	}
+	name := sourceName(src)
+	for _, p := range pl {
+		p.Message(text.Colourf("<grey><i>%v whispers to you: %v</i></grey>", name, t.Message))
+		o.Print(text.Colourf("<grey><i>You whisper to %v: %v</i></grey>", p.Name(), t.Message))
+	}
+}
+
+// sourceName returns the name of the cmd.Source passed, or 'Server' if the source has no name.
+func sourceName(src cmd.Source) string {
+	if n, ok := src.(cmd.NamedTarget); ok {
+		return n.Name()
+	}
+	return "Server"
+}
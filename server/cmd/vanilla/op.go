@@ -0,0 +1,60 @@
+package vanilla
+
+import (
+	"github.com/google/uuid"
+	"github.com/stcraft/dragonfly/server"
+	"github.com/stcraft/dragonfly/server/cmd"
+)
+
+// Op implements the /op command, granting operator status to a player. The player does not need to be online:
+// Operators are stored in the operator list of the permission.Manager of the server, so that the status is kept
+// when the player joins later.
+type Op struct {
+	srv *server.Server
+	permission
+	Player string `cmd:"player"`
+}
+
+// Run ...
+func (op Op) Run(_ cmd.Source, o *cmd.Output) {
+	id, name := uuid.Nil, op.Player
+	p, online := op.srv.PlayerByName(op.Player)
+	if online {
+		id, name = p.UUID(), p.Name()
+	}
+	if !op.srv.Permissions().AddOperator(id, name) {
+		o.Errorf("%v is already an operator.", name)
+		return
+	}
+	if online {
+		p.Permissions().SetOperator(true)
+		p.Message("You have been opped")
+	}
+	o.Printf("Opped: %v", name)
+}
+
+// Deop implements the /deop command, revoking operator status from a player, which does not need to be
+// online.
+type Deop struct {
+	srv *server.Server
+	permission
+	Player string `cmd:"player"`
+}
+
+// Run ...
+func (d Deop) Run(_ cmd.Source, o *cmd.Output) {
+	id, name := uuid.Nil, d.Player
+	p, online := d.srv.PlayerByName(d.Player)
+	if online {
+		id, name = p.UUID(), p.Name()
+	}
+	if !d.srv.Permissions().RemoveOperator(id, name) {
+		o.Errorf("%v is not an operator.", name)
+		return
+	}
+	if online {
+		p.Permissions().SetOperator(false)
+		p.Message("You have been de-opped")
+	}
+	o.Printf("De-opped: %v", name)
+}
@@ -0,0 +1,113 @@
+package vanilla
+
+import (
+	"github.com/stcraft/dragonfly/server"
+	"github.com/stcraft/dragonfly/server/cmd"
+)
+
+// List implements the /list command, listing all players currently online.
+type List struct {
+	permission
+	srv *server.Server
+}
+
+// Run ...
+func (l List) Run(_ cmd.Source, o *cmd.Output) {
+	pl := l.srv.Players()
+	o.Printf("There are %v/%v players online:", len(pl), l.srv.MaxPlayerCount())
+	o.Print(names(pl))
+}
+
+// Kick implements the /kick command, disconnecting one or more players from the server with an optional
+// reason.
+type Kick struct {
+	permission
+	Targets []cmd.Target `cmd:"name"`
+	Reason  cmd.Varargs  `cmd:"reason"`
+}
+
+// Run ...
+func (k Kick) Run(src cmd.Source, o *cmd.Output) {
+	pl, ok := targetPlayers(k.Targets, o)
+	if !ok {
+		return
+	}
+	msg := "Kicked by an operator."
+	if k.Reason != "" {
+		msg = string(k.Reason)
+	}
+	for _, p := range pl {
+		if p == src {
+			o.Errorf("You cannot kick yourself.")
+			continue
+		}
+		p.Disconnect(msg)
+		o.Printf("Kicked %v from the game: '%v'", p.Name(), msg)
+	}
+}
+
+// Stop implements the /stop command, closing the server.
+type Stop struct {
+	permission
+	srv *server.Server
+}
+
+// Run ...
+func (s Stop) Run(_ cmd.Source, o *cmd.Output) {
+	o.Printf("Stopping the server...")
+	// The server is closed in a separate goroutine: Closing the server disconnects all players, which waits for
+	// their sessions to stop processing packets, including the one that might be running this command.
+	go func() {
+		_ = s.srv.Close()
+	}()
+}
+
+// SaveAll implements the /save-all command, saving the worlds of the server and the data of all players online.
+type SaveAll struct {
+	permission
+	srv *server.Server
+}
+
+// Run ...
+func (s SaveAll) Run(_ cmd.Source, o *cmd.Output) {
+	o.Printf("Saving the game...")
+	if err := s.srv.Save(); err != nil {
+		o.Errorf("Saving failed: %v", err)
+		return
+	}
+	o.Printf("Saved the game")
+}
+
+// SaveOff implements the /save-off command, disabling the saving of the server so that its worlds may be backed
+// up.
+type SaveOff struct {
+	permission
+	srv *server.Server
+}
+
+// Run ...
+func (s SaveOff) Run(_ cmd.Source, o *cmd.Output) {
+	if !s.srv.Saving() {
+		o.Errorf("Saving is already turned off")
+		return
+	}
+	s.srv.SetSaving(false)
+	o.Printf("Automatic saving is now disabled")
+}
+
+// SaveOn implements the /save-on command, enabling the saving of the server after it was disabled using
+// /save-off.
+type SaveOn struct {
+	permission
+	srv *server.Server
+}
+
+// Run ...
+func (s SaveOn) Run(_ cmd.Source, o *cmd.Output) {
+	if s.srv.Saving() {
+		o.Errorf("Saving is already turned on")
+		return
+	}
+	s.srv.SetSaving(true)
+	o.Printf("Automatic saving is now enabled")
+}
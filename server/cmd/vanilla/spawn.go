@@ -0,0 +1,47 @@
+package vanilla
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/cmd"
+)
+
+// SetWorldSpawn implements the /setworldspawn command, setting the spawn of the world of the source to the
+// position passed or to the position of the source.
+type SetWorldSpawn struct {
+	permission
+	Position cmd.Optional[mgl64.Vec3] `cmd:"spawnPoint"`
+}
+
+// Run ...
+func (s SetWorldSpawn) Run(src cmd.Source, o *cmd.Output) {
+	w := src.World()
+	if w == nil {
+		o.Errorf("This command can only be run from within a world.")
+		return
+	}
+	pos := cube.PosFromVec3(s.Position.LoadOr(src.Position()))
+	w.SetSpawn(pos)
+	o.Printf("Set the world spawn point to (%v, %v, %v).", pos[0], pos[1], pos[2])
+}
+
+// SpawnPoint implements the /spawnpoint command, setting the position that one or more players respawn at
+// in their current world.
+type SpawnPoint struct {
+	permission
+	Targets  cmd.Optional[[]cmd.Target] `cmd:"player"`
+	Position cmd.Optional[mgl64.Vec3]   `cmd:"spawnPos"`
+}
+
+// Run ...
+func (s SpawnPoint) Run(src cmd.Source, o *cmd.Output) {
+	pl, ok := players(src, s.Targets, o)
+	if !ok {
+		return
+	}
+	for _, p := range pl {
+		pos := cube.PosFromVec3(s.Position.LoadOr(p.Position()))
+		p.World().SetPlayerSpawn(p.UUID(), pos)
+		o.Printf("Set the spawn point of %v to (%v, %v, %v).", p.Name(), pos[0], pos[1], pos[2])
+	}
+}
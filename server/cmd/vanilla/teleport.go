@@ -0,0 +1,106 @@
+package vanilla
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/cmd"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// TeleportToTarget implements the /tp <destination> command, teleporting the source to another target.
+type TeleportToTarget struct {
+	permission
+	Destination []cmd.Target `cmd:"destination"`
+}
+
+// Run ...
+func (t TeleportToTarget) Run(src cmd.Source, o *cmd.Output) {
+	teleportToTarget(src, []cmd.Target{src}, t.Destination, o)
+}
+
+// TeleportToPos implements the /tp <x y z> command, teleporting the source to a position.
+type TeleportToPos struct {
+	permission
+	Destination mgl64.Vec3 `cmd:"destination"`
+}
+
+// Run ...
+func (t TeleportToPos) Run(src cmd.Source, o *cmd.Output) {
+	teleport(src, []cmd.Target{src}, src.World(), t.Destination, o)
+}
+
+// TeleportTargetsToTarget implements the /tp <victim> <destination> command, teleporting one or more targets
+// to another target.
+type TeleportTargetsToTarget struct {
+	permission
+	Targets     []cmd.Target `cmd:"victim"`
+	Destination []cmd.Target `cmd:"destination"`
+}
+
+// Run ...
+func (t TeleportTargetsToTarget) Run(src cmd.Source, o *cmd.Output) {
+	teleportToTarget(src, t.Targets, t.Destination, o)
+}
+
+// TeleportTargetsToPos implements the /tp <victim> <x y z> command, teleporting one or more targets to a
+// position.
+type TeleportTargetsToPos struct {
+	permission
+	Targets     []cmd.Target `cmd:"victim"`
+	Destination mgl64.Vec3   `cmd:"destination"`
+}
+
+// Run ...
+func (t TeleportTargetsToPos) Run(src cmd.Source, o *cmd.Output) {
+	teleport(src, t.Targets, src.World(), t.Destination, o)
+}
+
+// teleporter is an entity that can be teleported, such as a *player.Player.
+type teleporter interface {
+	world.Entity
+	Teleport(pos mgl64.Vec3)
+}
+
+// teleportToTarget teleports all targets passed to the position of the first destination target.
+func teleportToTarget(src cmd.Source, targets, destination []cmd.Target, o *cmd.Output) {
+	if len(destination) != 1 {
+		o.Errorf("Only one destination may be selected, but %v were found.", len(destination))
+		return
+	}
+	dest := destination[0]
+	var w *world.World
+	if e, ok := dest.(world.Entity); ok {
+		w = e.World()
+	}
+	teleport(src, targets, w, dest.Position(), o)
+}
+
+// teleport teleports all targets passed to a position in the world.World passed. Targets in a different
+// world are first added to the world passed.
+func teleport(src cmd.Source, targets []cmd.Target, w *world.World, pos mgl64.Vec3, o *cmd.Output) {
+	if w == nil {
+		o.Errorf("This command can only be run from within a world.")
+		return
+	}
+	n := 0
+	for _, target := range targets {
+		t, ok := target.(teleporter)
+		if !ok {
+			continue
+		}
+		if t.World() != w {
+			w.AddEntity(t)
+		}
+		t.Teleport(pos)
+		n++
+		if named, ok := t.(cmd.NamedTarget); ok && target != src {
+			o.Printf("Teleported %v to %.2f, %.2f, %.2f.", named.Name(), pos[0], pos[1], pos[2])
+		}
+	}
+	if n == 0 {
+		o.Errorf("No targets matched selector.")
+		return
+	}
+	if n == 1 && len(targets) == 1 && targets[0] == src {
+		o.Printf("Teleported to %.2f, %.2f, %.2f.", pos[0], pos[1], pos[2])
+	}
+}
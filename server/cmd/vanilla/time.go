@@ -0,0 +1,118 @@
+package vanilla
+
+import (
+	"github.com/stcraft/dragonfly/server/cmd"
+)
+
+// TimeSet implements the /time set <amount> command, setting the time of the world of the source.
+type TimeSet struct {
+	permission
+	Set  cmd.SubCommand `cmd:"set"`
+	Time int            `cmd:"amount"`
+}
+
+// Run ...
+func (t TimeSet) Run(src cmd.Source, o *cmd.Output) {
+	setTime(src, t.Time, o)
+}
+
+// TimeSetPreset implements the /time set <time> command, setting the time of the world of the source to a
+// named time of the day, such as 'noon'.
+type TimeSetPreset struct {
+	permission
+	Set  cmd.SubCommand `cmd:"set"`
+	Time timeSpec       `cmd:"time"`
+}
+
+// Run ...
+func (t TimeSetPreset) Run(src cmd.Source, o *cmd.Output) {
+	setTime(src, timeSpecs[string(t.Time)], o)
+}
+
+// TimeAdd implements the /time add <amount> command, adding time to the current time of the world of the
+// source.
+type TimeAdd struct {
+	permission
+	Add  cmd.SubCommand `cmd:"add"`
+	Time int            `cmd:"amount"`
+}
+
+// Run ...
+func (t TimeAdd) Run(src cmd.Source, o *cmd.Output) {
+	setTime(src, src.World().Time()+t.Time, o)
+}
+
+// TimeQuery implements the /time query <query> command, returning the day time, game time or day of the
+// world of the source.
+type TimeQuery struct {
+	permission
+	Query cmd.SubCommand `cmd:"query"`
+	Value timeQuery      `cmd:"time"`
+}
+
+// Run ...
+func (t TimeQuery) Run(src cmd.Source, o *cmd.Output) {
+	w := src.World()
+	if w == nil {
+		o.Errorf("This command can only be run from within a world.")
+		return
+	}
+	switch t.Value {
+	case "daytime":
+		o.Printf("Daytime is %v.", w.Time()%24000)
+	case "gametime":
+		o.Printf("Gametime is %v.", w.Time())
+	case "day":
+		o.Printf("Day is %v.", w.Time()/24000)
+	}
+}
+
+// setTime sets the time of the world of the source to the time passed.
+func setTime(src cmd.Source, time int, o *cmd.Output) {
+	w := src.World()
+	if w == nil {
+		o.Errorf("This command can only be run from within a world.")
+		return
+	}
+	if time %= 24000; time < 0 {
+		time += 24000
+	}
+	w.SetTime(time)
+	o.Printf("Set the time to %v.", time)
+}
+
+// timeSpecs maps the names of times of the day to their respective time in ticks.
+var timeSpecs = map[string]int{
+	"day":      1000,
+	"noon":     6000,
+	"sunset":   12000,
+	"night":    13000,
+	"midnight": 18000,
+	"sunrise":  23000,
+}
+
+// timeSpec is a cmd.Enum holding the names of times of the day.
+type timeSpec string
+
+// Type ...
+func (timeSpec) Type() string {
+	return "TimeSpec"
+}
+
+// Options ...
+func (timeSpec) Options(cmd.Source) []string {
+	return []string{"day", "night", "noon", "midnight", "sunrise", "sunset"}
+}
+
+// timeQuery is a cmd.Enum holding the types of time that may be queried.
+type timeQuery string
+
+// Type ...
+func (timeQuery) Type() string {
+	return "TimeQuery"
+}
+
+// Options ...
+func (timeQuery) Options(cmd.Source) []string {
+	return []string{"daytime", "gametime", "day"}
+}
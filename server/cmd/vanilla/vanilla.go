@@ -0,0 +1,135 @@
+package vanilla
+
+import (
+	"sort"
+
+	"github.com/stcraft/dragonfly/server"
+	"github.com/stcraft/dragonfly/server/cmd"
+	"github.com/stcraft/dragonfly/server/player"
+)
+
+// Permissions decides whether a cmd.Source holds a specific permission. An implementation may be passed to
+// Register to control which sources are able to run the commands of this package.
+type Permissions interface {
+	// HasPermission checks if the cmd.Source passed holds the permission passed, such as
+	// 'minecraft.command.gamemode'. If false is returned, the source will not be able to see or run the
+	// command that requires it.
+	HasPermission(src cmd.Source, permission string) bool
+}
+
+// ConsoleOnly is a Permissions implementation that only allows sources that are not players, such as the
+// server console, to run commands. It is used by Register if no Permissions are passed.
+type ConsoleOnly struct{}
+
+// HasPermission returns true if the cmd.Source passed is not a *player.Player.
+func (ConsoleOnly) HasPermission(src cmd.Source, _ string) bool {
+	_, ok := src.(*player.Player)
+	return !ok
+}
+
+// Register registers the vanilla commands implemented in this package using cmd.Register. The commands
+// operate on the server.Server passed, which is used for commands such as /list, /save-all and /stop. The Permissions
+// passed decide which sources may run the commands. If nil, ConsoleOnly is used, meaning players will not be
+// able to run any of the commands registered. The server's permission.Manager, obtained through
+// server.Server.Permissions, may be passed to allow operators and players granted the permissions to run them.
+func Register(srv *server.Server, perms Permissions) {
+	if perms == nil {
+		perms = ConsoleOnly{}
+	}
+	p := func(name string) permission {
+		return permission{perm: "minecraft.command." + name, perms: perms}
+	}
+	for _, c := range []cmd.Command{
+		cmd.New("gamemode", "Sets a player's game mode.", []string{"gm"}, GameMode{permission: p("gamemode")}, GameModeID{permission: p("gamemode")}),
+		cmd.New("defaultgamemode", "Sets the default game mode of the world.", nil, DefaultGameMode{permission: p("defaultgamemode")}),
+		cmd.New("tp", "Teleports entities to a location or to another entity.", []string{"teleport"}, TeleportToTarget{permission: p("teleport")}, TeleportToPos{permission: p("teleport")}, TeleportTargetsToTarget{permission: p("teleport")}, TeleportTargetsToPos{permission: p("teleport")}),
+		cmd.New("give", "Gives an item to a player.", nil, Give{permission: p("give")}),
+		cmd.New("clear", "Clears items from a player's inventory.", nil, Clear{permission: p("clear")}),
+		cmd.New("enchant", "Adds an enchantment to a player's selected item.", nil, Enchant{permission: p("enchant")}),
+		cmd.New("effect", "Adds or removes status effects.", nil, EffectGive{permission: p("effect")}, EffectClear{permission: p("effect")}),
+		cmd.New("kill", "Kills entities.", nil, Kill{permission: p("kill")}),
+		cmd.New("xp", "Adds or removes player experience.", []string{"experience"}, Experience{permission: p("xp")}, ExperienceLevels{permission: p("xp")}),
+		cmd.New("time", "Changes or queries the world's game time.", nil, TimeSet{permission: p("time")}, TimeSetPreset{permission: p("time")}, TimeAdd{permission: p("time")}, TimeQuery{permission: p("time")}),
+		cmd.New("weather", "Sets the weather.", nil, Weather{permission: p("weather")}),
+		cmd.New("difficulty", "Sets the difficulty level.", nil, Difficulty{permission: p("difficulty")}, DifficultyID{permission: p("difficulty")}),
+		cmd.New("setworldspawn", "Sets the world spawn.", nil, SetWorldSpawn{permission: p("setworldspawn")}),
+		cmd.New("spawnpoint", "Sets the spawn point for a player.", nil, SpawnPoint{permission: p("spawnpoint")}),
+		cmd.New("say", "Sends a message in the chat to other players.", nil, Say{srv: srv, permission: p("say")}),
+		cmd.New("me", "Displays a message about yourself.", nil, Me{srv: srv, permission: p("me")}),
+		cmd.New("tell", "Sends a private message to one or more players.", []string{"msg", "w"}, Tell{permission: p("tell")}),
+		cmd.New("list", "Lists players on the server.", nil, List{srv: srv, permission: p("list")}),
+		cmd.New("op", "Grants operator status to a player.", nil, Op{srv: srv, permission: p("op")}),
+		cmd.New("deop", "Revokes operator status from a player.", nil, Deop{srv: srv, permission: p("deop")}),
+		cmd.New("kick", "Kicks a player from the server.", nil, Kick{permission: p("kick")}),
+		cmd.New("save-all", "Saves the server to disk.", nil, SaveAll{srv: srv, permission: p("save-all")}),
+		cmd.New("save-off", "Disables automatic server saves.", nil, SaveOff{srv: srv, permission: p("save-off")}),
+		cmd.New("save-on", "Enables automatic server saves.", nil, SaveOn{srv: srv, permission: p("save-on")}),
+		cmd.New("stop", "Stops the server.", nil, Stop{srv: srv, permission: p("stop")}),
+	} {
+		cmd.Register(c)
+	}
+}
+
+// permission is embedded in each of the Runnables in this package. It implements cmd.Allower by checking the
+// permission held through the Permissions passed to Register.
+type permission struct {
+	perm  string
+	perms Permissions
+}
+
+// Allow checks if the cmd.Source passed holds the permission required to run the command.
+func (p permission) Allow(src cmd.Source) bool {
+	if p.perms == nil {
+		return ConsoleOnly{}.HasPermission(src, p.perm)
+	}
+	return p.perms.HasPermission(src, p.perm)
+}
+
+// players converts the targets passed to a list of players. Targets that are not players are ignored. If the
+// optional targets were not set, the source is returned if it is a player. An error is added to the output if
+// no players could be found.
+func players(src cmd.Source, targets cmd.Optional[[]cmd.Target], o *cmd.Output) ([]*player.Player, bool) {
+	t, ok := targets.Load()
+	if !ok {
+		if p, ok := src.(*player.Player); ok {
+			return []*player.Player{p}, true
+		}
+		o.Errorf("A player must be specified when running this command from the console.")
+		return nil, false
+	}
+	return targetPlayers(t, o)
+}
+
+// targetPlayers converts the targets passed to a list of players. Targets that are not players are ignored. An
+// error is added to the output if none of the targets were players.
+func targetPlayers(t []cmd.Target, o *cmd.Output) ([]*player.Player, bool) {
+	pl := make([]*player.Player, 0, len(t))
+	for _, target := range t {
+		if p, ok := target.(*player.Player); ok {
+			pl = append(pl, p)
+		}
+	}
+	if len(pl) == 0 {
+		o.Errorf("No targets matched selector.")
+		return nil, false
+	}
+	return pl, true
+}
+
+// names returns the names of all players passed, joined by a comma.
+func names(pl []*player.Player) string {
+	s := ""
+	for i, p := range pl {
+		if i != 0 {
+			s += ", "
+		}
+		s += p.Name()
+	}
+	return s
+}
+
+// sorted sorts the strings passed in increasing order and returns them.
+func sorted(s []string) []string {
+	sort.Strings(s)
+	return s
+}
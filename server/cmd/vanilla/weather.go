@@ -0,0 +1,56 @@
+package vanilla
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/stcraft/dragonfly/server/cmd"
+)
+
+// Weather implements the /weather command, changing the weather in the world of the source.
+type Weather struct {
+	permission
+	Weather  weatherType       `cmd:"type"`
+	Duration cmd.Optional[int] `cmd:"duration"`
+}
+
+// Run ...
+func (c Weather) Run(src cmd.Source, o *cmd.Output) {
+	w := src.World()
+	if w == nil {
+		o.Errorf("This command can only be run from within a world.")
+		return
+	}
+	// Vanilla uses a random duration between 5 and 15 minutes if none is specified.
+	d := time.Duration(c.Duration.LoadOr(300+rand.Intn(600))) * time.Second
+	if d <= 0 {
+		o.Errorf("The duration must be positive.")
+		return
+	}
+	switch c.Weather {
+	case "clear":
+		w.StopThundering()
+		w.StopRaining()
+		o.Printf("Changing to clear weather.")
+	case "rain":
+		w.StopThundering()
+		w.StartRaining(d)
+		o.Printf("Changing to rainy weather.")
+	case "thunder":
+		w.StartThundering(d)
+		o.Printf("Changing to rain and thunder.")
+	}
+}
+
+// weatherType is a cmd.Enum holding the types of weather that may be set.
+type weatherType string
+
+// Type ...
+func (weatherType) Type() string {
+	return "WeatherType"
+}
+
+// Options ...
+func (weatherType) Options(cmd.Source) []string {
+	return []string{"clear", "rain", "thunder"}
+}
@@ -0,0 +1,80 @@
+package vanilla
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/stcraft/dragonfly/server/cmd"
+)
+
+// Experience implements the /xp <amount> command, adding or removing experience points from one or more
+// players.
+type Experience struct {
+	permission
+	Amount  int                        `cmd:"amount"`
+	Targets cmd.Optional[[]cmd.Target] `cmd:"player"`
+}
+
+// Run ...
+func (x Experience) Run(src cmd.Source, o *cmd.Output) {
+	pl, ok := players(src, x.Targets, o)
+	if !ok {
+		return
+	}
+	for _, p := range pl {
+		if x.Amount < 0 {
+			p.RemoveExperience(min(-x.Amount, p.Experience()))
+			continue
+		}
+		p.AddExperience(x.Amount)
+	}
+	o.Printf("Gave %v experience to %v.", x.Amount, names(pl))
+}
+
+// ExperienceLevels implements the /xp <amount>L command, adding or removing experience levels from one or
+// more players.
+type ExperienceLevels struct {
+	permission
+	Amount  levels                     `cmd:"amount"`
+	Targets cmd.Optional[[]cmd.Target] `cmd:"player"`
+}
+
+// Run ...
+func (x ExperienceLevels) Run(src cmd.Source, o *cmd.Output) {
+	pl, ok := players(src, x.Targets, o)
+	if !ok {
+		return
+	}
+	for _, p := range pl {
+		p.SetExperienceLevel(max(p.ExperienceLevel()+int(x.Amount), 0))
+	}
+	o.Printf("Gave %v levels to %v.", x.Amount, names(pl))
+}
+
+// levels is a cmd.Parameter for an amount of experience levels, written as a number followed by an 'L', such
+// as '5L'.
+type levels int
+
+// Type ...
+func (levels) Type() string {
+	return "levels"
+}
+
+// Parse ...
+func (levels) Parse(line *cmd.Line, v reflect.Value) error {
+	arg, ok := line.Next()
+	if !ok {
+		return cmd.ErrInsufficientArgs
+	}
+	if !strings.HasSuffix(strings.ToLower(arg), "l") {
+		return fmt.Errorf(`cannot parse argument "%v" as levels: expected a number followed by 'L'`, arg)
+	}
+	n, err := strconv.Atoi(arg[:len(arg)-1])
+	if err != nil {
+		return fmt.Errorf(`cannot parse argument "%v" as levels`, arg)
+	}
+	v.SetInt(int64(n))
+	return nil
+}
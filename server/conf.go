@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pelletier/go-toml"
@@ -13,12 +15,15 @@ import (
 	"github.com/stcraft/dragonfly/server/block"
 	"github.com/stcraft/dragonfly/server/entity"
 	"github.com/stcraft/dragonfly/server/internal/packbuilder"
+	"github.com/stcraft/dragonfly/server/permission"
 	"github.com/stcraft/dragonfly/server/player"
 	"github.com/stcraft/dragonfly/server/player/playerdb"
 	"github.com/stcraft/dragonfly/server/session"
 	"github.com/stcraft/dragonfly/server/world"
 	"github.com/stcraft/dragonfly/server/world/biome"
 	"github.com/stcraft/dragonfly/server/world/generator"
+	"github.com/stcraft/dragonfly/server/world/mcdb"
+	"github.com/stcraft/dragonfly/server/world/structure"
 )
 
 // Config contains options for starting a Minecraft server.
@@ -60,6 +65,30 @@ type Config struct {
 	// ReadOnly specifies whether the default worlds such as Overworld, Nether
 	// and the End should be opened in read only mode.
 	ReadOnly bool
+	// WorldsFolder is the directory in which the worlds of the Server are
+	// stored, each in a directory named after the world. Worlds loaded with a
+	// WorldSpec that has a Dir set may be stored outside of this directory. If
+	// left empty, WorldsFolder will be set to "worlds".
+	WorldsFolder string
+	// WorldProvider is a function used to create the world.Provider of a world
+	// stored in the directory passed. It is used for every world loaded that
+	// has no WorldSpec.Provider set. If left as nil, worlds are stored in the
+	// directory in the Minecraft format, using mcdb.
+	WorldProvider func(dir string) (world.Provider, error)
+	// Generator returns the world.Generator used to generate new chunks in
+	// the Dimension passed for worlds loaded without a WorldSpec.Generator,
+	// such as the default worlds. The seed passed is the seed stored in the
+	// data of the world. If left as nil, VanillaGenerator is used.
+	Generator func(dim world.Dimension, seed int64) world.Generator
+	// AutoLoadWorlds specifies if all worlds found in the WorldsFolder should
+	// be loaded when the Server is created, in addition to the default
+	// worlds. Worlds loaded this way are loaded as Overworld dimension with
+	// the generator returned by Generator.
+	AutoLoadWorlds bool
+	// Structures is the structure.Storage that structure blocks save structures
+	// to and load structures from. If left as nil, structures are stored as
+	// .mcstructure files in the "structures" directory.
+	Structures structure.Storage
 	// MaxPlayers is the maximum amount of players allowed to join the server at
 	// once.
 	MaxPlayers int
@@ -72,10 +101,54 @@ type Config struct {
 	// argument, which will be replaced with the name of the player joining or
 	// quitting.
 	JoinMessage, QuitMessage, ShutdownMessage string
+	// ValidateMovement specifies if the movement of players is validated by
+	// simulating it server-side. Players that move in a way that diverges too
+	// much from the simulated movement are reported through
+	// player.Handler.HandleMovementViolation and teleported back, unless the
+	// event is cancelled.
+	ValidateMovement bool
+	// MovementThreshold is the accumulated divergence, in blocks, between the
+	// position of a player and its simulated position at which the movement
+	// of the player is considered invalid. If left as 0, a threshold of 1 is
+	// used. MovementThreshold has no effect if ValidateMovement is false.
+	MovementThreshold float64
+	// ValidateCombat specifies if attacks of players are validated. The reach,
+	// line of sight, click rate and attack cooldown of attacks are checked,
+	// taking the latency of the player into account. Violations are reported
+	// through player.Handler.HandleCombatViolation and the attack is ignored,
+	// unless the event is cancelled.
+	ValidateCombat bool
+	// AttackReach is the maximum distance between the eyes of a player and
+	// the bounding box of an entity it attacks. Players in creative mode may
+	// reach 2 blocks further. If left as 0, a reach of 3 is used.
+	AttackReach float64
+	// MaxClicksPerSecond is the maximum number of times a player may attack
+	// or swing its arm per second. If left as 0, a maximum of 20 is used.
+	MaxClicksPerSecond int
+	// AttackCooldown is the minimum time between two attacks of a player. If
+	// left as 0, a cooldown of a single tick is used.
+	AttackCooldown time.Duration
+	// Permissions is the permission.Manager holding the permission groups of
+	// the server. It may be passed to vanilla.Register, or used to obtain a
+	// permission.Allower for commands. If left as nil, a new Manager without
+	// any groups is created.
+	Permissions *permission.Manager
+	// PermissionsFile is the path of the JSON file that the permission groups
+	// and operators of Permissions are persisted to. They are loaded from the
+	// file when the Server is created and saved to it when the Server is saved
+	// or closed. If left empty, they are kept only in memory and must be
+	// registered again every time the server starts.
+	PermissionsFile string
 	// PlayerProvider is the player.Provider used for storing and loading player
 	// data. If left as nil, player data will be newly created every time a
 	// player joins the server and no data will be stored.
 	PlayerProvider player.Provider
+	// AutoSaveInterval is the interval at which the worlds of the server and
+	// the data of players online are saved automatically, so that little is
+	// lost if the server is not closed properly. If left as 0, the server is
+	// saved every 5 minutes. Setting AutoSaveInterval to a negative value
+	// disables automatic saving.
+	AutoSaveInterval time.Duration
 	// RandomTickSpeed specifies the rate at which blocks should be ticked in
 	// the default worlds. Setting this value to -1 or lower will stop random
 	// ticking altogether, while setting it higher results in faster ticking. If
@@ -86,6 +159,11 @@ type Config struct {
 	// may be added to the Server's worlds. If no entity types are registered,
 	// Entities will be set to entity.DefaultRegistry.
 	Entities world.EntityRegistry
+	// Spawning holds settings for the natural spawning and despawning of
+	// entities in the default worlds. If Spawning.Entries is nil, it will be
+	// set to entity.SpawnEntries. Spawning may be disabled by setting
+	// Spawning.Disabled to true.
+	Spawning world.SpawnConfig
 }
 
 // Logger is used to report information and errors from a dragonfly Server. Any
@@ -147,12 +225,29 @@ func (conf Config) New() *Server {
 	if conf.Allower == nil {
 		conf.Allower = allower{}
 	}
+	if conf.Permissions == nil {
+		conf.Permissions = permission.NewManager()
+	}
+	if conf.PermissionsFile != "" {
+		if err := conf.Permissions.LoadFile(conf.PermissionsFile); err != nil {
+			// Don't save the permissions to the file, so that a file that could not
+			// be read isn't overwritten.
+			conf.Log.Errorf("config: %v", err)
+			conf.PermissionsFile = ""
+		}
+	}
 	if conf.MaxChunkRadius == 0 {
 		conf.MaxChunkRadius = 12
 	}
+	if conf.AutoSaveInterval == 0 {
+		conf.AutoSaveInterval = time.Minute * 5
+	}
 	if len(conf.Entities.Types()) == 0 {
 		conf.Entities = entity.DefaultRegistry
 	}
+	if conf.Spawning.Entries == nil {
+		conf.Spawning.Entries = entity.SpawnEntries
+	}
 	if !conf.DisableResourceBuilding {
 		if pack, ok := packbuilder.BuildResourcePack(); ok {
 			conf.Resources = append(conf.Resources, pack)
@@ -161,16 +256,42 @@ func (conf Config) New() *Server {
 	// Copy resources so that the slice can't be edited afterwards.
 	conf.Resources = slices.Clone(conf.Resources)
 
+	if conf.WorldsFolder == "" {
+		conf.WorldsFolder = "worlds"
+	}
+	if conf.Structures == nil {
+		conf.Structures = structure.DirStorage("structures")
+	}
+	if conf.Generator == nil {
+		conf.Generator = VanillaGenerator
+	}
+	if conf.WorldProvider == nil {
+		conf.WorldProvider = func(dir string) (world.Provider, error) {
+			return mcdb.Config{Log: conf.Log, Entities: conf.Entities}.Open(dir)
+		}
+	}
+
 	srv := &Server{
 		conf:     conf,
 		p:        make(map[uuid.UUID]*player.Player),
 		worlds:   make(map[string]*world.World),
+		dirs:     make(map[string]string),
 		handlers: make(map[string]player.Handler),
+		closing:  make(chan struct{}),
 	}
 
-	srv.LoadWorld("overworld", world.Overworld, nil, conf.ReadOnly)
-	srv.LoadWorld("nether", world.Nether, nil, conf.ReadOnly)
-	srv.LoadWorld("end", world.End, nil, conf.ReadOnly)
+	for _, spec := range []WorldSpec{
+		{Name: "overworld", Dimension: world.Overworld, ReadOnly: conf.ReadOnly},
+		{Name: "nether", Dimension: world.Nether, ReadOnly: conf.ReadOnly},
+		{Name: "end", Dimension: world.End, ReadOnly: conf.ReadOnly},
+	} {
+		if _, err := srv.LoadWorld(spec); err != nil {
+			panic(err)
+		}
+	}
+	if conf.AutoLoadWorlds {
+		srv.loadDiscoveredWorlds()
+	}
 
 	srv.registerTargetFunc()
 	srv.checkNetIsolation()
@@ -209,9 +330,29 @@ type UserConfig struct {
 		QuitMessage string
 	}
 	World struct {
+		// Folder is the directory in which the worlds of the server are
+		// stored. Each world is stored in a directory named after the world.
+		Folder string
+		// SaveData controls whether the data of worlds will be saved and
+		// loaded. If false, worlds will be empty every time the server starts
+		// and no data will be stored.
+		SaveData bool
+		// AutoLoad specifies whether all worlds found in the Folder should be
+		// loaded when the server starts.
+		AutoLoad bool
 		// ReadOnly specifies whether the default worlds like Overworld, Nether, End
 		// should be opened in read only mode
 		ReadOnly bool
+		// MobSpawning specifies whether mobs should spawn and despawn
+		// naturally in the default worlds.
+		MobSpawning bool
+		// Generator is the generator used for new chunks of the worlds of the
+		// server. It is one of "vanilla", "flat" and "void".
+		Generator string
+		// AutoSaveMinutes is the interval in minutes at which worlds and the
+		// data of players online are saved automatically. Set to 0 to disable
+		// automatic saving.
+		AutoSaveMinutes int
 	}
 	Players struct {
 		// MaxCount is the maximum amount of players allowed to join the server
@@ -231,6 +372,18 @@ type UserConfig struct {
 		// Folder controls where the player data will be stored by the default
 		// LevelDB player provider if it is enabled.
 		Folder string
+		// PermissionsFile is the JSON file that permission groups and
+		// operators are loaded from on startup and saved to. If empty, they are
+		// kept only in memory.
+		PermissionsFile string
+		// ValidateMovement controls whether the movement of players is
+		// simulated server-side and players moving in ways that are not
+		// possible are teleported back.
+		ValidateMovement bool
+		// ValidateCombat controls whether attacks of players are validated
+		// and attacks that are out of reach, through blocks or too fast are
+		// ignored.
+		ValidateCombat bool
 	}
 	Resources struct {
 		// AutoBuildPack is if the server should automatically generate a
@@ -256,23 +409,52 @@ func (uc UserConfig) Config(log Logger) (Config, error) {
 		ResourcesRequired:       uc.Resources.Required,
 		AuthDisabled:            !uc.Server.AuthEnabled,
 		ReadOnly:                uc.World.ReadOnly,
+		WorldsFolder:            uc.World.Folder,
+		AutoLoadWorlds:          uc.World.AutoLoad,
+		Spawning:                world.SpawnConfig{Disabled: !uc.World.MobSpawning},
 		MaxPlayers:              uc.Players.MaxCount,
 		MaxChunkRadius:          uc.Players.MaximumChunkRadius,
 		JoinMessage:             uc.Server.JoinMessage,
 		QuitMessage:             uc.Server.QuitMessage,
 		ShutdownMessage:         uc.Server.ShutdownMessage,
+		ValidateMovement:        uc.Players.ValidateMovement,
+		ValidateCombat:          uc.Players.ValidateCombat,
 		DisableResourceBuilding: !uc.Resources.AutoBuildPack,
 	}
+	switch strings.ToLower(uc.World.Generator) {
+	case "vanilla", "":
+		conf.Generator = VanillaGenerator
+	case "flat":
+		conf.Generator = func(dim world.Dimension, _ int64) world.Generator {
+			return FlatGenerator(dim)
+		}
+	case "void":
+		conf.Generator = func(dim world.Dimension, _ int64) world.Generator {
+			return VoidGenerator(dim)
+		}
+	default:
+		return conf, fmt.Errorf("unknown world generator %q", uc.World.Generator)
+	}
+	conf.AutoSaveInterval = time.Minute * time.Duration(uc.World.AutoSaveMinutes)
+	if uc.World.AutoSaveMinutes <= 0 {
+		conf.AutoSaveInterval = -1
+	}
 	conf.Resources, err = loadResources(uc.Resources.Folder)
 	if err != nil {
 		return conf, fmt.Errorf("load resources: %w", err)
 	}
+	if !uc.World.SaveData {
+		conf.WorldProvider = func(string) (world.Provider, error) {
+			return world.NopProvider{}, nil
+		}
+	}
 	if uc.Players.SaveData {
 		conf.PlayerProvider, err = playerdb.NewProvider(uc.Players.Folder)
 		if err != nil {
 			return conf, fmt.Errorf("create player provider: %w", err)
 		}
 	}
+	conf.PermissionsFile = uc.Players.PermissionsFile
 	conf.Listeners = append(conf.Listeners, uc.listenerFunc)
 	return conf, nil
 }
@@ -321,6 +503,20 @@ func FlatGenerator(dim world.Dimension) world.Generator {
 	panic("should never happen")
 }
 
+// VanillaGenerator loads a world.Generator for a world.Dimension that generates
+// terrain similar to that of vanilla, using the seed passed.
+func VanillaGenerator(dim world.Dimension, seed int64) world.Generator {
+	switch dim {
+	case world.Overworld:
+		return generator.NewOverworld(seed)
+	case world.Nether:
+		return generator.NewNether(seed)
+	case world.End:
+		return generator.NewEnd(seed)
+	}
+	panic("should never happen")
+}
+
 // DefaultConfig returns a configuration with the default values filled out.
 func DefaultConfig() UserConfig {
 	c := UserConfig{}
@@ -330,10 +526,17 @@ func DefaultConfig() UserConfig {
 	c.Server.AuthEnabled = true
 	c.Server.JoinMessage = "%v has joined the game"
 	c.Server.QuitMessage = "%v has left the game"
+	c.World.Folder = "worlds"
+	c.World.SaveData = true
+	c.World.AutoLoad = true
 	c.World.ReadOnly = false
+	c.World.MobSpawning = true
+	c.World.Generator = "vanilla"
+	c.World.AutoSaveMinutes = 5
 	c.Players.MaximumChunkRadius = 32
 	c.Players.SaveData = true
 	c.Players.Folder = "players"
+	c.Players.PermissionsFile = "permissions.json"
 	c.Resources.AutoBuildPack = true
 	c.Resources.Folder = "resources"
 	c.Resources.Required = false
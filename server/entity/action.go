@@ -42,9 +42,37 @@ type PickedUpAction struct {
 	action
 }
 
+// EatGrassAction is a world.EntityAction that makes a sheep display the animation of eating grass.
+type EatGrassAction struct{ action }
+
 // FireworkExplosionAction is a world.EntityAction that makes a Firework rocket display an explosion particle.
 type FireworkExplosionAction struct{ action }
 
+// WakeUpAction is a world.EntityAction that makes a sleeping entity leave the bed it is sleeping in.
+type WakeUpAction struct{ action }
+
+// MountAction is a world.EntityAction that makes an entity start riding a vehicle, such as a boat or a minecart.
+type MountAction struct {
+	// Vehicle is the entity that is being ridden.
+	Vehicle world.Entity
+	// Driver is true if the entity is in the driver seat of the vehicle, allowing it to steer.
+	Driver bool
+
+	action
+}
+
+// DismountAction is a world.EntityAction that makes an entity stop riding a vehicle.
+type DismountAction struct {
+	// Vehicle is the entity that was being ridden.
+	Vehicle world.Entity
+
+	action
+}
+
+// FishingHookBiteAction is a world.EntityAction that makes a fishing hook display a fish biting it, pulling the hook
+// under water.
+type FishingHookBiteAction struct{ action }
+
 // action implements the Action interface. Structures in this package may embed it to gets its functionality
 // out of the box.
 type action struct{}
@@ -0,0 +1,200 @@
+package entity
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// Boat is a Driveable entity that travels quickly over water. Boats may be ridden by up to two entities, of which
+// the first steers the boat.
+type Boat struct {
+	vehicle
+	wood item.BoatType
+
+	// forward and strafe hold the input of the driver of the boat, which is applied and reset when the boat
+	// is ticked.
+	forward, strafe float64
+}
+
+// NewBoat creates a new Boat made of the type of wood passed, facing the yaw passed.
+func NewBoat(pos mgl64.Vec3, yaw float64, wood item.BoatType) *Boat {
+	return &Boat{
+		vehicle: vehicle{seats: newSeats(2), pos: pos, rot: cube.Rotation{yaw, 0}, mc: &MovementComputer{Gravity: 0.04}},
+		wood:    wood,
+	}
+}
+
+const (
+	// boatDraft is the depth that a floating boat sinks below the surface of the water.
+	boatDraft = 0.1
+	// boatFriction is the friction applied to the velocity of a boat every tick in water or air.
+	boatFriction = 0.9
+)
+
+// Type returns BoatType.
+func (b *Boat) Type() world.EntityType {
+	return BoatType{}
+}
+
+// Wood returns the type of wood that the Boat is made of.
+func (b *Boat) Wood() item.BoatType {
+	return b.wood
+}
+
+// Variant returns the variant of the boat entity, which depends on the type of wood that it is made of.
+func (b *Boat) Variant() int32 {
+	return int32(b.wood.Uint8())
+}
+
+// World returns the world that the Boat is in.
+func (b *Boat) World() *world.World {
+	w, _ := world.OfEntity(b)
+	return w
+}
+
+// Teleport teleports the Boat to the position passed.
+func (b *Boat) Teleport(pos mgl64.Vec3) {
+	b.teleport(b, pos)
+}
+
+// SeatOffset returns the offset of the seat of the rider passed. When two entities ride the Boat, the driver
+// sits in the front and the passenger in the back.
+func (b *Boat) SeatOffset(e world.Entity) mgl64.Vec3 {
+	if len(b.Riders()) < 2 {
+		return mgl64.Vec3{0, 1.02}
+	}
+	if b.seat(e) == 0 {
+		return mgl64.Vec3{0.2, 1.02}
+	}
+	return mgl64.Vec3{-0.6, 1.02}
+}
+
+// Drive stores the input of the driver of the Boat. The input is applied the next time the Boat is ticked.
+func (b *Boat) Drive(driver world.Entity, forward, strafe, _ float64) {
+	if d, ok := b.driver(); !ok || d != driver {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.forward, b.strafe = forward, strafe
+}
+
+// Hurt damages the Boat. The Boat breaks and drops itself as an item once it has taken enough damage.
+func (b *Boat) Hurt(dmg float64, src world.DamageSource) (float64, bool) {
+	if broken, drops := b.hurt(b, dmg, src); broken {
+		if drops {
+			b.destroy(b, item.NewStack(item.Boat{Type: b.wood}, 1))
+		} else {
+			b.destroy(b)
+		}
+	}
+	return dmg, true
+}
+
+// Tick moves the Boat, making it float on water and applying the input of its driver.
+func (b *Boat) Tick(w *world.World, _ int64) {
+	b.dismountRemoved(w)
+	b.tickDamage()
+
+	b.mu.Lock()
+	pos, vel, rot := b.pos, b.vel, b.rot
+	if surface, ok := waterSurface(w, pos); ok && surface > pos[1] {
+		// The boat floats: Instead of gravity, the boat is pushed towards the surface of the water.
+		b.mc.Gravity = 0
+		vel[1] = (vel[1] + math.Min(surface-pos[1]-boatDraft, 0.5)*0.2) * 0.6
+	} else {
+		b.mc.Gravity = 0.04
+	}
+	vel[0] *= boatFriction
+	vel[2] *= boatFriction
+
+	rot[0] -= b.strafe * 5
+	acceleration := 0.0
+	if b.forward > 0 {
+		acceleration = 0.04
+	} else if b.forward < 0 {
+		acceleration = -0.005
+	} else if b.strafe != 0 {
+		acceleration = 0.005
+	}
+	vel = vel.Add(yawDirection(rot[0]).Mul(acceleration))
+	b.forward, b.strafe = 0, 0
+	b.mu.Unlock()
+
+	m := b.mc.TickMovement(b, pos, vel, rot)
+	m.Send()
+
+	b.mu.Lock()
+	b.pos, b.vel, b.rot = m.pos, m.vel, m.rot
+	b.mu.Unlock()
+}
+
+// Close removes the Boat from its world.
+func (b *Boat) Close() error {
+	b.dismountAll()
+	if w := b.World(); w != nil {
+		w.RemoveEntity(b)
+	}
+	return nil
+}
+
+// waterSurface returns the height of the surface of the water that an entity at the position passed floats in.
+// If there is no water at the position of the entity or directly below it, false is returned.
+func waterSurface(w *world.World, pos mgl64.Vec3) (float64, bool) {
+	p := cube.PosFromVec3(pos)
+	if !waterAt(w, p) {
+		if p = p.Side(cube.FaceDown); !waterAt(w, p) {
+			return 0, false
+		}
+	}
+	for waterAt(w, p.Side(cube.FaceUp)) {
+		p = p.Side(cube.FaceUp)
+	}
+	l, _ := w.Liquid(p)
+	return float64(p[1]) + float64(l.LiquidDepth())/9, true
+}
+
+// waterAt checks if the block at the position passed holds water.
+func waterAt(w *world.World, pos cube.Pos) bool {
+	l, ok := w.Liquid(pos)
+	if !ok {
+		return false
+	}
+	_, ok = l.(block.Water)
+	return ok
+}
+
+// BoatType is a world.EntityType implementation for Boat.
+type BoatType struct{}
+
+func (BoatType) EncodeEntity() string   { return "minecraft:boat" }
+func (BoatType) NetworkOffset() float64 { return 0.375 }
+func (BoatType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.7, 0, -0.7, 0.7, 0.455, 0.7)
+}
+
+func (BoatType) DecodeNBT(m map[string]any) world.Entity {
+	wood := item.OakBoat()
+	if v := int(nbtconv.Int32(m, "Variant")); v >= 0 && v < len(item.BoatTypes()) {
+		wood = item.BoatTypes()[v]
+	}
+	b := NewBoat(nbtconv.Vec3(m, "Pos"), float64(nbtconv.Float32(m, "Yaw")), wood)
+	b.vel = nbtconv.Vec3(m, "Motion")
+	return b
+}
+
+func (BoatType) EncodeNBT(e world.Entity) map[string]any {
+	b := e.(*Boat)
+	return map[string]any{
+		"Pos":     nbtconv.Vec3ToFloat32Slice(b.Position()),
+		"Motion":  nbtconv.Vec3ToFloat32Slice(b.Velocity()),
+		"Yaw":     float32(b.Rotation().Yaw()),
+		"Variant": b.Variant(),
+	}
+}
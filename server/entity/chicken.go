@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"math/rand"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/sound"
+)
+
+// NewChicken creates a new chicken at the position passed. Chickens are
+// passive mobs that follow players holding seeds and lay eggs every now and
+// then. They fall slowly and do not take fall damage.
+func NewChicken(pos mgl64.Vec3) *Mob {
+	b := &ChickenBehaviour{eggTicks: chickenEggTicks()}
+	b.MobBehaviour = MobBehaviourConfig{
+		Goals: []Goal{
+			SwimGoal{},
+			PanicGoal{Speed: 1.4},
+			&TemptGoal{Items: []world.Item{block.WheatSeeds{}, block.BeetrootSeeds{}, block.MelonSeeds{}, block.PumpkinSeeds{}}, Speed: 1},
+			WanderGoal{Speed: 1},
+			&LookAtPlayerGoal{Distance: 6},
+			&RandomLookGoal{},
+		},
+		NoFallDamage: true,
+		Path:         PathConfig{AvoidWater: true},
+		Tick:         b.tick,
+		Drops:        chickenDrops,
+	}.New()
+
+	conf := chickenConf
+	conf.Behaviour = b
+	return conf.New(ChickenType{}, pos)
+}
+
+var chickenConf = MobConfig{
+	MaxHealth:  4,
+	Speed:      0.125,
+	Experience: 2,
+	Category:   world.SpawnCategoryCreature,
+	Persistent: true,
+}
+
+// chickenEggTicks returns a random amount of ticks until a chicken lays its
+// next egg.
+func chickenEggTicks() int {
+	return 6000 + rand.Intn(6000)
+}
+
+// ChickenBehaviour implements the behaviour of a chicken, which lays an egg
+// every now and then.
+type ChickenBehaviour struct {
+	*MobBehaviour
+	eggTicks int
+}
+
+// tick slows down the fall of the chicken and makes it lay an egg every now
+// and then.
+func (b *ChickenBehaviour) tick(m *Mob) {
+	if vel := m.Velocity(); !m.OnGround() && vel[1] < 0 {
+		// Chickens flap their wings while falling, slowing them down.
+		vel[1] *= 0.6
+		m.SetVelocity(vel)
+	}
+	if b.eggTicks--; b.eggTicks <= 0 {
+		b.eggTicks = chickenEggTicks()
+
+		w, pos := m.World(), m.Position()
+		w.PlaySound(pos, sound.Pop{})
+		egg := NewItem(item.NewStack(item.Egg{}, 1), pos)
+		egg.SetVelocity(mgl64.Vec3{rand.Float64()*0.2 - 0.1, 0.2, rand.Float64()*0.2 - 0.1})
+		w.AddEntity(egg)
+	}
+}
+
+// chickenDrops returns the items dropped by a chicken when it dies.
+func chickenDrops(m *Mob) []item.Stack {
+	return []item.Stack{mobDrop(item.Feather{}, 0, 2), item.NewStack(item.Chicken{Cooked: m.OnFireDuration() > 0}, 1)}
+}
+
+// ChickenType is a world.EntityType implementation for chickens.
+type ChickenType struct{}
+
+func (ChickenType) EncodeEntity() string { return "minecraft:chicken" }
+func (ChickenType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.2, 0, -0.2, 0.2, 0.7, 0.2)
+}
+
+func (ChickenType) DecodeNBT(m map[string]any) world.Entity {
+	c := decodeMobNBT(m, NewChicken(nbtconv.Vec3(m, "Pos")))
+	if t := nbtconv.Int32(m, "EggLayTime"); t > 0 {
+		c.Behaviour().(*ChickenBehaviour).eggTicks = int(t)
+	}
+	return c
+}
+
+func (ChickenType) EncodeNBT(e world.Entity) map[string]any {
+	c := e.(*Mob)
+	data := encodeMobNBT(c)
+	data["EggLayTime"] = int32(c.Behaviour().(*ChickenBehaviour).eggTicks)
+	return data
+}
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// NewCow creates a new cow at the position passed. Cows are passive mobs that
+// wander around and follow players holding wheat.
+func NewCow(pos mgl64.Vec3) *Mob {
+	conf := cowConf
+	conf.Behaviour = MobBehaviourConfig{
+		Goals: []Goal{
+			SwimGoal{},
+			PanicGoal{Speed: 2},
+			&TemptGoal{Items: []world.Item{item.Wheat{}}, Speed: 1.25},
+			WanderGoal{Speed: 1},
+			&LookAtPlayerGoal{Distance: 6},
+			&RandomLookGoal{},
+		},
+		Path:  PathConfig{AvoidWater: true},
+		Drops: cowDrops,
+	}.New()
+	return conf.New(CowType{}, pos)
+}
+
+var cowConf = MobConfig{
+	MaxHealth:  10,
+	Speed:      0.1,
+	Experience: 2,
+	Category:   world.SpawnCategoryCreature,
+	Persistent: true,
+}
+
+// cowDrops returns the items dropped by a cow when it dies.
+func cowDrops(m *Mob) []item.Stack {
+	return []item.Stack{mobDrop(item.Leather{}, 0, 2), mobDrop(item.Beef{Cooked: m.OnFireDuration() > 0}, 1, 3)}
+}
+
+// CowType is a world.EntityType implementation for cows.
+type CowType struct{}
+
+func (CowType) EncodeEntity() string { return "minecraft:cow" }
+func (CowType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.45, 0, -0.45, 0.45, 1.3, 0.45)
+}
+
+func (CowType) DecodeNBT(m map[string]any) world.Entity {
+	return decodeMobNBT(m, NewCow(nbtconv.Vec3(m, "Pos")))
+}
+
+func (CowType) EncodeNBT(e world.Entity) map[string]any {
+	return encodeMobNBT(e.(*Mob))
+}
@@ -0,0 +1,159 @@
+package entity
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/sound"
+)
+
+// NewCreeper creates a new creeper at the position passed. Creepers are
+// hostile mobs that sneak up on players and explode once they get close.
+func NewCreeper(pos mgl64.Vec3) *Mob {
+	b := &CreeperBehaviour{}
+	b.MobBehaviour = MobBehaviourConfig{
+		Goals: []Goal{
+			SwimGoal{},
+			creeperSwellGoal{b: b},
+			&MeleeAttackGoal{Speed: 1},
+			WanderGoal{Speed: 0.8},
+			&LookAtPlayerGoal{Distance: 8},
+			&RandomLookGoal{},
+		},
+		TargetGoals: []Goal{
+			HurtByTargetGoal{},
+			NearestPlayerTargetGoal{Distance: 16},
+		},
+		Tick:  b.tick,
+		Drops: creeperDrops,
+	}.New()
+
+	conf := creeperConf
+	conf.Behaviour = b
+	return conf.New(CreeperType{}, pos)
+}
+
+var creeperConf = MobConfig{
+	MaxHealth:  20,
+	Speed:      0.125,
+	Experience: 5,
+	Category:   world.SpawnCategoryMonster,
+}
+
+// creeperFuse is the amount of ticks a creeper swells before it explodes.
+const creeperFuse = 30
+
+// CreeperBehaviour implements the behaviour of a creeper, which swells and
+// explodes when it gets close to its target.
+type CreeperBehaviour struct {
+	*MobBehaviour
+
+	fuse     int
+	swelling bool
+	ignited  bool
+	powered  bool
+}
+
+// Swelling checks if the creeper is currently swelling, meaning it is about to
+// explode.
+func (b *CreeperBehaviour) Swelling() bool {
+	return b.fuse > 0
+}
+
+// Powered checks if the creeper is powered. Powered creepers are created when
+// lightning strikes close to a creeper and have a bigger explosion.
+func (b *CreeperBehaviour) Powered() bool {
+	return b.powered
+}
+
+// SetPowered changes whether the creeper is powered.
+func (b *CreeperBehaviour) SetPowered(m *Mob, powered bool) {
+	b.powered = powered
+	m.updateState()
+}
+
+// Ignite ignites the creeper, making it swell and explode regardless of
+// whether it has a target.
+func (b *CreeperBehaviour) Ignite() {
+	b.ignited = true
+}
+
+// tick makes the creeper swell while it is close to its target or ignited,
+// exploding once its fuse runs out.
+func (b *CreeperBehaviour) tick(m *Mob) {
+	wasSwelling := b.Swelling()
+	if b.swelling || b.ignited {
+		if b.fuse == 0 {
+			m.World().PlaySound(m.Position(), sound.TNT{})
+		}
+		b.fuse++
+	} else if b.fuse > 0 {
+		b.fuse--
+	}
+	if b.fuse >= creeperFuse {
+		b.explode(m)
+		return
+	}
+	if wasSwelling != b.Swelling() {
+		m.updateState()
+	}
+}
+
+// explode makes the creeper explode, removing it from the world.
+func (b *CreeperBehaviour) explode(m *Mob) {
+	w, pos := m.World(), m.Position()
+	_ = m.Close()
+
+	conf := block.ExplosionConfig{Size: 3}
+	if b.powered {
+		conf.Size = 6
+	}
+	conf.Explode(w, pos)
+}
+
+// creeperDrops returns the items dropped by a creeper when it dies.
+func creeperDrops(*Mob) []item.Stack {
+	return []item.Stack{mobDrop(item.Gunpowder{}, 0, 2)}
+}
+
+// creeperSwellGoal makes a creeper swell when it is close to its target.
+type creeperSwellGoal struct {
+	b *CreeperBehaviour
+}
+
+func (creeperSwellGoal) Flags() GoalFlag { return GoalFlagMove }
+func (g creeperSwellGoal) CanStart(m *Mob) bool {
+	t := m.Target()
+	return g.b.fuse > 0 || (t != nil && m.Position().Sub(t.Position()).Len() < 3)
+}
+func (g creeperSwellGoal) CanContinue(m *Mob) bool { return g.CanStart(m) }
+func (creeperSwellGoal) Start(m *Mob)              { m.StopNavigating() }
+func (g creeperSwellGoal) Stop(*Mob)               { g.b.swelling = false }
+func (g creeperSwellGoal) Tick(m *Mob) {
+	t := m.Target()
+	g.b.swelling = t != nil && m.Position().Sub(t.Position()).Len() <= 7 && m.CanSee(t)
+}
+
+// CreeperType is a world.EntityType implementation for creepers.
+type CreeperType struct{}
+
+func (CreeperType) EncodeEntity() string { return "minecraft:creeper" }
+func (CreeperType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.3, 0, -0.3, 0.3, 1.7, 0.3)
+}
+
+func (CreeperType) DecodeNBT(m map[string]any) world.Entity {
+	c := decodeMobNBT(m, NewCreeper(nbtconv.Vec3(m, "Pos")))
+	c.Behaviour().(*CreeperBehaviour).powered = nbtconv.Bool(m, "powered")
+	return c
+}
+
+func (CreeperType) EncodeNBT(e world.Entity) map[string]any {
+	c := e.(*Mob)
+	data := encodeMobNBT(c)
+	data["powered"] = boolByte(c.Behaviour().(*CreeperBehaviour).powered)
+	return data
+}
@@ -44,6 +44,19 @@ type Ent struct {
 
 	fireDuration time.Duration
 	age          time.Duration
+
+	// ent is the world.Entity that embeds the Ent, such as a Mob. It is nil if
+	// the Ent is not embedded, in which case the Ent itself is the entity that
+	// is added to worlds and shown to viewers.
+	ent world.Entity
+}
+
+// entity returns the world.Entity that the Ent is part of.
+func (e *Ent) entity() world.Entity {
+	if e.ent != nil {
+		return e.ent
+	}
+	return e
 }
 
 // Explode propagates the explosion behaviour of the underlying Behaviour.
@@ -96,7 +109,7 @@ func (e *Ent) Rotation() cube.Rotation {
 
 // World returns the world of the entity.
 func (e *Ent) World() *world.World {
-	w, _ := world.OfEntity(e)
+	w, _ := world.OfEntity(e.entity())
 	return w
 }
 
@@ -130,7 +143,7 @@ func (e *Ent) SetOnFire(duration time.Duration) {
 		return
 	}
 	for _, v := range e.World().Viewers(pos) {
-		v.ViewEntityState(e)
+		v.ViewEntityState(e.entity())
 	}
 }
 
@@ -155,7 +168,7 @@ func (e *Ent) SetNameTag(s string) {
 	e.mu.Unlock()
 
 	for _, v := range e.World().Viewers(e.Position()) {
-		v.ViewEntityState(e)
+		v.ViewEntityState(e.entity())
 	}
 }
 
@@ -181,6 +194,6 @@ func (e *Ent) Tick(w *world.World, current int64) {
 
 // Close closes the Ent and removes the associated entity from the world.
 func (e *Ent) Close() error {
-	e.World().RemoveEntity(e)
+	e.World().RemoveEntity(e.entity())
 	return nil
 }
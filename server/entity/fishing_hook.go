@@ -0,0 +1,350 @@
+package entity
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/block/cube/trace"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/item/enchantment"
+	"github.com/stcraft/dragonfly/server/item/potion"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// FishingHook is the hook of a fishing rod, cast by an entity. Once it lands in water, fish bite after some
+// time, which may then be caught by reeling in the hook. A FishingHook that hits an entity hooks onto it, so
+// that the entity is pulled towards the owner of the hook once it is reeled in.
+type FishingHook struct {
+	mu    sync.Mutex
+	pos   mgl64.Vec3
+	vel   mgl64.Vec3
+	rot   cube.Rotation
+	age   time.Duration
+	owner world.Entity
+	rod   item.Stack
+
+	mc *MovementComputer
+
+	// hooked is the entity that the hook is attached to, if any.
+	hooked world.Entity
+	// wait is the number of ticks left until a fish bites. bite is the number of ticks left in which a fish
+	// that bit may be caught by reeling in the hook.
+	wait, bite int
+}
+
+// NewFishingHook creates a FishingHook cast by the owner passed using the fishing rod item stack passed.
+func NewFishingHook(pos mgl64.Vec3, owner world.Entity, rod item.Stack) *FishingHook {
+	return &FishingHook{pos: pos, owner: owner, rod: rod, mc: &MovementComputer{Gravity: 0.03, Drag: 0.08}}
+}
+
+// Type returns FishingHookType.
+func (h *FishingHook) Type() world.EntityType {
+	return FishingHookType{}
+}
+
+// Owner returns the entity that cast the FishingHook.
+func (h *FishingHook) Owner() world.Entity {
+	return h.owner
+}
+
+// Target returns the entity that the FishingHook is attached to. False is returned if the hook is not attached
+// to any entity.
+func (h *FishingHook) Target() (world.Entity, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.hooked, h.hooked != nil
+}
+
+// Position returns the current position of the FishingHook.
+func (h *FishingHook) Position() mgl64.Vec3 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.pos
+}
+
+// Velocity returns the current velocity of the FishingHook.
+func (h *FishingHook) Velocity() mgl64.Vec3 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.vel
+}
+
+// Rotation returns the rotation of the FishingHook.
+func (h *FishingHook) Rotation() cube.Rotation {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.rot
+}
+
+// World returns the world that the FishingHook is in.
+func (h *FishingHook) World() *world.World {
+	w, _ := world.OfEntity(h)
+	return w
+}
+
+// Tick moves the FishingHook and makes fish bite once it floats in water. The hook is removed if its owner
+// moved too far away or is no longer holding a fishing rod.
+func (h *FishingHook) Tick(w *world.World, _ int64) {
+	if !h.ownerHolding(w) {
+		_ = h.Close()
+		return
+	}
+
+	h.mu.Lock()
+	h.age += time.Second / 20
+	pos, vel, rot, hooked := h.pos, h.vel, h.rot, h.hooked
+	h.mu.Unlock()
+
+	if hooked != nil {
+		if hooked.World() == w && !fishingHookTargetDead(hooked) {
+			// The hook is attached to an entity, so it simply follows that entity around.
+			target := hooked.Position().Add(mgl64.Vec3{0, hooked.Type().BBox(hooked).Height() * 0.8})
+			h.move(&Movement{v: w.Viewers(pos), e: h, pos: target, dpos: target.Sub(pos), dvel: vel.Mul(-1), rot: rot})
+			return
+		}
+		h.mu.Lock()
+		h.hooked = nil
+		h.mu.Unlock()
+	}
+
+	if surface, ok := waterSurface(w, pos); ok && surface > pos[1]-0.1 {
+		// The hook floats on the surface of the water, bobbing up and down.
+		h.mc.Gravity = 0
+		vel[0], vel[2] = vel[0]*0.9, vel[2]*0.9
+		vel[1] = (vel[1] + math.Min(surface-pos[1]-0.1, 0.3)*0.1) * 0.8
+		vel = h.tickFish(w, pos, vel)
+	} else {
+		h.mc.Gravity = 0.03
+	}
+
+	m := h.mc.TickMovement(h, pos, vel, rot)
+	if !h.mc.OnGround() {
+		if target, ok := h.traceEntity(w, pos, m.pos); ok {
+			h.mu.Lock()
+			h.hooked = target
+			h.mu.Unlock()
+			for _, v := range w.Viewers(pos) {
+				v.ViewEntityState(h)
+			}
+		}
+	}
+	h.move(m)
+}
+
+// move sends the Movement passed to viewers and updates the position and velocity of the FishingHook.
+func (h *FishingHook) move(m *Movement) {
+	m.Send()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pos, h.vel, h.rot = m.pos, m.vel, m.rot
+}
+
+// tickFish ticks the fish approaching the FishingHook floating in water. The velocity passed is returned, with
+// the hook being pulled under water if a fish bites.
+func (h *FishingHook) tickFish(w *world.World, pos, vel mgl64.Vec3) mgl64.Vec3 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch {
+	case h.bite > 0:
+		if h.bite--; h.bite == 0 {
+			// The fish got away.
+			h.wait = h.waitTicks()
+		}
+	case h.wait > 0:
+		if h.wait--; h.wait == 0 {
+			h.bite = 10 + rand.Intn(10)
+			vel[1] -= 0.2
+			for _, v := range w.Viewers(pos) {
+				v.ViewEntityAction(h, FishingHookBiteAction{})
+			}
+		}
+	default:
+		h.wait = h.waitTicks()
+	}
+	return vel
+}
+
+// waitTicks returns a random number of ticks to wait until a fish bites. The number is reduced if the fishing
+// rod is enchanted with lure.
+func (h *FishingHook) waitTicks() int {
+	ticks := 100 + rand.Intn(500)
+	if l, ok := h.rod.Enchantment(enchantment.Lure{}); ok {
+		ticks -= int((enchantment.Lure{}).WaitReduction(l.Level()) / (time.Second / 20))
+	}
+	return max(ticks, 20)
+}
+
+// traceEntity finds the first entity that the FishingHook hits when moving from start to end. The owner of the
+// hook is ignored during the first ticks after casting it.
+func (h *FishingHook) traceEntity(w *world.World, start, end mgl64.Vec3) (world.Entity, bool) {
+	box := h.Type().BBox(h).Translate(start).Extend(end.Sub(start)).Grow(1)
+
+	var (
+		hit  world.Entity
+		dist = math.MaxFloat64
+	)
+	for _, e := range w.EntitiesWithin(box, h.ignores) {
+		if r, ok := trace.EntityIntercept(e, start, end); ok {
+			if d := r.Position().Sub(start).LenSqr(); d < dist {
+				hit, dist = e, d
+			}
+		}
+	}
+	return hit, hit != nil
+}
+
+// ignores checks if the FishingHook should not be able to hook onto the entity passed.
+func (h *FishingHook) ignores(e world.Entity) bool {
+	g, ok := e.(interface{ GameMode() world.GameMode })
+	_, living := e.(Living)
+	return (ok && !g.GameMode().HasCollision()) || e == h || !living || (h.age < time.Second/4 && e == h.owner)
+}
+
+// ownerHolding checks if the owner of the FishingHook is still in the world passed, within range of the hook
+// and holding a fishing rod in its main hand.
+func (h *FishingHook) ownerHolding(w *world.World) bool {
+	if h.owner == nil || h.owner.World() != w || fishingHookTargetDead(h.owner) {
+		return false
+	}
+	if h.owner.Position().Sub(h.Position()).Len() > fishingHookRange {
+		return false
+	}
+	held, ok := h.owner.(interface {
+		HeldItems() (mainHand, offHand item.Stack)
+	})
+	if !ok {
+		return false
+	}
+	main, _ := held.HeldItems()
+	_, ok = main.Item().(item.FishingRod)
+	return ok
+}
+
+// fishingHookRange is the maximum distance between a FishingHook and its owner before the hook is removed.
+const fishingHookRange = 32
+
+// Reel reels in the FishingHook. An entity hooked is pulled towards the owner and a fish that bit is caught.
+// The hook is removed, and the damage that should be dealt to the fishing rod is returned.
+func (h *FishingHook) Reel() int {
+	defer h.Close()
+
+	w, pos := h.World(), h.Position()
+	if w == nil || h.owner == nil {
+		return 0
+	}
+	h.mu.Lock()
+	hooked, bite, onGround := h.hooked, h.bite, h.mc.OnGround()
+	h.mu.Unlock()
+
+	switch {
+	case hooked != nil:
+		if v, ok := hooked.(interface {
+			Velocity() mgl64.Vec3
+			SetVelocity(vel mgl64.Vec3)
+		}); ok {
+			v.SetVelocity(v.Velocity().Add(h.owner.Position().Sub(hooked.Position()).Mul(0.1)))
+		}
+		return 5
+	case bite > 0:
+		diff := h.owner.Position().Sub(pos)
+		it := NewItem(h.loot(), pos)
+		it.SetVelocity(mgl64.Vec3{diff[0] * 0.1, diff[1]*0.1 + math.Sqrt(diff.Len())*0.08, diff[2] * 0.1})
+		w.AddEntity(it)
+		for _, orb := range NewExperienceOrbs(h.owner.Position(), rand.Intn(6)+1) {
+			w.AddEntity(orb)
+		}
+		return 1
+	case onGround:
+		return 2
+	}
+	return 0
+}
+
+// loot returns a random item stack caught by the FishingHook. The chance of catching treasure increases with
+// the level of the luck of the sea enchantment on the fishing rod.
+func (h *FishingHook) loot() item.Stack {
+	luck := 0
+	if l, ok := h.rod.Enchantment(enchantment.LuckOfTheSea{}); ok {
+		luck = l.Level()
+	}
+	treasure, junk := 5+luck*2, 10-luck*2
+	switch n := rand.Intn(100); {
+	case n < treasure:
+		return fishingTreasure()
+	case n < treasure+junk:
+		return fishingJunk()
+	}
+	return fishingFish()
+}
+
+// fishingFish returns a random fish caught using a FishingHook.
+func fishingFish() item.Stack {
+	switch n := rand.Intn(100); {
+	case n < 60:
+		return item.NewStack(item.Cod{}, 1)
+	case n < 85:
+		return item.NewStack(item.Salmon{}, 1)
+	case n < 87:
+		return item.NewStack(item.TropicalFish{}, 1)
+	}
+	return item.NewStack(item.Pufferfish{}, 1)
+}
+
+// fishingJunk returns a random junk item caught using a FishingHook.
+func fishingJunk() item.Stack {
+	junk := []item.Stack{
+		item.NewStack(item.Boots{Tier: item.ArmourTierLeather{}}, 1).Damage(rand.Intn(50)),
+		item.NewStack(item.Leather{}, 1),
+		item.NewStack(item.Bone{}, 1),
+		item.NewStack(item.Potion{Type: potion.Water()}, 1),
+		item.NewStack(item.Bowl{}, 1),
+		item.NewStack(item.Stick{}, 1),
+		item.NewStack(item.InkSac{}, 10),
+		item.NewStack(item.RottenFlesh{}, 1),
+		item.NewStack(item.FishingRod{}, 1).Damage(rand.Intn(300)),
+	}
+	return junk[rand.Intn(len(junk))]
+}
+
+// fishingTreasure returns a random treasure item caught using a FishingHook.
+func fishingTreasure() item.Stack {
+	switch rand.Intn(4) {
+	case 0:
+		return item.NewStack(item.Bow{}, 1).Damage(rand.Intn(300))
+	case 1:
+		return item.NewStack(item.FishingRod{}, 1).Damage(rand.Intn(300))
+	case 2:
+		return item.NewStack(item.NautilusShell{}, 1)
+	}
+	enchants := item.Enchantments()
+	t := enchants[rand.Intn(len(enchants))]
+	return item.NewStack(item.EnchantedBook{}, 1).WithEnchantments(item.NewEnchantment(t, rand.Intn(t.MaxLevel())+1))
+}
+
+// fishingHookTargetDead checks if the entity passed is a Living entity that is dead.
+func fishingHookTargetDead(e world.Entity) bool {
+	l, ok := e.(Living)
+	return ok && l.Dead()
+}
+
+// Close removes the FishingHook from its world.
+func (h *FishingHook) Close() error {
+	if w := h.World(); w != nil {
+		w.RemoveEntity(h)
+	}
+	return nil
+}
+
+// FishingHookType is a world.EntityType implementation for FishingHook.
+type FishingHookType struct{}
+
+func (FishingHookType) EncodeEntity() string { return "minecraft:fishing_hook" }
+func (FishingHookType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.125, 0, -0.125, 0.125, 0.25, 0.125)
+}
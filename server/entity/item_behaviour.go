@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/block/cube"
 	"github.com/stcraft/dragonfly/server/internal/nbtconv"
 	"github.com/stcraft/dragonfly/server/item"
 	"github.com/stcraft/dragonfly/server/world"
@@ -71,6 +73,9 @@ func (i *ItemBehaviour) Tick(e *Ent) *Movement {
 
 // tick checks if the item can be picked up or merged with nearby item stacks.
 func (i *ItemBehaviour) tick(e *Ent) {
+	if i.checkHopper(e) {
+		return
+	}
 	if i.pickupDelay == 0 {
 		i.checkNearby(e)
 	} else if i.pickupDelay < math.MaxInt16*(time.Second/20) {
@@ -106,6 +111,25 @@ func (i *ItemBehaviour) checkNearby(e *Ent) {
 	}
 }
 
+// checkHopper checks if the item entity is lying on top of a hopper. If so, the hopper collects as much of the
+// item as possible. True is returned if the item entity was (partially) collected.
+func (i *ItemBehaviour) checkHopper(e *Ent) bool {
+	w, pos := e.World(), e.Position()
+	h, ok := w.Block(cube.PosFromVec3(pos).Side(cube.FaceDown)).(block.Hopper)
+	if !ok {
+		return false
+	}
+	n := h.CollectItem(i.i)
+	if n == 0 {
+		return false
+	}
+	if n < i.i.Count() {
+		w.AddEntity(NewItem(i.i.Grow(-n), pos))
+	}
+	_ = e.Close()
+	return true
+}
+
 // merge merges the item entity with another item entity.
 func (i *ItemBehaviour) merge(e *Ent, other *Ent) bool {
 	w, pos := e.World(), e.Position()
@@ -0,0 +1,465 @@
+package entity
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/item/inventory"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// Minecart is a Driveable entity that travels along rails. Regular minecarts may be ridden by a single entity,
+// while other types of minecarts carry a chest, a hopper or TNT.
+type Minecart struct {
+	vehicle
+	kind item.MinecartType
+	inv  *inventory.Inventory
+
+	rail   cube.Pos
+	onRail bool
+	// forward is the forward input of the driver of the minecart, which is applied and reset when the
+	// minecart is ticked.
+	forward float64
+	// fuse is the time left until a TNT minecart explodes. It is negative if the minecart was not ignited.
+	fuse time.Duration
+	// disabled is true if a hopper minecart passed over a powered activator rail, preventing it from
+	// collecting items.
+	disabled bool
+}
+
+// NewMinecart creates a new Minecart of the type passed.
+func NewMinecart(pos mgl64.Vec3, kind item.MinecartType) *Minecart {
+	m := &Minecart{
+		vehicle: vehicle{seats: newSeats(0), pos: pos, mc: &MovementComputer{Gravity: 0.04, Drag: 0.05}},
+		kind:    kind,
+		fuse:    -1,
+	}
+	switch kind {
+	case item.RegularMinecart():
+		m.seats = newSeats(1)
+	case item.ChestMinecart():
+		m.inv = inventory.New(27, nil)
+	case item.HopperMinecart():
+		m.inv = inventory.New(5, nil)
+	}
+	return m
+}
+
+const (
+	// minecartMaxSpeed is the maximum distance in blocks that a minecart travels along rails every tick.
+	minecartMaxSpeed = 0.4
+	// minecartSlopeSpeed is the speed that a minecart gains every tick when travelling down a slope.
+	minecartSlopeSpeed = 0.0078125
+	// minecartBoost is the speed that a minecart gains every tick when travelling over a powered rail.
+	minecartBoost = 0.06
+)
+
+// Type returns the world.EntityType of the Minecart, which depends on what it carries.
+func (m *Minecart) Type() world.EntityType {
+	return MinecartType{kind: m.kind}
+}
+
+// Kind returns the type of the Minecart.
+func (m *Minecart) Kind() item.MinecartType {
+	return m.kind
+}
+
+// Inventory returns the inventory of a chest or hopper minecart. For other minecarts, nil is returned.
+func (m *Minecart) Inventory() *inventory.Inventory {
+	return m.inv
+}
+
+// ContainerOpener represents an entity that is able to open the container carried by another entity, such as
+// the inventory of a chest minecart.
+type ContainerOpener interface {
+	// OpenEntityContainer opens the container carried by the entity passed.
+	OpenEntityContainer(e world.Entity)
+}
+
+// Interact opens the inventory of a chest or hopper minecart if the user passed is able to open it. True is
+// returned if the inventory was opened.
+func (m *Minecart) Interact(user item.User) bool {
+	opener, ok := user.(ContainerOpener)
+	if !ok || m.inv == nil {
+		return false
+	}
+	opener.OpenEntityContainer(m)
+	return true
+}
+
+// World returns the world that the Minecart is in.
+func (m *Minecart) World() *world.World {
+	w, _ := world.OfEntity(m)
+	return w
+}
+
+// Teleport teleports the Minecart to the position passed.
+func (m *Minecart) Teleport(pos mgl64.Vec3) {
+	m.teleport(m, pos)
+}
+
+// Rail returns the position of the rail that the Minecart is currently travelling along. If the Minecart is not
+// on a rail, false is returned.
+func (m *Minecart) Rail() (cube.Pos, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.rail, m.onRail
+}
+
+// SeatOffset returns the offset of the seat of the Minecart.
+func (m *Minecart) SeatOffset(world.Entity) mgl64.Vec3 {
+	return mgl64.Vec3{0, 1.02}
+}
+
+// Drive stores the input of the driver of the Minecart. Drivers can only push their minecart forward while it is
+// (almost) standing still. The input is applied the next time the Minecart is ticked.
+func (m *Minecart) Drive(driver world.Entity, forward, _, _ float64) {
+	if d, ok := m.driver(); !ok || d != driver {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.forward = forward
+}
+
+// Fuse returns the time left until a TNT minecart explodes. If the Minecart was not ignited, 0 is returned.
+func (m *Minecart) Fuse() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return max(m.fuse, 0)
+}
+
+// Ignited checks if the Minecart is a TNT minecart that was ignited.
+func (m *Minecart) Ignited() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.fuse >= 0
+}
+
+// Ignite ignites a TNT minecart, making it explode after the fuse passed. Ignite does nothing if the Minecart is
+// not a TNT minecart or if it was already ignited.
+func (m *Minecart) Ignite(fuse time.Duration) {
+	if m.kind != item.TNTMinecart() || m.Ignited() {
+		return
+	}
+	m.mu.Lock()
+	m.fuse = fuse
+	m.mu.Unlock()
+	for _, v := range m.World().Viewers(m.Position()) {
+		v.ViewEntityState(m)
+	}
+}
+
+// Collect collects the item stack passed into the inventory of a hopper minecart. Other minecarts and hopper
+// minecarts that were disabled by an activator rail do not collect items.
+func (m *Minecart) Collect(stack item.Stack) int {
+	m.mu.Lock()
+	disabled := m.disabled
+	m.mu.Unlock()
+	if m.kind != item.HopperMinecart() || disabled {
+		return 0
+	}
+	n, _ := m.inv.AddItem(stack)
+	return n
+}
+
+// Hurt damages the Minecart. The Minecart breaks and drops itself and its contents once it has taken enough
+// damage.
+func (m *Minecart) Hurt(dmg float64, src world.DamageSource) (float64, bool) {
+	broken, drops := m.hurt(m, dmg, src)
+	if !broken {
+		return dmg, true
+	}
+	if !drops {
+		m.destroy(m)
+		return dmg, true
+	}
+	stacks := []item.Stack{item.NewStack(item.Minecart{Type: m.kind}, 1)}
+	if m.inv != nil {
+		stacks = append(stacks, m.inv.Clear()...)
+	}
+	m.destroy(m, stacks...)
+	return dmg, true
+}
+
+// Explode ignites a TNT minecart with a short random fuse. Other minecarts are damaged by the explosion.
+func (m *Minecart) Explode(src mgl64.Vec3, impact float64, conf block.ExplosionConfig) {
+	if m.kind == item.TNTMinecart() {
+		m.Ignite(time.Duration(rand.Intn(10)+10) * time.Second / 20)
+		return
+	}
+	m.Hurt(math.Floor((impact*impact+impact)*3.5*conf.Size+1), ExplosionDamageSource{})
+}
+
+// Tick moves the Minecart along the rail that it is on, or makes it fall and slide if it is not on a rail.
+func (m *Minecart) Tick(w *world.World, _ int64) {
+	m.dismountRemoved(w)
+	m.tickDamage()
+
+	m.mu.Lock()
+	if m.fuse >= 0 {
+		if m.fuse -= time.Second / 20; m.fuse <= 0 {
+			m.mu.Unlock()
+			m.destroy(m)
+			block.ExplosionConfig{}.Explode(w, m.Position())
+			return
+		}
+	}
+	pos, vel, rot := m.pos, m.vel, m.rot
+	m.mu.Unlock()
+
+	var mov *Movement
+	railPos, rail, ok := minecartRail(w, pos)
+	if ok {
+		mov = m.moveAlongRail(w, railPos, rail, pos, vel, rot)
+	} else {
+		mov = m.mc.TickMovement(m, pos, vel, rot)
+	}
+	mov.Send()
+
+	m.mu.Lock()
+	m.pos, m.vel, m.rot = mov.pos, mov.vel, mov.rot
+	m.rail, m.onRail = railPos, ok
+	m.forward = 0
+	m.mu.Unlock()
+
+	if !ok {
+		return
+	}
+	if insider, ok := rail.(block.EntityInsider); ok {
+		insider.EntityInside(railPos, w, m)
+	}
+	if r, ok := rail.(block.ActivatorRail); ok {
+		m.activate(r.Powered)
+	}
+}
+
+// activate handles the Minecart passing over an activator rail, which is powered if the bool passed is true.
+func (m *Minecart) activate(powered bool) {
+	switch m.kind {
+	case item.RegularMinecart():
+		if powered {
+			m.dismountAll()
+		}
+	case item.HopperMinecart():
+		m.mu.Lock()
+		m.disabled = powered
+		m.mu.Unlock()
+	case item.TNTMinecart():
+		if powered {
+			m.Ignite(time.Second * 4)
+		}
+	}
+}
+
+// moveAlongRail moves the Minecart along the rail passed at the position passed. The Minecart follows the shape
+// of the rail, accelerating down slopes and over powered rails.
+func (m *Minecart) moveAlongRail(w *world.World, railPos cube.Pos, rail block.MinecartRail, pos, vel mgl64.Vec3, rot cube.Rotation) *Movement {
+	shape := rail.RailShape()
+	dirs := shape.Directions()
+	up, ascending := shape.Ascending()
+	if ascending {
+		vel = vel.Sub(directionVec3(up).Mul(minecartSlopeSpeed))
+	}
+
+	// Redirect the velocity of the minecart along the rail, keeping the speed it had.
+	a, b := directionVec3(dirs[0]), directionVec3(dirs[1])
+	along := b.Sub(a).Normalize()
+	if vel.Dot(along) < 0 {
+		along = along.Mul(-1)
+	}
+	speed := math.Min(2, math.Hypot(vel[0], vel[2]))
+	vel = mgl64.Vec3{along[0] * speed, 0, along[2] * speed}
+
+	m.mu.Lock()
+	forward := m.forward
+	m.mu.Unlock()
+	driver, hasDriver := m.driver()
+	if hasDriver && forward > 0 && vel[0]*vel[0]+vel[2]*vel[2] < 0.01 {
+		vel = vel.Add(yawDirection(driver.Rotation().Yaw()).Mul(0.1))
+	}
+
+	powered := false
+	if r, ok := rail.(block.PoweredRail); ok {
+		if powered = r.Powered; !powered {
+			// Unpowered powered rails brake minecarts travelling along them.
+			if speed < 0.03 {
+				vel = mgl64.Vec3{}
+			} else {
+				vel = vel.Mul(0.5)
+			}
+		}
+	}
+
+	// Snap the minecart onto the line that runs between the two ends of the rail.
+	centre := railPos.Vec3Middle()
+	start, end := centre.Add(a.Mul(0.5)), centre.Add(b.Mul(0.5))
+	line := end.Sub(start)
+	t := ((pos[0]-start[0])*line[0] + (pos[2]-start[2])*line[2]) / (line[0]*line[0] + line[2]*line[2])
+	newPos := mgl64.Vec3{start[0] + line[0]*t, pos[1], start[2] + line[2]*t}
+
+	motion := vel
+	if hasDriver {
+		motion = motion.Mul(0.75)
+	}
+	motion[0] = mgl64.Clamp(motion[0], -minecartMaxSpeed, minecartMaxSpeed)
+	motion[2] = mgl64.Clamp(motion[2], -minecartMaxSpeed, minecartMaxSpeed)
+	newPos = newPos.Add(motion)
+	if minecartBlocked(w, railPos, dirs, newPos) {
+		// The rail ends in front of a block: Stop the minecart at the end of the rail.
+		newPos, vel = centre, mgl64.Vec3{}
+	}
+	newPos[1] = minecartRailHeight(railPos, shape, newPos)
+
+	if hasDriver {
+		vel = vel.Mul(0.997)
+	} else {
+		vel = vel.Mul(0.96)
+	}
+	if powered {
+		if s := math.Hypot(vel[0], vel[2]); s > 0.01 {
+			vel = vel.Add(vel.Mul(minecartBoost / s))
+		} else if !ascending && !shape.Curved() {
+			// Minecarts standing still on a powered rail are pushed away from a solid block at one of its ends.
+			if minecartSolid(w, railPos.Side(dirs[0].Face())) {
+				vel = b.Mul(0.02)
+			} else if minecartSolid(w, railPos.Side(dirs[1].Face())) {
+				vel = a.Mul(0.02)
+			}
+		}
+	}
+	if vel[0] != 0 || vel[2] != 0 {
+		rot[0] = mgl64.RadToDeg(math.Atan2(-vel[0], vel[2]))
+	}
+
+	velBefore := m.Velocity()
+	return &Movement{v: w.Viewers(pos), e: m,
+		pos: newPos, vel: vel, dpos: newPos.Sub(pos), dvel: vel.Sub(velBefore),
+		rot: rot, onGround: true,
+	}
+}
+
+// minecartRail returns the rail that a minecart at the position passed is travelling along. Minecarts travelling
+// down a slope may be positioned in the block above the rail.
+func minecartRail(w *world.World, pos mgl64.Vec3) (cube.Pos, block.MinecartRail, bool) {
+	p := cube.PosFromVec3(pos)
+	if r, ok := w.Block(p).(block.MinecartRail); ok {
+		return p, r, true
+	}
+	p = p.Side(cube.FaceDown)
+	if r, ok := w.Block(p).(block.MinecartRail); ok {
+		return p, r, true
+	}
+	return p, nil, false
+}
+
+// minecartRailHeight returns the height of a minecart at the horizontal position passed on a rail with the shape
+// passed at railPos.
+func minecartRailHeight(railPos cube.Pos, shape block.RailShape, pos mgl64.Vec3) float64 {
+	y := float64(railPos[1]) + 0.0625
+	up, ok := shape.Ascending()
+	if !ok {
+		return y
+	}
+	var t float64
+	switch up {
+	case cube.East:
+		t = pos[0] - float64(railPos[0])
+	case cube.West:
+		t = float64(railPos[0]) + 1 - pos[0]
+	case cube.South:
+		t = pos[2] - float64(railPos[2])
+	case cube.North:
+		t = float64(railPos[2]) + 1 - pos[2]
+	}
+	return y + mgl64.Clamp(t, 0, 1)
+}
+
+// minecartBlocked checks if a minecart at the position passed is moving past one of the ends of the rail at
+// railPos into a solid block without a rail to continue on.
+func minecartBlocked(w *world.World, railPos cube.Pos, dirs [2]cube.Direction, pos mgl64.Vec3) bool {
+	for _, d := range dirs {
+		side := railPos.Side(d.Face())
+		if cube.PosFromVec3(mgl64.Vec3{pos[0], float64(railPos[1]), pos[2]}) != side {
+			continue
+		}
+		for _, p := range [...]cube.Pos{side, side.Side(cube.FaceUp), side.Side(cube.FaceDown)} {
+			if _, ok := w.Block(p).(block.MinecartRail); ok {
+				return false
+			}
+		}
+		return minecartSolid(w, side)
+	}
+	return false
+}
+
+// minecartSolid checks if the block at the position passed has a solid top face, blocking minecarts.
+func minecartSolid(w *world.World, pos cube.Pos) bool {
+	return w.Block(pos).Model().FaceSolid(pos, cube.FaceUp, w)
+}
+
+// directionVec3 returns a unit vector pointing in the horizontal direction passed.
+func directionVec3(d cube.Direction) mgl64.Vec3 {
+	switch d {
+	case cube.North:
+		return mgl64.Vec3{0, 0, -1}
+	case cube.South:
+		return mgl64.Vec3{0, 0, 1}
+	case cube.West:
+		return mgl64.Vec3{-1, 0, 0}
+	}
+	return mgl64.Vec3{1, 0, 0}
+}
+
+// Close removes the Minecart from its world.
+func (m *Minecart) Close() error {
+	m.dismountAll()
+	if w := m.World(); w != nil {
+		w.RemoveEntity(m)
+	}
+	return nil
+}
+
+// MinecartType is a world.EntityType implementation for Minecart. The type depends on what the Minecart carries.
+type MinecartType struct {
+	kind item.MinecartType
+}
+
+func (t MinecartType) EncodeEntity() string { return "minecraft:" + t.kind.String() }
+func (MinecartType) NetworkOffset() float64 { return 0.35 }
+func (MinecartType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.49, 0, -0.49, 0.49, 0.7, 0.49)
+}
+
+func (t MinecartType) DecodeNBT(m map[string]any) world.Entity {
+	c := NewMinecart(nbtconv.Vec3(m, "Pos"), t.kind)
+	c.vel = nbtconv.Vec3(m, "Motion")
+	if c.inv != nil {
+		nbtconv.InvFromNBT(c.inv, nbtconv.Slice(m, "Items"))
+	}
+	if _, ok := m["Fuse"]; ok {
+		c.fuse = nbtconv.TickDuration[uint8](m, "Fuse")
+	}
+	return c
+}
+
+func (t MinecartType) EncodeNBT(e world.Entity) map[string]any {
+	c := e.(*Minecart)
+	data := map[string]any{
+		"Pos":    nbtconv.Vec3ToFloat32Slice(c.Position()),
+		"Motion": nbtconv.Vec3ToFloat32Slice(c.Velocity()),
+	}
+	if c.inv != nil {
+		data["Items"] = nbtconv.InvToNBT(c.inv)
+	}
+	if c.Ignited() {
+		data["Fuse"] = uint8(c.Fuse().Milliseconds() / 50)
+	}
+	return data
+}
@@ -0,0 +1,1070 @@
+package entity
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/block/cube/trace"
+	"github.com/stcraft/dragonfly/server/entity/effect"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/item/enchantment"
+	"github.com/stcraft/dragonfly/server/item/inventory"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/sound"
+)
+
+// MobBehaviourConfig holds optional parameters for a MobBehaviour.
+type MobBehaviourConfig struct {
+	// Goals are the goals pursued by the mob, ordered from high to low
+	// priority.
+	Goals []Goal
+	// TargetGoals are the goals used to select the target of the mob, ordered
+	// from high to low priority.
+	TargetGoals []Goal
+	// NoFallDamage specifies if the mob is immune to fall damage.
+	NoFallDamage bool
+	// Path holds parameters used to find a path when the mob navigates
+	// through the world. If Path.Height is 0, the height of the mob is used.
+	Path PathConfig
+	// Tick is called every tick that the mob is alive, after its goals and
+	// movement have been ticked.
+	Tick func(m *Mob)
+	// Drops returns the items that the mob drops when it dies.
+	Drops func(m *Mob) []item.Stack
+	// Interact is called when a user interacts with the mob without using its
+	// held item on it. It returns false if nothing happened.
+	Interact func(m *Mob, user item.User) bool
+}
+
+// New creates a MobBehaviour using the parameters in conf.
+func (conf MobBehaviourConfig) New() *MobBehaviour {
+	return &MobBehaviour{
+		conf:        conf,
+		mc:          &MovementComputer{Gravity: 0.08, Drag: 0.02},
+		goals:       NewGoalSelector(conf.Goals...),
+		targetGoals: NewGoalSelector(conf.TargetGoals...),
+	}
+}
+
+// MobBehaviour implements Behaviour for AI controlled mobs. Every tick, it
+// runs the goals of the mob, makes it follow the path it is navigating and
+// moves it using a MovementComputer. A MobBehaviour can only be used by a Mob,
+// either directly or embedded in a Behaviour that holds state specific to a
+// type of mob, such as SheepBehaviour.
+type MobBehaviour struct {
+	conf MobBehaviourConfig
+	mc   *MovementComputer
+
+	fallDistance float64
+	deathTicks   int
+	// portalCooldown is the amount of ticks left before the mob may travel
+	// through a portal again.
+	portalCooldown int
+
+	target Living
+
+	moving    bool
+	moveTo    mgl64.Vec3
+	moveSpeed float64
+	jumping   bool
+	collided  bool
+
+	path       *Path
+	pathSpeed  float64
+	stuckTicks int
+	lastPos    mgl64.Vec3
+
+	goals, targetGoals *GoalSelector
+}
+
+// mobBehaviour returns the MobBehaviour itself. It is promoted to the
+// behaviours that embed a *MobBehaviour, so that MobConfig.New can find it.
+func (b *MobBehaviour) mobBehaviour() *MobBehaviour {
+	return b
+}
+
+// Goals returns the GoalSelector that schedules the goals of the mob.
+func (b *MobBehaviour) Goals() *GoalSelector {
+	return b.goals
+}
+
+// TargetGoals returns the GoalSelector that schedules the goals used to
+// select the target of the mob.
+func (b *MobBehaviour) TargetGoals() *GoalSelector {
+	return b.targetGoals
+}
+
+// Explode hurts the mob and knocks it away from the position of an explosion.
+func (b *MobBehaviour) Explode(e *Ent, src mgl64.Vec3, impact float64, conf block.ExplosionConfig) {
+	m := e.ent.(*Mob)
+	diff := m.Position().Sub(src)
+	m.Hurt(math.Floor((impact*impact+impact)*3.5*conf.Size+1), ExplosionDamageSource{})
+	m.KnockBack(src, impact, diff[1]/diff.Len()*impact)
+}
+
+// Tick runs the goals of the mob, moves it and handles damage from the
+// environment.
+func (b *MobBehaviour) Tick(e *Ent) *Movement {
+	m := e.ent.(*Mob)
+	if m.Dead() {
+		b.tickDeath(m)
+		return nil
+	}
+	w := e.World()
+	b.tickFire(m, w)
+	m.effects.Tick(m)
+
+	e.mu.Lock()
+	if m.immunity > 0 {
+		m.immunity -= time.Second / 20
+	}
+	if b.portalCooldown > 0 {
+		b.portalCooldown--
+	}
+	if b.target != nil && (b.target.Dead() || b.target.World() != w) {
+		b.target = nil
+	}
+	e.mu.Unlock()
+
+	if m.Dead() {
+		return nil
+	}
+	b.targetGoals.Tick(m)
+	b.goals.Tick(m)
+	b.tickNavigation(m, w)
+
+	mov := b.tickMovement(m, w)
+	// The mob may have left the world while moving, for example by entering a
+	// portal to another world.
+	if b.conf.Tick != nil && m.World() == w {
+		b.conf.Tick(m)
+	}
+	return mov
+}
+
+// tickDeath progresses the death animation of the mob, removing it once the
+// animation is finished.
+func (b *MobBehaviour) tickDeath(m *Mob) {
+	m.mu.Lock()
+	died := b.deathTicks == 0
+	b.deathTicks++
+	remove := b.deathTicks >= 20
+	m.mu.Unlock()
+	if died {
+		// The goals of the mob are stopped here rather than when it is killed,
+		// so that they are only ever accessed while ticking.
+		b.goals.Stop(m)
+		b.targetGoals.Stop(m)
+	}
+	if remove {
+		_ = m.Close()
+	}
+}
+
+// tickFire handles fire damage and extinguishing of the mob. The fire
+// duration itself is decreased by the Ent.
+func (b *MobBehaviour) tickFire(m *Mob, w *world.World) {
+	d := m.OnFireDuration()
+	if d <= 0 {
+		return
+	}
+	feet := cube.PosFromVec3(m.Position())
+	if _, ok := w.Liquid(feet); ok || w.RainingAt(feet) {
+		m.Extinguish()
+		return
+	}
+	if d%time.Second == 0 {
+		m.Hurt(1, block.FireDamageSource{})
+	}
+}
+
+// tickNavigation makes the mob follow its current path, if it has one.
+func (b *MobBehaviour) tickNavigation(m *Mob, w *world.World) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if b.path == nil {
+		return
+	}
+	next, ok := b.path.Current()
+	for ok {
+		centre := next.Vec3Middle()
+		if math.Hypot(centre[0]-m.pos[0], centre[2]-m.pos[2]) > 0.35 || math.Abs(float64(next[1])-m.pos[1]) >= 1 {
+			break
+		}
+		b.path.Advance()
+		next, ok = b.path.Current()
+	}
+	if !ok {
+		b.path = nil
+		return
+	}
+	if m.pos.Sub(b.lastPos).Len() < 0.01 {
+		if b.stuckTicks++; b.stuckTicks > 60 {
+			// The mob has not been able to make progress for a while: It is
+			// most likely stuck, so we stop following the path.
+			b.path = nil
+			return
+		}
+	} else {
+		b.stuckTicks = 0
+	}
+	b.lastPos = m.pos
+
+	if b.conf.Path.OpenDoors {
+		for i := 0; i < b.conf.Path.Height; i++ {
+			doorPos := next.Add(cube.Pos{0, i})
+			if door, ok := w.Block(doorPos).(block.WoodDoor); ok && !door.Open && m.pos.Sub(doorPos.Vec3Middle()).Len() < 2 {
+				door.Activate(doorPos, cube.FaceUp, w, nil, nil)
+				break
+			}
+		}
+	}
+	b.moving, b.moveTo, b.moveSpeed = true, next.Vec3Middle(), b.pathSpeed
+	if float64(next[1]) > m.pos[1]+0.5 || b.collided {
+		b.jumping = true
+	}
+}
+
+// tickMovement moves the mob according to its velocity and the movement and
+// jumping requested by its goals.
+func (b *MobBehaviour) tickMovement(m *Mob, w *world.World) *Movement {
+	m.mu.Lock()
+	_, inLiquid := w.Liquid(cube.PosFromVec3(m.pos))
+	if b.moving {
+		b.applyMovement(m, w)
+	}
+	if inLiquid {
+		m.vel = m.vel.Mul(0.8)
+		m.vel[1] += 0.06
+		if b.jumping {
+			m.vel[1] += 0.04
+		}
+		b.fallDistance = 0
+	} else if b.jumping && b.mc.onGround {
+		m.vel[1] = 0.42
+		if boost, ok := m.effects.Effect(effect.JumpBoost{}); ok {
+			m.vel[1] += float64(boost.Level()) / 10
+		}
+	}
+	b.moving, b.jumping = false, false
+
+	velBefore := m.vel
+	mov := b.mc.TickMovement(m, m.pos, m.vel, m.rot)
+	m.pos, m.vel = mov.pos, mov.vel
+	b.collided = (velBefore[0] != 0 && mov.vel[0] == 0) || (velBefore[2] != 0 && mov.vel[2] == 0)
+
+	var fallDistance float64
+	if mov.dpos[1] < 0 {
+		b.fallDistance -= mov.dpos[1]
+	}
+	if b.mc.onGround {
+		fallDistance, b.fallDistance = b.fallDistance, 0
+	}
+	m.mu.Unlock()
+
+	if fallDistance > 0 {
+		b.fall(m, w, fallDistance)
+	}
+	m.checkEntityInsiders(w)
+	return mov
+}
+
+// applyMovement changes the velocity of the mob so that it moves towards the
+// position requested using Mob.MoveTowards. applyMovement must be called
+// while holding the lock of the mob.
+func (b *MobBehaviour) applyMovement(m *Mob, w *world.World) {
+	diff := b.moveTo.Sub(m.pos)
+	diff[1] = 0
+	if diff.Len() <= 0.01 {
+		return
+	}
+	friction := 1 - b.mc.Drag
+	if b.mc.onGround {
+		if f, ok := w.Block(cube.PosFromVec3(m.pos).Side(cube.FaceDown)).(block.Frictional); ok {
+			friction *= f.Friction()
+		} else {
+			friction *= 0.6
+		}
+	}
+	// Velocity is set so that the mob moves exactly its speed during this
+	// tick, after friction is applied. The mob only has limited control over
+	// its movement while in the air.
+	target := diff.Normalize().Mul(math.Min(m.speed*b.moveSpeed, diff.Len()) / friction)
+	control := 0.6
+	if !b.mc.onGround {
+		control = 0.1
+	}
+	m.vel[0] += (target[0] - m.vel[0]) * control
+	m.vel[2] += (target[2] - m.vel[2]) * control
+
+	if !b.lookLocked() {
+		m.rot = cube.Rotation{-mgl64.RadToDeg(math.Atan2(diff[0], diff[2])), m.rot.Pitch()}
+	}
+}
+
+// lookLocked checks if a goal controlling the look direction of the mob is
+// running, in which case the rotation of the mob should not follow its
+// movement.
+func (b *MobBehaviour) lookLocked() bool {
+	for _, g := range b.goals.Running() {
+		if g.Flags()&GoalFlagLook != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// fall is called when the mob hits the ground after falling.
+func (b *MobBehaviour) fall(m *Mob, w *world.World, distance float64) {
+	pos := cube.PosFromVec3(m.Position())
+	bl := w.Block(pos)
+	if len(bl.Model().BBox(pos, w)) == 0 {
+		pos = pos.Side(cube.FaceDown)
+		bl = w.Block(pos)
+	}
+	if h, ok := bl.(block.EntityLander); ok {
+		h.EntityLand(pos, w, m, &distance)
+	}
+	if b.conf.NoFallDamage {
+		return
+	}
+	dmg := distance - 3
+	if boost, ok := m.Effect(effect.JumpBoost{}); ok {
+		dmg -= float64(boost.Level())
+	}
+	if dmg < 0.5 {
+		return
+	}
+	m.Hurt(math.Ceil(dmg), FallDamageSource{})
+}
+
+// MobConfig holds parameters that influence the way a Mob behaves.
+type MobConfig struct {
+	// Behaviour is the Behaviour of the Mob. It is either a *MobBehaviour or
+	// a Behaviour that embeds one, such as SheepBehaviour.
+	Behaviour Behaviour
+	// MaxHealth is the maximum health of the Mob. If 0, a maximum health of
+	// 20 is used.
+	MaxHealth float64
+	// Speed is the movement speed of the Mob in blocks per tick.
+	Speed float64
+	// AttackDamage is the damage dealt by the Mob when it attacks another
+	// entity without holding a weapon.
+	AttackDamage float64
+	// Experience is the amount of experience dropped by the Mob when it is
+	// killed by a player.
+	Experience int
+	// Category is the world.SpawnCategory that the Mob counts towards when
+	// entities spawn naturally.
+	Category world.SpawnCategory
+	// Persistent specifies if the Mob should never despawn when no players
+	// are nearby, like animals. Mobs that are not persistent may still be
+	// made persistent by calling Mob.SetPersistent or giving them a name tag.
+	Persistent bool
+}
+
+// New creates a new Mob using conf. The Mob has a type and a position.
+func (conf MobConfig) New(t world.EntityType, pos mgl64.Vec3) *Mob {
+	if conf.MaxHealth == 0 {
+		conf.MaxHealth = 20
+	}
+	m := &Mob{
+		Ent:     Config{Behaviour: conf.Behaviour}.New(t, pos),
+		conf:    conf,
+		b:       conf.Behaviour.(interface{ mobBehaviour() *MobBehaviour }).mobBehaviour(),
+		speed:   conf.Speed,
+		persist: conf.Persistent,
+		health:  NewHealthManager(conf.MaxHealth, conf.MaxHealth),
+		effects: NewEffectManager(),
+	}
+	m.ent = m
+	if m.b.conf.Path.Height == 0 {
+		m.b.conf.Path.Height = int(math.Ceil(t.BBox(nil).Height()))
+	}
+	m.armour = inventory.NewArmour(func(int, item.Stack, item.Stack) {
+		if w := m.World(); w != nil {
+			for _, v := range w.Viewers(m.Position()) {
+				v.ViewEntityArmour(m)
+			}
+		}
+	})
+	return m
+}
+
+// Mob is a world.Entity implementation for living, AI controlled entities
+// such as zombies and cows. A Mob is an Ent, of which the behaviour is a
+// MobBehaviour: The goals, pathfinding and movement of a Mob are implemented
+// by the MobBehaviour. Mobs implement Living: They have health, may wear
+// armour and hold items, and are able to take damage and receive effects.
+type Mob struct {
+	*Ent
+	conf MobConfig
+	b    *MobBehaviour
+
+	persist  bool
+	speed    float64
+	immunity time.Duration
+
+	mainHand, offHand item.Stack
+
+	attacker Living
+	lastHurt time.Duration
+	hurt     bool
+
+	health  *HealthManager
+	effects *EffectManager
+	armour  *inventory.Armour
+}
+
+// Interact is called when the user passed interacts with the Mob without using
+// its held item on it, such as a player opening the trading window of a
+// villager. Interact returns false if the MobBehaviour of the Mob does nothing
+// when interacted with.
+func (m *Mob) Interact(user item.User) bool {
+	if m.b.conf.Interact == nil {
+		return false
+	}
+	return m.b.conf.Interact(m, user)
+}
+
+// Teleport teleports the Mob to the position passed. Unlike regular movement,
+// it immediately changes the position of the Mob for viewers.
+func (m *Mob) Teleport(pos mgl64.Vec3) {
+	if w := m.World(); w != nil {
+		for _, v := range w.Viewers(m.Position()) {
+			v.ViewEntityTeleport(m, pos)
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pos, m.vel, m.b.fallDistance = pos, mgl64.Vec3{}, 0
+	m.b.path = nil
+}
+
+// EyeHeight returns the offset from the position of the Mob at which its eyes
+// are found.
+func (m *Mob) EyeHeight() float64 {
+	return m.Type().BBox(m).Height() * 0.85
+}
+
+// OnGround checks if the Mob is currently on the ground.
+func (m *Mob) OnGround() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.b.mc.OnGround()
+}
+
+// FallDistance returns the distance that the Mob has fallen since it last
+// stood on the ground.
+func (m *Mob) FallDistance() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.b.fallDistance
+}
+
+// SpawnCategory returns the world.SpawnCategory of the Mob, as specified in
+// its MobConfig.
+func (m *Mob) SpawnCategory() world.SpawnCategory {
+	return m.conf.Category
+}
+
+// Persistent checks if the Mob is prevented from despawning when no players
+// are nearby. This is the case for mobs configured to be persistent, mobs that
+// were made persistent using SetPersistent and mobs with a name tag.
+func (m *Mob) Persistent() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.persist || m.name != ""
+}
+
+// SetPersistent changes if the Mob is prevented from despawning when no
+// players are nearby.
+func (m *Mob) SetPersistent(v bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.persist = v
+}
+
+// Health returns the current health of the Mob.
+func (m *Mob) Health() float64 {
+	return m.health.Health()
+}
+
+// MaxHealth returns the maximum health of the Mob.
+func (m *Mob) MaxHealth() float64 {
+	return m.health.MaxHealth()
+}
+
+// SetMaxHealth sets the maximum health of the Mob. If the current health of
+// the Mob is higher than the new maximum, the health is set to the new
+// maximum.
+func (m *Mob) SetMaxHealth(v float64) {
+	m.health.SetMaxHealth(v)
+}
+
+// Dead checks if the Mob is considered dead. True is returned if the health of
+// the Mob is equal to or lower than 0.
+func (m *Mob) Dead() bool {
+	return m.Health() <= mgl64.Epsilon
+}
+
+// AttackImmune checks if the Mob is currently immune to entity attacks,
+// meaning it was recently attacked.
+func (m *Mob) AttackImmune() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.immunity > 0
+}
+
+// SetAttackImmunity sets the duration the Mob is immune to entity attacks.
+func (m *Mob) SetAttackImmunity(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.immunity = d
+}
+
+// Hurt hurts the Mob for a given amount of damage. The source passed
+// represents the cause of the damage, for example AttackDamageSource if the
+// Mob is attacked by another entity. If the final damage exceeds the health
+// that the Mob currently has, the Mob is killed.
+// Hurt returns the final damage dealt to the Mob and if the Mob was vulnerable
+// to this kind of damage.
+func (m *Mob) Hurt(dmg float64, src world.DamageSource) (float64, bool) {
+	if _, ok := m.Effect(effect.FireResistance{}); (ok && src.Fire()) || m.Dead() || dmg < 0 {
+		return 0, false
+	}
+	totalDamage := m.FinalDamageFrom(dmg, src)
+	m.health.AddHealth(-totalDamage)
+
+	var origin world.Entity
+	if s, ok := src.(AttackDamageSource); ok {
+		origin = s.Attacker
+	} else if s, ok := src.(ProjectileDamageSource); ok {
+		origin = s.Owner
+	}
+	if src.ReducedByArmour() {
+		m.armour.Damage(dmg, m.damageItem)
+		if l, ok := origin.(Living); ok {
+			if thornsDmg := m.armour.ThornsDamage(m.damageItem); thornsDmg > 0 {
+				l.Hurt(thornsDmg, enchantment.ThornsDamageSource{Owner: m})
+			}
+		}
+	}
+
+	m.mu.Lock()
+	if l, ok := origin.(Living); ok && origin != world.Entity(m) {
+		m.attacker = l
+	}
+	m.lastHurt, m.hurt = m.age, true
+	m.immunity = time.Second / 2
+	m.mu.Unlock()
+
+	for _, v := range m.World().Viewers(m.Position()) {
+		v.ViewEntityAction(m, HurtAction{})
+	}
+	if m.Dead() {
+		m.kill(origin)
+	}
+	return totalDamage, true
+}
+
+// FinalDamageFrom resolves the final damage received by the Mob if it is
+// attacked by the source passed with the damage passed. FinalDamageFrom takes
+// into account things such as the armour worn and the enchantments on the
+// individual pieces. The damage returned will be at the least 0.
+func (m *Mob) FinalDamageFrom(dmg float64, src world.DamageSource) float64 {
+	dmg = math.Max(dmg, 0)
+
+	dmg -= m.armour.DamageReduction(dmg, src)
+	if res, ok := m.Effect(effect.Resistance{}); ok {
+		dmg *= effect.Resistance{}.Multiplier(src, res.Level())
+	}
+	return dmg
+}
+
+// Heal heals the Mob for a given amount of health. If the health added to the
+// original health exceeds the maximum health of the Mob, Heal will not add
+// the full amount. If the health passed is negative, Heal will not do
+// anything.
+func (m *Mob) Heal(health float64, _ world.HealingSource) {
+	if m.Dead() || health < 0 {
+		return
+	}
+	m.health.AddHealth(health)
+}
+
+// KnockBack knocks the Mob back with a given force and height. A source is
+// passed which indicates the source of the velocity, typically the position of
+// an attacking entity. The source is used to calculate the direction which the
+// Mob should be knocked back in.
+func (m *Mob) KnockBack(src mgl64.Vec3, force, height float64) {
+	if m.Dead() {
+		return
+	}
+	velocity := m.Position().Sub(src)
+	velocity[1] = 0
+
+	if velocity.Len() != 0 {
+		velocity = velocity.Normalize().Mul(force)
+	}
+	velocity[1] = height
+
+	m.SetVelocity(velocity.Mul(1 - m.armour.KnockBackResistance()))
+}
+
+// AddEffect adds an effect.Effect to the Mob. If the effect is instant, it is
+// applied to the Mob immediately. If not, the effect is applied to the Mob
+// every time the Tick method is called.
+func (m *Mob) AddEffect(e effect.Effect) {
+	m.effects.Add(e, m)
+	m.updateState()
+}
+
+// RemoveEffect removes any effect that might currently be active on the Mob.
+func (m *Mob) RemoveEffect(e effect.Type) {
+	m.effects.Remove(e, m)
+	m.updateState()
+}
+
+// Effect returns the effect instance and true if the Mob has the effect. If
+// not found, it will return an empty effect instance and false.
+func (m *Mob) Effect(e effect.Type) (effect.Effect, bool) {
+	return m.effects.Effect(e)
+}
+
+// Effects returns any effect currently applied to the Mob. The returned
+// effects are guaranteed not to have expired when returned.
+func (m *Mob) Effects() []effect.Effect {
+	return m.effects.Effects()
+}
+
+// Speed returns the current movement speed of the Mob in blocks per tick.
+func (m *Mob) Speed() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.speed
+}
+
+// SetSpeed sets the movement speed of the Mob in blocks per tick.
+func (m *Mob) SetSpeed(speed float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.speed = speed
+}
+
+// Armour returns the armour inventory of the Mob.
+func (m *Mob) Armour() *inventory.Armour {
+	return m.armour
+}
+
+// HeldItems returns the items currently held in the main hand and off-hand of
+// the Mob.
+func (m *Mob) HeldItems() (mainHand, offHand item.Stack) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.mainHand, m.offHand
+}
+
+// SetHeldItems sets the items held in the main hand and off-hand of the Mob.
+func (m *Mob) SetHeldItems(mainHand, offHand item.Stack) {
+	m.mu.Lock()
+	m.mainHand, m.offHand = mainHand, offHand
+	m.mu.Unlock()
+
+	for _, v := range m.World().Viewers(m.Position()) {
+		v.ViewEntityItems(m)
+	}
+}
+
+// Target returns the entity that the Mob is currently targeting, or nil if it
+// does not have a target.
+func (m *Mob) Target() Living {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.b.target
+}
+
+// SetTarget changes the target of the Mob. Passing nil clears the target.
+func (m *Mob) SetTarget(target Living) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.b.target = target
+}
+
+// Attacker returns the entity that last hurt the Mob and the time that has
+// passed since. Nil is returned if the Mob was never hurt by another entity.
+func (m *Mob) Attacker() (Living, time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.attacker, m.age - m.lastHurt
+}
+
+// LastHurt returns the time that has passed since the Mob was last hurt. False
+// is returned if the Mob was never hurt.
+func (m *Mob) LastHurt() (time.Duration, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.age - m.lastHurt, m.hurt
+}
+
+// AttackEntity makes the Mob attack the entity passed with the item held in
+// its main hand. True is returned if the entity was hurt by the attack.
+func (m *Mob) AttackEntity(e world.Entity) bool {
+	l, ok := e.(Living)
+	if !ok || l.AttackImmune() || l.Dead() {
+		return false
+	}
+	m.SwingArm()
+
+	dmg := m.conf.AttackDamage
+	held, _ := m.HeldItems()
+	if w, ok := held.Item().(item.Weapon); ok {
+		dmg += w.AttackDamage()
+	}
+	if strength, ok := m.Effect(effect.Strength{}); ok {
+		dmg += dmg * effect.Strength{}.Multiplier(strength.Level())
+	}
+	if weakness, ok := m.Effect(effect.Weakness{}); ok {
+		dmg -= dmg * effect.Weakness{}.Multiplier(weakness.Level())
+	}
+	n, vulnerable := l.Hurt(dmg, AttackDamageSource{Attacker: m})
+	m.World().PlaySound(EyePosition(e), sound.Attack{Damage: !mgl64.FloatEqual(n, 0)})
+	if !vulnerable {
+		return false
+	}
+	l.KnockBack(m.Position(), 0.4, 0.4)
+	if f, ok := held.Enchantment(enchantment.FireAspect{}); ok {
+		if flammable, ok := e.(interface{ SetOnFire(time.Duration) }); ok {
+			flammable.SetOnFire((enchantment.FireAspect{}).Duration(f.Level()))
+		}
+	}
+	return true
+}
+
+// SwingArm makes the Mob swing its arm.
+func (m *Mob) SwingArm() {
+	for _, v := range m.World().Viewers(m.Position()) {
+		v.ViewEntityAction(m, SwingArmAction{})
+	}
+}
+
+// LookAt rotates the Mob so that it looks at the position passed.
+func (m *Mob) LookAt(pos mgl64.Vec3) {
+	diff := pos.Sub(EyePosition(m))
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rot = cube.Rotation{
+		-mgl64.RadToDeg(math.Atan2(diff[0], diff[2])),
+		-mgl64.RadToDeg(math.Atan2(diff[1], math.Hypot(diff[0], diff[2]))),
+	}
+}
+
+// CanSee checks if the Mob has a clear line of sight to the entity passed,
+// meaning no blocks are in between the eyes of the Mob and those of the
+// entity.
+func (m *Mob) CanSee(e world.Entity) bool {
+	return lineOfSight(m.World(), EyePosition(m), EyePosition(e))
+}
+
+// Jump makes the Mob jump on its next tick if it is on the ground, or swim
+// upwards if it is in a liquid.
+func (m *Mob) Jump() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.b.jumping = true
+}
+
+// MoveTowards makes the Mob move towards the position passed during its next
+// tick. The speed passed is multiplied with the speed of the Mob.
+func (m *Mob) MoveTowards(pos mgl64.Vec3, speed float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.b.moving, m.b.moveTo, m.b.moveSpeed = true, pos, speed
+}
+
+// Navigate finds a path to the position passed and makes the Mob follow it
+// with a speed that is multiplied with the speed of the Mob. If no path to the
+// position could be found, the Mob follows the path that gets it closest.
+// Navigate returns false if the Mob was unable to move towards the position
+// at all.
+func (m *Mob) Navigate(pos mgl64.Vec3, speed float64) bool {
+	w := m.World()
+	path, _ := FindPath(w, m.feetPos(w), cube.PosFromVec3(pos), m.b.conf.Path)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if path == nil || path.Finished() {
+		m.b.path = nil
+		return false
+	}
+	m.b.path, m.b.pathSpeed, m.b.stuckTicks = path, speed, 0
+	return true
+}
+
+// Navigating checks if the Mob is currently following a path.
+func (m *Mob) Navigating() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.b.path != nil
+}
+
+// Path returns the path that the Mob is currently following, or nil if it is
+// not following a path.
+func (m *Mob) Path() *Path {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.b.path
+}
+
+// StopNavigating makes the Mob stop following its current path.
+func (m *Mob) StopNavigating() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.b.path = nil
+}
+
+// mobPortalCooldown is the amount of ticks after travelling through a portal
+// before a Mob may travel through a portal again.
+const mobPortalCooldown = 300
+
+// EnterPortal is called when the Mob is inside a portal block at the position
+// passed. Unlike players, mobs travel through portals instantly, after which
+// they must spend 15 seconds outside of portals before using one again.
+func (m *Mob) EnterPortal(portal block.Portal, pos cube.Pos) {
+	m.mu.Lock()
+	if m.b.portalCooldown > 0 {
+		m.b.portalCooldown = mobPortalCooldown
+		m.mu.Unlock()
+		return
+	}
+	m.b.portalCooldown = mobPortalCooldown
+	m.mu.Unlock()
+
+	w := m.World()
+	dest := portal.PortalDestination(pos, w)
+	if dest == nil {
+		return
+	}
+	if dest != w {
+		// The Mob is removed from its world right away, so that it is no
+		// longer ticked while it travels to the destination.
+		w.RemoveEntity(m)
+	}
+	// The exit portal is looked up and created during the tick of the
+	// destination, so that mobs arriving at the same time do not each create
+	// one.
+	dest.Exec(func() {
+		target := portal.PortalTarget(pos, w, dest, m)
+		if dest == w {
+			m.Teleport(target)
+			return
+		}
+		m.mu.Lock()
+		m.pos, m.vel, m.b.fallDistance = target, mgl64.Vec3{}, 0
+		m.b.path = nil
+		m.mu.Unlock()
+		dest.AddEntity(m)
+	})
+}
+
+// checkEntityInsiders calls EntityInside on all blocks that the Mob is inside
+// of.
+func (m *Mob) checkEntityInsiders(w *world.World) {
+	box := m.Type().BBox(m).Translate(m.Position()).Grow(-0.0001)
+	min, max := cube.PosFromVec3(box.Min()), cube.PosFromVec3(box.Max())
+
+	for y := min[1]; y <= max[1]; y++ {
+		for x := min[0]; x <= max[0]; x++ {
+			for z := min[2]; z <= max[2]; z++ {
+				pos := cube.Pos{x, y, z}
+				if collide, ok := w.Block(pos).(block.EntityInsider); ok {
+					collide.EntityInside(pos, w, m)
+				}
+				if l, ok := w.Liquid(pos); ok {
+					if collide, ok := l.(block.EntityInsider); ok {
+						collide.EntityInside(pos, w, m)
+					}
+				}
+			}
+		}
+	}
+}
+
+// feetPos returns the block position that the feet of the Mob are in. If the
+// Mob is standing on a block that is not a full block, such as a slab, the
+// position above it is returned.
+func (m *Mob) feetPos(w *world.World) cube.Pos {
+	pos := cube.PosFromVec3(m.Position())
+	if collisionHeight(w, pos) > 0.125 {
+		return pos.Side(cube.FaceUp)
+	}
+	return pos
+}
+
+// kill handles the death of the Mob, dropping its items and experience.
+func (m *Mob) kill(killer world.Entity) {
+	w, pos := m.World(), m.Position()
+	for _, v := range w.Viewers(pos) {
+		v.ViewEntityAction(m, DeathAction{})
+	}
+	m.StopNavigating()
+
+	var drops []item.Stack
+	if m.b.conf.Drops != nil {
+		drops = m.b.conf.Drops(m)
+	}
+	for _, it := range drops {
+		if it.Empty() {
+			continue
+		}
+		ent := NewItem(it, pos)
+		ent.SetVelocity(mgl64.Vec3{rand.Float64()*0.2 - 0.1, 0.2, rand.Float64()*0.2 - 0.1})
+		w.AddEntity(ent)
+	}
+	if _, ok := killer.(interface{ GameMode() world.GameMode }); ok && m.conf.Experience > 0 {
+		for _, orb := range NewExperienceOrbs(pos, m.conf.Experience) {
+			orb.SetVelocity(mgl64.Vec3{(rand.Float64()*0.2 - 0.1) * 2, rand.Float64() * 0.4, (rand.Float64()*0.2 - 0.1) * 2})
+			w.AddEntity(orb)
+		}
+	}
+}
+
+// damageItem damages the item stack passed with the damage passed and returns
+// the new stack.
+func (m *Mob) damageItem(s item.Stack, d int) item.Stack {
+	if s.Empty() {
+		return s
+	}
+	if _, ok := s.Item().(item.Durable); !ok {
+		return s
+	}
+	if e, ok := s.Enchantment(enchantment.Unbreaking{}); ok {
+		d = (enchantment.Unbreaking{}).Reduce(s.Item(), e.Level(), d)
+	}
+	return s.Damage(d)
+}
+
+// updateState sends the state of the Mob to all viewers.
+func (m *Mob) updateState() {
+	w := m.World()
+	if w == nil {
+		return
+	}
+	for _, v := range w.Viewers(m.Position()) {
+		v.ViewEntityState(m)
+	}
+}
+
+// lineOfSight checks if no blocks are in between the start and end positions
+// passed.
+func lineOfSight(w *world.World, start, end mgl64.Vec3) bool {
+	visible := true
+	trace.TraverseBlocks(start, end, func(pos cube.Pos) bool {
+		if _, ok := trace.BlockIntercept(pos, w, w.Block(pos), start, end); ok {
+			visible = false
+		}
+		return visible
+	})
+	return visible
+}
+
+// burnInDaylight sets the Mob passed on fire if it is exposed to direct
+// sunlight during the day without wearing a helmet.
+func burnInDaylight(m *Mob) {
+	w, pos := m.World(), cube.PosFromVec3(EyePosition(m))
+	if t := w.Time() % 24000; t >= 12000 || w.SkyLight(pos) < 15 || w.RainingAt(pos) {
+		return
+	}
+	if _, ok := w.Liquid(pos); ok {
+		return
+	}
+	if helmet := m.Armour().Helmet(); !helmet.Empty() {
+		if rand.Intn(20) == 0 {
+			m.Armour().SetHelmet(m.damageItem(helmet, 1))
+		}
+		return
+	}
+	if m.OnFireDuration() <= 0 {
+		m.SetOnFire(time.Second * 8)
+	}
+}
+
+// mobDrop returns a stack of the item passed with a random count between min
+// and max, both inclusive.
+func mobDrop(it world.Item, min, max int) item.Stack {
+	return item.NewStack(it, min+rand.Intn(max-min+1))
+}
+
+// decodeMobNBT decodes the properties shared by all mobs from the NBT map
+// passed into the Mob.
+func decodeMobNBT(data map[string]any, m *Mob) *Mob {
+	m.vel = nbtconv.Vec3(data, "Motion")
+	m.rot = nbtconv.Rotation(data)
+	m.fireDuration = time.Duration(nbtconv.Int16(data, "Fire")) * time.Second / 20
+	m.name = nbtconv.String(data, "CustomName")
+	if _, ok := data["Persistent"]; ok {
+		m.persist = nbtconv.Bool(data, "Persistent")
+	}
+	if _, ok := data["Health"]; ok {
+		m.health.SetHealth(float64(nbtconv.Float32(data, "Health")))
+	}
+	if armour, ok := data["Armor"].([]any); ok && len(armour) == 4 {
+		stacks := make([]item.Stack, 4)
+		for i, a := range armour {
+			if it, ok := a.(map[string]any); ok {
+				stacks[i] = nbtconv.Item(it, nil)
+			}
+		}
+		m.armour.Set(stacks[0], stacks[1], stacks[2], stacks[3])
+	}
+	if _, ok := data["Mainhand"]; ok {
+		m.mainHand = nbtconv.MapItem(data, "Mainhand")
+	}
+	if _, ok := data["Offhand"]; ok {
+		m.offHand = nbtconv.MapItem(data, "Offhand")
+	}
+	return m
+}
+
+// encodeMobNBT encodes the properties shared by all mobs to a map that can be
+// encoded to NBT.
+func encodeMobNBT(m *Mob) map[string]any {
+	yaw, pitch := m.Rotation().Elem()
+	mainHand, offHand := m.HeldItems()
+	m.mu.Lock()
+	persist := m.persist
+	m.mu.Unlock()
+	armour := make([]any, 0, 4)
+	for _, it := range m.armour.Slots() {
+		armour = append(armour, writeMobItem(it))
+	}
+	data := map[string]any{
+		"Pos":        nbtconv.Vec3ToFloat32Slice(m.Position()),
+		"Motion":     nbtconv.Vec3ToFloat32Slice(m.Velocity()),
+		"Yaw":        float32(yaw),
+		"Pitch":      float32(pitch),
+		"Health":     float32(m.Health()),
+		"Fire":       int16(m.OnFireDuration() / (time.Second / 20)),
+		"Armor":      armour,
+		"Mainhand":   writeMobItem(mainHand),
+		"Offhand":    writeMobItem(offHand),
+		"Persistent": boolByte(persist),
+	}
+	if name := m.NameTag(); name != "" {
+		data["CustomName"] = name
+	}
+	return data
+}
+
+// writeMobItem encodes an item.Stack held or worn by a Mob. Empty stacks are
+// encoded as an empty map, so that the slot they occupy is preserved.
+func writeMobItem(s item.Stack) map[string]any {
+	if s.Empty() {
+		return map[string]any{}
+	}
+	return nbtconv.WriteItem(s, true)
+}
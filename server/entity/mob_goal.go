@@ -0,0 +1,121 @@
+package entity
+
+// GoalFlag is a flag that specifies which controls of a Mob a Goal makes use
+// of. Two goals that share a flag cannot be running at the same time.
+type GoalFlag uint8
+
+const (
+	// GoalFlagMove is set by goals that control the movement of a Mob.
+	GoalFlagMove GoalFlag = 1 << iota
+	// GoalFlagLook is set by goals that control the direction a Mob looks
+	// in.
+	GoalFlagLook
+	// GoalFlagJump is set by goals that make a Mob jump.
+	GoalFlagJump
+	// GoalFlagTarget is set by goals that select the target of a Mob.
+	GoalFlagTarget
+)
+
+// Goal is a single objective pursued by a Mob, such as wandering around,
+// attacking its target or panicking after being hurt. Goals are scheduled by
+// a GoalSelector.
+type Goal interface {
+	// Flags returns the controls of the Mob that the Goal makes use of.
+	Flags() GoalFlag
+	// CanStart checks if the Goal should start running.
+	CanStart(m *Mob) bool
+	// CanContinue checks if a running Goal should keep running. The Goal is
+	// stopped if false is returned.
+	CanContinue(m *Mob) bool
+	// Start is called when the Goal starts running.
+	Start(m *Mob)
+	// Stop is called when the Goal stops running, either because it could no
+	// longer continue or because it was interrupted by a Goal with a higher
+	// priority.
+	Stop(m *Mob)
+	// Tick is called every tick that the Goal is running.
+	Tick(m *Mob)
+}
+
+// GoalSelector schedules the Goals of a Mob based on their priority. Every
+// tick, goals that are able to start are started, as long as no goal with a
+// higher priority that shares one of its flags is running. Running goals with
+// a lower priority are interrupted if they share a flag with a goal that is
+// started.
+type GoalSelector struct {
+	goals   []Goal
+	running []bool
+}
+
+// NewGoalSelector creates a GoalSelector for the goals passed. Goals are
+// passed in order of priority, from high to low.
+func NewGoalSelector(goals ...Goal) *GoalSelector {
+	return &GoalSelector{goals: goals, running: make([]bool, len(goals))}
+}
+
+// Goals returns all goals of the GoalSelector in order of priority.
+func (s *GoalSelector) Goals() []Goal {
+	return s.goals
+}
+
+// Running returns all goals that are currently running.
+func (s *GoalSelector) Running() []Goal {
+	running := make([]Goal, 0, len(s.goals))
+	for i, g := range s.goals {
+		if s.running[i] {
+			running = append(running, g)
+		}
+	}
+	return running
+}
+
+// Tick ticks the GoalSelector, stopping goals that can no longer continue,
+// starting goals that can start and ticking all goals that are running.
+func (s *GoalSelector) Tick(m *Mob) {
+	for i, g := range s.goals {
+		if s.running[i] && !g.CanContinue(m) {
+			s.running[i] = false
+			g.Stop(m)
+		}
+	}
+	for i, g := range s.goals {
+		if s.running[i] || !s.available(i) || !g.CanStart(m) {
+			continue
+		}
+		for j, other := range s.goals[i+1:] {
+			if s.running[i+1+j] && other.Flags()&g.Flags() != 0 {
+				s.running[i+1+j] = false
+				other.Stop(m)
+			}
+		}
+		s.running[i] = true
+		g.Start(m)
+	}
+	for i, g := range s.goals {
+		if s.running[i] {
+			g.Tick(m)
+		}
+	}
+}
+
+// Stop stops all goals that are currently running.
+func (s *GoalSelector) Stop(m *Mob) {
+	for i, g := range s.goals {
+		if s.running[i] {
+			s.running[i] = false
+			g.Stop(m)
+		}
+	}
+}
+
+// available checks if the goal at the index passed could be started without
+// conflicting with a running goal of a higher priority.
+func (s *GoalSelector) available(index int) bool {
+	flags := s.goals[index].Flags()
+	for i, g := range s.goals[:index] {
+		if s.running[i] && g.Flags()&flags != 0 {
+			return false
+		}
+	}
+	return true
+}
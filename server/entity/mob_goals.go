@@ -0,0 +1,429 @@
+package entity
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/sound"
+)
+
+// SwimGoal makes a Mob swim upwards while it is in a liquid, so that it does
+// not drown.
+type SwimGoal struct{}
+
+func (SwimGoal) Flags() GoalFlag { return GoalFlagJump }
+func (SwimGoal) CanStart(m *Mob) bool {
+	_, ok := m.World().Liquid(cube.PosFromVec3(EyePosition(m).Sub(mgl64.Vec3{0, 0.4})))
+	return ok
+}
+func (g SwimGoal) CanContinue(m *Mob) bool { return g.CanStart(m) }
+func (SwimGoal) Start(*Mob)                {}
+func (SwimGoal) Stop(*Mob)                 {}
+func (SwimGoal) Tick(m *Mob) {
+	if rand.Float64() < 0.8 {
+		m.Jump()
+	}
+}
+
+// WanderGoal makes a Mob walk to random positions around it.
+type WanderGoal struct {
+	// Speed is the multiplier of the speed of the Mob while wandering.
+	Speed float64
+	// Interval is the average amount of ticks in between the Mob starting to
+	// wander. If 0, an interval of 120 ticks is used.
+	Interval int
+}
+
+func (WanderGoal) Flags() GoalFlag { return GoalFlagMove }
+func (g WanderGoal) CanStart(m *Mob) bool {
+	interval := g.Interval
+	if interval == 0 {
+		interval = 120
+	}
+	return !m.Navigating() && rand.Intn(interval) == 0
+}
+func (WanderGoal) CanContinue(m *Mob) bool { return m.Navigating() }
+func (g WanderGoal) Start(m *Mob) {
+	if pos, ok := randomPosition(m, 10, 7, true); ok {
+		m.Navigate(pos, g.Speed)
+	}
+}
+func (WanderGoal) Stop(m *Mob) { m.StopNavigating() }
+func (WanderGoal) Tick(*Mob)   {}
+
+// PanicGoal makes a Mob run around randomly for a short while after being
+// hurt.
+type PanicGoal struct {
+	// Speed is the multiplier of the speed of the Mob while panicking.
+	Speed float64
+}
+
+func (PanicGoal) Flags() GoalFlag { return GoalFlagMove }
+func (PanicGoal) CanStart(m *Mob) bool {
+	d, hurt := m.LastHurt()
+	return hurt && d < time.Second*5 && (d < time.Second/20 || m.OnFireDuration() > 0)
+}
+func (PanicGoal) CanContinue(m *Mob) bool {
+	d, _ := m.LastHurt()
+	return m.Navigating() && d < time.Second*5
+}
+func (g PanicGoal) Start(m *Mob) {
+	if pos, ok := randomPosition(m, 5, 4, false); ok {
+		m.Navigate(pos, g.Speed)
+	}
+}
+func (PanicGoal) Stop(m *Mob) { m.StopNavigating() }
+func (g PanicGoal) Tick(m *Mob) {
+	if p := m.Path(); p != nil && p.Finished() {
+		g.Start(m)
+	}
+}
+
+// LookAtPlayerGoal makes a Mob look at players close to it every now and then.
+type LookAtPlayerGoal struct {
+	// Distance is the maximum distance that a player may be at for the Mob to
+	// look at it.
+	Distance float64
+
+	player   world.Entity
+	duration int
+}
+
+func (*LookAtPlayerGoal) Flags() GoalFlag { return GoalFlagLook }
+func (g *LookAtPlayerGoal) CanStart(m *Mob) bool {
+	if rand.Float64() >= 0.02 {
+		return false
+	}
+	g.player = nearestPlayer(m, g.Distance, false)
+	return g.player != nil
+}
+func (g *LookAtPlayerGoal) CanContinue(m *Mob) bool {
+	return g.duration > 0 && g.player.World() == m.World() && m.Position().Sub(g.player.Position()).Len() <= g.Distance
+}
+func (g *LookAtPlayerGoal) Start(*Mob) { g.duration = 40 + rand.Intn(40) }
+func (g *LookAtPlayerGoal) Stop(*Mob)  { g.player = nil }
+func (g *LookAtPlayerGoal) Tick(m *Mob) {
+	m.LookAt(EyePosition(g.player))
+	g.duration--
+}
+
+// RandomLookGoal makes a Mob look around randomly while idle.
+type RandomLookGoal struct {
+	yaw      float64
+	duration int
+}
+
+func (*RandomLookGoal) Flags() GoalFlag         { return GoalFlagLook }
+func (*RandomLookGoal) CanStart(*Mob) bool      { return rand.Float64() < 0.02 }
+func (g *RandomLookGoal) CanContinue(*Mob) bool { return g.duration > 0 }
+func (g *RandomLookGoal) Start(*Mob) {
+	g.yaw, g.duration = rand.Float64()*360-180, 20+rand.Intn(20)
+}
+func (*RandomLookGoal) Stop(*Mob) {}
+func (g *RandomLookGoal) Tick(m *Mob) {
+	m.LookAt(EyePosition(m).Add(cube.Rotation{g.yaw, 0}.Vec3()))
+	g.duration--
+}
+
+// TemptGoal makes a Mob follow players that hold one of the items that tempt
+// it.
+type TemptGoal struct {
+	// Items holds the items that tempt the Mob.
+	Items []world.Item
+	// Speed is the multiplier of the speed of the Mob while following the
+	// player.
+	Speed float64
+
+	player world.Entity
+	repath int
+}
+
+func (*TemptGoal) Flags() GoalFlag { return GoalFlagMove | GoalFlagLook }
+func (g *TemptGoal) CanStart(m *Mob) bool {
+	g.player = nil
+	for _, e := range m.World().EntitiesWithin(m.Type().BBox(m).Translate(m.Position()).Grow(10), nil) {
+		if isPlayer(e, true) && g.tempting(e) {
+			g.player = e
+			return true
+		}
+	}
+	return false
+}
+func (g *TemptGoal) CanContinue(m *Mob) bool {
+	return g.player.World() == m.World() && m.Position().Sub(g.player.Position()).Len() <= 10 && g.tempting(g.player)
+}
+func (g *TemptGoal) Start(*Mob) { g.repath = 0 }
+func (g *TemptGoal) Stop(m *Mob) {
+	g.player = nil
+	m.StopNavigating()
+}
+func (g *TemptGoal) Tick(m *Mob) {
+	m.LookAt(EyePosition(g.player))
+	if m.Position().Sub(g.player.Position()).Len() < 2.5 {
+		m.StopNavigating()
+		return
+	}
+	if g.repath--; g.repath <= 0 {
+		g.repath = 10
+		m.Navigate(g.player.Position(), g.Speed)
+	}
+}
+
+// tempting checks if the entity passed holds one of the items of the
+// TemptGoal.
+func (g *TemptGoal) tempting(e world.Entity) bool {
+	c, ok := e.(item.Carrier)
+	if !ok {
+		return false
+	}
+	main, off := c.HeldItems()
+	for _, held := range []item.Stack{main, off} {
+		if held.Empty() {
+			continue
+		}
+		name, _ := held.Item().EncodeItem()
+		for _, it := range g.Items {
+			if n, _ := it.EncodeItem(); n == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MeleeAttackGoal makes a Mob walk towards its target and attack it once it is
+// close enough.
+type MeleeAttackGoal struct {
+	// Speed is the multiplier of the speed of the Mob while chasing its
+	// target.
+	Speed float64
+
+	cooldown, repath int
+}
+
+func (*MeleeAttackGoal) Flags() GoalFlag { return GoalFlagMove | GoalFlagLook }
+func (*MeleeAttackGoal) CanStart(m *Mob) bool {
+	t := m.Target()
+	return t != nil && !t.Dead()
+}
+func (g *MeleeAttackGoal) CanContinue(m *Mob) bool {
+	t := m.Target()
+	return t != nil && !t.Dead() && m.Position().Sub(t.Position()).Len() < 32
+}
+func (g *MeleeAttackGoal) Start(*Mob) { g.repath = 0 }
+func (g *MeleeAttackGoal) Stop(m *Mob) {
+	m.StopNavigating()
+}
+func (g *MeleeAttackGoal) Tick(m *Mob) {
+	t := m.Target()
+	if t == nil {
+		return
+	}
+	m.LookAt(EyePosition(t))
+	dist := m.Position().Sub(t.Position()).Len()
+	if g.repath--; g.repath <= 0 {
+		g.repath = 4 + rand.Intn(7)
+		if dist > 16 {
+			g.repath += 10
+		}
+		if !m.Navigate(t.Position(), g.Speed) {
+			g.repath += 15
+		}
+	}
+	if g.cooldown > 0 {
+		g.cooldown--
+	}
+	reach := m.Type().BBox(m).Width()*2 + t.Type().BBox(t).Width()/2
+	if g.cooldown <= 0 && dist <= reach && m.CanSee(t) {
+		g.cooldown = 20
+		m.AttackEntity(t)
+	}
+}
+
+// RangedAttackGoal makes a Mob shoot arrows at its target from a distance,
+// moving closer if the target is out of range or out of sight.
+type RangedAttackGoal struct {
+	// Speed is the multiplier of the speed of the Mob while moving towards
+	// its target.
+	Speed float64
+	// Interval is the amount of ticks in between two shots.
+	Interval int
+	// Range is the maximum distance at which the Mob shoots at its target.
+	Range float64
+
+	seeTicks, cooldown, repath int
+}
+
+func (*RangedAttackGoal) Flags() GoalFlag { return GoalFlagMove | GoalFlagLook }
+func (*RangedAttackGoal) CanStart(m *Mob) bool {
+	t := m.Target()
+	return t != nil && !t.Dead()
+}
+func (g *RangedAttackGoal) CanContinue(m *Mob) bool {
+	t := m.Target()
+	return t != nil && !t.Dead() && m.Position().Sub(t.Position()).Len() < 32
+}
+func (g *RangedAttackGoal) Start(*Mob) {
+	g.seeTicks, g.cooldown, g.repath = 0, g.Interval, 0
+}
+func (g *RangedAttackGoal) Stop(m *Mob) {
+	m.StopNavigating()
+}
+func (g *RangedAttackGoal) Tick(m *Mob) {
+	t := m.Target()
+	if t == nil {
+		return
+	}
+	dist, visible := m.Position().Sub(t.Position()).Len(), m.CanSee(t)
+	if visible {
+		g.seeTicks++
+	} else {
+		g.seeTicks = 0
+	}
+	if dist <= g.Range && g.seeTicks >= 20 {
+		m.StopNavigating()
+	} else if g.repath--; g.repath <= 0 {
+		g.repath = 10
+		m.Navigate(t.Position(), g.Speed)
+	}
+	m.LookAt(EyePosition(t))
+
+	if g.cooldown > 0 {
+		g.cooldown--
+		return
+	}
+	if visible && dist <= g.Range {
+		g.cooldown = g.Interval
+		shootArrow(m, t)
+	}
+}
+
+// shootArrow makes the Mob passed shoot an arrow at the target passed.
+func shootArrow(m *Mob, t world.Entity) {
+	w, eye := m.World(), EyePosition(m)
+	diff := t.Position().Add(mgl64.Vec3{0, t.Type().BBox(t).Height() / 3}).Sub(eye)
+	diff[1] += math.Hypot(diff[0], diff[2]) * 0.2
+
+	vel := diff.Normalize().Add(mgl64.Vec3{rand.NormFloat64(), rand.NormFloat64(), rand.NormFloat64()}.Mul(0.0075 * 6)).Mul(1.6)
+	rot := cube.Rotation{
+		-mgl64.RadToDeg(math.Atan2(vel[0], vel[2])),
+		-mgl64.RadToDeg(math.Atan2(vel[1], math.Hypot(vel[0], vel[2]))),
+	}
+	arrow := NewArrow(eye, rot, m)
+	arrow.conf.Behaviour.(*ProjectileBehaviour).conf.DisablePickup = true
+	arrow.vel = vel
+
+	w.PlaySound(eye, sound.BowShoot{})
+	w.AddEntity(arrow)
+}
+
+// HurtByTargetGoal makes a Mob target the entity that last hurt it.
+type HurtByTargetGoal struct{}
+
+func (HurtByTargetGoal) Flags() GoalFlag { return GoalFlagTarget }
+func (HurtByTargetGoal) CanStart(m *Mob) bool {
+	attacker, d := m.Attacker()
+	return attacker != nil && d < time.Second/20 && !attacker.Dead() && attacker != m.Target() && targetable(attacker)
+}
+func (HurtByTargetGoal) CanContinue(m *Mob) bool { return validTarget(m, m.Target(), 32) }
+func (HurtByTargetGoal) Start(m *Mob) {
+	attacker, _ := m.Attacker()
+	m.SetTarget(attacker)
+}
+func (HurtByTargetGoal) Stop(m *Mob) { m.SetTarget(nil) }
+func (HurtByTargetGoal) Tick(*Mob)   {}
+
+// NearestPlayerTargetGoal makes a Mob target the nearest player that it can
+// see.
+type NearestPlayerTargetGoal struct {
+	// Distance is the maximum distance that a player may be at for the Mob to
+	// target it.
+	Distance float64
+}
+
+func (NearestPlayerTargetGoal) Flags() GoalFlag { return GoalFlagTarget }
+func (g NearestPlayerTargetGoal) CanStart(m *Mob) bool {
+	return m.Target() == nil && rand.Intn(10) == 0 && nearestPlayer(m, g.Distance, true) != nil
+}
+func (g NearestPlayerTargetGoal) CanContinue(m *Mob) bool {
+	return validTarget(m, m.Target(), g.Distance)
+}
+func (g NearestPlayerTargetGoal) Start(m *Mob) {
+	if p, ok := nearestPlayer(m, g.Distance, true).(Living); ok {
+		m.SetTarget(p)
+	}
+}
+func (NearestPlayerTargetGoal) Stop(m *Mob) { m.SetTarget(nil) }
+func (NearestPlayerTargetGoal) Tick(*Mob)   {}
+
+// validTarget checks if the entity passed is a valid target for the Mob to
+// keep attacking.
+func validTarget(m *Mob, t Living, distance float64) bool {
+	return t != nil && !t.Dead() && t.World() == m.World() && targetable(t) && m.Position().Sub(t.Position()).Len() <= distance
+}
+
+// targetable checks if the entity passed may be targeted by a Mob. Players
+// that cannot take damage, such as those in creative mode, cannot be
+// targeted.
+func targetable(e world.Entity) bool {
+	if g, ok := e.(interface{ GameMode() world.GameMode }); ok {
+		return g.GameMode().AllowsTakingDamage() && g.GameMode().Visible()
+	}
+	return true
+}
+
+// isPlayer checks if the entity passed is a player. If survival is true, only
+// players that can take damage are considered.
+func isPlayer(e world.Entity, survival bool) bool {
+	g, ok := e.(interface{ GameMode() world.GameMode })
+	return ok && (!survival || g.GameMode().AllowsTakingDamage())
+}
+
+// nearestPlayer returns the player nearest to the Mob passed within the
+// distance passed. If targeting is true, only players that may be targeted
+// and that can be seen by the Mob are returned. Nil is returned if no player
+// was found.
+func nearestPlayer(m *Mob, distance float64, targeting bool) world.Entity {
+	pos := m.Position()
+	var (
+		nearest world.Entity
+		minDist = distance
+	)
+	for _, e := range m.World().EntitiesWithin(m.Type().BBox(m).Translate(pos).Grow(distance), nil) {
+		if !isPlayer(e, false) || (targeting && (!targetable(e) || !m.CanSee(e))) {
+			continue
+		}
+		if l, ok := e.(Living); ok && l.Dead() {
+			continue
+		}
+		if d := pos.Sub(e.Position()).Len(); d <= minDist {
+			nearest, minDist = e, d
+		}
+	}
+	return nearest
+}
+
+// randomPosition returns a random position around the Mob passed that it is
+// able to stand on. If avoidWater is true, positions in water are not
+// returned.
+func randomPosition(m *Mob, horizontal, vertical int, avoidWater bool) (mgl64.Vec3, bool) {
+	w := m.World()
+	f := pathFinder{w: w, conf: m.b.conf.Path}
+	base := cube.PosFromVec3(m.Position())
+	for i := 0; i < 10; i++ {
+		pos := base.Add(cube.Pos{rand.Intn(horizontal*2+1) - horizontal, rand.Intn(vertical*2+1) - vertical, rand.Intn(horizontal*2+1) - horizontal})
+		if !f.passable(pos) || !f.standable(pos) {
+			continue
+		}
+		if _, ok := w.Liquid(pos); ok && avoidWater {
+			continue
+		}
+		return pos.Vec3Middle(), true
+	}
+	return mgl64.Vec3{}, false
+}
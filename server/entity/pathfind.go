@@ -0,0 +1,355 @@
+package entity
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// PathConfig holds parameters that influence the way a Path is found by
+// FindPath.
+type PathConfig struct {
+	// Height is the height of the entity in blocks. The entity requires at
+	// least this amount of free space above every position on the path.
+	// If Height is 0, a height of 2 is used.
+	Height int
+	// MaxNodes is the maximum amount of positions that are evaluated while
+	// finding a path. If 0, a maximum of 512 positions is used.
+	MaxNodes int
+	// MaxDistance is the maximum horizontal distance from the start that a
+	// position on the path may be at. If 0, a maximum distance of 32 is used.
+	MaxDistance int
+	// MaxFallDistance is the maximum amount of blocks the entity is willing
+	// to drop down when following the path. A fall of more than 3 blocks
+	// results in fall damage. If 0, a maximum of 3 is used.
+	MaxFallDistance int
+	// OpenDoors specifies if the entity is able to open closed wooden doors.
+	// If false, closed doors are treated as if they were solid.
+	OpenDoors bool
+	// AvoidWater makes the entity strongly prefer paths that do not lead
+	// through water.
+	AvoidWater bool
+}
+
+// Path is a path through a world.World found by FindPath. It consists of a
+// list of positions that an entity can walk along to reach a destination.
+type Path struct {
+	points []cube.Pos
+	index  int
+}
+
+// Positions returns all positions that are part of the path, including
+// positions that have already been passed.
+func (p *Path) Positions() []cube.Pos {
+	return p.points
+}
+
+// Current returns the position on the path that the entity following it
+// should currently move towards. False is returned if the path was finished.
+func (p *Path) Current() (cube.Pos, bool) {
+	if p.Finished() {
+		return cube.Pos{}, false
+	}
+	return p.points[p.index], true
+}
+
+// Advance moves to the next position on the path.
+func (p *Path) Advance() {
+	if !p.Finished() {
+		p.index++
+	}
+}
+
+// Finished checks if all positions of the path have been passed.
+func (p *Path) Finished() bool {
+	return p.index >= len(p.points)
+}
+
+// Destination returns the final position of the path.
+func (p *Path) Destination() cube.Pos {
+	if len(p.points) == 0 {
+		return cube.Pos{}
+	}
+	return p.points[len(p.points)-1]
+}
+
+// FindPath finds a path from start to end in the world.World passed using the
+// A* algorithm. Both start and end are the positions of the feet of the
+// entity. The path found takes into account the height of the entity, doors,
+// liquids, fences and the distance the entity is able to drop without taking
+// fall damage, as configured in the PathConfig passed.
+// If no path could be found that leads to the end position, the path that
+// gets closest to it is returned along with false. FindPath returns nil if
+// the entity is unable to move at all.
+func FindPath(w *world.World, start, end cube.Pos, conf PathConfig) (*Path, bool) {
+	if conf.Height == 0 {
+		conf.Height = 2
+	}
+	if conf.MaxNodes == 0 {
+		conf.MaxNodes = 512
+	}
+	if conf.MaxDistance == 0 {
+		conf.MaxDistance = 32
+	}
+	if conf.MaxFallDistance == 0 {
+		conf.MaxFallDistance = 3
+	}
+	f := pathFinder{w: w, conf: conf, start: start, end: end, nodes: map[cube.Pos]*pathNode{}}
+	return f.find()
+}
+
+// pathNode is a single position evaluated by a pathFinder.
+type pathNode struct {
+	pos    cube.Pos
+	parent *pathNode
+
+	cost, heuristic float64
+	closed          bool
+	index           int
+}
+
+// pathFinder holds the state of a single search performed by FindPath.
+type pathFinder struct {
+	w          *world.World
+	conf       PathConfig
+	start, end cube.Pos
+
+	nodes map[cube.Pos]*pathNode
+	open  pathQueue
+}
+
+// find performs the A* search and returns the resulting path.
+func (f *pathFinder) find() (*Path, bool) {
+	first := &pathNode{pos: f.start, heuristic: f.distance(f.start, f.end)}
+	f.nodes[f.start] = first
+	heap.Push(&f.open, first)
+
+	closest := first
+	for evaluated := 0; f.open.Len() > 0 && evaluated < f.conf.MaxNodes; evaluated++ {
+		n := heap.Pop(&f.open).(*pathNode)
+		n.closed = true
+		if n.pos == f.end {
+			return f.path(n), true
+		}
+		if n.heuristic < closest.heuristic {
+			closest = n
+		}
+		for _, next := range f.neighbours(n.pos) {
+			cost := n.cost + f.distance(n.pos, next) + f.penalty(next)
+			if existing, ok := f.nodes[next]; ok {
+				if existing.closed || cost >= existing.cost {
+					continue
+				}
+				existing.parent, existing.cost = n, cost
+				heap.Fix(&f.open, existing.index)
+				continue
+			}
+			node := &pathNode{pos: next, parent: n, cost: cost, heuristic: f.distance(next, f.end)}
+			f.nodes[next] = node
+			heap.Push(&f.open, node)
+		}
+	}
+	if closest == first {
+		return nil, false
+	}
+	return f.path(closest), false
+}
+
+// path reconstructs the Path leading up to the pathNode passed.
+func (f *pathFinder) path(n *pathNode) *Path {
+	var points []cube.Pos
+	for ; n.parent != nil; n = n.parent {
+		points = append(points, n.pos)
+	}
+	for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+	return &Path{points: points}
+}
+
+// neighbours returns all positions that an entity standing at the position
+// passed can move to directly.
+func (f *pathFinder) neighbours(pos cube.Pos) []cube.Pos {
+	neighbours := make([]cube.Pos, 0, 8)
+	open := make(map[cube.Direction]bool, 4)
+	for _, d := range cube.Directions() {
+		next, ok := f.step(pos, pos.Side(d.Face()))
+		if ok {
+			neighbours = append(neighbours, next)
+			open[d] = next[1] == pos[1]
+		}
+	}
+	// Diagonal movement is only possible if both sides next to it are open,
+	// so that the entity does not clip the corners of blocks.
+	for _, a := range []cube.Direction{cube.North, cube.South} {
+		for _, b := range []cube.Direction{cube.West, cube.East} {
+			if !open[a] || !open[b] {
+				continue
+			}
+			diagonal := pos.Side(a.Face()).Side(b.Face())
+			if next, ok := f.step(pos, diagonal); ok && next[1] == pos[1] {
+				neighbours = append(neighbours, next)
+			}
+		}
+	}
+	return neighbours
+}
+
+// step attempts to move from the position passed to the horizontally adjacent
+// position to. The position that the entity ends up at is returned, taking
+// into account jumping up a block and dropping down.
+func (f *pathFinder) step(from, to cube.Pos) (cube.Pos, bool) {
+	if abs(to[0]-f.start[0]) > f.conf.MaxDistance || abs(to[2]-f.start[2]) > f.conf.MaxDistance {
+		return to, false
+	}
+	if to[1] < f.w.Range()[0] || to[1] > f.w.Range()[1] {
+		return to, false
+	}
+	if !f.passable(to) {
+		// The entity might be able to jump up on the block if there is enough
+		// space above it, and above the position it jumps from.
+		up := to.Side(cube.FaceUp)
+		if f.passable(up) && f.standable(up) && f.passableAt(from.Add(cube.Pos{0, f.conf.Height})) {
+			return up, true
+		}
+		return to, false
+	}
+	if f.standable(to) {
+		return to, true
+	}
+	// There is no floor below the position, so the entity would fall down.
+	// Find the floor and make sure the fall is not too deep.
+	for i := 1; i <= f.conf.MaxFallDistance; i++ {
+		down := to.Sub(cube.Pos{0, i})
+		if !f.passable(down) {
+			return down, false
+		}
+		if f.standable(down) {
+			return down, true
+		}
+	}
+	return to, false
+}
+
+// passable checks if an entity is able to stand with its feet at the
+// position passed, meaning there is enough free space at the position and
+// above it.
+func (f *pathFinder) passable(pos cube.Pos) bool {
+	if !f.passableAt(pos) {
+		return false
+	}
+	// Fences and walls have a collision box extending above the block, so the
+	// position directly above them is blocked too.
+	below := pos.Side(cube.FaceDown)
+	if collisionHeight(f.w, below) > 1 {
+		return false
+	}
+	for i := 1; i < f.conf.Height; i++ {
+		if !f.passableAt(pos.Add(cube.Pos{0, i})) {
+			return false
+		}
+	}
+	return true
+}
+
+// passableAt checks if the single block at the position passed can be moved
+// through by an entity.
+func (f *pathFinder) passableAt(pos cube.Pos) bool {
+	if liq, ok := f.w.Liquid(pos); ok {
+		if _, lava := liq.(block.Lava); lava {
+			return false
+		}
+	}
+	switch b := f.w.Block(pos).(type) {
+	case block.WoodDoor:
+		return b.Open || f.conf.OpenDoors
+	case block.WoodFenceGate:
+		return b.Open
+	case block.WoodTrapdoor:
+		return b.Open
+	case block.Fire, block.Cactus:
+		return false
+	}
+	return collisionHeight(f.w, pos) <= 0.125
+}
+
+// standable checks if the position passed has a floor that an entity can
+// stand on, or is in water so that the entity can swim.
+func (f *pathFinder) standable(pos cube.Pos) bool {
+	if _, ok := f.w.Liquid(pos); ok {
+		return true
+	}
+	below := pos.Side(cube.FaceDown)
+	if _, ok := f.w.Block(below).(block.Cactus); ok {
+		return false
+	}
+	if _, ok := f.w.Liquid(below); ok {
+		return true
+	}
+	return collisionHeight(f.w, below) > 0
+}
+
+// penalty returns the additional cost of moving to the position passed.
+func (f *pathFinder) penalty(pos cube.Pos) float64 {
+	if _, ok := f.w.Liquid(pos); ok {
+		if f.conf.AvoidWater {
+			return 8
+		}
+		return 1
+	}
+	if b, ok := f.w.Block(pos).(block.WoodDoor); ok && !b.Open {
+		return 1
+	}
+	return 0
+}
+
+// distance returns the euclidean distance between two positions.
+func (f *pathFinder) distance(a, b cube.Pos) float64 {
+	return a.Vec3().Sub(b.Vec3()).Len()
+}
+
+// collisionHeight returns the height of the highest collision box of the block
+// at the position passed, relative to the bottom of the block. 0 is returned
+// if the block has no collision boxes.
+func collisionHeight(w *world.World, pos cube.Pos) float64 {
+	height := 0.0
+	for _, box := range w.Block(pos).Model().BBox(pos, w) {
+		height = math.Max(height, box.Max()[1])
+	}
+	return height
+}
+
+// abs returns the absolute value of an int.
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// pathQueue is a priority queue of pathNodes, ordered by their expected total
+// cost. It implements heap.Interface.
+type pathQueue []*pathNode
+
+func (q pathQueue) Len() int { return len(q) }
+func (q pathQueue) Less(i, j int) bool {
+	return q[i].cost+q[i].heuristic < q[j].cost+q[j].heuristic
+}
+func (q pathQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index, q[j].index = i, j
+}
+func (q *pathQueue) Push(x any) {
+	n := x.(*pathNode)
+	n.index = len(*q)
+	*q = append(*q, n)
+}
+func (q *pathQueue) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
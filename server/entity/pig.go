@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// NewPig creates a new pig at the position passed. Pigs are passive mobs that
+// wander around and follow players holding carrots, potatoes or beetroots.
+func NewPig(pos mgl64.Vec3) *Mob {
+	conf := pigConf
+	conf.Behaviour = MobBehaviourConfig{
+		Goals: []Goal{
+			SwimGoal{},
+			PanicGoal{Speed: 1.25},
+			&TemptGoal{Items: []world.Item{item.CarrotOnAStick{}, block.Carrot{}, block.Potato{}, item.Beetroot{}}, Speed: 1.2},
+			WanderGoal{Speed: 1},
+			&LookAtPlayerGoal{Distance: 6},
+			&RandomLookGoal{},
+		},
+		Path:  PathConfig{AvoidWater: true},
+		Drops: pigDrops,
+	}.New()
+	return conf.New(PigType{}, pos)
+}
+
+var pigConf = MobConfig{
+	MaxHealth:  10,
+	Speed:      0.125,
+	Experience: 2,
+	Category:   world.SpawnCategoryCreature,
+	Persistent: true,
+}
+
+// pigDrops returns the items dropped by a pig when it dies.
+func pigDrops(m *Mob) []item.Stack {
+	return []item.Stack{mobDrop(item.Porkchop{Cooked: m.OnFireDuration() > 0}, 1, 3)}
+}
+
+// PigType is a world.EntityType implementation for pigs.
+type PigType struct{}
+
+func (PigType) EncodeEntity() string { return "minecraft:pig" }
+func (PigType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.45, 0, -0.45, 0.45, 0.9, 0.45)
+}
+
+func (PigType) DecodeNBT(m map[string]any) world.Entity {
+	return decodeMobNBT(m, NewPig(nbtconv.Vec3(m, "Pos")))
+}
+
+func (PigType) EncodeNBT(e world.Entity) map[string]any {
+	return encodeMobNBT(e.(*Mob))
+}
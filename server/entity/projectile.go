@@ -3,6 +3,7 @@ package entity
 import (
 	"math"
 	"math/rand"
+	"slices"
 	"time"
 
 	"github.com/go-gl/mathgl/mgl64"
@@ -67,6 +68,15 @@ type ProjectileBehaviourConfig struct {
 	// multiplier such as 0.5 will reduce the projectile's velocity, but retain
 	// half of it after inverting the axis on which the projectile collided.
 	BlockCollisionVelocityMultiplier float64
+	// Piercing is the amount of entities that the projectile passes through
+	// before it is stopped by an entity. Entities that were already hit by
+	// the projectile are not hit again.
+	Piercing int
+	// SurviveEntityCollision specifies if a projectile with this
+	// ProjectileBehaviour should survive hitting an entity. If set to true,
+	// the projectile bounces off the entity it hits and is no longer able to
+	// hit any entities afterwards, like a trident.
+	SurviveEntityCollision bool
 	// DisablePickup specifies if picking up the projectile should be disabled,
 	// which is relevant in the case SurviveBlockCollision is set to true. Some
 	// projectiles, such as arrows, cannot be picked up if they are shot by
@@ -101,6 +111,9 @@ type ProjectileBehaviour struct {
 
 	collisionPos cube.Pos
 	collided     bool
+
+	hitEntities []world.Entity
+	bounced     bool
 }
 
 // Owner returns the owner of the projectile.
@@ -168,6 +181,12 @@ func (lt *ProjectileBehaviour) Tick(e *Ent) *Movement {
 		if l, ok := r.Entity().(Living); ok && lt.conf.Damage >= 0 {
 			lt.hitEntity(l, e, before, vel)
 		}
+		if lt.survivesEntityHit(e, r.Entity(), vel) {
+			if lt.conf.Hit != nil {
+				lt.conf.Hit(e, result)
+			}
+			return m
+		}
 	case trace.BlockResult:
 		bpos := r.BlockPosition()
 		if t, ok := w.Block(bpos).(block.TNT); ok && e.OnFireDuration() > 0 {
@@ -259,6 +278,26 @@ func (lt *ProjectileBehaviour) hitBlockSurviving(e *Ent, r trace.BlockResult, m
 	e.mu.Unlock()
 }
 
+// survivesEntityHit records the entity passed as hit by the projectile and
+// checks if the projectile survives hitting it, either by piercing through it
+// or by bouncing off it. The velocity of the projectile is updated accordingly.
+func (lt *ProjectileBehaviour) survivesEntityHit(e *Ent, hit world.Entity, vel mgl64.Vec3) bool {
+	lt.hitEntities = append(lt.hitEntities, hit)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(lt.hitEntities) <= lt.conf.Piercing {
+		e.vel = vel
+		return true
+	}
+	if lt.conf.SurviveEntityCollision {
+		lt.bounced = true
+		e.vel = mgl64.Vec3{vel[0] * -0.01, vel[1] * -0.1, vel[2] * -0.01}
+		return true
+	}
+	return false
+}
+
 // hitEntity is called when a projectile hits a Living. It deals damage to the
 // entity and knocks it back. Additionally, it applies any potion effects and
 // fire if applicable.
@@ -322,12 +361,13 @@ func (lt *ProjectileBehaviour) tickMovement(e *Ent) (*Movement, trace.Result) {
 }
 
 // ignores returns a function to ignore entities in trace.Perform that are
-// either a spectator, not living, the entity itself or its owner in the first
-// 5 ticks.
+// either a spectator, not living, the entity itself, its owner in the first
+// 5 ticks or an entity that was already hit. If the projectile bounced off an
+// entity, all entities are ignored.
 func (lt *ProjectileBehaviour) ignores(e *Ent) func(other world.Entity) bool {
 	return func(other world.Entity) (ignored bool) {
 		g, ok := other.(interface{ GameMode() world.GameMode })
 		_, living := other.(Living)
-		return (ok && !g.GameMode().HasCollision()) || e == other || !living || (e.age < time.Second/4 && lt.owner == other)
+		return (ok && !g.GameMode().HasCollision()) || e == other || !living || (e.age < time.Second/4 && lt.owner == other) || lt.bounced || slices.Contains(lt.hitEntities, other)
 	}
 }
@@ -16,19 +16,34 @@ import (
 var DefaultRegistry = conf.New([]world.EntityType{
 	AreaEffectCloudType{},
 	ArrowType{},
+	BoatType{},
 	BottleOfEnchantingType{},
+	ChickenType{},
+	CowType{},
+	CreeperType{},
 	EggType{},
 	EnderPearlType{},
 	ExperienceOrbType{},
 	FallingBlockType{},
 	FireworkType{},
+	FishingHookType{},
 	ItemType{},
 	LightningType{},
 	LingeringPotionType{},
+	MinecartType{kind: item.RegularMinecart()},
+	MinecartType{kind: item.ChestMinecart()},
+	MinecartType{kind: item.HopperMinecart()},
+	MinecartType{kind: item.TNTMinecart()},
+	PigType{},
+	SheepType{},
+	SkeletonType{},
 	SnowballType{},
 	SplashPotionType{},
 	TNTType{},
 	TextType{},
+	TridentType{},
+	VillagerType{},
+	ZombieType{},
 })
 
 var conf = world.EntityRegistryConfig{
@@ -48,7 +63,7 @@ var conf = world.EntityRegistryConfig{
 		b.vel = vel
 		return b
 	},
-	Arrow: func(pos, vel mgl64.Vec3, rot cube.Rotation, damage float64, owner world.Entity, critical, disallowPickup, obtainArrowOnPickup bool, punchLevel int, tip any) world.Entity {
+	Arrow: func(pos, vel mgl64.Vec3, rot cube.Rotation, damage float64, owner world.Entity, critical, disallowPickup, obtainArrowOnPickup bool, punchLevel, piercingLevel int, tip any) world.Entity {
 		a := NewTippedArrowWithDamage(pos, rot, damage, owner, tip.(potion.Potion))
 		b := a.conf.Behaviour.(*ProjectileBehaviour)
 		b.conf.KnockBackForceAddend = float64(punchLevel) * (enchantment.Punch{}).KnockBackMultiplier()
@@ -57,6 +72,7 @@ var conf = world.EntityRegistryConfig{
 			b.conf.PickupItem = item.NewStack(item.Arrow{Tip: tip.(potion.Potion)}, 1)
 		}
 		b.conf.Critical = critical
+		b.conf.Piercing = piercingLevel
 		a.vel = vel
 		return a
 	},
@@ -88,7 +104,24 @@ var conf = world.EntityRegistryConfig{
 		p.vel = vel
 		return p
 	},
+	Trident: func(pos, vel mgl64.Vec3, rot cube.Rotation, owner world.Entity, trident any, disallowPickup bool) world.Entity {
+		t := NewTrident(pos, rot, owner, trident.(item.Stack))
+		t.conf.Behaviour.(*TridentBehaviour).projectile.conf.DisablePickup = disallowPickup
+		t.vel = vel
+		return t
+	},
+	FishingHook: func(pos, vel mgl64.Vec3, owner world.Entity, rod any) world.Entity {
+		h := NewFishingHook(pos, owner, rod.(item.Stack))
+		h.vel = vel
+		return h
+	},
 	Lightning: func(pos mgl64.Vec3) world.Entity {
 		return NewLightning(pos)
 	},
+	Boat: func(pos mgl64.Vec3, yaw float64, t any) world.Entity {
+		return NewBoat(pos, yaw, t.(item.BoatType))
+	},
+	Minecart: func(pos mgl64.Vec3, t any) world.Entity {
+		return NewMinecart(pos, t.(item.MinecartType))
+	},
 }
@@ -0,0 +1,123 @@
+package entity
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// Rideable represents an entity that may be ridden by other entities, such as a boat or a minecart.
+type Rideable interface {
+	world.Entity
+	// Riders returns the entities currently riding the Rideable, ordered by the seat they occupy. The first
+	// rider, if any, is the driver of the Rideable.
+	Riders() []world.Entity
+	// AddRider adds an entity to the first free seat of the Rideable. False is returned if the entity could not
+	// be added, for example because all seats are taken.
+	AddRider(e world.Entity) bool
+	// RemoveRider removes an entity from the seat it occupies. Riders behind it move up one seat.
+	RemoveRider(e world.Entity)
+	// SeatOffset returns the offset from the position of the Rideable at which the rider passed is positioned.
+	SeatOffset(e world.Entity) mgl64.Vec3
+}
+
+// Driveable represents a Rideable that may be steered by its driver, the entity in its first seat.
+type Driveable interface {
+	Rideable
+	// Drive steers the Driveable using the input of the driver passed. forward and strafe are values in the
+	// range [-1, 1], where forward is positive when moving forward and strafe is positive when moving to the
+	// left. yaw is the yaw of the driver in degrees.
+	Drive(driver world.Entity, forward, strafe, yaw float64)
+}
+
+// Rider represents an entity that is able to ride a Rideable.
+type Rider interface {
+	world.Entity
+	// Riding returns the Rideable that the Rider is currently riding. If it is not riding anything, false is
+	// returned.
+	Riding() (Rideable, bool)
+	// Mount makes the Rider start riding the Rideable passed. False is returned if the Rider could not mount it.
+	Mount(r Rideable) bool
+	// Dismount makes the Rider stop riding the Rideable that it is currently riding, if any.
+	Dismount()
+}
+
+// seats holds the riders of a Rideable. It may be embedded by a Rideable to implement its Riders, AddRider and
+// RemoveRider methods.
+type seats struct {
+	mu     sync.Mutex
+	n      int
+	riders []world.Entity
+}
+
+// newSeats creates seats that hold up to n riders.
+func newSeats(n int) *seats {
+	return &seats{n: n}
+}
+
+// Riders ...
+func (s *seats) Riders() []world.Entity {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.riders)
+}
+
+// AddRider ...
+func (s *seats) AddRider(e world.Entity) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.riders) >= s.n || slices.Contains(s.riders, e) {
+		return false
+	}
+	s.riders = append(s.riders, e)
+	return true
+}
+
+// RemoveRider ...
+func (s *seats) RemoveRider(e world.Entity) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.riders = slices.DeleteFunc(s.riders, func(rider world.Entity) bool {
+		return rider == e
+	})
+}
+
+// seat returns the index of the seat occupied by the entity passed, or -1 if it is not riding.
+func (s *seats) seat(e world.Entity) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Index(s.riders, e)
+}
+
+// driver returns the entity in the first seat. False is returned if no entity is riding.
+func (s *seats) driver() (world.Entity, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.riders) == 0 {
+		return nil, false
+	}
+	return s.riders[0], true
+}
+
+// dismountAll makes all riders dismount. Riders that do not implement Rider are removed from their seats
+// directly.
+func (s *seats) dismountAll() {
+	for _, e := range s.Riders() {
+		if r, ok := e.(Rider); ok {
+			r.Dismount()
+		}
+		s.RemoveRider(e)
+	}
+}
+
+// dismountRemoved makes riders dismount that are no longer in the world passed, for example because they were
+// removed or travelled to another world.
+func (s *seats) dismountRemoved(w *world.World) {
+	for _, e := range s.Riders() {
+		if e.World() != w {
+			s.RemoveRider(e)
+		}
+	}
+}
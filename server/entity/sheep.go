@@ -0,0 +1,200 @@
+package entity
+
+import (
+	"math/rand"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/particle"
+)
+
+// NewSheep creates a new sheep at the position passed with a random natural
+// wool colour. Sheep are passive mobs that eat grass to regrow their wool
+// after being sheared.
+func NewSheep(pos mgl64.Vec3) *Mob {
+	b := &SheepBehaviour{colour: randomSheepColour()}
+	b.MobBehaviour = MobBehaviourConfig{
+		Goals: []Goal{
+			SwimGoal{},
+			PanicGoal{Speed: 1.25},
+			&TemptGoal{Items: []world.Item{item.Wheat{}}, Speed: 1.1},
+			&sheepEatGrassGoal{b: b},
+			WanderGoal{Speed: 1},
+			&LookAtPlayerGoal{Distance: 6},
+			&RandomLookGoal{},
+		},
+		Path:  PathConfig{AvoidWater: true},
+		Drops: b.drops,
+	}.New()
+
+	conf := sheepConf
+	conf.Behaviour = b
+	return conf.New(SheepType{}, pos)
+}
+
+var sheepConf = MobConfig{
+	MaxHealth:  8,
+	Speed:      0.115,
+	Experience: 2,
+	Category:   world.SpawnCategoryCreature,
+	Persistent: true,
+}
+
+// randomSheepColour returns a random wool colour for a sheep, with the same
+// distribution as sheep spawned naturally.
+func randomSheepColour() item.Colour {
+	switch n := rand.Intn(100000); {
+	case n < 5000:
+		return item.ColourBlack()
+	case n < 10000:
+		return item.ColourGrey()
+	case n < 15000:
+		return item.ColourLightGrey()
+	case n < 18000:
+		return item.ColourBrown()
+	case n < 18164:
+		return item.ColourPink()
+	}
+	return item.ColourWhite()
+}
+
+// SheepBehaviour implements the behaviour of a sheep, of which the wool may
+// be sheared.
+type SheepBehaviour struct {
+	*MobBehaviour
+	colour  item.Colour
+	sheared bool
+}
+
+// Colour returns the colour of the wool of the sheep.
+func (b *SheepBehaviour) Colour() item.Colour {
+	return b.colour
+}
+
+// SetColour changes the colour of the wool of the sheep.
+func (b *SheepBehaviour) SetColour(m *Mob, c item.Colour) {
+	b.colour = c
+	m.updateState()
+}
+
+// Sheared checks if the sheep has been sheared. Sheared sheep do not have any
+// wool until they eat grass.
+func (b *SheepBehaviour) Sheared() bool {
+	return b.sheared
+}
+
+// Shear shears the sheep, dropping 1-3 wool of its colour. False is returned if
+// the sheep was already sheared.
+func (b *SheepBehaviour) Shear(m *Mob) bool {
+	if b.sheared {
+		return false
+	}
+	b.sheared = true
+	m.updateState()
+
+	w, pos := m.World(), m.Position()
+	for i := 0; i < 1+rand.Intn(3); i++ {
+		ent := NewItem(item.NewStack(block.Wool{Colour: b.colour}, 1), pos.Add(mgl64.Vec3{0, 1}))
+		ent.SetVelocity(mgl64.Vec3{rand.Float64()*0.2 - 0.1, 0.1 + rand.Float64()*0.05, rand.Float64()*0.2 - 0.1})
+		w.AddEntity(ent)
+	}
+	return true
+}
+
+// drops returns the items dropped by the sheep when it dies.
+func (b *SheepBehaviour) drops(m *Mob) []item.Stack {
+	drops := []item.Stack{mobDrop(item.Mutton{Cooked: m.OnFireDuration() > 0}, 1, 2)}
+	if !b.sheared {
+		drops = append(drops, item.NewStack(block.Wool{Colour: b.colour}, 1))
+	}
+	return drops
+}
+
+// sheepEatGrassGoal makes a sheep eat grass every now and then, regrowing its
+// wool if it was sheared.
+type sheepEatGrassGoal struct {
+	b     *SheepBehaviour
+	ticks int
+}
+
+func (*sheepEatGrassGoal) Flags() GoalFlag {
+	return GoalFlagMove | GoalFlagLook | GoalFlagJump
+}
+func (g *sheepEatGrassGoal) CanStart(m *Mob) bool {
+	return rand.Intn(1000) == 0 && g.edible(m) != nil
+}
+func (g *sheepEatGrassGoal) CanContinue(*Mob) bool { return g.ticks > 0 }
+func (g *sheepEatGrassGoal) Start(m *Mob) {
+	g.ticks = 40
+	m.StopNavigating()
+	for _, v := range m.World().Viewers(m.Position()) {
+		v.ViewEntityAction(m, EatGrassAction{})
+	}
+}
+func (g *sheepEatGrassGoal) Stop(*Mob) { g.ticks = 0 }
+func (g *sheepEatGrassGoal) Tick(m *Mob) {
+	if g.ticks--; g.ticks != 4 {
+		return
+	}
+	pos := g.edible(m)
+	if pos == nil {
+		return
+	}
+	w := m.World()
+	b := w.Block(*pos)
+	w.AddParticle(pos.Vec3Centre(), particle.BlockBreak{Block: b})
+	if _, ok := b.(block.Grass); ok {
+		w.SetBlock(*pos, block.Dirt{}, nil)
+	} else {
+		w.SetBlock(*pos, nil, nil)
+	}
+	if g.b.sheared {
+		g.b.sheared = false
+		m.updateState()
+	}
+}
+
+// edible returns the position of the grass that the sheep would eat, or nil
+// if there is no grass to eat.
+func (g *sheepEatGrassGoal) edible(m *Mob) *cube.Pos {
+	w, pos := m.World(), cube.PosFromVec3(m.Position())
+	if _, ok := w.Block(pos).(block.TallGrass); ok {
+		return &pos
+	}
+	below := pos.Side(cube.FaceDown)
+	if _, ok := w.Block(below).(block.Grass); ok {
+		return &below
+	}
+	return nil
+}
+
+// SheepType is a world.EntityType implementation for sheep.
+type SheepType struct{}
+
+func (SheepType) EncodeEntity() string { return "minecraft:sheep" }
+func (SheepType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.45, 0, -0.45, 0.45, 1.3, 0.45)
+}
+
+func (SheepType) DecodeNBT(m map[string]any) world.Entity {
+	s := decodeMobNBT(m, NewSheep(nbtconv.Vec3(m, "Pos")))
+	b := s.Behaviour().(*SheepBehaviour)
+	if _, ok := m["Color"]; ok {
+		b.colour = item.Colours()[nbtconv.Uint8(m, "Color")%16]
+	}
+	b.sheared = nbtconv.Bool(m, "Sheared")
+	return s
+}
+
+func (SheepType) EncodeNBT(e world.Entity) map[string]any {
+	s := e.(*Mob)
+	b := s.Behaviour().(*SheepBehaviour)
+	data := encodeMobNBT(s)
+	data["Color"] = b.colour.Uint8()
+	data["Sheared"] = boolByte(b.sheared)
+	return data
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// NewSkeleton creates a new skeleton at the position passed. Skeletons are
+// hostile mobs that shoot arrows at players from a distance using the bow
+// they hold. They burn in daylight.
+func NewSkeleton(pos mgl64.Vec3) *Mob {
+	conf := skeletonConf
+	conf.Behaviour = MobBehaviourConfig{
+		Goals: []Goal{
+			SwimGoal{},
+			&RangedAttackGoal{Speed: 1, Interval: 40, Range: 15},
+			WanderGoal{Speed: 1},
+			&LookAtPlayerGoal{Distance: 8},
+			&RandomLookGoal{},
+		},
+		TargetGoals: []Goal{
+			HurtByTargetGoal{},
+			NearestPlayerTargetGoal{Distance: 16},
+		},
+		Tick:  burnInDaylight,
+		Drops: skeletonDrops,
+	}.New()
+	m := conf.New(SkeletonType{}, pos)
+	m.mainHand = item.NewStack(item.Bow{}, 1)
+	return m
+}
+
+var skeletonConf = MobConfig{
+	MaxHealth:  20,
+	Speed:      0.125,
+	Experience: 5,
+	Category:   world.SpawnCategoryMonster,
+}
+
+// skeletonDrops returns the items dropped by a skeleton when it dies.
+func skeletonDrops(*Mob) []item.Stack {
+	return []item.Stack{mobDrop(item.Bone{}, 0, 2), mobDrop(item.Arrow{}, 0, 2)}
+}
+
+// SkeletonType is a world.EntityType implementation for skeletons.
+type SkeletonType struct{}
+
+func (SkeletonType) EncodeEntity() string { return "minecraft:skeleton" }
+func (SkeletonType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.3, 0, -0.3, 0.3, 1.99, 0.3)
+}
+
+func (SkeletonType) DecodeNBT(m map[string]any) world.Entity {
+	return decodeMobNBT(m, NewSkeleton(nbtconv.Vec3(m, "Pos")))
+}
+
+func (SkeletonType) EncodeNBT(e world.Entity) map[string]any {
+	return encodeMobNBT(e.(*Mob))
+}
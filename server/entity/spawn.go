@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/biome"
+)
+
+// SpawnEntries returns the world.SpawnEntries of the mobs implemented in this
+// package that spawn naturally in the world.Biome passed. It may be used as
+// the Entries function of a world.SpawnConfig to enable vanilla spawning in a
+// world.World.
+func SpawnEntries(b world.Biome) []world.SpawnEntry {
+	switch b.(type) {
+	case biome.NetherWastes, biome.CrimsonForest, biome.WarpedForest, biome.SoulSandValley, biome.BasaltDeltas,
+		biome.End, biome.MushroomFields, biome.MushroomFieldShore, biome.DeepDark:
+		return nil
+	case biome.Ocean, biome.DeepOcean, biome.ColdOcean, biome.DeepColdOcean, biome.LukewarmOcean,
+		biome.DeepLukewarmOcean, biome.WarmOcean, biome.DeepWarmOcean, biome.FrozenOcean, biome.DeepFrozenOcean,
+		biome.LegacyFrozenOcean, biome.River, biome.FrozenRiver, biome.Beach, biome.SnowyBeach, biome.StonyShore,
+		biome.Desert, biome.DesertHills, biome.DesertLakes, biome.Badlands, biome.BadlandsPlateau,
+		biome.ErodedBadlands, biome.ModifiedBadlandsPlateau, biome.WoodedBadlandsPlateau,
+		biome.ModifiedWoodedBadlandsPlateau, biome.SnowyPlains, biome.IceSpikes, biome.SnowySlopes,
+		biome.FrozenPeaks, biome.JaggedPeaks, biome.StonyPeaks, biome.Grove, biome.SnowyMountains,
+		biome.DripstoneCaves, biome.LushCaves:
+		return monsterEntries
+	}
+	return defaultEntries
+}
+
+var (
+	// monsterEntries holds the spawn entries of the monsters that spawn in
+	// nearly every biome of the overworld.
+	monsterEntries = []world.SpawnEntry{
+		mobSpawnEntry(world.SpawnCategoryMonster, 95, 4, 4, NewZombie),
+		mobSpawnEntry(world.SpawnCategoryMonster, 100, 4, 4, NewSkeleton),
+		mobSpawnEntry(world.SpawnCategoryMonster, 100, 4, 4, NewCreeper),
+	}
+	// defaultEntries holds the spawn entries of biomes with grass, in which
+	// animals spawn as well as monsters.
+	defaultEntries = append([]world.SpawnEntry{
+		mobSpawnEntry(world.SpawnCategoryCreature, 12, 4, 4, NewSheep),
+		mobSpawnEntry(world.SpawnCategoryCreature, 10, 4, 4, NewPig),
+		mobSpawnEntry(world.SpawnCategoryCreature, 10, 4, 4, NewChicken),
+		mobSpawnEntry(world.SpawnCategoryCreature, 8, 4, 4, NewCow),
+	}, monsterEntries...)
+)
+
+// mobSpawnEntry creates a world.SpawnEntry for a Mob created using the
+// function passed. Animals only spawn on grass.
+func mobSpawnEntry(c world.SpawnCategory, weight, minGroup, maxGroup int, f func(pos mgl64.Vec3) *Mob) world.SpawnEntry {
+	entry := world.SpawnEntry{Category: c, Weight: weight, MinGroup: minGroup, MaxGroup: maxGroup, New: func(pos mgl64.Vec3) world.Entity {
+		return f(pos)
+	}}
+	if c == world.SpawnCategoryCreature {
+		entry.Spawnable = func(w *world.World, pos cube.Pos) bool {
+			_, grass := w.Block(pos.Side(cube.FaceDown)).(block.Grass)
+			return grass
+		}
+	}
+	return entry
+}
@@ -0,0 +1,4 @@
+// Package trade implements trade offers and merchants, such as villagers, whose offers may be traded by players
+// in a trading window. Custom merchants, for example for shops run by NPCs, may be created using NewShop or by
+// implementing the Merchant interface.
+package trade
@@ -0,0 +1,88 @@
+package trade
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// Merchant is a source of trade offers, such as a villager. The offers of a Merchant are shown to a player in a
+// trading window, which may be opened using player.Player.OpenTrade.
+type Merchant interface {
+	// Name returns the name displayed at the top of the trading window of the Merchant. Translation keys, such
+	// as "entity.villager.farmer", are translated by the client.
+	Name() string
+	// Tier returns the current tier of the Merchant, ranging from 0 to MaxTier. Offers with a tier higher than
+	// the tier of the Merchant are shown as locked.
+	Tier() int
+	// Offers returns the offers of the Merchant. The index of an Offer in the slice returned is used to
+	// identify it when it is traded.
+	Offers() []Offer
+	// Trade is called when the trader passed trades the Offer at the index passed. The trader has paid the
+	// price of the Offer when Trade is called. Trade returns false if the Offer could not be traded, for
+	// example because it was disabled, in which case the trade is cancelled.
+	Trade(trader world.Entity, index int) bool
+}
+
+// Shop is a Merchant with a fixed set of offers, which may be used to create custom merchants such as shops
+// run by NPCs. The offers of a Shop are only restocked when Restock is called. Offers are unlocked regardless
+// of their tier.
+type Shop struct {
+	name string
+
+	mu     sync.Mutex
+	offers []Offer
+}
+
+// NewShop creates a new Shop with the name and offers passed. The name is displayed at the top of the trading
+// window.
+func NewShop(name string, offers ...Offer) *Shop {
+	return &Shop{name: name, offers: slices.Clone(offers)}
+}
+
+// Name returns the name of the Shop passed to NewShop.
+func (s *Shop) Name() string {
+	return s.name
+}
+
+// Tier always returns MaxTier, so that all offers of the Shop are unlocked.
+func (s *Shop) Tier() int {
+	return MaxTier
+}
+
+// Offers returns the current offers of the Shop.
+func (s *Shop) Offers() []Offer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.offers)
+}
+
+// SetOffers replaces the offers of the Shop with the offers passed. Players that currently have the trading
+// window of the Shop opened see the new offers once the window is reopened.
+func (s *Shop) SetOffers(offers ...Offer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offers = slices.Clone(offers)
+}
+
+// Trade increases the uses of the Offer at the index passed. False is returned if no Offer exists at the index
+// or if it is disabled.
+func (s *Shop) Trade(_ world.Entity, index int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if index < 0 || index >= len(s.offers) || s.offers[index].Disabled() {
+		return false
+	}
+	s.offers[index].Uses++
+	return true
+}
+
+// Restock restocks all offers of the Shop, resetting their uses.
+func (s *Shop) Restock() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, o := range s.offers {
+		s.offers[i] = o.Restock()
+	}
+}
@@ -0,0 +1,76 @@
+package trade
+
+import (
+	"math"
+
+	"github.com/stcraft/dragonfly/server/item"
+)
+
+// MaxTier is the highest tier that a Merchant may reach. Tiers range from 0 (novice) to MaxTier (master).
+const MaxTier = 4
+
+// tierExperience holds the total amount of experience that a Merchant must have collected to reach each
+// tier.
+var tierExperience = [MaxTier + 1]int{0, 10, 70, 150, 250}
+
+// TierExperience returns the total amount of experience that a Merchant must have collected to reach the tier
+// passed. The tier passed is clamped between 0 and MaxTier.
+func TierExperience(tier int) int {
+	return tierExperience[max(0, min(tier, MaxTier))]
+}
+
+// Offer is a trade offered by a Merchant. A player may pay the input items of an Offer to receive its output.
+type Offer struct {
+	// Input is the first item that must be paid for the Offer. Its count is the base price of the Offer, before
+	// the demand of the Offer is taken into account.
+	Input item.Stack
+	// SecondInput is a second item that must be paid for the Offer. SecondInput may be left empty if only one
+	// item needs to be paid.
+	SecondInput item.Stack
+	// Output is the item that the player receives when trading the Offer.
+	Output item.Stack
+
+	// Tier is the tier that the Merchant must have reached for the Offer to be unlocked.
+	Tier int
+	// MaxUses is the amount of times that the Offer may be traded before it is disabled. If 0, the Offer may
+	// be traded an unlimited amount of times.
+	MaxUses int
+	// Uses is the amount of times that the Offer has been traded since the Merchant last restocked.
+	Uses int
+	// Experience is the amount of experience that the Merchant collects every time the Offer is traded.
+	Experience int
+	// RewardExperience specifies if the player receives experience orbs when it trades the Offer.
+	RewardExperience bool
+	// PriceMultiplier is the factor with which the Demand of the Offer changes the price of its Input.
+	PriceMultiplier float64
+	// Demand is the demand for the Offer. A positive demand increases the price of the Offer. The demand of an
+	// Offer changes when its Merchant restocks.
+	Demand int
+}
+
+// Disabled checks if the Offer has been traded MaxUses times, so that it may no longer be traded until the
+// Merchant restocks.
+func (o Offer) Disabled() bool {
+	return o.MaxUses > 0 && o.Uses >= o.MaxUses
+}
+
+// Price returns the first input that must be paid for the Offer, with its count adjusted to the Demand of the
+// Offer. The count of the stack returned is at least 1 and at most the max count of the item.
+func (o Offer) Price() item.Stack {
+	if o.Input.Empty() {
+		return o.Input
+	}
+	count := o.Input.Count()
+	count += max(0, int(math.Floor(float64(count*o.Demand)*o.PriceMultiplier)))
+	return o.Input.Grow(max(1, min(count, o.Input.MaxCount())) - o.Input.Count())
+}
+
+// Restock resets the uses of the Offer and updates its demand based on the amount of uses since the last
+// restock. Offers without a MaxUses keep their demand.
+func (o Offer) Restock() Offer {
+	if o.MaxUses > 0 {
+		o.Demand += o.Uses - (o.MaxUses - o.Uses)
+	}
+	o.Uses = 0
+	return o
+}
@@ -0,0 +1,216 @@
+package entity
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/block/cube/trace"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/item/enchantment"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/sound"
+)
+
+// NewTrident creates a thrown trident entity from the trident item stack passed. The trident may be picked up
+// again once it lands, after which the stack passed is added to the inventory of the entity picking it up.
+func NewTrident(pos mgl64.Vec3, rot cube.Rotation, owner world.Entity, trident item.Stack) *Ent {
+	b := &TridentBehaviour{trident: trident}
+	conf := tridentConf
+	conf.Hit = b.hit
+	conf.PickupItem = trident
+	b.projectile = conf.New(owner)
+
+	e := Config{Behaviour: b}.New(TridentType{}, pos)
+	e.rot = rot
+	return e
+}
+
+var tridentConf = ProjectileBehaviourConfig{
+	Gravity:                0.05,
+	Drag:                   0.01,
+	Damage:                 -1,
+	SurviveBlockCollision:  true,
+	SurviveEntityCollision: true,
+}
+
+// tridentDamage is the damage dealt by a thrown trident to the entity it hits.
+const tridentDamage = 8.0
+
+// TridentBehaviour implements the behaviour of a thrown trident. It behaves like a projectile until it hits an
+// entity or a block, after which a trident enchanted with loyalty returns to its owner.
+type TridentBehaviour struct {
+	projectile *ProjectileBehaviour
+	trident    item.Stack
+
+	landed, returning bool
+}
+
+// Owner returns the entity that threw the trident.
+func (t *TridentBehaviour) Owner() world.Entity {
+	return t.projectile.Owner()
+}
+
+// Item returns the trident item stack that the trident entity was created from.
+func (t *TridentBehaviour) Item() item.Stack {
+	return t.trident
+}
+
+// Returning checks if the trident is currently returning to its owner.
+func (t *TridentBehaviour) Returning() bool {
+	return t.returning
+}
+
+// Tick moves the trident. Once a trident enchanted with loyalty hits something, it starts flying back to its
+// owner.
+func (t *TridentBehaviour) Tick(e *Ent) *Movement {
+	if t.returning {
+		return t.tickReturning(e)
+	}
+	m := t.projectile.Tick(e)
+	if t.projectile.collided && !t.landed {
+		t.landed = true
+		e.World().PlaySound(e.Position(), sound.TridentHitGround{})
+	}
+	if (t.projectile.collided || t.projectile.bounced) && t.loyalty() > 0 && t.ownerPresent(e) {
+		t.returning = true
+		w := e.World()
+		w.PlaySound(e.Position(), sound.TridentReturn{})
+		for _, v := range w.Viewers(e.Position()) {
+			v.ViewEntityState(e)
+		}
+	}
+	return m
+}
+
+// tickReturning moves the trident towards its owner, passing through any blocks in its way. Once the trident
+// reaches its owner, it is picked up.
+func (t *TridentBehaviour) tickReturning(e *Ent) *Movement {
+	if !t.ownerPresent(e) {
+		// The owner is no longer around to catch the trident, so it simply drops down.
+		t.returning = false
+		return nil
+	}
+	owner, w := t.Owner(), e.World()
+	target := EyePosition(owner)
+
+	e.mu.Lock()
+	pos, vel := e.pos, e.vel
+	diff := target.Sub(pos)
+	if diff.Len() < 1 {
+		e.mu.Unlock()
+		t.pickup(e, owner)
+		return nil
+	}
+	newVel := vel.Mul(0.95).Add(diff.Normalize().Mul((enchantment.Loyalty{}).ReturnSpeed(t.loyalty())))
+	newPos := pos.Add(diff.Mul(0.015 * float64(t.loyalty()))).Add(newVel)
+	rot := cube.Rotation{mgl64.RadToDeg(math.Atan2(newVel[0], newVel[2])), 0}
+	e.pos, e.vel, e.rot = newPos, newVel, rot
+	e.mu.Unlock()
+
+	return &Movement{v: w.Viewers(pos), e: e, pos: newPos, vel: newVel, dpos: newPos.Sub(pos), dvel: newVel.Sub(vel), rot: rot}
+}
+
+// pickup makes the owner passed pick up the trident and closes the entity.
+func (t *TridentBehaviour) pickup(e *Ent, owner world.Entity) {
+	if c, ok := owner.(Collector); ok {
+		for _, v := range e.World().Viewers(e.Position()) {
+			v.ViewEntityAction(e, PickedUpAction{Collector: c})
+		}
+		if !t.projectile.conf.DisablePickup {
+			_ = c.Collect(t.trident)
+		}
+	}
+	_ = e.Close()
+}
+
+// hit is called when the trident hits an entity or a block. It damages the entity hit and summons lightning
+// if the trident is enchanted with channeling during a thunderstorm.
+func (t *TridentBehaviour) hit(e *Ent, target trace.Result) {
+	r, ok := target.(trace.EntityResult)
+	if !ok {
+		return
+	}
+	w := e.World()
+	if l, ok := r.Entity().(Living); ok {
+		dmg := tridentDamage
+		if i, ok := t.trident.Enchantment(enchantment.Impaling{}); ok && (enchantment.Impaling{}).Affects(l) {
+			dmg += (enchantment.Impaling{}).Addend(i.Level())
+		}
+		if _, vulnerable := l.Hurt(dmg, ProjectileDamageSource{Projectile: e, Owner: t.Owner()}); vulnerable {
+			l.KnockBack(r.Position(), 0.45, 0.3608)
+		}
+	}
+	w.PlaySound(r.Position(), sound.TridentHit{})
+
+	if _, ok := t.trident.Enchantment(enchantment.Channeling{}); ok {
+		pos := r.Entity().Position()
+		if w.ThunderingAt(cube.PosFromVec3(pos)) {
+			w.AddEntity(NewLightning(pos))
+			w.PlaySound(pos, sound.TridentThunder{})
+		}
+	}
+}
+
+// loyalty returns the level of the loyalty enchantment of the trident, or 0 if it does not have it.
+func (t *TridentBehaviour) loyalty() int {
+	if l, ok := t.trident.Enchantment(enchantment.Loyalty{}); ok {
+		return l.Level()
+	}
+	return 0
+}
+
+// ownerPresent checks if the owner of the trident is still alive and in the same world as the trident.
+func (t *TridentBehaviour) ownerPresent(e *Ent) bool {
+	owner := t.Owner()
+	if owner == nil || owner.World() != e.World() {
+		return false
+	}
+	if l, ok := owner.(Living); ok && l.Dead() {
+		return false
+	}
+	return true
+}
+
+// TridentType is a world.EntityType implementation for a thrown trident.
+type TridentType struct{}
+
+func (TridentType) EncodeEntity() string { return "minecraft:thrown_trident" }
+func (TridentType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.125, 0, -0.125, 0.125, 0.25, 0.125)
+}
+
+func (TridentType) DecodeNBT(m map[string]any) world.Entity {
+	trident := nbtconv.MapItem(m, "Trident")
+	if trident.Empty() {
+		trident = item.NewStack(item.Trident{}, 1)
+	}
+	t := NewTrident(nbtconv.Vec3(m, "Pos"), nbtconv.Rotation(m), nil, trident)
+	b := t.conf.Behaviour.(*TridentBehaviour)
+	t.vel = nbtconv.Vec3(m, "Motion")
+	b.projectile.conf.DisablePickup = !nbtconv.Bool(m, "player")
+	if _, ok := m["StuckToBlockPos"]; ok {
+		b.projectile.collisionPos = nbtconv.Pos(m, "StuckToBlockPos")
+		b.projectile.collided, b.landed = true, true
+	}
+	return t
+}
+
+func (TridentType) EncodeNBT(e world.Entity) map[string]any {
+	t := e.(*Ent)
+	b := t.conf.Behaviour.(*TridentBehaviour)
+	yaw, pitch := t.Rotation().Elem()
+	data := map[string]any{
+		"Pos":     nbtconv.Vec3ToFloat32Slice(t.Position()),
+		"Yaw":     float32(yaw),
+		"Pitch":   float32(pitch),
+		"Motion":  nbtconv.Vec3ToFloat32Slice(t.Velocity()),
+		"Trident": nbtconv.WriteItem(b.trident, true),
+		"player":  boolByte(!b.projectile.conf.DisablePickup),
+	}
+	if b.projectile.collided {
+		data["StuckToBlockPos"] = nbtconv.PosToInt32Slice(b.projectile.collisionPos)
+	}
+	return data
+}
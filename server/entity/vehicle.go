@@ -0,0 +1,140 @@
+package entity
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// vehicle holds the state shared by the Rideable entities in this package, such as Boat and Minecart: Their
+// position, velocity and rotation, the riders in their seats and the damage they have taken.
+type vehicle struct {
+	*seats
+
+	mu  sync.Mutex
+	pos mgl64.Vec3
+	vel mgl64.Vec3
+	rot cube.Rotation
+
+	// damage is the damage that the vehicle has taken. It slowly decreases over time and the vehicle breaks
+	// once it exceeds vehicleMaxDamage.
+	damage float64
+	age    time.Duration
+
+	mc *MovementComputer
+}
+
+// vehicleMaxDamage is the damage that a vehicle can take before it breaks.
+const vehicleMaxDamage = 40
+
+// Position returns the current position of the vehicle.
+func (v *vehicle) Position() mgl64.Vec3 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.pos
+}
+
+// Velocity returns the current velocity of the vehicle. The values in the Vec3 returned represent the speed on
+// that axis in blocks/tick.
+func (v *vehicle) Velocity() mgl64.Vec3 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.vel
+}
+
+// SetVelocity sets the velocity of the vehicle. The values in the Vec3 passed represent the speed on that axis
+// in blocks/tick.
+func (v *vehicle) SetVelocity(vel mgl64.Vec3) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.vel = vel
+}
+
+// Rotation returns the rotation of the vehicle.
+func (v *vehicle) Rotation() cube.Rotation {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.rot
+}
+
+// Age returns the total time lived of the vehicle. It increases by time.Second/20 for every time it is ticked.
+func (v *vehicle) Age() time.Duration {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.age
+}
+
+// OnGround checks if the vehicle is currently on the ground.
+func (v *vehicle) OnGround() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.mc.OnGround()
+}
+
+// teleport teleports the vehicle e to the position passed, immediately changing its position for viewers.
+func (v *vehicle) teleport(e world.Entity, pos mgl64.Vec3) {
+	if w := e.World(); w != nil {
+		for _, viewer := range w.Viewers(v.Position()) {
+			viewer.ViewEntityTeleport(e, pos)
+		}
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.pos, v.vel = pos, mgl64.Vec3{}
+}
+
+// tickDamage slowly repairs the damage that the vehicle has taken and advances its age.
+func (v *vehicle) tickDamage() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.damage = math.Max(v.damage-1, 0)
+	v.age += time.Second / 20
+}
+
+// hurt deals damage to the vehicle e. Vehicles break instantly if attacked by a player in creative mode, in which
+// case they do not drop anything. True is returned if the vehicle should break as a result of the damage, along
+// with a bool indicating if it should drop its items.
+func (v *vehicle) hurt(e world.Entity, dmg float64, src world.DamageSource) (broken, drops bool) {
+	if s, ok := src.(AttackDamageSource); ok {
+		if g, ok := s.Attacker.(interface{ GameMode() world.GameMode }); ok && g.GameMode().CreativeInventory() {
+			return true, false
+		}
+	}
+	v.mu.Lock()
+	v.damage += dmg * 10
+	broken = v.damage > vehicleMaxDamage
+	v.mu.Unlock()
+
+	for _, viewer := range e.World().Viewers(v.Position()) {
+		viewer.ViewEntityAction(e, HurtAction{})
+	}
+	return broken, broken
+}
+
+// destroy makes all riders of the vehicle e dismount, drops the item stacks passed and removes the vehicle from
+// its world.
+func (v *vehicle) destroy(e world.Entity, drops ...item.Stack) {
+	v.dismountAll()
+	w, pos := e.World(), v.Position()
+	for _, it := range drops {
+		if it.Empty() {
+			continue
+		}
+		ent := NewItem(it, pos)
+		ent.SetVelocity(mgl64.Vec3{rand.Float64()*0.2 - 0.1, 0.2, rand.Float64()*0.2 - 0.1})
+		w.AddEntity(ent)
+	}
+	_ = e.Close()
+}
+
+// yawDirection returns the horizontal direction vector of the yaw passed in degrees.
+func yawDirection(yaw float64) mgl64.Vec3 {
+	rad := mgl64.DegToRad(yaw)
+	return mgl64.Vec3{-math.Sin(rad), 0, math.Cos(rad)}
+}
@@ -0,0 +1,554 @@
+package entity
+
+import (
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/entity/effect"
+	"github.com/stcraft/dragonfly/server/entity/trade"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// NewVillager creates a new villager with the profession passed at the position passed. Villagers are passive
+// mobs that trade with players. Villagers without a profession take the profession of the first unclaimed
+// workstation that they find.
+func NewVillager(pos mgl64.Vec3, profession VillagerProfession) *Mob {
+	b := &VillagerBehaviour{}
+	b.MobBehaviour = MobBehaviourConfig{
+		Goals: []Goal{
+			SwimGoal{},
+			PanicGoal{Speed: 1.5},
+			villagerTradeGoal{b: b},
+			&villagerClaimWorkstationGoal{b: b},
+			villagerWorkGoal{b: b},
+			WanderGoal{Speed: 0.8},
+			&LookAtPlayerGoal{Distance: 8},
+			&RandomLookGoal{},
+		},
+		Path:     PathConfig{AvoidWater: true},
+		Tick:     b.tick,
+		Interact: b.interact,
+	}.New()
+
+	conf := villagerConf
+	conf.Behaviour = b
+	m := conf.New(VillagerType{}, pos)
+	b.SetProfession(m, profession)
+	return m
+}
+
+var villagerConf = MobConfig{
+	MaxHealth:  20,
+	Speed:      0.1,
+	Category:   world.SpawnCategoryCreature,
+	Persistent: true,
+}
+
+// TradeOpener represents an entity that is able to open the trading window of a trade.Merchant, such as a
+// player.
+type TradeOpener interface {
+	// OpenTrade opens the trading window of the trade.Merchant passed. The entity passed is shown in the
+	// trading window.
+	OpenTrade(m trade.Merchant, e world.Entity)
+}
+
+// VillagerBehaviour implements the behaviour of a villager, which trades with
+// players if it has a profession.
+type VillagerBehaviour struct {
+	*MobBehaviour
+	mu sync.Mutex
+
+	profession VillagerProfession
+	tier       int
+	experience int
+	offers     []trade.Offer
+
+	jobSite    cube.Pos
+	hasJobSite bool
+	customer   world.Entity
+
+	restocks    int
+	restockDay  int
+	lastRestock time.Duration
+}
+
+// Profession returns the profession of the villager.
+func (b *VillagerBehaviour) Profession() VillagerProfession {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.profession
+}
+
+// SetProfession changes the profession of the villager. The tier and experience of the villager are reset and
+// its offers are replaced with new offers of the profession.
+func (b *VillagerBehaviour) SetProfession(m *Mob, p VillagerProfession) {
+	b.mu.Lock()
+	b.profession, b.tier, b.experience, b.offers = p, 0, 0, nil
+	b.addOffers(0)
+	b.mu.Unlock()
+	m.updateState()
+}
+
+// Tier returns the tier of the villager, ranging from 0 (novice) to trade.MaxTier (master).
+func (b *VillagerBehaviour) Tier() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tier
+}
+
+// TradeExperience returns the experience that the villager has collected by trading with players. The
+// villager reaches a new tier once its experience reaches trade.TierExperience.
+func (b *VillagerBehaviour) TradeExperience() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.experience
+}
+
+// Offers returns the current offers of the villager.
+func (b *VillagerBehaviour) Offers() []trade.Offer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return slices.Clone(b.offers)
+}
+
+// SetOffers replaces the offers of the villager with the offers passed.
+func (b *VillagerBehaviour) SetOffers(offers ...trade.Offer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.offers = slices.Clone(offers)
+}
+
+// Restock restocks all offers of the villager, resetting their uses and updating their demand.
+func (b *VillagerBehaviour) Restock() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, o := range b.offers {
+		b.offers[i] = o.Restock()
+	}
+}
+
+// JobSite returns the position of the workstation claimed by the villager. False is returned if the villager
+// has not claimed a workstation.
+func (b *VillagerBehaviour) JobSite() (cube.Pos, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.jobSite, b.hasJobSite
+}
+
+// Customer returns the entity that the villager is currently trading with, or nil if it is not trading.
+func (b *VillagerBehaviour) Customer() world.Entity {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.customer
+}
+
+// Variant returns the profession of the villager as the variant of the entity.
+func (b *VillagerBehaviour) Variant() int32 {
+	return int32(b.Profession().Uint8())
+}
+
+// Merchant returns the villager as a trade.Merchant, which may be passed to a TradeOpener to trade with the
+// villager.
+func (b *VillagerBehaviour) Merchant(m *Mob) trade.Merchant {
+	return villagerMerchant{m: m, b: b}
+}
+
+// interact opens the trading window of the villager for the user passed if the villager has a profession and
+// is not already trading with another entity.
+func (b *VillagerBehaviour) interact(m *Mob, user item.User) bool {
+	opener, ok := user.(TradeOpener)
+	if !ok {
+		return false
+	}
+	b.mu.Lock()
+	if !b.profession.Employed() || len(b.offers) == 0 || (b.customer != nil && b.customer != user) {
+		b.mu.Unlock()
+		return false
+	}
+	b.customer = user
+	b.mu.Unlock()
+
+	m.StopNavigating()
+	opener.OpenTrade(b.Merchant(m), m)
+	return true
+}
+
+// tick stops the villager from trading with customers that walked away and
+// makes it lose its workstation if it was removed.
+func (b *VillagerBehaviour) tick(m *Mob) {
+	w := m.World()
+	b.mu.Lock()
+	if c := b.customer; c != nil && (c.World() != w || c.Position().Sub(m.Position()).Len() > 8) {
+		b.customer = nil
+	}
+	lost := b.hasJobSite && m.Age()%time.Second == 0 && !b.profession.Workstation(w.Block(b.jobSite))
+	if lost {
+		b.hasJobSite = false
+	}
+	unemployed := lost && b.experience == 0
+	b.mu.Unlock()
+
+	if unemployed {
+		// Villagers that have never traded lose their profession when their workstation is removed.
+		b.SetProfession(m, ProfessionNone())
+	}
+}
+
+// addOffers adds two random trades of the tier passed for the profession of the villager to its offers.
+// addOffers must be called with b.mu locked.
+func (b *VillagerBehaviour) addOffers(tier int) {
+	pool := slices.Clone(villagerTrades[b.profession][tier])
+	rand.Shuffle(len(pool), func(i, j int) {
+		pool[i], pool[j] = pool[j], pool[i]
+	})
+	for _, t := range pool[:min(2, len(pool))] {
+		b.offers = append(b.offers, t.offer(tier))
+	}
+}
+
+// trade trades the offer at the index passed, increasing its uses and the experience of the villager. False is
+// returned if the offer could not be traded.
+func (b *VillagerBehaviour) trade(m *Mob, trader world.Entity, index int) bool {
+	b.mu.Lock()
+	if b.customer != trader || index < 0 || index >= len(b.offers) {
+		b.mu.Unlock()
+		return false
+	}
+	o := b.offers[index]
+	if o.Disabled() || o.Tier > b.tier {
+		b.mu.Unlock()
+		return false
+	}
+	b.offers[index].Uses++
+	b.experience += o.Experience
+
+	levelled := false
+	for b.tier < trade.MaxTier && b.experience >= trade.TierExperience(b.tier+1) {
+		b.tier++
+		b.addOffers(b.tier)
+		levelled = true
+	}
+	b.mu.Unlock()
+
+	if levelled {
+		m.AddEffect(effect.New(effect.Regeneration{}, 1, time.Second*10))
+	}
+	m.updateState()
+	return true
+}
+
+// stopTrading stops the villager from trading with the entity passed.
+func (b *VillagerBehaviour) stopTrading(trader world.Entity) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.customer == trader {
+		b.customer = nil
+	}
+}
+
+// claimable checks if the villager could claim the block passed as workstation. Villagers without a profession
+// may claim any workstation, while villagers with a profession only claim the workstation of their profession.
+// claimable must be called with b.mu locked.
+func (b *VillagerBehaviour) claimable(bl world.Block) bool {
+	if b.profession == ProfessionNone() {
+		_, ok := professionFromWorkstation(bl)
+		return ok
+	}
+	return b.profession.Workstation(bl)
+}
+
+// claim claims the workstation at the position passed, taking the profession of the workstation if the villager
+// did not yet have a profession.
+func (b *VillagerBehaviour) claim(m *Mob, pos cube.Pos) {
+	bl := m.World().Block(pos)
+	b.mu.Lock()
+	if b.hasJobSite || !b.claimable(bl) {
+		b.mu.Unlock()
+		return
+	}
+	b.jobSite, b.hasJobSite = pos, true
+	unemployed := b.profession == ProfessionNone()
+	b.mu.Unlock()
+
+	if unemployed {
+		p, _ := professionFromWorkstation(bl)
+		b.SetProfession(m, p)
+	}
+}
+
+// work makes the villager work at its workstation, restocking its offers if any of them were used. Villagers
+// restock at most twice a day.
+func (b *VillagerBehaviour) work(m *Mob) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if day := m.World().Time() / 24000; day != b.restockDay {
+		b.restockDay, b.restocks = day, 0
+	}
+	if b.restocks >= 2 || (b.restocks > 0 && m.Age()-b.lastRestock < time.Minute*2) {
+		return
+	}
+	if !slices.ContainsFunc(b.offers, func(o trade.Offer) bool { return o.Uses > 0 }) {
+		return
+	}
+	for i, o := range b.offers {
+		b.offers[i] = o.Restock()
+	}
+	b.restocks++
+	b.lastRestock = m.Age()
+}
+
+// villagerMerchant implements the trade.Merchant of a villager.
+type villagerMerchant struct {
+	m *Mob
+	b *VillagerBehaviour
+}
+
+// Name returns the name tag of the villager, or the name of its profession if it has no name tag.
+func (v villagerMerchant) Name() string {
+	if name := v.m.NameTag(); name != "" {
+		return name
+	}
+	return v.b.Profession().translation()
+}
+
+// Tier ...
+func (v villagerMerchant) Tier() int {
+	return v.b.Tier()
+}
+
+// Offers ...
+func (v villagerMerchant) Offers() []trade.Offer {
+	return v.b.Offers()
+}
+
+// Trade ...
+func (v villagerMerchant) Trade(trader world.Entity, index int) bool {
+	return v.b.trade(v.m, trader, index)
+}
+
+// StopTrading is called when the trader passed closes the trading window of the villager.
+func (v villagerMerchant) StopTrading(trader world.Entity) {
+	v.b.stopTrading(trader)
+}
+
+// villagerTradeGoal makes a villager stand still and look at the entity that it is trading with.
+type villagerTradeGoal struct {
+	b *VillagerBehaviour
+}
+
+func (villagerTradeGoal) Flags() GoalFlag           { return GoalFlagMove | GoalFlagLook }
+func (g villagerTradeGoal) CanStart(*Mob) bool      { return g.b.Customer() != nil }
+func (g villagerTradeGoal) CanContinue(m *Mob) bool { return g.CanStart(m) }
+func (villagerTradeGoal) Start(m *Mob)              { m.StopNavigating() }
+func (villagerTradeGoal) Stop(*Mob)                 {}
+func (g villagerTradeGoal) Tick(m *Mob) {
+	if c := g.b.Customer(); c != nil {
+		m.LookAt(EyePosition(c))
+	}
+}
+
+// villagerClaimWorkstationGoal makes a villager without a workstation walk to the closest unclaimed
+// workstation that it could claim, and claim it.
+type villagerClaimWorkstationGoal struct {
+	b      *VillagerBehaviour
+	target cube.Pos
+}
+
+func (*villagerClaimWorkstationGoal) Flags() GoalFlag { return GoalFlagMove }
+func (g *villagerClaimWorkstationGoal) CanStart(m *Mob) bool {
+	if rand.Intn(200) != 0 {
+		return false
+	}
+	if _, ok := g.b.JobSite(); ok || g.b.Profession() == ProfessionNitwit() {
+		return false
+	}
+	pos, ok := g.find(m)
+	g.target = pos
+	return ok
+}
+func (*villagerClaimWorkstationGoal) CanContinue(m *Mob) bool { return m.Navigating() }
+func (g *villagerClaimWorkstationGoal) Start(m *Mob) {
+	if !g.near(m) {
+		m.Navigate(g.target.Vec3Centre(), 1)
+	}
+}
+func (g *villagerClaimWorkstationGoal) Stop(m *Mob) {
+	m.StopNavigating()
+	if g.near(m) && !villagerClaimed(m, g.target) {
+		g.b.claim(m, g.target)
+	}
+}
+func (g *villagerClaimWorkstationGoal) Tick(m *Mob) {
+	if g.near(m) {
+		m.StopNavigating()
+	}
+}
+
+// near checks if the villager is close enough to its target to claim it.
+func (g *villagerClaimWorkstationGoal) near(m *Mob) bool {
+	return m.Position().Sub(g.target.Vec3Centre()).Len() <= 2.5
+}
+
+// find finds the closest unclaimed workstation within 16 blocks horizontally and 4 blocks vertically that the
+// villager could claim.
+func (g *villagerClaimWorkstationGoal) find(m *Mob) (cube.Pos, bool) {
+	w, origin := m.World(), cube.PosFromVec3(m.Position())
+	var (
+		closest cube.Pos
+		dist    = -1
+	)
+	g.b.mu.Lock()
+	for x := -16; x <= 16; x++ {
+		for y := -4; y <= 4; y++ {
+			for z := -16; z <= 16; z++ {
+				pos := origin.Add(cube.Pos{x, y, z})
+				if d := x*x + y*y + z*z; (dist == -1 || d < dist) && g.b.claimable(w.Block(pos)) {
+					closest, dist = pos, d
+				}
+			}
+		}
+	}
+	g.b.mu.Unlock()
+	return closest, dist != -1 && !villagerClaimed(m, closest)
+}
+
+// villagerClaimed checks if the workstation at the position passed has been claimed by a villager other than
+// the one passed.
+func villagerClaimed(m *Mob, pos cube.Pos) bool {
+	box := cube.Box(-64, -64, -64, 64, 64, 64).Translate(pos.Vec3())
+	for _, e := range m.World().EntitiesWithin(box, nil) {
+		other, ok := e.(*Mob)
+		if !ok || other == m {
+			continue
+		}
+		if b, ok := other.Behaviour().(*VillagerBehaviour); ok {
+			if site, ok := b.JobSite(); ok && site == pos {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// villagerWorkGoal makes a villager walk to its workstation every now and then during the day to work, which
+// restocks its offers.
+type villagerWorkGoal struct {
+	b *VillagerBehaviour
+}
+
+func (villagerWorkGoal) Flags() GoalFlag { return GoalFlagMove }
+func (g villagerWorkGoal) CanStart(m *Mob) bool {
+	_, ok := g.b.JobSite()
+	return ok && m.World().Time()%24000 < 12000 && rand.Intn(400) == 0
+}
+func (g villagerWorkGoal) CanContinue(m *Mob) bool { return m.Navigating() && !g.near(m) }
+func (g villagerWorkGoal) Start(m *Mob) {
+	if site, ok := g.b.JobSite(); ok && !g.near(m) {
+		m.Navigate(site.Vec3Centre(), 0.8)
+	}
+}
+func (g villagerWorkGoal) Stop(m *Mob) {
+	m.StopNavigating()
+	if g.near(m) {
+		g.b.work(m)
+	}
+}
+func (villagerWorkGoal) Tick(*Mob) {}
+
+// near checks if the villager is close enough to its workstation to work.
+func (g villagerWorkGoal) near(m *Mob) bool {
+	site, ok := g.b.JobSite()
+	return ok && m.Position().Sub(site.Vec3Centre()).Len() <= 2.5
+}
+
+// VillagerType is a world.EntityType implementation for villagers.
+type VillagerType struct{}
+
+func (VillagerType) EncodeEntity() string { return "minecraft:villager_v2" }
+func (VillagerType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.3, 0, -0.3, 0.3, 1.9, 0.3)
+}
+
+func (VillagerType) DecodeNBT(m map[string]any) world.Entity {
+	profession := ProfessionNone()
+	for _, p := range VillagerProfessions() {
+		if int32(p.Uint8()) == nbtconv.Int32(m, "Variant") {
+			profession = p
+		}
+	}
+	v := decodeMobNBT(m, NewVillager(nbtconv.Vec3(m, "Pos"), profession))
+	b := v.Behaviour().(*VillagerBehaviour)
+	b.tier = int(nbtconv.Int32(m, "TradeTier"))
+	b.experience = int(nbtconv.Int32(m, "TradeExperience"))
+	if _, ok := m["JobSite"]; ok {
+		b.jobSite, b.hasJobSite = nbtconv.Pos(m, "JobSite"), true
+	}
+	if offers, ok := m["Offers"].([]any); ok {
+		b.offers = make([]trade.Offer, 0, len(offers))
+		for _, o := range offers {
+			if data, ok := o.(map[string]any); ok {
+				b.offers = append(b.offers, decodeOfferNBT(data))
+			}
+		}
+	}
+	return v
+}
+
+func (VillagerType) EncodeNBT(e world.Entity) map[string]any {
+	v := e.(*Mob)
+	b := v.Behaviour().(*VillagerBehaviour)
+	data := encodeMobNBT(v)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data["Variant"] = int32(b.profession.Uint8())
+	data["TradeTier"] = int32(b.tier)
+	data["TradeExperience"] = int32(b.experience)
+	if b.hasJobSite {
+		data["JobSite"] = nbtconv.PosToInt32Slice(b.jobSite)
+	}
+	offers := make([]any, 0, len(b.offers))
+	for _, o := range b.offers {
+		offers = append(offers, encodeOfferNBT(o))
+	}
+	data["Offers"] = offers
+	return data
+}
+
+// decodeOfferNBT decodes a trade.Offer from the map passed.
+func decodeOfferNBT(m map[string]any) trade.Offer {
+	return trade.Offer{
+		Input:            nbtconv.MapItem(m, "buyA"),
+		SecondInput:      nbtconv.MapItem(m, "buyB"),
+		Output:           nbtconv.MapItem(m, "sell"),
+		Tier:             int(nbtconv.Int32(m, "tier")),
+		MaxUses:          int(nbtconv.Int32(m, "maxUses")),
+		Uses:             int(nbtconv.Int32(m, "uses")),
+		Experience:       int(nbtconv.Int32(m, "traderExp")),
+		RewardExperience: nbtconv.Bool(m, "rewardExp"),
+		PriceMultiplier:  float64(nbtconv.Float32(m, "priceMultiplierA")),
+		Demand:           int(nbtconv.Int32(m, "demand")),
+	}
+}
+
+// encodeOfferNBT encodes the trade.Offer passed to a map that can be encoded to NBT.
+func encodeOfferNBT(o trade.Offer) map[string]any {
+	return map[string]any{
+		"buyA":             writeMobItem(o.Input),
+		"buyB":             writeMobItem(o.SecondInput),
+		"sell":             writeMobItem(o.Output),
+		"tier":             int32(o.Tier),
+		"maxUses":          int32(o.MaxUses),
+		"uses":             int32(o.Uses),
+		"traderExp":        int32(o.Experience),
+		"rewardExp":        boolByte(o.RewardExperience),
+		"priceMultiplierA": float32(o.PriceMultiplier),
+		"demand":           int32(o.Demand),
+	}
+}
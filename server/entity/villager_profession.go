@@ -0,0 +1,202 @@
+package entity
+
+import (
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// VillagerProfession is the profession of a villager. The profession of a villager decides the trades that it
+// offers. Villagers without a profession take the profession of the first unclaimed workstation they find.
+type VillagerProfession struct {
+	profession
+}
+
+// ProfessionNone returns the profession of villagers that have not claimed a workstation yet.
+func ProfessionNone() VillagerProfession {
+	return VillagerProfession{0}
+}
+
+// ProfessionFarmer returns the farmer profession, which uses a composter as workstation.
+func ProfessionFarmer() VillagerProfession {
+	return VillagerProfession{1}
+}
+
+// ProfessionFisherman returns the fisherman profession, which uses a barrel as workstation.
+func ProfessionFisherman() VillagerProfession {
+	return VillagerProfession{2}
+}
+
+// ProfessionShepherd returns the shepherd profession, which uses a loom as workstation.
+func ProfessionShepherd() VillagerProfession {
+	return VillagerProfession{3}
+}
+
+// ProfessionFletcher returns the fletcher profession, which uses a fletching table as workstation.
+func ProfessionFletcher() VillagerProfession {
+	return VillagerProfession{4}
+}
+
+// ProfessionLibrarian returns the librarian profession, which uses a lectern as workstation.
+func ProfessionLibrarian() VillagerProfession {
+	return VillagerProfession{5}
+}
+
+// ProfessionCleric returns the cleric profession, which uses a brewing stand as workstation.
+func ProfessionCleric() VillagerProfession {
+	return VillagerProfession{7}
+}
+
+// ProfessionArmourer returns the armourer profession, which uses a blast furnace as workstation.
+func ProfessionArmourer() VillagerProfession {
+	return VillagerProfession{8}
+}
+
+// ProfessionWeaponsmith returns the weaponsmith profession, which uses a grindstone as workstation.
+func ProfessionWeaponsmith() VillagerProfession {
+	return VillagerProfession{9}
+}
+
+// ProfessionToolsmith returns the toolsmith profession, which uses a smithing table as workstation.
+func ProfessionToolsmith() VillagerProfession {
+	return VillagerProfession{10}
+}
+
+// ProfessionButcher returns the butcher profession, which uses a smoker as workstation.
+func ProfessionButcher() VillagerProfession {
+	return VillagerProfession{11}
+}
+
+// ProfessionMason returns the mason profession, which uses a stonecutter as workstation.
+func ProfessionMason() VillagerProfession {
+	return VillagerProfession{13}
+}
+
+// ProfessionNitwit returns the nitwit profession. Nitwits never claim a workstation and do not trade.
+func ProfessionNitwit() VillagerProfession {
+	return VillagerProfession{14}
+}
+
+// VillagerProfessions returns all villager professions.
+func VillagerProfessions() []VillagerProfession {
+	return []VillagerProfession{
+		ProfessionNone(), ProfessionFarmer(), ProfessionFisherman(), ProfessionShepherd(), ProfessionFletcher(),
+		ProfessionLibrarian(), ProfessionCleric(), ProfessionArmourer(), ProfessionWeaponsmith(),
+		ProfessionToolsmith(), ProfessionButcher(), ProfessionMason(), ProfessionNitwit(),
+	}
+}
+
+// professionFromWorkstation returns the profession that uses the block passed as workstation. False is
+// returned if the block is not a workstation.
+func professionFromWorkstation(b world.Block) (VillagerProfession, bool) {
+	for _, p := range VillagerProfessions() {
+		if p.Workstation(b) {
+			return p, true
+		}
+	}
+	return VillagerProfession{}, false
+}
+
+type profession uint8
+
+// Uint8 returns the profession as a uint8. This value is equal to the variant of the villager entity.
+func (p profession) Uint8() uint8 {
+	return uint8(p)
+}
+
+// Employed checks if the profession is one that offers trades, meaning it is neither ProfessionNone nor
+// ProfessionNitwit.
+func (p profession) Employed() bool {
+	return p != 0 && p != 14
+}
+
+// Workstation checks if the block passed is the workstation of the profession.
+func (p profession) Workstation(b world.Block) bool {
+	switch b.(type) {
+	case block.Composter:
+		return p == 1
+	case block.Barrel:
+		return p == 2
+	case block.Loom:
+		return p == 3
+	case block.FletchingTable:
+		return p == 4
+	case block.Lectern:
+		return p == 5
+	case block.BrewingStand:
+		return p == 7
+	case block.BlastFurnace:
+		return p == 8
+	case block.Grindstone:
+		return p == 9
+	case block.SmithingTable:
+		return p == 10
+	case block.Smoker:
+		return p == 11
+	case block.Stonecutter:
+		return p == 13
+	}
+	return false
+}
+
+// Name ...
+func (p profession) Name() string {
+	switch p {
+	case 0:
+		return "None"
+	case 1:
+		return "Farmer"
+	case 2:
+		return "Fisherman"
+	case 3:
+		return "Shepherd"
+	case 4:
+		return "Fletcher"
+	case 5:
+		return "Librarian"
+	case 7:
+		return "Cleric"
+	case 8:
+		return "Armourer"
+	case 9:
+		return "Weaponsmith"
+	case 10:
+		return "Toolsmith"
+	case 11:
+		return "Butcher"
+	case 13:
+		return "Mason"
+	case 14:
+		return "Nitwit"
+	}
+	panic("should never happen")
+}
+
+// translation returns the translation key of the profession, which is shown at the top of the trading window
+// of a villager.
+func (p profession) translation() string {
+	switch p {
+	case 1:
+		return "entity.villager.farmer"
+	case 2:
+		return "entity.villager.fisherman"
+	case 3:
+		return "entity.villager.shepherd"
+	case 4:
+		return "entity.villager.fletcher"
+	case 5:
+		return "entity.villager.librarian"
+	case 7:
+		return "entity.villager.cleric"
+	case 8:
+		return "entity.villager.armor"
+	case 9:
+		return "entity.villager.weapon"
+	case 10:
+		return "entity.villager.tool"
+	case 11:
+		return "entity.villager.butcher"
+	case 13:
+		return "entity.villager.mason"
+	}
+	return "entity.villager.name"
+}
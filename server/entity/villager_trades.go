@@ -0,0 +1,399 @@
+package entity
+
+import (
+	"math/rand"
+
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/entity/trade"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/item/enchantment"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// villagerTrade is a trade that a villager may offer once it reaches a tier of its profession.
+type villagerTrade struct {
+	input, secondInput, output item.Stack
+	maxUses, experience        int
+	multiplier                 float64
+}
+
+// offer creates a trade.Offer of the tier passed from the villagerTrade. Enchanted books without enchantments
+// are given a random enchantment, with a price depending on the level of the enchantment.
+func (t villagerTrade) offer(tier int) trade.Offer {
+	if _, ok := t.output.Item().(item.EnchantedBook); ok && len(t.output.Enchantments()) == 0 {
+		t = randomEnchantedBookTrade(t)
+	}
+	return trade.Offer{
+		Input:            t.input,
+		SecondInput:      t.secondInput,
+		Output:           t.output,
+		Tier:             tier,
+		MaxUses:          t.maxUses,
+		Experience:       t.experience,
+		RewardExperience: true,
+		PriceMultiplier:  t.multiplier,
+	}
+}
+
+// villagerBuys returns a villagerTrade in which the villager buys count of the item passed for a single
+// emerald.
+func villagerBuys(it world.Item, count, maxUses, experience int) villagerTrade {
+	return villagerTrade{
+		input:      item.NewStack(it, count),
+		output:     item.NewStack(item.Emerald{}, 1),
+		maxUses:    maxUses,
+		experience: experience,
+		multiplier: 0.05,
+	}
+}
+
+// villagerSells returns a villagerTrade in which the villager sells count of the item passed for the amount
+// of emeralds passed.
+func villagerSells(it world.Item, count, emeralds, maxUses, experience int) villagerTrade {
+	return villagerTrade{
+		input:      item.NewStack(item.Emerald{}, emeralds),
+		output:     item.NewStack(it, count),
+		maxUses:    maxUses,
+		experience: experience,
+		multiplier: 0.05,
+	}
+}
+
+// villagerSellsEquipment returns a villagerTrade in which the villager sells a single piece of equipment, such
+// as armour or a tool, for the amount of emeralds passed. The price of equipment is more sensitive to demand.
+func villagerSellsEquipment(it world.Item, emeralds, maxUses, experience int) villagerTrade {
+	t := villagerSells(it, 1, emeralds, maxUses, experience)
+	t.multiplier = 0.2
+	return t
+}
+
+// villagerSellsEnchanted returns a villagerTrade in which the villager sells a single piece of equipment with
+// the enchantment passed for the amount of emeralds passed.
+func villagerSellsEnchanted(it world.Item, e item.Enchantment, emeralds, maxUses, experience int) villagerTrade {
+	t := villagerSellsEquipment(it, emeralds, maxUses, experience)
+	t.output = t.output.WithEnchantments(e)
+	return t
+}
+
+// villagerExchanges returns a villagerTrade in which the villager exchanges the input passed and an amount of
+// emeralds for the output passed.
+func villagerExchanges(input item.Stack, emeralds int, output item.Stack, maxUses, experience int) villagerTrade {
+	return villagerTrade{
+		input:       input,
+		secondInput: item.NewStack(item.Emerald{}, emeralds),
+		output:      output,
+		maxUses:     maxUses,
+		experience:  experience,
+		multiplier:  0.05,
+	}
+}
+
+// randomEnchantedBookTrade turns the villagerTrade passed into a trade that sells an enchanted book with a
+// random enchantment. The emerald price of the trade is based on the level of the enchantment and is doubled
+// for treasure enchantments.
+func randomEnchantedBookTrade(t villagerTrade) villagerTrade {
+	var types []item.EnchantmentType
+	for _, e := range item.Enchantments() {
+		switch e.(type) {
+		case enchantment.SoulSpeed, enchantment.SwiftSneak:
+			// These enchantments can only be found in loot chests.
+			continue
+		}
+		types = append(types, e)
+	}
+	e := types[rand.Intn(len(types))]
+	lvl := 1 + rand.Intn(e.MaxLevel())
+
+	price := 2 + rand.Intn(5+lvl*10) + 3*lvl
+	if tr, ok := e.(interface{ Treasure() bool }); ok && tr.Treasure() {
+		price *= 2
+	}
+	t.secondInput = item.NewStack(item.Emerald{}, min(price, 64))
+	t.output = item.NewStack(item.EnchantedBook{}, 1).WithEnchantments(item.NewEnchantment(e, lvl))
+	return t
+}
+
+// villagerTrades holds the trades that villagers of each profession may offer, indexed by the tier at which
+// they become available. Every time a villager reaches a new tier, two random trades of that tier are added to
+// its offers.
+var villagerTrades = map[VillagerProfession][trade.MaxTier + 1][]villagerTrade{
+	ProfessionFarmer(): {
+		{
+			villagerBuys(item.Wheat{}, 20, 16, 2),
+			villagerBuys(block.Potato{}, 26, 16, 2),
+			villagerBuys(block.Carrot{}, 22, 16, 2),
+			villagerBuys(item.Beetroot{}, 15, 16, 2),
+			villagerSells(item.Bread{}, 6, 1, 16, 1),
+		},
+		{
+			villagerBuys(block.Pumpkin{}, 6, 12, 10),
+			villagerSells(item.PumpkinPie{}, 4, 1, 12, 5),
+			villagerSells(item.Apple{}, 4, 1, 16, 5),
+		},
+		{
+			villagerSells(item.Cookie{}, 18, 3, 12, 10),
+			villagerBuys(block.Melon{}, 4, 12, 20),
+		},
+		{
+			villagerSells(block.Cake{}, 1, 1, 12, 15),
+		},
+		{
+			villagerSells(item.GoldenCarrot{}, 3, 3, 12, 30),
+			villagerSells(item.GlisteringMelonSlice{}, 3, 4, 12, 30),
+		},
+	},
+	ProfessionFisherman(): {
+		{
+			villagerBuys(item.Coal{}, 10, 16, 2),
+			villagerExchanges(item.NewStack(item.Cod{}, 6), 1, item.NewStack(item.Cod{Cooked: true}, 6), 16, 1),
+		},
+		{
+			villagerBuys(item.Cod{}, 15, 16, 10),
+			villagerExchanges(item.NewStack(item.Salmon{}, 6), 1, item.NewStack(item.Salmon{Cooked: true}, 6), 16, 5),
+		},
+		{
+			villagerBuys(item.Salmon{}, 13, 16, 20),
+			villagerSellsEquipment(item.FishingRod{}, 3, 3, 10),
+		},
+		{
+			villagerBuys(item.TropicalFish{}, 6, 12, 30),
+		},
+		{
+			villagerBuys(item.Pufferfish{}, 4, 12, 30),
+			villagerBuys(item.Boat{Type: item.OakBoat()}, 1, 12, 30),
+		},
+	},
+	ProfessionShepherd(): {
+		{
+			villagerBuys(block.Wool{Colour: item.ColourWhite()}, 18, 16, 2),
+			villagerBuys(block.Wool{Colour: item.ColourBrown()}, 18, 16, 2),
+			villagerBuys(block.Wool{Colour: item.ColourBlack()}, 18, 16, 2),
+			villagerBuys(block.Wool{Colour: item.ColourGrey()}, 18, 16, 2),
+			villagerSellsEquipment(item.Shears{}, 2, 12, 1),
+		},
+		{
+			villagerBuys(item.Dye{Colour: item.ColourWhite()}, 12, 16, 10),
+			villagerBuys(item.Dye{Colour: item.ColourGrey()}, 12, 16, 10),
+			villagerBuys(item.Dye{Colour: item.ColourBlack()}, 12, 16, 10),
+			villagerBuys(item.Dye{Colour: item.ColourLightBlue()}, 12, 16, 10),
+			villagerBuys(item.Dye{Colour: item.ColourLime()}, 12, 16, 10),
+			villagerSells(block.Wool{Colour: item.ColourWhite()}, 1, 1, 16, 5),
+			villagerSells(block.Carpet{Colour: item.ColourWhite()}, 4, 1, 16, 5),
+		},
+		{
+			villagerBuys(item.Dye{Colour: item.ColourYellow()}, 12, 16, 20),
+			villagerBuys(item.Dye{Colour: item.ColourLightGrey()}, 12, 16, 20),
+			villagerBuys(item.Dye{Colour: item.ColourOrange()}, 12, 16, 20),
+			villagerBuys(item.Dye{Colour: item.ColourRed()}, 12, 16, 20),
+			villagerBuys(item.Dye{Colour: item.ColourPink()}, 12, 16, 20),
+			villagerSells(block.Bed{Colour: item.ColourWhite()}, 1, 3, 12, 10),
+		},
+		{
+			villagerBuys(item.Dye{Colour: item.ColourBrown()}, 12, 16, 30),
+			villagerBuys(item.Dye{Colour: item.ColourPurple()}, 12, 16, 30),
+			villagerBuys(item.Dye{Colour: item.ColourBlue()}, 12, 16, 30),
+			villagerBuys(item.Dye{Colour: item.ColourGreen()}, 12, 16, 30),
+			villagerBuys(item.Dye{Colour: item.ColourMagenta()}, 12, 16, 30),
+			villagerBuys(item.Dye{Colour: item.ColourCyan()}, 12, 16, 30),
+			villagerSells(block.Banner{Colour: item.ColourWhite()}, 1, 3, 12, 15),
+		},
+		{
+			villagerSells(block.Banner{Colour: item.ColourBlack()}, 1, 3, 12, 30),
+			villagerSells(block.Bed{Colour: item.ColourRed()}, 1, 3, 12, 30),
+		},
+	},
+	ProfessionFletcher(): {
+		{
+			villagerBuys(item.Stick{}, 32, 16, 2),
+			villagerSells(item.Arrow{}, 16, 1, 12, 1),
+			villagerExchanges(item.NewStack(block.Gravel{}, 10), 1, item.NewStack(item.Flint{}, 10), 12, 1),
+		},
+		{
+			villagerBuys(item.Flint{}, 26, 12, 10),
+			villagerSellsEquipment(item.Bow{}, 2, 12, 5),
+		},
+		{
+			villagerSellsEquipment(item.Crossbow{}, 3, 12, 10),
+		},
+		{
+			villagerBuys(item.Feather{}, 24, 12, 30),
+			villagerSellsEnchanted(item.Bow{}, item.NewEnchantment(enchantment.Power{}, 1), 7, 3, 15),
+		},
+		{
+			villagerSellsEnchanted(item.Crossbow{}, item.NewEnchantment(enchantment.Piercing{}, 1), 8, 3, 30),
+			villagerSells(item.Arrow{}, 32, 3, 12, 30),
+		},
+	},
+	ProfessionLibrarian(): {
+		{
+			villagerBuys(item.Paper{}, 24, 16, 2),
+			villagerSells(block.Bookshelf{}, 1, 9, 12, 1),
+			villagerExchanges(item.NewStack(item.Book{}, 1), 0, item.NewStack(item.EnchantedBook{}, 1), 12, 1),
+		},
+		{
+			villagerBuys(item.Book{}, 4, 12, 10),
+			villagerSells(block.Lantern{Type: block.NormalFire()}, 1, 1, 12, 5),
+			villagerExchanges(item.NewStack(item.Book{}, 1), 0, item.NewStack(item.EnchantedBook{}, 1), 12, 5),
+		},
+		{
+			villagerBuys(item.InkSac{}, 5, 12, 20),
+			villagerSells(block.Glass{}, 4, 1, 12, 10),
+			villagerExchanges(item.NewStack(item.Book{}, 1), 0, item.NewStack(item.EnchantedBook{}, 1), 12, 10),
+		},
+		{
+			villagerBuys(item.BookAndQuill{}, 2, 12, 30),
+			villagerSells(item.Clock{}, 1, 5, 12, 15),
+			villagerSells(item.Compass{}, 1, 4, 12, 15),
+		},
+		{
+			villagerExchanges(item.NewStack(item.Book{}, 1), 0, item.NewStack(item.EnchantedBook{}, 1), 12, 30),
+		},
+	},
+	ProfessionCleric(): {
+		{
+			villagerBuys(item.RottenFlesh{}, 32, 16, 2),
+			villagerSells(block.RedstoneWire{}, 2, 1, 12, 1),
+		},
+		{
+			villagerBuys(item.GoldIngot{}, 3, 12, 10),
+			villagerSells(item.LapisLazuli{}, 1, 1, 12, 5),
+		},
+		{
+			villagerBuys(item.RabbitFoot{}, 2, 12, 20),
+			villagerSells(block.Glowstone{}, 1, 4, 12, 10),
+		},
+		{
+			villagerBuys(item.Scute{}, 4, 12, 30),
+			villagerBuys(item.GlassBottle{}, 9, 12, 30),
+			villagerSells(item.EnderPearl{}, 1, 5, 12, 15),
+		},
+		{
+			villagerBuys(block.NetherWart{}, 22, 12, 30),
+			villagerSells(item.BottleOfEnchanting{}, 1, 3, 12, 30),
+		},
+	},
+	ProfessionArmourer(): {
+		{
+			villagerBuys(item.Coal{}, 15, 16, 2),
+			villagerSellsEquipment(item.Helmet{Tier: item.ArmourTierIron{}}, 5, 12, 1),
+			villagerSellsEquipment(item.Chestplate{Tier: item.ArmourTierIron{}}, 9, 12, 1),
+			villagerSellsEquipment(item.Leggings{Tier: item.ArmourTierIron{}}, 7, 12, 1),
+			villagerSellsEquipment(item.Boots{Tier: item.ArmourTierIron{}}, 4, 12, 1),
+		},
+		{
+			villagerBuys(item.IronIngot{}, 4, 12, 10),
+			villagerSellsEquipment(item.Boots{Tier: item.ArmourTierChain{}}, 1, 12, 5),
+			villagerSellsEquipment(item.Leggings{Tier: item.ArmourTierChain{}}, 3, 12, 5),
+		},
+		{
+			villagerBuys(item.Diamond{}, 1, 12, 20),
+			villagerSellsEquipment(item.Helmet{Tier: item.ArmourTierChain{}}, 1, 12, 10),
+			villagerSellsEquipment(item.Chestplate{Tier: item.ArmourTierChain{}}, 4, 12, 10),
+			villagerSellsEquipment(item.Shield{}, 5, 12, 10),
+		},
+		{
+			villagerSellsEquipment(item.Leggings{Tier: item.ArmourTierDiamond{}}, 19, 3, 15),
+			villagerSellsEquipment(item.Boots{Tier: item.ArmourTierDiamond{}}, 13, 3, 15),
+		},
+		{
+			villagerSellsEquipment(item.Helmet{Tier: item.ArmourTierDiamond{}}, 13, 3, 30),
+			villagerSellsEquipment(item.Chestplate{Tier: item.ArmourTierDiamond{}}, 21, 3, 30),
+		},
+	},
+	ProfessionWeaponsmith(): {
+		{
+			villagerBuys(item.Coal{}, 15, 16, 2),
+			villagerSellsEquipment(item.Axe{Tier: item.ToolTierIron}, 3, 12, 1),
+			villagerSellsEnchanted(item.Sword{Tier: item.ToolTierIron}, item.NewEnchantment(enchantment.Sharpness{}, 1), 2, 3, 1),
+		},
+		{
+			villagerBuys(item.IronIngot{}, 4, 12, 10),
+		},
+		{
+			villagerBuys(item.Flint{}, 24, 12, 20),
+		},
+		{
+			villagerBuys(item.Diamond{}, 1, 12, 30),
+			villagerSellsEnchanted(item.Axe{Tier: item.ToolTierDiamond}, item.NewEnchantment(enchantment.Sharpness{}, 1), 12, 3, 15),
+		},
+		{
+			villagerSellsEnchanted(item.Sword{Tier: item.ToolTierDiamond}, item.NewEnchantment(enchantment.Sharpness{}, 2), 8, 3, 30),
+		},
+	},
+	ProfessionToolsmith(): {
+		{
+			villagerBuys(item.Coal{}, 15, 16, 2),
+			villagerSellsEquipment(item.Axe{Tier: item.ToolTierStone}, 1, 12, 1),
+			villagerSellsEquipment(item.Shovel{Tier: item.ToolTierStone}, 1, 12, 1),
+			villagerSellsEquipment(item.Pickaxe{Tier: item.ToolTierStone}, 1, 12, 1),
+			villagerSellsEquipment(item.Hoe{Tier: item.ToolTierStone}, 1, 12, 1),
+		},
+		{
+			villagerBuys(item.IronIngot{}, 4, 12, 10),
+		},
+		{
+			villagerBuys(item.Flint{}, 30, 12, 20),
+			villagerSellsEnchanted(item.Pickaxe{Tier: item.ToolTierIron}, item.NewEnchantment(enchantment.Efficiency{}, 1), 3, 3, 10),
+			villagerSellsEquipment(item.Hoe{Tier: item.ToolTierDiamond}, 4, 3, 10),
+		},
+		{
+			villagerBuys(item.Diamond{}, 1, 12, 30),
+			villagerSellsEnchanted(item.Axe{Tier: item.ToolTierDiamond}, item.NewEnchantment(enchantment.Efficiency{}, 1), 12, 3, 15),
+			villagerSellsEnchanted(item.Shovel{Tier: item.ToolTierDiamond}, item.NewEnchantment(enchantment.Efficiency{}, 1), 5, 3, 15),
+		},
+		{
+			villagerSellsEnchanted(item.Pickaxe{Tier: item.ToolTierDiamond}, item.NewEnchantment(enchantment.Efficiency{}, 2), 13, 3, 30),
+		},
+	},
+	ProfessionButcher(): {
+		{
+			villagerBuys(item.Chicken{}, 14, 16, 2),
+			villagerBuys(item.Porkchop{}, 7, 16, 2),
+			villagerBuys(item.Rabbit{}, 4, 16, 2),
+			villagerSells(item.RabbitStew{}, 1, 1, 12, 1),
+		},
+		{
+			villagerBuys(item.Coal{}, 15, 16, 10),
+			villagerSells(item.Porkchop{Cooked: true}, 5, 1, 16, 5),
+			villagerSells(item.Chicken{Cooked: true}, 8, 1, 16, 5),
+		},
+		{
+			villagerBuys(item.Mutton{}, 7, 16, 20),
+			villagerBuys(item.Beef{}, 10, 16, 20),
+		},
+		{
+			villagerBuys(block.DriedKelp{}, 10, 12, 30),
+		},
+		{
+			villagerSells(item.Beef{Cooked: true}, 6, 1, 12, 30),
+		},
+	},
+	ProfessionMason(): {
+		{
+			villagerBuys(item.ClayBall{}, 10, 16, 2),
+			villagerSells(item.Brick{}, 10, 1, 16, 1),
+		},
+		{
+			villagerBuys(block.Stone{}, 20, 16, 10),
+			villagerSells(block.StoneBricks{Type: block.ChiseledStoneBricks()}, 4, 1, 16, 5),
+		},
+		{
+			villagerBuys(block.Granite{}, 16, 16, 20),
+			villagerBuys(block.Andesite{}, 16, 16, 20),
+			villagerBuys(block.Diorite{}, 16, 16, 20),
+			villagerSells(block.Granite{Polished: true}, 4, 1, 16, 10),
+			villagerSells(block.Andesite{Polished: true}, 4, 1, 16, 10),
+			villagerSells(block.Diorite{Polished: true}, 4, 1, 16, 10),
+		},
+		{
+			villagerBuys(item.NetherQuartz{}, 12, 12, 30),
+			villagerSells(block.Terracotta{}, 1, 1, 12, 15),
+			villagerSells(block.StainedTerracotta{Colour: item.ColourOrange()}, 1, 1, 12, 15),
+			villagerSells(block.GlazedTerracotta{Colour: item.ColourLightBlue()}, 1, 1, 12, 15),
+		},
+		{
+			villagerSells(block.QuartzPillar{}, 1, 1, 12, 30),
+			villagerSells(block.Quartz{}, 1, 1, 12, 30),
+		},
+	},
+}
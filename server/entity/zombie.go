@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// NewZombie creates a new zombie at the position passed. Zombies are hostile
+// mobs that chase players and attack them in melee range. They burn in
+// daylight.
+func NewZombie(pos mgl64.Vec3) *Mob {
+	conf := zombieConf
+	conf.Behaviour = MobBehaviourConfig{
+		Goals: []Goal{
+			SwimGoal{},
+			&MeleeAttackGoal{Speed: 1},
+			WanderGoal{Speed: 0.8},
+			&LookAtPlayerGoal{Distance: 8},
+			&RandomLookGoal{},
+		},
+		TargetGoals: []Goal{
+			HurtByTargetGoal{},
+			NearestPlayerTargetGoal{Distance: 35},
+		},
+		Tick:  burnInDaylight,
+		Drops: zombieDrops,
+	}.New()
+	return conf.New(ZombieType{}, pos)
+}
+
+var zombieConf = MobConfig{
+	MaxHealth:    20,
+	Speed:        0.115,
+	AttackDamage: 3,
+	Experience:   5,
+	Category:     world.SpawnCategoryMonster,
+}
+
+// zombieDrops returns the items dropped by a zombie when it dies.
+func zombieDrops(*Mob) []item.Stack {
+	return []item.Stack{mobDrop(item.RottenFlesh{}, 0, 2)}
+}
+
+// ZombieType is a world.EntityType implementation for zombies.
+type ZombieType struct{}
+
+func (ZombieType) EncodeEntity() string { return "minecraft:zombie" }
+func (ZombieType) BBox(world.Entity) cube.BBox {
+	return cube.Box(-0.3, 0, -0.3, 0.3, 1.9, 0.3)
+}
+
+func (ZombieType) DecodeNBT(m map[string]any) world.Entity {
+	return decodeMobNBT(m, NewZombie(nbtconv.Vec3(m, "Pos")))
+}
+
+func (ZombieType) EncodeNBT(e world.Entity) map[string]any {
+	return encodeMobNBT(e.(*Mob))
+}
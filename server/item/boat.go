@@ -0,0 +1,48 @@
+package item
+
+import (
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// Boat is an item that can be placed as a boat entity, which may be ridden by up to two entities and travels
+// quickly over water.
+type Boat struct {
+	// Type is the type of the boat.
+	Type BoatType
+}
+
+// MaxCount ...
+func (Boat) MaxCount() int {
+	return 1
+}
+
+// UseOnBlock ...
+func (b Boat) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w *world.World, user User, ctx *UseContext) bool {
+	spawn := pos.Side(face).Vec3Middle()
+	if _, ok := w.Liquid(pos); ok {
+		// Boats placed on liquid are placed on top of its surface.
+		spawn = pos.Side(cube.FaceUp).Vec3Middle()
+	}
+	create := w.EntityRegistry().Config().Boat
+	w.AddEntity(create(spawn, user.Rotation().Yaw(), b.Type))
+
+	ctx.SubtractFromCount(1)
+	return true
+}
+
+// FuelInfo ...
+func (Boat) FuelInfo() FuelInfo {
+	return newFuelInfo(time.Second * 60)
+}
+
+// EncodeItem ...
+func (b Boat) EncodeItem() (name string, meta int16) {
+	if b.Type == BambooRaft() {
+		return "minecraft:bamboo_raft", 0
+	}
+	return "minecraft:" + b.Type.String() + "_boat", 0
+}
@@ -0,0 +1,113 @@
+package item
+
+// BoatType represents the type of wood that a boat is made of. The bamboo raft is also a type of boat.
+type BoatType struct {
+	boat
+}
+
+// OakBoat returns the oak boat type.
+func OakBoat() BoatType {
+	return BoatType{0}
+}
+
+// SpruceBoat returns the spruce boat type.
+func SpruceBoat() BoatType {
+	return BoatType{1}
+}
+
+// BirchBoat returns the birch boat type.
+func BirchBoat() BoatType {
+	return BoatType{2}
+}
+
+// JungleBoat returns the jungle boat type.
+func JungleBoat() BoatType {
+	return BoatType{3}
+}
+
+// AcaciaBoat returns the acacia boat type.
+func AcaciaBoat() BoatType {
+	return BoatType{4}
+}
+
+// DarkOakBoat returns the dark oak boat type.
+func DarkOakBoat() BoatType {
+	return BoatType{5}
+}
+
+// MangroveBoat returns the mangrove boat type.
+func MangroveBoat() BoatType {
+	return BoatType{6}
+}
+
+// BambooRaft returns the bamboo raft boat type.
+func BambooRaft() BoatType {
+	return BoatType{7}
+}
+
+// CherryBoat returns the cherry boat type.
+func CherryBoat() BoatType {
+	return BoatType{8}
+}
+
+// BoatTypes returns all boat types.
+func BoatTypes() []BoatType {
+	return []BoatType{OakBoat(), SpruceBoat(), BirchBoat(), JungleBoat(), AcaciaBoat(), DarkOakBoat(), MangroveBoat(), BambooRaft(), CherryBoat()}
+}
+
+type boat uint8
+
+// Uint8 returns the boat type as a uint8. This value is equal to the variant of the boat entity.
+func (b boat) Uint8() uint8 {
+	return uint8(b)
+}
+
+// Name ...
+func (b boat) Name() string {
+	switch b {
+	case 0:
+		return "Oak Boat"
+	case 1:
+		return "Spruce Boat"
+	case 2:
+		return "Birch Boat"
+	case 3:
+		return "Jungle Boat"
+	case 4:
+		return "Acacia Boat"
+	case 5:
+		return "Dark Oak Boat"
+	case 6:
+		return "Mangrove Boat"
+	case 7:
+		return "Bamboo Raft"
+	case 8:
+		return "Cherry Boat"
+	}
+	panic("unknown boat type")
+}
+
+// String ...
+func (b boat) String() string {
+	switch b {
+	case 0:
+		return "oak"
+	case 1:
+		return "spruce"
+	case 2:
+		return "birch"
+	case 3:
+		return "jungle"
+	case 4:
+		return "acacia"
+	case 5:
+		return "dark_oak"
+	case 6:
+		return "mangrove"
+	case 7:
+		return "bamboo"
+	case 8:
+		return "cherry"
+	}
+	panic("unknown boat type")
+}
@@ -0,0 +1,173 @@
+package item
+
+import (
+	"time"
+
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/sound"
+)
+
+// Crossbow is a ranged weapon similar to a bow that shoots arrows. Unlike a bow, a crossbow is loaded by holding
+// it until it is charged, after which it stays loaded until it is used to shoot.
+type Crossbow struct {
+	// Item is the projectile that the crossbow is loaded with. The crossbow is not loaded if Item is empty.
+	Item Stack
+}
+
+// MaxCount always returns 1.
+func (Crossbow) MaxCount() int {
+	return 1
+}
+
+// DurabilityInfo ...
+func (Crossbow) DurabilityInfo() DurabilityInfo {
+	return DurabilityInfo{
+		MaxDurability: 465,
+		BrokenItem:    simpleItem(Stack{}),
+	}
+}
+
+// FuelInfo ...
+func (Crossbow) FuelInfo() FuelInfo {
+	return newFuelInfo(time.Second * 15)
+}
+
+// EnchantmentValue ...
+func (Crossbow) EnchantmentValue() int {
+	return 1
+}
+
+// Charged checks if the crossbow is loaded with a projectile.
+func (c Crossbow) Charged() bool {
+	return !c.Item.Empty()
+}
+
+// Release loads the crossbow with an arrow if it was held long enough to be fully charged.
+func (c Crossbow) Release(releaser Releaser, duration time.Duration, ctx *UseContext) {
+	if c.Charged() {
+		return
+	}
+	held, left := releaser.HeldItems()
+	chargeDuration, quickCharge := time.Second*5/4, false
+	for _, enchant := range held.Enchantments() {
+		if q, ok := enchant.Type().(interface{ ChargeDuration(int) time.Duration }); ok {
+			chargeDuration, quickCharge = q.ChargeDuration(enchant.Level()), true
+		}
+	}
+	if duration < chargeDuration {
+		return
+	}
+
+	creative := releaser.GameMode().CreativeInventory()
+	arrow, ok := ctx.FirstFunc(func(stack Stack) bool {
+		_, ok := stack.Item().(Arrow)
+		return ok
+	})
+	if !ok && !creative {
+		return
+	}
+	if !ok {
+		// No arrows in the inventory of a creative releaser: Load a regular arrow.
+		arrow = NewStack(Arrow{}, 1)
+	}
+	c.Item = arrow.Grow(-arrow.Count() + 1)
+	if !creative {
+		ctx.Consume(c.Item)
+	}
+	releaser.SetHeldItems(held.WithItem(c), left)
+	releaser.PlaySound(sound.CrossbowLoad{QuickCharge: quickCharge})
+}
+
+// Use shoots the projectile that the crossbow is loaded with. If the crossbow is not loaded, Use returns false.
+func (c Crossbow) Use(w *world.World, user User, ctx *UseContext) bool {
+	if !c.Charged() {
+		return false
+	}
+	arrow, ok := c.Item.Item().(Arrow)
+	if !ok {
+		return false
+	}
+	held, left := user.HeldItems()
+	creative := false
+	if g, ok := user.(interface{ GameMode() world.GameMode }); ok {
+		creative = g.GameMode().CreativeInventory()
+	}
+
+	extra, piercing := 0, 0
+	for _, enchant := range held.Enchantments() {
+		if m, ok := enchant.Type().(interface{ ExtraProjectiles() int }); ok {
+			extra = m.ExtraProjectiles()
+		}
+		if p, ok := enchant.Type().(interface{ Pierces(int) int }); ok {
+			piercing = p.Pierces(enchant.Level())
+		}
+	}
+
+	create := w.EntityRegistry().Config().Arrow
+	for i := 0; i <= extra; i++ {
+		// Additional projectiles are shot at alternating angles of 10 degrees to either side.
+		offset := float64((i+1)/2) * 10
+		if i%2 == 0 {
+			offset = -offset
+		}
+		dir := cube.Rotation{user.Rotation().Yaw() + offset, user.Rotation().Pitch()}
+		rot := cube.Rotation{-dir[0], -dir[1]}
+		if rot[0] > 180 {
+			rot[0] = 360 - rot[0]
+		}
+		// Only the arrow in the middle may be picked up to obtain the arrow that was loaded.
+		obtain := i == 0 && !creative
+		w.AddEntity(create(eyePosition(user), dir.Vec3().Mul(3.15), rot, 2, user, true, false, obtain, 0, piercing, arrow.Tip))
+	}
+
+	c.Item = Stack{}
+	user.SetHeldItems(held.WithItem(c), left)
+	ctx.DamageItem(1 + extra)
+	w.PlaySound(user.Position(), sound.CrossbowShoot{})
+	return true
+}
+
+// Requirements returns the required items to load the crossbow.
+func (Crossbow) Requirements() []Stack {
+	return []Stack{NewStack(Arrow{}, 1)}
+}
+
+// EncodeNBT ...
+func (c Crossbow) EncodeNBT() map[string]any {
+	if !c.Charged() {
+		return nil
+	}
+	name, meta := c.Item.Item().EncodeItem()
+	charged := map[string]any{"Name": name, "Damage": meta, "Count": byte(1)}
+	if nbt, ok := c.Item.Item().(world.NBTer); ok {
+		charged["tag"] = nbt.EncodeNBT()
+	}
+	return map[string]any{"chargedItem": charged}
+}
+
+// DecodeNBT ...
+func (c Crossbow) DecodeNBT(data map[string]any) any {
+	charged, ok := data["chargedItem"].(map[string]any)
+	if !ok {
+		return c
+	}
+	name, _ := charged["Name"].(string)
+	meta, _ := charged["Damage"].(int16)
+	it, ok := world.ItemByName(name, meta)
+	if !ok {
+		return c
+	}
+	if tag, ok := charged["tag"].(map[string]any); ok {
+		if nbt, ok := it.(world.NBTer); ok {
+			it = nbt.DecodeNBT(tag).(world.Item)
+		}
+	}
+	c.Item = NewStack(it, 1)
+	return c
+}
+
+// EncodeItem ...
+func (Crossbow) EncodeItem() (name string, meta int16) {
+	return "minecraft:crossbow", 0
+}
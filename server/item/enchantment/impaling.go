@@ -0,0 +1,61 @@
+package enchantment
+
+import (
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// Impaling is a trident enchantment that increases the damage dealt to entities that are in water or rain.
+type Impaling struct{}
+
+// Name ...
+func (Impaling) Name() string {
+	return "Impaling"
+}
+
+// MaxLevel ...
+func (Impaling) MaxLevel() int {
+	return 5
+}
+
+// Cost ...
+func (Impaling) Cost(level int) (int, int) {
+	min := 1 + (level-1)*8
+	return min, min + 20
+}
+
+// Rarity ...
+func (Impaling) Rarity() item.EnchantmentRarity {
+	return item.EnchantmentRarityRare
+}
+
+// Addend returns the additional damage dealt to an entity affected by impaling.
+func (Impaling) Addend(level int) float64 {
+	return float64(level) * 2.5
+}
+
+// Affects checks if the entity passed takes additional damage from impaling, which is the case if it is in
+// water or in the rain.
+func (Impaling) Affects(e world.Entity) bool {
+	w := e.World()
+	if w == nil {
+		return false
+	}
+	pos := cube.PosFromVec3(e.Position())
+	if l, ok := w.Liquid(pos); ok && l.LiquidType() == "water" {
+		return true
+	}
+	return w.RainingAt(pos)
+}
+
+// CompatibleWithEnchantment ...
+func (Impaling) CompatibleWithEnchantment(item.EnchantmentType) bool {
+	return true
+}
+
+// CompatibleWithItem ...
+func (Impaling) CompatibleWithItem(i world.Item) bool {
+	_, ok := i.(item.Trident)
+	return ok
+}
@@ -0,0 +1,41 @@
+package enchantment
+
+import (
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// LuckOfTheSea is a fishing rod enchantment that increases the chance of catching treasure instead of junk.
+type LuckOfTheSea struct{}
+
+// Name ...
+func (LuckOfTheSea) Name() string {
+	return "Luck of the Sea"
+}
+
+// MaxLevel ...
+func (LuckOfTheSea) MaxLevel() int {
+	return 3
+}
+
+// Cost ...
+func (LuckOfTheSea) Cost(level int) (int, int) {
+	min := 15 + (level-1)*9
+	return min, min + 50
+}
+
+// Rarity ...
+func (LuckOfTheSea) Rarity() item.EnchantmentRarity {
+	return item.EnchantmentRarityRare
+}
+
+// CompatibleWithEnchantment ...
+func (LuckOfTheSea) CompatibleWithEnchantment(item.EnchantmentType) bool {
+	return true
+}
+
+// CompatibleWithItem ...
+func (LuckOfTheSea) CompatibleWithItem(i world.Item) bool {
+	_, ok := i.(item.FishingRod)
+	return ok
+}
@@ -0,0 +1,48 @@
+package enchantment
+
+import (
+	"time"
+
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// Lure is a fishing rod enchantment that decreases the time it takes for a fish to bite.
+type Lure struct{}
+
+// Name ...
+func (Lure) Name() string {
+	return "Lure"
+}
+
+// MaxLevel ...
+func (Lure) MaxLevel() int {
+	return 3
+}
+
+// Cost ...
+func (Lure) Cost(level int) (int, int) {
+	min := 15 + (level-1)*9
+	return min, min + 50
+}
+
+// Rarity ...
+func (Lure) Rarity() item.EnchantmentRarity {
+	return item.EnchantmentRarityRare
+}
+
+// WaitReduction returns the duration by which the time waiting for a fish to bite is reduced.
+func (Lure) WaitReduction(level int) time.Duration {
+	return time.Duration(level) * time.Second * 5
+}
+
+// CompatibleWithEnchantment ...
+func (Lure) CompatibleWithEnchantment(item.EnchantmentType) bool {
+	return true
+}
+
+// CompatibleWithItem ...
+func (Lure) CompatibleWithItem(i world.Item) bool {
+	_, ok := i.(item.FishingRod)
+	return ok
+}
@@ -0,0 +1,46 @@
+package enchantment
+
+import (
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// Multishot is a crossbow enchantment that makes the crossbow shoot three arrows at the cost of one.
+type Multishot struct{}
+
+// Name ...
+func (Multishot) Name() string {
+	return "Multishot"
+}
+
+// MaxLevel ...
+func (Multishot) MaxLevel() int {
+	return 1
+}
+
+// Cost ...
+func (Multishot) Cost(int) (int, int) {
+	return 20, 50
+}
+
+// Rarity ...
+func (Multishot) Rarity() item.EnchantmentRarity {
+	return item.EnchantmentRarityRare
+}
+
+// ExtraProjectiles returns the amount of additional projectiles shot by a crossbow with multishot.
+func (Multishot) ExtraProjectiles() int {
+	return 2
+}
+
+// CompatibleWithEnchantment ...
+func (Multishot) CompatibleWithEnchantment(t item.EnchantmentType) bool {
+	_, piercing := t.(Piercing)
+	return !piercing
+}
+
+// CompatibleWithItem ...
+func (Multishot) CompatibleWithItem(i world.Item) bool {
+	_, ok := i.(item.Crossbow)
+	return ok
+}
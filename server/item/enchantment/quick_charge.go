@@ -0,0 +1,47 @@
+package enchantment
+
+import (
+	"time"
+
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// QuickCharge is a crossbow enchantment that reduces the time needed to load the crossbow.
+type QuickCharge struct{}
+
+// Name ...
+func (QuickCharge) Name() string {
+	return "Quick Charge"
+}
+
+// MaxLevel ...
+func (QuickCharge) MaxLevel() int {
+	return 3
+}
+
+// Cost ...
+func (QuickCharge) Cost(level int) (int, int) {
+	return 12 + (level-1)*20, 50
+}
+
+// Rarity ...
+func (QuickCharge) Rarity() item.EnchantmentRarity {
+	return item.EnchantmentRarityUncommon
+}
+
+// ChargeDuration returns the duration that loading a crossbow with the quick charge level passed takes.
+func (QuickCharge) ChargeDuration(level int) time.Duration {
+	return time.Duration(max(25-level*5, 0)) * time.Second / 20
+}
+
+// CompatibleWithEnchantment ...
+func (QuickCharge) CompatibleWithEnchantment(item.EnchantmentType) bool {
+	return true
+}
+
+// CompatibleWithItem ...
+func (QuickCharge) CompatibleWithItem(i world.Item) bool {
+	_, ok := i.(item.Crossbow)
+	return ok
+}
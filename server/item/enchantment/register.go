@@ -26,19 +26,19 @@ func init() {
 	item.RegisterEnchantment(20, Punch{})
 	item.RegisterEnchantment(21, Flame{})
 	item.RegisterEnchantment(22, Infinity{})
-	// TODO: (23) Luck of the Sea.
-	// TODO: (24) Lure.
+	item.RegisterEnchantment(23, LuckOfTheSea{})
+	item.RegisterEnchantment(24, Lure{})
 	// TODO: (25) Frost Walker.
 	item.RegisterEnchantment(26, Mending{})
 	// TODO: (27) Curse of Binding.
 	item.RegisterEnchantment(28, CurseOfVanishing{})
-	// TODO: (29) Impaling.
-	// TODO: (30) Riptide.
-	// TODO: (31) Loyalty.
-	// TODO: (32) Channeling.
-	// TODO: (33) Multishot.
-	// TODO: (34) Piercing.
-	// TODO: (35) Quick Charge.
+	item.RegisterEnchantment(29, Impaling{})
+	item.RegisterEnchantment(30, Riptide{})
+	item.RegisterEnchantment(31, Loyalty{})
+	item.RegisterEnchantment(32, Channeling{})
+	item.RegisterEnchantment(33, Multishot{})
+	item.RegisterEnchantment(34, Piercing{})
+	item.RegisterEnchantment(35, QuickCharge{})
 	item.RegisterEnchantment(36, SoulSpeed{})
 	item.RegisterEnchantment(37, SwiftSneak{})
 }
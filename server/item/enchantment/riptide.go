@@ -0,0 +1,48 @@
+package enchantment
+
+import (
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// Riptide is a trident enchantment that launches the player holding the trident instead of throwing it. It
+// only works while the player is in water or in the rain.
+type Riptide struct{}
+
+// Name ...
+func (Riptide) Name() string {
+	return "Riptide"
+}
+
+// MaxLevel ...
+func (Riptide) MaxLevel() int {
+	return 3
+}
+
+// Cost ...
+func (Riptide) Cost(level int) (int, int) {
+	return 10 + level*7, 50
+}
+
+// Rarity ...
+func (Riptide) Rarity() item.EnchantmentRarity {
+	return item.EnchantmentRarityRare
+}
+
+// RiptideForce returns the velocity with which a player is launched by a trident with the riptide level passed.
+func (Riptide) RiptideForce(level int) float64 {
+	return 3 * float64(1+level) / 4
+}
+
+// CompatibleWithEnchantment ...
+func (Riptide) CompatibleWithEnchantment(t item.EnchantmentType) bool {
+	_, loyalty := t.(Loyalty)
+	_, channeling := t.(Channeling)
+	return !loyalty && !channeling
+}
+
+// CompatibleWithItem ...
+func (Riptide) CompatibleWithItem(i world.Item) bool {
+	_, ok := i.(item.Trident)
+	return ok
+}
@@ -0,0 +1,10 @@
+package item
+
+// EnderEye is an item used to activate end portals by placing it in end
+// portal frames.
+type EnderEye struct{}
+
+// EncodeItem ...
+func (EnderEye) EncodeItem() (name string, meta int16) {
+	return "minecraft:ender_eye", 0
+}
@@ -0,0 +1,85 @@
+package item
+
+import (
+	"time"
+
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/sound"
+)
+
+// FishingRod is a tool used to catch fish and other items from bodies of water. It may also be used to pull
+// entities towards the user.
+type FishingRod struct{}
+
+// MaxCount always returns 1.
+func (FishingRod) MaxCount() int {
+	return 1
+}
+
+// HandEquipped ...
+func (FishingRod) HandEquipped() bool {
+	return true
+}
+
+// DurabilityInfo ...
+func (FishingRod) DurabilityInfo() DurabilityInfo {
+	return DurabilityInfo{
+		MaxDurability: 385,
+		BrokenItem:    simpleItem(Stack{}),
+	}
+}
+
+// FuelInfo ...
+func (FishingRod) FuelInfo() FuelInfo {
+	return newFuelInfo(time.Second * 15)
+}
+
+// EnchantmentValue ...
+func (FishingRod) EnchantmentValue() int {
+	return 1
+}
+
+// Use casts the hook of the fishing rod. If the user already cast a hook, it is reeled in instead.
+func (FishingRod) Use(w *world.World, user User, ctx *UseContext) bool {
+	if hook, ok := fishingHookOf(w, user); ok {
+		ctx.DamageItem(hook.Reel())
+		return true
+	}
+	held, _ := user.HeldItems()
+	create := w.EntityRegistry().Config().FishingHook
+	w.AddEntity(create(eyePosition(user), user.Rotation().Vec3().Mul(fishingHookSpeed), user, held))
+	w.PlaySound(user.Position(), sound.ItemThrow{})
+	return true
+}
+
+// fishingHookSpeed is the speed in blocks/tick with which the hook of a fishing rod is cast.
+const fishingHookSpeed = 1.1
+
+// fishingHook represents the hook of a fishing rod, cast by a User.
+type fishingHook interface {
+	world.Entity
+	// Owner returns the entity that cast the hook.
+	Owner() world.Entity
+	// Reel reels in the hook, returning the damage that should be dealt to the fishing rod as a result.
+	Reel() int
+}
+
+// fishingHookOf returns the fishing hook cast by the user passed, if it has one.
+func fishingHookOf(w *world.World, user User) (fishingHook, bool) {
+	box := cube.Box(-1, -1, -1, 1, 1, 1).Translate(user.Position()).Grow(fishingHookRange)
+	for _, e := range w.EntitiesWithin(box, nil) {
+		if hook, ok := e.(fishingHook); ok && hook.Owner() == user {
+			return hook, true
+		}
+	}
+	return nil, false
+}
+
+// fishingHookRange is the maximum distance between a fishing hook and its owner.
+const fishingHookRange = 32
+
+// EncodeItem ...
+func (FishingRod) EncodeItem() (name string, meta int16) {
+	return "minecraft:fishing_rod", 0
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/stcraft/dragonfly/server/block/cube"
 	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/portal"
 	"github.com/stcraft/dragonfly/server/world/sound"
 )
 
@@ -39,6 +40,10 @@ func (f FlintAndSteel) UseOnBlock(pos cube.Pos, face cube.Face, _ mgl64.Vec3, w
 		return true
 	} else if s := pos.Side(face); w.Block(s) == air() {
 		w.PlaySound(s.Vec3Centre(), sound.Ignite{})
+		if p, ok := portal.NetherPortalFromPos(w, s); ok && !p.Activated() {
+			p.Activate()
+			return true
+		}
 		w.SetBlock(s, fire(), nil)
 		w.ScheduleBlockUpdate(s, time.Duration(30+rand.Intn(10))*time.Second/20)
 		return true
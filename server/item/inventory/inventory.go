@@ -7,6 +7,7 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/stcraft/dragonfly/server/item"
 )
@@ -23,6 +24,8 @@ type Inventory struct {
 
 	f      func(slot int, before, after item.Stack)
 	canAdd func(s item.Stack, slot int) bool
+
+	changed atomic.Bool
 }
 
 // ErrSlotOutOfRange is returned by any methods on inventory when a slot is passed which is not within the
@@ -326,11 +329,18 @@ func (inv *Inventory) setItem(slot int, it item.Stack) func() {
 	}
 	before := inv.slots[slot]
 	inv.slots[slot] = it
+	inv.changed.Store(true)
 	return func() {
 		inv.f(slot, before, it)
 	}
 }
 
+// Changed returns true if any slot of the inventory was changed since the last call to Changed. It may be used
+// to find out if the items of an inventory need to be saved.
+func (inv *Inventory) Changed() bool {
+	return inv.changed.Swap(false)
+}
+
 // Size returns the size of the inventory. It is always the same value as that passed in the call to New() and
 // is always at least 1.
 func (inv *Inventory) Size() int {
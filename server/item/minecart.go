@@ -0,0 +1,43 @@
+package item
+
+import (
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// Minecart is an item that can be placed on rails as a minecart entity, which travels along the rails.
+type Minecart struct {
+	// Type is the type of the minecart.
+	Type MinecartType
+}
+
+// minecartRail represents a block that a minecart may be placed on, such as a rail.
+type minecartRail interface {
+	// MinecartPosition returns the position of a minecart that is placed in the middle of the rail at the
+	// position passed.
+	MinecartPosition(pos cube.Pos) mgl64.Vec3
+}
+
+// MaxCount ...
+func (Minecart) MaxCount() int {
+	return 1
+}
+
+// UseOnBlock ...
+func (m Minecart) UseOnBlock(pos cube.Pos, _ cube.Face, _ mgl64.Vec3, w *world.World, _ User, ctx *UseContext) bool {
+	r, ok := w.Block(pos).(minecartRail)
+	if !ok {
+		return false
+	}
+	create := w.EntityRegistry().Config().Minecart
+	w.AddEntity(create(r.MinecartPosition(pos), m.Type))
+
+	ctx.SubtractFromCount(1)
+	return true
+}
+
+// EncodeItem ...
+func (m Minecart) EncodeItem() (name string, meta int16) {
+	return "minecraft:" + m.Type.String(), 0
+}
@@ -0,0 +1,69 @@
+package item
+
+// MinecartType represents a type of minecart. Besides regular minecarts that may be ridden, minecarts may carry a
+// chest, a hopper or TNT.
+type MinecartType struct {
+	minecart
+}
+
+// RegularMinecart returns the regular minecart type, which may be ridden by an entity.
+func RegularMinecart() MinecartType {
+	return MinecartType{0}
+}
+
+// ChestMinecart returns the minecart type carrying a chest.
+func ChestMinecart() MinecartType {
+	return MinecartType{1}
+}
+
+// HopperMinecart returns the minecart type carrying a hopper.
+func HopperMinecart() MinecartType {
+	return MinecartType{2}
+}
+
+// TNTMinecart returns the minecart type carrying TNT.
+func TNTMinecart() MinecartType {
+	return MinecartType{3}
+}
+
+// MinecartTypes returns all minecart types.
+func MinecartTypes() []MinecartType {
+	return []MinecartType{RegularMinecart(), ChestMinecart(), HopperMinecart(), TNTMinecart()}
+}
+
+type minecart uint8
+
+// Uint8 returns the minecart type as a uint8.
+func (m minecart) Uint8() uint8 {
+	return uint8(m)
+}
+
+// Name ...
+func (m minecart) Name() string {
+	switch m {
+	case 0:
+		return "Minecart"
+	case 1:
+		return "Minecart with Chest"
+	case 2:
+		return "Minecart with Hopper"
+	case 3:
+		return "Minecart with TNT"
+	}
+	panic("unknown minecart type")
+}
+
+// String ...
+func (m minecart) String() string {
+	switch m {
+	case 0:
+		return "minecart"
+	case 1:
+		return "chest_minecart"
+	case 2:
+		return "hopper_minecart"
+	case 3:
+		return "tnt_minecart"
+	}
+	panic("unknown minecart type")
+}
@@ -0,0 +1,151 @@
+package recipe
+
+import (
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// Potion is a recipe that may be brewed in a brewing stand. It turns a potion of one type into a potion of
+// another type using a reagent, such as turning an awkward potion into a potion of swiftness using sugar.
+type Potion struct {
+	recipe
+}
+
+// NewPotion creates a new potion recipe and returns it. The input potion is turned into the output potion
+// when brewed with the reagent passed.
+func NewPotion(input, reagent Item, output item.Stack) Potion {
+	return Potion{recipe: recipe{
+		input:  []Item{input, reagent},
+		output: []item.Stack{output},
+		block:  "brewing_stand",
+	}}
+}
+
+// PotionContainerChange is a recipe that may be brewed in a brewing stand. It changes the container of a potion
+// while keeping the type of the potion, such as turning a drinkable potion into a splash potion using gunpowder.
+type PotionContainerChange struct {
+	recipe
+}
+
+// NewPotionContainerChange creates a new potion container change recipe and returns it. Potions held in the
+// input container are turned into potions of the same type held in the output container when brewed with the
+// reagent passed.
+func NewPotionContainerChange(input, output world.Item, reagent Item) PotionContainerChange {
+	return PotionContainerChange{recipe: recipe{
+		input:  []Item{item.NewStack(input, 1), reagent},
+		output: []item.Stack{item.NewStack(output, 1)},
+		block:  "brewing_stand",
+	}}
+}
+
+// brewKey identifies a brewing recipe by the name and meta of its input and reagent. For a
+// PotionContainerChange, the meta of the input is always 0, as the potion type is kept.
+type brewKey struct {
+	input       string
+	inputMeta   int16
+	reagent     string
+	reagentMeta int16
+}
+
+var (
+	// potions holds all Potion recipes with item.Stack inputs and reagents, indexed by their brewKey.
+	potions = map[brewKey]Potion{}
+	// containerChanges holds all PotionContainerChange recipes with an item.Stack reagent, indexed by their
+	// brewKey.
+	containerChanges = map[brewKey]PotionContainerChange{}
+	// brewingTags holds all brewing recipes with an ItemTag input or reagent, which cannot be indexed by name.
+	brewingTags []Recipe
+)
+
+// indexBrewing adds the Recipe passed to the index used by Brew if it is a Potion or PotionContainerChange.
+// If a recipe with the same input and reagent was already registered, the earlier recipe is kept.
+func indexBrewing(r Recipe) {
+	switch r := r.(type) {
+	case Potion:
+		input, okInput := r.input[0].(item.Stack)
+		reagent, okReagent := r.input[1].(item.Stack)
+		if !okInput || !okReagent {
+			brewingTags = append(brewingTags, r)
+			return
+		}
+		k := newBrewKey(input, reagent, true)
+		if _, ok := potions[k]; !ok {
+			potions[k] = r
+		}
+	case PotionContainerChange:
+		reagent, ok := r.input[1].(item.Stack)
+		if !ok {
+			brewingTags = append(brewingTags, r)
+			return
+		}
+		k := newBrewKey(r.input[0].(item.Stack), reagent, false)
+		if _, ok := containerChanges[k]; !ok {
+			containerChanges[k] = r
+		}
+	}
+}
+
+// newBrewKey creates a brewKey for the input and reagent passed. If inputMeta is false, the meta of the input
+// is left out.
+func newBrewKey(input, reagent item.Stack, inputMeta bool) brewKey {
+	k := brewKey{}
+	k.input, k.inputMeta = input.Item().EncodeItem()
+	k.reagent, k.reagentMeta = reagent.Item().EncodeItem()
+	if !inputMeta {
+		k.inputMeta = 0
+	}
+	return k
+}
+
+// Brew returns the result of brewing the input passed with the reagent passed in a brewing stand. False is
+// returned if no Potion or PotionContainerChange recipe exists for the combination.
+func Brew(input, reagent item.Stack) (item.Stack, bool) {
+	if input.Empty() || reagent.Empty() {
+		return item.Stack{}, false
+	}
+	loadVanilla()
+	if r, ok := potions[newBrewKey(input, reagent, true)]; ok {
+		return r.output[0], true
+	}
+	if r, ok := containerChanges[newBrewKey(input, reagent, false)]; ok {
+		return changeContainer(r, input)
+	}
+	for _, r := range brewingTags {
+		switch r := r.(type) {
+		case Potion:
+			if matchesItem(r.input[0], input, true) && matchesItem(r.input[1], reagent, true) {
+				return r.output[0], true
+			}
+		case PotionContainerChange:
+			if matchesItem(r.input[0], input, false) && matchesItem(r.input[1], reagent, true) {
+				return changeContainer(r, input)
+			}
+		}
+	}
+	return item.Stack{}, false
+}
+
+// changeContainer returns the output of the PotionContainerChange passed for the input passed. The output is
+// held in the container of the recipe, but keeps the potion type of the input, which is stored in its meta.
+func changeContainer(r PotionContainerChange, input item.Stack) (item.Stack, bool) {
+	_, meta := input.Item().EncodeItem()
+	name, _ := r.output[0].Item().EncodeItem()
+	if it, ok := world.ItemByName(name, meta); ok {
+		return item.NewStack(it, 1), true
+	}
+	return item.Stack{}, false
+}
+
+// matchesItem checks if the item stack passed matches the recipe item. If meta is false, only the names of the
+// items are compared.
+func matchesItem(i Item, s item.Stack, meta bool) bool {
+	name, m := s.Item().EncodeItem()
+	switch i := i.(type) {
+	case item.Stack:
+		otherName, otherMeta := i.Item().EncodeItem()
+		return name == otherName && (!meta || m == otherMeta)
+	case ItemTag:
+		return i.Contains(name)
+	}
+	return false
+}
@@ -2,17 +2,43 @@ package recipe
 
 import (
 	"slices"
+	"sync"
 )
 
-// recipes is a list of each recipe.
-var recipes []Recipe
+var (
+	// recipes is a list of each recipe.
+	recipes []Recipe
+	// vanillaOnce is used to register the vanilla recipes once, the first time recipes are requested.
+	vanillaOnce sync.Once
+)
 
-// Recipes returns each recipe in a slice.
+// Recipes returns each recipe in a slice. Vanilla recipes are always returned before recipes passed to
+// Register, regardless of when Register was called.
 func Recipes() []Recipe {
+	loadVanilla()
 	return slices.Clone(recipes)
 }
 
 // Register registers a new recipe.
 func Register(recipe Recipe) {
 	recipes = append(recipes, recipe)
+	indexBrewing(recipe)
+}
+
+// loadVanilla registers the vanilla recipes if this has not yet been done. Recipes registered using Register
+// before the vanilla recipes were loaded are registered again after them, so that the vanilla recipes always
+// come first.
+func loadVanilla() {
+	vanillaOnce.Do(func() {
+		custom := recipes
+		recipes = nil
+		clear(potions)
+		clear(containerChanges)
+		brewingTags = nil
+
+		registerVanilla()
+		for _, r := range custom {
+			Register(r)
+		}
+	})
 }
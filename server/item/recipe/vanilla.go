@@ -2,10 +2,8 @@ package recipe
 
 import (
 	_ "embed"
-	// Ensure all blocks and items are registered before trying to load vanilla recipes.
+
 	"github.com/sandertv/gophertunnel/minecraft/nbt"
-	_ "github.com/stcraft/dragonfly/server/block"
-	_ "github.com/stcraft/dragonfly/server/item"
 )
 
 var (
@@ -33,7 +31,10 @@ type shapelessRecipe struct {
 	Priority int32       `nbt:"priority"`
 }
 
-func init() {
+// registerVanilla registers all vanilla recipes. It is called the first time recipes are requested rather than
+// when the package is initialised, so that the blocks and items used in the recipes, some of which rely on this
+// package, are registered by then.
+func registerVanilla() {
 	var craftingRecipes struct {
 		Shaped    []shapedRecipe    `nbt:"shaped"`
 		Shapeless []shapelessRecipe `nbt:"shapeless"`
@@ -94,4 +95,6 @@ func init() {
 			priority: uint32(s.Priority),
 		}})
 	}
+
+	registerVanillaBrewing()
 }
@@ -0,0 +1,115 @@
+package recipe
+
+import (
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/item/potion"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// potionMix is a vanilla potion mix: A potion of the input type brewed with the reagent becomes a potion of the
+// output type.
+type potionMix struct {
+	input   potion.Potion
+	reagent string
+	output  potion.Potion
+}
+
+// vanillaPotionMixes holds all vanilla potion mixes. Each of these mixes may be brewed with drinkable, splash and
+// lingering potions.
+var vanillaPotionMixes = []potionMix{
+	{potion.Water(), "minecraft:nether_wart", potion.Awkward()},
+	{potion.Water(), "minecraft:redstone", potion.LongMundane()},
+	{potion.Water(), "minecraft:glowstone_dust", potion.Thick()},
+	{potion.Water(), "minecraft:glistering_melon_slice", potion.Mundane()},
+	{potion.Water(), "minecraft:ghast_tear", potion.Mundane()},
+	{potion.Water(), "minecraft:rabbit_foot", potion.Mundane()},
+	{potion.Water(), "minecraft:blaze_powder", potion.Mundane()},
+	{potion.Water(), "minecraft:spider_eye", potion.Mundane()},
+	{potion.Water(), "minecraft:sugar", potion.Mundane()},
+	{potion.Water(), "minecraft:magma_cream", potion.Mundane()},
+	{potion.Water(), "minecraft:fermented_spider_eye", potion.Weakness()},
+
+	{potion.Awkward(), "minecraft:golden_carrot", potion.NightVision()},
+	{potion.NightVision(), "minecraft:redstone", potion.LongNightVision()},
+	{potion.NightVision(), "minecraft:fermented_spider_eye", potion.Invisibility()},
+	{potion.LongNightVision(), "minecraft:fermented_spider_eye", potion.LongInvisibility()},
+	{potion.Invisibility(), "minecraft:redstone", potion.LongInvisibility()},
+
+	{potion.Awkward(), "minecraft:rabbit_foot", potion.Leaping()},
+	{potion.Leaping(), "minecraft:redstone", potion.LongLeaping()},
+	{potion.Leaping(), "minecraft:glowstone_dust", potion.StrongLeaping()},
+	{potion.Leaping(), "minecraft:fermented_spider_eye", potion.Slowness()},
+	{potion.LongLeaping(), "minecraft:fermented_spider_eye", potion.LongSlowness()},
+
+	{potion.Awkward(), "minecraft:magma_cream", potion.FireResistance()},
+	{potion.FireResistance(), "minecraft:redstone", potion.LongFireResistance()},
+	{potion.FireResistance(), "minecraft:fermented_spider_eye", potion.Slowness()},
+	{potion.LongFireResistance(), "minecraft:fermented_spider_eye", potion.LongSlowness()},
+
+	{potion.Awkward(), "minecraft:sugar", potion.Swiftness()},
+	{potion.Swiftness(), "minecraft:redstone", potion.LongSwiftness()},
+	{potion.Swiftness(), "minecraft:glowstone_dust", potion.StrongSwiftness()},
+	{potion.Swiftness(), "minecraft:fermented_spider_eye", potion.Slowness()},
+	{potion.LongSwiftness(), "minecraft:fermented_spider_eye", potion.LongSlowness()},
+	{potion.Slowness(), "minecraft:redstone", potion.LongSlowness()},
+	{potion.Slowness(), "minecraft:glowstone_dust", potion.StrongSlowness()},
+
+	{potion.Awkward(), "minecraft:pufferfish", potion.WaterBreathing()},
+	{potion.WaterBreathing(), "minecraft:redstone", potion.LongWaterBreathing()},
+
+	{potion.Awkward(), "minecraft:glistering_melon_slice", potion.Healing()},
+	{potion.Healing(), "minecraft:glowstone_dust", potion.StrongHealing()},
+	{potion.Healing(), "minecraft:fermented_spider_eye", potion.Harming()},
+	{potion.StrongHealing(), "minecraft:fermented_spider_eye", potion.StrongHarming()},
+	{potion.Harming(), "minecraft:glowstone_dust", potion.StrongHarming()},
+
+	{potion.Awkward(), "minecraft:spider_eye", potion.Poison()},
+	{potion.Poison(), "minecraft:redstone", potion.LongPoison()},
+	{potion.Poison(), "minecraft:glowstone_dust", potion.StrongPoison()},
+	{potion.Poison(), "minecraft:fermented_spider_eye", potion.Harming()},
+	{potion.LongPoison(), "minecraft:fermented_spider_eye", potion.Harming()},
+	{potion.StrongPoison(), "minecraft:fermented_spider_eye", potion.StrongHarming()},
+
+	{potion.Awkward(), "minecraft:ghast_tear", potion.Regeneration()},
+	{potion.Regeneration(), "minecraft:redstone", potion.LongRegeneration()},
+	{potion.Regeneration(), "minecraft:glowstone_dust", potion.StrongRegeneration()},
+
+	{potion.Awkward(), "minecraft:blaze_powder", potion.Strength()},
+	{potion.Strength(), "minecraft:redstone", potion.LongStrength()},
+	{potion.Strength(), "minecraft:glowstone_dust", potion.StrongStrength()},
+	{potion.Weakness(), "minecraft:redstone", potion.LongWeakness()},
+
+	{potion.Awkward(), "minecraft:turtle_helmet", potion.TurtleMaster()},
+	{potion.TurtleMaster(), "minecraft:redstone", potion.LongTurtleMaster()},
+	{potion.TurtleMaster(), "minecraft:glowstone_dust", potion.StrongTurtleMaster()},
+
+	{potion.Awkward(), "minecraft:phantom_membrane", potion.SlowFalling()},
+	{potion.SlowFalling(), "minecraft:redstone", potion.LongSlowFalling()},
+}
+
+// registerVanillaBrewing registers all vanilla potion mixes for drinkable, splash and lingering potions, and the
+// container changes that turn drinkable potions into splash potions and splash potions into lingering potions.
+func registerVanillaBrewing() {
+	containers := []func(t potion.Potion) world.Item{
+		func(t potion.Potion) world.Item { return item.Potion{Type: t} },
+		func(t potion.Potion) world.Item { return item.SplashPotion{Type: t} },
+		func(t potion.Potion) world.Item { return item.LingeringPotion{Type: t} },
+	}
+	for _, mix := range vanillaPotionMixes {
+		reagent, ok := world.ItemByName(mix.reagent, 0)
+		if !ok {
+			// This can be expected to happen, as some reagents aren't currently implemented.
+			continue
+		}
+		for _, container := range containers {
+			Register(NewPotion(item.NewStack(container(mix.input), 1), item.NewStack(reagent, 1), item.NewStack(container(mix.output), 1)))
+		}
+	}
+
+	if gunpowder, ok := world.ItemByName("minecraft:gunpowder", 0); ok {
+		Register(NewPotionContainerChange(item.Potion{}, item.SplashPotion{}, item.NewStack(gunpowder, 1)))
+	}
+	if dragonBreath, ok := world.ItemByName("minecraft:dragon_breath", 0); ok {
+		Register(NewPotionContainerChange(item.SplashPotion{}, item.LingeringPotion{}, item.NewStack(dragonBreath, 1)))
+	}
+}
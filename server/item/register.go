@@ -40,6 +40,7 @@ func init() {
 	world.RegisterItem(Compass{})
 	world.RegisterItem(Cookie{})
 	world.RegisterItem(CopperIngot{})
+	world.RegisterItem(Crossbow{})
 	world.RegisterItem(Diamond{})
 	world.RegisterItem(DiscFragment{})
 	world.RegisterItem(DragonBreath{})
@@ -50,11 +51,13 @@ func init() {
 	world.RegisterItem(Emerald{})
 	world.RegisterItem(EnchantedApple{})
 	world.RegisterItem(EnchantedBook{})
+	world.RegisterItem(EnderEye{})
 	world.RegisterItem(EnderPearl{})
 	world.RegisterItem(Feather{})
 	world.RegisterItem(FermentedSpiderEye{})
 	world.RegisterItem(FireCharge{})
 	world.RegisterItem(Firework{})
+	world.RegisterItem(FishingRod{})
 	world.RegisterItem(FlintAndSteel{})
 	world.RegisterItem(Flint{})
 	world.RegisterItem(GhastTear{})
@@ -109,6 +112,7 @@ func init() {
 	world.RegisterItem(Salmon{})
 	world.RegisterItem(Scute{})
 	world.RegisterItem(Shears{})
+	world.RegisterItem(Shield{})
 	world.RegisterItem(ShulkerShell{})
 	world.RegisterItem(Slimeball{})
 	world.RegisterItem(Snowball{})
@@ -116,11 +120,18 @@ func init() {
 	world.RegisterItem(Spyglass{})
 	world.RegisterItem(Stick{})
 	world.RegisterItem(Sugar{})
+	world.RegisterItem(Trident{})
 	world.RegisterItem(TropicalFish{})
 	world.RegisterItem(TurtleShell{})
 	world.RegisterItem(WarpedFungusOnAStick{})
 	world.RegisterItem(Wheat{})
 	world.RegisterItem(WrittenBook{})
+	for _, t := range BoatTypes() {
+		world.RegisterItem(Boat{Type: t})
+	}
+	for _, t := range MinecartTypes() {
+		world.RegisterItem(Minecart{Type: t})
+	}
 	for _, t := range ArmourTiers() {
 		world.RegisterItem(Helmet{Tier: t})
 		world.RegisterItem(Chestplate{Tier: t})
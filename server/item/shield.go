@@ -0,0 +1,51 @@
+package item
+
+import "time"
+
+// Shield is a defensive item that protects the holder from attacks and projectiles while it is raised. A shield is
+// raised by sneaking while holding it in either hand, or by using it while holding it in the main hand.
+type Shield struct{}
+
+// MaxCount always returns 1.
+func (Shield) MaxCount() int {
+	return 1
+}
+
+// OffHand ...
+func (Shield) OffHand() bool {
+	return true
+}
+
+// DurabilityInfo ...
+func (Shield) DurabilityInfo() DurabilityInfo {
+	return DurabilityInfo{
+		MaxDurability: 337,
+		BrokenItem:    simpleItem(Stack{}),
+	}
+}
+
+// RepairableBy ...
+func (Shield) RepairableBy(i Stack) bool {
+	return toolTierRepairable(ToolTierWood)(i)
+}
+
+// FuelInfo ...
+func (Shield) FuelInfo() FuelInfo {
+	return newFuelInfo(time.Second * 15)
+}
+
+// Release does nothing: A shield is raised for as long as it is being used, and lowered once it is released.
+func (Shield) Release(Releaser, time.Duration, *UseContext) {}
+
+// Requirements ...
+func (Shield) Requirements() []Stack {
+	return nil
+}
+
+// ShieldDisableDuration is the duration for which a shield is disabled after it blocks an attack by an axe.
+const ShieldDisableDuration = time.Second * 5
+
+// EncodeItem ...
+func (Shield) EncodeItem() (name string, meta int16) {
+	return "minecraft:shield", 0
+}
@@ -159,6 +159,17 @@ func (s Stack) Item() world.Item {
 	return s.item
 }
 
+// WithItem returns a copy of the Stack with the item type passed, keeping the count, damage, custom name, lore,
+// values and enchantments of the original Stack. This is useful for items that change their own state, such as
+// a crossbow being loaded.
+func (s Stack) WithItem(t world.Item) Stack {
+	if t == nil {
+		panic("cannot have a stack with item type nil")
+	}
+	s.item = t
+	return s
+}
+
 // AttackDamage returns the attack damage to the stack. By default, the value returned is 1.0. If the item
 // held implements the item.Weapon interface, this damage may be different.
 func (s Stack) AttackDamage() float64 {
@@ -0,0 +1,113 @@
+package item
+
+import (
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/sound"
+)
+
+// Trident is a weapon that may be used in melee combat or thrown at entities. A trident enchanted with riptide
+// launches its user through water and rain instead of being thrown.
+type Trident struct{}
+
+// MaxCount always returns 1.
+func (Trident) MaxCount() int {
+	return 1
+}
+
+// AttackDamage ...
+func (Trident) AttackDamage() float64 {
+	return 8
+}
+
+// HandEquipped ...
+func (Trident) HandEquipped() bool {
+	return true
+}
+
+// DurabilityInfo ...
+func (Trident) DurabilityInfo() DurabilityInfo {
+	return DurabilityInfo{
+		MaxDurability:    251,
+		BrokenItem:       simpleItem(Stack{}),
+		AttackDurability: 1,
+		BreakDurability:  2,
+	}
+}
+
+// EnchantmentValue ...
+func (Trident) EnchantmentValue() int {
+	return 1
+}
+
+// Release throws the trident if it was held for at least half a second. If the trident is enchanted with riptide,
+// the releaser is launched in the direction it is looking instead, as long as it is in water or rain.
+func (t Trident) Release(releaser Releaser, duration time.Duration, ctx *UseContext) {
+	if duration < time.Second/2 {
+		return
+	}
+	held, _ := releaser.HeldItems()
+	if held.Durability() <= 1 {
+		// A trident that is about to break can no longer be thrown.
+		return
+	}
+	w := releaser.World()
+	for _, enchant := range held.Enchantments() {
+		if r, ok := enchant.Type().(interface{ RiptideForce(int) float64 }); ok {
+			t.riptide(releaser, r.RiptideForce(enchant.Level()), enchant.Level(), ctx)
+			return
+		}
+	}
+
+	rot := releaser.Rotation()
+	rot = cube.Rotation{-rot[0], -rot[1]}
+	if rot[0] > 180 {
+		rot[0] = 360 - rot[0]
+	}
+	creative := releaser.GameMode().CreativeInventory()
+	thrown := held
+	if !creative {
+		thrown = held.Damage(1)
+		ctx.SubtractFromCount(1)
+	}
+
+	create := w.EntityRegistry().Config().Trident
+	w.AddEntity(create(eyePosition(releaser), releaser.Rotation().Vec3().Mul(2.5), rot, releaser, thrown, creative))
+	w.PlaySound(releaser.Position(), sound.TridentThrow{})
+}
+
+// riptide launches the releaser passed with the force passed, provided it is in water or in the rain.
+func (Trident) riptide(releaser Releaser, force float64, level int, ctx *UseContext) {
+	w, pos := releaser.World(), releaser.Position()
+	if !tridentWet(w, cube.PosFromVec3(pos)) && !tridentWet(w, cube.PosFromVec3(eyePosition(releaser))) {
+		return
+	}
+	v, ok := releaser.(interface{ SetVelocity(vel mgl64.Vec3) })
+	if !ok {
+		return
+	}
+	v.SetVelocity(releaser.Rotation().Vec3().Mul(force))
+	ctx.DamageItem(1)
+	w.PlaySound(pos, sound.TridentRiptide{Level: level})
+}
+
+// tridentWet checks if the position passed is in water or in the rain.
+func tridentWet(w *world.World, pos cube.Pos) bool {
+	if l, ok := w.Liquid(pos); ok && l.LiquidType() == "water" {
+		return true
+	}
+	return w.RainingAt(pos)
+}
+
+// Requirements ...
+func (Trident) Requirements() []Stack {
+	return nil
+}
+
+// EncodeItem ...
+func (Trident) EncodeItem() (name string, meta int16) {
+	return "minecraft:trident", 0
+}
@@ -0,0 +1,31 @@
+package permission
+
+import (
+	"github.com/stcraft/dragonfly/server/cmd"
+)
+
+// Allower is a cmd.Allower that allows only sources holding a specific permission. An Allower may be embedded
+// in a cmd.Runnable to limit the sources that may see and run the command:
+//
+//	type Ban struct {
+//	    permission.Allower
+//	    Target []cmd.Target
+//	}
+//
+//	cmd.Register(cmd.New("ban", "Bans a player.", nil, Ban{Allower: m.Allower("server.command.ban")}))
+//
+// An Allower is obtained through a call to Manager.Allower. The zero value of Allower allows only sources
+// that do not implement Holder, such as the console.
+type Allower struct {
+	m          *Manager
+	permission string
+}
+
+// Allow checks if the cmd.Source passed holds the permission of the Allower.
+func (a Allower) Allow(src cmd.Source) bool {
+	if a.m == nil {
+		_, ok := src.(Holder)
+		return !ok
+	}
+	return a.m.HasPermission(src, a.permission)
+}
@@ -0,0 +1,11 @@
+// Package permission implements operators and permission nodes for players. Permission nodes are dot separated
+// strings, such as 'minecraft.command.gamemode', which may be granted or denied to a player directly through
+// their Set, or indirectly through the Groups that they are part of. A node ending with '*' matches all nodes
+// that start with the part before it, so that 'minecraft.command.*' grants all vanilla commands.
+//
+// A Manager holds the Groups of a server and resolves whether a cmd.Source holds a permission. Commands may
+// embed an Allower, obtained through Manager.Allower, to only be visible and runnable for sources holding a
+// specific permission. The Manager also holds the list of operators of the server, identified by the UUID of
+// the player. The Groups and operators of a Manager are held in memory, but may be persisted to a JSON file
+// using Manager.SaveFile and loaded again on startup using Manager.LoadFile.
+package permission
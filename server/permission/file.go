@@ -0,0 +1,80 @@
+package permission
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// managerFile is the JSON layout of a file holding the Groups and Operators of a Manager.
+type managerFile struct {
+	Default   string     `json:"default,omitempty"`
+	Groups    []Group    `json:"groups"`
+	Operators []Operator `json:"operators"`
+}
+
+// LoadFile replaces the Groups, the default Group and the Operators of the Manager with those stored in the
+// JSON file at the path passed, as written by SaveFile. If no file exists at the path, the Manager is left unchanged and no
+// error is returned.
+func (m *Manager) LoadFile(path string) error {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("load permissions: %w", err)
+	}
+	var f managerFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return fmt.Errorf("load permissions: decode %v: %w", path, err)
+	}
+	groups := make(map[string]Group, len(f.Groups))
+	for _, g := range f.Groups {
+		if g.Name == "" {
+			return fmt.Errorf("load permissions: group without name in %v", path)
+		}
+		groups[g.Name] = g
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.groups, m.def, m.ops = groups, f.Default, f.Operators
+	return nil
+}
+
+// SaveFile writes the Groups, the default Group and the Operators of the Manager to a JSON file at the path passed, so that
+// they may be loaded again using LoadFile. The file is first written to a temporary file which then replaces
+// the file at the path, so that the file is never left partially written.
+func (m *Manager) SaveFile(path string) error {
+	m.mu.RLock()
+	f := managerFile{Default: m.def, Groups: make([]Group, 0, len(m.groups)), Operators: slices.Clone(m.ops)}
+	for _, g := range m.groups {
+		f.Groups = append(f.Groups, g)
+	}
+	m.mu.RUnlock()
+	sort.Slice(f.Groups, func(i, j int) bool {
+		return f.Groups[i].Name < f.Groups[j].Name
+	})
+
+	b, err := json.MarshalIndent(f, "", "\t")
+	if err != nil {
+		return fmt.Errorf("save permissions: encode: %w", err)
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0777); err != nil {
+			return fmt.Errorf("save permissions: %w", err)
+		}
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return fmt.Errorf("save permissions: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("save permissions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package permission
+
+import (
+	"strings"
+)
+
+// Group is a named collection of permission nodes that may be shared by many players. Players are added to a
+// Group using Set.AddGroup.
+type Group struct {
+	// Name is the name of the Group, such as 'moderator'. Sets refer to the Group by this name.
+	Name string `json:"name"`
+	// Nodes holds the permission nodes of the Group. Nodes mapped to true are granted, while nodes mapped to
+	// false are denied.
+	Nodes map[string]bool `json:"nodes,omitempty"`
+	// Inherits holds the names of Groups that this Group inherits permissions from. Nodes of the Group itself
+	// take precedence over nodes of the Groups it inherits from, and earlier Groups take precedence over later
+	// ones.
+	Inherits []string `json:"inherits,omitempty"`
+}
+
+// match looks up the permission passed in a map of nodes. The exact permission is tried first, after which
+// wildcard nodes are tried from most to least specific: 'a.b.c' first tries 'a.b.c', then 'a.b.*', 'a.*'
+// and finally '*'. The value of the first node found is returned along with true.
+func match(nodes map[string]bool, permission string) (v, ok bool) {
+	if len(nodes) == 0 {
+		return false, false
+	}
+	if v, ok := nodes[permission]; ok {
+		return v, true
+	}
+	for i := strings.LastIndexByte(permission, '.'); i != -1; i = strings.LastIndexByte(permission, '.') {
+		permission = permission[:i]
+		if v, ok := nodes[permission+".*"]; ok {
+			return v, true
+		}
+	}
+	v, ok = nodes["*"]
+	return v, ok
+}
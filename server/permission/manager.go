@@ -0,0 +1,126 @@
+package permission
+
+import (
+	"sync"
+
+	"github.com/stcraft/dragonfly/server/cmd"
+)
+
+// Manager holds the Groups of a server and resolves whether a cmd.Source holds a permission. Manager
+// implements the Permissions interface of the vanilla command package, so that it may be passed to
+// vanilla.Register directly. Methods on Manager may be called from multiple goroutines concurrently.
+type Manager struct {
+	mu     sync.RWMutex
+	groups map[string]Group
+	def    string
+	ops    []Operator
+}
+
+// NewManager creates a new Manager without any Groups.
+func NewManager() *Manager {
+	return &Manager{groups: make(map[string]Group)}
+}
+
+// AddGroup adds a Group to the Manager. An existing Group with the same name is replaced.
+func (m *Manager) AddGroup(g Group) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.groups[g.Name] = g
+}
+
+// RemoveGroup removes the Group with the name passed from the Manager. Sets that are part of the Group
+// remain so, but no longer receive any permissions through it until a Group with the same name is added.
+func (m *Manager) RemoveGroup(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.groups, name)
+}
+
+// Group looks up a Group by its name. If not found, false is returned.
+func (m *Manager) Group(name string) (Group, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	g, ok := m.groups[name]
+	return g, ok
+}
+
+// Groups returns all Groups added to the Manager.
+func (m *Manager) Groups() []Group {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	groups := make([]Group, 0, len(m.groups))
+	for _, g := range m.groups {
+		groups = append(groups, g)
+	}
+	return groups
+}
+
+// SetDefaultGroup sets the name of the Group that every Set is implicitly part of. The default Group is
+// consulted after all Groups that a Set was explicitly added to. Passing an empty string disables the
+// default Group.
+func (m *Manager) SetDefaultGroup(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.def = name
+}
+
+// HasPermission checks if the cmd.Source passed holds the permission passed. Sources that do not implement
+// Holder, such as the console, hold all permissions. For a Holder, the permission is resolved in order
+// through the nodes of its Set, its operator status, the Groups of the Set and finally the default Group.
+func (m *Manager) HasPermission(src cmd.Source, permission string) bool {
+	h, ok := src.(Holder)
+	if !ok {
+		return true
+	}
+	return m.Resolve(h.Permissions(), permission)
+}
+
+// Resolve checks if the Set passed holds the permission passed. Resolve may be used to check the permissions
+// of a Set that does not belong to a cmd.Source, such as that of an offline player.
+func (m *Manager) Resolve(s *Set, permission string) bool {
+	if v, ok := s.lookup(permission); ok {
+		return v
+	}
+	if s.Operator() {
+		return true
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	visited := make(map[string]struct{})
+	for _, name := range append(s.Groups(), m.def) {
+		if v, ok := m.resolveGroup(name, permission, visited); ok {
+			return v
+		}
+	}
+	return false
+}
+
+// Allower returns an Allower that allows only sources holding the permission passed.
+func (m *Manager) Allower(permission string) Allower {
+	return Allower{m: m, permission: permission}
+}
+
+// resolveGroup looks up the permission passed in the Group with the name passed and, if not found, in the
+// Groups it inherits from. Groups already present in visited are skipped, so that cyclic inheritance does not
+// lead to infinite recursion. resolveGroup must be called with m.mu held.
+func (m *Manager) resolveGroup(name, permission string, visited map[string]struct{}) (v, ok bool) {
+	if _, seen := visited[name]; seen || name == "" {
+		return false, false
+	}
+	visited[name] = struct{}{}
+
+	g, found := m.groups[name]
+	if !found {
+		return false, false
+	}
+	if v, ok := match(g.Nodes, permission); ok {
+		return v, true
+	}
+	for _, parent := range g.Inherits {
+		if v, ok := m.resolveGroup(parent, permission, visited); ok {
+			return v, true
+		}
+	}
+	return false, false
+}
@@ -0,0 +1,83 @@
+package permission
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Operator is an entry in the operator list of a Manager. Operators are identified by the UUID of the player.
+// An Operator added by name only, such as a player that never joined the server, has a zero UUID until a
+// player with that name joins, after which the Operator is bound to the UUID of that player.
+type Operator struct {
+	// UUID is the UUID of the player. It is uuid.Nil if the player has not yet joined since being added.
+	UUID uuid.UUID `json:"uuid"`
+	// Name is the name of the player when it was added or when it last joined.
+	Name string `json:"name"`
+}
+
+// AddOperator adds the player with the UUID and name passed to the operator list of the Manager. The UUID
+// may be uuid.Nil for a player that is not online, in which case the player is bound by its name when it
+// joins. False is returned if the player was already an operator.
+func (m *Manager) AddOperator(id uuid.UUID, name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.operatorIndex(id, name) != -1 {
+		return false
+	}
+	m.ops = append(m.ops, Operator{UUID: id, Name: name})
+	return true
+}
+
+// RemoveOperator removes the player with the UUID and name passed from the operator list of the Manager. If
+// the UUID is uuid.Nil, the Operator is looked up by its name. False is returned if the player was not an
+// operator.
+func (m *Manager) RemoveOperator(id uuid.UUID, name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.operatorIndex(id, name)
+	if i == -1 {
+		return false
+	}
+	m.ops = slices.Delete(m.ops, i, i+1)
+	return true
+}
+
+// IsOperator checks if the player with the UUID and name passed is in the operator list of the Manager. If
+// the player was added by name only, it is bound to the UUID passed. The name stored for the Operator is
+// updated to the name passed.
+func (m *Manager) IsOperator(id uuid.UUID, name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.operatorIndex(id, name)
+	if i == -1 {
+		return false
+	}
+	if id != uuid.Nil {
+		m.ops[i] = Operator{UUID: id, Name: name}
+	}
+	return true
+}
+
+// Operators returns all entries in the operator list of the Manager.
+func (m *Manager) Operators() []Operator {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.ops)
+}
+
+// operatorIndex returns the index of the Operator matching the UUID and name passed, or -1 if no Operator
+// matches. If the UUID is not uuid.Nil, Operators with that UUID match, as well as Operators with the name
+// passed that were not yet bound to a UUID. If the UUID is uuid.Nil, any Operator with the name passed
+// matches. Names are compared case-insensitively. operatorIndex must be called with m.mu held.
+func (m *Manager) operatorIndex(id uuid.UUID, name string) int {
+	if id != uuid.Nil {
+		if i := slices.IndexFunc(m.ops, func(op Operator) bool { return op.UUID == id }); i != -1 {
+			return i
+		}
+	}
+	return slices.IndexFunc(m.ops, func(op Operator) bool {
+		return (id == uuid.Nil || op.UUID == uuid.Nil) && strings.EqualFold(op.Name, name)
+	})
+}
@@ -0,0 +1,118 @@
+package permission
+
+import (
+	"maps"
+	"slices"
+	"sync"
+)
+
+// Holder is implemented by sources that have a Set of permissions, such as a *player.Player.
+type Holder interface {
+	// Permissions returns the Set holding the operator status, groups and permission nodes of the Holder.
+	Permissions() *Set
+}
+
+// Set holds the permissions of a single player: whether the player is an operator, the names of the Groups
+// the player is part of and the permission nodes that were granted or denied to the player directly.
+// Methods on Set may be called from multiple goroutines concurrently. The zero value of Set is a Set with no
+// permissions, ready to use.
+type Set struct {
+	mu       sync.RWMutex
+	operator bool
+	groups   []string
+	nodes    map[string]bool
+}
+
+// NewSet creates a new Set with the operator status, groups and nodes passed. Nodes mapped to true are
+// granted, while nodes mapped to false are explicitly denied.
+func NewSet(operator bool, groups []string, nodes map[string]bool) *Set {
+	return &Set{operator: operator, groups: slices.Clone(groups), nodes: maps.Clone(nodes)}
+}
+
+// Operator returns true if the Set belongs to an operator. Operators hold all permissions that were not
+// explicitly denied to them.
+func (s *Set) Operator() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.operator
+}
+
+// SetOperator changes the operator status of the Set. The operator status of a Set is not persisted: To make
+// a player an operator across sessions, add it to the operator list of the Manager using
+// Manager.AddOperator.
+func (s *Set) SetOperator(operator bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.operator = operator
+}
+
+// Grant grants the permission node passed. Any earlier Deny of the same node is overwritten.
+func (s *Set) Grant(node string) {
+	s.set(node, true)
+}
+
+// Deny explicitly denies the permission node passed, even if it is granted through a Group or through the
+// operator status of the Set.
+func (s *Set) Deny(node string) {
+	s.set(node, false)
+}
+
+// Unset removes a node previously passed to Grant or Deny, so that the permission is again resolved through
+// the Groups of the Set.
+func (s *Set) Unset(node string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.nodes, node)
+}
+
+// Nodes returns all permission nodes set directly. Nodes mapped to true are granted, while nodes mapped to
+// false are denied.
+func (s *Set) Nodes() map[string]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return maps.Clone(s.nodes)
+}
+
+// AddGroup adds the Set to the Group with the name passed. AddGroup has no effect if the Set was already part
+// of the Group.
+func (s *Set) AddGroup(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !slices.Contains(s.groups, name) {
+		s.groups = append(s.groups, name)
+	}
+}
+
+// RemoveGroup removes the Set from the Group with the name passed.
+func (s *Set) RemoveGroup(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.groups = slices.DeleteFunc(s.groups, func(g string) bool {
+		return g == name
+	})
+}
+
+// Groups returns the names of all Groups that the Set is part of, in the order that they were added.
+func (s *Set) Groups() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.groups)
+}
+
+// set sets a node in the Set to a specific value.
+func (s *Set) set(node string, v bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.nodes == nil {
+		s.nodes = make(map[string]bool)
+	}
+	s.nodes[node] = v
+}
+
+// lookup checks if the Set holds a node matching the permission passed. If found, the value of the most
+// specific node is returned, along with true.
+func (s *Set) lookup(permission string) (v, ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return match(s.nodes, permission)
+}
@@ -0,0 +1,59 @@
+// Package combat holds the types of violations that may be detected when a player attacks an entity with
+// combat validation enabled.
+package combat
+
+// Violation is a type of violation of the rules of combat by a player attacking an entity.
+type Violation struct {
+	violation
+}
+
+// Reach returns the Violation of a player attacking an entity that was further away than the player is able to
+// reach, taking the latency of the player into account.
+func Reach() Violation {
+	return Violation{0}
+}
+
+// ClickRate returns the Violation of a player clicking, either by attacking or swinging its arm, more often
+// per second than allowed.
+func ClickRate() Violation {
+	return Violation{1}
+}
+
+// Cooldown returns the Violation of a player attacking an entity before the attack cooldown since its previous
+// attack has passed.
+func Cooldown() Violation {
+	return Violation{2}
+}
+
+// LineOfSight returns the Violation of a player attacking an entity that it could not see because blocks were
+// in the way.
+func LineOfSight() Violation {
+	return Violation{3}
+}
+
+// Violations returns all types of Violation.
+func Violations() []Violation {
+	return []Violation{Reach(), ClickRate(), Cooldown(), LineOfSight()}
+}
+
+type violation uint8
+
+// Uint8 returns the violation as a uint8.
+func (v violation) Uint8() uint8 {
+	return uint8(v)
+}
+
+// String returns the violation as a string.
+func (v violation) String() string {
+	switch v {
+	case 0:
+		return "reach"
+	case 1:
+		return "click rate"
+	case 2:
+		return "cooldown"
+	case 3:
+		return "line of sight"
+	}
+	panic("should never happen")
+}
@@ -56,6 +56,13 @@ type Data struct {
 	FallDistance float64
 	// World is the world the player was last in.
 	World *world.World
+	// Groups holds the names of the permission groups that the player is part of.
+	Groups []string
+	// Permissions holds the permission nodes granted (true) or denied (false) to the player directly.
+	Permissions map[string]bool
+	// Extensions holds arbitrary data of plugins stored for the player. If nil, the player starts without
+	// any.
+	Extensions *Extensions
 }
 
 // InventoryData is a struct that contains all data of the player inventories.
@@ -0,0 +1,203 @@
+package player
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Extensions holds arbitrary data of plugins for a player, stored under namespaced names such as
+// "economy:coins". Values are accessed in a typed way using a Key, which encodes and decodes them using a
+// versioned Codec so that they may be persisted by a Provider together with the rest of the Data of a player.
+// Values that were loaded but not yet accessed, or that failed to decode, are kept in their encoded form, so
+// that saving never loses data of plugins that are not loaded or whose schema changed.
+// Extensions is safe for concurrent use.
+type Extensions struct {
+	mu     sync.Mutex
+	values map[string]extension
+}
+
+// RawExtension is the encoded form of a value held in Extensions, as stored by a Provider.
+type RawExtension struct {
+	// Version is the version of the Codec that the value was encoded with.
+	Version int
+	// Data holds the encoded value.
+	Data []byte
+}
+
+// extension is a single value held in Extensions, either encoded or decoded.
+type extension struct {
+	raw RawExtension
+	// value is the decoded value. encode is non-nil if the value was decoded or set, and is used to encode it
+	// again.
+	value  any
+	encode func() (RawExtension, error)
+}
+
+// NewExtensions returns Extensions holding the encoded values passed, keyed by their names. raw may be nil, in
+// which case the Extensions returned are empty.
+func NewExtensions(raw map[string]RawExtension) *Extensions {
+	e := &Extensions{values: make(map[string]extension, len(raw))}
+	for name, r := range raw {
+		e.values[name] = extension{raw: r}
+	}
+	return e
+}
+
+// Encode encodes all values held by the Extensions and returns them keyed by their names. Values that were
+// never decoded are returned as they were loaded. If a value fails to encode, the value it was decoded from, if
+// any, is returned for it instead, along with an error.
+func (e *Extensions) Encode() (map[string]RawExtension, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var err error
+	m := make(map[string]RawExtension, len(e.values))
+	for name, ext := range e.values {
+		if ext.encode == nil {
+			m[name] = ext.raw
+			continue
+		}
+		raw, encErr := ext.encode()
+		if encErr != nil {
+			err = fmt.Errorf("encode extension %v: %w", name, encErr)
+			if ext.raw.Data != nil {
+				m[name] = ext.raw
+			}
+			continue
+		}
+		m[name] = raw
+	}
+	return m, err
+}
+
+// Names returns the names of all values held by the Extensions.
+func (e *Extensions) Names() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	names := make([]string, 0, len(e.values))
+	for name := range e.values {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Key is a typed key of a value held in Extensions. A Key is created using NewKey and is usually stored in a
+// global variable by a plugin.
+type Key[T any] struct {
+	name  string
+	codec Codec[T]
+}
+
+// NewKey creates a Key for values of type T stored under the name passed, encoded using the Codec passed. The
+// name must be namespaced, such as "economy:coins", to prevent conflicts between plugins. NewKey panics if the
+// name has no namespace.
+func NewKey[T any](name string, codec Codec[T]) Key[T] {
+	if ns, path, ok := strings.Cut(name, ":"); !ok || ns == "" || path == "" {
+		panic(fmt.Sprintf("extension key %q must be of the form namespace:name", name))
+	}
+	return Key[T]{name: name, codec: codec}
+}
+
+// Name returns the namespaced name of the Key.
+func (k Key[T]) Name() string {
+	return k.name
+}
+
+// Get returns the value held by the Extensions passed for the Key. If no value is held, false is returned. An
+// error is returned if the value could not be decoded, in which case the encoded value is kept as is.
+func (k Key[T]) Get(e *Extensions) (T, bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var zero T
+	ext, ok := e.values[k.name]
+	if !ok {
+		return zero, false, nil
+	}
+	if ext.encode != nil {
+		v, ok := ext.value.(T)
+		if !ok {
+			return zero, true, fmt.Errorf("decode extension %v: value is of type %T, not %T", k.name, ext.value, zero)
+		}
+		return v, true, nil
+	}
+	v, err := k.codec.Decode(ext.raw.Version, ext.raw.Data)
+	if err != nil {
+		return zero, true, fmt.Errorf("decode extension %v (version %v): %w", k.name, ext.raw.Version, err)
+	}
+	e.values[k.name] = k.extension(v, ext.raw)
+	return v, true, nil
+}
+
+// Set sets the value held by the Extensions passed for the Key, replacing any existing value.
+func (k Key[T]) Set(e *Extensions, v T) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.values[k.name] = k.extension(v, RawExtension{})
+}
+
+// Delete removes the value held by the Extensions passed for the Key, if any.
+func (k Key[T]) Delete(e *Extensions) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.values, k.name)
+}
+
+// extension returns an extension holding the decoded value passed, that was decoded from raw.
+func (k Key[T]) extension(v T, raw RawExtension) extension {
+	return extension{raw: raw, value: v, encode: func() (RawExtension, error) {
+		b, err := k.codec.Encode(v)
+		return RawExtension{Version: k.codec.Version(), Data: b}, err
+	}}
+}
+
+// Codec encodes and decodes values of type T held in Extensions. A Codec has a version, which is stored along
+// with every value it encodes, so that values encoded with an older version may be migrated when decoded.
+type Codec[T any] interface {
+	// Version returns the current version of the Codec. Values are always encoded using the current version.
+	Version() int
+	// Encode encodes a value using the current version of the Codec.
+	Encode(v T) ([]byte, error)
+	// Decode decodes a value encoded with the version passed. Decode returns an error if the version is not
+	// supported, for example because it is newer than the current version.
+	Decode(version int, b []byte) (T, error)
+}
+
+// JSONCodec returns a Codec that encodes values of type T as JSON with the version passed. Values encoded with an
+// older version are passed to migrate, which converts them to a value of type T. If migrate is nil, values
+// encoded with an older version are decoded as if they had the current version.
+func JSONCodec[T any](version int, migrate func(version int, b []byte) (T, error)) Codec[T] {
+	return jsonCodec[T]{version: version, migrate: migrate}
+}
+
+// jsonCodec is the Codec returned by JSONCodec.
+type jsonCodec[T any] struct {
+	version int
+	migrate func(version int, b []byte) (T, error)
+}
+
+// Version ...
+func (c jsonCodec[T]) Version() int {
+	return c.version
+}
+
+// Encode ...
+func (c jsonCodec[T]) Encode(v T) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Decode ...
+func (c jsonCodec[T]) Decode(version int, b []byte) (T, error) {
+	var v T
+	switch {
+	case version > c.version:
+		return v, fmt.Errorf("version %v is newer than the current version %v", version, c.version)
+	case version < c.version && c.migrate != nil:
+		return c.migrate(version, b)
+	}
+	err := json.Unmarshal(b, &v)
+	return v, err
+}
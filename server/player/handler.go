@@ -5,10 +5,12 @@ import (
 	"time"
 
 	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block"
 	"github.com/stcraft/dragonfly/server/block/cube"
 	"github.com/stcraft/dragonfly/server/cmd"
 	"github.com/stcraft/dragonfly/server/event"
 	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/player/combat"
 	"github.com/stcraft/dragonfly/server/player/skin"
 	"github.com/stcraft/dragonfly/server/world"
 )
@@ -24,12 +26,31 @@ type Handler interface {
 	// HandleMove handles the movement of a player. ctx.Cancel() may be called to cancel the movement event.
 	// The new position, yaw and pitch are passed.
 	HandleMove(ctx *event.Context, newPos mgl64.Vec3, newYaw, newPitch float64)
+	// HandleMovementViolation handles the player moving to a position that diverges too much from the position
+	// that the server simulated for its input, if movement validation is enabled. expected is the simulated
+	// position and actual the position the player moved to. ctx.Cancel() may be called to accept the movement
+	// anyway. If not cancelled, the player is teleported back to its last valid position.
+	HandleMovementViolation(ctx *event.Context, expected, actual mgl64.Vec3)
 	// HandleJump handles the player jumping.
 	HandleJump()
 	// HandleTeleport handles the teleportation of a player. ctx.Cancel() may be called to cancel it.
 	HandleTeleport(ctx *event.Context, pos mgl64.Vec3)
 	// HandleChangeWorld handles when the player is added to a new world. before may be nil.
 	HandleChangeWorld(before, after *world.World)
+	// HandlePortal handles the player travelling through a portal at a position, such as a nether portal or an
+	// end portal. ctx.Cancel() may be called to prevent the player from travelling. The world that the player
+	// travels to may be changed by assigning to *destination. The player is placed at the position that the
+	// portal leads to in this world.
+	HandlePortal(ctx *event.Context, portal block.Portal, pos cube.Pos, destination **world.World)
+	// HandleSleep handles the player starting to sleep in the bed at the position passed. ctx.Cancel() may be
+	// called to prevent the player from sleeping.
+	HandleSleep(ctx *event.Context, pos cube.Pos)
+	// HandleWake handles the player waking up after sleeping in a bed. ctx.Cancel() may be called to keep the
+	// player sleeping.
+	HandleWake(ctx *event.Context)
+	// HandleMount handles the player starting to ride an entity, such as a boat or a minecart. ctx.Cancel() may
+	// be called to prevent the player from riding the entity.
+	HandleMount(ctx *event.Context, vehicle world.Entity)
 	// HandleToggleSprint handles when the player starts or stops sprinting.
 	// After is true if the player is sprinting after toggling (changing their sprinting state).
 	HandleToggleSprint(ctx *event.Context, after bool)
@@ -104,6 +125,11 @@ type Handler interface {
 	// spawn critical hit particles around the target entity. These particles will not be displayed
 	// if no damage is dealt.
 	HandleAttackEntity(ctx *event.Context, e world.Entity, force, height *float64, critical *bool)
+	// HandleCombatViolation handles the player attacking an entity in a way that violates the rules of combat,
+	// if combat validation is enabled. The type of violation and its violation level, which increases with
+	// every violation of the type and decreases over time, are passed. ctx.Cancel() may be called to allow
+	// the attack anyway. If not cancelled, the attack is ignored.
+	HandleCombatViolation(ctx *event.Context, target world.Entity, v combat.Violation, level float64)
 	// HandleExperienceGain handles the player gaining experience. ctx.Cancel() may be called to cancel
 	// the gain.
 	// The amount is also provided which can be modified.
@@ -148,37 +174,43 @@ type NopHandler struct {
 // Compile time check to make sure NopHandler implements Handler.
 var _ Handler = NopHandler{}
 
-func (NopHandler) New(p *Player) Handler                                                      { return NopHandler{} }
-func (NopHandler) HandleJoin(*event.Context)                                                  {}
-func (NopHandler) HandleItemDrop(*event.Context, world.Entity)                                {}
-func (NopHandler) HandleMove(*event.Context, mgl64.Vec3, float64, float64)                    {}
-func (NopHandler) HandleJump()                                                                {}
-func (NopHandler) HandleTeleport(*event.Context, mgl64.Vec3)                                  {}
-func (NopHandler) HandleChangeWorld(*world.World, *world.World)                               {}
-func (NopHandler) HandleToggleSprint(*event.Context, bool)                                    {}
-func (NopHandler) HandleToggleSneak(*event.Context, bool)                                     {}
-func (NopHandler) HandleCommandExecution(*event.Context, cmd.Command, []string)               {}
-func (NopHandler) HandleTransfer(*event.Context, *net.UDPAddr)                                {}
-func (NopHandler) HandleChat(*event.Context, *string)                                         {}
-func (NopHandler) HandleSkinChange(*event.Context, *skin.Skin)                                {}
-func (NopHandler) HandleStartBreak(*event.Context, cube.Pos)                                  {}
-func (NopHandler) HandleBlockBreak(*event.Context, cube.Pos, *[]item.Stack, *int)             {}
-func (NopHandler) HandleBlockPlace(*event.Context, cube.Pos, world.Block)                     {}
-func (NopHandler) HandleBlockPick(*event.Context, cube.Pos, world.Block)                      {}
-func (NopHandler) HandleSignEdit(*event.Context, bool, string, string)                        {}
-func (NopHandler) HandleLecternPageTurn(*event.Context, cube.Pos, int, *int)                  {}
-func (NopHandler) HandleItemPickup(*event.Context, *item.Stack)                               {}
-func (NopHandler) HandleItemUse(*event.Context)                                               {}
-func (NopHandler) HandleItemUseOnBlock(*event.Context, cube.Pos, cube.Face, mgl64.Vec3)       {}
-func (NopHandler) HandleItemUseOnEntity(*event.Context, world.Entity)                         {}
-func (NopHandler) HandleItemConsume(*event.Context, item.Stack)                               {}
-func (NopHandler) HandleItemDamage(*event.Context, item.Stack, int)                           {}
-func (NopHandler) HandleAttackEntity(*event.Context, world.Entity, *float64, *float64, *bool) {}
-func (NopHandler) HandleExperienceGain(*event.Context, *int)                                  {}
-func (NopHandler) HandlePunchAir(*event.Context)                                              {}
-func (NopHandler) HandleHurt(*event.Context, *float64, *time.Duration, world.DamageSource)    {}
-func (NopHandler) HandleHeal(*event.Context, *float64, world.HealingSource)                   {}
-func (NopHandler) HandleFoodLoss(*event.Context, int, *int)                                   {}
-func (NopHandler) HandleDeath(world.DamageSource, *bool)                                      {}
-func (NopHandler) HandleRespawn(*mgl64.Vec3, **world.World)                                   {}
-func (NopHandler) HandleQuit()                                                                {}
+func (NopHandler) New(p *Player) Handler                                                         { return NopHandler{} }
+func (NopHandler) HandleJoin(*event.Context)                                                     {}
+func (NopHandler) HandleItemDrop(*event.Context, world.Entity)                                   {}
+func (NopHandler) HandleMove(*event.Context, mgl64.Vec3, float64, float64)                       {}
+func (NopHandler) HandleMovementViolation(*event.Context, mgl64.Vec3, mgl64.Vec3)                {}
+func (NopHandler) HandleJump()                                                                   {}
+func (NopHandler) HandleTeleport(*event.Context, mgl64.Vec3)                                     {}
+func (NopHandler) HandleChangeWorld(*world.World, *world.World)                                  {}
+func (NopHandler) HandlePortal(*event.Context, block.Portal, cube.Pos, **world.World)            {}
+func (NopHandler) HandleSleep(*event.Context, cube.Pos)                                          {}
+func (NopHandler) HandleWake(*event.Context)                                                     {}
+func (NopHandler) HandleMount(*event.Context, world.Entity)                                      {}
+func (NopHandler) HandleToggleSprint(*event.Context, bool)                                       {}
+func (NopHandler) HandleToggleSneak(*event.Context, bool)                                        {}
+func (NopHandler) HandleCommandExecution(*event.Context, cmd.Command, []string)                  {}
+func (NopHandler) HandleTransfer(*event.Context, *net.UDPAddr)                                   {}
+func (NopHandler) HandleChat(*event.Context, *string)                                            {}
+func (NopHandler) HandleSkinChange(*event.Context, *skin.Skin)                                   {}
+func (NopHandler) HandleStartBreak(*event.Context, cube.Pos)                                     {}
+func (NopHandler) HandleBlockBreak(*event.Context, cube.Pos, *[]item.Stack, *int)                {}
+func (NopHandler) HandleBlockPlace(*event.Context, cube.Pos, world.Block)                        {}
+func (NopHandler) HandleBlockPick(*event.Context, cube.Pos, world.Block)                         {}
+func (NopHandler) HandleSignEdit(*event.Context, bool, string, string)                           {}
+func (NopHandler) HandleLecternPageTurn(*event.Context, cube.Pos, int, *int)                     {}
+func (NopHandler) HandleItemPickup(*event.Context, *item.Stack)                                  {}
+func (NopHandler) HandleItemUse(*event.Context)                                                  {}
+func (NopHandler) HandleItemUseOnBlock(*event.Context, cube.Pos, cube.Face, mgl64.Vec3)          {}
+func (NopHandler) HandleItemUseOnEntity(*event.Context, world.Entity)                            {}
+func (NopHandler) HandleItemConsume(*event.Context, item.Stack)                                  {}
+func (NopHandler) HandleItemDamage(*event.Context, item.Stack, int)                              {}
+func (NopHandler) HandleAttackEntity(*event.Context, world.Entity, *float64, *float64, *bool)    {}
+func (NopHandler) HandleCombatViolation(*event.Context, world.Entity, combat.Violation, float64) {}
+func (NopHandler) HandleExperienceGain(*event.Context, *int)                                     {}
+func (NopHandler) HandlePunchAir(*event.Context)                                                 {}
+func (NopHandler) HandleHurt(*event.Context, *float64, *time.Duration, world.DamageSource)       {}
+func (NopHandler) HandleHeal(*event.Context, *float64, world.HealingSource)                      {}
+func (NopHandler) HandleFoodLoss(*event.Context, int, *int)                                      {}
+func (NopHandler) HandleDeath(world.DamageSource, *bool)                                         {}
+func (NopHandler) HandleRespawn(*mgl64.Vec3, **world.World)                                      {}
+func (NopHandler) HandleQuit()                                                                   {}
@@ -19,12 +19,15 @@ import (
 	"github.com/stcraft/dragonfly/server/cmd"
 	"github.com/stcraft/dragonfly/server/entity"
 	"github.com/stcraft/dragonfly/server/entity/effect"
+	"github.com/stcraft/dragonfly/server/entity/trade"
 	"github.com/stcraft/dragonfly/server/event"
 	"github.com/stcraft/dragonfly/server/internal/sliceutil"
 	"github.com/stcraft/dragonfly/server/item"
 	"github.com/stcraft/dragonfly/server/item/enchantment"
 	"github.com/stcraft/dragonfly/server/item/inventory"
+	"github.com/stcraft/dragonfly/server/permission"
 	"github.com/stcraft/dragonfly/server/player/bossbar"
+	"github.com/stcraft/dragonfly/server/player/combat"
 	"github.com/stcraft/dragonfly/server/player/form"
 	"github.com/stcraft/dragonfly/server/player/scoreboard"
 	"github.com/stcraft/dragonfly/server/player/skin"
@@ -103,7 +106,26 @@ type Player struct {
 
 	breakParticleCounter atomic.Uint32
 
+	portalMu sync.Mutex
+	// portal is the portal that the player is currently inside of, positioned at portalPos. It is reset every
+	// tick.
+	portal    block.Portal
+	portalPos cube.Pos
+	// portalTicks is the amount of consecutive ticks that the player has spent inside a portal.
+	portalTicks int
+	// portalCooldown is the amount of ticks that the player must spend outside a portal before being able to
+	// travel through a portal again.
+	portalCooldown int
+
+	sleeping atomic.Bool
+	sleepPos atomic.Value[cube.Pos]
+
+	riding atomic.Value[entity.Rideable]
+
 	hunger *hungerManager
+
+	perms *permission.Set
+	ext   *Extensions
 }
 
 // New returns a new initialised player. A random UUID is generated for the player, so that it may be
@@ -141,6 +163,8 @@ func New(name string, skin skin.Skin, pos mgl64.Vec3) *Player {
 		pos:               *atomic.NewValue(pos),
 		cooldowns:         make(map[string]time.Time),
 		mc:                &entity.MovementComputer{Gravity: 0.08, Drag: 0.02, DragBeforeGravity: true},
+		perms:             &permission.Set{},
+		ext:               NewExtensions(nil),
 	}
 	return p
 }
@@ -633,6 +657,10 @@ func (p *Player) Hurt(dmg float64, src world.DamageSource) (float64, bool) {
 	if dmg < 0 {
 		return 0, true
 	}
+	if p.blockedBy(src) {
+		p.blockDamage(dmg, src)
+		return 0, false
+	}
 
 	totalDamage := p.FinalDamageFrom(dmg, src)
 	damageLeft := totalDamage
@@ -647,6 +675,7 @@ func (p *Player) Hurt(dmg float64, src world.DamageSource) (float64, bool) {
 		}
 	}
 	p.addHealth(-damageLeft)
+	p.Wake()
 
 	if src.ReducedByArmour() {
 		p.Exhaust(0.1)
@@ -682,6 +711,81 @@ func (p *Player) Hurt(dmg float64, src world.DamageSource) (float64, bool) {
 	return totalDamage, true
 }
 
+// Blocking checks if the Player is currently blocking with a shield. A Player blocks if it is using a shield held
+// in its main hand or if it is sneaking while holding a shield in either hand. A Player cannot block while its
+// shield is disabled.
+func (p *Player) Blocking() bool {
+	if p.HasCooldown(item.Shield{}) {
+		return false
+	}
+	main, off := p.HeldItems()
+	_, mainShield := main.Item().(item.Shield)
+	if p.UsingItem() {
+		return mainShield
+	}
+	_, offShield := off.Item().(item.Shield)
+	return p.Sneaking() && (mainShield || offShield)
+}
+
+// blockedBy checks if the damage from the source passed is blocked by the shield of the Player. Only attacks and
+// projectiles coming from in front of the Player are blocked.
+func (p *Player) blockedBy(src world.DamageSource) bool {
+	var origin mgl64.Vec3
+	switch s := src.(type) {
+	case entity.AttackDamageSource:
+		origin = s.Attacker.Position()
+	case entity.ProjectileDamageSource:
+		origin = s.Projectile.Position()
+	default:
+		return false
+	}
+	if !p.Blocking() {
+		return false
+	}
+	diff := origin.Sub(p.Position())
+	diff[1] = 0
+	dir := p.Rotation().Vec3()
+	dir[1] = 0
+	return diff.Dot(dir) > 0
+}
+
+// blockDamage handles the Player blocking the damage passed with its shield. The shield is damaged and disabled
+// if the attacker used an axe.
+func (p *Player) blockDamage(dmg float64, src world.DamageSource) {
+	w := p.World()
+	w.PlaySound(p.Position(), sound.ShieldBlock{})
+
+	if dmg >= 3 {
+		main, off := p.HeldItems()
+		if _, ok := main.Item().(item.Shield); ok {
+			p.SetHeldItems(p.damageItem(main, 1+int(math.Floor(dmg))), off)
+		} else {
+			p.SetHeldItems(main, p.damageItem(off, 1+int(math.Floor(dmg))))
+		}
+	}
+	s, ok := src.(entity.AttackDamageSource)
+	if !ok {
+		return
+	}
+	if h, ok := s.Attacker.(interface {
+		HeldItems() (mainHand, offHand item.Stack)
+	}); ok {
+		if held, _ := h.HeldItems(); isAxe(held) {
+			p.SetCooldown(item.Shield{}, item.ShieldDisableDuration)
+			p.updateState()
+		}
+	}
+	if l, ok := s.Attacker.(entity.Living); ok {
+		l.KnockBack(p.Position(), 0.5, 0.1)
+	}
+}
+
+// isAxe checks if the item stack passed holds an axe.
+func isAxe(s item.Stack) bool {
+	_, ok := s.Item().(item.Axe)
+	return ok
+}
+
 // FinalDamageFrom resolves the final damage received by the player if it is attacked by the source passed
 // with the damage passed. FinalDamageFrom takes into account things such as the armour worn and the
 // enchantments on the individual pieces.
@@ -884,6 +988,8 @@ func (p *Player) Kill(src world.DamageSource, msg string) {
 	})
 	p.StopSneaking()
 	p.StopSprinting()
+	p.Wake()
+	p.Dismount()
 
 	w, pos := p.World(), p.Position()
 	if !keepInv {
@@ -950,7 +1056,11 @@ func (p *Player) Respawn() {
 	p.Extinguish()
 	p.ResetFallDistance()
 
-	pos := w.PlayerSpawn(p.UUID()).Vec3Middle()
+	spawn := w.PlayerSpawn(p.UUID())
+	pos := spawn.Vec3Middle()
+	if b, ok := w.Block(spawn).(block.Bed); ok {
+		pos = bedSpawnPosition(w, spawn, b)
+	}
 
 	p.Handle(func(h Handler) *event.Context {
 		h.HandleRespawn(&pos, &w)
@@ -1023,6 +1133,8 @@ func (p *Player) StartSneaking() {
 	if !p.sneaking.CAS(false, true) {
 		return
 	}
+	// Sneaking makes the player leave the entity that it is riding.
+	p.Dismount()
 	if !p.Flying() {
 		p.StopSprinting()
 	}
@@ -1075,13 +1187,13 @@ func (p *Player) StopSwimming() {
 
 // StartGliding makes the player start gliding if it is not currently doing so.
 func (p *Player) StartGliding() {
-	if !p.gliding.CAS(false, true) {
-		return
-	}
 	chest := p.Armour().Chestplate()
 	if _, ok := chest.Item().(item.Elytra); !ok || chest.Durability() < 2 {
 		return
 	}
+	if !p.gliding.CAS(false, true) {
+		return
+	}
 	p.updateState()
 }
 
@@ -1138,6 +1250,29 @@ func (p *Player) DisableFlight() {
 	p.Session().SendAbilities()
 }
 
+// CombatViolation reports that the player attacked the target passed in a way that violates the rules of
+// combat. Handler.HandleCombatViolation is called with the violation level passed, and true is returned if the
+// attack should be ignored, or false if the event was cancelled and the attack should go through.
+func (p *Player) CombatViolation(target world.Entity, v combat.Violation, level float64) bool {
+	ctx := event.C()
+	return !p.Handle(func(h Handler) *event.Context {
+		h.HandleCombatViolation(ctx, target, v, level)
+		return ctx
+	})
+}
+
+// MovementViolation reports that the player moved to the position actual, while the server expected it to be
+// at the position expected based on its input. Handler.HandleMovementViolation is called, and true is returned
+// if the movement should be corrected, or false if the event was cancelled and the movement should be
+// accepted.
+func (p *Player) MovementViolation(expected, actual mgl64.Vec3) bool {
+	ctx := event.C()
+	return !p.Handle(func(h Handler) *event.Context {
+		h.HandleMovementViolation(ctx, expected, actual)
+		return ctx
+	})
+}
+
 // Jump makes the player jump if they are on ground. It exhausts the player by 0.05 food points, an additional 0.15
 // is exhausted if the player is sprint jumping.
 func (p *Player) Jump() {
@@ -1303,6 +1438,19 @@ func (p *Player) GameMode() world.GameMode {
 	return p.gameMode.Load()
 }
 
+// Permissions returns the permission.Set of the player, holding its operator status, groups and permission
+// nodes. Changes made to the Set are saved along with the rest of the player's data. If the operator status
+// of the player changes, the client is updated within a few seconds.
+func (p *Player) Permissions() *permission.Set {
+	return p.perms
+}
+
+// Extensions returns the Extensions of the player, holding arbitrary data of plugins. Values stored in the
+// Extensions are saved along with the rest of the player's data.
+func (p *Player) Extensions() *Extensions {
+	return p.ext
+}
+
 // HasCooldown returns true if the item passed has an active cooldown, meaning it currently cannot be used again. If the
 // world.Item passed is nil, HasCooldown always returns false.
 func (p *Player) HasCooldown(item world.Item) bool {
@@ -1362,7 +1510,7 @@ func (p *Player) UseItem() {
 		p.SetCooldown(it, cd.Cooldown())
 	}
 
-	if _, ok := it.(item.Releasable); ok {
+	if _, ok := it.(item.Releasable); ok && !charged(it) {
 		if !p.canRelease() {
 			return
 		}
@@ -1380,6 +1528,8 @@ func (p *Player) UseItem() {
 		// We only swing the player's arm if the item held actually does something. If it doesn't, there is no
 		// reason to swing the arm.
 		p.SwingArm()
+		// The item may have changed the items held while being used, so we get them again.
+		i, left = p.HeldItems()
 		p.SetHeldItems(p.subtractItem(p.damageItem(i, useCtx.Damage), useCtx.CountSub), left)
 		p.addNewItem(useCtx)
 	case item.Consumable:
@@ -1443,6 +1593,13 @@ func (p *Player) ReleaseItem() {
 	p.updateState()
 }
 
+// charged checks if the item passed is charged, such as a loaded crossbow. A charged item is used immediately
+// instead of being released.
+func charged(it world.Item) bool {
+	c, ok := it.(interface{ Charged() bool })
+	return ok && c.Charged()
+}
+
 // canRelease returns whether the player can release the item currently held in the main hand.
 func (p *Player) canRelease() bool {
 	held, _ := p.HeldItems()
@@ -1571,17 +1728,22 @@ func (p *Player) UseItemOnEntity(e world.Entity) bool {
 		return false
 	}
 	i, left := p.HeldItems()
-	usable, ok := i.Item().(item.UsableOnEntity)
-	if !ok {
-		return true
+	if usable, ok := i.Item().(item.UsableOnEntity); ok {
+		useCtx := p.useContext()
+		if usable.UseOnEntity(e, e.World(), p, useCtx) {
+			p.SwingArm()
+			p.SetHeldItems(p.subtractItem(p.damageItem(i, useCtx.Damage), useCtx.CountSub), left)
+			p.addNewItem(useCtx)
+			return true
+		}
 	}
-	useCtx := p.useContext()
-	if !usable.UseOnEntity(e, e.World(), p, useCtx) {
+	if in, ok := e.(interface{ Interact(user item.User) bool }); ok && in.Interact(p) {
 		return true
 	}
-	p.SwingArm()
-	p.SetHeldItems(p.subtractItem(p.damageItem(i, useCtx.Damage), useCtx.CountSub), left)
-	p.addNewItem(useCtx)
+	if r, ok := e.(entity.Rideable); ok && !p.Sneaking() {
+		// Entities that do nothing with the held item may be ridden instead.
+		p.Mount(r)
+	}
 	return true
 }
 
@@ -1613,6 +1775,13 @@ func (p *Player) AttackEntity(e world.Entity) bool {
 	i, _ := p.HeldItems()
 	living, ok := e.(entity.Living)
 	if !ok {
+		if h, ok := e.(interface {
+			Hurt(dmg float64, src world.DamageSource) (float64, bool)
+		}); ok {
+			// Entities that are not living, such as boats and minecarts, may still be damaged by attacks.
+			h.Hurt(i.AttackDamage(), entity.AttackDamageSource{Attacker: p})
+			return true
+		}
 		return false
 	}
 	if living.AttackImmune() {
@@ -1629,6 +1798,9 @@ func (p *Player) AttackEntity(e world.Entity) bool {
 	if s, ok := i.Enchantment(enchantment.Sharpness{}); ok {
 		dmg += (enchantment.Sharpness{}).Addend(s.Level())
 	}
+	if imp, ok := i.Enchantment(enchantment.Impaling{}); ok && (enchantment.Impaling{}).Affects(living) {
+		dmg += (enchantment.Impaling{}).Addend(imp.Level())
+	}
 	if critical {
 		dmg *= 1.5
 	}
@@ -2070,6 +2242,7 @@ func (p *Player) Teleport(pos mgl64.Vec3) {
 // teleport teleports the player to a target position in the world. It does not call the Handler of the
 // player.
 func (p *Player) teleport(pos mgl64.Vec3) {
+	p.Dismount()
 	for _, v := range p.viewers() {
 		v.ViewEntityTeleport(p, pos)
 	}
@@ -2385,6 +2558,24 @@ func (p *Player) OpenBlockContainer(pos cube.Pos) {
 	}
 }
 
+// OpenEntityContainer opens the container carried by the entity passed, such as the inventory of a chest
+// minecart. If the entity carries no container, OpenEntityContainer does nothing.
+// OpenEntityContainer will also do nothing if the player has no session connected to it.
+func (p *Player) OpenEntityContainer(e world.Entity) {
+	if p.Session() != session.Nop {
+		p.Session().OpenEntityContainer(e)
+	}
+}
+
+// OpenTrade opens the trading window of the trade.Merchant passed. The entity passed is shown in the trading
+// window. If nil, the player itself is shown instead.
+// OpenTrade does nothing if the player has no session connected to it.
+func (p *Player) OpenTrade(m trade.Merchant, e world.Entity) {
+	if p.Session() != session.Nop {
+		p.Session().OpenTrade(m, e)
+	}
+}
+
 // HideEntity hides a world.Entity from the Player so that it can under no circumstance see it. Hidden entities can be
 // made visible again through a call to ShowEntity.
 func (p *Player) HideEntity(e world.Entity) {
@@ -2445,6 +2636,9 @@ func (p *Player) Tick(w *world.World, current int64) {
 
 	p.checkBlockCollisions(p.vel.Load(), w)
 	p.onGround.Store(p.checkOnGround(w))
+	p.tickPortal(w)
+	p.tickSleep(w)
+	p.tickRiding(w)
 
 	p.effects.Tick(p)
 
@@ -2701,6 +2895,275 @@ func (p *Player) checkBlockCollisions(vel mgl64.Vec3, w *world.World) {
 	p.collidedVertically.Store(!mgl64.FloatEqual(deltaY, vel[1]))
 }
 
+// Mount makes the player start riding the entity passed, such as a boat or a minecart. If the player is already
+// riding another entity, it dismounts it first. False is returned if the player could not mount the entity, for
+// example because all of its seats are taken.
+func (p *Player) Mount(r entity.Rideable) bool {
+	if p.Dead() || r == world.Entity(p) {
+		return false
+	}
+	if current, ok := p.Riding(); ok {
+		if current == r {
+			return false
+		}
+		p.Dismount()
+	}
+	ctx := event.C()
+	if p.Handle(func(h Handler) *event.Context {
+		h.HandleMount(ctx, r)
+		return ctx
+	}) {
+		return false
+	}
+	if !r.AddRider(p) {
+		return false
+	}
+	p.Wake()
+	p.StopSprinting()
+	p.StopSneaking()
+	p.riding.Store(r)
+
+	riders := r.Riders()
+	for _, v := range p.viewers() {
+		v.ViewEntityAction(p, entity.MountAction{Vehicle: r, Driver: riders[0] == world.Entity(p)})
+	}
+	p.updateState()
+	return true
+}
+
+// Riding returns the entity that the player is currently riding. False is returned if the player is not riding
+// anything.
+func (p *Player) Riding() (entity.Rideable, bool) {
+	r := p.riding.Load()
+	return r, r != nil
+}
+
+// Dismount makes the player stop riding the entity it is currently riding. Nothing happens if the player is not
+// riding anything.
+func (p *Player) Dismount() {
+	r := p.riding.Swap(nil)
+	if r == nil {
+		return
+	}
+	r.RemoveRider(p)
+	for _, v := range p.viewers() {
+		v.ViewEntityAction(p, entity.DismountAction{Vehicle: r})
+	}
+	p.updateState()
+}
+
+// Drive steers the entity that the player is riding, provided the player is in the driver seat of an entity that
+// may be steered. forward and strafe are the movement input of the player in the range [-1, 1].
+func (p *Player) Drive(forward, strafe float64) {
+	r, ok := p.Riding()
+	if !ok {
+		return
+	}
+	if d, ok := r.(entity.Driveable); ok {
+		d.Drive(p, forward, strafe, p.Rotation().Yaw())
+	}
+}
+
+// tickRiding moves the player along with the entity it is riding. The player dismounts if the entity was removed
+// from the world that the player is in.
+func (p *Player) tickRiding(w *world.World) {
+	r, ok := p.Riding()
+	if !ok {
+		return
+	}
+	if r.World() != w {
+		p.Dismount()
+		return
+	}
+	p.pos.Store(r.Position())
+	p.vel.Store(mgl64.Vec3{})
+	p.ResetFallDistance()
+}
+
+// Sleep makes the player sleep in the bed at the position passed. Nothing happens if the player is already
+// sleeping or if there is no unoccupied bed at the position. The player is woken up when it is hurt, when the
+// bed is broken or when enough players in the world are sleeping to skip the night.
+func (p *Player) Sleep(pos cube.Pos) {
+	if _, ok := p.Sleeping(); ok {
+		return
+	}
+	w := p.World()
+	if b, ok := w.Block(pos).(block.Bed); !ok || b.Occupied {
+		return
+	}
+	ctx := event.C()
+	if p.Handle(func(h Handler) *event.Context {
+		h.HandleSleep(ctx, pos)
+		return ctx
+	}) {
+		return
+	}
+	setBedOccupied(w, pos, true)
+	p.sleepPos.Store(pos)
+	p.sleeping.Store(true)
+
+	p.StopSprinting()
+	p.StopSneaking()
+	p.teleport(pos.Vec3Middle().Add(mgl64.Vec3{0, 0.5625}))
+	p.updateState()
+}
+
+// Sleeping returns the position of the bed that the player is sleeping in. False is returned if the player is
+// not currently sleeping.
+func (p *Player) Sleeping() (cube.Pos, bool) {
+	if !p.sleeping.Load() {
+		return cube.Pos{}, false
+	}
+	return p.sleepPos.Load(), true
+}
+
+// Wake wakes the player up if it is sleeping, so that it leaves the bed it was sleeping in.
+func (p *Player) Wake() {
+	pos, ok := p.Sleeping()
+	if !ok {
+		return
+	}
+	ctx := event.C()
+	if p.Handle(func(h Handler) *event.Context {
+		h.HandleWake(ctx)
+		return ctx
+	}) {
+		// The client may already think it is no longer sleeping, so we resend the state to keep it in the bed.
+		p.updateState()
+		return
+	}
+	if !p.sleeping.CAS(true, false) {
+		return
+	}
+	setBedOccupied(p.World(), pos, false)
+	p.updateState()
+	for _, v := range p.viewers() {
+		v.ViewEntityAction(p, entity.WakeUpAction{})
+	}
+}
+
+// setBedOccupied changes the occupied state of both halves of the bed at the position passed, if there is a bed
+// at the position.
+func setBedOccupied(w *world.World, pos cube.Pos, occupied bool) {
+	b, ok := w.Block(pos).(block.Bed)
+	if !ok {
+		return
+	}
+	b.Occupied = occupied
+	w.SetBlock(pos, b, nil)
+
+	other := b.OtherHalf(pos)
+	if o, ok := w.Block(other).(block.Bed); ok {
+		o.Occupied = occupied
+		w.SetBlock(other, o, nil)
+	}
+}
+
+// tickSleep wakes the player up if the bed it is sleeping in no longer exists.
+func (p *Player) tickSleep(w *world.World) {
+	if pos, ok := p.Sleeping(); ok {
+		if _, ok := w.Block(pos).(block.Bed); !ok {
+			p.Wake()
+		}
+	}
+}
+
+// bedSpawnPosition returns the position next to the bed at the position passed at which a player respawns. If no
+// free space is found next to the bed, the position on top of the bed is returned.
+func bedSpawnPosition(w *world.World, pos cube.Pos, b block.Bed) mgl64.Vec3 {
+	for _, half := range []cube.Pos{pos, b.OtherHalf(pos)} {
+		for x := -1; x <= 1; x++ {
+			for z := -1; z <= 1; z++ {
+				for y := -1; y <= 1; y++ {
+					if c := half.Add(cube.Pos{x, y, z}); canSpawnAt(w, c) {
+						return c.Vec3Middle()
+					}
+				}
+			}
+		}
+	}
+	return pos.Vec3Middle().Add(mgl64.Vec3{0, 0.5625})
+}
+
+// canSpawnAt checks if a player can spawn at the position passed: The position and the block above it must be
+// passable, while the block below must have a solid top face.
+func canSpawnAt(w *world.World, pos cube.Pos) bool {
+	below := pos.Side(cube.FaceDown)
+	if !w.Block(below).Model().FaceSolid(below, cube.FaceUp, w) {
+		return false
+	}
+	for _, c := range []cube.Pos{pos, pos.Side(cube.FaceUp)} {
+		if _, ok := w.Block(c).Model().(model.Empty); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// EnterPortal is called when the player is inside a portal block at the position passed. If the player stays
+// inside a nether portal for 4 seconds, or enters an end portal or end gateway, it travels to the destination of
+// the portal. Players in creative or spectator mode travel through nether portals instantly.
+func (p *Player) EnterPortal(portal block.Portal, pos cube.Pos) {
+	p.portalMu.Lock()
+	defer p.portalMu.Unlock()
+	p.portal, p.portalPos = portal, pos
+}
+
+// portalCooldown is the amount of ticks that a player must spend outside a portal after travelling before it
+// can travel through a portal again.
+const portalCooldown = 10
+
+// tickPortal checks if the player has been inside a portal for long enough to travel through it. If so, the
+// player travels to the destination of the portal.
+func (p *Player) tickPortal(w *world.World) {
+	p.portalMu.Lock()
+	portal, pos := p.portal, p.portalPos
+	p.portal = nil
+	if portal == nil {
+		p.portalTicks = 0
+		if p.portalCooldown > 0 {
+			p.portalCooldown--
+		}
+		p.portalMu.Unlock()
+		return
+	}
+	if p.portalCooldown > 0 {
+		// The player has just travelled through a portal, or is still standing in the portal it arrived in. It
+		// must step out of the portal before it can travel again.
+		p.portalCooldown = portalCooldown
+		p.portalMu.Unlock()
+		return
+	}
+	delay := 1
+	if _, ok := portal.(block.NetherPortal); ok && p.GameMode().AllowsTakingDamage() {
+		delay = 80
+	}
+	if p.portalTicks++; p.portalTicks < delay {
+		p.portalMu.Unlock()
+		return
+	}
+	p.portalTicks, p.portalCooldown = 0, portalCooldown
+	p.portalMu.Unlock()
+
+	dest := portal.PortalDestination(pos, w)
+	ctx := event.C()
+	if p.Handle(func(h Handler) *event.Context {
+		h.HandlePortal(ctx, portal, pos, &dest)
+		return ctx
+	}) || dest == nil {
+		return
+	}
+	// The exit portal is looked up and created during the tick of the destination, so that entities arriving
+	// at the same time do not each create one.
+	dest.Exec(func() {
+		target := portal.PortalTarget(pos, w, dest, p)
+		if dest != w {
+			dest.AddEntity(p)
+		}
+		p.Teleport(target)
+	})
+}
+
 // checkEntityInsiders checks if the player is colliding with any EntityInsider blocks.
 func (p *Player) checkEntityInsiders(w *world.World, entityBBox cube.BBox) {
 	box := entityBBox.Grow(-0.0001)
@@ -3002,6 +3465,8 @@ func (p *Player) close(msg string) {
 		p.Respawn()
 	}
 
+	p.Wake()
+	p.Dismount()
 	p.Handle(func(h Handler) *event.Context {
 		h.HandleQuit()
 		return nil
@@ -3050,6 +3515,11 @@ func (p *Player) load(data Data) {
 	p.fireTicks.Store(data.FireTicks)
 	p.fallDistance.Store(data.FallDistance)
 
+	p.perms = permission.NewSet(false, data.Groups, data.Permissions)
+	if data.Extensions != nil {
+		p.ext = data.Extensions
+	}
+
 	p.loadInventory(data.Inventory)
 	for slot, stack := range data.EnderChestInventory {
 		_ = p.enderChest.SetItem(slot, stack)
@@ -3107,6 +3577,9 @@ func (p *Player) Data() Data {
 		FireTicks:           p.fireTicks.Load(),
 		FallDistance:        p.fallDistance.Load(),
 		World:               p.World(),
+		Groups:              p.Permissions().Groups(),
+		Permissions:         p.Permissions().Nodes(),
+		Extensions:          p.ext,
 	}
 }
 
@@ -0,0 +1,23 @@
+package playerdb
+
+import "github.com/stcraft/dragonfly/server/player"
+
+func extensionsToData(ext *player.Extensions) (map[string]jsonExtension, error) {
+	if ext == nil {
+		return nil, nil
+	}
+	raw, err := ext.Encode()
+	data := make(map[string]jsonExtension, len(raw))
+	for name, r := range raw {
+		data[name] = jsonExtension{Version: r.Version, Data: r.Data}
+	}
+	return data, err
+}
+
+func dataToExtensions(data map[string]jsonExtension) *player.Extensions {
+	raw := make(map[string]player.RawExtension, len(data))
+	for name, d := range data {
+		raw[name] = player.RawExtension{Version: d.Version, Data: d.Data}
+	}
+	return player.NewExtensions(raw)
+}
@@ -38,14 +38,20 @@ func (p *Provider) fromJson(d jsonData, lookupWorld func(world.Dimension) *world
 		Inventory:           dataToInv(d.Inventory),
 		EnderChestInventory: make([]item.Stack, 27),
 		World:               lookupWorld(dim),
+		Groups:              d.Groups,
+		Permissions:         d.Permissions,
+		Extensions:          dataToExtensions(d.Extensions),
 	}
 	decodeItems(d.EnderChestInventory, data.EnderChestInventory)
 	return data
 }
 
-func (p *Provider) toJson(d player.Data) jsonData {
+// toJson converts player.Data to jsonData. If any of the extensions of the player could not be encoded, an error
+// is returned along with jsonData holding all other data.
+func (p *Provider) toJson(d player.Data) (jsonData, error) {
 	dim, _ := world.DimensionID(d.World.Dimension())
 	mode, _ := world.GameModeID(d.GameMode)
+	ext, err := extensionsToData(d.Extensions)
 	return jsonData{
 		UUID:                d.UUID.String(),
 		Username:            d.Username,
@@ -71,7 +77,10 @@ func (p *Provider) toJson(d player.Data) jsonData {
 		Inventory:           invToData(d.Inventory),
 		EnderChestInventory: encodeItems(d.EnderChestInventory),
 		Dimension:           uint8(dim),
-	}
+		Groups:              d.Groups,
+		Permissions:         d.Permissions,
+		Extensions:          ext,
+	}, err
 }
 
 type jsonData struct {
@@ -94,6 +103,9 @@ type jsonData struct {
 	FireTicks                        int64
 	FallDistance                     float64
 	Dimension                        uint8
+	Groups                           []string
+	Permissions                      map[string]bool
+	Extensions                       map[string]jsonExtension
 }
 
 type jsonInventoryData struct {
@@ -117,3 +129,8 @@ type jsonEffect struct {
 	Duration time.Duration
 	Ambient  bool
 }
+
+type jsonExtension struct {
+	Version int
+	Data    []byte
+}
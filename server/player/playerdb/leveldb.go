@@ -31,13 +31,19 @@ func NewProvider(path string) (*Provider, error) {
 	return &Provider{db: db}, nil
 }
 
-// Save ...
+// Save saves the player.Data passed. If any of the player.Extensions of the player could not be encoded, the
+// rest of the data is still saved and an error is returned. The extensions that failed to encode keep the
+// value they were loaded with.
 func (p *Provider) Save(id uuid.UUID, d player.Data) error {
-	b, err := json.Marshal(p.toJson(d))
+	data, extErr := p.toJson(d)
+	b, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
-	return p.db.Put(id[:], b, nil)
+	if err := p.db.Put(id[:], b, nil); err != nil {
+		return err
+	}
+	return extErr
 }
 
 // Load ...
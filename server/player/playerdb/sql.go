@@ -0,0 +1,628 @@
+package playerdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stcraft/dragonfly/server/entity/effect"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/player"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// ErrConflict is returned by SQLProvider.Save if the data of a player was saved by another server since it was
+// last loaded, or if the lease of the SQLProvider on the data expired and was claimed by another server. The
+// data passed is not saved, so that a player moving between servers never has its items duplicated or
+// overwritten with stale data.
+var ErrConflict = errors.New("player data was modified by another server since it was loaded")
+
+const (
+	// leaseDuration is the duration after which the lease of an SQLProvider on the data of a player expires if it
+	// is not renewed. Leases of players online are renewed every leaseDuration/4, so that the data of players
+	// of a server that crashed can be loaded by other servers shortly after.
+	leaseDuration = time.Minute
+	// leaseTimeout is the maximum duration SQLProvider.Load waits for the lease of another server on the data of
+	// a player to be released, such as when the player is moved from one server to another.
+	leaseTimeout = time.Second * 3
+	// leaseRetryInterval is the interval at which SQLProvider.Load attempts to claim a lease held by another
+	// server.
+	leaseRetryInterval = time.Millisecond * 100
+)
+
+// Dialect holds the differences in SQL syntax between database systems that an SQLProvider needs to account for.
+type Dialect struct {
+	// Placeholder returns the placeholder for the argument at the (1-based) index passed in a query.
+	Placeholder func(n int) string
+	// BlobType is the column type used to store binary data.
+	BlobType string
+}
+
+var (
+	// SQLite is the Dialect of SQLite databases.
+	SQLite = Dialect{Placeholder: func(int) string { return "?" }, BlobType: "BLOB"}
+	// MySQL is the Dialect of MySQL and MariaDB databases.
+	MySQL = Dialect{Placeholder: func(int) string { return "?" }, BlobType: "LONGBLOB"}
+	// Postgres is the Dialect of PostgreSQL databases.
+	Postgres = Dialect{Placeholder: func(n int) string { return "$" + strconv.Itoa(n) }, BlobType: "BYTEA"}
+)
+
+// SQLProvider is a player data provider that stores data in an SQL database using database/sql. Unlike Provider,
+// the database may be shared by several servers, for example behind a proxy. The data of a player is stored in
+// normalised tables.
+//
+// Loading the data of a player claims a lease on it, which is released by SQLProvider.Release once the player
+// leaves and its data is saved. While another server holds the lease, Load waits for it to be released and
+// fails with player.ErrDataInUse if it is not released in time. As a safety net, saving is also guarded by
+// optimistic locking: Saving data of a player fails with ErrConflict if another server saved data of that
+// player after it was loaded by this SQLProvider.
+type SQLProvider struct {
+	db      *sql.DB
+	dialect Dialect
+	// owner is the ID stored in the leases claimed by the SQLProvider. It is unique for every SQLProvider.
+	owner string
+
+	mu sync.Mutex
+	// versions holds the version of the data of every player last loaded or saved by the SQLProvider.
+	versions map[uuid.UUID]int64
+	// leases holds the number of times the data of every player the SQLProvider holds a lease on was loaded
+	// and not yet released. The lease is only released once this number reaches zero, so that a player that
+	// logs in again before its previous session was closed keeps the lease.
+	leases map[uuid.UUID]int
+
+	closing chan struct{}
+	running sync.WaitGroup
+}
+
+// Containers of items stored in the player_items table.
+const (
+	containerInventory  = "inventory"
+	containerArmour     = "armour"
+	containerOffHand    = "offhand"
+	containerEnderChest = "ender_chest"
+)
+
+// NewSQLProvider creates a player data provider that saves and loads data using the sql.DB passed, which may use
+// any driver. The Dialect passed must match the database system of the driver. The tables used are created if
+// they do not yet exist. The SQLProvider takes ownership of the sql.DB and closes it when it is closed.
+func NewSQLProvider(db *sql.DB, dialect Dialect) (*SQLProvider, error) {
+	p := &SQLProvider{
+		db:       db,
+		dialect:  dialect,
+		owner:    uuid.NewString(),
+		versions: map[uuid.UUID]int64{},
+		leases:   map[uuid.UUID]int{},
+		closing:  make(chan struct{}),
+	}
+	for _, q := range []string{
+		`CREATE TABLE IF NOT EXISTS players (
+			uuid VARCHAR(36) PRIMARY KEY,
+			version BIGINT NOT NULL,
+			username VARCHAR(255) NOT NULL,
+			dimension INTEGER NOT NULL,
+			pos_x DOUBLE PRECISION NOT NULL, pos_y DOUBLE PRECISION NOT NULL, pos_z DOUBLE PRECISION NOT NULL,
+			vel_x DOUBLE PRECISION NOT NULL, vel_y DOUBLE PRECISION NOT NULL, vel_z DOUBLE PRECISION NOT NULL,
+			yaw DOUBLE PRECISION NOT NULL, pitch DOUBLE PRECISION NOT NULL,
+			health DOUBLE PRECISION NOT NULL, max_health DOUBLE PRECISION NOT NULL,
+			hunger INTEGER NOT NULL, food_tick INTEGER NOT NULL,
+			exhaustion DOUBLE PRECISION NOT NULL, saturation DOUBLE PRECISION NOT NULL,
+			absorption DOUBLE PRECISION NOT NULL,
+			enchantment_seed BIGINT NOT NULL,
+			experience INTEGER NOT NULL,
+			air_supply BIGINT NOT NULL, max_air_supply BIGINT NOT NULL,
+			game_mode INTEGER NOT NULL,
+			fire_ticks BIGINT NOT NULL,
+			fall_distance DOUBLE PRECISION NOT NULL,
+			main_hand_slot INTEGER NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS player_items (
+			uuid VARCHAR(36) NOT NULL,
+			container VARCHAR(16) NOT NULL,
+			slot INTEGER NOT NULL,
+			item ` + dialect.BlobType + ` NOT NULL,
+			PRIMARY KEY (uuid, container, slot)
+		)`,
+		`CREATE TABLE IF NOT EXISTS player_effects (
+			uuid VARCHAR(36) NOT NULL,
+			effect_id INTEGER NOT NULL,
+			level INTEGER NOT NULL,
+			duration BIGINT NOT NULL,
+			ambient BOOLEAN NOT NULL,
+			PRIMARY KEY (uuid, effect_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS player_groups (
+			uuid VARCHAR(36) NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			PRIMARY KEY (uuid, name)
+		)`,
+		`CREATE TABLE IF NOT EXISTS player_permissions (
+			uuid VARCHAR(36) NOT NULL,
+			node VARCHAR(255) NOT NULL,
+			granted BOOLEAN NOT NULL,
+			PRIMARY KEY (uuid, node)
+		)`,
+		`CREATE TABLE IF NOT EXISTS player_extensions (
+			uuid VARCHAR(36) NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			version INTEGER NOT NULL,
+			data ` + dialect.BlobType + ` NOT NULL,
+			PRIMARY KEY (uuid, name)
+		)`,
+		`CREATE TABLE IF NOT EXISTS player_leases (
+			uuid VARCHAR(36) PRIMARY KEY,
+			owner VARCHAR(36) NOT NULL,
+			expiry BIGINT NOT NULL
+		)`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			return nil, fmt.Errorf("create tables: %w", err)
+		}
+	}
+	p.running.Add(1)
+	go p.renewLeases()
+	return p, nil
+}
+
+// Save saves the player.Data passed and renews the lease of the SQLProvider on it. If the data of the player was
+// saved by another server since it was last loaded by the SQLProvider, or if another server claimed the lease on
+// it, the data is not saved and ErrConflict is returned. If any of the
+// player.Extensions of the player could not be encoded, the rest of the data is still saved and an error is
+// returned.
+func (p *SQLProvider) Save(id uuid.UUID, d player.Data) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var (
+		ext    map[string]player.RawExtension
+		extErr error
+	)
+	if d.Extensions != nil {
+		ext, extErr = d.Extensions.Encode()
+	}
+	version, known := p.versions[id]
+
+	tx, err := p.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("save player %v: %w", id, err)
+	}
+	defer tx.Rollback()
+
+	if err := p.renewLease(tx, id); err != nil {
+		return fmt.Errorf("save player %v: %w", id, err)
+	}
+	if err := p.savePlayer(tx, id, d, version, known); err != nil {
+		return fmt.Errorf("save player %v: %w", id, err)
+	}
+	if err := p.saveRelated(tx, id, d, ext); err != nil {
+		return fmt.Errorf("save player %v: %w", id, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("save player %v: %w", id, err)
+	}
+	p.versions[id] = version + 1
+	return extErr
+}
+
+// savePlayer saves the columns of the players table for the player.Data passed. The version passed is the
+// version of the data that was loaded, and known specifies if any data was loaded at all. ErrConflict is
+// returned if the version stored does not match.
+func (p *SQLProvider) savePlayer(tx *sql.Tx, id uuid.UUID, d player.Data, version int64, known bool) error {
+	dim, _ := world.DimensionID(d.World.Dimension())
+	mode, _ := world.GameModeID(d.GameMode)
+	values := []any{
+		d.Username, dim,
+		d.Position[0], d.Position[1], d.Position[2], d.Velocity[0], d.Velocity[1], d.Velocity[2],
+		d.Yaw, d.Pitch, d.Health, d.MaxHealth, d.Hunger, d.FoodTick,
+		d.ExhaustionLevel, d.SaturationLevel, d.AbsorptionLevel, d.EnchantmentSeed, d.Experience,
+		d.AirSupply, d.MaxAirSupply, mode, d.FireTicks, d.FallDistance, int(d.Inventory.MainHandSlot),
+	}
+	columns := []string{
+		"username", "dimension", "pos_x", "pos_y", "pos_z", "vel_x", "vel_y", "vel_z", "yaw", "pitch", "health",
+		"max_health", "hunger", "food_tick", "exhaustion", "saturation", "absorption", "enchantment_seed",
+		"experience", "air_supply", "max_air_supply", "game_mode", "fire_ticks", "fall_distance", "main_hand_slot",
+	}
+
+	if !known {
+		// No data was loaded for this player, so it should not yet have any data stored. If another server
+		// stored data in the meantime, inserting the row fails.
+		var exists int
+		switch err := p.queryRow(tx, "SELECT 1 FROM players WHERE uuid = ?", id.String()).Scan(&exists); {
+		case err == nil:
+			return ErrConflict
+		case !errors.Is(err, sql.ErrNoRows):
+			return err
+		}
+		columns = append(columns, "version", "uuid")
+		values = append(values, int64(1), id.String())
+		_, err := p.exec(tx, "INSERT INTO players ("+strings.Join(columns, ", ")+") VALUES (?"+strings.Repeat(", ?", len(columns)-1)+")", values...)
+		return err
+	}
+	values = append(values, version+1, id.String(), version)
+	res, err := p.exec(tx, "UPDATE players SET "+strings.Join(columns, " = ?, ")+" = ?, version = ? WHERE uuid = ? AND version = ?", values...)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrConflict
+	}
+	return nil
+}
+
+// saveRelated replaces the rows of the player passed in all tables other than the players table.
+func (p *SQLProvider) saveRelated(tx *sql.Tx, id uuid.UUID, d player.Data, ext map[string]player.RawExtension) error {
+	for _, table := range []string{"player_items", "player_effects", "player_groups", "player_permissions", "player_extensions"} {
+		if _, err := p.exec(tx, "DELETE FROM "+table+" WHERE uuid = ?", id.String()); err != nil {
+			return err
+		}
+	}
+
+	insertItems := func(container string, items []item.Stack) error {
+		for slot, it := range items {
+			if b := encodeItem(it); b != nil {
+				if _, err := p.exec(tx, "INSERT INTO player_items (uuid, container, slot, item) VALUES (?, ?, ?, ?)", id.String(), container, slot, b); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	inv := d.Inventory
+	if err := insertItems(containerInventory, inv.Items); err != nil {
+		return err
+	}
+	if err := insertItems(containerArmour, []item.Stack{inv.Helmet, inv.Chestplate, inv.Leggings, inv.Boots}); err != nil {
+		return err
+	}
+	if err := insertItems(containerOffHand, []item.Stack{inv.OffHand}); err != nil {
+		return err
+	}
+	if err := insertItems(containerEnderChest, d.EnderChestInventory); err != nil {
+		return err
+	}
+
+	for _, e := range d.Effects {
+		effectID, ok := effect.ID(e.Type())
+		if !ok {
+			continue
+		}
+		if _, err := p.exec(tx, "INSERT INTO player_effects (uuid, effect_id, level, duration, ambient) VALUES (?, ?, ?, ?, ?)", id.String(), effectID, e.Level(), int64(e.Duration()), e.Ambient()); err != nil {
+			return err
+		}
+	}
+	for _, g := range d.Groups {
+		if _, err := p.exec(tx, "INSERT INTO player_groups (uuid, name) VALUES (?, ?)", id.String(), g); err != nil {
+			return err
+		}
+	}
+	for node, granted := range d.Permissions {
+		if _, err := p.exec(tx, "INSERT INTO player_permissions (uuid, node, granted) VALUES (?, ?, ?)", id.String(), node, granted); err != nil {
+			return err
+		}
+	}
+	for name, r := range ext {
+		if _, err := p.exec(tx, "INSERT INTO player_extensions (uuid, name, version, data) VALUES (?, ?, ?, ?)", id.String(), name, r.Version, r.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load claims a lease on the data of the player with the UUID passed and loads it. If another server holds the
+// lease, Load waits for it to be released and returns an error wrapping player.ErrDataInUse if this does not
+// happen in time. If no data is stored for the player, the lease is still claimed and an error wrapping
+// sql.ErrNoRows is returned. Every successful claim must be followed by a call to Release.
+func (p *SQLProvider) Load(id uuid.UUID, world func(world.Dimension) *world.World) (player.Data, error) {
+	deadline := time.Now().Add(leaseTimeout)
+	for {
+		d, err := p.tryLoad(id, world)
+		if errors.Is(err, player.ErrDataInUse) && time.Now().Before(deadline) {
+			time.Sleep(leaseRetryInterval)
+			continue
+		}
+		if err != nil {
+			return player.Data{}, fmt.Errorf("load player %v: %w", id, err)
+		}
+		return d, nil
+	}
+}
+
+// tryLoad attempts to claim the lease on the data of the player with the UUID passed and load it once.
+func (p *SQLProvider) tryLoad(id uuid.UUID, world func(world.Dimension) *world.World) (player.Data, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	tx, err := p.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return player.Data{}, err
+	}
+	defer tx.Rollback()
+
+	if err := p.claimLease(tx, id); err != nil {
+		return player.Data{}, err
+	}
+	d, version, err := p.load(tx, id, world)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return player.Data{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return player.Data{}, err
+	}
+	p.leases[id]++
+	if err != nil {
+		// The player has no data stored yet. The lease is held regardless, so that no other server creates
+		// data for it in the meantime.
+		delete(p.versions, id)
+		return player.Data{}, err
+	}
+	p.versions[id] = version
+	return d, nil
+}
+
+// Release releases the lease of the SQLProvider on the data of the player with the UUID passed, so that other
+// servers may load it. Release should be called once the data of the player was saved when it left the server.
+func (p *SQLProvider) Release(id uuid.UUID) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.leases[id]--; p.leases[id] > 0 {
+		return nil
+	}
+	delete(p.leases, id)
+	delete(p.versions, id)
+	if _, err := p.db.Exec(p.rebind("DELETE FROM player_leases WHERE uuid = ? AND owner = ?"), id.String(), p.owner); err != nil {
+		return fmt.Errorf("release player %v: %w", id, err)
+	}
+	return nil
+}
+
+// claimLease claims the lease on the data of the player with the UUID passed. player.ErrDataInUse is returned
+// if another server holds a lease on it that has not yet expired.
+func (p *SQLProvider) claimLease(tx *sql.Tx, id uuid.UUID) error {
+	var (
+		owner  string
+		expiry int64
+		now    = time.Now()
+	)
+	switch err := p.queryRow(tx, "SELECT owner, expiry FROM player_leases WHERE uuid = ?", id.String()).Scan(&owner, &expiry); {
+	case errors.Is(err, sql.ErrNoRows):
+		if _, err := p.exec(tx, "INSERT INTO player_leases (uuid, owner, expiry) VALUES (?, ?, ?)", id.String(), p.owner, now.Add(leaseDuration).UnixMilli()); err != nil {
+			// Another server inserted a lease for the player after it was queried.
+			return fmt.Errorf("%w: %v", player.ErrDataInUse, err)
+		}
+		return nil
+	case err != nil:
+		return err
+	case owner != p.owner && expiry > now.UnixMilli():
+		return player.ErrDataInUse
+	}
+	res, err := p.exec(tx, "UPDATE player_leases SET owner = ?, expiry = ? WHERE uuid = ? AND owner = ? AND expiry = ?", p.owner, now.Add(leaseDuration).UnixMilli(), id.String(), owner, expiry)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return player.ErrDataInUse
+	}
+	return nil
+}
+
+// renewLease renews the lease of the SQLProvider on the data of the player with the UUID passed. ErrConflict is
+// returned if the SQLProvider no longer holds the lease.
+func (p *SQLProvider) renewLease(tx *sql.Tx, id uuid.UUID) error {
+	res, err := p.exec(tx, "UPDATE player_leases SET expiry = ? WHERE uuid = ? AND owner = ?", time.Now().Add(leaseDuration).UnixMilli(), id.String(), p.owner)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrConflict
+	}
+	return nil
+}
+
+// renewLeases renews the leases held by the SQLProvider every leaseDuration/4 until it is closed, so that they do
+// not expire while the players are online.
+func (p *SQLProvider) renewLeases() {
+	defer p.running.Done()
+	t := time.NewTicker(leaseDuration / 4)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			p.mu.Lock()
+			for id := range p.leases {
+				// A lease that could not be renewed was claimed by another server after it expired. Saving the
+				// data of the player will fail with ErrConflict, so there is nothing else to do here.
+				_, _ = p.db.Exec(p.rebind("UPDATE player_leases SET expiry = ? WHERE uuid = ? AND owner = ?"), time.Now().Add(leaseDuration).UnixMilli(), id.String(), p.owner)
+			}
+			p.mu.Unlock()
+		case <-p.closing:
+			return
+		}
+	}
+}
+
+// load loads the data of the player with the UUID passed and returns it with its version.
+func (p *SQLProvider) load(tx *sql.Tx, id uuid.UUID, lookupWorld func(world.Dimension) *world.World) (player.Data, int64, error) {
+	var (
+		d                  = player.Data{UUID: id, EnderChestInventory: make([]item.Stack, 27)}
+		version            int64
+		dim, mode, mainHnd int
+	)
+	d.Inventory.Items = make([]item.Stack, 36)
+	err := p.queryRow(tx, `SELECT version, username, dimension, pos_x, pos_y, pos_z, vel_x, vel_y, vel_z, yaw, pitch,
+		health, max_health, hunger, food_tick, exhaustion, saturation, absorption, enchantment_seed, experience,
+		air_supply, max_air_supply, game_mode, fire_ticks, fall_distance, main_hand_slot
+		FROM players WHERE uuid = ?`, id.String()).Scan(
+		&version, &d.Username, &dim,
+		&d.Position[0], &d.Position[1], &d.Position[2], &d.Velocity[0], &d.Velocity[1], &d.Velocity[2],
+		&d.Yaw, &d.Pitch, &d.Health, &d.MaxHealth, &d.Hunger, &d.FoodTick,
+		&d.ExhaustionLevel, &d.SaturationLevel, &d.AbsorptionLevel, &d.EnchantmentSeed, &d.Experience,
+		&d.AirSupply, &d.MaxAirSupply, &mode, &d.FireTicks, &d.FallDistance, &mainHnd,
+	)
+	if err != nil {
+		return d, 0, err
+	}
+	dimension, _ := world.DimensionByID(dim)
+	d.World = lookupWorld(dimension)
+	d.GameMode, _ = world.GameModeByID(mode)
+	d.Inventory.MainHandSlot = uint32(mainHnd)
+
+	err = p.query(tx, "SELECT container, slot, item FROM player_items WHERE uuid = ?", []any{id.String()}, func(rows *sql.Rows) error {
+		var (
+			container string
+			slot      int
+			b         []byte
+		)
+		if err := rows.Scan(&container, &slot, &b); err != nil {
+			return err
+		}
+		it := decodeItem(b)
+		switch container {
+		case containerInventory:
+			if slot < len(d.Inventory.Items) {
+				d.Inventory.Items[slot] = it
+			}
+		case containerEnderChest:
+			if slot < len(d.EnderChestInventory) {
+				d.EnderChestInventory[slot] = it
+			}
+		case containerOffHand:
+			d.Inventory.OffHand = it
+		case containerArmour:
+			for i, s := range []*item.Stack{&d.Inventory.Helmet, &d.Inventory.Chestplate, &d.Inventory.Leggings, &d.Inventory.Boots} {
+				if i == slot {
+					*s = it
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return d, 0, err
+	}
+
+	var effects []jsonEffect
+	err = p.query(tx, "SELECT effect_id, level, duration, ambient FROM player_effects WHERE uuid = ?", []any{id.String()}, func(rows *sql.Rows) error {
+		var e jsonEffect
+		var duration int64
+		if err := rows.Scan(&e.ID, &e.Level, &duration, &e.Ambient); err != nil {
+			return err
+		}
+		e.Duration = time.Duration(duration)
+		effects = append(effects, e)
+		return nil
+	})
+	if err != nil {
+		return d, 0, err
+	}
+	d.Effects = dataToEffects(effects)
+
+	err = p.query(tx, "SELECT name FROM player_groups WHERE uuid = ?", []any{id.String()}, func(rows *sql.Rows) error {
+		var g string
+		err := rows.Scan(&g)
+		d.Groups = append(d.Groups, g)
+		return err
+	})
+	if err != nil {
+		return d, 0, err
+	}
+
+	d.Permissions = map[string]bool{}
+	err = p.query(tx, "SELECT node, granted FROM player_permissions WHERE uuid = ?", []any{id.String()}, func(rows *sql.Rows) error {
+		var (
+			node    string
+			granted bool
+		)
+		err := rows.Scan(&node, &granted)
+		d.Permissions[node] = granted
+		return err
+	})
+	if err != nil {
+		return d, 0, err
+	}
+
+	ext := map[string]player.RawExtension{}
+	err = p.query(tx, "SELECT name, version, data FROM player_extensions WHERE uuid = ?", []any{id.String()}, func(rows *sql.Rows) error {
+		var (
+			name string
+			r    player.RawExtension
+		)
+		err := rows.Scan(&name, &r.Version, &r.Data)
+		ext[name] = r
+		return err
+	})
+	if err != nil {
+		return d, 0, err
+	}
+	d.Extensions = player.NewExtensions(ext)
+	return d, version, nil
+}
+
+// Close releases all leases still held by the SQLProvider and closes its sql.DB.
+func (p *SQLProvider) Close() error {
+	close(p.closing)
+	p.running.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id := range p.leases {
+		_, _ = p.db.Exec(p.rebind("DELETE FROM player_leases WHERE uuid = ? AND owner = ?"), id.String(), p.owner)
+	}
+	clear(p.leases)
+	return p.db.Close()
+}
+
+// exec executes a query with the arguments passed in the transaction passed. Placeholders in the query must be
+// written as '?' and are rewritten to those of the Dialect of the SQLProvider.
+func (p *SQLProvider) exec(tx *sql.Tx, q string, args ...any) (sql.Result, error) {
+	return tx.Exec(p.rebind(q), args...)
+}
+
+// queryRow queries a single row in the transaction passed. Placeholders are rewritten as in exec.
+func (p *SQLProvider) queryRow(tx *sql.Tx, q string, args ...any) *sql.Row {
+	return tx.QueryRow(p.rebind(q), args...)
+}
+
+// query queries rows in the transaction passed and calls f for every row. Placeholders are rewritten as in
+// exec.
+func (p *SQLProvider) query(tx *sql.Tx, q string, args []any, f func(rows *sql.Rows) error) error {
+	rows, err := tx.Query(p.rebind(q), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := f(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// rebind replaces all '?' placeholders in the query passed with those of the Dialect of the SQLProvider.
+func (p *SQLProvider) rebind(q string) string {
+	var (
+		b strings.Builder
+		n int
+	)
+	for _, r := range q {
+		if r == '?' {
+			n++
+			b.WriteString(p.dialect.Placeholder(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,158 @@
+package playerdb_test
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/google/uuid"
+	"github.com/stcraft/dragonfly/server/entity/effect"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/player"
+	"github.com/stcraft/dragonfly/server/player/playerdb"
+	"github.com/stcraft/dragonfly/server/player/playerdb/sqlite"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// openSQLite opens an SQLProvider on the SQLite database file at the path passed and closes it once the test
+// finishes.
+func openSQLite(t *testing.T, path string) *playerdb.SQLProvider {
+	t.Helper()
+	p, err := sqlite.Open(path)
+	if err != nil {
+		t.Fatalf("open %v: %v", path, err)
+	}
+	t.Cleanup(func() { _ = p.Close() })
+	return p
+}
+
+// testData returns player.Data with the world and health passed and a few items and effects.
+func testData(id uuid.UUID, w *world.World, health float64) player.Data {
+	d := player.Data{
+		UUID:                id,
+		Username:            "Steve",
+		Position:            mgl64.Vec3{1.5, 64, -3.5},
+		Yaw:                 90,
+		Health:              health,
+		MaxHealth:           20,
+		Hunger:              17,
+		GameMode:            world.GameModeSurvival,
+		Experience:          42,
+		World:               w,
+		EnderChestInventory: make([]item.Stack, 27),
+		Effects:             []effect.Effect{effect.New(effect.Speed{}, 2, time.Minute)},
+		Groups:              []string{"builder"},
+		Permissions:         map[string]bool{"dragonfly.command.give": true},
+	}
+	d.Inventory.Items = make([]item.Stack, 36)
+	d.Inventory.Items[3] = item.NewStack(item.Apple{}, 5)
+	d.Inventory.Helmet = item.NewStack(item.Helmet{Tier: item.ArmourTierIron{}}, 1)
+	d.Inventory.MainHandSlot = 3
+	d.EnderChestInventory[26] = item.NewStack(item.Diamond{}, 64)
+	return d
+}
+
+func TestSQLProviderRoundTrip(t *testing.T) {
+	w := world.Config{}.New()
+	defer w.Close()
+	worlds := func(world.Dimension) *world.World { return w }
+
+	id := uuid.New()
+	p := openSQLite(t, filepath.Join(t.TempDir(), "players.db"))
+	if _, err := p.Load(id, worlds); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("load of new player: got %v, want %v", err, sql.ErrNoRows)
+	}
+	want := testData(id, w, 13)
+	if err := p.Save(id, want); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := p.Release(id); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+
+	got, err := p.Load(id, worlds)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	defer p.Release(id)
+	if got.Username != want.Username || got.Position != want.Position || got.Yaw != want.Yaw || got.Health != want.Health ||
+		got.Hunger != want.Hunger || got.GameMode != want.GameMode || got.Experience != want.Experience || got.World != w {
+		t.Errorf("player: got %+v, want %+v", got, want)
+	}
+	if got.Inventory.MainHandSlot != 3 || !got.Inventory.Items[3].Equal(want.Inventory.Items[3]) || got.Inventory.Items[3].Count() != 5 {
+		t.Errorf("inventory slot 3: got %v, want %v", got.Inventory.Items[3], want.Inventory.Items[3])
+	}
+	if !got.Inventory.Helmet.Equal(want.Inventory.Helmet) {
+		t.Errorf("helmet: got %v, want %v", got.Inventory.Helmet, want.Inventory.Helmet)
+	}
+	if s := got.EnderChestInventory[26]; !s.Equal(want.EnderChestInventory[26]) || s.Count() != 64 {
+		t.Errorf("ender chest slot 26: got %v, want %v", s, want.EnderChestInventory[26])
+	}
+	if len(got.Effects) != 1 || got.Effects[0].Type() != (effect.Speed{}) || got.Effects[0].Level() != 2 || got.Effects[0].Duration() != time.Minute {
+		t.Errorf("effects: got %v, want %v", got.Effects, want.Effects)
+	}
+	if len(got.Groups) != 1 || got.Groups[0] != "builder" || !got.Permissions["dragonfly.command.give"] {
+		t.Errorf("groups and permissions: got %v %v, want %v %v", got.Groups, got.Permissions, want.Groups, want.Permissions)
+	}
+}
+
+func TestSQLProviderLease(t *testing.T) {
+	w := world.Config{}.New()
+	defer w.Close()
+	worlds := func(world.Dimension) *world.World { return w }
+
+	// a and b act as two servers sharing the same database.
+	path := filepath.Join(t.TempDir(), "players.db")
+	a, b := openSQLite(t, path), openSQLite(t, path)
+	id := uuid.New()
+
+	if _, err := a.Load(id, worlds); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("load by a: got %v, want %v", err, sql.ErrNoRows)
+	}
+	if err := a.Save(id, testData(id, w, 20)); err != nil {
+		t.Fatalf("save by a: %v", err)
+	}
+	if _, err := b.Load(id, worlds); !errors.Is(err, player.ErrDataInUse) {
+		t.Fatalf("load by b while a holds the lease: got %v, want %v", err, player.ErrDataInUse)
+	}
+	if err := b.Save(id, testData(id, w, 1)); !errors.Is(err, playerdb.ErrConflict) {
+		t.Fatalf("save by b without the lease: got %v, want %v", err, playerdb.ErrConflict)
+	}
+
+	// The player moves from a to b: b starts loading before a saved the final data of the player and released
+	// the lease, and must wait for a to do so.
+	loaded := make(chan player.Data)
+	go func() {
+		d, err := b.Load(id, worlds)
+		if err != nil {
+			t.Errorf("load by b after a released the lease: %v", err)
+		}
+		loaded <- d
+	}()
+	time.Sleep(time.Millisecond * 300)
+	if err := a.Save(id, testData(id, w, 7)); err != nil {
+		t.Fatalf("save by a: %v", err)
+	}
+	if err := a.Release(id); err != nil {
+		t.Fatalf("release by a: %v", err)
+	}
+	if d := <-loaded; d.Health != 7 {
+		t.Fatalf("health loaded by b: got %v, want 7", d.Health)
+	}
+
+	if _, err := a.Load(id, worlds); !errors.Is(err, player.ErrDataInUse) {
+		t.Fatalf("load by a while b holds the lease: got %v, want %v", err, player.ErrDataInUse)
+	}
+	if err := a.Save(id, testData(id, w, 20)); !errors.Is(err, playerdb.ErrConflict) {
+		t.Fatalf("save by a after b claimed the lease: got %v, want %v", err, playerdb.ErrConflict)
+	}
+	if err := b.Save(id, testData(id, w, 3)); err != nil {
+		t.Fatalf("save by b: %v", err)
+	}
+	if err := b.Release(id); err != nil {
+		t.Fatalf("release by b: %v", err)
+	}
+}
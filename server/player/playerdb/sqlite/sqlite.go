@@ -0,0 +1,30 @@
+// Package sqlite provides a playerdb.SQLProvider that stores player data in a local SQLite database file. It
+// uses the cgo based github.com/mattn/go-sqlite3 driver and is therefore kept separate from the playerdb
+// package.
+package sqlite
+
+import (
+	"database/sql"
+	"net/url"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stcraft/dragonfly/server/player/playerdb"
+)
+
+// Open opens the SQLite database file at the path passed, creating it if it does not yet exist, and returns a
+// playerdb.SQLProvider that stores player data in it.
+func Open(path string) (*playerdb.SQLProvider, error) {
+	db, err := sql.Open("sqlite3", "file:"+url.PathEscape(path)+"?_busy_timeout=5000&_journal_mode=WAL&_txlock=immediate")
+	if err != nil {
+		return nil, err
+	}
+	// SQLite only supports a single writer at a time, so using a single connection prevents transactions from
+	// failing because the database is locked.
+	db.SetMaxOpenConns(1)
+	p, err := playerdb.NewSQLProvider(db, playerdb.SQLite)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return p, nil
+}
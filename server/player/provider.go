@@ -21,6 +21,18 @@ type Provider interface {
 	io.Closer
 }
 
+// ErrDataInUse may be returned by Provider.Load if the data of a player is in use by another server sharing the
+// storage of the Provider, for example because the player is still connected to it. Unlike other errors returned
+// by Load, the player is disconnected instead of joining with default values.
+var ErrDataInUse = errors.New("player data is in use by another server")
+
+// Releaser may be implemented by a Provider that claims the data of a player when it is loaded, so that other
+// servers sharing its storage cannot load it at the same time. Release is called after the data of a player
+// was saved when it left the server, after which the claim is given up.
+type Releaser interface {
+	Release(uuid uuid.UUID) error
+}
+
 // Compile time check to make sure NopProvider implements Provider.
 var _ Provider = (*NopProvider)(nil)
 
@@ -5,6 +5,7 @@ import (
 	"context"
 	_ "embed"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,6 +14,7 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/df-mc/atomic"
 	"github.com/go-gl/mathgl/mgl32"
@@ -31,12 +33,12 @@ import (
 	"github.com/stcraft/dragonfly/server/internal/iteminternal"
 	"github.com/stcraft/dragonfly/server/internal/sliceutil"
 	_ "github.com/stcraft/dragonfly/server/item" // Imported for maintaining correct initialisation order.
+	"github.com/stcraft/dragonfly/server/permission"
 	"github.com/stcraft/dragonfly/server/player"
 	"github.com/stcraft/dragonfly/server/player/chat"
 	"github.com/stcraft/dragonfly/server/player/skin"
 	"github.com/stcraft/dragonfly/server/session"
 	"github.com/stcraft/dragonfly/server/world"
-	"github.com/stcraft/dragonfly/server/world/mcdb"
 	"golang.org/x/exp/maps"
 )
 
@@ -47,9 +49,21 @@ type Server struct {
 
 	once    sync.Once
 	started atomic.Bool
+	// closing is closed when the server starts closing, stopping the
+	// automatic saving of the server.
+	closing chan struct{}
+
+	// smu is held while the data of a player is saved, so that data saved
+	// automatically never overwrites data saved when the player quit.
+	smu sync.Mutex
+	// savingDisabled specifies if saving was disabled using SetSaving.
+	savingDisabled atomic.Bool
 
 	wmu    sync.Mutex
 	worlds map[string]*world.World
+	// dirs holds the directories of all loaded worlds that are stored in a
+	// directory, indexed by the name of the world.
+	dirs map[string]string
 
 	customBlocks []protocol.BlockEntry
 	customItems  []protocol.ItemComponentEntry
@@ -111,6 +125,7 @@ func (srv *Server) Start() {
 	srv.conf.Log.Infof("Starting Dragonfly for Minecraft v%v...", protocol.CurrentVersion)
 	srv.startListening()
 	srv.closeOnProgramEnd()
+	go srv.autoSave()
 
 	srv.wait()
 }
@@ -199,6 +214,13 @@ func (srv *Server) MaxPlayerCount() int {
 	return srv.conf.MaxPlayers
 }
 
+// Permissions returns the permission.Manager of the server, holding the
+// permission groups that players may be part of. The Manager may be used to
+// check permissions of command sources, or to obtain a permission.Allower.
+func (srv *Server) Permissions() *permission.Manager {
+	return srv.conf.Permissions
+}
+
 // Players returns a list of all players currently connected to the server.
 // Note that the slice returned is not updated when new players join or leave,
 // so it is only valid for as long as no new players join or players leave.
@@ -248,6 +270,98 @@ func (srv *Server) Broadcast(message string, args ...any) {
 	Console.SendMessage(msg)
 }
 
+// Save saves the data of all players online and all worlds loaded to their
+// providers, and the permission groups and operators to
+// Config.PermissionsFile if set. Players keep playing and worlds keep ticking
+// while the server is saved. Save also saves the server if saving was disabled
+// using SetSaving, so that a consistent backup of the files of the worlds may
+// be made by disabling saving, calling Save and copying the files once it
+// returns. An error is returned if any of the data could not be saved.
+func (srv *Server) Save() error {
+	var errs []error
+	for _, p := range srv.Players() {
+		if err := srv.savePlayer(p); err != nil {
+			errs = append(errs, fmt.Errorf("save player %v: %w", p.Name(), err))
+		}
+	}
+
+	srv.wmu.Lock()
+	worlds := maps.Clone(srv.worlds)
+	srv.wmu.Unlock()
+
+	for name, w := range worlds {
+		if err := w.Save(); err != nil {
+			errs = append(errs, fmt.Errorf("save world %v: %w", name, err))
+		}
+	}
+	if srv.conf.PermissionsFile != "" {
+		if err := srv.conf.Permissions.SaveFile(srv.conf.PermissionsFile); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SetSaving enables or disables saving of the server. While saving is
+// disabled, the server is not saved automatically and its worlds no longer
+// write chunks to their providers, so that the files of the worlds may be
+// copied to make a backup. This is equivalent to the save-off and save-on
+// commands of vanilla. The data of players is still saved when they quit.
+func (srv *Server) SetSaving(enabled bool) {
+	srv.savingDisabled.Store(!enabled)
+
+	srv.wmu.Lock()
+	defer srv.wmu.Unlock()
+	for _, w := range srv.worlds {
+		w.SetSaving(enabled)
+	}
+}
+
+// Saving returns whether saving of the server is enabled. Saving is enabled
+// unless disabled using SetSaving.
+func (srv *Server) Saving() bool {
+	return !srv.savingDisabled.Load()
+}
+
+// savePlayer saves the data of a player to the player.Provider of the server
+// if the player is still online.
+func (srv *Server) savePlayer(p *player.Player) error {
+	srv.smu.Lock()
+	defer srv.smu.Unlock()
+
+	if online, ok := srv.Player(p.UUID()); !ok || online != p {
+		// The player quit while the server was being saved, in which case its
+		// data was already saved when it quit.
+		return nil
+	}
+	return srv.conf.PlayerProvider.Save(p.UUID(), p.Data())
+}
+
+// autoSave saves the server every Config.AutoSaveInterval until the server is
+// closed. The server is not saved while saving is disabled.
+func (srv *Server) autoSave() {
+	if srv.conf.AutoSaveInterval < 0 {
+		return
+	}
+	t := time.NewTicker(srv.conf.AutoSaveInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			if !srv.Saving() {
+				continue
+			}
+			srv.conf.Log.Debugf("Saving server...")
+			if err := srv.Save(); err != nil {
+				srv.conf.Log.Errorf("Error while saving server: %v", err)
+			}
+		case <-srv.closing:
+			return
+		}
+	}
+}
+
 // closeOnProgramEnd closes the server right before the program ends, so that
 // all data of the server are saved properly.
 func (srv *Server) closeOnProgramEnd() {
@@ -275,6 +389,7 @@ func (srv *Server) Close() error {
 func (srv *Server) close() {
 	srv.conf.Log.Infof("Server shutting down...")
 	defer srv.conf.Log.Infof("Server stopped.")
+	close(srv.closing)
 
 	srv.conf.Log.Debugf("Disconnecting players...")
 	for _, p := range srv.Players() {
@@ -286,6 +401,12 @@ func (srv *Server) close() {
 	if err := srv.conf.PlayerProvider.Close(); err != nil {
 		srv.conf.Log.Errorf("Error while closing player provider: %v", err)
 	}
+	if srv.conf.PermissionsFile != "" {
+		srv.conf.Log.Debugf("Saving permissions...")
+		if err := srv.conf.Permissions.SaveFile(srv.conf.PermissionsFile); err != nil {
+			srv.conf.Log.Errorf("Error while saving permissions: %v", err)
+		}
+	}
 
 	srv.conf.Log.Debugf("Closing worlds...")
 
@@ -401,7 +522,16 @@ func (srv *Server) finaliseConn(ctx context.Context, conn session.Conn, l Listen
 	data := srv.defaultGameData()
 
 	var playerData *player.Data
-	if d, err := srv.conf.PlayerProvider.Load(id, srv.dimension); err == nil {
+	d, err := srv.conf.PlayerProvider.Load(id, srv.dimension)
+	if errors.Is(err, player.ErrDataInUse) {
+		// Joining with default values would overwrite the data stored once
+		// the player leaves again, so the player is disconnected instead.
+		_ = l.Disconnect(conn, "Your data is still in use by another server, please try again.")
+
+		srv.conf.Log.Debugf("connection %v failed loading player data: %v\n", conn.RemoteAddr(), err)
+		return
+	}
+	if err == nil {
 		if d.World == nil {
 			d.World = srv.Overworld()
 		}
@@ -409,12 +539,16 @@ func (srv *Server) finaliseConn(ctx context.Context, conn session.Conn, l Listen
 		dim, _ := world.DimensionID(d.World.Dimension())
 		data.Dimension = int32(dim)
 		data.Yaw, data.Pitch = float32(d.Yaw), float32(d.Pitch)
-
 		playerData = &d
 	}
+	operator := srv.conf.Permissions.IsOperator(id, conn.IdentityData().DisplayName)
+	if operator {
+		data.PlayerPermissions = packet.PermissionLevelOperator
+	}
 
 	if err := conn.StartGameContext(ctx, data); err != nil {
 		_ = l.Disconnect(conn, "Connection timeout.")
+		srv.releasePlayer(id)
 
 		srv.conf.Log.Debugf("connection %v failed spawning: %v\n", conn.RemoteAddr(), err)
 		return
@@ -426,6 +560,7 @@ func (srv *Server) finaliseConn(ctx context.Context, conn session.Conn, l Listen
 
 	s := srv.createPlayer(id, conn, playerData)
 	p := s.Controllable().(*player.Player)
+	p.Permissions().SetOperator(operator)
 
 	for key, handler := range srv.handlers {
 		h := handler.New(p)
@@ -521,12 +656,25 @@ func (srv *Server) handleSessionClose(c session.Controllable) {
 		return
 	}
 
+	srv.smu.Lock()
 	if err := srv.conf.PlayerProvider.Save(p.UUID(), p.Data()); err != nil {
 		srv.conf.Log.Errorf("Error while saving data: %v", err)
 	}
+	srv.releasePlayer(p.UUID())
+	srv.smu.Unlock()
 	srv.pwg.Done()
 }
 
+// releasePlayer releases the data of the player with the UUID passed if the
+// player.Provider of the server claims the data of players when loaded.
+func (srv *Server) releasePlayer(id uuid.UUID) {
+	if r, ok := srv.conf.PlayerProvider.(player.Releaser); ok {
+		if err := r.Release(id); err != nil {
+			srv.conf.Log.Errorf("Error while releasing data: %v", err)
+		}
+	}
+}
+
 // createPlayer creates a new player instance using the UUID and connection
 // passed.
 func (srv *Server) createPlayer(id uuid.UUID, conn session.Conn, data *player.Data) *session.Session {
@@ -534,7 +682,19 @@ func (srv *Server) createPlayer(id uuid.UUID, conn session.Conn, data *player.Da
 	if data != nil {
 		w, gm, pos = data.World, data.GameMode, data.Position
 	}
-	s := session.New(conn, srv.conf.MaxChunkRadius, srv.conf.Log, srv.conf.JoinMessage, srv.conf.QuitMessage)
+	s := session.Config{
+		Log:                srv.conf.Log,
+		MaxChunkRadius:     srv.conf.MaxChunkRadius,
+		JoinMessage:        srv.conf.JoinMessage,
+		QuitMessage:        srv.conf.QuitMessage,
+		Structures:         srv.conf.Structures,
+		ValidateMovement:   srv.conf.ValidateMovement,
+		MovementThreshold:  srv.conf.MovementThreshold,
+		ValidateCombat:     srv.conf.ValidateCombat,
+		AttackReach:        srv.conf.AttackReach,
+		MaxClicksPerSecond: srv.conf.MaxClicksPerSecond,
+		AttackCooldown:     srv.conf.AttackCooldown,
+	}.New(conn)
 	p := player.NewWithSession(conn.IdentityData().DisplayName, conn.IdentityData().XUID, id, srv.parseSkin(conn.ClientData()), s, pos, data)
 
 	s.Spawn(p, pos, w, gm, srv.handleSessionClose)
@@ -542,10 +702,21 @@ func (srv *Server) createPlayer(id uuid.UUID, conn session.Conn, data *player.Da
 	return s
 }
 
-// LoadWorld loads a world of the server with a specific dimension, ending
-// the program if the world could not be loaded. The layers passed are used to
-// create a generator.Flat that is used as generator for the world.
-func (srv *Server) LoadWorld(name string, dim world.Dimension, gen world.Generator, readOnly bool) {
+// LoadWorld loads a world of the server using the WorldSpec passed. The world
+// may be obtained by calling Server.World with the name of the spec once
+// loaded. An error is returned if a world with the same name is already loaded
+// or if the world.Provider of the world could not be created. The name of the
+// spec must be a single, non-empty directory name.
+func (srv *Server) LoadWorld(spec WorldSpec) (*world.World, error) {
+	if err := validateWorldName(spec.Name); err != nil {
+		return nil, fmt.Errorf("load world: %w", err)
+	}
+	if srv.World(spec.Name) != nil {
+		return nil, fmt.Errorf("load world %v: world is already loaded", spec.Name)
+	}
+	if spec.Dimension == nil {
+		spec.Dimension = world.Overworld
+	}
 	logger := srv.conf.Log
 	if v, ok := logger.(interface {
 		WithField(key string, field any) *logrus.Entry
@@ -553,41 +724,63 @@ func (srv *Server) LoadWorld(name string, dim world.Dimension, gen world.Generat
 		// Add a dimension field to be able to distinguish between the different
 		// dimensions in the log. Dimensions implement fmt.Stringer so we can
 		// just fmt.Sprint them for a readable name.
-		logger = v.WithField("dimension", strings.ToLower(fmt.Sprint(dim)))
+		logger = v.WithField("dimension", strings.ToLower(fmt.Sprint(spec.Dimension)))
 	}
 	logger.Debugf("Loading world...")
 
-	provider, err := mcdb.Config{Log: srv.conf.Log}.Open(name)
-	if err != nil {
-		panic(err)
+	provider, dir := spec.Provider, ""
+	if provider == nil {
+		var err error
+		if dir, err = srv.worldDir(spec); err != nil {
+			return nil, fmt.Errorf("load world %v: %w", spec.Name, err)
+		}
+		if provider, err = srv.conf.WorldProvider(dir); err != nil {
+			return nil, fmt.Errorf("load world %v: %w", spec.Name, err)
+		}
 	}
 
+	gen := spec.Generator
 	if gen == nil {
-		gen = FlatGenerator(dim)
+		gen = srv.conf.Generator(spec.Dimension, provider.Settings().Seed)
 	}
 
 	conf := world.Config{
 		Log:             logger,
-		Dim:             dim,
+		Dim:             spec.Dimension,
 		Provider:        provider,
 		Generator:       gen,
 		RandomTickSpeed: srv.conf.RandomTickSpeed,
-		ReadOnly:        readOnly,
+		ReadOnly:        spec.ReadOnly,
 		Entities:        srv.conf.Entities,
+		Spawning:        srv.conf.Spawning,
+
+		PortalDestination: srv.dimension,
 	}
 
 	w := conf.New()
-	logger.Infof(`Opened world "%v".`, w.Name())
 
 	srv.wmu.Lock()
-	srv.worlds[name] = w
+	if _, ok := srv.worlds[spec.Name]; ok {
+		// Another world with the same name was loaded while this one was being
+		// opened.
+		srv.wmu.Unlock()
+		w.Close()
+		return nil, fmt.Errorf("load world %v: world is already loaded", spec.Name)
+	}
+	srv.worlds[spec.Name] = w
+	w.SetSaving(srv.Saving())
+	if dir != "" {
+		srv.dirs[spec.Name] = dir
+	}
 	srv.wmu.Unlock()
+
+	logger.Infof(`Opened world "%v".`, w.Name())
+	return w, nil
 }
 
 // WorldExists returns whether the world with the provided name is loaded
 func (srv *Server) WorldExists(name string) bool {
-	_, ok := srv.worlds[name]
-	return ok
+	return srv.World(name) != nil
 }
 
 // LoadedWorlds return a list of loaded worlds
@@ -621,6 +814,7 @@ func (srv *Server) UnloadWorld(name string) {
 
 	srv.wmu.Lock()
 	delete(srv.worlds, name)
+	delete(srv.dirs, name)
 	srv.wmu.Unlock()
 }
 
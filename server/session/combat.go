@@ -0,0 +1,271 @@
+package session
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/block/cube/trace"
+	"github.com/stcraft/dragonfly/server/entity"
+	"github.com/stcraft/dragonfly/server/player/combat"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+const (
+	// positionHistoryDuration is the duration for which the positions of entities viewed by a session are
+	// remembered to rewind them when the controllable attacks.
+	positionHistoryDuration = time.Second * 2
+	// rewindMargin is the margin added to the round trip time of a session when rewinding the positions of
+	// entities, to account for the delay between the client receiving a position and rendering it.
+	rewindMargin = time.Millisecond * 50
+	// maxRewindLatency is the maximum latency of a session used to rewind the positions of entities. Sessions
+	// with a higher latency, or that pretend to have one, are validated as if they had this latency.
+	maxRewindLatency = time.Millisecond * 200
+	// maxTickAge is the maximum time between receiving the last input of the client and an attack for the
+	// time at which that input was received to be used as the time of the attack.
+	maxTickAge = time.Second / 10
+	// creativeReachBonus is the additional reach that players in creative mode have when attacking.
+	creativeReachBonus = 2
+	// hitboxMargin is the amount by which the bounding boxes of attacked entities are grown, matching the
+	// area that clients may hit an entity in.
+	hitboxMargin = 0.1
+	// violationDecay is the amount by which the violation level of a type of combat violation decreases per
+	// second.
+	violationDecay = 0.2
+)
+
+// combatValidator validates attacks by the controllable of a session. It keeps track of the positions of the
+// entities viewed by the session, so that their positions may be rewound to the moment the client attacked,
+// and of the clicks and attacks of the controllable.
+type combatValidator struct {
+	reach    float64
+	maxCPS   int
+	cooldown time.Duration
+
+	mu sync.Mutex
+	// tick is the last client tick received in a PlayerAuthInput packet and tickTime the time at which it was
+	// received. Attacks are performed during the last client tick.
+	tick     uint64
+	tickTime time.Time
+	// history holds the positions recently sent to the client for every entity viewed.
+	history map[world.Entity][]positionRecord
+	// clicks holds the times at which the controllable clicked during the last second.
+	clicks []time.Time
+	// lastAttack is the time at which the controllable last attacked an entity.
+	lastAttack time.Time
+	// levels holds the violation level of every type of combat.Violation, along with the time at which it
+	// was last updated.
+	levels map[combat.Violation]violationLevel
+}
+
+// positionRecord is a position of an entity sent to the client at a specific time.
+type positionRecord struct {
+	t   time.Time
+	pos mgl64.Vec3
+}
+
+// violationLevel is the level of a type of combat.Violation at a specific time.
+type violationLevel struct {
+	t     time.Time
+	level float64
+}
+
+// combatViolation is a combat.Violation detected by a combatValidator, along with its violation level.
+type combatViolation struct {
+	v     combat.Violation
+	level float64
+}
+
+// newCombatValidator returns a combatValidator using the reach, maximum number of clicks per second and
+// attack cooldown passed.
+func newCombatValidator(reach float64, maxCPS int, cooldown time.Duration) *combatValidator {
+	return &combatValidator{
+		reach:    reach,
+		maxCPS:   maxCPS,
+		cooldown: cooldown,
+		history:  map[world.Entity][]positionRecord{},
+		levels:   map[combat.Violation]violationLevel{},
+	}
+}
+
+// Record records the position of an entity as sent to the client at the current time.
+func (c *combatValidator) Record(e world.Entity, pos mgl64.Vec3) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	records := c.history[e]
+	// Drop all records that are too old, but keep at least one so that the last known position remains
+	// available for entities that have not moved for a while.
+	n := 0
+	for n < len(records)-1 && now.Sub(records[n+1].t) > positionHistoryDuration {
+		n++
+	}
+	c.history[e] = append(records[n:], positionRecord{t: now, pos: pos})
+}
+
+// Tick registers the client tick passed, received in a PlayerAuthInput packet. Ticks that are not newer than
+// the last tick registered are ignored.
+func (c *combatValidator) Tick(tick uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if tick <= c.tick && !c.tickTime.IsZero() {
+		return
+	}
+	c.tick, c.tickTime = tick, time.Now()
+}
+
+// Forget removes the position history of an entity, called when the entity is no longer viewed.
+func (c *combatValidator) Forget(e world.Entity) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.history, e)
+}
+
+// Click registers a click of the controllable, either as the result of swinging its arm or attacking.
+func (c *combatValidator) Click() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.click(time.Now())
+}
+
+// click registers a click at the time passed, dropping clicks older than a second.
+func (c *combatValidator) click(now time.Time) {
+	n := 0
+	for n < len(c.clicks) && now.Sub(c.clicks[n]) > time.Second {
+		n++
+	}
+	c.clicks = append(c.clicks[n:], now)
+}
+
+// Validate validates an attack of the Controllable passed on the entity passed, rewinding the position of the
+// target to where the client saw it during the client tick of the attack, using the latency passed. All
+// violations detected are returned with their updated violation level.
+func (c *combatValidator) Validate(attacker Controllable, target world.Entity, latency time.Duration) []combatViolation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.click(now)
+
+	var violations []combatViolation
+	if c.maxCPS > 0 && len(c.clicks) > c.maxCPS {
+		violations = append(violations, c.violation(combat.ClickRate(), now))
+	}
+	if now.Sub(c.lastAttack) < c.cooldown {
+		violations = append(violations, c.violation(combat.Cooldown(), now))
+	}
+
+	reach := c.reach
+	if attacker.GameMode().CreativeInventory() {
+		reach += creativeReachBonus
+	}
+	eyes := entity.EyePosition(attacker)
+	box := target.Type().BBox(target).Grow(hitboxMargin)
+
+	// The attack was performed during the last client tick, of which the input was received at tickTime. The
+	// client saw the target where the server placed it a full round trip before that.
+	attackTime := now
+	if !c.tickTime.IsZero() && now.Sub(c.tickTime) <= maxTickAge {
+		attackTime = c.tickTime
+	}
+	if latency > maxRewindLatency {
+		latency = maxRewindLatency
+	}
+	box = box.Translate(c.rewind(target, attackTime.Add(-latency*2-rewindMargin)))
+
+	if closestPoint(box, eyes).Sub(eyes).Len() > reach {
+		violations = append(violations, c.violation(combat.Reach(), now))
+	} else if !lineOfSight(attacker.World(), eyes, box) {
+		violations = append(violations, c.violation(combat.LineOfSight(), now))
+	}
+	return violations
+}
+
+// Attacked registers that the controllable successfully attacked an entity, starting the attack cooldown.
+func (c *combatValidator) Attacked() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastAttack = time.Now()
+}
+
+// rewind returns the position of the entity passed as sent to the client at the time passed. The position is
+// interpolated between the two positions recorded around that time, like the client interpolates the movement
+// of entities. If no positions were recorded for the entity, its current position is returned.
+func (c *combatValidator) rewind(e world.Entity, t time.Time) mgl64.Vec3 {
+	records := c.history[e]
+	if len(records) == 0 {
+		return e.Position()
+	}
+	i := sort.Search(len(records), func(i int) bool {
+		return records[i].t.After(t)
+	})
+	switch i {
+	case 0:
+		return records[0].pos
+	case len(records):
+		return records[len(records)-1].pos
+	}
+	prev, next := records[i-1], records[i]
+	f := float64(t.Sub(prev.t)) / float64(next.t.Sub(prev.t))
+	return prev.pos.Add(next.pos.Sub(prev.pos).Mul(f))
+}
+
+// violation increases the violation level of the combat.Violation passed by 1 and returns it. The violation
+// level decreases by violationDecay every second.
+func (c *combatValidator) violation(v combat.Violation, now time.Time) combatViolation {
+	l := c.levels[v]
+	level := math.Max(l.level-now.Sub(l.t).Seconds()*violationDecay, 0) + 1
+	c.levels[v] = violationLevel{t: now, level: level}
+	return combatViolation{v: v, level: level}
+}
+
+// validateAttack validates an attack of the controllable on the entity passed if combat validation is enabled.
+// Every violation detected is reported to the controllable, and false is returned if the attack should be
+// ignored because any of the reports was not cancelled.
+func (s *Session) validateAttack(e world.Entity) bool {
+	if s.combat == nil {
+		return true
+	}
+	valid := true
+	for _, v := range s.combat.Validate(s.c, e, s.conn.Latency()) {
+		if s.c.CombatViolation(e, v.v, v.level) {
+			valid = false
+		}
+	}
+	return valid
+}
+
+// closestPoint returns the point within the cube.BBox passed that is closest to the position passed.
+func closestPoint(box cube.BBox, pos mgl64.Vec3) mgl64.Vec3 {
+	min, max := box.Min(), box.Max()
+	return mgl64.Vec3{
+		math.Max(min[0], math.Min(pos[0], max[0])),
+		math.Max(min[1], math.Min(pos[1], max[1])),
+		math.Max(min[2], math.Min(pos[2], max[2])),
+	}
+}
+
+// lineOfSight checks if any of the closest point, the centre and the top of the cube.BBox passed can be seen
+// from the eye position passed without blocks in the way.
+func lineOfSight(w *world.World, eyes mgl64.Vec3, box cube.BBox) bool {
+	centre := box.Min().Add(box.Max()).Mul(0.5)
+	top := mgl64.Vec3{centre[0], box.Max()[1] - hitboxMargin, centre[2]}
+	for _, target := range [...]mgl64.Vec3{closestPoint(box, eyes), centre, top} {
+		if target.ApproxEqual(eyes) {
+			return true
+		}
+		blocked := false
+		trace.TraverseBlocks(eyes, target, func(pos cube.Pos) bool {
+			_, blocked = trace.BlockIntercept(pos, w, w.Block(pos), eyes, target)
+			return !blocked
+		})
+		if !blocked {
+			return true
+		}
+	}
+	return false
+}
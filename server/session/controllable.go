@@ -5,10 +5,13 @@ import (
 	"github.com/google/uuid"
 	"github.com/stcraft/dragonfly/server/block/cube"
 	"github.com/stcraft/dragonfly/server/cmd"
+	"github.com/stcraft/dragonfly/server/entity"
 	"github.com/stcraft/dragonfly/server/entity/effect"
 	"github.com/stcraft/dragonfly/server/item"
 	"github.com/stcraft/dragonfly/server/item/inventory"
+	"github.com/stcraft/dragonfly/server/permission"
 	"github.com/stcraft/dragonfly/server/player/chat"
+	"github.com/stcraft/dragonfly/server/player/combat"
 	"github.com/stcraft/dragonfly/server/player/form"
 	"github.com/stcraft/dragonfly/server/player/skin"
 	"github.com/stcraft/dragonfly/server/world"
@@ -31,12 +34,15 @@ type Controllable interface {
 	SetHeldItems(right, left item.Stack)
 
 	Move(deltaPos mgl64.Vec3, deltaYaw, deltaPitch float64)
+	MovementViolation(expected, actual mgl64.Vec3) bool
 	Speed() float64
 
 	Chat(msg ...any)
 	ExecuteCommand(commandLine string)
 	GameMode() world.GameMode
 	SetGameMode(mode world.GameMode)
+	Permissions() *permission.Set
+	Effect(e effect.Type) (effect.Effect, bool)
 	Effects() []effect.Effect
 	Flight() bool
 
@@ -47,6 +53,7 @@ type Controllable interface {
 	BreakBlock(pos cube.Pos)
 	PickBlock(pos cube.Pos)
 	AttackEntity(e world.Entity) bool
+	CombatViolation(target world.Entity, v combat.Violation, level float64) bool
 	Drop(s item.Stack) (n int)
 	SwingArm()
 	PunchAir()
@@ -60,6 +67,13 @@ type Controllable interface {
 	Respawn()
 	Dead() bool
 
+	Sleeping() (cube.Pos, bool)
+	Wake()
+
+	Riding() (entity.Rideable, bool)
+	Dismount()
+	Drive(forward, strafe float64)
+
 	StartSneaking()
 	Sneaking() bool
 	StopSneaking()
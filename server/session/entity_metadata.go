@@ -6,8 +6,10 @@ import (
 
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/stcraft/dragonfly/server/block/cube"
 	"github.com/stcraft/dragonfly/server/entity"
 	"github.com/stcraft/dragonfly/server/entity/effect"
+	"github.com/stcraft/dragonfly/server/entity/trade"
 	"github.com/stcraft/dragonfly/server/internal/nbtconv"
 	"github.com/stcraft/dragonfly/server/item"
 	"github.com/stcraft/dragonfly/server/item/potion"
@@ -34,10 +36,17 @@ func (s *Session) parseEntityMetadata(e world.Entity) protocol.EntityMetadata {
 	if _, ok := e.Type().(entity.LingeringPotionType); ok {
 		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagLingering)
 	}
+	if containerType, inv, ok := entityContainer(e); ok {
+		m[protocol.EntityDataKeyContainerType] = containerType
+		m[protocol.EntityDataKeyContainerSize] = int32(inv.Size())
+	}
 	s.addSpecificMetadata(e, m)
 	if ent, ok := e.(*entity.Ent); ok {
 		s.addSpecificMetadata(ent.Behaviour(), m)
 	}
+	if mob, ok := e.(*entity.Mob); ok {
+		s.addSpecificMetadata(mob.Behaviour(), m)
+	}
 	return m
 }
 
@@ -54,6 +63,13 @@ func (s *Session) addSpecificMetadata(e any, m protocol.EntityMetadata) {
 	if gl, ok := e.(glider); ok && gl.Gliding() {
 		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagGliding)
 	}
+	if sl, ok := e.(sleeper); ok {
+		if pos, sleeping := sl.Sleeping(); sleeping {
+			m[protocol.EntityDataKeyBedPosition] = protocol.BlockPos{int32(pos[0]), int32(pos[1]), int32(pos[2])}
+			m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagSleeping)
+			m.SetFlag(protocol.EntityDataKeyPlayerFlags, entityDataPlayerFlagSleep)
+		}
+	}
 	if b, ok := e.(breather); ok {
 		m[protocol.EntityDataKeyAirSupply] = int16(b.AirSupply().Milliseconds() / 50)
 		m[protocol.EntityDataKeyAirSupplyMax] = int16(b.MaxAirSupply().Milliseconds() / 50)
@@ -73,6 +89,17 @@ func (s *Session) addSpecificMetadata(e any, m protocol.EntityMetadata) {
 	if u, ok := e.(using); ok && u.UsingItem() {
 		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagUsingItem)
 	}
+	if b, ok := e.(blocker); ok && b.Blocking() {
+		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagBlocking)
+	}
+	if r, ok := e.(returning); ok && r.Returning() {
+		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagReturnTrident)
+	}
+	if h, ok := e.(hook); ok {
+		if target, ok := h.Target(); ok {
+			m[protocol.EntityDataKeyTarget] = int64(s.entityRuntimeID(target))
+		}
+	}
 	if c, ok := e.(arrow); ok && c.Critical() {
 		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagCritical)
 	}
@@ -98,10 +125,16 @@ func (s *Session) addSpecificMetadata(e any, m protocol.EntityMetadata) {
 	if sc, ok := e.(scaled); ok {
 		m[protocol.EntityDataKeyScale] = float32(sc.Scale())
 	}
-	if t, ok := e.(tnt); ok {
+	if t, ok := e.(tnt); ok && tntIgnited(e) {
 		m[protocol.EntityDataKeyFuseTime] = int32(t.Fuse().Milliseconds() / 50)
 		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagIgnited)
 	}
+	if r, ok := e.(entity.Rider); ok {
+		if v, ok := r.Riding(); ok {
+			m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagRiding)
+			m[protocol.EntityDataKeySeatOffset] = vec64To32(v.SeatOffset(r))
+		}
+	}
 	if n, ok := e.(named); ok {
 		m[protocol.EntityDataKeyName] = n.NameTag()
 		m[protocol.EntityDataKeyAlwaysShowNameTag] = uint8(1)
@@ -159,6 +192,23 @@ func (s *Session) addSpecificMetadata(e any, m protocol.EntityMetadata) {
 			}
 		}
 	}
+	if sw, ok := e.(swelling); ok && sw.Swelling() {
+		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagIgnited)
+	}
+	if p, ok := e.(powered); ok && p.Powered() {
+		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagPowered)
+	}
+	if sh, ok := e.(shearable); ok {
+		m[protocol.EntityDataKeyColorIndex] = sh.Colour().Uint8()
+		if sh.Sheared() {
+			m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagSheared)
+		}
+	}
+	if t, ok := e.(trader); ok {
+		m[protocol.EntityDataKeyTradeTier] = int32(t.Tier())
+		m[protocol.EntityDataKeyMaxTradeTier] = int32(trade.MaxTier)
+		m[protocol.EntityDataKeyTradeExperience] = int32(t.TradeExperience())
+	}
 	if v, ok := e.(variable); ok {
 		m[protocol.EntityDataKeyVariant] = v.Variant()
 	}
@@ -183,6 +233,14 @@ type glider interface {
 	Gliding() bool
 }
 
+type sleeper interface {
+	Sleeping() (cube.Pos, bool)
+}
+
+// entityDataPlayerFlagSleep is the index of the flag in protocol.EntityDataKeyPlayerFlags that is set when a
+// player is sleeping.
+const entityDataPlayerFlagSleep = 1
+
 type breather interface {
 	Breathing() bool
 	AirSupply() time.Duration
@@ -242,6 +300,18 @@ type arrow interface {
 	Critical() bool
 }
 
+type blocker interface {
+	Blocking() bool
+}
+
+type returning interface {
+	Returning() bool
+}
+
+type hook interface {
+	Target() (world.Entity, bool)
+}
+
 type orb interface {
 	Experience() int
 }
@@ -259,10 +329,41 @@ type tnt interface {
 	Fuse() time.Duration
 }
 
+type ignitable interface {
+	Ignited() bool
+}
+
+// tntIgnited checks if an entity implementing tnt is ignited. Entities such as TNT minecarts have a fuse, but are
+// only ignited once activated.
+func tntIgnited(e any) bool {
+	if i, ok := e.(ignitable); ok {
+		return i.Ignited()
+	}
+	return true
+}
+
 type living interface {
 	DeathPosition() (mgl64.Vec3, world.Dimension, bool)
 }
 
+type swelling interface {
+	Swelling() bool
+}
+
+type powered interface {
+	Powered() bool
+}
+
+type shearable interface {
+	Colour() item.Colour
+	Sheared() bool
+}
+
+type trader interface {
+	Tier() int
+	TradeExperience() int
+}
+
 type variable interface {
 	Variant() int32
 }
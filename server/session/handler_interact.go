@@ -18,6 +18,8 @@ func (h *InteractHandler) Handle(p packet.Packet, s *Session) error {
 	switch pk.ActionType {
 	case packet.InteractActionMouseOverEntity:
 		// We don't need this action.
+	case packet.InteractActionLeaveVehicle:
+		s.c.Dismount()
 	case packet.InteractActionOpenInventory:
 		if s.invOpened {
 			// When there is latency, this might end up being sent multiple times. If we send a ContainerOpen
@@ -130,7 +130,13 @@ func (h *InventoryTransactionHandler) handleUseItemOnEntityTransaction(data *pro
 	case protocol.UseItemOnEntityActionInteract:
 		valid = s.c.UseItemOnEntity(e)
 	case protocol.UseItemOnEntityActionAttack:
-		valid = s.c.AttackEntity(e)
+		if !s.validateAttack(e) {
+			valid = false
+			break
+		}
+		if valid = s.c.AttackEntity(e); valid && s.combat != nil {
+			s.combat.Attacked()
+		}
 	default:
 		return fmt.Errorf("unhandled UseItemOnEntity ActionType %v", data.ActionType)
 	}
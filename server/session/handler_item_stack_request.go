@@ -89,6 +89,10 @@ func (h *ItemStackRequestHandler) handleRequest(req protocol.ItemStackRequest, s
 		case *protocol.BeaconPaymentStackRequestAction:
 			err = h.handleBeaconPayment(a, s)
 		case *protocol.CraftRecipeStackRequestAction:
+			if s.containerOpened.Load() && s.openedMerchant.Load() != nil {
+				err = h.handleTrade(a, s)
+				break
+			}
 			if s.containerOpened.Load() {
 				var special bool
 				switch s.c.World().Block(s.openedPos.Load()).(type) {
@@ -17,6 +17,9 @@ type PlayerAuthInputHandler struct{}
 // Handle ...
 func (h PlayerAuthInputHandler) Handle(p packet.Packet, s *Session) error {
 	pk := p.(*packet.PlayerAuthInput)
+	if s.combat != nil {
+		s.combat.Tick(pk.Tick)
+	}
 	if err := h.handleMovement(pk, s); err != nil {
 		return err
 	}
@@ -45,12 +48,13 @@ func (h PlayerAuthInputHandler) handleMovement(pk *packet.PlayerAuthInput, s *Se
 
 	newPos := vec32To64(pk.Position)
 	deltaPos, deltaYaw, deltaPitch := newPos.Sub(pos), float64(pk.Yaw)-yaw, float64(pk.Pitch)-pitch
-	if mgl64.FloatEqual(deltaPos.Len(), 0) && mgl64.FloatEqual(deltaYaw, 0) && mgl64.FloatEqual(deltaPitch, 0) {
-		// The PlayerAuthInput packet is sent every tick, so don't do anything if the position and rotation
-		// were unchanged.
-		return nil
+	_, riding := s.c.Riding()
+	if riding {
+		// The position of a player riding an entity is controlled by the server. Instead of moving, the input
+		// of the player is forwarded to the entity it is riding.
+		s.c.Drive(float64(pk.MoveVector.Y()), float64(pk.MoveVector.X()))
+		deltaPos = mgl64.Vec3{}
 	}
-
 	if expected := s.teleportPos.Load(); expected != nil {
 		if newPos.Sub(*expected).Len() > 1 {
 			// The player has moved before it received the teleport packet. Ignore this movement entirely and
@@ -59,6 +63,26 @@ func (h PlayerAuthInputHandler) handleMovement(pk *packet.PlayerAuthInput, s *Se
 			return nil
 		}
 		s.teleportPos.Store(nil)
+		if s.movement != nil {
+			s.movement.Reset(newPos)
+		}
+	}
+	if s.movement != nil && !riding {
+		if expected, ok := s.movement.Validate(s.c, movementInputFromPacket(pk), newPos); !ok {
+			if s.c.MovementViolation(expected, newPos) {
+				// The movement diverged too much from the movement we simulated and the violation wasn't
+				// cancelled, so teleport the player back to its last valid position. Movement is ignored
+				// until the client has processed the teleport.
+				s.ViewEntityTeleport(s.c, s.c.Position())
+				return nil
+			}
+			s.movement.Accept(newPos)
+		}
+	}
+	if mgl64.FloatEqual(deltaPos.Len(), 0) && mgl64.FloatEqual(deltaYaw, 0) && mgl64.FloatEqual(deltaPitch, 0) {
+		// The PlayerAuthInput packet is sent every tick, so don't do anything if the position and rotation
+		// were unchanged.
+		return nil
 	}
 
 	s.c.Move(deltaPos, deltaYaw, deltaPitch)
@@ -124,6 +148,9 @@ func (h PlayerAuthInputHandler) handleInputFlags(flags uint64, s *Session) {
 		s.c.Jump()
 	}
 	if flags&packet.InputFlagMissedSwing != 0 {
+		if s.combat != nil {
+			s.combat.Click()
+		}
 		s.swingingArm.Store(true)
 		defer s.swingingArm.Store(false)
 		s.c.PunchAir()
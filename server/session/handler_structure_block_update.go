@@ -0,0 +1,109 @@
+package session
+
+import (
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"github.com/stcraft/dragonfly/server/block"
+)
+
+const (
+	// maxStructureSize is the maximum size of the region of a structure block on the X and Z axis.
+	maxStructureSize = 64
+	// maxStructureHeight is the maximum size of the region of a structure block on the Y axis.
+	maxStructureHeight = 384
+	// maxStructureOffset is the maximum offset of the region of a structure block on any axis.
+	maxStructureOffset = 64
+)
+
+// StructureBlockUpdateHandler handles the StructureBlockUpdate packet, sent when a player edits a structure
+// block using the in-game UI.
+type StructureBlockUpdateHandler struct{}
+
+// Handle ...
+func (StructureBlockUpdateHandler) Handle(p packet.Packet, s *Session) error {
+	pk := p.(*packet.StructureBlockUpdate)
+	pos := blockPosFromProtocol(pk.Position)
+	if !canReach(s.c, pos.Vec3Middle()) {
+		return fmt.Errorf("block at %v is not within reach", pos)
+	}
+	if !s.c.GameMode().CreativeInventory() {
+		return fmt.Errorf("structure block at %v edited outside of creative mode", pos)
+	}
+	if !s.c.Permissions().Operator() {
+		// Structure blocks save to and load from the structure storage of the server, so, like in vanilla,
+		// only operators may use them.
+		return fmt.Errorf("structure block at %v edited by a player that is not an operator", pos)
+	}
+	w := s.c.World()
+	b, ok := w.Block(pos).(block.StructureBlock)
+	if !ok {
+		return fmt.Errorf("block at %v is not a structure block", pos)
+	}
+
+	mode, ok := structureBlockModeFromProtocol(pk.StructureBlockType)
+	if !ok {
+		return fmt.Errorf("unknown structure block type %v", pk.StructureBlockType)
+	}
+	size, offset := blockPosFromProtocol(pk.Settings.Size), blockPosFromProtocol(pk.Settings.Offset)
+	if size[0] < 0 || size[0] > maxStructureSize || size[1] < 0 || size[1] > maxStructureHeight || size[2] < 0 || size[2] > maxStructureSize {
+		return fmt.Errorf("invalid structure size %v", size)
+	}
+	for _, v := range offset {
+		if v < -maxStructureOffset || v > maxStructureOffset {
+			return fmt.Errorf("invalid structure offset %v", offset)
+		}
+	}
+	if pk.Settings.Rotation > protocol.StructureRotationRotate270 {
+		return fmt.Errorf("invalid structure rotation %v", pk.Settings.Rotation)
+	} else if pk.Settings.Mirror > protocol.StructureMirrorBothAxes {
+		return fmt.Errorf("invalid structure mirror %v", pk.Settings.Mirror)
+	}
+
+	b.Mode, b.Name, b.DataField = mode, pk.StructureName, pk.DataField
+	b.Size, b.Offset = size, offset
+	b.Rotation = int(pk.Settings.Rotation)
+	b.MirrorX = pk.Settings.Mirror == protocol.StructureMirrorXAxis || pk.Settings.Mirror == protocol.StructureMirrorBothAxes
+	b.MirrorZ = pk.Settings.Mirror == protocol.StructureMirrorZAxis || pk.Settings.Mirror == protocol.StructureMirrorBothAxes
+	b.IncludeEntities = !pk.Settings.IgnoreEntities
+	b.ShowBoundingBox = pk.ShowBoundingBox
+	w.SetBlock(pos, b, nil)
+
+	if !pk.ShouldTrigger {
+		return nil
+	}
+	var err error
+	switch mode {
+	case block.StructureSaveMode():
+		err = b.Save(pos, w, s.structures)
+	case block.StructureLoadMode():
+		err = b.Load(pos, w, s.structures)
+	}
+	if err != nil {
+		// Failing to save or load a structure, for example because no structure with the name exists, is not
+		// a reason to disconnect the player, so we only notify them.
+		s.log.Debugf("failed to %v structure %q for %v: %v", mode, b.Name, s.c.Name(), err)
+		s.SendMessage(text.Colourf("<red>Could not %v structure %v: %v</red>", mode, b.Name, err))
+	}
+	return nil
+}
+
+// structureBlockModeFromProtocol converts a structure block type sent in a StructureBlockUpdate packet to a
+// block.StructureBlockMode.
+func structureBlockModeFromProtocol(t int32) (block.StructureBlockMode, bool) {
+	switch t {
+	case packet.StructureBlockData:
+		return block.StructureDataMode(), true
+	case packet.StructureBlockSave:
+		return block.StructureSaveMode(), true
+	case packet.StructureBlockLoad:
+		return block.StructureLoadMode(), true
+	case packet.StructureBlockCorner:
+		return block.StructureCornerMode(), true
+	case packet.StructureBlockExport:
+		return block.StructureExportMode(), true
+	}
+	return block.StructureBlockMode{}, false
+}
@@ -0,0 +1,77 @@
+package session
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/stcraft/dragonfly/server/entity"
+	"github.com/stcraft/dragonfly/server/item"
+)
+
+const (
+	// tradeFirstInputSlot is the slot index of the first input item in the trading window.
+	tradeFirstInputSlot = 0x04
+	// tradeSecondInputSlot is the slot index of the second input item in the trading window.
+	tradeSecondInputSlot = 0x05
+
+	// tradeNetworkIDOffset is added to the index of a trade offer to obtain its network ID. The offset ensures
+	// that the network IDs of trade offers do not overlap with those of crafting recipes.
+	tradeNetworkIDOffset = 0x10000
+)
+
+// handleTrade handles a CraftRecipe stack request action made using a trading window.
+func (h *ItemStackRequestHandler) handleTrade(a *protocol.CraftRecipeStackRequestAction, s *Session) error {
+	m := s.openedMerchant.Load()
+	offers := m.Offers()
+	index := int(a.RecipeNetworkID) - tradeNetworkIDOffset
+	if index < 0 || index >= len(offers) {
+		return fmt.Errorf("trade offer with network id %v does not exist", a.RecipeNetworkID)
+	}
+	o := offers[index]
+	if o.Disabled() {
+		return fmt.Errorf("trade offer with network id %v is disabled", a.RecipeNetworkID)
+	}
+	tier := m.Tier()
+	if o.Tier > tier {
+		return fmt.Errorf("trade offer with network id %v is locked", a.RecipeNetworkID)
+	}
+
+	// Check if the input items cover the price of the offer.
+	firstSlot := protocol.StackRequestSlotInfo{ContainerID: protocol.ContainerTradeTwoIngredientOne, Slot: tradeFirstInputSlot}
+	secondSlot := protocol.StackRequestSlotInfo{ContainerID: protocol.ContainerTradeTwoIngredientTwo, Slot: tradeSecondInputSlot}
+	first, _ := h.itemInSlot(firstSlot, s)
+	second, _ := h.itemInSlot(secondSlot, s)
+	price := o.Price()
+	if !coversPrice(first, price) {
+		return fmt.Errorf("first input item does not cover the price of the trade offer")
+	}
+	if !o.SecondInput.Empty() && !coversPrice(second, o.SecondInput) {
+		return fmt.Errorf("second input item does not cover the price of the trade offer")
+	}
+	if !m.Trade(s.c, index) {
+		return fmt.Errorf("trade offer with network id %v was not traded by the merchant", a.RecipeNetworkID)
+	}
+
+	h.setItemInSlot(firstSlot, first.Grow(-price.Count()), s)
+	if !o.SecondInput.Empty() {
+		h.setItemInSlot(secondSlot, second.Grow(-o.SecondInput.Count()), s)
+	}
+	if o.RewardExperience {
+		w, pos := s.c.World(), s.openedMerchantEntity.Load().Position().Add(mgl64.Vec3{0, 0.5})
+		for _, orb := range entity.NewExperienceOrbs(pos, 3+rand.Intn(4)) {
+			w.AddEntity(orb)
+		}
+	}
+	if m.Tier() != tier {
+		// The merchant reached a new tier, unlocking new offers, so the client needs to be updated.
+		s.sendTrade()
+	}
+	return h.createResults(s, o.Output)
+}
+
+// coversPrice checks if the item stack passed is comparable to the price passed and has a count at least as high.
+func coversPrice(has, price item.Stack) bool {
+	return has.Comparable(price) && has.Count() >= price.Count()
+}
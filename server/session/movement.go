@@ -0,0 +1,466 @@
+package session
+
+import (
+	"math"
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/entity/effect"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/item/inventory"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+const (
+	// movementErrorMargin is the divergence in blocks between the simulated and the actual position of a
+	// player that is tolerated every tick without being added to the violation buffer.
+	movementErrorMargin = 0.03
+	// movementBufferDecay is the amount by which the violation buffer decreases every tick that the movement
+	// of the player is within the movementErrorMargin.
+	movementBufferDecay = 0.005
+	// movementGraceTicks is the number of ticks after a teleport or a velocity change during which movement
+	// is validated with a wider tolerance, giving the client time to process it.
+	movementGraceTicks = 10
+	// movementGraceMargin is the divergence in blocks tolerated every tick during the grace ticks, in addition
+	// to the movementErrorMargin and the change in velocity that caused them.
+	movementGraceMargin = 0.1
+	// maxSwimSpeed is the maximum distance in blocks that a player swimming in water may move in a single
+	// tick, including the effects of Depth Strider and Dolphin's Grace.
+	maxSwimSpeed = 0.6
+	// maxGlideSpeed is the maximum distance in blocks that a player gliding with an elytra may move in a
+	// single tick, including diving and boosting with fireworks.
+	maxGlideSpeed = 4.0
+	// stepHeight is the maximum height of a block that a player can walk onto without jumping.
+	stepHeight = 0.6
+	// bedBounceFactor is the part of the vertical velocity of a player landing on a bed with which it bounces
+	// back up.
+	bedBounceFactor = 0.66
+)
+
+// climbableBlocks holds the names of all blocks that players can climb. Climbing is not simulated, so movement
+// of players in these blocks is not validated.
+var climbableBlocks = map[string]struct{}{
+	"minecraft:ladder":                       {},
+	"minecraft:vine":                         {},
+	"minecraft:twisting_vines":               {},
+	"minecraft:weeping_vines":                {},
+	"minecraft:cave_vines":                   {},
+	"minecraft:cave_vines_body_with_berries": {},
+	"minecraft:cave_vines_head_with_berries": {},
+	"minecraft:scaffolding":                  {},
+}
+
+// unsimulatedBlocks holds the names of blocks that change the movement of players in ways that are not
+// simulated, such as cobwebs slowing players down and slime blocks bouncing them up. Movement of players in or
+// on these blocks is not validated.
+var unsimulatedBlocks = map[string]struct{}{
+	"minecraft:web":              {},
+	"minecraft:honey_block":      {},
+	"minecraft:slime":            {},
+	"minecraft:powder_snow":      {},
+	"minecraft:bubble_column":    {},
+	"minecraft:sweet_berry_bush": {},
+}
+
+// movementSimulator simulates the movement of a player server-side, based on the input it sends every tick. It
+// replays the input of the player against the collision of blocks in the world, gravity, effects and liquids to
+// find out where the player should be after a tick, so that movement diverging from it may be flagged.
+type movementSimulator struct {
+	// threshold is the value that the violation buffer must exceed for the movement of the player to be
+	// flagged.
+	threshold float64
+
+	mu  sync.Mutex
+	pos mgl64.Vec3
+	vel mgl64.Vec3
+	// buffer holds the accumulated divergence between the simulated and actual movement of the player.
+	buffer float64
+	// grace is the number of ticks left during which movement is validated with a wider tolerance.
+	grace int
+	// graceMargin is the divergence in blocks tolerated every tick during the grace ticks, in addition to the
+	// movementErrorMargin.
+	graceMargin float64
+}
+
+// movementInput holds the input of a player relevant to its movement during a single tick.
+type movementInput struct {
+	forward, strafe              float64
+	yaw                          float64
+	jumping, sprinting, sneaking bool
+}
+
+// movementInputFromPacket returns the movementInput held by a packet.PlayerAuthInput.
+func movementInputFromPacket(pk *packet.PlayerAuthInput) movementInput {
+	return movementInput{
+		forward:   float64(pk.MoveVector.Y()),
+		strafe:    float64(pk.MoveVector.X()),
+		yaw:       float64(pk.Yaw),
+		jumping:   pk.InputData&packet.InputFlagJumping != 0,
+		sprinting: pk.InputData&packet.InputFlagSprinting != 0,
+		sneaking:  pk.InputData&packet.InputFlagSneaking != 0,
+	}
+}
+
+// Reset resets the simulator to the position passed, clearing the velocity and the violation buffer. Movement
+// is validated with a slightly wider tolerance for a short period after resetting.
+func (m *movementSimulator) Reset(pos mgl64.Vec3) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pos, m.vel, m.buffer = pos, mgl64.Vec3{}, 0
+	m.grace, m.graceMargin = movementGraceTicks, movementGraceMargin
+}
+
+// SetVelocity changes the velocity of the simulated player, for example as the result of knock-back. As the
+// client only applies the velocity once it receives it, the movement of the player may diverge by up to the
+// change in velocity for a short period afterwards.
+func (m *movementSimulator) SetVelocity(vel mgl64.Vec3) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.grace, m.graceMargin = movementGraceTicks, vel.Sub(m.vel).Len()+movementGraceMargin
+	m.vel = vel
+}
+
+// Validate simulates a tick of movement of the Controllable passed using the movementInput and compares the
+// result with the position that the client moved to. If the divergence accumulated over the last ticks
+// exceeds the threshold of the simulator, Validate returns the simulated position and false. In any other
+// case, the simulator is synchronised with the position of the client and true is returned.
+func (m *movementSimulator) Validate(c Controllable, in movementInput, actual mgl64.Vec3) (mgl64.Vec3, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w := c.World()
+	box := c.Type().BBox(c)
+	if m.exempt(c, w, box, actual) {
+		m.sync(c, actual, actual.Sub(m.pos), [3]bool{})
+		m.buffer = 0
+		return actual, true
+	}
+	margin := movementErrorMargin
+	if m.grace > 0 {
+		m.grace--
+		margin += m.graceMargin
+	}
+
+	var expected mgl64.Vec3
+	var collided [3]bool
+	if limit, ok := m.speedLimit(c, w, box); ok {
+		expected, collided = m.simulateBounded(w, box, actual, limit)
+	} else {
+		expected, collided = m.simulate(c, w, box, in)
+	}
+	if diff := expected.Sub(actual).Len(); diff > margin {
+		m.buffer += diff - margin
+	} else {
+		m.buffer = math.Max(m.buffer-movementBufferDecay, 0)
+	}
+	if m.buffer > m.threshold {
+		return expected, false
+	}
+	m.sync(c, actual, actual.Sub(m.pos), collided)
+	return actual, true
+}
+
+// Accept accepts the movement of the client to the position passed after it was flagged, resetting the
+// violation buffer.
+func (m *movementSimulator) Accept(pos mgl64.Vec3) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pos, m.buffer = pos, 0
+}
+
+// exempt checks if the movement of the Controllable passed from the position of the simulator to the actual
+// position passed cannot be simulated, for example because it is flying, climbing or moving through a cobweb.
+func (m *movementSimulator) exempt(c Controllable, w *world.World, box cube.BBox, actual mgl64.Vec3) bool {
+	if _, riding := c.Riding(); riding {
+		return true
+	}
+	if _, sleeping := c.Sleeping(); sleeping {
+		return true
+	}
+	if c.Dead() || c.Flying() {
+		return true
+	}
+	exempt := false
+	for _, pos := range [...]mgl64.Vec3{m.pos, actual} {
+		// Blocks just below the player, such as slime blocks, are included, as they affect players standing
+		// on them.
+		blocksIn(w, box.Translate(pos).Extend(mgl64.Vec3{0, -0.5}), func(_ cube.Pos, b world.Block) bool {
+			name, _ := b.EncodeBlock()
+			_, climbable := climbableBlocks[name]
+			_, unsimulated := unsimulatedBlocks[name]
+			exempt = climbable || unsimulated
+			return !exempt
+		})
+		if exempt {
+			return true
+		}
+	}
+	return false
+}
+
+// speedLimit returns the maximum distance that the Controllable passed may move in a single tick if it is
+// swimming in water or gliding with an elytra. Both are controlled by the client, so the limit is only
+// returned if the player is actually in water or wearing an elytra that is not broken.
+func (m *movementSimulator) speedLimit(c Controllable, w *world.World, box cube.BBox) (float64, bool) {
+	if c.Gliding() {
+		if a, ok := c.(interface{ Armour() *inventory.Armour }); ok {
+			chest := a.Armour().Chestplate()
+			if _, ok := chest.Item().(item.Elytra); ok && chest.Durability() >= 2 {
+				return maxGlideSpeed, true
+			}
+		}
+	}
+	if c.Swimming() {
+		if water, _ := liquidsIn(w, box.Translate(m.pos)); water {
+			return maxSwimSpeed, true
+		}
+	}
+	return 0, false
+}
+
+// simulateBounded simulates a single tick of movement of a player towards the actual position passed, for
+// movement that cannot be simulated precisely, such as swimming or gliding. The movement is limited to the
+// distance passed and collides with blocks, so that the player cannot move too fast or through blocks.
+func (m *movementSimulator) simulateBounded(w *world.World, box cube.BBox, actual mgl64.Vec3, limit float64) (mgl64.Vec3, [3]bool) {
+	vel := actual.Sub(m.pos)
+	if l := vel.Len(); l > limit {
+		vel = vel.Mul(limit / l)
+	}
+	delta := collide(w, box.Translate(m.pos), vel, onGround(w, box.Translate(m.pos)))
+	collided := [3]bool{
+		!mgl64.FloatEqual(delta[0], vel[0]),
+		!mgl64.FloatEqual(delta[1], vel[1]),
+		!mgl64.FloatEqual(delta[2], vel[2]),
+	}
+	return m.pos.Add(delta), collided
+}
+
+// simulate simulates a single tick of movement of the Controllable passed, starting at the position and
+// velocity of the simulator. The resulting position is returned, along with the axes on which the movement
+// collided with blocks.
+func (m *movementSimulator) simulate(c Controllable, w *world.World, box cube.BBox, in movementInput) (mgl64.Vec3, [3]bool) {
+	vel := m.vel
+	ground := onGround(w, box.Translate(m.pos))
+	water, lava := liquidsIn(w, box.Translate(m.pos))
+
+	speed := c.Speed()
+	if sprinting := c.Sprinting(); in.sprinting && !sprinting {
+		speed *= 1.3
+	} else if !in.sprinting && sprinting {
+		speed /= 1.3
+	}
+	forward, strafe := in.forward*0.98, in.strafe*0.98
+	if in.sneaking {
+		forward, strafe = forward*0.3, strafe*0.3
+	}
+
+	if in.jumping {
+		switch {
+		case water || lava:
+			vel[1] += 0.04
+		case ground:
+			vel[1] = 0.42
+			if e, ok := c.Effect(effect.JumpBoost{}); ok {
+				vel[1] += float64(e.Level()) * 0.1
+			}
+			if in.sprinting {
+				yaw := mgl64.DegToRad(in.yaw)
+				vel[0] -= math.Sin(yaw) * 0.2
+				vel[2] += math.Cos(yaw) * 0.2
+			}
+		}
+	}
+
+	switch {
+	case water || lava:
+		vel = moveRelative(vel, forward, strafe, in.yaw, 0.02)
+	case ground:
+		// The acceleration on the ground is scaled so that it is balanced out by the friction of the block
+		// below.
+		f := blockFriction(w, m.pos)
+		vel = moveRelative(vel, forward, strafe, in.yaw, speed*(0.216/(f*f*f)))
+	case in.sprinting:
+		vel = moveRelative(vel, forward, strafe, in.yaw, 0.026)
+	default:
+		vel = moveRelative(vel, forward, strafe, in.yaw, 0.02)
+	}
+
+	delta := collide(w, box.Translate(m.pos), vel, ground)
+	collided := [3]bool{
+		!mgl64.FloatEqual(delta[0], vel[0]),
+		!mgl64.FloatEqual(delta[1], vel[1]),
+		!mgl64.FloatEqual(delta[2], vel[2]),
+	}
+	return m.pos.Add(delta), collided
+}
+
+// sync synchronises the simulator with the position that the client moved to. The velocity for the next tick
+// is computed from the movement of the client, after applying gravity and friction.
+func (m *movementSimulator) sync(c Controllable, pos, delta mgl64.Vec3, collided [3]bool) {
+	w, box := c.World(), c.Type().BBox(c).Translate(pos)
+	vel := delta
+	for i, hit := range collided {
+		if hit {
+			vel[i] = 0
+		}
+	}
+	ground := onGround(w, box)
+	if ground {
+		// The player either landed or stepped onto a block, neither of which leave it with any vertical
+		// velocity, unless it landed on a bed without sneaking, which bounces it back up.
+		vel[1] = 0
+		if _, bed := w.Block(cube.PosFromVec3(pos.Sub(mgl64.Vec3{0, 0.2}))).(block.Bed); bed && collided[1] && m.vel[1] < 0 && !c.Sneaking() {
+			vel[1] = -m.vel[1] * bedBounceFactor
+		}
+	}
+	water, lava := liquidsIn(w, box)
+
+	switch {
+	case water:
+		vel = vel.Mul(0.8)
+		vel[1] -= 0.02
+	case lava:
+		vel = vel.Mul(0.5)
+		vel[1] -= 0.02
+	default:
+		if e, ok := c.Effect(effect.Levitation{}); ok {
+			vel[1] += (0.05*float64(e.Level()) - vel[1]) * 0.2
+		} else if _, ok := c.Effect(effect.SlowFalling{}); ok && vel[1] <= 0 {
+			vel[1] -= 0.01
+		} else {
+			vel[1] -= 0.08
+		}
+		vel[1] *= 0.98
+
+		friction := 0.91
+		if ground {
+			friction *= blockFriction(w, pos)
+		}
+		vel[0] *= friction
+		vel[2] *= friction
+	}
+	if _, ok := w.Block(cube.PosFromVec3(pos.Sub(mgl64.Vec3{0, 0.5}))).(block.SoulSand); ok && ground {
+		vel[0] *= 0.4
+		vel[2] *= 0.4
+	}
+	m.pos, m.vel = pos, vel
+}
+
+// moveRelative accelerates the velocity passed by the forward and strafe input, rotated by the yaw passed in
+// degrees and scaled so that the acceleration is at most equal to speed.
+func moveRelative(vel mgl64.Vec3, forward, strafe, yaw, speed float64) mgl64.Vec3 {
+	l := forward*forward + strafe*strafe
+	if l < 1e-4 {
+		return vel
+	}
+	f := speed / math.Max(math.Sqrt(l), 1)
+	forward, strafe = forward*f, strafe*f
+
+	sin, cos := math.Sincos(mgl64.DegToRad(yaw))
+	vel[0] += strafe*cos - forward*sin
+	vel[2] += forward*cos + strafe*sin
+	return vel
+}
+
+// collide returns the movement of the cube.BBox passed when moving with the velocity passed, taking the
+// collision with blocks into account. If the box is on the ground and collides horizontally, it attempts to
+// step onto the obstacle if it is no higher than stepHeight.
+func collide(w *world.World, box cube.BBox, vel mgl64.Vec3, onGround bool) mgl64.Vec3 {
+	blocks := blockBBoxesAround(w, box.Extend(vel).Extend(mgl64.Vec3{0, stepHeight}))
+	delta := collideWith(blocks, box, vel)
+	if !onGround || (mgl64.FloatEqual(delta[0], vel[0]) && mgl64.FloatEqual(delta[2], vel[2])) {
+		return delta
+	}
+	// Try to step up onto the obstacle: Move up first, then horizontally and finally back down.
+	step := collideWith(blocks, box, mgl64.Vec3{0, stepHeight})
+	stepped := collideWith(blocks, box.Translate(step), mgl64.Vec3{vel[0], 0, vel[2]})
+	down := collideWith(blocks, box.Translate(step.Add(stepped)), mgl64.Vec3{0, -step[1] + math.Min(vel[1], 0)})
+	stepped = step.Add(stepped).Add(down)
+	if stepped[0]*stepped[0]+stepped[2]*stepped[2] > delta[0]*delta[0]+delta[2]*delta[2] {
+		return stepped
+	}
+	return delta
+}
+
+// collideWith returns the movement of the cube.BBox passed when moving with the velocity passed, colliding with
+// the block boxes passed on the Y, X and Z axes in that order.
+func collideWith(blocks []cube.BBox, box cube.BBox, vel mgl64.Vec3) mgl64.Vec3 {
+	dx, dy, dz := vel[0], vel[1], vel[2]
+	for _, b := range blocks {
+		dy = box.YOffset(b, dy)
+	}
+	box = box.Translate(mgl64.Vec3{0, dy})
+	for _, b := range blocks {
+		dx = box.XOffset(b, dx)
+	}
+	box = box.Translate(mgl64.Vec3{dx})
+	for _, b := range blocks {
+		dz = box.ZOffset(b, dz)
+	}
+	return mgl64.Vec3{dx, dy, dz}
+}
+
+// blockBBoxesAround returns the bounding boxes of all blocks that intersect with the cube.BBox passed, translated
+// to the position of the block.
+func blockBBoxesAround(w *world.World, box cube.BBox) []cube.BBox {
+	var boxes []cube.BBox
+	blocksIn(w, box.Grow(0.25), func(pos cube.Pos, b world.Block) bool {
+		for _, bb := range b.Model().BBox(pos, w) {
+			boxes = append(boxes, bb.Translate(pos.Vec3()))
+		}
+		return true
+	})
+	return boxes
+}
+
+// blocksIn calls the function passed for every block that intersects with the cube.BBox passed, until it
+// returns false.
+func blocksIn(w *world.World, box cube.BBox, f func(pos cube.Pos, b world.Block) bool) {
+	min, max := cube.PosFromVec3(box.Min()), cube.PosFromVec3(box.Max())
+	for y := min[1]; y <= max[1]; y++ {
+		for x := min[0]; x <= max[0]; x++ {
+			for z := min[2]; z <= max[2]; z++ {
+				pos := cube.Pos{x, y, z}
+				if !f(pos, w.Block(pos)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// onGround checks if the cube.BBox passed is standing on top of a block.
+func onGround(w *world.World, box cube.BBox) bool {
+	const d = -0.01
+	return !mgl64.FloatEqual(collideWith(blockBBoxesAround(w, box.Extend(mgl64.Vec3{0, d})), box, mgl64.Vec3{0, d})[1], d)
+}
+
+// liquidsIn checks if the cube.BBox passed intersects with water or lava.
+func liquidsIn(w *world.World, box cube.BBox) (water, lava bool) {
+	box = box.Grow(-0.001)
+	min, max := cube.PosFromVec3(box.Min()), cube.PosFromVec3(box.Max())
+	for y := min[1]; y <= max[1]; y++ {
+		for x := min[0]; x <= max[0]; x++ {
+			for z := min[2]; z <= max[2]; z++ {
+				switch l, _ := w.Liquid(cube.Pos{x, y, z}); l.(type) {
+				case block.Water:
+					water = true
+				case block.Lava:
+					lava = true
+				}
+			}
+		}
+	}
+	return water, lava
+}
+
+// blockFriction returns the friction of the block below the position passed.
+func blockFriction(w *world.World, pos mgl64.Vec3) float64 {
+	if f, ok := w.Block(cube.PosFromVec3(pos.Sub(mgl64.Vec3{0, 0.5}))).(block.Frictional); ok {
+		return f.Friction()
+	}
+	return 0.6
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/stcraft/dragonfly/server/item/creative"
 	"github.com/stcraft/dragonfly/server/item/inventory"
 	"github.com/stcraft/dragonfly/server/item/recipe"
+	"github.com/stcraft/dragonfly/server/permission"
 	"github.com/stcraft/dragonfly/server/player/form"
 	"github.com/stcraft/dragonfly/server/player/skin"
 	"github.com/stcraft/dragonfly/server/world"
@@ -54,6 +55,19 @@ func (s *Session) closeCurrentContainer() {
 
 	s.closeWindow()
 
+	if m := s.openedMerchant.Load(); m != nil {
+		s.openedMerchant.Store(nil)
+		s.openedMerchantEntity.Store(nil)
+		if c, ok := m.(tradeCloser); ok {
+			c.StopTrading(s.c)
+		}
+		return
+	}
+	if e := s.openedEntity.Load(); e != nil {
+		s.openedEntity.Store(nil)
+		return
+	}
+
 	pos := s.openedPos.Load()
 	w := s.c.World()
 	b := w.Block(pos)
@@ -86,6 +100,9 @@ func (s *Session) EmptyUIInventory() {
 
 // SendRespawn spawns the Controllable entity of the session client-side in the world, provided it has died.
 func (s *Session) SendRespawn(pos mgl64.Vec3, state byte) {
+	if s.movement != nil {
+		s.movement.Reset(pos)
+	}
 	s.writePacket(&packet.Respawn{
 		Position:        vec64To32(pos.Add(entityOffset(s.c))),
 		State:           state,
@@ -96,6 +113,10 @@ func (s *Session) SendRespawn(pos mgl64.Vec3, state byte) {
 // sendRecipes sends the current crafting recipes to the session.
 func (s *Session) sendRecipes() {
 	recipes := make([]protocol.Recipe, 0, len(recipe.Recipes()))
+	var (
+		potionRecipes          []protocol.PotionRecipe
+		containerChangeRecipes []protocol.PotionContainerChangeRecipe
+	)
 	for index, i := range recipe.Recipes() {
 		networkID := uint32(index) + 1
 		s.recipes[networkID] = i
@@ -132,9 +153,35 @@ func (s *Session) sendRecipes() {
 				Block:           i.Block(),
 				RecipeNetworkID: networkID,
 			})
+		case recipe.Potion:
+			input, inputMeta := recipeItemID(i.Input()[0])
+			reagent, reagentMeta := recipeItemID(i.Input()[1])
+			output, outputMeta := recipeItemID(i.Output()[0])
+			potionRecipes = append(potionRecipes, protocol.PotionRecipe{
+				InputPotionID:        input,
+				InputPotionMetadata:  inputMeta,
+				ReagentItemID:        reagent,
+				ReagentItemMetadata:  reagentMeta,
+				OutputPotionID:       output,
+				OutputPotionMetadata: outputMeta,
+			})
+		case recipe.PotionContainerChange:
+			input, _ := recipeItemID(i.Input()[0])
+			reagent, _ := recipeItemID(i.Input()[1])
+			output, _ := recipeItemID(i.Output()[0])
+			containerChangeRecipes = append(containerChangeRecipes, protocol.PotionContainerChangeRecipe{
+				InputItemID:   input,
+				ReagentItemID: reagent,
+				OutputItemID:  output,
+			})
 		}
 	}
-	s.writePacket(&packet.CraftingData{Recipes: recipes, ClearRecipes: true})
+	s.writePacket(&packet.CraftingData{
+		Recipes:                      recipes,
+		PotionRecipes:                potionRecipes,
+		PotionContainerChangeRecipes: containerChangeRecipes,
+		ClearRecipes:                 true,
+	})
 }
 
 // sendInv sends the inventory passed to the client with the window ID.
@@ -172,6 +219,13 @@ type smelter interface {
 	ResetExperience() int
 }
 
+// tradeCloser is an interface representing a trade.Merchant that is notified when a trader closes its trading
+// window.
+type tradeCloser interface {
+	// StopTrading is called when the trader passed closes the trading window of the merchant.
+	StopTrading(trader world.Entity)
+}
+
 // invByID attempts to return an inventory by the ID passed. If found, the inventory is returned and the bool
 // returned is true.
 func (s *Session) invByID(id int32) (*inventory.Inventory, bool) {
@@ -239,6 +293,17 @@ func (s *Session) invByID(id int32) (*inventory.Inventory, bool) {
 				return s.ui, true
 			}
 		}
+	case protocol.ContainerBrewingStandInput, protocol.ContainerBrewingStandResult, protocol.ContainerBrewingStandFuel:
+		if s.containerOpened.Load() {
+			if _, ok := s.c.World().Block(s.openedPos.Load()).(block.BrewingStand); ok {
+				return s.openedWindow.Load(), true
+			}
+		}
+	case protocol.ContainerTradeIngredientOne, protocol.ContainerTradeIngredientTwo,
+		protocol.ContainerTradeTwoIngredientOne, protocol.ContainerTradeTwoIngredientTwo:
+		if s.containerOpened.Load() && s.openedMerchant.Load() != nil {
+			return s.ui, true
+		}
 	case protocol.ContainerFurnaceIngredient, protocol.ContainerFurnaceFuel, protocol.ContainerFurnaceResult,
 		protocol.ContainerBlastFurnaceIngredient, protocol.ContainerSmokerIngredient:
 		if s.containerOpened.Load() {
@@ -380,10 +445,11 @@ func (s *Session) SendAbilities() {
 	if mode.AllowsInteraction() {
 		abilities |= protocol.AbilityDoorsAndSwitches | protocol.AbilityOpenContainers | protocol.AbilityAttackPlayers | protocol.AbilityAttackMobs
 	}
+	playerPerms, commandPerms := permissionLevels(s.c.Permissions())
 	s.writePacket(&packet.UpdateAbilities{AbilityData: protocol.AbilityData{
 		EntityUniqueID:     selfEntityRuntimeID,
-		PlayerPermissions:  packet.PermissionLevelMember,
-		CommandPermissions: packet.CommandPermissionLevelNormal,
+		PlayerPermissions:  playerPerms,
+		CommandPermissions: commandPerms,
 		Layers: []protocol.AbilityLayer{ // TODO: Support customization of fly and walk speeds.
 			{
 				Type:      protocol.AbilityLayerTypeBase,
@@ -396,6 +462,15 @@ func (s *Session) SendAbilities() {
 	}})
 }
 
+// permissionLevels returns the player and command permission levels sent to the client for the
+// permission.Set passed. Operators are shown the operator settings in the client.
+func permissionLevels(perms *permission.Set) (player, command uint8) {
+	if perms.Operator() {
+		return packet.PermissionLevelOperator, packet.CommandPermissionLevelAdmin
+	}
+	return packet.PermissionLevelMember, packet.CommandPermissionLevelNormal
+}
+
 // SendHealth sends the health and max health to the player.
 func (s *Session) SendHealth(health *entity.HealthManager) {
 	s.writePacket(&packet.UpdateAttributes{
@@ -769,6 +844,17 @@ func stacksToRecipeStacks(inputs []item.Stack) []protocol.ItemStack {
 	return items
 }
 
+// recipeItemID returns the network ID and metadata of the recipe item passed. If the item is not an item stack,
+// both are 0.
+func recipeItemID(i recipe.Item) (id, meta int32) {
+	if s, ok := i.(item.Stack); ok && !s.Empty() {
+		if rid, m, ok := world.ItemRuntimeID(s.Item()); ok {
+			return rid, int32(m)
+		}
+	}
+	return 0, 0
+}
+
 // stacksToIngredientItems converts a list of item.Stacks to recipe ingredient items used over the network.
 func stacksToIngredientItems(inputs []recipe.Item) []protocol.ItemDescriptorCount {
 	items := make([]protocol.ItemDescriptorCount, 0, len(inputs))
@@ -19,6 +19,7 @@ import (
 	"github.com/sandertv/gophertunnel/minecraft/text"
 	"github.com/stcraft/dragonfly/server/block"
 	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/entity/trade"
 	"github.com/stcraft/dragonfly/server/internal/sliceutil"
 	"github.com/stcraft/dragonfly/server/item"
 	"github.com/stcraft/dragonfly/server/item/inventory"
@@ -26,6 +27,7 @@ import (
 	"github.com/stcraft/dragonfly/server/player/chat"
 	"github.com/stcraft/dragonfly/server/player/form"
 	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/structure"
 )
 
 // Session handles incoming packets from connections and sends outgoing packets by providing a thin layer
@@ -71,6 +73,9 @@ type Session struct {
 	openedContainerID              atomic.Uint32
 	openedWindow                   atomic.Value[*inventory.Inventory]
 	openedPos                      atomic.Value[cube.Pos]
+	openedMerchant                 atomic.Value[trade.Merchant]
+	openedMerchantEntity           atomic.Value[world.Entity]
+	openedEntity                   atomic.Value[world.Entity]
 	swingingArm                    atomic.Bool
 
 	recipes map[uint32]recipe.Recipe
@@ -82,6 +87,17 @@ type Session struct {
 
 	joinMessage, quitMessage string
 
+	// structures is the structure.Storage that structure blocks edited by the session save structures to and
+	// load structures from.
+	structures structure.Storage
+
+	// movement is the movementSimulator used to validate the movement of the controllable. It is nil if
+	// movement validation is disabled.
+	movement *movementSimulator
+	// combat is the combatValidator used to validate attacks of the controllable. It is nil if combat
+	// validation is disabled.
+	combat *combatValidator
+
 	closeBackground chan struct{}
 }
 
@@ -136,16 +152,67 @@ const selfEntityRuntimeID = 1
 // must therefore always be 1.
 var errSelfRuntimeID = errors.New("invalid entity runtime ID: runtime ID for self must always be 1")
 
+// Config holds the settings used to create a Session by calling Config.New.
+type Config struct {
+	// Log is the Logger that debug messages and errors encountered while handling packets are written to.
+	Log Logger
+	// MaxChunkRadius is the maximum chunk radius that the client of the session may request.
+	MaxChunkRadius int
+	// JoinMessage and QuitMessage are the messages broadcast when the controllable of the session joins and
+	// quits. Both may have a '%v' argument that is replaced with the name of the controllable.
+	JoinMessage, QuitMessage string
+	// Structures is the structure.Storage that structure blocks edited by the session save to and load
+	// structures from.
+	Structures structure.Storage
+	// ValidateMovement specifies if the movement sent by the client is validated by simulating it
+	// server-side. Movement that diverges too much from the simulated movement is reported to the
+	// Controllable and, unless the report is cancelled, corrected by teleporting the client back.
+	ValidateMovement bool
+	// MovementThreshold is the accumulated divergence, in blocks, between the client position and the
+	// simulated position at which movement is considered invalid. If left as 0, a threshold of 1 is used.
+	// MovementThreshold has no effect if ValidateMovement is false.
+	MovementThreshold float64
+	// ValidateCombat specifies if attacks by the controllable are validated. The positions of attacked
+	// entities are rewound to the client tick of the attack, using the latency of the session, to check the
+	// reach and line of sight of attacks, and
+	// the rate of clicks and attacks is limited. Attacks violating any of these are reported to the
+	// Controllable and, unless the report is cancelled, ignored.
+	ValidateCombat bool
+	// AttackReach is the maximum distance between the eyes of the controllable and the bounding box of an
+	// entity attacked. Players in creative mode may reach 2 blocks further. If left as 0, a reach of 3 is used.
+	// AttackReach has no effect if ValidateCombat is false.
+	AttackReach float64
+	// MaxClicksPerSecond is the maximum number of times the controllable may attack or swing its arm per
+	// second. If left as 0, a maximum of 20 clicks per second is used. MaxClicksPerSecond has no effect if
+	// ValidateCombat is false.
+	MaxClicksPerSecond int
+	// AttackCooldown is the minimum time between two attacks of the controllable. If left as 0, a cooldown of
+	// a single tick is used. AttackCooldown has no effect if ValidateCombat is false.
+	AttackCooldown time.Duration
+}
+
 // New returns a new session using a controllable entity. The session will control this entity using the
 // packets that it receives.
 // New takes the connection from which to accept packets. It will start handling these packets after a call to
 // Session.Spawn().
-func New(conn Conn, maxChunkRadius int, log Logger, joinMessage, quitMessage string) *Session {
+func (conf Config) New(conn Conn) *Session {
 	r := conn.ChunkRadius()
-	if r > maxChunkRadius {
-		r = maxChunkRadius
+	if r > conf.MaxChunkRadius {
+		r = conf.MaxChunkRadius
 		_ = conn.WritePacket(&packet.ChunkRadiusUpdated{ChunkRadius: int32(r)})
 	}
+	if conf.MovementThreshold <= 0 {
+		conf.MovementThreshold = 1
+	}
+	if conf.AttackReach <= 0 {
+		conf.AttackReach = 3
+	}
+	if conf.MaxClicksPerSecond <= 0 {
+		conf.MaxClicksPerSecond = 20
+	}
+	if conf.AttackCooldown <= 0 {
+		conf.AttackCooldown = time.Second / 20
+	}
 
 	s := &Session{}
 	*s = Session{
@@ -158,15 +225,22 @@ func New(conn Conn, maxChunkRadius int, log Logger, joinMessage, quitMessage str
 		hiddenEntities:         map[world.Entity]struct{}{},
 		blobs:                  map[uint64][]byte{},
 		chunkRadius:            int32(r),
-		maxChunkRadius:         int32(maxChunkRadius),
+		maxChunkRadius:         int32(conf.MaxChunkRadius),
 		conn:                   conn,
-		log:                    log,
+		log:                    conf.Log,
 		currentEntityRuntimeID: 1,
 		heldSlot:               atomic.NewUint32(0),
-		joinMessage:            joinMessage,
-		quitMessage:            quitMessage,
+		joinMessage:            conf.JoinMessage,
+		quitMessage:            conf.QuitMessage,
+		structures:             conf.Structures,
 		openedWindow:           *atomic.NewValue(inventory.New(1, nil)),
 	}
+	if conf.ValidateMovement {
+		s.movement = &movementSimulator{threshold: conf.MovementThreshold}
+	}
+	if conf.ValidateCombat {
+		s.combat = newCombatValidator(conf.AttackReach, conf.MaxClicksPerSecond, conf.AttackCooldown)
+	}
 
 	s.registerHandlers()
 	return s
@@ -183,6 +257,9 @@ func (s *Session) Spawn(c Controllable, pos mgl64.Vec3, w *world.World, gm world
 
 	s.chunkLoader = world.NewLoader(int(s.chunkRadius), w, s)
 	s.chunkLoader.Move(pos)
+	if s.movement != nil {
+		s.movement.Reset(pos)
+	}
 	s.writePacket(&packet.NetworkChunkPublisherUpdate{
 		Position: protocol.BlockPos{int32(pos[0]), int32(pos[1]), int32(pos[2])},
 		Radius:   uint32(s.chunkRadius) << 4,
@@ -331,6 +408,7 @@ func (s *Session) background() {
 		t                 = time.NewTicker(time.Second / 20)
 		r                 = s.sendAvailableCommands()
 		enums, enumValues = s.enums()
+		operator          = s.c.Permissions().Operator()
 		ok                bool
 		i                 int
 	)
@@ -347,6 +425,12 @@ func (s *Session) background() {
 				s.resendEnums(enums, enumValues)
 			}
 			if i%100 == 0 {
+				// The permission level of the client must match the operator status of the Controllable, so
+				// that the client shows the operator settings and commands it may run.
+				if op := s.c.Permissions().Operator(); op != operator {
+					operator = op
+					s.SendAbilities()
+				}
 				// Try to resend commands only every 5 seconds.
 				if r, ok = s.resendCommands(r); ok {
 					enums, enumValues = s.enums()
@@ -465,6 +549,7 @@ func (s *Session) registerHandlers() {
 		packet.IDRequestChunkRadius:        &RequestChunkRadiusHandler{},
 		packet.IDRespawn:                   &RespawnHandler{},
 		packet.IDSetPlayerInventoryOptions: nil,
+		packet.IDStructureBlockUpdate:      &StructureBlockUpdateHandler{},
 		packet.IDSubChunkRequest:           &SubChunkRequestHandler{},
 		packet.IDText:                      &TextHandler{},
 		packet.IDTickSync:                  nil,
@@ -2,7 +2,9 @@ package session
 
 import (
 	"image/color"
+	"math"
 	"math/rand"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,11 +13,13 @@ import (
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 	"github.com/stcraft/dragonfly/server/block"
 	"github.com/stcraft/dragonfly/server/block/cube"
 	"github.com/stcraft/dragonfly/server/entity"
+	"github.com/stcraft/dragonfly/server/entity/trade"
 	"github.com/stcraft/dragonfly/server/internal/nbtconv"
 	"github.com/stcraft/dragonfly/server/item"
 	"github.com/stcraft/dragonfly/server/item/inventory"
@@ -70,6 +74,9 @@ func (s *Session) ViewEntity(e world.Entity) {
 		s.entities[runtimeID] = e
 	}
 	s.entityMutex.Unlock()
+	if s.combat != nil {
+		s.combat.Record(e, e.Position())
+	}
 
 	yaw, pitch := e.Rotation().Elem()
 	metadata := s.parseEntityMetadata(e)
@@ -106,6 +113,7 @@ func (s *Session) ViewEntity(e world.Entity) {
 			UUID:            v.UUID(),
 			Username:        v.Name(),
 			Yaw:             float32(yaw),
+			EntityLinks:     s.entityLinks(e, runtimeID),
 			AbilityData: protocol.AbilityData{
 				EntityUniqueID: int64(runtimeID),
 				Layers: []protocol.AbilityLayer{{
@@ -157,9 +165,43 @@ func (s *Session) ViewEntity(e world.Entity) {
 		Pitch:           float32(pitch),
 		Yaw:             float32(yaw),
 		HeadYaw:         float32(yaw),
+		EntityLinks:     s.entityLinks(e, runtimeID),
 	})
 }
 
+// entityLinks returns the entity links currently active on the entity passed with the runtime ID passed: Links to
+// the riders of the entity if it is an entity.Rideable and a link to the vehicle that it is riding, if any. Links to
+// entities that are not yet viewed by the Session are omitted and sent when those entities are viewed.
+func (s *Session) entityLinks(e world.Entity, runtimeID uint64) []protocol.EntityLink {
+	var links []protocol.EntityLink
+	if r, ok := e.(entity.Rideable); ok {
+		for i, rider := range r.Riders() {
+			if id := s.entityRuntimeID(rider); id != 0 {
+				links = append(links, entityLink(runtimeID, id, i == 0))
+			}
+		}
+	}
+	if r, ok := e.(entity.Rider); ok {
+		if v, ok := r.Riding(); ok {
+			if id := s.entityRuntimeID(v); id != 0 {
+				riders := v.Riders()
+				links = append(links, entityLink(id, runtimeID, len(riders) > 0 && riders[0] == e))
+			}
+		}
+	}
+	return links
+}
+
+// entityLink returns an entity link between the ridden entity and the rider with the runtime IDs passed. If driver
+// is true, the rider controls the ridden entity.
+func entityLink(ridden, rider uint64, driver bool) protocol.EntityLink {
+	linkType := byte(protocol.EntityLinkPassenger)
+	if driver {
+		linkType = protocol.EntityLinkRider
+	}
+	return protocol.EntityLink{RiddenEntityUniqueID: int64(ridden), RiderEntityUniqueID: int64(rider), Type: linkType}
+}
+
 // ViewEntityGameMode ...
 func (s *Session) ViewEntityGameMode(e world.Entity) {
 	if s.entityHidden(e) {
@@ -188,6 +230,13 @@ func (s *Session) HideEntity(e world.Entity) {
 		delete(s.entities, id)
 	}
 	s.entityMutex.Unlock()
+	if s.combat != nil {
+		s.combat.Forget(e)
+	}
+	if s.containerOpened.Load() && s.openedEntity.Load() == e {
+		// The entity whose container was opened is no longer visible, so its container can no longer be used.
+		s.closeCurrentContainer()
+	}
 	if !ok {
 		// The entity was already removed some other way. We don't need to send a packet.
 		return
@@ -202,6 +251,10 @@ func (s *Session) ViewEntityMovement(e world.Entity, pos mgl64.Vec3, rot cube.Ro
 		return
 	}
 
+	if s.combat != nil {
+		s.combat.Record(e, pos)
+	}
+
 	flags := byte(0)
 	if onGround {
 		flags |= packet.MoveFlagOnGround
@@ -219,6 +272,9 @@ func (s *Session) ViewEntityVelocity(e world.Entity, velocity mgl64.Vec3) {
 	if s.entityHidden(e) {
 		return
 	}
+	if s.movement != nil && e == s.c {
+		s.movement.SetVelocity(velocity)
+	}
 	s.writePacket(&packet.SetActorMotion{
 		EntityRuntimeID: s.entityRuntimeID(e),
 		Velocity:        vec64To32(velocity),
@@ -249,6 +305,11 @@ func (s *Session) ViewEntityTeleport(e world.Entity, position mgl64.Vec3) {
 	if id == selfEntityRuntimeID {
 		s.chunkLoader.Move(position)
 		s.teleportPos.Store(&position)
+		if s.movement != nil {
+			s.movement.Reset(position)
+		}
+	} else if s.combat != nil {
+		s.combat.Record(e, position)
 	}
 
 	s.writePacket(&packet.SetActorMotion{EntityRuntimeID: id})
@@ -527,6 +588,18 @@ func (s *Session) playSound(pos mgl64.Vec3, t world.Sound, disableRelative bool)
 			Position:  vec64To32(pos),
 		})
 		return
+	case sound.ClickFail:
+		s.writePacket(&packet.LevelEvent{
+			EventType: packet.LevelEventSoundClickFail,
+			Position:  vec64To32(pos),
+		})
+		return
+	case sound.Launch:
+		s.writePacket(&packet.LevelEvent{
+			EventType: packet.LevelEventSoundLaunch,
+			Position:  vec64To32(pos),
+		})
+		return
 	case sound.Pop:
 		s.writePacket(&packet.LevelEvent{
 			EventType: packet.LevelEventSoundInfinityArrowPickup,
@@ -585,6 +658,8 @@ func (s *Session) playSound(pos mgl64.Vec3, t world.Sound, disableRelative bool)
 		pk.SoundType = packet.SoundEventBlastFurnaceUse
 	case sound.SmokerCrackle:
 		pk.SoundType = packet.SoundEventSmokerUse
+	case sound.PotionBrewed:
+		pk.SoundType = packet.SoundEventPotionBrewed
 	case sound.UseSpyglass:
 		pk.SoundType = packet.SoundEventUseSpyglass
 	case sound.StopUsingSpyglass:
@@ -637,6 +712,10 @@ func (s *Session) playSound(pos mgl64.Vec3, t world.Sound, disableRelative bool)
 		pk.SoundType, pk.ExtraData = packet.SoundEventFenceGateOpen, int32(world.BlockRuntimeID(so.Block))
 	case sound.FenceGateClose:
 		pk.SoundType, pk.ExtraData = packet.SoundEventFenceGateClose, int32(world.BlockRuntimeID(so.Block))
+	case sound.PowerOn:
+		pk.SoundType, pk.ExtraData = packet.SoundEventPowerOn, int32(world.BlockRuntimeID(so.Block))
+	case sound.PowerOff:
+		pk.SoundType, pk.ExtraData = packet.SoundEventPowerOff, int32(world.BlockRuntimeID(so.Block))
 	case sound.Deny:
 		pk.SoundType = packet.SoundEventDeny
 	case sound.BlockPlace:
@@ -704,6 +783,34 @@ func (s *Session) playSound(pos mgl64.Vec3, t world.Sound, disableRelative bool)
 		pk.SoundType = packet.SoundEventBowHit
 	case sound.ItemThrow:
 		pk.SoundType, pk.EntityType = packet.SoundEventThrow, "minecraft:player"
+	case sound.ShieldBlock:
+		pk.SoundType = packet.SoundEventShieldBlock
+	case sound.TridentThrow:
+		pk.SoundType = packet.SoundEventTridentThrow
+	case sound.TridentHit:
+		pk.SoundType = packet.SoundEventTridentHit
+	case sound.TridentHitGround:
+		pk.SoundType = packet.SoundEventTridentHitGround
+	case sound.TridentReturn:
+		pk.SoundType = packet.SoundEventTridentReturn
+	case sound.TridentRiptide:
+		switch so.Level {
+		case 1:
+			pk.SoundType = packet.SoundEventTridentRiptide1
+		case 2:
+			pk.SoundType = packet.SoundEventTridentRiptide2
+		default:
+			pk.SoundType = packet.SoundEventTridentRiptide3
+		}
+	case sound.TridentThunder:
+		pk.SoundType = packet.SoundEventTridentThunder
+	case sound.CrossbowLoad:
+		pk.SoundType = packet.SoundEventCrossbowLoadingEnd
+		if so.QuickCharge {
+			pk.SoundType = packet.SoundEventCrossbowQuickChargeEnd
+		}
+	case sound.CrossbowShoot:
+		pk.SoundType = packet.SoundEventCrossbowShoot
 	case sound.LevelUp:
 		pk.SoundType, pk.ExtraData = packet.SoundEventLevelUp, 0x10000000
 	case sound.Experience:
@@ -765,6 +872,12 @@ func (s *Session) playSound(pos mgl64.Vec3, t world.Sound, disableRelative bool)
 		pk.SoundType = packet.SoundEventComposterReady
 	case sound.LecternBookPlace:
 		pk.SoundType = packet.SoundEventLecternBookPlace
+	case sound.EndPortalCreated:
+		pk.SoundType = packet.SoundEventEndPortalCreated
+	case sound.PistonExtend:
+		pk.SoundType = packet.SoundEventPistonOut
+	case sound.PistonRetract:
+		pk.SoundType = packet.SoundEventPistonIn
 	}
 	s.writePacket(pk)
 }
@@ -819,6 +932,33 @@ func (s *Session) ViewFurnaceUpdate(prevCookTime, cookTime, prevRemainingFuelTim
 	}
 }
 
+// ViewBrewingUpdate updates a brewing stand for the associated session based on previous times and fuel.
+func (s *Session) ViewBrewingUpdate(prevBrewTime, brewTime time.Duration, prevFuelAmount, fuelAmount, prevFuelTotal, fuelTotal int32) {
+	if prevBrewTime != brewTime {
+		s.writePacket(&packet.ContainerSetData{
+			WindowID: byte(s.openedWindowID.Load()),
+			Key:      packet.ContainerDataBrewingStandBrewTime,
+			Value:    int32(brewTime.Milliseconds() / 50),
+		})
+	}
+
+	if prevFuelAmount != fuelAmount {
+		s.writePacket(&packet.ContainerSetData{
+			WindowID: byte(s.openedWindowID.Load()),
+			Key:      packet.ContainerDataBrewingStandFuelAmount,
+			Value:    fuelAmount,
+		})
+	}
+
+	if prevFuelTotal != fuelTotal {
+		s.writePacket(&packet.ContainerSetData{
+			WindowID: byte(s.openedWindowID.Load()),
+			Key:      packet.ContainerDataBrewingStandFuelTotal,
+			Value:    fuelTotal,
+		})
+	}
+}
+
 // ViewBlockUpdate ...
 func (s *Session) ViewBlockUpdate(pos cube.Pos, b world.Block, layer int) {
 	blockPos := protocol.BlockPos{int32(pos[0]), int32(pos[1]), int32(pos[2])}
@@ -882,11 +1022,36 @@ func (s *Session) ViewEntityAction(e world.Entity, a world.EntityAction) {
 			EventType:       packet.ActorEventShake,
 			EventData:       int32(act.Duration.Milliseconds() / 50),
 		})
+	case entity.EatGrassAction:
+		s.writePacket(&packet.ActorEvent{
+			EntityRuntimeID: s.entityRuntimeID(e),
+			EventType:       packet.ActorEventEatGrass,
+		})
 	case entity.FireworkExplosionAction:
 		s.writePacket(&packet.ActorEvent{
 			EntityRuntimeID: s.entityRuntimeID(e),
 			EventType:       packet.ActorEventFireworksExplode,
 		})
+	case entity.FishingHookBiteAction:
+		s.writePacket(&packet.ActorEvent{
+			EntityRuntimeID: s.entityRuntimeID(e),
+			EventType:       packet.ActorEventFishhookHookTime,
+		})
+	case entity.WakeUpAction:
+		s.writePacket(&packet.Animate{
+			EntityRuntimeID: s.entityRuntimeID(e),
+			ActionType:      packet.AnimateActionStopSleep,
+		})
+	case entity.MountAction:
+		link := entityLink(s.entityRuntimeID(act.Vehicle), s.entityRuntimeID(e), act.Driver)
+		link.RiderInitiated = true
+		s.writePacket(&packet.SetActorLink{EntityLink: link})
+	case entity.DismountAction:
+		s.writePacket(&packet.SetActorLink{EntityLink: protocol.EntityLink{
+			RiddenEntityUniqueID: int64(s.entityRuntimeID(act.Vehicle)),
+			RiderEntityUniqueID:  int64(s.entityRuntimeID(e)),
+			Type:                 protocol.EntityLinkRemove,
+		}})
 	case entity.EatAction:
 		if user, ok := e.(item.User); ok {
 			held, _ := user.HeldItems()
@@ -956,6 +1121,8 @@ func (s *Session) OpenFakeContainer(pos cube.Pos, b block.Container) uint32 {
 		containerType = protocol.ContainerTypeHopper
 	case block.Dispenser:
 		containerType = protocol.ContainerTypeDispenser
+	case block.Dropper:
+		containerType = protocol.ContainerTypeDropper
 	}
 
 	s.writePacket(&packet.ContainerOpen{
@@ -1019,6 +1186,8 @@ func (s *Session) OpenBlockContainer(pos cube.Pos) {
 		containerType = protocol.ContainerTypeStonecutter
 	case block.SmithingTable:
 		containerType = protocol.ContainerTypeSmithingTable
+	case block.StructureBlock:
+		containerType = protocol.ContainerTypeStructureEditor
 	case block.EnderChest:
 		b.AddViewer(w, pos)
 
@@ -1037,6 +1206,120 @@ func (s *Session) OpenBlockContainer(pos cube.Pos) {
 	})
 }
 
+// OpenEntityContainer opens the container carried by an entity, such as the inventory of a chest minecart. If
+// the entity carries no container, OpenEntityContainer does nothing.
+func (s *Session) OpenEntityContainer(e world.Entity) {
+	if s == Nop {
+		return
+	}
+	containerType, inv, ok := entityContainer(e)
+	if !ok || (s.containerOpened.Load() && s.openedEntity.Load() == e) {
+		return
+	}
+	s.closeCurrentContainer()
+
+	nextID := s.nextWindowID()
+	s.containerOpened.Store(true)
+	s.openedWindow.Store(inv)
+	s.openedContainerID.Store(uint32(containerType))
+	s.openedEntity.Store(e)
+
+	pos := cube.PosFromVec3(e.Position())
+	s.writePacket(&packet.ContainerOpen{
+		WindowID:                nextID,
+		ContainerType:           containerType,
+		ContainerPosition:       protocol.BlockPos{int32(pos[0]), int32(pos[1]), int32(pos[2])},
+		ContainerEntityUniqueID: int64(s.entityRuntimeID(e)),
+	})
+	s.sendInv(inv, uint32(nextID))
+}
+
+// entityContainer returns the container type and inventory of the container carried by an entity. False is
+// returned if the entity carries no container.
+func entityContainer(e world.Entity) (byte, *inventory.Inventory, bool) {
+	m, ok := e.(*entity.Minecart)
+	if !ok || m.Inventory() == nil {
+		return 0, nil, false
+	}
+	if m.Kind() == item.HopperMinecart() {
+		return protocol.ContainerTypeCartHopper, m.Inventory(), true
+	}
+	return protocol.ContainerTypeCartChest, m.Inventory(), true
+}
+
+// OpenTrade opens the trading window of the trade.Merchant passed. The entity passed is shown in the trading
+// window. If nil, the entity controlled by the Session is shown instead.
+func (s *Session) OpenTrade(m trade.Merchant, e world.Entity) {
+	if s == Nop {
+		return
+	}
+	s.closeCurrentContainer()
+	if e == nil {
+		e = s.c
+	}
+
+	s.nextWindowID()
+	s.containerOpened.Store(true)
+	s.openedWindow.Store(inventory.New(1, nil))
+	s.openedContainerID.Store(protocol.ContainerTypeTrade)
+	s.openedMerchant.Store(m)
+	s.openedMerchantEntity.Store(e)
+	s.sendTrade()
+}
+
+// sendTrade sends the offers of the trade.Merchant currently opened to the client. The client opens the trading
+// window if it was not yet opened.
+func (s *Session) sendTrade() {
+	m, e := s.openedMerchant.Load(), s.openedMerchantEntity.Load()
+	if m == nil {
+		return
+	}
+	offers := m.Offers()
+	recipes := make([]any, 0, len(offers))
+	for i, o := range offers {
+		price := o.Price()
+		maxUses := int32(o.MaxUses)
+		if maxUses == 0 {
+			maxUses = math.MaxInt32
+		}
+		// Prices are already adjusted to the demand of the offer, so the demand is not sent to the client.
+		offer := map[string]any{
+			"buyA":      nbtconv.WriteItem(price, false),
+			"buyCountA": int32(price.Count()),
+			"sell":      nbtconv.WriteItem(o.Output, false),
+			"tier":      int32(o.Tier),
+			"maxUses":   maxUses,
+			"uses":      int32(o.Uses),
+			"traderExp": int32(o.Experience),
+			"rewardExp": boolByte(o.RewardExperience),
+			"netId":     int32(tradeNetworkIDOffset + i),
+		}
+		if !o.SecondInput.Empty() {
+			offer["buyB"] = nbtconv.WriteItem(o.SecondInput, false)
+			offer["buyCountB"] = int32(o.SecondInput.Count())
+		}
+		recipes = append(recipes, offer)
+	}
+	tiers := make([]any, 0, trade.MaxTier+1)
+	for tier := 0; tier <= trade.MaxTier; tier++ {
+		tiers = append(tiers, map[string]any{strconv.Itoa(tier): int32(trade.TierExperience(tier))})
+	}
+	data, err := nbt.Marshal(map[string]any{"Recipes": recipes, "TierExpRequirements": tiers})
+	if err != nil {
+		panic("should never happen")
+	}
+	s.writePacket(&packet.UpdateTrade{
+		WindowID:         byte(s.openedWindowID.Load()),
+		WindowType:       protocol.ContainerTypeTrade,
+		TradeTier:        int32(m.Tier()),
+		VillagerUniqueID: int64(s.entityRuntimeID(e)),
+		EntityUniqueID:   selfEntityRuntimeID,
+		DisplayName:      m.Name(),
+		NewTradeUI:       true,
+		SerialisedOffers: data,
+	})
+}
+
 // openNormalContainer opens a normal container that can hold items in it server-side.
 func (s *Session) openNormalContainer(b block.Container, pos cube.Pos) {
 	b.AddViewer(s, s.c.World(), pos)
@@ -1054,10 +1337,14 @@ func (s *Session) openNormalContainer(b block.Container, pos cube.Pos) {
 		containerType = protocol.ContainerTypeBlastFurnace
 	case block.Smoker:
 		containerType = protocol.ContainerTypeSmoker
+	case block.BrewingStand:
+		containerType = protocol.ContainerTypeBrewingStand
 	case block.Hopper:
 		containerType = protocol.ContainerTypeHopper
 	case block.Dispenser:
 		containerType = protocol.ContainerTypeDispenser
+	case block.Dropper:
+		containerType = protocol.ContainerTypeDropper
 	}
 
 	s.writePacket(&packet.ContainerOpen{
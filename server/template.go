@@ -0,0 +1,71 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/mcdb"
+)
+
+// Template is a world that is opened once, from which any number of instanced
+// worlds may be loaded using Server.LoadInstance. Instanced worlds read the
+// chunks of the Template, but keep all changes made to them in memory, so that
+// they are discarded once the instance is unloaded. A Template is not loaded
+// as a world itself and is never written to.
+type Template struct {
+	spec WorldSpec
+	prov world.Provider
+}
+
+// LoadTemplate opens the world specified by the WorldSpec passed as a
+// Template. The Dimension, Generator and ReadOnly fields of the WorldSpec are
+// used for all instances loaded from the Template. The Generator is used to
+// generate chunks that are not present in the Template.
+func (srv *Server) LoadTemplate(spec WorldSpec) (*Template, error) {
+	if spec.Dimension == nil {
+		spec.Dimension = world.Overworld
+	}
+	prov := spec.Provider
+	if prov == nil {
+		dir, err := srv.worldDir(spec)
+		if err != nil {
+			return nil, fmt.Errorf("load template %v: %w", spec.Name, err)
+		}
+		if prov, err = srv.conf.WorldProvider(dir); err != nil {
+			return nil, fmt.Errorf("load template %v: %w", spec.Name, err)
+		}
+	}
+	if spec.Generator == nil {
+		spec.Generator = srv.conf.Generator(spec.Dimension, prov.Settings().Seed)
+	}
+	return &Template{spec: spec, prov: prov}, nil
+}
+
+// LoadInstance loads a new world with the name passed as an instance of the
+// Template passed. The world may be used like any other world of the server.
+// Changes made to the world are kept in memory and are discarded when the
+// world is unloaded using Server.UnloadWorld.
+func (srv *Server) LoadInstance(t *Template, name string) (*world.World, error) {
+	overlay, err := mcdb.Config{Log: srv.conf.Log, Entities: srv.conf.Entities}.OpenMemory(name)
+	if err != nil {
+		return nil, fmt.Errorf("load instance %v: %w", name, err)
+	}
+	w, err := srv.LoadWorld(WorldSpec{
+		Name:      name,
+		Dimension: t.spec.Dimension,
+		Generator: t.spec.Generator,
+		ReadOnly:  t.spec.ReadOnly,
+		Provider:  world.NewInstanceProvider(t.prov, overlay),
+	})
+	if err != nil {
+		_ = overlay.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Close closes the Template. All instances loaded from the Template must be
+// unloaded before the Template is closed.
+func (t *Template) Close() error {
+	return t.prov.Close()
+}
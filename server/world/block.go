@@ -224,6 +224,14 @@ type NBTer interface {
 	EncodeNBT() map[string]any
 }
 
+// ChangeTracker represents a block with a block entity holding data that may change without the block being
+// set to the World again, such as a chest of which the inventory changes. The World uses it to find out if a
+// chunk must be saved.
+type ChangeTracker interface {
+	// Changed returns true if the data of the block changed since the last call to Changed.
+	Changed() bool
+}
+
 // LiquidDisplacer represents a block that is able to displace a liquid to a different world layer, without
 // fully removing the liquid.
 type LiquidDisplacer interface {
@@ -43,3 +43,11 @@ func init() {
 		legacyMappings[entry.Legacy] = entry.Updated
 	}
 }
+
+// UpgradeLegacyBlock upgrades a block state from versions prior to 1.13, which is identified by a name and a
+// meta value, to the name, properties and version of the block state it was converted to. False is returned
+// if no mapping exists for the legacy block state.
+func UpgradeLegacyBlock(name string, meta int16) (string, map[string]any, int32, bool) {
+	entry, ok := upgradeLegacyEntry(name, meta)
+	return entry.Name, entry.State, entry.Version, ok
+}
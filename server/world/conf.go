@@ -36,6 +36,14 @@ type Config struct {
 	// Entities is an EntityRegistry with all entity types registered that may
 	// be added to the World.
 	Entities EntityRegistry
+	// Spawning holds settings for the natural spawning and despawning of
+	// entities in the World. By default, no entities spawn naturally, as the
+	// World has no spawn entries for its biomes.
+	Spawning SpawnConfig
+	// PortalDestination is a function that returns the World that portals in
+	// this World lead to for a specific Dimension. If set to nil, or if the
+	// function returns nil, portals lead to the World itself.
+	PortalDestination func(dim Dimension) *World
 }
 
 // Logger is a logger implementation that may be passed to the Log field of Config. World will send errors and debug
@@ -69,6 +77,7 @@ func (conf Config) New() *World {
 	s := conf.Provider.Settings()
 	w := &World{
 		scheduledUpdates: make(map[cube.Pos]int64),
+		redstoneToggles:  make(map[cube.Pos][]int64),
 		entities:         make(map[Entity]ChunkPos),
 		viewers:          make(map[*Loader]Viewer),
 		chunks:           make(map[ChunkPos]*Column),
@@ -0,0 +1,530 @@
+package world
+
+import (
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world/chunk"
+)
+
+// EditOpts holds options that may be passed to region edits, such as World.Fill, to modify the way the edit
+// is applied. The zero value applies an edit synchronously and records its history.
+type EditOpts struct {
+	// ChunksPerTick is the maximum amount of chunks changed by the edit every tick. If set to 0, the edit is
+	// applied completely before the method creating it returns. Otherwise, the edit is spread over as many
+	// ticks as needed, so that large edits do not freeze the World.
+	ChunksPerTick int
+	// Progress is called every time all changes in a chunk have been applied, with the amount of chunks done
+	// and the total amount of chunks changed by the edit. It may call methods on the Edit, such as
+	// Edit.Progress.
+	Progress func(done, total int)
+	// DisableHistory disables recording the blocks replaced by the edit. Edits without history use less
+	// memory, but cannot be undone.
+	DisableHistory bool
+}
+
+var (
+	// ErrNoHistory is returned by Edit.Undo if the blocks replaced by an Edit were not recorded.
+	ErrNoHistory = errors.New("edit has no history")
+	// ErrEmptyHistory is returned by EditHistory.Undo and EditHistory.Redo if there is no Edit to undo or
+	// redo.
+	ErrEmptyHistory = errors.New("edit history is empty")
+)
+
+// Edit is a change of many blocks in a World, such as one created by World.Fill or World.Paste. Unlike
+// World.SetBlock, an Edit groups its changes per chunk: It writes directly into the chunk, recalculates its
+// light once and sends the changed chunk to viewers once, without sending updates for individual blocks or
+// triggering block updates.
+type Edit struct {
+	w    *World
+	opts EditOpts
+	op   editOp
+
+	mu      sync.Mutex
+	chunks  []ChunkPos
+	n       int
+	history map[ChunkPos][]blockChange
+
+	done chan struct{}
+}
+
+// editOp is an operation performed by an Edit on every chunk it changes.
+type editOp interface {
+	// chunks returns the positions of all chunks changed by the editOp.
+	chunks() []ChunkPos
+	// apply calls set for every block changed by the editOp in the chunk at the position passed. set is
+	// passed the runtime IDs of the new blocks on both layers and the Block to store as block entity, if any.
+	apply(w *World, pos ChunkPos, c *Column, set func(pos cube.Pos, rid [2]uint32, nbt Block))
+}
+
+// blockChange is a change of a single block, recorded in the history of an Edit.
+type blockChange struct {
+	pos cube.Pos
+	rid [2]uint32
+	nbt Block
+}
+
+// newEdit creates an Edit in the World for the editOp passed and starts applying it.
+func (w *World) newEdit(op editOp, opts *EditOpts) *Edit {
+	if opts == nil {
+		opts = &EditOpts{}
+	}
+	e := &Edit{w: w, opts: *opts, op: op, done: make(chan struct{})}
+	if w == nil {
+		close(e.done)
+		return e
+	}
+	e.chunks = op.chunks()
+	if !e.opts.DisableHistory {
+		e.history = make(map[ChunkPos][]blockChange, len(e.chunks))
+	}
+	if e.opts.ChunksPerTick <= 0 {
+		for e.step(len(e.chunks)) {
+		}
+		return e
+	}
+	w.editMu.Lock()
+	w.edits = append(w.edits, e)
+	w.editMu.Unlock()
+	return e
+}
+
+// Done returns a channel that is closed once all changes of the Edit have been applied.
+func (e *Edit) Done() <-chan struct{} {
+	return e.done
+}
+
+// Wait blocks until all changes of the Edit have been applied.
+func (e *Edit) Wait() {
+	<-e.done
+}
+
+// Progress returns the amount of chunks changed so far and the total amount of chunks changed by the Edit.
+func (e *Edit) Progress() (done, total int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.n, len(e.chunks)
+}
+
+// Undo creates a new Edit that restores all blocks changed by the Edit to the blocks they were before the
+// Edit was applied. The Edit must be done before it is undone. ErrNoHistory is returned if the Edit was
+// created with EditOpts.DisableHistory set to true. The Edit returned may itself be undone to redo the Edit.
+func (e *Edit) Undo(opts *EditOpts) (*Edit, error) {
+	select {
+	case <-e.done:
+	default:
+		return nil, errors.New("undo edit: edit is not done")
+	}
+	if e.history == nil {
+		return nil, ErrNoHistory
+	}
+	return e.w.newEdit(historyOp(e.history), opts), nil
+}
+
+// step applies the changes of at most n chunks of the Edit. It returns false if all changes have been
+// applied. EditOpts.Progress is called without e.mu held, so that it may call methods on the Edit.
+func (e *Edit) step(n int) bool {
+	for i := 0; i < n; i++ {
+		e.mu.Lock()
+		if e.n >= len(e.chunks) {
+			e.mu.Unlock()
+			break
+		}
+		e.applyChunk(e.chunks[e.n])
+		e.n++
+		done, total := e.n, len(e.chunks)
+		e.mu.Unlock()
+
+		if e.opts.Progress != nil {
+			e.opts.Progress(done, total)
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.n < len(e.chunks) {
+		return true
+	}
+	select {
+	case <-e.done:
+	default:
+		close(e.done)
+	}
+	return false
+}
+
+// applyChunk applies all changes of the Edit in the chunk at the position passed, recalculates the light of
+// the chunk and sends it to all of its viewers.
+func (e *Edit) applyChunk(pos ChunkPos) {
+	w := e.w
+	c := w.chunk(pos)
+
+	var history []blockChange
+	changed := false
+	e.op.apply(w, pos, c, func(p cube.Pos, rid [2]uint32, nbt Block) {
+		x, y, z := uint8(p[0]), int16(p[1]), uint8(p[2])
+		if e.history != nil {
+			history = append(history, blockChange{pos: p, rid: [2]uint32{c.Block(x, y, z, 0), c.Block(x, y, z, 1)}, nbt: c.BlockEntities[p]})
+		}
+		c.SetBlock(x, y, z, 0, rid[0])
+		c.SetBlock(x, y, z, 1, rid[1])
+		if nbt != nil {
+			c.BlockEntities[p] = nbt
+		} else {
+			delete(c.BlockEntities, p)
+		}
+		changed = true
+	})
+	if !changed {
+		c.Unlock()
+		return
+	}
+	if e.history != nil {
+		e.history[pos] = history
+	}
+	c.modified = true
+	chunk.LightArea([]*chunk.Chunk{c.Chunk}, int(pos[0]), int(pos[1])).Fill()
+
+	// Instead of sending an update for every block changed, we resend the complete chunk to all viewers.
+	for _, viewer := range c.viewers {
+		viewer.ViewChunk(pos, c.Chunk, c.BlockEntities)
+	}
+	c.Unlock()
+
+	w.chunkMu.Lock()
+	w.calculateLight(pos)
+	w.chunkMu.Unlock()
+}
+
+// finishEdits applies all remaining changes of Edits spread over multiple ticks that are not yet done. It is
+// called when the World is closed, so that the Edits are completed before the World is saved.
+func (w *World) finishEdits() {
+	w.editMu.Lock()
+	edits := w.edits
+	w.edits = nil
+	w.editMu.Unlock()
+
+	for _, e := range edits {
+		for e.step(len(e.chunks)) {
+		}
+	}
+}
+
+// tickEdits applies the changes of all Edits spread over multiple ticks that are not yet done.
+func (t ticker) tickEdits() {
+	t.w.editMu.Lock()
+	edits := slices.Clone(t.w.edits)
+	t.w.editMu.Unlock()
+	if len(edits) == 0 {
+		return
+	}
+
+	pending := edits[:0]
+	for _, e := range edits {
+		if e.step(e.opts.ChunksPerTick) {
+			pending = append(pending, e)
+		}
+	}
+	t.w.editMu.Lock()
+	// Edits may have been added while applying the changes of the others, so we only replace the ones that
+	// were present before.
+	t.w.edits = append(pending, t.w.edits[len(edits):]...)
+	t.w.editMu.Unlock()
+}
+
+// Fill sets all blocks in the region between the corners a and b, both inclusive, to the Block passed. Passing
+// nil sets all blocks in the region to air. Liquids in the region are removed, unless the Block passed is a
+// Liquid itself.
+func (w *World) Fill(a, b cube.Pos, bl Block, opts *EditOpts) *Edit {
+	if bl == nil {
+		bl = air()
+	}
+	rid := [2]uint32{BlockRuntimeID(bl), airRID}
+	var nbt Block
+	if nbtBlocks[rid[0]] {
+		nbt = bl
+	}
+	return w.newEdit(regionOp{min: minPos(a, b), max: maxPos(a, b), f: func(cube.Pos, [2]uint32) ([2]uint32, Block, bool) {
+		return rid, nbt, true
+	}}, opts)
+}
+
+// Replace replaces all blocks in the region between the corners a and b, both inclusive, for which the
+// function match returns true with the Block passed. Passing nil replaces the matching blocks with air.
+// Liquids at the positions of replaced blocks are removed, unless the Block passed is a Liquid itself.
+func (w *World) Replace(a, b cube.Pos, match func(b Block) bool, bl Block, opts *EditOpts) *Edit {
+	if bl == nil {
+		bl = air()
+	}
+	rid := [2]uint32{BlockRuntimeID(bl), airRID}
+	var nbt Block
+	if nbtBlocks[rid[0]] {
+		nbt = bl
+	}
+	// Matching blocks by their runtime ID allows us to call match only once for every block state in the
+	// region.
+	matches := map[uint32]bool{}
+	return w.newEdit(regionOp{min: minPos(a, b), max: maxPos(a, b), f: func(_ cube.Pos, current [2]uint32) ([2]uint32, Block, bool) {
+		m, ok := matches[current[0]]
+		if !ok {
+			b, _ := BlockByRuntimeID(current[0])
+			m = match(b)
+			matches[current[0]] = m
+		}
+		return rid, nbt, m
+	}}, opts)
+}
+
+// Paste builds the Structure passed, such as a Clipboard returned by World.Copy, with its origin at the
+// position passed. Like World.BuildStructure, positions for which the Structure returns a nil Block are left
+// unchanged.
+func (w *World) Paste(pos cube.Pos, s Structure, opts *EditOpts) *Edit {
+	dim := s.Dimensions()
+	if dim[0] <= 0 || dim[1] <= 0 || dim[2] <= 0 {
+		return w.newEdit(historyOp(nil), opts)
+	}
+	var (
+		c      *Column
+		cPos   ChunkPos
+		region = regionOp{min: pos, max: pos.Add(cube.Pos{dim[0] - 1, dim[1] - 1, dim[2] - 1})}
+	)
+	blockAt := func(x, y, z int) Block {
+		actual := pos.Add(cube.Pos{x, y, z})
+		if chunkPosFromBlockPos(actual) == cPos {
+			return w.blockInChunk(c, actual)
+		}
+		return w.Block(actual)
+	}
+	region.f = func(p cube.Pos, current [2]uint32) ([2]uint32, Block, bool) {
+		b, liq := s.At(p[0]-pos[0], p[1]-pos[1], p[2]-pos[2], blockAt)
+		if b == nil {
+			return current, nil, false
+		}
+		rid := [2]uint32{BlockRuntimeID(b), airRID}
+		if liq != nil {
+			rid[1] = BlockRuntimeID(liq)
+		}
+		if nbtBlocks[rid[0]] {
+			return rid, b, true
+		}
+		return rid, nil, true
+	}
+	region.before = func(pos ChunkPos, col *Column) {
+		cPos, c = pos, col
+	}
+	return w.newEdit(region, opts)
+}
+
+// Copy copies all blocks, liquids and block entities in the region between the corners a and b, both
+// inclusive, into a Clipboard, which may be pasted using World.Paste. The region is read on a per-chunk
+// basis, so that every chunk is locked only once.
+func (w *World) Copy(a, b cube.Pos) *Clipboard {
+	lo, hi := minPos(a, b), maxPos(a, b)
+	cb := &Clipboard{size: [3]int{hi[0] - lo[0] + 1, hi[1] - lo[1] + 1, hi[2] - lo[2] + 1}, nbt: map[int]Block{}}
+	cb.rid = make([][2]uint32, cb.size[0]*cb.size[1]*cb.size[2])
+	for i := range cb.rid {
+		cb.rid[i] = [2]uint32{airRID, airRID}
+	}
+	if w == nil {
+		return cb
+	}
+	region := regionOp{min: lo, max: hi}
+	for _, pos := range region.chunks() {
+		c := w.chunk(pos)
+		region.iter(w, pos, func(p cube.Pos) {
+			x, y, z := uint8(p[0]), int16(p[1]), uint8(p[2])
+			i := cb.index(p[0]-lo[0], p[1]-lo[1], p[2]-lo[2])
+			cb.rid[i] = [2]uint32{c.Block(x, y, z, 0), c.Block(x, y, z, 1)}
+			if nbt, ok := c.BlockEntities[p]; ok {
+				cb.nbt[i] = nbt
+			}
+		})
+		c.Unlock()
+	}
+	return cb
+}
+
+// Clipboard is a Structure holding a copy of a region of a World, created using World.Copy.
+type Clipboard struct {
+	size [3]int
+	rid  [][2]uint32
+	nbt  map[int]Block
+}
+
+// Dimensions ...
+func (cb *Clipboard) Dimensions() [3]int {
+	return cb.size
+}
+
+// At ...
+func (cb *Clipboard) At(x, y, z int, _ func(x, y, z int) Block) (Block, Liquid) {
+	i := cb.index(x, y, z)
+	if nbt, ok := cb.nbt[i]; ok {
+		// Blocks with block entities are stored with their data, so we return those directly.
+		return nbt, cb.liquid(cb.rid[i][1])
+	}
+	b, _ := BlockByRuntimeID(cb.rid[i][0])
+	return b, cb.liquid(cb.rid[i][1])
+}
+
+// liquid returns the Liquid with the runtime ID passed, or nil if the runtime ID is not that of a liquid.
+func (cb *Clipboard) liquid(rid uint32) Liquid {
+	if rid == airRID || !liquidBlocks[rid] {
+		return nil
+	}
+	b, _ := BlockByRuntimeID(rid)
+	return b.(Liquid)
+}
+
+// index returns the index of the position passed in the Clipboard.
+func (cb *Clipboard) index(x, y, z int) int {
+	return (y*cb.size[2]+z)*cb.size[0] + x
+}
+
+// regionOp is an editOp that changes blocks in a cuboid region.
+type regionOp struct {
+	min, max cube.Pos
+	// before, if not nil, is called with every chunk before the region in it is changed.
+	before func(pos ChunkPos, c *Column)
+	// f returns the new runtime IDs and block entity of a block at a position with the current runtime IDs
+	// passed. If false is returned, the block is not changed.
+	f func(pos cube.Pos, current [2]uint32) ([2]uint32, Block, bool)
+}
+
+// chunks ...
+func (r regionOp) chunks() []ChunkPos {
+	var chunks []ChunkPos
+	for x := r.min[0] >> 4; x <= r.max[0]>>4; x++ {
+		for z := r.min[2] >> 4; z <= r.max[2]>>4; z++ {
+			chunks = append(chunks, ChunkPos{int32(x), int32(z)})
+		}
+	}
+	return chunks
+}
+
+// apply ...
+func (r regionOp) apply(w *World, pos ChunkPos, c *Column, set func(pos cube.Pos, rid [2]uint32, nbt Block)) {
+	if r.before != nil {
+		r.before(pos, c)
+	}
+	r.iter(w, pos, func(p cube.Pos) {
+		x, y, z := uint8(p[0]), int16(p[1]), uint8(p[2])
+		current := [2]uint32{c.Block(x, y, z, 0), c.Block(x, y, z, 1)}
+		if rid, nbt, ok := r.f(p, current); ok {
+			set(p, rid, nbt)
+		}
+	})
+}
+
+// iter calls f for every position of the region within the chunk at the position passed and the height
+// range of the World.
+func (r regionOp) iter(w *World, pos ChunkPos, f func(p cube.Pos)) {
+	baseX, baseZ := int(pos[0])<<4, int(pos[1])<<4
+	minX, maxX := max(r.min[0], baseX), min(r.max[0], baseX+15)
+	minZ, maxZ := max(r.min[2], baseZ), min(r.max[2], baseZ+15)
+	minY, maxY := max(r.min[1], w.Range()[0]), min(r.max[1], w.Range()[1])
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			for z := minZ; z <= maxZ; z++ {
+				f(cube.Pos{x, y, z})
+			}
+		}
+	}
+}
+
+// historyOp is an editOp that restores the blocks recorded in the history of an Edit.
+type historyOp map[ChunkPos][]blockChange
+
+// chunks ...
+func (h historyOp) chunks() []ChunkPos {
+	chunks := make([]ChunkPos, 0, len(h))
+	for pos := range h {
+		chunks = append(chunks, pos)
+	}
+	slices.SortFunc(chunks, func(a, b ChunkPos) int {
+		if a[0] != b[0] {
+			return int(a[0]) - int(b[0])
+		}
+		return int(a[1]) - int(b[1])
+	})
+	return chunks
+}
+
+// apply ...
+func (h historyOp) apply(_ *World, pos ChunkPos, _ *Column, set func(pos cube.Pos, rid [2]uint32, nbt Block)) {
+	for _, change := range h[pos] {
+		set(change.pos, change.rid, change.nbt)
+	}
+}
+
+// minPos returns a cube.Pos with the lowest coordinates of the two positions passed on every axis.
+func minPos(a, b cube.Pos) cube.Pos {
+	return cube.Pos{min(a[0], b[0]), min(a[1], b[1]), min(a[2], b[2])}
+}
+
+// maxPos returns a cube.Pos with the highest coordinates of the two positions passed on every axis.
+func maxPos(a, b cube.Pos) cube.Pos {
+	return cube.Pos{max(a[0], b[0]), max(a[1], b[1]), max(a[2], b[2])}
+}
+
+// EditHistory holds the most recent Edits made, such as the Edits of a single player, so that they may be
+// undone and redone in order. An EditHistory is safe for concurrent use.
+type EditHistory struct {
+	mu         sync.Mutex
+	max        int
+	undo, redo []*Edit
+}
+
+// NewEditHistory creates a new EditHistory that holds at most n Edits. Edits added to an EditHistory that is
+// full push out the oldest Edit.
+func NewEditHistory(n int) *EditHistory {
+	return &EditHistory{max: max(n, 1)}
+}
+
+// Add adds an Edit to the EditHistory. All Edits that were undone previously can no longer be redone.
+func (h *EditHistory) Add(e *Edit) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.undo = h.push(h.undo, e)
+	h.redo = nil
+}
+
+// Undo undoes the most recent Edit in the EditHistory that was not yet undone and returns the Edit that
+// restores the blocks. ErrEmptyHistory is returned if there is no Edit to undo.
+func (h *EditHistory) Undo(opts *EditOpts) (*Edit, error) {
+	return h.move(&h.undo, &h.redo, opts)
+}
+
+// Redo redoes the most recent Edit in the EditHistory that was undone and returns the Edit that changes the
+// blocks again. ErrEmptyHistory is returned if there is no Edit to redo.
+func (h *EditHistory) Redo(opts *EditOpts) (*Edit, error) {
+	return h.move(&h.redo, &h.undo, opts)
+}
+
+// move undoes the last Edit in from and adds the resulting Edit to to.
+func (h *EditHistory) move(from, to *[]*Edit, opts *EditOpts) (*Edit, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(*from) == 0 {
+		return nil, ErrEmptyHistory
+	}
+	last := (*from)[len(*from)-1]
+	last.Wait()
+	e, err := last.Undo(opts)
+	if err != nil {
+		return nil, err
+	}
+	*from = (*from)[:len(*from)-1]
+	*to = h.push(*to, e)
+	return e, nil
+}
+
+// push appends the Edit passed to the slice passed, removing the first Edit if the slice is full.
+func (h *EditHistory) push(s []*Edit, e *Edit) []*Edit {
+	if len(s) >= h.max {
+		s = slices.Delete(s, 0, 1)
+	}
+	return append(s, e)
+}
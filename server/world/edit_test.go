@@ -0,0 +1,106 @@
+package world_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// checkRegion fails the test if any block in the region between a and b is not equal to the block passed.
+func checkRegion(t *testing.T, w *world.World, a, b cube.Pos, want world.Block) {
+	t.Helper()
+	for x := a[0]; x <= b[0]; x++ {
+		for y := a[1]; y <= b[1]; y++ {
+			for z := a[2]; z <= b[2]; z++ {
+				if got := w.Block(cube.Pos{x, y, z}); got != want {
+					t.Fatalf("block at %v: got %#v, want %#v", cube.Pos{x, y, z}, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestEditHistoryUndoRedo(t *testing.T) {
+	w := world.Config{}.New()
+	defer w.Close()
+
+	// The region crosses the borders of four chunks.
+	a, b := cube.Pos{-3, 0, -3}, cube.Pos{3, 2, 3}
+	h := world.NewEditHistory(2)
+
+	h.Add(w.Fill(a, b, block.Stone{}, nil))
+	checkRegion(t, w, a, b, block.Stone{})
+	h.Add(w.Fill(a, b, block.Dirt{}, nil))
+	checkRegion(t, w, a, b, block.Dirt{})
+
+	for _, want := range []world.Block{block.Stone{}, block.Air{}} {
+		if _, err := h.Undo(nil); err != nil {
+			t.Fatalf("undo: %v", err)
+		}
+		checkRegion(t, w, a, b, want)
+	}
+	if _, err := h.Undo(nil); err != world.ErrEmptyHistory {
+		t.Fatalf("undo of empty history: got %v, want %v", err, world.ErrEmptyHistory)
+	}
+	for _, want := range []world.Block{block.Stone{}, block.Dirt{}} {
+		if _, err := h.Redo(nil); err != nil {
+			t.Fatalf("redo: %v", err)
+		}
+		checkRegion(t, w, a, b, want)
+	}
+	if _, err := h.Redo(nil); err != world.ErrEmptyHistory {
+		t.Fatalf("redo of empty history: got %v, want %v", err, world.ErrEmptyHistory)
+	}
+
+	e := w.Fill(a, b, block.Stone{}, &world.EditOpts{DisableHistory: true})
+	if _, err := e.Undo(nil); err != world.ErrNoHistory {
+		t.Fatalf("undo without history: got %v, want %v", err, world.ErrNoHistory)
+	}
+}
+
+func TestEditMultiTick(t *testing.T) {
+	w := world.Config{}.New()
+	defer w.Close()
+
+	var (
+		edit     atomic.Pointer[world.Edit]
+		progress []int
+	)
+	a, b := cube.Pos{0, 0, 0}, cube.Pos{47, 0, 31}
+	e := w.Fill(a, b, block.Stone{}, &world.EditOpts{ChunksPerTick: 1, Progress: func(done, total int) {
+		if e := edit.Load(); e != nil {
+			// Calling Progress from the callback must not deadlock.
+			if d, _ := e.Progress(); d != done {
+				t.Errorf("progress in callback: got %v, want %v", d, done)
+			}
+		}
+		progress = append(progress, done)
+	}})
+	edit.Store(e)
+
+	select {
+	case <-e.Done():
+	case <-time.After(time.Second * 5):
+		t.Fatalf("edit not done after 5 seconds")
+	}
+	if done, total := e.Progress(); done != 6 || total != 6 {
+		t.Fatalf("progress: got %v/%v, want 6/6", done, total)
+	}
+	for i, done := range progress {
+		if done != i+1 {
+			t.Fatalf("progress callbacks: got %v, want 1 through 6", progress)
+		}
+	}
+	checkRegion(t, w, a, b, block.Stone{})
+
+	undo, err := e.Undo(&world.EditOpts{ChunksPerTick: 2})
+	if err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+	undo.Wait()
+	checkRegion(t, w, a, b, block.Air{})
+}
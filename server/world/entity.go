@@ -104,14 +104,18 @@ type EntityRegistryConfig struct {
 	FallingBlock       func(bl Block, pos mgl64.Vec3) Entity
 	TNT                func(pos mgl64.Vec3, fuse time.Duration, igniter Entity) Entity
 	BottleOfEnchanting func(pos, vel mgl64.Vec3, owner Entity) Entity
-	Arrow              func(pos, vel mgl64.Vec3, rot cube.Rotation, damage float64, owner Entity, critical, disallowPickup, obtainArrowOnPickup bool, punchLevel int, tip any) Entity
+	Arrow              func(pos, vel mgl64.Vec3, rot cube.Rotation, damage float64, owner Entity, critical, disallowPickup, obtainArrowOnPickup bool, punchLevel, piercingLevel int, tip any) Entity
 	Egg                func(pos, vel mgl64.Vec3, owner Entity) Entity
 	EnderPearl         func(pos, vel mgl64.Vec3, owner Entity) Entity
 	Firework           func(pos mgl64.Vec3, rot cube.Rotation, attached bool, firework Item, owner Entity) Entity
+	FishingHook        func(pos, vel mgl64.Vec3, owner Entity, rod any) Entity
 	LingeringPotion    func(pos, vel mgl64.Vec3, t any, owner Entity) Entity
 	Snowball           func(pos, vel mgl64.Vec3, owner Entity) Entity
 	SplashPotion       func(pos, vel mgl64.Vec3, t any, owner Entity) Entity
+	Trident            func(pos, vel mgl64.Vec3, rot cube.Rotation, owner Entity, trident any, disallowPickup bool) Entity
 	Lightning          func(pos mgl64.Vec3) Entity
+	Boat               func(pos mgl64.Vec3, yaw float64, t any) Entity
+	Minecart           func(pos mgl64.Vec3, t any) Entity
 }
 
 // New creates an EntityRegistry using conf and the EntityTypes passed.
@@ -0,0 +1,202 @@
+package generator
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/biome"
+	"github.com/stcraft/dragonfly/server/world/chunk"
+)
+
+const (
+	// endIslandLevel is the y value around which the islands of the end float.
+	endIslandLevel = 56
+	// endIslandRadius is the approximate radius of the central island of the
+	// end.
+	endIslandRadius = 100
+	// outerIslandsDistance is the distance from the centre of the end at
+	// which the outer islands start forming.
+	outerIslandsDistance = 1000
+)
+
+// End is a world.Generator that generates terrain similar to that of the
+// vanilla end: A central island of end stone with a ring of obsidian pillars
+// and the exit portal, surrounded by a void and, further out, by many smaller
+// outer islands. An End generator is deterministic and may be used by
+// multiple goroutines at the same time. It may be constructed by calling
+// NewEnd.
+type End struct {
+	seed int64
+
+	islands, surface octaveNoise
+
+	air, endStone, obsidian, bedrock, ironBars uint32
+	pillars                                    [10]endPillar
+}
+
+// endPillar is one of the obsidian pillars on the central island of the end.
+type endPillar struct {
+	x, z, radius, height int
+	caged                bool
+}
+
+// NewEnd creates a new End generator that generates terrain using the seed
+// passed.
+func NewEnd(seed int64) *End {
+	e := &End{
+		seed:    seed,
+		islands: newOctaveNoise(seed, 201, 3, 1.0/96),
+		surface: newOctaveNoise(seed, 202, 2, 1.0/32),
+
+		air:      world.BlockRuntimeID(block.Air{}),
+		endStone: world.BlockRuntimeID(block.EndStone{}),
+		obsidian: world.BlockRuntimeID(block.Obsidian{}),
+		bedrock:  world.BlockRuntimeID(block.Bedrock{}),
+		ironBars: world.BlockRuntimeID(block.IronBars{}),
+	}
+	// The pillars are placed in a circle around the centre of the island. Their
+	// sizes are shuffled using the seed, so that every world has a different
+	// arrangement, like in vanilla.
+	sizes := rand.New(rand.NewSource(seed)).Perm(len(e.pillars))
+	for i := range e.pillars {
+		angle := 2 * (-math.Pi + math.Pi/10*float64(i))
+		size := sizes[i]
+		e.pillars[i] = endPillar{
+			x:      int(math.Floor(42 * math.Cos(angle))),
+			z:      int(math.Floor(42 * math.Sin(angle))),
+			radius: 2 + size/3,
+			height: 76 + size*3,
+			caged:  size == 1 || size == 2,
+		}
+	}
+	return e
+}
+
+// Seed returns the seed of the End generator.
+func (e *End) Seed() int64 {
+	return e.seed
+}
+
+// GenerateChunk ...
+func (e *End) GenerateChunk(pos world.ChunkPos, c *chunk.Chunk) {
+	baseX, baseZ := int(pos[0])<<4, int(pos[1])<<4
+	id := uint32(biome.End{}.EncodeBiome())
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			cx, cz := uint8(x), uint8(z)
+			if bottom, top, ok := e.island(baseX+x, baseZ+z); ok {
+				for y := max(bottom+1, c.Range().Min()); y <= min(top, c.Range().Max()); y++ {
+					c.SetBlock(cx, int16(y), cz, 0, e.endStone)
+				}
+			}
+			for y := c.Range().Min(); y <= c.Range().Max(); y++ {
+				c.SetBiome(cx, int16(y), cz, id)
+			}
+		}
+	}
+	for _, p := range e.pillars {
+		e.pillar(c, baseX, baseZ, p)
+	}
+	e.exitPortal(c, baseX, baseZ)
+}
+
+// island returns the y values of the bottom and top of the island at the
+// column passed. False is returned if no island is present at the column.
+func (e *End) island(x, z int) (bottom, top int, ok bool) {
+	fx, fz := float64(x), float64(z)
+	dist := math.Hypot(fx, fz)
+	n := e.surface.at2(fx, fz)
+
+	// The central island is roughly round with slightly irregular edges.
+	v := clamp(1-(dist+n*12)/endIslandRadius, 0, 1)
+	if dist > outerIslandsDistance {
+		// The outer islands gradually become more common further away from the
+		// void that surrounds the central island.
+		outer := clamp((e.islands.at2(fx, fz)*2.2-0.3)*3, 0, 1) * clamp((dist-outerIslandsDistance)/200, 0, 1)
+		v = math.Max(v, outer)
+	}
+	if v <= 0 {
+		return 0, 0, false
+	}
+	top = endIslandLevel + int(8*math.Sqrt(v)+3*n)
+	bottom = endIslandLevel - int(48*math.Pow(v, 0.7)+4*n)
+	return bottom, top, top > bottom
+}
+
+// pillar places the blocks of an obsidian pillar that are within the chunk
+// passed.
+func (e *End) pillar(c *chunk.Chunk, baseX, baseZ int, p endPillar) {
+	set := func(x, y, z int, rid uint32) {
+		if x >= baseX && x < baseX+16 && z >= baseZ && z < baseZ+16 && y >= c.Range().Min() && y <= c.Range().Max() {
+			c.SetBlock(uint8(x-baseX), int16(y), uint8(z-baseZ), 0, rid)
+		}
+	}
+	if p.x+p.radius+2 < baseX || p.x-p.radius-2 >= baseX+16 || p.z+p.radius+2 < baseZ || p.z-p.radius-2 >= baseZ+16 {
+		return
+	}
+	for dx := -p.radius; dx <= p.radius; dx++ {
+		for dz := -p.radius; dz <= p.radius; dz++ {
+			if dx*dx+dz*dz > p.radius*p.radius+1 {
+				continue
+			}
+			for y := c.Range().Min(); y < p.height; y++ {
+				set(p.x+dx, y, p.z+dz, e.obsidian)
+			}
+		}
+	}
+	set(p.x, p.height, p.z, e.bedrock)
+	if !p.caged {
+		return
+	}
+	// Caged pillars have a cage of iron bars around the top, protecting the end
+	// crystal on top of it.
+	for dx := -2; dx <= 2; dx++ {
+		for dz := -2; dz <= 2; dz++ {
+			for dy := 0; dy <= 3; dy++ {
+				if abs(dx) == 2 || abs(dz) == 2 || dy == 3 {
+					set(p.x+dx, p.height+dy, p.z+dz, e.ironBars)
+				}
+			}
+		}
+	}
+}
+
+// exitPortal places the blocks of the inactive exit portal at the centre of
+// the central island that are within the chunk passed.
+func (e *End) exitPortal(c *chunk.Chunk, baseX, baseZ int) {
+	if baseX > 4 || baseX+16 < -4 || baseZ > 4 || baseZ+16 < -4 {
+		return
+	}
+	_, y, _ := e.island(0, 0)
+	set := func(x, y, z int, rid uint32) {
+		if x >= baseX && x < baseX+16 && z >= baseZ && z < baseZ+16 {
+			c.SetBlock(uint8(x-baseX), int16(y), uint8(z-baseZ), 0, rid)
+		}
+	}
+	for dx := -4; dx <= 4; dx++ {
+		for dz := -4; dz <= 4; dz++ {
+			d := dx*dx + dz*dz
+			switch {
+			case d <= 12:
+				// The bowl of the portal, which is filled with portal blocks
+				// once the ender dragon is defeated.
+				set(dx, y, dz, e.bedrock)
+				for ly := y + 1; ly <= y+4; ly++ {
+					set(dx, ly, dz, e.air)
+				}
+				if d > 6 {
+					set(dx, y+1, dz, e.bedrock)
+				}
+			case d <= 20:
+				for ly := y + 1; ly <= y+4; ly++ {
+					set(dx, ly, dz, e.air)
+				}
+			}
+		}
+	}
+	for ly := y + 1; ly <= y+4; ly++ {
+		set(0, ly, 0, e.bedrock)
+	}
+}
@@ -0,0 +1,145 @@
+package generator_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/chunk"
+	"github.com/stcraft/dragonfly/server/world/generator"
+)
+
+// regionRadius is the radius in chunks of the square region of chunks generated by the tests.
+const regionRadius = 3
+
+// region returns the positions of all chunks in the region generated by the tests, in ascending order.
+func region() []world.ChunkPos {
+	var positions []world.ChunkPos
+	for x := int32(-regionRadius); x <= regionRadius; x++ {
+		for z := int32(-regionRadius); z <= regionRadius; z++ {
+			positions = append(positions, world.ChunkPos{x, z})
+		}
+	}
+	return positions
+}
+
+// generateSequential generates the chunks at the positions passed one by one, in the order passed.
+func generateSequential(g world.Generator, dim world.Dimension, positions []world.ChunkPos) map[world.ChunkPos]*chunk.Chunk {
+	chunks := make(map[world.ChunkPos]*chunk.Chunk, len(positions))
+	for _, pos := range positions {
+		c := chunk.New(world.BlockRuntimeID(block.Air{}), dim.Range())
+		g.GenerateChunk(pos, c)
+		chunks[pos] = c
+	}
+	return chunks
+}
+
+// generateParallel generates the chunks at the positions passed, all at the same time.
+func generateParallel(g world.Generator, dim world.Dimension, positions []world.ChunkPos) map[world.ChunkPos]*chunk.Chunk {
+	chunks := make([]*chunk.Chunk, len(positions))
+	var wg sync.WaitGroup
+	for i, pos := range positions {
+		wg.Add(1)
+		go func(i int, pos world.ChunkPos) {
+			defer wg.Done()
+			chunks[i] = chunk.New(world.BlockRuntimeID(block.Air{}), dim.Range())
+			g.GenerateChunk(pos, chunks[i])
+		}(i, pos)
+	}
+	wg.Wait()
+
+	m := make(map[world.ChunkPos]*chunk.Chunk, len(positions))
+	for i, pos := range positions {
+		m[pos] = chunks[i]
+	}
+	return m
+}
+
+// compareChunks fails the test if any block or biome of the chunks in a differs from that in b.
+func compareChunks(t *testing.T, a, b map[world.ChunkPos]*chunk.Chunk) {
+	t.Helper()
+	for pos, ca := range a {
+		cb := b[pos]
+		r := ca.Range()
+		for x := uint8(0); x < 16; x++ {
+			for z := uint8(0); z < 16; z++ {
+				for y := int16(r.Min()); y <= int16(r.Max()); y++ {
+					for layer := uint8(0); layer < 2; layer++ {
+						if ba, bb := ca.Block(x, y, z, layer), cb.Block(x, y, z, layer); ba != bb {
+							t.Fatalf("chunk %v: block at %v %v %v (layer %v) differs: %v != %v", pos, x, y, z, layer, name(ba), name(bb))
+						}
+					}
+					if ba, bb := ca.Biome(x, y, z), cb.Biome(x, y, z); ba != bb {
+						t.Fatalf("chunk %v: biome at %v %v %v differs: %v != %v", pos, x, y, z, ba, bb)
+					}
+				}
+			}
+		}
+	}
+}
+
+// borderFeatures counts the blocks in the border columns of the chunks passed with a name that contains any
+// of the strings passed.
+func borderFeatures(chunks map[world.ChunkPos]*chunk.Chunk, features ...string) int {
+	n := 0
+	for _, c := range chunks {
+		r := c.Range()
+		for x := uint8(0); x < 16; x++ {
+			for z := uint8(0); z < 16; z++ {
+				if x != 0 && x != 15 && z != 0 && z != 15 {
+					continue
+				}
+				for y := int16(r.Min()); y <= int16(r.Max()); y++ {
+					for _, f := range features {
+						if strings.Contains(name(c.Block(x, y, z, 0)), f) {
+							n++
+						}
+					}
+				}
+			}
+		}
+	}
+	return n
+}
+
+// name returns the name of the block with the runtime ID passed.
+func name(rid uint32) string {
+	b, _ := world.BlockByRuntimeID(rid)
+	n, _ := b.EncodeBlock()
+	return n
+}
+
+// testDeterministic generates the region of chunks using the generator passed in ascending order, in
+// descending order using a new generator with the same seed, and in parallel, and fails the test if any of
+// the chunks differ. Blocks of the features passed must be present in the border columns of the chunks, so
+// that features crossing chunk borders are compared too.
+func testDeterministic(t *testing.T, newGenerator func() world.Generator, dim world.Dimension, features ...string) {
+	positions := region()
+	reversed := make([]world.ChunkPos, len(positions))
+	for i, pos := range positions {
+		reversed[len(positions)-1-i] = pos
+	}
+
+	want := generateSequential(newGenerator(), dim, positions)
+	for _, f := range features {
+		if borderFeatures(want, f) == 0 {
+			t.Fatalf("no %v found in the border columns of the chunks generated", f)
+		}
+	}
+	compareChunks(t, want, generateSequential(newGenerator(), dim, reversed))
+	compareChunks(t, want, generateParallel(newGenerator(), dim, reversed))
+}
+
+func TestOverworldDeterministic(t *testing.T) {
+	testDeterministic(t, func() world.Generator { return generator.NewOverworld(1) }, world.Overworld, "leaves", "_ore")
+}
+
+func TestNetherDeterministic(t *testing.T) {
+	testDeterministic(t, func() world.Generator { return generator.NewNether(1) }, world.Nether, "quartz_ore", "glowstone")
+}
+
+func TestEndDeterministic(t *testing.T) {
+	testDeterministic(t, func() world.Generator { return generator.NewEnd(1) }, world.End, "obsidian")
+}
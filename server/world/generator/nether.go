@@ -0,0 +1,400 @@
+package generator
+
+import (
+	"math"
+
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/biome"
+	"github.com/stcraft/dragonfly/server/world/chunk"
+)
+
+const (
+	// netherLavaLevel is the highest y value at which the lava oceans of the
+	// nether are filled with lava.
+	netherLavaLevel = 31
+	// fungusAttempts is the amount of huge fungi attempted per chunk in
+	// crimson and warped forests.
+	fungusAttempts = 8
+)
+
+// Nether is a world.Generator that generates terrain similar to that of the
+// vanilla nether: Caverns of netherrack above lava oceans, with soul sand
+// valleys, crimson and warped forests and basalt deltas. Like Overworld, a
+// Nether generator is deterministic and may be used by multiple goroutines at
+// the same time. It may be constructed by calling NewNether.
+type Nether struct {
+	seed int64
+
+	terrain, temperature, humidity, surface octaveNoise
+
+	air, netherrack, bedrock, lava, soulSand, soulSoil, gravel uint32
+	basalt, blackstone, glowstone, fire, soulFire              uint32
+	stems, warts                                               [2]uint32
+	shroomlight                                                uint32
+	ores                                                       []oreFeature
+}
+
+// NewNether creates a new Nether generator that generates terrain using the
+// seed passed.
+func NewNether(seed int64) *Nether {
+	rid := world.BlockRuntimeID
+	return &Nether{
+		seed:        seed,
+		terrain:     newOctaveNoise(seed, 101, 4, 1.0/80),
+		temperature: newOctaveNoise(seed, 102, 3, 1.0/240),
+		humidity:    newOctaveNoise(seed, 103, 3, 1.0/240),
+		surface:     newOctaveNoise(seed, 104, 3, 1.0/16),
+
+		air:         rid(block.Air{}),
+		netherrack:  rid(block.Netherrack{}),
+		bedrock:     rid(block.Bedrock{}),
+		lava:        rid(block.Lava{Still: true, Depth: 8}),
+		soulSand:    rid(block.SoulSand{}),
+		soulSoil:    rid(block.SoulSoil{}),
+		gravel:      rid(block.Gravel{}),
+		basalt:      rid(block.Basalt{Axis: cube.Y}),
+		blackstone:  rid(block.Blackstone{Type: block.NormalBlackstone()}),
+		glowstone:   rid(block.Glowstone{}),
+		fire:        rid(block.Fire{Type: block.NormalFire()}),
+		soulFire:    rid(block.Fire{Type: block.SoulFire()}),
+		stems:       [2]uint32{rid(block.Log{Wood: block.CrimsonWood(), Axis: cube.Y}), rid(block.Log{Wood: block.WarpedWood(), Axis: cube.Y})},
+		warts:       [2]uint32{rid(block.NetherWartBlock{}), rid(block.NetherWartBlock{Warped: true})},
+		shroomlight: rid(block.Shroomlight{}),
+		ores:        netherOres(),
+	}
+}
+
+// Seed returns the seed of the Nether generator.
+func (n *Nether) Seed() int64 {
+	return n.seed
+}
+
+// netherOres returns the ore features generated in the nether.
+func netherOres() []oreFeature {
+	netherrack := world.BlockRuntimeID(block.Netherrack{})
+	ore := func(b world.Block) map[uint32]uint32 {
+		return map[uint32]uint32{netherrack: world.BlockRuntimeID(b)}
+	}
+	debris := map[uint32]uint32{
+		netherrack: world.BlockRuntimeID(block.AncientDebris{}),
+		world.BlockRuntimeID(block.Basalt{Axis: cube.Y}):                       world.BlockRuntimeID(block.AncientDebris{}),
+		world.BlockRuntimeID(block.Blackstone{Type: block.NormalBlackstone()}): world.BlockRuntimeID(block.AncientDebris{}),
+	}
+	return []oreFeature{
+		{replace: ore(block.NetherGoldOre{}), size: 10, count: 10, minY: 10, maxY: 117},
+		{replace: ore(block.NetherQuartzOre{}), size: 14, count: 16, minY: 10, maxY: 117},
+		{replace: debris, size: 3, count: 1, minY: 8, maxY: 24, triangle: true},
+		{replace: debris, size: 2, count: 1, minY: 8, maxY: 119},
+	}
+}
+
+// GenerateChunk ...
+func (n *Nether) GenerateChunk(pos world.ChunkPos, c *chunk.Chunk) {
+	g := &netherGeneration{columns: make(map[[2]int][]float64, 25)}
+	baseX, baseZ := int(pos[0])<<4, int(pos[1])<<4
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			n.fillColumn(g, c, baseX+x, baseZ+z, uint8(x), uint8(z))
+		}
+	}
+	generateOres(n.seed, pos, c, n.ores, n.biome)
+	n.generateFungi(g, pos, c)
+}
+
+// fillColumn fills a single column of the chunk passed with terrain, lava,
+// surface blocks, decoration and biomes.
+func (n *Nether) fillColumn(g *netherGeneration, c *chunk.Chunk, x, z int, cx, cz uint8) {
+	r := c.Range()
+	s := n.sampler(g, x, z, r)
+	b := n.biome(x, z)
+	noise := n.surface.at2(float64(x), float64(z))
+
+	for y := r.Max(); y >= r.Min(); y-- {
+		rid := n.air
+		switch {
+		case s.at(y) > 0:
+			rid = n.netherrack
+			if s.at(y+1) <= 0 || y == r.Max() {
+				rid = n.floor(b, x, y, z, noise)
+			} else if s.at(y+2) <= 0 || s.at(y+3) <= 0 {
+				rid = n.subFloor(b, noise)
+			} else if s.at(y-1) <= 0 {
+				rid = n.ceiling(b)
+			}
+		case y <= netherLavaLevel:
+			rid = n.lava
+		case s.at(y-1) > 0:
+			rid = n.floorDecoration(b, x, y, z)
+		case s.at(y+1) > 0:
+			n.hangGlowstone(c, s, x, y, z, cx, cz)
+		}
+		if (y <= r.Min()+4 && n.bedrockAt(x, y, z, y-r.Min())) || (y >= r.Max()-4 && n.bedrockAt(x, y, z, r.Max()-y)) {
+			rid = n.bedrock
+		}
+		if rid != n.air {
+			c.SetBlock(cx, int16(y), cz, 0, rid)
+		}
+	}
+
+	id := uint32(b.EncodeBiome())
+	for y := r.Min(); y <= r.Max(); y++ {
+		c.SetBiome(cx, int16(y), cz, id)
+	}
+}
+
+// bedrockAt checks if bedrock is placed at a position with a distance from the
+// top or bottom of the nether.
+func (n *Nether) bedrockAt(x, y, z, dist int) bool {
+	return dist == 0 || positionRand(n.seed, x, y, z, 102) < 1-float64(dist)/5
+}
+
+// floor returns the block placed at the top of solid terrain, directly below
+// air or lava.
+func (n *Nether) floor(b world.Biome, x, y, z int, noise float64) uint32 {
+	switch b.(type) {
+	case biome.SoulSandValley:
+		if noise > 0 {
+			return n.soulSand
+		}
+		return n.soulSoil
+	case biome.BasaltDeltas:
+		switch {
+		case noise < -0.4 && y > netherLavaLevel:
+			// Small pools of lava form the deltas that give the biome its name.
+			return n.lava
+		case noise > 0.1:
+			return n.blackstone
+		}
+		return n.basalt
+	case biome.CrimsonForest, biome.WarpedForest:
+		return n.netherrack
+	}
+	// Gravel and soul sand form beaches near the surface of lava oceans.
+	if y >= netherLavaLevel-1 && y <= netherLavaLevel+3 {
+		switch {
+		case noise > 0.25:
+			return n.gravel
+		case noise < -0.25:
+			return n.soulSand
+		}
+	}
+	return n.netherrack
+}
+
+// subFloor returns the block placed just below the floor of the nether.
+func (n *Nether) subFloor(b world.Biome, noise float64) uint32 {
+	switch b.(type) {
+	case biome.SoulSandValley:
+		return n.soulSoil
+	case biome.BasaltDeltas:
+		if noise > 0.1 {
+			return n.blackstone
+		}
+		return n.basalt
+	}
+	return n.netherrack
+}
+
+// ceiling returns the block placed at the bottom of solid terrain, directly
+// above air.
+func (n *Nether) ceiling(b world.Biome) uint32 {
+	if _, ok := b.(biome.BasaltDeltas); ok {
+		return n.basalt
+	}
+	return n.netherrack
+}
+
+// floorDecoration returns the block placed directly above the floor of the
+// nether, such as fire or loose blocks of basalt.
+func (n *Nether) floorDecoration(b world.Biome, x, y, z int) uint32 {
+	rnd := positionRand(n.seed, x, y, z, 103)
+	switch b.(type) {
+	case biome.SoulSandValley:
+		if rnd < 0.005 {
+			return n.soulFire
+		}
+	case biome.NetherWastes:
+		if rnd < 0.003 {
+			return n.fire
+		}
+	case biome.BasaltDeltas:
+		if rnd < 0.02 {
+			return n.basalt
+		}
+	}
+	return n.air
+}
+
+// hangGlowstone places a column of glowstone hanging from the ceiling above
+// the position passed, with a small chance.
+func (n *Nether) hangGlowstone(c *chunk.Chunk, s netherSampler, x, y, z int, cx, cz uint8) {
+	if positionRand(n.seed, x, y, z, 104) >= 0.02 {
+		return
+	}
+	length := 1 + int(positionRand(n.seed, x, y, z, 105)*5)
+	for ly := y; ly > y-length && ly > netherLavaLevel && s.at(ly) <= 0; ly-- {
+		c.SetBlock(cx, int16(ly), cz, 0, n.glowstone)
+	}
+}
+
+// biome returns the biome of the column at the x and z passed. Nether biomes
+// are selected based on the temperature and humidity of the column.
+func (n *Nether) biome(x, z int) world.Biome {
+	t, h := n.temperature.at2(float64(x), float64(z))*2.2, n.humidity.at2(float64(x), float64(z))*2.2
+	points := [...]struct {
+		t, h float64
+		b    world.Biome
+	}{
+		{0, 0, biome.NetherWastes{}},
+		{0, -0.5, biome.SoulSandValley{}},
+		{0.4, 0, biome.CrimsonForest{}},
+		{0, 0.5, biome.WarpedForest{}},
+		{-0.5, 0, biome.BasaltDeltas{}},
+	}
+	best, dist := points[0].b, math.Inf(1)
+	for _, p := range points {
+		if d := (t-p.t)*(t-p.t) + (h-p.h)*(h-p.h); d < dist {
+			best, dist = p.b, d
+		}
+	}
+	return best
+}
+
+// netherGeneration holds the state of the generation of a single chunk of
+// the nether. It caches the densities sampled at the corners of cells.
+type netherGeneration struct {
+	columns map[[2]int][]float64
+}
+
+// column returns the densities sampled at the corners of cells in the column
+// at the x and z passed, which must be multiples of cellWidth.
+func (n *Nether) column(g *netherGeneration, x, z int, r cube.Range) []float64 {
+	if col, ok := g.columns[[2]int{x, z}]; ok {
+		return col
+	}
+	col := make([]float64, (r.Max()+1-r.Min())/cellHeight+1)
+	for i := range col {
+		y := float64(r.Min() + i*cellHeight)
+		col[i] = n.terrain.at3(float64(x), y*1.6, float64(z))*2.6 + netherBias.at(y)
+	}
+	g.columns[[2]int{x, z}] = col
+	return col
+}
+
+// netherBias maps the y value to a bias added to the density of the nether,
+// making sure the nether is closed off at the top and bottom.
+var netherBias = spline{{0, 2}, {8, 0.5}, {24, 0.1}, {40, -0.1}, {96, -0.15}, {108, 0.1}, {118, 0.8}, {127, 2}}
+
+// netherSampler interpolates the densities of the four columns at the corners
+// of the cell that a column is in.
+type netherSampler struct {
+	r                  cube.Range
+	c00, c10, c01, c11 []float64
+	fx, fz             float64
+}
+
+// sampler returns a netherSampler for the column at the x and z passed.
+func (n *Nether) sampler(g *netherGeneration, x, z int, r cube.Range) netherSampler {
+	x0, z0 := floorDiv(x, cellWidth)*cellWidth, floorDiv(z, cellWidth)*cellWidth
+	return netherSampler{
+		r:   r,
+		c00: n.column(g, x0, z0, r), c10: n.column(g, x0+cellWidth, z0, r),
+		c01: n.column(g, x0, z0+cellWidth, r), c11: n.column(g, x0+cellWidth, z0+cellWidth, r),
+		fx: float64(x-x0) / cellWidth, fz: float64(z-z0) / cellWidth,
+	}
+}
+
+// at returns the density at the y passed. Positions with a density above 0
+// are solid. Positions outside the range of the nether are always solid.
+func (s netherSampler) at(y int) float64 {
+	if y < s.r.Min() || y >= s.r.Max() {
+		return 1
+	}
+	i, fy := (y-s.r.Min())/cellHeight, float64((y-s.r.Min())%cellHeight)/cellHeight
+	return lerp(fy,
+		lerp(s.fz, lerp(s.fx, s.c00[i], s.c10[i]), lerp(s.fx, s.c01[i], s.c11[i])),
+		lerp(s.fz, lerp(s.fx, s.c00[i+1], s.c10[i+1]), lerp(s.fx, s.c01[i+1], s.c11[i+1])))
+}
+
+// generateFungi generates the huge fungi of crimson and warped forests that
+// originate in the chunk at the position passed and in its neighbours. Only
+// the blocks of these fungi that are within the chunk are placed.
+func (n *Nether) generateFungi(g *netherGeneration, pos world.ChunkPos, c *chunk.Chunk) {
+	baseX, baseZ := int(pos[0])<<4, int(pos[1])<<4
+	for dx := int32(-1); dx <= 1; dx++ {
+		for dz := int32(-1); dz <= 1; dz++ {
+			r := chunkRand(n.seed, pos[0]+dx, pos[1]+dz, 3000)
+			for i := 0; i < fungusAttempts; i++ {
+				x, z, y, height := int(pos[0]+dx)<<4+r.Intn(16), int(pos[1]+dz)<<4+r.Intn(16), netherLavaLevel+2+r.Intn(80), 4+r.Intn(6)
+				if x < baseX-4 || x > baseX+19 || z < baseZ-4 || z > baseZ+19 {
+					continue
+				}
+				warped := 0
+				switch n.biome(x, z).(type) {
+				case biome.CrimsonForest:
+				case biome.WarpedForest:
+					warped = 1
+				default:
+					continue
+				}
+				// Find the floor below the random y value that the fungus grows
+				// on. The fungus is only placed if there is enough space above it.
+				s := n.sampler(g, x, z, c.Range())
+				for y > netherLavaLevel && (s.at(y-1) <= 0 || s.at(y) > 0) {
+					y--
+				}
+				if y <= netherLavaLevel+1 || s.at(y+height+1) > 0 {
+					continue
+				}
+				n.fungus(c, baseX, baseZ, x, y, z, height, warped)
+			}
+		}
+	}
+}
+
+// fungus places a huge fungus with its stem starting at the position passed.
+// The warped passed is 1 for warped fungi and 0 for crimson fungi.
+func (n *Nether) fungus(c *chunk.Chunk, baseX, baseZ, x, y, z, height, warped int) {
+	set := func(x, y, z int, rid uint32) {
+		if x < baseX || x >= baseX+16 || z < baseZ || z >= baseZ+16 || y > c.Range().Max() {
+			return
+		}
+		if c.Block(uint8(x-baseX), int16(y), uint8(z-baseZ), 0) == n.air {
+			c.SetBlock(uint8(x-baseX), int16(y), uint8(z-baseZ), 0, rid)
+		}
+	}
+	for i := 0; i < height; i++ {
+		set(x, y+i, z, n.stems[warped])
+	}
+	top := y + height - 1
+	for ly := top - 2; ly <= top+1; ly++ {
+		radius := 2
+		if ly == top+1 {
+			radius = 1
+		}
+		for dx := -radius; dx <= radius; dx++ {
+			for dz := -radius; dz <= radius; dz++ {
+				edge := abs(dx) == radius || abs(dz) == radius
+				if ly < top && !edge {
+					// The lower layers of the cap only form its rim.
+					continue
+				}
+				rid := n.warts[warped]
+				if positionRand(n.seed, x+dx, ly, z+dz, 106) < 0.08 {
+					rid = n.shroomlight
+				}
+				set(x+dx, ly, z+dz, rid)
+			}
+		}
+	}
+}
+
+// abs returns the absolute value of x.
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
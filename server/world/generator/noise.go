@@ -0,0 +1,191 @@
+package generator
+
+import (
+	"math"
+	"math/rand"
+)
+
+// perlin is a seeded implementation of improved Perlin noise in two and three
+// dimensions. It returns values roughly in the range -1 to 1.
+type perlin struct {
+	p          [512]uint8
+	xo, yo, zo float64
+}
+
+// newPerlin creates a new perlin noise generator using the rand.Rand passed to
+// shuffle its permutation table and choose an offset.
+func newPerlin(r *rand.Rand) *perlin {
+	n := &perlin{xo: r.Float64() * 256, yo: r.Float64() * 256, zo: r.Float64() * 256}
+	for i := 0; i < 256; i++ {
+		n.p[i] = uint8(i)
+	}
+	for i := 255; i > 0; i-- {
+		j := r.Intn(i + 1)
+		n.p[i], n.p[j] = n.p[j], n.p[i]
+	}
+	copy(n.p[256:], n.p[:256])
+	return n
+}
+
+// noise3 returns the noise value at a point in three-dimensional space.
+func (n *perlin) noise3(x, y, z float64) float64 {
+	x, y, z = x+n.xo, y+n.yo, z+n.zo
+	fx, fy, fz := math.Floor(x), math.Floor(y), math.Floor(z)
+	xi, yi, zi := int(fx)&255, int(fy)&255, int(fz)&255
+	x, y, z = x-fx, y-fy, z-fz
+	u, v, w := fade(x), fade(y), fade(z)
+
+	a, b := int(n.p[xi])+yi, int(n.p[xi+1])+yi
+	aa, ab, ba, bb := int(n.p[a])+zi, int(n.p[a+1])+zi, int(n.p[b])+zi, int(n.p[b+1])+zi
+
+	return lerp(w,
+		lerp(v,
+			lerp(u, grad3(n.p[aa], x, y, z), grad3(n.p[ba], x-1, y, z)),
+			lerp(u, grad3(n.p[ab], x, y-1, z), grad3(n.p[bb], x-1, y-1, z))),
+		lerp(v,
+			lerp(u, grad3(n.p[aa+1], x, y, z-1), grad3(n.p[ba+1], x-1, y, z-1)),
+			lerp(u, grad3(n.p[ab+1], x, y-1, z-1), grad3(n.p[bb+1], x-1, y-1, z-1))))
+}
+
+// noise2 returns the noise value at a point in two-dimensional space.
+func (n *perlin) noise2(x, z float64) float64 {
+	return n.noise3(x, 0, z)
+}
+
+// fade is the quintic smoothing function used by Perlin noise.
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+// lerp linearly interpolates between a and b using t.
+func lerp(t, a, b float64) float64 {
+	return a + t*(b-a)
+}
+
+// grad3 returns the dot product of a pseudo-random gradient vector selected
+// by the hash passed and the vector x, y, z.
+func grad3(hash uint8, x, y, z float64) float64 {
+	switch hash & 15 {
+	case 0, 12:
+		return x + y
+	case 1, 14:
+		return -x + y
+	case 2:
+		return x - y
+	case 3:
+		return -x - y
+	case 4:
+		return x + z
+	case 5:
+		return -x + z
+	case 6:
+		return x - z
+	case 7:
+		return -x - z
+	case 8:
+		return y + z
+	case 9, 13:
+		return -y + z
+	case 10:
+		return y - z
+	}
+	return -y - z
+}
+
+// octaveNoise sums multiple octaves of perlin noise, each with double the
+// frequency and half the amplitude of the previous one. The result is
+// normalised to roughly the range -1 to 1.
+type octaveNoise struct {
+	octaves []*perlin
+	scale   float64
+	norm    float64
+}
+
+// newOctaveNoise creates an octaveNoise with a number of octaves. The scale
+// passed is the frequency of the first octave. The seed and salt passed are
+// combined so that every noise of a generator is unique, but deterministic.
+func newOctaveNoise(seed, salt int64, octaves int, scale float64) octaveNoise {
+	r := rand.New(rand.NewSource(seed ^ salt*0x5deece66d))
+	n := octaveNoise{scale: scale, octaves: make([]*perlin, octaves)}
+	amplitude := 1.0
+	for i := range n.octaves {
+		n.octaves[i] = newPerlin(r)
+		n.norm += amplitude
+		amplitude /= 2
+	}
+	n.norm = 1 / n.norm
+	return n
+}
+
+// at3 returns the noise value at a point in three-dimensional space.
+func (n octaveNoise) at3(x, y, z float64) float64 {
+	x, y, z = x*n.scale, y*n.scale, z*n.scale
+	v, amplitude := 0.0, 1.0
+	for _, o := range n.octaves {
+		v += o.noise3(x, y, z) * amplitude
+		x, y, z, amplitude = x*2, y*2, z*2, amplitude/2
+	}
+	return v * n.norm
+}
+
+// at2 returns the noise value at a point in two-dimensional space.
+func (n octaveNoise) at2(x, z float64) float64 {
+	x, z = x*n.scale, z*n.scale
+	v, amplitude := 0.0, 1.0
+	for _, o := range n.octaves {
+		v += o.noise2(x, z) * amplitude
+		x, z, amplitude = x*2, z*2, amplitude/2
+	}
+	return v * n.norm
+}
+
+// spline is a piecewise linear function defined by a list of points sorted by
+// their x value.
+type spline [][2]float64
+
+// at returns the value of the spline at x. Values beyond the first and last
+// points are clamped.
+func (s spline) at(x float64) float64 {
+	if x <= s[0][0] {
+		return s[0][1]
+	}
+	for i := 1; i < len(s); i++ {
+		if x <= s[i][0] {
+			a, b := s[i-1], s[i]
+			return lerp((x-a[0])/(b[0]-a[0]), a[1], b[1])
+		}
+	}
+	return s[len(s)-1][1]
+}
+
+// clamp clamps x between a and b.
+func clamp(x, a, b float64) float64 {
+	return math.Max(a, math.Min(b, x))
+}
+
+// chunkRand returns a rand.Rand that is unique for a chunk, but deterministic
+// for the seed, chunk position and salt passed, so that features of a chunk
+// are the same regardless of the order in which chunks are generated.
+func chunkRand(seed int64, x, z int32, salt int64) *rand.Rand {
+	h := uint64(seed)
+	for _, v := range []uint64{uint64(uint32(x)), uint64(uint32(z)), uint64(salt)} {
+		h ^= v + 0x9e3779b97f4a7c15 + (h << 6) + (h >> 2)
+		h = (h ^ (h >> 30)) * 0xbf58476d1ce4e5b9
+		h = (h ^ (h >> 27)) * 0x94d049bb133111eb
+		h ^= h >> 31
+	}
+	return rand.New(rand.NewSource(int64(h)))
+}
+
+// positionRand returns a deterministic pseudo-random value between 0 and 1
+// for a position in the world, using the seed and salt passed.
+func positionRand(seed int64, x, y, z int, salt int64) float64 {
+	h := uint64(seed) ^ uint64(salt)*0x9e3779b97f4a7c15
+	h ^= uint64(int64(x)) * 0xbf58476d1ce4e5b9
+	h ^= uint64(int64(y)) * 0x94d049bb133111eb
+	h ^= uint64(int64(z)) * 0x2545f4914f6cdd1d
+	h = (h ^ (h >> 30)) * 0xbf58476d1ce4e5b9
+	h = (h ^ (h >> 27)) * 0x94d049bb133111eb
+	h ^= h >> 31
+	return float64(h>>11) / (1 << 53)
+}
@@ -0,0 +1,212 @@
+package generator
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/biome"
+	"github.com/stcraft/dragonfly/server/world/chunk"
+)
+
+// oreFeature describes the distribution of blobs of an ore, or of another
+// type of block, that replace the base blocks of terrain underground.
+type oreFeature struct {
+	// replace maps the runtime IDs of blocks that may be replaced by the ore
+	// to the runtime ID of the ore block that replaces them.
+	replace map[uint32]uint32
+	// size is the size of a single blob. The amount of blocks in a blob is
+	// roughly equal to its size.
+	size int
+	// count is the amount of blobs attempted per chunk. If count is 0, one
+	// blob is attempted with a chance of 1/rarity.
+	count, rarity int
+	// minY and maxY are the minimum and maximum y value of blobs. If
+	// triangle is true, blobs are more common in the middle of the range.
+	minY, maxY int
+	triangle   bool
+	// biomes is an optional function that filters the biomes in which the
+	// ore generates.
+	biomes func(b world.Biome) bool
+}
+
+// generateOres places the ores of the features passed in the chunk at the
+// position passed. Blobs of ores that originate in neighbouring chunks are
+// placed too, so that blobs crossing chunk borders are placed completely.
+// biomeAt returns the biome at a column and is only called for features with
+// a biome filter.
+func generateOres(seed int64, pos world.ChunkPos, c *chunk.Chunk, features []oreFeature, biomeAt func(x, z int) world.Biome) {
+	for dx := int32(-1); dx <= 1; dx++ {
+		for dz := int32(-1); dz <= 1; dz++ {
+			// Every feature draws the same random values regardless of the chunk
+			// being generated, so a single source may be shared by all of them.
+			r := chunkRand(seed, pos[0]+dx, pos[1]+dz, 1000)
+			for _, f := range features {
+				f.generate(r, pos, pos[0]+dx, pos[1]+dz, c, biomeAt)
+			}
+		}
+	}
+}
+
+// generate generates the blobs of the oreFeature that originate in the chunk
+// at the x and z passed, placing the blocks that are within the chunk at the
+// position passed.
+func (f oreFeature) generate(r *rand.Rand, pos world.ChunkPos, cx, cz int32, c *chunk.Chunk, biomeAt func(x, z int) world.Biome) {
+	count := f.count
+	if count == 0 && r.Intn(f.rarity) == 0 {
+		count = 1
+	}
+	for i := 0; i < count; i++ {
+		x, z := int(cx)<<4+r.Intn(16), int(cz)<<4+r.Intn(16)
+		var y int
+		if f.triangle {
+			half := (f.maxY - f.minY) / 2
+			y = f.minY + r.Intn(half+1) + r.Intn(half+1)
+		} else {
+			y = f.minY + r.Intn(f.maxY-f.minY+1)
+		}
+		angle, y1, y2, blobSeed := r.Float64()*math.Pi, y+r.Intn(3)-1, y+r.Intn(3)-1, r.Uint64()
+
+		spread := float64(f.size) / 8
+		reach := spread + float64(f.size)/16 + 2
+		minX, minZ := float64(pos[0])*16, float64(pos[1])*16
+		if float64(x)+reach < minX || float64(x)-reach >= minX+16 || float64(z)+reach < minZ || float64(z)-reach >= minZ+16 {
+			// The blob cannot reach the chunk that is being generated.
+			continue
+		}
+		if y < c.Range().Min() || y > c.Range().Max() {
+			continue
+		}
+		if f.biomes != nil && !f.biomes(biomeAt(x, z)) {
+			continue
+		}
+		f.place(c, pos, blob{
+			x1: float64(x) + math.Sin(angle)*spread, x2: float64(x) - math.Sin(angle)*spread,
+			z1: float64(z) + math.Cos(angle)*spread, z2: float64(z) - math.Cos(angle)*spread,
+			y1: float64(y1), y2: float64(y2), seed: blobSeed,
+		})
+	}
+}
+
+// blob is a single blob of ore: A line from one point to another, along
+// which spheres of varying size are filled.
+type blob struct {
+	x1, x2, y1, y2, z1, z2 float64
+	seed                   uint64
+}
+
+// place places the blocks of the blob passed that are within the chunk at the
+// position passed.
+func (f oreFeature) place(c *chunk.Chunk, pos world.ChunkPos, b blob) {
+	baseX, baseZ := int(pos[0])<<4, int(pos[1])<<4
+	h := b.seed
+	for i := 0; i < f.size; i++ {
+		t := float64(i) / float64(f.size)
+		cx, cy, cz := lerp(t, b.x1, b.x2), lerp(t, b.y1, b.y2), lerp(t, b.z1, b.z2)
+
+		h = splitMix(h)
+		d := float64(h>>11) / (1 << 53) * float64(f.size) / 16
+		radius := ((math.Sin(math.Pi*t)+1)*d + 1) / 2
+
+		for x := int(math.Floor(cx - radius)); x <= int(math.Floor(cx+radius)); x++ {
+			if x < baseX || x >= baseX+16 {
+				continue
+			}
+			for z := int(math.Floor(cz - radius)); z <= int(math.Floor(cz+radius)); z++ {
+				if z < baseZ || z >= baseZ+16 {
+					continue
+				}
+				for y := int(math.Floor(cy - radius)); y <= int(math.Floor(cy+radius)); y++ {
+					if y < c.Range().Min() || y > c.Range().Max() {
+						continue
+					}
+					dx, dy, dz := (float64(x)+0.5-cx)/radius, (float64(y)+0.5-cy)/radius, (float64(z)+0.5-cz)/radius
+					if dx*dx+dy*dy+dz*dz >= 1 {
+						continue
+					}
+					lx, lz := uint8(x-baseX), uint8(z-baseZ)
+					if ore, ok := f.replace[c.Block(lx, int16(y), lz, 0)]; ok {
+						c.SetBlock(lx, int16(y), lz, 0, ore)
+					}
+				}
+			}
+		}
+	}
+}
+
+// splitMix advances the splitmix64 state passed and returns the next value.
+func splitMix(h uint64) uint64 {
+	h += 0x9e3779b97f4a7c15
+	h = (h ^ (h >> 30)) * 0xbf58476d1ce4e5b9
+	h = (h ^ (h >> 27)) * 0x94d049bb133111eb
+	return h ^ (h >> 31)
+}
+
+// overworldOres returns the ore features generated in the overworld. The
+// distribution of these features is similar to that of vanilla.
+func overworldOres() []oreFeature {
+	stone, deepslate := world.BlockRuntimeID(block.Stone{}), world.BlockRuntimeID(block.Deepslate{Type: block.NormalDeepslate(), Axis: cube.Y})
+	ore := func(stoneOre, deepslateOre world.Block) map[uint32]uint32 {
+		return map[uint32]uint32{stone: world.BlockRuntimeID(stoneOre), deepslate: world.BlockRuntimeID(deepslateOre)}
+	}
+	rock := func(b world.Block) map[uint32]uint32 {
+		return map[uint32]uint32{stone: world.BlockRuntimeID(b)}
+	}
+	coal := ore(block.CoalOre{Type: block.StoneOre()}, block.CoalOre{Type: block.DeepslateOre()})
+	iron := ore(block.IronOre{Type: block.StoneOre()}, block.IronOre{Type: block.DeepslateOre()})
+	copperOre := ore(block.CopperOre{Type: block.StoneOre()}, block.CopperOre{Type: block.DeepslateOre()})
+	gold := ore(block.GoldOre{Type: block.StoneOre()}, block.GoldOre{Type: block.DeepslateOre()})
+	lapis := ore(block.LapisOre{Type: block.StoneOre()}, block.LapisOre{Type: block.DeepslateOre()})
+	diamond := ore(block.DiamondOre{Type: block.StoneOre()}, block.DiamondOre{Type: block.DeepslateOre()})
+	emerald := ore(block.EmeraldOre{Type: block.StoneOre()}, block.EmeraldOre{Type: block.DeepslateOre()})
+	tuff := world.BlockRuntimeID(block.Tuff{})
+
+	return []oreFeature{
+		{replace: rock(block.Dirt{}), size: 33, count: 7, minY: 0, maxY: 160},
+		{replace: rock(block.Gravel{}), size: 33, count: 14, minY: -64, maxY: 320},
+		{replace: rock(block.Granite{}), size: 64, rarity: 6, minY: 64, maxY: 128},
+		{replace: rock(block.Granite{}), size: 64, count: 2, minY: 0, maxY: 60},
+		{replace: rock(block.Diorite{}), size: 64, rarity: 6, minY: 64, maxY: 128},
+		{replace: rock(block.Diorite{}), size: 64, count: 2, minY: 0, maxY: 60},
+		{replace: rock(block.Andesite{}), size: 64, rarity: 6, minY: 64, maxY: 128},
+		{replace: rock(block.Andesite{}), size: 64, count: 2, minY: 0, maxY: 60},
+		{replace: map[uint32]uint32{stone: tuff, deepslate: tuff}, size: 64, count: 2, minY: -64, maxY: 0},
+
+		{replace: coal, size: 17, count: 30, minY: 136, maxY: 320},
+		{replace: coal, size: 17, count: 20, minY: 0, maxY: 192, triangle: true},
+		{replace: iron, size: 9, count: 90, minY: 80, maxY: 384, triangle: true},
+		{replace: iron, size: 9, count: 10, minY: -24, maxY: 56, triangle: true},
+		{replace: iron, size: 4, count: 10, minY: -64, maxY: 72},
+		{replace: copperOre, size: 10, count: 16, minY: -16, maxY: 112, triangle: true},
+		{replace: gold, size: 9, count: 4, minY: -64, maxY: 32, triangle: true},
+		{replace: gold, size: 9, count: 50, minY: 32, maxY: 256, biomes: badlands},
+		{replace: lapis, size: 7, count: 2, minY: -32, maxY: 32, triangle: true},
+		{replace: lapis, size: 7, count: 4, minY: -64, maxY: 64},
+		{replace: diamond, size: 4, count: 7, minY: -144, maxY: 16, triangle: true},
+		{replace: diamond, size: 12, rarity: 9, minY: -144, maxY: 16, triangle: true},
+		{replace: emerald, size: 3, count: 100, minY: -16, maxY: 480, triangle: true, biomes: mountains},
+	}
+}
+
+// badlands checks if the biome passed is one of the badlands biomes.
+func badlands(b world.Biome) bool {
+	switch b.(type) {
+	case biome.Badlands, biome.BadlandsPlateau, biome.ErodedBadlands, biome.ModifiedBadlandsPlateau,
+		biome.WoodedBadlandsPlateau, biome.ModifiedWoodedBadlandsPlateau:
+		return true
+	}
+	return false
+}
+
+// mountains checks if the biome passed is one of the mountain biomes.
+func mountains(b world.Biome) bool {
+	switch b.(type) {
+	case biome.JaggedPeaks, biome.FrozenPeaks, biome.StonyPeaks, biome.SnowySlopes, biome.Grove, biome.Meadow,
+		biome.CherryGrove, biome.WindsweptHills, biome.WindsweptForest, biome.WindsweptGravellyHills,
+		biome.GravellyMountainsPlus, biome.MountainEdge, biome.TaigaMountains, biome.SnowyTaigaMountains:
+		return true
+	}
+	return false
+}
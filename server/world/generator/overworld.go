@@ -0,0 +1,398 @@
+package generator
+
+import (
+	"math"
+
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/chunk"
+)
+
+const (
+	// seaLevel is the highest y value at which oceans and rivers are filled
+	// with water.
+	seaLevel = 62
+	// lavaLevel is the y value below which caves are filled with lava.
+	lavaLevel = -55
+	// cellWidth and cellHeight are the horizontal and vertical distance
+	// between the points at which terrain density is sampled. The density
+	// of blocks in between is interpolated.
+	cellWidth, cellHeight = 4, 8
+)
+
+// Overworld is a world.Generator that generates terrain similar to that of the
+// vanilla overworld. The shape of the terrain and the biomes are derived from
+// several climate noises, such as continentalness, erosion and weirdness.
+// Terrain is carved by caves, which may hold aquifers, and is decorated with
+// ores, trees and plants. An Overworld generator is deterministic: For the
+// same seed, every chunk is always generated in exactly the same way,
+// regardless of the order in which chunks are generated. Overworld may be
+// used by multiple goroutines at the same time. It may be constructed by
+// calling NewOverworld.
+type Overworld struct {
+	seed int64
+
+	continentalness, erosion, weirdness octaveNoise
+	temperature, humidity               octaveNoise
+	terrain                             octaveNoise
+	cheese, spaghettiA, spaghettiB      octaveNoise
+	aquifer, surface                    octaveNoise
+
+	air, stone, deepslate, bedrock, water, lava uint32
+	surfaceBlocks
+	decorationBlocks
+	ores []oreFeature
+}
+
+// NewOverworld creates a new Overworld generator that generates terrain
+// using the seed passed.
+func NewOverworld(seed int64) *Overworld {
+	return &Overworld{
+		seed:            seed,
+		continentalness: newOctaveNoise(seed, 1, 6, 1.0/900),
+		erosion:         newOctaveNoise(seed, 2, 5, 1.0/420),
+		weirdness:       newOctaveNoise(seed, 3, 4, 1.0/300),
+		temperature:     newOctaveNoise(seed, 4, 4, 1.0/700),
+		humidity:        newOctaveNoise(seed, 5, 4, 1.0/560),
+		terrain:         newOctaveNoise(seed, 6, 4, 1.0/96),
+		cheese:          newOctaveNoise(seed, 7, 2, 1.0/72),
+		spaghettiA:      newOctaveNoise(seed, 8, 2, 1.0/56),
+		spaghettiB:      newOctaveNoise(seed, 9, 2, 1.0/56),
+		aquifer:         newOctaveNoise(seed, 10, 2, 1.0/48),
+		surface:         newOctaveNoise(seed, 11, 3, 1.0/24),
+
+		air:       world.BlockRuntimeID(block.Air{}),
+		stone:     world.BlockRuntimeID(block.Stone{}),
+		deepslate: world.BlockRuntimeID(block.Deepslate{Type: block.NormalDeepslate(), Axis: cube.Y}),
+		bedrock:   world.BlockRuntimeID(block.Bedrock{}),
+		water:     world.BlockRuntimeID(block.Water{Still: true, Depth: 8}),
+		lava:      world.BlockRuntimeID(block.Lava{Still: true, Depth: 8}),
+
+		surfaceBlocks:    newSurfaceBlocks(),
+		decorationBlocks: newDecorationBlocks(),
+		ores:             overworldOres(),
+	}
+}
+
+// Seed returns the seed of the Overworld generator.
+func (o *Overworld) Seed() int64 {
+	return o.seed
+}
+
+// GenerateChunk ...
+func (o *Overworld) GenerateChunk(pos world.ChunkPos, c *chunk.Chunk) {
+	g := o.newGeneration(c.Range())
+	baseX, baseZ := int(pos[0])<<4, int(pos[1])<<4
+
+	var cols [16][16]surfaceColumn
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			cols[x][z] = o.fillColumn(g, c, baseX+x, baseZ+z, uint8(x), uint8(z))
+		}
+	}
+	generateOres(o.seed, pos, c, o.ores, func(x, z int) world.Biome {
+		return o.climate(float64(x), float64(z)).biome()
+	})
+	o.decorate(g, pos, c, cols)
+}
+
+// surfaceColumn holds the y value of the highest solid block of a column and
+// the biome at its surface.
+type surfaceColumn struct {
+	top   int
+	biome world.Biome
+}
+
+// fillColumn fills a single column of the chunk passed with terrain, liquids,
+// surface blocks and biomes.
+func (o *Overworld) fillColumn(g *generation, c *chunk.Chunk, x, z int, cx, cz uint8) surfaceColumn {
+	r := c.Range()
+	col := g.columnSampler(o, x, z)
+	cl := o.climate(float64(x), float64(z))
+	surfaceBiome := cl.biome()
+	steep, n := col.steep(), o.surface.at2(float64(x), float64(z))
+
+	top, depth, surfaceDone := r.Min()-1, -1, false
+	for y := col.maxY(); y >= r.Min(); y-- {
+		terrain, carve := col.at(y)
+		rid := o.air
+		switch {
+		case terrain > 0 && carve <= 0:
+			if depth < 0 && !surfaceDone {
+				top, depth = y, 0
+			}
+			rid = o.stoneAt(x, y, z)
+			if !surfaceDone {
+				rid, surfaceDone = o.surfaceBlock(surfaceBiome, x, y, z, top, depth, steep, n, rid)
+				depth++
+			}
+		case terrain > 0:
+			surfaceDone = surfaceDone || depth >= 0
+			rid = o.cave(g, x, y, z)
+		case y <= seaLevel:
+			surfaceDone = surfaceDone || depth >= 0
+			rid = o.water
+		default:
+			surfaceDone = surfaceDone || depth >= 0
+		}
+		if y <= r.Min()+4 && o.bedrockAt(x, y, z, r.Min()) {
+			rid = o.bedrock
+		}
+		if rid != o.air {
+			c.SetBlock(cx, int16(y), cz, 0, rid)
+		}
+	}
+
+	surfaceID := uint32(surfaceBiome.EncodeBiome())
+	for y := r.Min(); y <= r.Max(); y++ {
+		id := surfaceID
+		if y < top-24 {
+			if b, ok := cl.caveBiome(y); ok {
+				id = uint32(b.EncodeBiome())
+			}
+		}
+		c.SetBiome(cx, int16(y), cz, id)
+	}
+	return surfaceColumn{top: top, biome: surfaceBiome}
+}
+
+// stoneAt returns the base stone block at a position: Stone above y=0 and
+// deepslate below, with a gradual transition between the two.
+func (o *Overworld) stoneAt(x, y, z int) uint32 {
+	if y >= 0 {
+		return o.stone
+	}
+	if y <= -8 || o.positionRand(x, y, z, 1) < float64(-y)/8 {
+		return o.deepslate
+	}
+	return o.stone
+}
+
+// bedrockAt checks if bedrock should be placed at a position near the bottom
+// of the world. The bottom layer is always bedrock, while the layers above it
+// hold less and less bedrock.
+func (o *Overworld) bedrockAt(x, y, z, min int) bool {
+	return y == min || o.positionRand(x, y, z, 2) < 1-float64(y-min)/5
+}
+
+// cave returns the block placed at a position carved out by a cave. Caves
+// deep underground are filled with lava, while some caves hold aquifers
+// filled with water up to a certain level.
+func (o *Overworld) cave(g *generation, x, y, z int) uint32 {
+	if y <= lavaLevel {
+		return o.lava
+	}
+	if level, ok := g.aquiferLevel(o, x, y, z); ok && y <= level {
+		return o.water
+	}
+	return o.air
+}
+
+// positionRand returns a deterministic pseudo-random value between 0 and 1
+// for a position in the world.
+func (o *Overworld) positionRand(x, y, z int, salt int64) float64 {
+	return positionRand(o.seed, x, y, z, salt)
+}
+
+// climate holds the climate parameters of a column in the world. These
+// parameters determine the shape of the terrain and the biome of the column.
+type climate struct {
+	temperature, humidity                   float64
+	continentalness, erosion, weirdness, pv float64
+}
+
+// climate returns the climate of the column at the x and z passed.
+func (o *Overworld) climate(x, z float64) climate {
+	c := climate{
+		temperature:     clamp(o.temperature.at2(x, z)*2.2, -1, 1),
+		humidity:        clamp(o.humidity.at2(x, z)*2.2, -1, 1),
+		continentalness: clamp(o.continentalness.at2(x, z)*2.4+0.08, -1.2, 1),
+		erosion:         clamp(o.erosion.at2(x, z)*2.2, -1, 1),
+		weirdness:       clamp(o.weirdness.at2(x, z)*2.2, -1, 1),
+	}
+	// Peaks and valleys are derived from the weirdness, so that rivers (at a
+	// weirdness of 0) form at the bottom of valleys.
+	c.pv = 1 - math.Abs(3*math.Abs(c.weirdness)-2)
+	return c
+}
+
+var (
+	// continentalHeight maps continentalness to the base height of terrain.
+	continentalHeight = spline{{-1.2, 16}, {-1.05, 30}, {-0.455, 36}, {-0.2, 48}, {-0.19, 56}, {-0.11, 63}, {0.03, 66}, {0.3, 72}, {1, 84}}
+	// erosionFactor maps erosion to the extent to which mountains form.
+	erosionFactor = spline{{-1, 1}, {-0.78, 0.85}, {-0.375, 0.5}, {-0.2225, 0.28}, {0.05, 0.1}, {0.45, 0.03}, {0.55, 0}, {1, 0}}
+	// ridgeFactor maps peaks and valleys to the height of mountains.
+	ridgeFactor = spline{{-1, 0}, {-0.4, 0.15}, {0, 0.4}, {0.4, 0.75}, {1, 1}}
+)
+
+// shape returns the base height of terrain and the amplitude with which the
+// terrain deviates from that height in the column with the climate.
+func (c climate) shape() (height, amplitude float64) {
+	inland := clamp((c.continentalness+0.11)/0.41, 0, 1)
+	mountains := erosionFactor.at(c.erosion) * ridgeFactor.at(c.pv) * inland
+	hills := (c.pv + 1) / 2 * (1 - erosionFactor.at(c.erosion)) * inland
+
+	height = continentalHeight.at(c.continentalness) + 170*mountains + 10*hills
+	if c.continentalness > -0.19 {
+		// Carve rivers into the terrain where the weirdness is close to 0.
+		river := clamp((0.08-math.Abs(c.weirdness))/0.05, 0, 1)
+		height = lerp(river, height, seaLevel-5)
+	}
+	return height, 3 + 32*mountains + 2*(1-inland)
+}
+
+// generation holds the state of the generation of a single chunk. It caches
+// the densities sampled at the corners of cells, so that positions in the
+// same cell, or in neighbouring chunks, do not require sampling noise again.
+type generation struct {
+	r       cube.Range
+	columns map[[2]int]*densityColumn
+	aquifer map[[3]int]int
+}
+
+// newGeneration creates a generation for a chunk with the range passed.
+func (o *Overworld) newGeneration(r cube.Range) *generation {
+	return &generation{r: r, columns: make(map[[2]int]*densityColumn, 36), aquifer: make(map[[3]int]int)}
+}
+
+// densityColumn holds the densities sampled at the corners of cells in a
+// single column.
+type densityColumn struct {
+	height, amplitude float64
+	terrain, carve    []float64
+}
+
+// column returns the densityColumn at the x and z passed, which must be
+// multiples of cellWidth.
+func (g *generation) column(o *Overworld, x, z int) *densityColumn {
+	if col, ok := g.columns[[2]int{x, z}]; ok {
+		return col
+	}
+	n := (g.r.Max()+1-g.r.Min())/cellHeight + 1
+	col := &densityColumn{terrain: make([]float64, n), carve: make([]float64, n)}
+	col.height, col.amplitude = o.climate(float64(x), float64(z)).shape()
+
+	for i := 0; i < n; i++ {
+		y := float64(g.r.Min() + i*cellHeight)
+		col.terrain[i] = (col.height-y)/col.amplitude + o.terrain.at3(float64(x), y*1.5, float64(z))*2.4
+		col.carve[i] = o.carve(float64(x), y, float64(z), col.height, g.r)
+	}
+	g.columns[[2]int{x, z}] = col
+	return col
+}
+
+// carve returns the extent to which a position is carved out by caves. A
+// value above 0 means the position is part of a cave.
+func (o *Overworld) carve(x, y, z, height float64, r cube.Range) float64 {
+	below := height - y
+	if y <= float64(r.Min()+4) || below < 2 {
+		return -1
+	}
+	// Large caverns only form well below the surface, while thin tunnels may
+	// form entrances to caves at the surface, except below oceans.
+	cheese := o.cheese.at3(x, y*1.6, z)*2.6 - 0.62 - (1-clamp((below-8)/16, 0, 1))*2
+	a, b := o.spaghettiA.at3(x, y*1.4, z), o.spaghettiB.at3(x, y*1.4, z)
+	spaghetti := (0.05 - math.Hypot(a, b)) * 16
+	if height < seaLevel {
+		spaghetti -= (1 - clamp((below-6)/6, 0, 1)) * 2
+	}
+	return math.Max(cheese, spaghetti)
+}
+
+// columnSampler interpolates the densities of the four densityColumns at the
+// corners of the cell that a column is in.
+type columnSampler struct {
+	r                  cube.Range
+	c00, c10, c01, c11 *densityColumn
+	fx, fz             float64
+}
+
+// columnSampler returns a columnSampler for the column at the x and z passed.
+func (g *generation) columnSampler(o *Overworld, x, z int) columnSampler {
+	x0, z0 := floorDiv(x, cellWidth)*cellWidth, floorDiv(z, cellWidth)*cellWidth
+	return columnSampler{
+		r:   g.r,
+		c00: g.column(o, x0, z0), c10: g.column(o, x0+cellWidth, z0),
+		c01: g.column(o, x0, z0+cellWidth), c11: g.column(o, x0+cellWidth, z0+cellWidth),
+		fx: float64(x-x0) / cellWidth, fz: float64(z-z0) / cellWidth,
+	}
+}
+
+// at returns the terrain density and the carve value at the y passed. A
+// terrain density above 0 means the position is solid, unless the carve value
+// is also above 0.
+func (s columnSampler) at(y int) (terrain, carve float64) {
+	i, fy := (y-s.r.Min())/cellHeight, float64((y-s.r.Min())%cellHeight)/cellHeight
+	return s.interpolate(func(c *densityColumn) []float64 { return c.terrain }, i, fy),
+		s.interpolate(func(c *densityColumn) []float64 { return c.carve }, i, fy)
+}
+
+// interpolate trilinearly interpolates the values returned by f for the
+// corners of the cell.
+func (s columnSampler) interpolate(f func(c *densityColumn) []float64, i int, fy float64) float64 {
+	v00, v10, v01, v11 := f(s.c00), f(s.c10), f(s.c01), f(s.c11)
+	return lerp(fy,
+		lerp(s.fz, lerp(s.fx, v00[i], v10[i]), lerp(s.fx, v01[i], v11[i])),
+		lerp(s.fz, lerp(s.fx, v00[i+1], v10[i+1]), lerp(s.fx, v01[i+1], v11[i+1])))
+}
+
+// maxY returns the highest y value at which the column could be solid. All
+// blocks above it are always air.
+func (s columnSampler) maxY() int {
+	top := math.Inf(-1)
+	for _, c := range [...]*densityColumn{s.c00, s.c10, s.c01, s.c11} {
+		top = math.Max(top, c.height+c.amplitude*2.4)
+	}
+	return min(s.r.Max(), max(int(top)+cellHeight, seaLevel))
+}
+
+// steep checks if the terrain of the column is steep, based on the heights at
+// the corners of its cell.
+func (s columnSampler) steep() bool {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, c := range [...]*densityColumn{s.c00, s.c10, s.c01, s.c11} {
+		lo, hi = math.Min(lo, c.height), math.Max(hi, c.height)
+	}
+	return (hi-lo)/cellWidth > 1.2
+}
+
+// surface returns the y value of the highest solid block in the column, and
+// false if that block was carved out by a cave. If the column has no solid
+// blocks, the minimum y value minus one is returned.
+func (s columnSampler) surface() (int, bool) {
+	for y := s.maxY(); y >= s.r.Min(); y-- {
+		if terrain, carve := s.at(y); terrain > 0 {
+			return y, carve <= 0
+		}
+	}
+	return s.r.Min() - 1, false
+}
+
+// aquiferLevel returns the level up to which the cave at a position is
+// filled with water. False is returned if the cave holds no aquifer.
+// Aquifers are determined per cell of 16x12x16 blocks.
+func (g *generation) aquiferLevel(o *Overworld, x, y, z int) (int, bool) {
+	if y >= seaLevel-8 {
+		return 0, false
+	}
+	key := [3]int{floorDiv(x, 16), floorDiv(y, 12), floorDiv(z, 16)}
+	level, ok := g.aquifer[key]
+	if !ok {
+		level = math.MinInt
+		cx, cy, cz := key[0]*16+8, key[1]*12+6, key[2]*16+8
+		if o.aquifer.at3(float64(cx), float64(cy), float64(cz))*2 > 0.22 {
+			level = key[1]*12 + 3 + int(o.positionRand(key[0], key[1], key[2], 3)*8)
+		}
+		g.aquifer[key] = level
+	}
+	return level, level != math.MinInt
+}
+
+// floorDiv divides a by b, rounding towards negative infinity.
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return -((-a + b - 1) / b)
+	}
+	return a / b
+}
@@ -0,0 +1,280 @@
+package generator
+
+import (
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/biome"
+)
+
+// The tables below select biomes based on the temperature (rows) and humidity
+// (columns) of a column, similarly to the way vanilla selects biomes. A nil
+// entry in a variant table means the regular biome is used.
+var (
+	deepOceans   = [5]world.Biome{biome.DeepFrozenOcean{}, biome.DeepColdOcean{}, biome.DeepOcean{}, biome.DeepLukewarmOcean{}, biome.DeepWarmOcean{}}
+	oceans       = [5]world.Biome{biome.FrozenOcean{}, biome.ColdOcean{}, biome.Ocean{}, biome.LukewarmOcean{}, biome.WarmOcean{}}
+	middleBiomes = [5][5]world.Biome{
+		{biome.SnowyPlains{}, biome.SnowyPlains{}, biome.SnowyPlains{}, biome.SnowyTaiga{}, biome.Taiga{}},
+		{biome.Plains{}, biome.Plains{}, biome.Forest{}, biome.Taiga{}, biome.OldGrowthSpruceTaiga{}},
+		{biome.FlowerForest{}, biome.Plains{}, biome.Forest{}, biome.BirchForest{}, biome.DarkForest{}},
+		{biome.Savanna{}, biome.Savanna{}, biome.Forest{}, biome.Jungle{}, biome.Jungle{}},
+		{biome.Desert{}, biome.Desert{}, biome.Desert{}, biome.Desert{}, biome.Desert{}},
+	}
+	middleVariants = [5][5]world.Biome{
+		{biome.IceSpikes{}, nil, biome.SnowyTaiga{}, nil, nil},
+		{nil, nil, nil, nil, biome.OldGrowthPineTaiga{}},
+		{biome.SunflowerPlains{}, nil, nil, biome.OldGrowthBirchForest{}, nil},
+		{nil, nil, biome.Plains{}, biome.JungleEdge{}, biome.BambooJungle{}},
+		{biome.DesertLakes{}, nil, nil, nil, nil},
+	}
+	plateauBiomes = [5][5]world.Biome{
+		{biome.SnowyPlains{}, biome.SnowyPlains{}, biome.SnowyPlains{}, biome.SnowyTaiga{}, biome.SnowyTaiga{}},
+		{biome.Meadow{}, biome.Meadow{}, biome.Forest{}, biome.Taiga{}, biome.OldGrowthSpruceTaiga{}},
+		{biome.Meadow{}, biome.Meadow{}, biome.Meadow{}, biome.Meadow{}, biome.DarkForest{}},
+		{biome.SavannaPlateau{}, biome.SavannaPlateau{}, biome.Forest{}, biome.Forest{}, biome.Jungle{}},
+		{biome.Badlands{}, biome.Badlands{}, biome.BadlandsPlateau{}, biome.WoodedBadlandsPlateau{}, biome.WoodedBadlandsPlateau{}},
+	}
+	plateauVariants = [5][5]world.Biome{
+		{biome.IceSpikes{}, nil, nil, nil, nil},
+		{biome.CherryGrove{}, nil, biome.Meadow{}, biome.Meadow{}, biome.OldGrowthPineTaiga{}},
+		{biome.CherryGrove{}, biome.CherryGrove{}, biome.Forest{}, biome.BirchForest{}, nil},
+		{nil, nil, nil, nil, nil},
+		{biome.ErodedBadlands{}, biome.ErodedBadlands{}, nil, nil, nil},
+	}
+	shatteredBiomes = [5][5]world.Biome{
+		{biome.WindsweptGravellyHills{}, biome.WindsweptGravellyHills{}, biome.WindsweptHills{}, biome.WindsweptForest{}, biome.WindsweptForest{}},
+		{biome.WindsweptGravellyHills{}, biome.WindsweptGravellyHills{}, biome.WindsweptHills{}, biome.WindsweptForest{}, biome.WindsweptForest{}},
+		{biome.WindsweptHills{}, biome.WindsweptHills{}, biome.WindsweptHills{}, biome.WindsweptForest{}, biome.WindsweptForest{}},
+		{nil, nil, nil, nil, nil},
+		{nil, nil, nil, nil, nil},
+	}
+	shatteredVariants = [5][5]world.Biome{
+		{biome.GravellyMountainsPlus{}, biome.GravellyMountainsPlus{}, biome.SnowyTaigaMountains{}, biome.SnowyTaigaMountains{}, biome.SnowyTaigaMountains{}},
+		{biome.GravellyMountainsPlus{}, biome.MountainEdge{}, biome.MountainEdge{}, biome.TaigaMountains{}, biome.TaigaMountains{}},
+		{nil, nil, biome.MountainEdge{}, nil, nil},
+		{nil, nil, nil, nil, nil},
+		{nil, nil, nil, nil, nil},
+	}
+	// hillVariants holds the hilly variants of biomes, used in areas that are
+	// moderately eroded and high up.
+	hillVariants = map[world.Biome]world.Biome{
+		biome.Forest{}:                biome.WoodedHills{},
+		biome.Taiga{}:                 biome.TaigaHills{},
+		biome.BirchForest{}:           biome.BirchForestHills{},
+		biome.OldGrowthBirchForest{}:  biome.TallBirchHills{},
+		biome.DarkForest{}:            biome.DarkForestHills{},
+		biome.Jungle{}:                biome.JungleHills{},
+		biome.JungleEdge{}:            biome.ModifiedJungleEdge{},
+		biome.BambooJungle{}:          biome.BambooJungleHills{},
+		biome.Desert{}:                biome.DesertHills{},
+		biome.SnowyTaiga{}:            biome.SnowyTaigaHills{},
+		biome.OldGrowthSpruceTaiga{}:  biome.GiantSpruceTaigaHills{},
+		biome.OldGrowthPineTaiga{}:    biome.GiantTreeTaigaHills{},
+		biome.Swamp{}:                 biome.SwampHills{},
+		biome.Badlands{}:              biome.ModifiedBadlandsPlateau{},
+		biome.WoodedBadlandsPlateau{}: biome.ModifiedWoodedBadlandsPlateau{},
+		biome.SavannaPlateau{}:        biome.ShatteredSavannaPlateau{},
+		biome.SnowyPlains{}:           biome.SnowyMountains{},
+	}
+)
+
+// index returns the index of the value passed in the sorted list of
+// thresholds.
+func index(v float64, thresholds ...float64) int {
+	for i, t := range thresholds {
+		if v < t {
+			return i
+		}
+	}
+	return len(thresholds)
+}
+
+// biome returns the biome at the surface of a column with the climate.
+func (c climate) biome() world.Biome {
+	t := index(c.temperature, -0.45, -0.15, 0.2, 0.55)
+	h := index(c.humidity, -0.35, -0.1, 0.1, 0.3)
+	e := index(c.erosion, -0.78, -0.375, -0.2225, 0.05, 0.45, 0.55)
+	weird := c.weirdness > 0
+
+	switch {
+	case c.continentalness < -1.05:
+		if c.continentalness < -1.1 {
+			return biome.MushroomFields{}
+		}
+		return biome.MushroomFieldShore{}
+	case c.continentalness < -0.455:
+		return deepOceans[t]
+	case c.continentalness < -0.19:
+		return oceans[t]
+	}
+	coast, far := c.continentalness < -0.11, c.continentalness >= 0.3
+	inland := c.continentalness >= 0.03
+
+	switch {
+	case c.pv < -0.85:
+		// Valleys: Rivers, unless the terrain is strongly eroded mountains.
+		if e >= 2 || coast {
+			if e == 6 && inland && t > 0 {
+				return c.swamp(t)
+			}
+			if t == 0 {
+				return biome.FrozenRiver{}
+			}
+			return biome.River{}
+		}
+		return c.middle(t, h, weird)
+	case coast:
+		switch {
+		case e <= 2 && c.pv > 0.2:
+			return biome.StonyShore{}
+		case e >= 3:
+			return c.beach(t)
+		}
+		return c.middle(t, h, weird)
+	case c.pv > 0.7:
+		// Peaks.
+		switch {
+		case e <= 1 && inland:
+			return c.peak(t, weird)
+		case e <= 2:
+			return c.slope(t, h, weird)
+		case e == 5 && far:
+			return c.shattered(t, h, weird)
+		case e <= 3:
+			return c.plateau(t, h, weird)
+		}
+		return c.middle(t, h, weird)
+	case c.pv > 0.2:
+		// High terrain.
+		switch {
+		case e == 0 && inland:
+			return c.peak(t, weird)
+		case e <= 1:
+			return c.slope(t, h, weird)
+		case e <= 3:
+			return c.plateau(t, h, weird)
+		case e == 4:
+			return c.hills(c.middle(t, h, weird))
+		case e == 5 && inland:
+			return c.shattered(t, h, weird)
+		}
+		return c.middle(t, h, weird)
+	case c.pv > -0.6:
+		// Mid-height terrain.
+		switch {
+		case e == 0 && far:
+			return c.slope(t, h, weird)
+		case e <= 3 && inland:
+			return c.plateau(t, h, weird)
+		case e == 5 && far:
+			return c.shattered(t, h, weird)
+		case e == 6 && t > 0 && t < 4:
+			return c.swamp(t)
+		}
+		return c.middle(t, h, weird)
+	}
+	// Low terrain.
+	if e == 6 && t > 0 && t < 4 {
+		return c.swamp(t)
+	}
+	return c.middle(t, h, weird)
+}
+
+// middle returns the regular biome for a temperature and humidity.
+func (c climate) middle(t, h int, weird bool) world.Biome {
+	if weird && middleVariants[t][h] != nil {
+		return middleVariants[t][h]
+	}
+	return middleBiomes[t][h]
+}
+
+// plateau returns the biome of high, flat terrain for a temperature and
+// humidity.
+func (c climate) plateau(t, h int, weird bool) world.Biome {
+	if weird && plateauVariants[t][h] != nil {
+		return plateauVariants[t][h]
+	}
+	return plateauBiomes[t][h]
+}
+
+// shattered returns the biome of windswept terrain for a temperature and
+// humidity.
+func (c climate) shattered(t, h int, weird bool) world.Biome {
+	if weird && shatteredVariants[t][h] != nil {
+		return shatteredVariants[t][h]
+	}
+	if b := shatteredBiomes[t][h]; b != nil {
+		return b
+	}
+	if t == 3 {
+		return biome.WindsweptSavanna{}
+	}
+	return c.plateau(t, h, weird)
+}
+
+// hills returns the hilly variant of the biome passed, if it has one.
+func (c climate) hills(b world.Biome) world.Biome {
+	if v, ok := hillVariants[b]; ok {
+		return v
+	}
+	if _, ok := b.(biome.Jungle); ok && c.weirdness > 0.5 {
+		return biome.ModifiedJungle{}
+	}
+	return b
+}
+
+// slope returns the biome of mountain slopes for a temperature and humidity.
+func (c climate) slope(t, h int, weird bool) world.Biome {
+	if t >= 3 {
+		return c.plateau(t, h, weird)
+	}
+	if h <= 1 {
+		return biome.SnowySlopes{}
+	}
+	return biome.Grove{}
+}
+
+// peak returns the biome of mountain peaks for a temperature.
+func (c climate) peak(t int, weird bool) world.Biome {
+	switch {
+	case t <= 2 && weird:
+		return biome.FrozenPeaks{}
+	case t <= 2:
+		return biome.JaggedPeaks{}
+	case t == 3:
+		return biome.StonyPeaks{}
+	}
+	return biome.ErodedBadlands{}
+}
+
+// beach returns the beach biome for a temperature.
+func (c climate) beach(t int) world.Biome {
+	switch t {
+	case 0:
+		return biome.SnowyBeach{}
+	case 4:
+		return biome.Desert{}
+	}
+	return biome.Beach{}
+}
+
+// swamp returns the swamp biome for a temperature.
+func (c climate) swamp(t int) world.Biome {
+	if t >= 3 {
+		return biome.MangroveSwamp{}
+	}
+	return biome.Swamp{}
+}
+
+// caveBiome returns the biome of caves deep below the surface of a column
+// with the climate, at the y passed. False is returned if the surface biome
+// extends down to this depth.
+func (c climate) caveBiome(y int) (world.Biome, bool) {
+	switch {
+	case y < -16 && c.erosion < -0.375 && c.continentalness > -0.11:
+		return biome.DeepDark{}, true
+	case c.humidity > 0.7:
+		return biome.LushCaves{}, true
+	case c.continentalness > 0.8:
+		return biome.DripstoneCaves{}, true
+	}
+	return nil, false
+}
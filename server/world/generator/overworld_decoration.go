@@ -0,0 +1,581 @@
+package generator
+
+import (
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/biome"
+	"github.com/stcraft/dragonfly/server/world/chunk"
+)
+
+const (
+	// treeAttempts is the amount of trees attempted per chunk. The density of
+	// trees in a biome determines how many of these attempts succeed.
+	treeAttempts = 32
+	// treeReach is the maximum horizontal distance between the origin of a
+	// tree and any of its blocks.
+	treeReach = 4
+)
+
+// treeKind is a shape of tree that may be generated.
+type treeKind uint8
+
+const (
+	oakTree treeKind = iota
+	birchTree
+	tallBirchTree
+	spruceTree
+	pineTree
+	jungleTree
+	acaciaTree
+	darkOakTree
+	cherryTree
+	mangroveTree
+)
+
+// vegetation describes the trees and plants that grow at the surface of a
+// biome.
+type vegetation struct {
+	// trees is the average amount of trees per chunk. The kinds of trees
+	// generated are picked from kinds, which may hold duplicates to make a
+	// kind more common.
+	trees float64
+	kinds []treeKind
+	// grass, flowers and doubles are the chances per column that short grass,
+	// a flower or a double plant grows. ferns is the fraction of the grass
+	// that is replaced with ferns.
+	grass, ferns, flowers, doubles float64
+	// flowerTypes and doubleTypes hold the runtime IDs of the flowers and
+	// double plants (lower and upper half) that may grow.
+	flowerTypes []uint32
+	doubleTypes [][2]uint32
+	// cactus, deadBush and fruit are the chances per column of the plants
+	// growing. pumpkin holds the runtime ID of the fruit block: Either a
+	// pumpkin or a melon.
+	cactus, deadBush, fruit float64
+	pumpkin                 uint32
+}
+
+// decorationBlocks holds the runtime IDs of the blocks used to decorate the
+// surface of the terrain.
+type decorationBlocks struct {
+	shortGrass, fern, cactus, deadBush, sugarCane, pumpkin, melon uint32
+	tallGrass, largeFern, sunflower                               [2]uint32
+	plainsFlowers, forestFlowers, allFlowers, meadowFlowers       []uint32
+	swampFlowers                                                  []uint32
+	forestDoubles                                                 [][2]uint32
+	corals, kelp                                                  []uint32
+	seaPickle                                                     uint32
+	logs, leaves                                                  map[block.WoodType]uint32
+	leafSet                                                       map[uint32]struct{}
+}
+
+// newDecorationBlocks looks up the runtime IDs of all blocks used to decorate
+// the surface of the terrain.
+func newDecorationBlocks() decorationBlocks {
+	rid := world.BlockRuntimeID
+	flowers := func(types ...block.FlowerType) (s []uint32) {
+		for _, t := range types {
+			s = append(s, rid(block.Flower{Type: t}))
+		}
+		return s
+	}
+	double := func(t block.DoubleFlowerType) [2]uint32 {
+		return [2]uint32{rid(block.DoubleFlower{Type: t}), rid(block.DoubleFlower{Type: t, UpperPart: true})}
+	}
+	d := decorationBlocks{
+		shortGrass: rid(block.TallGrass{Type: block.NormalTallGrass()}),
+		fern:       rid(block.TallGrass{Type: block.FernTallGrass()}),
+		cactus:     rid(block.Cactus{}),
+		deadBush:   rid(block.DeadBush{}),
+		sugarCane:  rid(block.SugarCane{}),
+		pumpkin:    rid(block.Pumpkin{}),
+		melon:      rid(block.Melon{}),
+		seaPickle:  rid(block.SeaPickle{}),
+		tallGrass: [2]uint32{
+			rid(block.DoubleTallGrass{Type: block.NormalDoubleTallGrass()}),
+			rid(block.DoubleTallGrass{Type: block.NormalDoubleTallGrass(), UpperPart: true}),
+		},
+		largeFern: [2]uint32{
+			rid(block.DoubleTallGrass{Type: block.FernDoubleTallGrass()}),
+			rid(block.DoubleTallGrass{Type: block.FernDoubleTallGrass(), UpperPart: true}),
+		},
+		sunflower:     double(block.Sunflower()),
+		plainsFlowers: flowers(block.Dandelion(), block.Poppy(), block.AzureBluet(), block.RedTulip(), block.OrangeTulip(), block.WhiteTulip(), block.PinkTulip(), block.OxeyeDaisy(), block.Cornflower()),
+		forestFlowers: flowers(block.Dandelion(), block.Poppy(), block.LilyOfTheValley()),
+		allFlowers: flowers(block.Dandelion(), block.Poppy(), block.Allium(), block.AzureBluet(), block.RedTulip(), block.OrangeTulip(),
+			block.WhiteTulip(), block.PinkTulip(), block.OxeyeDaisy(), block.Cornflower(), block.LilyOfTheValley()),
+		meadowFlowers: flowers(block.Dandelion(), block.Poppy(), block.Allium(), block.AzureBluet(), block.OxeyeDaisy(), block.Cornflower()),
+		swampFlowers:  flowers(block.BlueOrchid()),
+		forestDoubles: [][2]uint32{double(block.Lilac()), double(block.RoseBush()), double(block.Peony())},
+		logs:          map[block.WoodType]uint32{},
+		leaves:        map[block.WoodType]uint32{},
+		leafSet:       map[uint32]struct{}{},
+	}
+	for _, t := range block.CoralTypes() {
+		d.corals = append(d.corals, rid(block.Coral{Type: t}))
+	}
+	for age := 0; age < 25; age++ {
+		d.kelp = append(d.kelp, rid(block.Kelp{Age: age}))
+	}
+	for _, w := range block.WoodTypes() {
+		if w == block.CrimsonWood() || w == block.WarpedWood() {
+			continue
+		}
+		d.logs[w] = rid(block.Log{Wood: w, Axis: cube.Y})
+		d.leaves[w] = rid(block.Leaves{Wood: w})
+		d.leafSet[d.leaves[w]] = struct{}{}
+	}
+	return d
+}
+
+// vegetation returns the vegetation growing in the biome passed.
+func (o *Overworld) vegetation(b world.Biome) vegetation {
+	d := o.decorationBlocks
+	switch b.(type) {
+	case biome.Plains:
+		return vegetation{trees: 0.05, kinds: []treeKind{oakTree}, grass: 0.3, doubles: 0.02, flowers: 0.02, flowerTypes: d.plainsFlowers, fruit: 0.002, pumpkin: d.pumpkin}
+	case biome.SunflowerPlains:
+		return vegetation{trees: 0.05, kinds: []treeKind{oakTree}, grass: 0.3, doubles: 0.06, doubleTypes: [][2]uint32{d.sunflower}, flowers: 0.02, flowerTypes: d.plainsFlowers}
+	case biome.Forest, biome.WoodedHills:
+		return vegetation{trees: 10, kinds: []treeKind{oakTree, oakTree, oakTree, birchTree}, grass: 0.1, doubles: 0.01, doubleTypes: d.forestDoubles, flowers: 0.01, flowerTypes: d.forestFlowers, fruit: 0.002, pumpkin: d.pumpkin}
+	case biome.FlowerForest:
+		return vegetation{trees: 6, kinds: []treeKind{oakTree, birchTree}, grass: 0.05, doubles: 0.03, doubleTypes: d.forestDoubles, flowers: 0.2, flowerTypes: d.allFlowers}
+	case biome.BirchForest, biome.BirchForestHills:
+		return vegetation{trees: 10, kinds: []treeKind{birchTree}, grass: 0.1, flowers: 0.01, flowerTypes: d.forestFlowers}
+	case biome.OldGrowthBirchForest, biome.TallBirchHills:
+		return vegetation{trees: 10, kinds: []treeKind{tallBirchTree, birchTree}, grass: 0.1, flowers: 0.01, flowerTypes: d.forestFlowers}
+	case biome.DarkForest, biome.DarkForestHills:
+		return vegetation{trees: 16, kinds: []treeKind{darkOakTree, darkOakTree, oakTree}, grass: 0.1, flowers: 0.005, flowerTypes: d.forestFlowers, doubles: 0.01, doubleTypes: d.forestDoubles}
+	case biome.Taiga, biome.TaigaHills, biome.TaigaMountains, biome.SnowyTaiga, biome.SnowyTaigaHills, biome.SnowyTaigaMountains:
+		return vegetation{trees: 10, kinds: []treeKind{spruceTree, spruceTree, pineTree}, grass: 0.15, ferns: 0.7, doubles: 0.01, fruit: 0.002, pumpkin: d.pumpkin}
+	case biome.OldGrowthPineTaiga, biome.OldGrowthSpruceTaiga, biome.GiantTreeTaigaHills, biome.GiantSpruceTaigaHills:
+		return vegetation{trees: 12, kinds: []treeKind{pineTree, pineTree, spruceTree}, grass: 0.2, ferns: 0.7, doubles: 0.02, fruit: 0.002, pumpkin: d.pumpkin}
+	case biome.Jungle, biome.JungleHills, biome.ModifiedJungle, biome.BambooJungle, biome.BambooJungleHills:
+		return vegetation{trees: 25, kinds: []treeKind{jungleTree, jungleTree, jungleTree, oakTree}, grass: 0.3, ferns: 0.25, doubles: 0.03, flowers: 0.005, flowerTypes: d.forestFlowers, fruit: 0.01, pumpkin: d.melon}
+	case biome.JungleEdge, biome.ModifiedJungleEdge:
+		return vegetation{trees: 2, kinds: []treeKind{jungleTree, oakTree}, grass: 0.3, ferns: 0.25, doubles: 0.02, fruit: 0.005, pumpkin: d.melon}
+	case biome.Savanna, biome.SavannaPlateau, biome.ShatteredSavannaPlateau, biome.WindsweptSavanna:
+		return vegetation{trees: 1, kinds: []treeKind{acaciaTree, acaciaTree, acaciaTree, oakTree}, grass: 0.35, doubles: 0.04}
+	case biome.WoodedBadlandsPlateau, biome.ModifiedWoodedBadlandsPlateau:
+		return vegetation{trees: 5, kinds: []treeKind{oakTree}, grass: 0.05, deadBush: 0.01}
+	case biome.Badlands, biome.BadlandsPlateau, biome.ErodedBadlands, biome.ModifiedBadlandsPlateau:
+		return vegetation{cactus: 0.005, deadBush: 0.01}
+	case biome.Desert, biome.DesertHills, biome.DesertLakes:
+		return vegetation{cactus: 0.01, deadBush: 0.008}
+	case biome.Swamp, biome.SwampHills:
+		return vegetation{trees: 2, kinds: []treeKind{oakTree}, grass: 0.15, flowers: 0.01, flowerTypes: d.swampFlowers}
+	case biome.MangroveSwamp:
+		return vegetation{trees: 10, kinds: []treeKind{mangroveTree}, grass: 0.05}
+	case biome.CherryGrove:
+		return vegetation{trees: 10, kinds: []treeKind{cherryTree}, grass: 0.2, flowers: 0.02, flowerTypes: d.meadowFlowers}
+	case biome.Meadow:
+		return vegetation{trees: 0.1, kinds: []treeKind{oakTree, birchTree}, grass: 0.4, flowers: 0.08, flowerTypes: d.meadowFlowers, doubles: 0.02}
+	case biome.Grove:
+		return vegetation{trees: 10, kinds: []treeKind{spruceTree}}
+	case biome.SnowyPlains:
+		return vegetation{trees: 0.05, kinds: []treeKind{spruceTree}}
+	case biome.WindsweptForest:
+		return vegetation{trees: 3, kinds: []treeKind{spruceTree, oakTree}, grass: 0.1}
+	case biome.WindsweptHills, biome.WindsweptGravellyHills, biome.GravellyMountainsPlus, biome.MountainEdge:
+		return vegetation{trees: 0.1, kinds: []treeKind{spruceTree, oakTree}, grass: 0.1}
+	case biome.Beach, biome.SnowyBeach, biome.StonyShore, biome.StonyPeaks, biome.JaggedPeaks, biome.FrozenPeaks,
+		biome.SnowySlopes, biome.SnowyMountains, biome.IceSpikes, biome.MushroomFields, biome.MushroomFieldShore:
+		return vegetation{}
+	}
+	return vegetation{grass: 0.1}
+}
+
+// decorate generates trees and plants in the chunk at the position passed.
+// Trees that originate in neighbouring chunks are placed too, so that trees
+// crossing chunk borders are placed completely.
+func (o *Overworld) decorate(g *generation, pos world.ChunkPos, c *chunk.Chunk, cols [16][16]surfaceColumn) {
+	o.generateTrees(g, pos, c)
+
+	baseX, baseZ := int(pos[0])<<4, int(pos[1])<<4
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			o.decorateColumn(c, baseX+x, baseZ+z, uint8(x), uint8(z), cols[x][z])
+		}
+	}
+}
+
+// generateTrees generates the trees that originate in the chunk at the
+// position passed and in its neighbours. Only the blocks of these trees that
+// are within the chunk are placed.
+func (o *Overworld) generateTrees(g *generation, pos world.ChunkPos, c *chunk.Chunk) {
+	w := treeWriter{o: o, c: c, baseX: int(pos[0]) << 4, baseZ: int(pos[1]) << 4}
+	for dx := int32(-1); dx <= 1; dx++ {
+		for dz := int32(-1); dz <= 1; dz++ {
+			r := chunkRand(o.seed, pos[0]+dx, pos[1]+dz, 2000)
+			for i := 0; i < treeAttempts; i++ {
+				x, z, roll, pick := int(pos[0]+dx)<<4+r.Intn(16), int(pos[1]+dz)<<4+r.Intn(16), r.Float64(), r.Float64()
+				if x < w.baseX-treeReach-1 || x > w.baseX+15+treeReach || z < w.baseZ-treeReach-1 || z > w.baseZ+15+treeReach {
+					// The tree cannot reach the chunk that is being generated.
+					continue
+				}
+				b := o.climate(float64(x), float64(z)).biome()
+				v := o.vegetation(b)
+				if len(v.kinds) == 0 || roll >= v.trees/treeAttempts {
+					continue
+				}
+				kind := v.kinds[int(pick*float64(len(v.kinds)))]
+				y, ok := o.treeGround(g, b, x, z)
+				if !ok {
+					continue
+				}
+				if kind == darkOakTree {
+					// Dark oak trees have a trunk of 2x2 blocks, which must all be
+					// placed on ground at the same height.
+					if !o.sameGround(g, b, y, [2]int{x + 1, z}, [2]int{x, z + 1}, [2]int{x + 1, z + 1}) {
+						continue
+					}
+				}
+				w.tree(kind, x, y+1, z)
+			}
+		}
+	}
+}
+
+// treeGround returns the y value of the ground at the column passed if a
+// tree may grow on it.
+func (o *Overworld) treeGround(g *generation, b world.Biome, x, z int) (int, bool) {
+	col := g.columnSampler(o, x, z)
+	y, ok := col.surface()
+	if !ok || y < seaLevel {
+		return 0, false
+	}
+	ground, _ := o.surfaceBlock(b, x, y, z, y, 0, col.steep(), o.surface.at2(float64(x), float64(z)), o.stone)
+	switch ground {
+	case o.grass, o.dirt, o.coarseDirt, o.podzol, o.mud, o.snow:
+		return y, true
+	}
+	return 0, false
+}
+
+// sameGround checks if trees may grow at the ground of all columns passed and
+// if the ground of these columns is at the y passed.
+func (o *Overworld) sameGround(g *generation, b world.Biome, y int, columns ...[2]int) bool {
+	for _, col := range columns {
+		if other, ok := o.treeGround(g, b, col[0], col[1]); !ok || other != y {
+			return false
+		}
+	}
+	return true
+}
+
+// decorateColumn places plants at the surface of a single column.
+func (o *Overworld) decorateColumn(c *chunk.Chunk, x, z int, cx, cz uint8, col surfaceColumn) {
+	top := col.top
+	if top < seaLevel-24 || top+4 > c.Range().Max() {
+		return
+	}
+	ground, above := c.Block(cx, int16(top), cz, 0), c.Block(cx, int16(top+1), cz, 0)
+	if above == o.water {
+		o.decorateWater(c, x, z, cx, cz, top, col.biome)
+		return
+	}
+	if above != o.air || top < seaLevel {
+		return
+	}
+	v, d := o.vegetation(col.biome), o.decorationBlocks
+	rnd := func(salt int64) float64 {
+		return o.positionRand(x, top, z, salt)
+	}
+	set := func(y int, rid uint32) {
+		c.SetBlock(cx, int16(y), cz, 0, rid)
+	}
+
+	switch ground {
+	case o.sand, o.redSand, o.grass, o.dirt, o.coarseDirt, o.podzol, o.mud:
+		if rnd(20) < 0.2 && o.besideWater(c, cx, cz, top) {
+			for y := top + 1; y <= top+1+int(rnd(21)*3); y++ {
+				set(y, d.sugarCane)
+			}
+			return
+		}
+	default:
+		if ground != o.terracotta && !o.band(ground) {
+			return
+		}
+	}
+
+	switch ground {
+	case o.sand, o.redSand:
+		if rnd(22) < v.cactus {
+			h := 1 + int(rnd(23)*3)
+			if o.cactusFits(c, cx, cz, top, h) {
+				for y := top + 1; y <= top+h; y++ {
+					set(y, d.cactus)
+				}
+			}
+		} else if rnd(24) < v.deadBush {
+			set(top+1, d.deadBush)
+		}
+	case o.grass, o.podzol, o.coarseDirt, o.dirt, o.mud:
+		if ground != o.grass && ground != o.podzol {
+			break
+		}
+		switch {
+		case rnd(25) < v.doubles:
+			double := d.tallGrass
+			if len(v.doubleTypes) > 0 {
+				double = v.doubleTypes[int(rnd(26)*float64(len(v.doubleTypes)))]
+			} else if rnd(26) < v.ferns {
+				double = d.largeFern
+			}
+			set(top+1, double[0])
+			set(top+2, double[1])
+		case rnd(27) < v.grass:
+			if rnd(28) < v.ferns {
+				set(top+1, d.fern)
+				break
+			}
+			set(top+1, d.shortGrass)
+		case rnd(29) < v.flowers && len(v.flowerTypes) > 0:
+			// Flowers of the same type grow in patches, so the type is selected
+			// using noise rather than randomly.
+			n := clamp((o.surface.at2(float64(x)*4, float64(z)*4)+1)/2, 0, 0.999)
+			set(top+1, v.flowerTypes[int(n*float64(len(v.flowerTypes)))])
+		case rnd(30) < v.fruit && ground == o.grass:
+			set(top+1, v.pumpkin)
+		}
+	default:
+		if rnd(24) < v.deadBush {
+			set(top+1, d.deadBush)
+		}
+	}
+}
+
+// decorateWater places plants at the floor of oceans.
+func (o *Overworld) decorateWater(c *chunk.Chunk, x, z int, cx, cz uint8, top int, b world.Biome) {
+	depth := seaLevel - top
+	if depth < 3 {
+		return
+	}
+	d := o.decorationBlocks
+	rnd := func(salt int64) float64 {
+		return o.positionRand(x, top, z, salt)
+	}
+	waterlog := func(y int, rid uint32) {
+		c.SetBlock(cx, int16(y), cz, 0, rid)
+		c.SetBlock(cx, int16(y), cz, 1, o.water)
+	}
+	switch b.(type) {
+	case biome.WarmOcean, biome.DeepWarmOcean:
+		switch {
+		case rnd(40) < 0.08:
+			waterlog(top+1, d.corals[int(rnd(41)*float64(len(d.corals)))])
+		case rnd(42) < 0.02:
+			waterlog(top+1, d.seaPickle)
+		}
+	case biome.Ocean, biome.DeepOcean, biome.LukewarmOcean, biome.DeepLukewarmOcean, biome.ColdOcean, biome.DeepColdOcean:
+		if rnd(43) < 0.08 {
+			h := 1 + int(rnd(44)*float64(min(depth-2, 10)))
+			for y := top + 1; y <= top+h; y++ {
+				waterlog(y, d.kelp[int(o.positionRand(x, y, z, 45)*float64(len(d.kelp)))])
+			}
+		}
+	}
+}
+
+// band checks if the runtime ID passed is one of the bands of terracotta.
+func (o *Overworld) band(rid uint32) bool {
+	for _, b := range o.bands {
+		if b == rid {
+			return true
+		}
+	}
+	return false
+}
+
+// besideWater checks if any of the blocks horizontally next to the position
+// in the chunk passed is water. Only neighbours within the chunk are checked.
+func (o *Overworld) besideWater(c *chunk.Chunk, cx, cz uint8, y int) bool {
+	for _, n := range [...][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		nx, nz := int(cx)+n[0], int(cz)+n[1]
+		if nx >= 0 && nx < 16 && nz >= 0 && nz < 16 && c.Block(uint8(nx), int16(y), uint8(nz), 0) == o.water {
+			return true
+		}
+	}
+	return false
+}
+
+// cactusFits checks if a cactus of a height fits on top of the position in
+// the chunk passed: Cacti break if any block is placed next to them.
+func (o *Overworld) cactusFits(c *chunk.Chunk, cx, cz uint8, top, height int) bool {
+	if cx == 0 || cx == 15 || cz == 0 || cz == 15 {
+		return false
+	}
+	for y := top + 1; y <= top+height; y++ {
+		for _, n := range [...][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+			if c.Block(uint8(int(cx)+n[0]), int16(y), uint8(int(cz)+n[1]), 0) != o.air {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// treeWriter places the blocks of trees in a chunk. Blocks outside the chunk
+// are discarded.
+type treeWriter struct {
+	o            *Overworld
+	c            *chunk.Chunk
+	baseX, baseZ int
+}
+
+// tree places a tree of the kind passed with its trunk starting at the
+// position passed.
+func (w treeWriter) tree(kind treeKind, x, y, z int) {
+	rnd := func(salt int64, n int) int {
+		return int(w.o.positionRand(x, y, z, salt) * float64(n))
+	}
+	switch kind {
+	case oakTree:
+		w.blob(x, y, z, block.OakWood(), 4+rnd(50, 3))
+	case birchTree:
+		w.blob(x, y, z, block.BirchWood(), 5+rnd(50, 3))
+	case tallBirchTree:
+		w.blob(x, y, z, block.BirchWood(), 8+rnd(50, 5))
+	case jungleTree:
+		w.blob(x, y, z, block.JungleWood(), 6+rnd(50, 5))
+	case mangroveTree:
+		w.blob(x, y, z, block.Mangrove(), 4+rnd(50, 4))
+	case spruceTree:
+		h := 6 + rnd(50, 4)
+		w.cone(x, y, z, block.SpruceWood(), h, 1+rnd(51, 2), 2+rnd(52, 2))
+	case pineTree:
+		h := 10 + rnd(50, 6)
+		w.cone(x, y, z, block.SpruceWood(), h, h/2+rnd(51, 3), 2)
+	case acaciaTree:
+		w.acacia(x, y, z, 5+rnd(50, 3), cube.Directions()[rnd(51, 4)], 1+rnd(52, 2))
+	case darkOakTree:
+		w.darkOak(x, y, z, 6+rnd(50, 3))
+	case cherryTree:
+		w.cherry(x, y, z, 5+rnd(50, 3))
+	}
+}
+
+// blob places a tree with a round crown of leaves, such as oak, birch and
+// jungle trees.
+func (w treeWriter) blob(x, y, z int, wood block.WoodType, height int) {
+	top, leaves := y+height-1, w.o.leaves[wood]
+	for ly := top - 2; ly <= top+1; ly++ {
+		rel := ly - top - 1
+		w.layer(x, ly, z, 1-rel/2, rel == 0, leaves)
+	}
+	w.trunk(x, y, z, wood, height)
+}
+
+// cone places a cone-shaped tree, such as spruce trees. Leaves grow on all
+// but the bottom bare blocks of the trunk, with a maximum radius of maxRadius.
+func (w treeWriter) cone(x, y, z int, wood block.WoodType, height, bare, maxRadius int) {
+	top, leaves := y+height-1, w.o.leaves[wood]
+	radius, limit := 0, 1
+	for ly := top + 1; ly >= y+bare; ly-- {
+		w.layer(x, ly, z, radius, true, leaves)
+		if radius >= limit {
+			radius, limit = 1, min(limit+1, maxRadius)
+			continue
+		}
+		radius++
+	}
+	w.trunk(x, y, z, wood, height)
+}
+
+// acacia places an acacia tree, whose trunk bends in a direction near the
+// top before ending in a flat crown of leaves.
+func (w treeWriter) acacia(x, y, z, height int, d cube.Direction, bend int) {
+	log := w.o.logs[block.AcaciaWood()]
+	offset := cube.Pos{}.Side(d.Face())
+	straight := height - bend
+	for i := 0; i < height; i++ {
+		if i >= straight {
+			x, z = x+offset.X(), z+offset.Z()
+		}
+		w.set(x, y+i, z, log, false)
+	}
+	top, leaves := y+height-1, w.o.leaves[block.AcaciaWood()]
+	w.layer(x, top, z, 3, true, leaves)
+	w.layer(x, top+1, z, 1, false, leaves)
+	w.dirt(x-offset.X()*bend, y-1, z-offset.Z()*bend)
+}
+
+// darkOak places a dark oak tree, which has a trunk of 2x2 blocks and a wide
+// crown of leaves.
+func (w treeWriter) darkOak(x, y, z, height int) {
+	top, leaves := y+height-1, w.o.leaves[block.DarkOakWood()]
+	for i, radius := range [...]int{2, 3, 3, 2} {
+		ly := top - 2 + i
+		for dx := -radius; dx <= radius+1; dx++ {
+			for dz := -radius; dz <= radius+1; dz++ {
+				if (dx == -radius || dx == radius+1) && (dz == -radius || dz == radius+1) {
+					continue
+				}
+				w.set(x+dx, ly, z+dz, leaves, true)
+			}
+		}
+	}
+	for _, o := range [...][2]int{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+		w.trunk(x+o[0], y, z+o[1], block.DarkOakWood(), height)
+	}
+}
+
+// cherry places a cherry tree, which has a wide, flat crown of leaves.
+func (w treeWriter) cherry(x, y, z, height int) {
+	top, leaves := y+height-1, w.o.leaves[block.Cherry()]
+	w.layer(x, top-1, z, 2, true, leaves)
+	w.layer(x, top, z, 3, true, leaves)
+	w.layer(x, top+1, z, 3, true, leaves)
+	w.layer(x, top+2, z, 2, false, leaves)
+	w.trunk(x, y, z, block.Cherry(), height)
+}
+
+// trunk places the trunk of a tree of a height, with a block of dirt below.
+func (w treeWriter) trunk(x, y, z int, wood block.WoodType, height int) {
+	for i := 0; i < height; i++ {
+		w.set(x, y+i, z, w.o.logs[wood], false)
+	}
+	w.dirt(x, y-1, z)
+}
+
+// dirt places dirt at the position passed, below the trunk of a tree.
+func (w treeWriter) dirt(x, y, z int) {
+	if x >= w.baseX && x < w.baseX+16 && z >= w.baseZ && z < w.baseZ+16 {
+		w.c.SetBlock(uint8(x-w.baseX), int16(y), uint8(z-w.baseZ), 0, w.o.dirt)
+	}
+}
+
+// layer places a square layer of the leaves passed with a radius around the
+// position passed. If corners is true, the corners of the
+// layer are always left out. Otherwise, they are left out randomly.
+func (w treeWriter) layer(x, y, z, radius int, corners bool, leaves uint32) {
+	for dx := -radius; dx <= radius; dx++ {
+		for dz := -radius; dz <= radius; dz++ {
+			if radius > 0 && (dx == -radius || dx == radius) && (dz == -radius || dz == radius) {
+				if corners || w.o.positionRand(x+dx, y, z+dz, 60) < 0.5 {
+					continue
+				}
+			}
+			w.set(x+dx, y, z+dz, leaves, true)
+		}
+	}
+}
+
+// set places a block at a position if it is within the chunk. If leaves is
+// true, the block is only placed if the position is air. Otherwise, it also
+// replaces leaves.
+func (w treeWriter) set(x, y, z int, rid uint32, leaves bool) {
+	if x < w.baseX || x >= w.baseX+16 || z < w.baseZ || z >= w.baseZ+16 || y < w.c.Range().Min() || y > w.c.Range().Max() {
+		return
+	}
+	cx, cz := uint8(x-w.baseX), uint8(z-w.baseZ)
+	current := w.c.Block(cx, int16(y), cz, 0)
+	if current != w.o.air {
+		if _, ok := w.o.leafSet[current]; leaves || !ok {
+			return
+		}
+	}
+	w.c.SetBlock(cx, int16(y), cz, 0, rid)
+}
@@ -0,0 +1,161 @@
+package generator
+
+import (
+	"github.com/stcraft/dragonfly/server/block"
+	"github.com/stcraft/dragonfly/server/item"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/biome"
+)
+
+// surfaceBlocks holds the runtime IDs of the blocks that are placed at the
+// surface of the terrain.
+type surfaceBlocks struct {
+	grass, dirt, coarseDirt, podzol, mud uint32
+	sand, redSand, sandstone, gravel     uint32
+	snow, packedIce, terracotta          uint32
+	bands                                []uint32
+}
+
+// newSurfaceBlocks looks up the runtime IDs of all surface blocks.
+func newSurfaceBlocks() surfaceBlocks {
+	s := surfaceBlocks{
+		grass:      world.BlockRuntimeID(block.Grass{}),
+		dirt:       world.BlockRuntimeID(block.Dirt{}),
+		coarseDirt: world.BlockRuntimeID(block.Dirt{Coarse: true}),
+		podzol:     world.BlockRuntimeID(block.Podzol{}),
+		mud:        world.BlockRuntimeID(block.Mud{}),
+		sand:       world.BlockRuntimeID(block.Sand{}),
+		redSand:    world.BlockRuntimeID(block.Sand{Red: true}),
+		sandstone:  world.BlockRuntimeID(block.Sandstone{Type: block.NormalSandstone()}),
+		gravel:     world.BlockRuntimeID(block.Gravel{}),
+		snow:       world.BlockRuntimeID(block.Snow{}),
+		packedIce:  world.BlockRuntimeID(block.PackedIce{}),
+		terracotta: world.BlockRuntimeID(block.Terracotta{}),
+	}
+	// The bands of terracotta found in badlands, repeating every 16 blocks.
+	for _, c := range []item.Colour{
+		item.ColourOrange(), item.ColourWhite(), item.ColourYellow(), item.ColourOrange(), item.ColourBrown(),
+		item.ColourRed(), item.ColourLightGrey(), item.ColourOrange(), item.ColourYellow(), item.ColourWhite(),
+		item.ColourRed(), item.ColourBrown(), item.ColourOrange(), item.ColourLightGrey(), item.ColourWhite(),
+		item.ColourYellow(),
+	} {
+		s.bands = append(s.bands, world.BlockRuntimeID(block.StainedTerracotta{Colour: c}))
+	}
+	s.bands[1], s.bands[8], s.bands[14] = s.terracotta, s.terracotta, s.terracotta
+	return s
+}
+
+// surfaceBlock returns the block placed at a position near the surface of a
+// column, where top is the y value of the highest solid block and depth is
+// the amount of solid blocks above the position. The stone passed is returned
+// if the position is too deep to be part of the surface, in which case true
+// is also returned to signal that no further surface blocks are placed.
+func (o *Overworld) surfaceBlock(b world.Biome, x, y, z, top, depth int, steep bool, n float64, stone uint32) (uint32, bool) {
+	s := o.surfaceBlocks
+	if top < seaLevel-1 {
+		// The surface is underwater, so place the floor of the ocean, river or
+		// lake.
+		if depth < 3 {
+			return o.floorBlock(b, n), false
+		}
+		return stone, true
+	}
+	switch b.(type) {
+	case biome.Desert, biome.DesertHills, biome.DesertLakes, biome.Beach, biome.SnowyBeach:
+		switch {
+		case depth < 3:
+			return s.sand, false
+		case depth < 6:
+			return s.sandstone, false
+		}
+		return stone, true
+	case biome.Badlands, biome.BadlandsPlateau, biome.ErodedBadlands, biome.ModifiedBadlandsPlateau,
+		biome.WoodedBadlandsPlateau, biome.ModifiedWoodedBadlandsPlateau:
+		switch {
+		case depth == 0 && !steep && top < 96:
+			return s.redSand, false
+		case depth == 0 && !steep && n > 0:
+			if _, ok := b.(biome.WoodedBadlandsPlateau); ok {
+				return s.coarseDirt, false
+			}
+		case depth >= 16:
+			return stone, true
+		}
+		return s.bands[((y+int(n*4))%len(s.bands)+len(s.bands))%len(s.bands)], false
+	case biome.StonyShore, biome.StonyPeaks:
+		return stone, true
+	case biome.WindsweptGravellyHills, biome.GravellyMountainsPlus:
+		if n > -0.2 && depth < 3 {
+			return s.gravel, false
+		}
+		return stone, true
+	case biome.JaggedPeaks, biome.FrozenPeaks, biome.SnowySlopes, biome.SnowyMountains:
+		switch {
+		case steep:
+			return stone, true
+		case depth == 0 && n > 0.3:
+			if _, ok := b.(biome.FrozenPeaks); ok {
+				return s.packedIce, false
+			}
+		case depth >= 2:
+			return stone, true
+		}
+		return s.snow, false
+	case biome.SnowyPlains, biome.IceSpikes, biome.SnowyTaiga, biome.SnowyTaigaHills, biome.SnowyTaigaMountains, biome.Grove:
+		if depth == 0 {
+			return s.snow, false
+		}
+	case biome.MangroveSwamp:
+		if depth < 4 {
+			return s.mud, false
+		}
+		return stone, true
+	case biome.OldGrowthPineTaiga, biome.OldGrowthSpruceTaiga, biome.GiantTreeTaigaHills, biome.GiantSpruceTaigaHills:
+		if depth == 0 && n > 0.25 {
+			return s.podzol, false
+		} else if depth == 0 && n < -0.25 {
+			return s.coarseDirt, false
+		}
+	case biome.WindsweptHills, biome.WindsweptForest, biome.MountainEdge, biome.TaigaMountains:
+		if steep {
+			return stone, true
+		}
+	case biome.WindsweptSavanna, biome.ShatteredSavannaPlateau:
+		if steep {
+			return stone, true
+		} else if depth == 0 && n > 0.3 {
+			return s.coarseDirt, false
+		}
+	}
+	switch {
+	case depth == 0:
+		return s.grass, false
+	case depth < 4:
+		return s.dirt, false
+	}
+	return stone, true
+}
+
+// floorBlock returns the block placed at the floor of oceans, rivers and lakes
+// in the biome passed.
+func (o *Overworld) floorBlock(b world.Biome, n float64) uint32 {
+	s := o.surfaceBlocks
+	switch b.(type) {
+	case biome.DeepOcean, biome.DeepColdOcean, biome.DeepFrozenOcean, biome.ColdOcean, biome.FrozenOcean:
+		return s.gravel
+	case biome.WarmOcean, biome.LukewarmOcean, biome.DeepLukewarmOcean, biome.DeepWarmOcean, biome.Desert,
+		biome.DesertHills, biome.DesertLakes, biome.Beach, biome.SnowyBeach:
+		return s.sand
+	case biome.Ocean, biome.River, biome.FrozenRiver:
+		if n > 0.1 {
+			return s.gravel
+		}
+		return s.sand
+	case biome.MangroveSwamp:
+		return s.mud
+	}
+	if n > 0.35 {
+		return s.gravel
+	}
+	return s.dirt
+}
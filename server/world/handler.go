@@ -35,6 +35,10 @@ type Handler interface {
 	// wood, that can be broken by fire. HandleBlockBurn is often succeeded by HandleFireSpread, when fire spreads to
 	// the position of the original block and the event.Context is not cancelled in HandleBlockBurn.
 	HandleBlockBurn(ctx *event.Context, pos cube.Pos)
+	// HandleBrew handles a brewing stand at a cube.Pos finishing brewing potions. The ingredient and the potions
+	// being brewed may be obtained through the inventory of the brewing stand. ctx.Cancel() may be called to
+	// prevent the potions from being brewed and the ingredient from being consumed.
+	HandleBrew(ctx *event.Context, pos cube.Pos)
 	// HandleEntitySpawn handles an entity being spawned into a World through a call to World.AddEntity.
 	HandleEntitySpawn(e Entity)
 	// HandleEntityDespawn handles an entity being despawned from a World through a call to World.RemoveEntity.
@@ -59,6 +63,7 @@ func (NopHandler) HandleLiquidHarden(*event.Context, cube.Pos, Block, Block, Blo
 func (NopHandler) HandleSound(*event.Context, Sound, mgl64.Vec3)                      {}
 func (NopHandler) HandleFireSpread(*event.Context, cube.Pos, cube.Pos)                {}
 func (NopHandler) HandleBlockBurn(*event.Context, cube.Pos)                           {}
+func (NopHandler) HandleBrew(*event.Context, cube.Pos)                                {}
 func (NopHandler) HandleEntitySpawn(Entity)                                           {}
 func (NopHandler) HandleEntityDespawn(Entity)                                         {}
 func (NopHandler) HandleClose()                                                       {}
@@ -0,0 +1,74 @@
+package world
+
+import (
+	"errors"
+
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/google/uuid"
+	"github.com/stcraft/dragonfly/server/block/cube"
+)
+
+// Compile time check to make sure InstanceProvider implements Provider.
+var _ Provider = (*InstanceProvider)(nil)
+
+// InstanceProvider is a Provider for instanced worlds: Worlds that are created from a template. Columns are
+// read from a template Provider, which may be shared by any number of InstanceProviders, while all changes
+// to the World are stored in a private overlay Provider. The template Provider is never written to, so that
+// all changes made to an instanced World are discarded together with its overlay.
+type InstanceProvider struct {
+	template, overlay Provider
+	set               *Settings
+}
+
+// NewInstanceProvider creates an InstanceProvider that reads columns from the template Provider passed and
+// stores changes in the overlay Provider passed. The Settings of the template are copied, so that changes
+// to the Settings of the instanced World do not affect the template or other instances.
+func NewInstanceProvider(template, overlay Provider) *InstanceProvider {
+	return &InstanceProvider{template: template, overlay: overlay, set: template.Settings().clone()}
+}
+
+// Settings returns the Settings copied from the template Provider.
+func (p *InstanceProvider) Settings() *Settings {
+	return p.set
+}
+
+// SaveSettings saves the Settings passed to the overlay Provider.
+func (p *InstanceProvider) SaveSettings(s *Settings) {
+	p.overlay.SaveSettings(s)
+}
+
+// LoadPlayerSpawnPosition loads the spawn position of a player from the overlay Provider. If not found
+// there, the spawn position is loaded from the template Provider.
+func (p *InstanceProvider) LoadPlayerSpawnPosition(id uuid.UUID) (cube.Pos, bool, error) {
+	pos, exists, err := p.overlay.LoadPlayerSpawnPosition(id)
+	if exists || err != nil {
+		return pos, exists, err
+	}
+	return p.template.LoadPlayerSpawnPosition(id)
+}
+
+// SavePlayerSpawnPosition saves the spawn position of a player to the overlay Provider.
+func (p *InstanceProvider) SavePlayerSpawnPosition(id uuid.UUID, pos cube.Pos) error {
+	return p.overlay.SavePlayerSpawnPosition(id, pos)
+}
+
+// LoadColumn loads a Column from the overlay Provider. If the Column was never stored in the overlay, it is
+// loaded from the template Provider instead.
+func (p *InstanceProvider) LoadColumn(pos ChunkPos, dim Dimension) (*Column, error) {
+	col, err := p.overlay.LoadColumn(pos, dim)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return p.template.LoadColumn(pos, dim)
+	}
+	return col, err
+}
+
+// StoreColumn stores a Column in the overlay Provider.
+func (p *InstanceProvider) StoreColumn(pos ChunkPos, dim Dimension, col *Column) error {
+	return p.overlay.StoreColumn(pos, dim, col)
+}
+
+// Close closes the overlay Provider, discarding all changes if the overlay does not persist its data. The
+// template Provider is not closed, as it may be shared with other InstanceProviders.
+func (p *InstanceProvider) Close() error {
+	return p.overlay.Close()
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/df-mc/goleveldb/leveldb"
 	"github.com/df-mc/goleveldb/leveldb/opt"
+	"github.com/df-mc/goleveldb/leveldb/storage"
 	"github.com/sirupsen/logrus"
 	"github.com/stcraft/dragonfly/server/entity"
 	"github.com/stcraft/dragonfly/server/world"
@@ -44,31 +45,20 @@ type Config struct {
 	Entities world.EntityRegistry
 }
 
-// Open creates a new DB reading and writing from/to files under the path
-// passed. If a world is present at the path, Open will parse its data and
-// initialise the world with it. If the data cannot be parsed, an error is
-// returned.
-func (conf Config) Open(name string) (*DB, error) {
-	if err := os.MkdirAll("worlds", 077); err != nil {
-		panic(err)
-	}
-	if conf.Log == nil {
-		conf.Log = logrus.New()
-	}
-	if conf.BlockSize == 0 {
-		conf.BlockSize = 16 * opt.KiB
-	}
-	if len(conf.Entities.Types()) == 0 {
-		conf.Entities = entity.DefaultRegistry
+// Open creates a new DB reading and writing from/to files under the directory
+// passed. The directory is created if it does not yet exist. If a world is
+// present in the directory, Open will parse its data and initialise the world
+// with it. If the data cannot be parsed, an error is returned.
+func (conf Config) Open(dir string) (*DB, error) {
+	conf = conf.withDefaults()
+	if err := os.MkdirAll(filepath.Join(dir, "db"), 0777); err != nil {
+		return nil, fmt.Errorf("open db: %w", err)
 	}
 
-	dir := filepath.Join("worlds", name)
-	_ = os.MkdirAll(filepath.Join(dir, "db"), 0777)
-
 	db := &DB{conf: conf, dir: dir, ldat: &leveldat.Data{}}
 	if _, err := os.Stat(filepath.Join(dir, "level.dat")); os.IsNotExist(err) {
 		// A level.dat was not currently present for the world.
-		db.ldat.FillDefault(name)
+		db.ldat.FillDefault(filepath.Base(dir))
 	} else {
 		ldat, err := leveldat.ReadFile(filepath.Join(dir, "level.dat"))
 		if err != nil {
@@ -98,3 +88,39 @@ func (conf Config) Open(name string) (*DB, error) {
 	db.ldb = ldb
 	return db, nil
 }
+
+// OpenMemory creates a new DB that keeps all of its data in memory instead of
+// on disk. All data stored in the DB is lost once it is closed, which makes it
+// suitable for short-lived worlds. The name passed is used as the name of the
+// world in its settings.
+func (conf Config) OpenMemory(name string) (*DB, error) {
+	conf = conf.withDefaults()
+
+	db := &DB{conf: conf, ldat: &leveldat.Data{}}
+	db.ldat.FillDefault(name)
+	db.set = db.ldat.Settings()
+	ldb, err := leveldb.Open(storage.NewMemStorage(), &opt.Options{
+		Compression: conf.Compression,
+		BlockSize:   conf.BlockSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error opening leveldb database: %w", err)
+	}
+	db.ldb = ldb
+	return db, nil
+}
+
+// withDefaults returns a copy of conf with the default values filled out for
+// all fields that were left empty.
+func (conf Config) withDefaults() Config {
+	if conf.Log == nil {
+		conf.Log = logrus.New()
+	}
+	if conf.BlockSize == 0 {
+		conf.BlockSize = 16 * opt.KiB
+	}
+	if len(conf.Entities.Types()) == 0 {
+		conf.Entities = entity.DefaultRegistry
+	}
+	return conf
+}
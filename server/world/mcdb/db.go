@@ -43,9 +43,17 @@ func (db *DB) Settings() *world.Settings {
 	return db.set
 }
 
-// SaveSettings saves the world.Settings passed to the level.dat.
+// SaveSettings saves the world.Settings passed to the level.dat. If the DB is
+// stored in a directory, the level.dat file is written immediately, so that
+// the settings are not lost if the process is killed before the DB is closed.
 func (db *DB) SaveSettings(s *world.Settings) {
 	db.ldat.PutSettings(s)
+	if db.dir == "" {
+		return
+	}
+	if err := db.writeLevelDat(); err != nil {
+		db.conf.Log.Errorf("save settings: %v", err)
+	}
 }
 
 // playerData holds the fields that indicate where player data is stored for a player with a specific UUID.
@@ -410,19 +418,33 @@ func (db *DB) NewColumnIterator(r *IteratorRange) *ColumnIterator {
 
 // Close closes the provider, saving any file that might need to be saved, such as the level.dat.
 func (db *DB) Close() error {
+	if db.dir == "" {
+		// The DB was opened using Config.OpenMemory, so there are no files to
+		// save.
+		return db.ldb.Close()
+	}
+	if err := db.writeLevelDat(); err != nil {
+		return fmt.Errorf("close: %w", err)
+	}
+	return db.ldb.Close()
+}
+
+// writeLevelDat writes the level.dat and levelname.txt files of the DB to its
+// directory.
+func (db *DB) writeLevelDat() error {
 	db.ldat.LastPlayed = time.Now().Unix()
 
 	var ldat leveldat.LevelDat
 	if err := ldat.Marshal(*db.ldat); err != nil {
-		return fmt.Errorf("close: %w", err)
+		return err
 	}
 	if err := ldat.WriteFile(filepath.Join(db.dir, "level.dat")); err != nil {
-		return fmt.Errorf("close: %w", err)
+		return err
 	}
 	if err := os.WriteFile(filepath.Join(db.dir, "levelname.txt"), []byte(db.ldat.LevelName), 0644); err != nil {
-		return fmt.Errorf("close: write levelname.txt: %w", err)
+		return fmt.Errorf("write levelname.txt: %w", err)
 	}
-	return db.ldb.Close()
+	return nil
 }
 
 // dbKey holds a position and dimension.
@@ -198,6 +198,7 @@ func (d *Data) FillDefault(name string) {
 	d.PVP = true
 	d.Platform = 2
 	d.PlatformBroadcastIntent = 3
+	d.PlayersSleepingPercentage = 100
 	d.RainLevel = 1.0
 	d.RandomSeed = time.Now().Unix()
 	d.RandomTickSpeed = 1
@@ -225,6 +226,7 @@ func (d *Data) Settings() *world.Settings {
 	return &world.Settings{
 		Name:            d.LevelName,
 		Spawn:           cube.Pos{int(d.SpawnX), int(d.SpawnY), int(d.SpawnZ)},
+		Seed:            d.RandomSeed,
 		Time:            d.Time,
 		TimeCycle:       d.DoDayLightCycle,
 		RainTime:        int64(d.RainTime),
@@ -236,6 +238,8 @@ func (d *Data) Settings() *world.Settings {
 		DefaultGameMode: mode,
 		Difficulty:      difficulty,
 		TickRange:       d.ServerChunkTickRange,
+
+		RequiredSleepingPercentage: int(d.PlayersSleepingPercentage),
 	}
 }
 
@@ -244,6 +248,7 @@ func (d *Data) PutSettings(s *world.Settings) {
 	d.LevelName = s.Name
 	d.SpawnX, d.SpawnY, d.SpawnZ = int32(s.Spawn.X()), int32(s.Spawn.Y()), int32(s.Spawn.Z())
 	d.LimitedWorldOriginX, d.LimitedWorldOriginY, d.LimitedWorldOriginZ = d.SpawnX, d.SpawnY, d.SpawnZ
+	d.RandomSeed = s.Seed
 	d.Time = s.Time
 	d.DoDayLightCycle = s.TimeCycle
 	d.DoWeatherCycle = s.WeatherCycle
@@ -257,6 +262,7 @@ func (d *Data) PutSettings(s *world.Settings) {
 	}
 	d.CurrentTick = s.CurrentTick
 	d.ServerChunkTickRange = s.TickRange
+	d.PlayersSleepingPercentage = int32(s.RequiredSleepingPercentage)
 	mode, _ := world.GameModeID(s.DefaultGameMode)
 	d.GameType = int32(mode)
 	difficulty, _ := world.DifficultyID(s.Difficulty)
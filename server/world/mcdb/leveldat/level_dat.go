@@ -88,16 +88,33 @@ func (ld *LevelDat) Write(w io.Writer) error {
 	return nil
 }
 
-// WriteFile writes ld to a file at name.
+// WriteFile writes ld to a file at name. The data is first written to a
+// temporary file, which then replaces the file at name, so that the file is
+// never left partially written if the process is stopped while writing.
 func (ld *LevelDat) WriteFile(name string) error {
-	f, err := os.OpenFile(name, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, 0644)
+	tmp := name + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		return fmt.Errorf("level.dat: open file: %w", err)
 	}
 	w := bufio.NewWriter(f)
-	defer func() {
-		_ = w.Flush()
+	if err := ld.Write(w); err != nil {
 		_ = f.Close()
-	}()
-	return ld.Write(w)
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("level.dat: write file: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("level.dat: sync file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("level.dat: close file: %w", err)
+	}
+	if err := os.Rename(tmp, name); err != nil {
+		return fmt.Errorf("level.dat: replace file: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,95 @@
+package portal
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// endPlatformPos is the position of the centre of the obsidian platform that
+// entities arrive on when entering the end through an end portal.
+var endPlatformPos = cube.Pos{100, 48, 0}
+
+// EndPlatform creates the obsidian platform that entities arrive on when
+// entering the end through an end portal, clearing the space above it. The
+// position at which entities should be placed is returned.
+func EndPlatform(w *world.World) mgl64.Vec3 {
+	a, o := air(), obsidian()
+	for x := -2; x <= 2; x++ {
+		for z := -2; z <= 2; z++ {
+			pos := endPlatformPos.Add(cube.Pos{x, 0, z})
+			w.SetBlock(pos, o, nil)
+			for y := 1; y <= 3; y++ {
+				w.SetBlock(pos.Add(cube.Pos{0, y}), a, nil)
+			}
+		}
+	}
+	return endPlatformPos.Side(cube.FaceUp).Vec3Middle()
+}
+
+const (
+	// gatewayDistance is the distance from the centre of the end that gateways
+	// on the central island take entities to.
+	gatewayDistance = 1024
+	// gatewayReturnDistance is the distance from the centre of the end that
+	// gateways on the outer islands take entities back to.
+	gatewayReturnDistance = 96
+)
+
+// GatewayDestination returns the position that an end gateway at the position
+// passed takes entities to. Gateways near the centre of the end lead to the
+// outer islands in the direction of the gateway, while gateways further away
+// lead back to the edge of the central island. If no land is found at the
+// destination of a gateway on the central island, a small end stone platform
+// is created.
+func GatewayDestination(w *world.World, pos cube.Pos) mgl64.Vec3 {
+	dir := mgl64.Vec2{float64(pos[0]), float64(pos[2])}
+	if dir.Len() == 0 {
+		dir = mgl64.Vec2{1, 0}
+	}
+	far := dir.Len() >= gatewayDistance/2
+	dir = dir.Normalize()
+
+	if far {
+		target := dir.Mul(gatewayReturnDistance)
+		if top, ok := landAt(w, int(target[0]), int(target[1])); ok {
+			return top
+		}
+		return gatewayPlatform(w, int(target[0]), int(target[1]))
+	}
+	// Search along the direction of the gateway for the first island, like
+	// vanilla does.
+	for i := 0; i < 16; i++ {
+		target := dir.Mul(gatewayDistance + float64(i*16))
+		if top, ok := landAt(w, int(math.Floor(target[0])), int(math.Floor(target[1]))); ok {
+			return top
+		}
+	}
+	target := dir.Mul(gatewayDistance)
+	return gatewayPlatform(w, int(math.Floor(target[0])), int(math.Floor(target[1])))
+}
+
+// landAt returns the position on top of the highest block at the x and z
+// passed. False is returned if the column does not contain any blocks.
+func landAt(w *world.World, x, z int) (mgl64.Vec3, bool) {
+	y := w.HighestBlock(x, z)
+	pos := cube.Pos{x, y, z}
+	if !solidFloor(w, pos) {
+		return mgl64.Vec3{}, false
+	}
+	return pos.Side(cube.FaceUp).Vec3Middle(), true
+}
+
+// gatewayPlatform creates a small platform of end stone at the x and z passed
+// and returns the position on top of it.
+func gatewayPlatform(w *world.World, x, z int) mgl64.Vec3 {
+	s := blockByName("minecraft:end_stone", nil)
+	for dx := -2; dx <= 2; dx++ {
+		for dz := -2; dz <= 2; dz++ {
+			w.SetBlock(cube.Pos{x + dx, 75, z + dz}, s, nil)
+		}
+	}
+	return cube.Pos{x, 76, z}.Vec3Middle()
+}
@@ -0,0 +1,327 @@
+package portal
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+const (
+	// minNetherWidth and maxNetherWidth are the minimum and maximum width of
+	// the interior of a nether portal.
+	minNetherWidth, maxNetherWidth = 2, 21
+	// minNetherHeight and maxNetherHeight are the minimum and maximum height of
+	// the interior of a nether portal.
+	minNetherHeight, maxNetherHeight = 3, 21
+)
+
+// Nether is a nether portal structure: A rectangular frame of obsidian
+// enclosing an interior that may be filled with nether portal blocks. A Nether
+// portal is obtained by calling NetherPortalFromPos or
+// FindOrCreateNetherPortal.
+type Nether struct {
+	w *world.World
+	// origin is the position of the interior of the portal with the lowest
+	// coordinates.
+	origin        cube.Pos
+	axis          cube.Axis
+	width, height int
+}
+
+// NetherPortalFromPos returns the nether portal that has its interior at the
+// position passed. False is returned if the position is not within the
+// interior of a nether portal that is completely framed by obsidian.
+func NetherPortalFromPos(w *world.World, pos cube.Pos) (Nether, bool) {
+	if !netherInterior(w.Block(pos)) {
+		return Nether{}, false
+	}
+	for _, axis := range []cube.Axis{cube.X, cube.Z} {
+		if n, ok := netherPortalFromPos(w, pos, axis); ok {
+			return n, true
+		}
+	}
+	return Nether{}, false
+}
+
+// netherPortalFromPos attempts to find a nether portal with the axis passed
+// that has its interior at the position passed.
+func netherPortalFromPos(w *world.World, pos cube.Pos, axis cube.Axis) (Nether, bool) {
+	n := Nether{w: w, origin: pos, axis: axis}
+	for i := 0; netherInterior(w.Block(n.origin.Side(cube.FaceDown))); i++ {
+		if i == maxNetherHeight {
+			return Nether{}, false
+		}
+		n.origin = n.origin.Side(cube.FaceDown)
+	}
+	back := n.offset(-1, 0)
+	for i := 0; netherInterior(w.Block(n.origin.Add(back))); i++ {
+		if i == maxNetherWidth {
+			return Nether{}, false
+		}
+		n.origin = n.origin.Add(back)
+	}
+	for n.width < maxNetherWidth+1 && netherInterior(w.Block(n.origin.Add(n.offset(n.width, 0)))) {
+		n.width++
+	}
+	for n.height < maxNetherHeight+1 && netherInterior(w.Block(n.origin.Add(n.offset(0, n.height)))) {
+		n.height++
+	}
+	if n.width < minNetherWidth || n.width > maxNetherWidth || n.height < minNetherHeight || n.height > maxNetherHeight {
+		return Nether{}, false
+	}
+	return n, n.framed()
+}
+
+// framed checks if the interior of the portal is completely surrounded by
+// obsidian and does not contain any blocks other than air, fire and portal
+// blocks.
+func (n Nether) framed() bool {
+	for col := -1; col <= n.width; col++ {
+		for row := -1; row <= n.height; row++ {
+			b := n.w.Block(n.origin.Add(n.offset(col, row)))
+			frame := col == -1 || col == n.width || row == -1 || row == n.height
+			corner := (col == -1 || col == n.width) && (row == -1 || row == n.height)
+			switch {
+			case corner:
+				// The corners of the frame are not required for the portal to be
+				// valid.
+			case frame && name(b) != "minecraft:obsidian":
+				return false
+			case !frame && !netherInterior(b):
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// offset returns the offset of the block in the column and row passed,
+// relative to the origin of the portal.
+func (n Nether) offset(col, row int) cube.Pos {
+	if n.axis == cube.X {
+		return cube.Pos{col, row, 0}
+	}
+	return cube.Pos{0, row, col}
+}
+
+// Axis returns the axis along which the portal is oriented.
+func (n Nether) Axis() cube.Axis {
+	return n.axis
+}
+
+// Bounds returns the width and height of the interior of the portal.
+func (n Nether) Bounds() (width, height int) {
+	return n.width, n.height
+}
+
+// Positions returns the positions of all blocks in the interior of the
+// portal.
+func (n Nether) Positions() []cube.Pos {
+	positions := make([]cube.Pos, 0, n.width*n.height)
+	for col := 0; col < n.width; col++ {
+		for row := 0; row < n.height; row++ {
+			positions = append(positions, n.origin.Add(n.offset(col, row)))
+		}
+	}
+	return positions
+}
+
+// Activated checks if the interior of the portal is filled with portal blocks.
+func (n Nether) Activated() bool {
+	p := netherPortal(n.axis)
+	for _, pos := range n.Positions() {
+		if n.w.Block(pos) != p {
+			return false
+		}
+	}
+	return true
+}
+
+// Activate fills the interior of the portal with portal blocks.
+func (n Nether) Activate() {
+	p := netherPortal(n.axis)
+	for _, pos := range n.Positions() {
+		n.w.SetBlock(pos, p, nil)
+	}
+}
+
+// Deactivate removes all portal blocks from the interior of the portal.
+func (n Nether) Deactivate() {
+	a := air()
+	for _, pos := range n.Positions() {
+		if name(n.w.Block(pos)) == "minecraft:portal" {
+			n.w.SetBlock(pos, a, nil)
+		}
+	}
+}
+
+// Spawn returns the position at which entities that travel to the portal are
+// placed: The bottom centre of the interior of the portal.
+func (n Nether) Spawn() mgl64.Vec3 {
+	centre := n.origin.Vec3Middle()
+	if n.axis == cube.X {
+		return centre.Add(mgl64.Vec3{float64(n.width)/2 - 0.5})
+	}
+	return centre.Add(mgl64.Vec3{0, 0, float64(n.width)/2 - 0.5})
+}
+
+// NetherPosition translates a position in a World with the Dimension from to
+// the position a nether portal in a World with the Dimension to leads to.
+// Positions in the nether are scaled up by a factor of 8 when leading to the
+// overworld, and overworld positions are scaled down by a factor of 8 when
+// leading to the nether. Other positions are left unchanged.
+func NetherPosition(pos mgl64.Vec3, from, to world.Dimension) mgl64.Vec3 {
+	switch {
+	case from == world.Overworld && to == world.Nether:
+		return mgl64.Vec3{pos[0] / 8, pos[1], pos[2] / 8}
+	case from == world.Nether && to == world.Overworld:
+		return mgl64.Vec3{pos[0] * 8, pos[1], pos[2] * 8}
+	}
+	return pos
+}
+
+// FindOrCreateNetherPortal finds the nether portal closest to the position
+// passed in the World. If no portal is found, a new portal with the axis
+// passed is created near the position and activated. Portals are searched in a
+// radius of 16 blocks in the nether and 32 blocks in other dimensions.
+func FindOrCreateNetherPortal(w *world.World, pos cube.Pos, axis cube.Axis) Nether {
+	radius := 32
+	if w.Dimension() == world.Nether {
+		radius = 16
+	}
+	pos[1] = min(max(pos[1], w.Range()[0]+1), w.Range()[1]-4)
+	if n, ok := FindNetherPortal(w, pos, radius); ok {
+		return n
+	}
+	return CreateNetherPortal(w, pos, axis, radius)
+}
+
+// FindNetherPortal searches the activated nether portal closest to the
+// position passed, within the horizontal radius passed. False is returned if
+// no portal could be found.
+func FindNetherPortal(w *world.World, pos cube.Pos, radius int) (Nether, bool) {
+	px, pz := netherPortal(cube.X), netherPortal(cube.Z)
+	closest, dist := cube.Pos{}, math.MaxInt
+	for x := pos[0] - radius; x <= pos[0]+radius; x++ {
+		for z := pos[2] - radius; z <= pos[2]+radius; z++ {
+			for y := w.Range()[1]; y >= w.Range()[0]; y-- {
+				p := cube.Pos{x, y, z}
+				if b := w.Block(p); b != px && b != pz {
+					continue
+				}
+				if d := distSq(p, pos); d < dist {
+					closest, dist = p, d
+				}
+			}
+		}
+	}
+	if dist == math.MaxInt {
+		return Nether{}, false
+	}
+	return NetherPortalFromPos(w, closest)
+}
+
+// CreateNetherPortal creates a new activated nether portal with the axis passed
+// near the position passed. The portal is created at the position closest to
+// pos within the radius passed that has enough space for it. If no such
+// position exists, the portal is forced at pos, with a small obsidian platform
+// around it.
+func CreateNetherPortal(w *world.World, pos cube.Pos, axis cube.Axis, radius int) Nether {
+	n := Nether{w: w, axis: axis, width: 2, height: 3}
+
+	origin, dist := cube.Pos{}, math.MaxInt
+	for x := pos[0] - radius; x <= pos[0]+radius; x++ {
+		for z := pos[2] - radius; z <= pos[2]+radius; z++ {
+			for y := w.Range()[1] - 4; y > w.Range()[0]; y-- {
+				n.origin = cube.Pos{x, y, z}
+				if d := distSq(n.origin, pos); d < dist && n.fits() {
+					origin, dist = n.origin, d
+				}
+			}
+		}
+	}
+	if dist == math.MaxInt {
+		n.origin = cube.Pos{pos[0], min(max(pos[1], 70), w.Range()[1]-10), pos[2]}
+		n.build(true)
+		return n
+	}
+	n.origin = origin
+	n.build(false)
+	return n
+}
+
+// fits checks if a new portal fits at the origin of the Nether: The space the
+// portal and the area in front and behind it occupy must be air, and the
+// blocks below it must be solid.
+func (n Nether) fits() bool {
+	a := air()
+	if n.w.Block(n.origin) != a || !solidFloor(n.w, n.origin.Side(cube.FaceDown)) {
+		// Fast path: Most positions are not suitable.
+		return false
+	}
+	for col := -1; col <= n.width; col++ {
+		for depth := -1; depth <= 1; depth++ {
+			base := n.origin.Add(n.offset(col, 0)).Add(n.depth(depth))
+			if !solidFloor(n.w, base.Side(cube.FaceDown)) {
+				return false
+			}
+			for row := 0; row <= n.height; row++ {
+				if n.w.Block(base.Add(cube.Pos{0, row})) != a {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// depth returns the offset perpendicular to the axis of the portal.
+func (n Nether) depth(d int) cube.Pos {
+	if n.axis == cube.X {
+		return cube.Pos{0, 0, d}
+	}
+	return cube.Pos{d, 0, 0}
+}
+
+// build places the frame of the portal, clears the area in front and behind
+// it and activates the portal. If platform is true, obsidian platforms are
+// placed in front and behind the portal for entities to stand on.
+func (n Nether) build(platform bool) {
+	a, o := air(), obsidian()
+	for col := -1; col <= n.width; col++ {
+		for depth := -1; depth <= 1; depth++ {
+			for row := -1; row <= n.height; row++ {
+				pos := n.origin.Add(n.offset(col, row)).Add(n.depth(depth))
+				switch {
+				case depth == 0 && (col == -1 || col == n.width || row == -1 || row == n.height):
+					n.w.SetBlock(pos, o, nil)
+				case row == -1:
+					if platform && col >= 0 && col < n.width {
+						n.w.SetBlock(pos, o, nil)
+					}
+				default:
+					n.w.SetBlock(pos, a, nil)
+				}
+			}
+		}
+	}
+	n.Activate()
+}
+
+// netherInterior checks if a block may be part of the interior of a nether
+// portal.
+func netherInterior(b world.Block) bool {
+	switch name(b) {
+	case "minecraft:air", "minecraft:fire", "minecraft:soul_fire", "minecraft:portal":
+		return true
+	}
+	return false
+}
+
+// distSq returns the squared distance between two positions.
+func distSq(a, b cube.Pos) int {
+	x, y, z := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return x*x + y*y + z*z
+}
@@ -0,0 +1,45 @@
+// Package portal implements the detection, creation and activation of nether
+// and end portal structures and the mapping of positions between the worlds
+// these portals connect.
+package portal
+
+import (
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// blockByName returns the block with the name and properties passed. It
+// panics if no such block is registered.
+func blockByName(name string, properties map[string]any) world.Block {
+	b, ok := world.BlockByName(name, properties)
+	if !ok {
+		panic("could not find block " + name)
+	}
+	return b
+}
+
+// name returns the name of the block passed.
+func name(b world.Block) string {
+	n, _ := b.EncodeBlock()
+	return n
+}
+
+// air returns an air block.
+func air() world.Block {
+	return blockByName("minecraft:air", nil)
+}
+
+// obsidian returns an obsidian block.
+func obsidian() world.Block {
+	return blockByName("minecraft:obsidian", nil)
+}
+
+// netherPortal returns a nether portal block with the axis passed.
+func netherPortal(axis cube.Axis) world.Block {
+	return blockByName("minecraft:portal", map[string]any{"portal_axis": axis.String()})
+}
+
+// solidFloor checks if the block at the position passed can be stood on.
+func solidFloor(w *world.World, pos cube.Pos) bool {
+	return w.Block(pos).Model().FaceSolid(pos, cube.FaceUp, w)
+}
@@ -0,0 +1,159 @@
+package world
+
+import (
+	"time"
+
+	"github.com/stcraft/dragonfly/server/block/cube"
+)
+
+// RedstonePowerSource represents a block that emits redstone power, such as a lever, a redstone torch or redstone
+// dust. Power is emitted in two forms: Weak power only powers the block directly next to the source, while strong
+// power additionally powers the blocks around a block that conducts redstone, such as stone.
+type RedstonePowerSource interface {
+	// WeakPower returns the power level (0-15) that the block at the position passed emits towards the block
+	// on its face passed. If accountForDust is false, redstone dust should not emit power. This is used by
+	// redstone dust to prevent it from powering itself through the blocks around it.
+	WeakPower(pos cube.Pos, face cube.Face, w *World, accountForDust bool) int
+	// StrongPower returns the power level (0-15) that the block at the position passed emits into the block on
+	// its face passed, so that the receiving block, if it conducts redstone, powers the blocks around it. The
+	// accountForDust parameter has the same meaning as in WeakPower.
+	StrongPower(pos cube.Pos, face cube.Face, w *World, accountForDust bool) int
+}
+
+// RedstonePower returns the redstone power (0-15) that the block at the position passed emits towards the
+// block on its face passed. If the block at the position conducts redstone, the strong power it receives from
+// the blocks around it is included.
+func (w *World) RedstonePower(pos cube.Pos, face cube.Face, accountForDust bool) int {
+	if w == nil || pos.OutOfBounds(w.Range()) {
+		return 0
+	}
+	b, power := w.Block(pos), 0
+	if src, ok := b.(RedstonePowerSource); ok {
+		power = src.WeakPower(pos, face, w, accountForDust)
+	}
+	if power == 15 || !conductsRedstone(b, pos, w) {
+		return power
+	}
+	for _, f := range cube.Faces() {
+		side := pos.Side(f)
+		if src, ok := w.Block(side).(RedstonePowerSource); ok {
+			power = max(power, src.StrongPower(side, f.Opposite(), w, accountForDust))
+			if power == 15 {
+				break
+			}
+		}
+	}
+	return power
+}
+
+// ReceivedRedstonePower returns the highest redstone power (0-15) that the block at the position passed
+// receives from any of the blocks around it.
+func (w *World) ReceivedRedstonePower(pos cube.Pos, accountForDust bool) int {
+	power := 0
+	for _, f := range cube.Faces() {
+		power = max(power, w.RedstonePower(pos.Side(f), f.Opposite(), accountForDust))
+		if power == 15 {
+			break
+		}
+	}
+	return power
+}
+
+// ConductsRedstone checks if the block at the position passed conducts redstone power. Blocks that conduct
+// redstone are full, solid blocks that block light and do not emit power themselves, such as stone.
+func (w *World) ConductsRedstone(pos cube.Pos) bool {
+	if w == nil || pos.OutOfBounds(w.Range()) {
+		return false
+	}
+	return conductsRedstone(w.Block(pos), pos, w)
+}
+
+// UpdateRedstone notifies the blocks around the position passed that the redstone power emitted by the block
+// at that position changed. Besides the blocks directly around the position, the blocks around any block
+// adjacent to it that conducts redstone are also updated, as power may be conducted through these blocks.
+// UpdateRedstone should be called by RedstonePowerSource implementations when their output changes.
+func (w *World) UpdateRedstone(pos cube.Pos) {
+	if w == nil || pos.OutOfBounds(w.Range()) {
+		return
+	}
+	w.doBlockUpdatesAround(pos)
+	for _, f := range cube.Faces() {
+		if side := pos.Side(f); w.ConductsRedstone(side) {
+			w.doBlockUpdatesAround(side)
+		}
+	}
+}
+
+// RecordRedstoneToggle records that the redstone component at the position passed was toggled. The toggle
+// expires after the duration passed, measured in ticks of the World. The amount of toggles at the position
+// that have not yet expired, including this one, is returned. RecordRedstoneToggle may be used by components
+// such as redstone torches to burn out when toggled too often.
+func (w *World) RecordRedstoneToggle(pos cube.Pos, expiry time.Duration) int {
+	if w == nil || pos.OutOfBounds(w.Range()) {
+		return 0
+	}
+	w.set.Lock()
+	t := w.set.CurrentTick
+	w.set.Unlock()
+
+	w.toggleMu.Lock()
+	defer w.toggleMu.Unlock()
+	toggles := append(activeToggles(w.redstoneToggles[pos], t), t+expiry.Nanoseconds()/int64(time.Second/20))
+	w.redstoneToggles[pos] = toggles
+	return len(toggles)
+}
+
+// RedstoneToggles returns the amount of toggles recorded using RecordRedstoneToggle for the redstone component
+// at the position passed that have not yet expired.
+func (w *World) RedstoneToggles(pos cube.Pos) int {
+	if w == nil || pos.OutOfBounds(w.Range()) {
+		return 0
+	}
+	w.set.Lock()
+	t := w.set.CurrentTick
+	w.set.Unlock()
+
+	w.toggleMu.Lock()
+	defer w.toggleMu.Unlock()
+	return len(activeToggles(w.redstoneToggles[pos], t))
+}
+
+// pruneRedstoneToggles removes the toggles recorded using RecordRedstoneToggle that have expired at the tick
+// passed, removing positions without any remaining toggles.
+func (t ticker) pruneRedstoneToggles(tick int64) {
+	t.w.toggleMu.Lock()
+	defer t.w.toggleMu.Unlock()
+	for pos, toggles := range t.w.redstoneToggles {
+		if toggles = activeToggles(toggles, tick); len(toggles) == 0 {
+			delete(t.w.redstoneToggles, pos)
+			continue
+		}
+		t.w.redstoneToggles[pos] = toggles
+	}
+}
+
+// activeToggles returns the expiry ticks of the toggles passed that have not yet expired at the tick passed.
+// The toggles are sorted by their expiry tick, as they are recorded in order.
+func activeToggles(toggles []int64, tick int64) []int64 {
+	for len(toggles) > 0 && toggles[0] <= tick {
+		toggles = toggles[1:]
+	}
+	return toggles
+}
+
+// conductsRedstone checks if the Block passed, placed at a specific position, conducts redstone power.
+func conductsRedstone(b Block, pos cube.Pos, w *World) bool {
+	if _, ok := b.(RedstonePowerSource); ok {
+		return false
+	}
+	if d, ok := b.(lightDiffuser); ok && d.LightDiffusionLevel() < 15 {
+		return false
+	}
+	m := b.Model()
+	for _, f := range cube.Faces() {
+		if !m.FaceSolid(pos, f, w) {
+			return false
+		}
+	}
+	return true
+}
@@ -17,6 +17,9 @@ type Settings struct {
 	Name string
 	// Spawn is the spawn position of the World. New players that join the world will be spawned here.
 	Spawn cube.Pos
+	// Seed is the seed of the World. It is not used by the World itself, but may be used to create the
+	// Generator of the World, so that its terrain is generated the same way every time it is loaded.
+	Seed int64
 	// Time is the current time of the World. It advances every tick if TimeCycle is set to true.
 	Time int64
 	// TimeCycle specifies if the time should advance every tick. If set to false, time won't change.
@@ -42,6 +45,9 @@ type Settings struct {
 	// TickRange is the radius in chunks around a Viewer that has its blocks and entities ticked when the world is
 	// ticked. If set to 0, blocks and entities will never be ticked.
 	TickRange int32
+	// RequiredSleepingPercentage is the percentage of players in the World that must be sleeping for the night to
+	// be skipped. If set to 0, a single sleeping player is enough to skip the night.
+	RequiredSleepingPercentage int
 }
 
 // defaultSettings returns the default Settings for a new World.
@@ -53,5 +59,30 @@ func defaultSettings() *Settings {
 		TimeCycle:       true,
 		WeatherCycle:    true,
 		TickRange:       6,
+
+		RequiredSleepingPercentage: 100,
+	}
+}
+
+// clone returns a copy of the Settings that is not shared with any World.
+func (s *Settings) clone() *Settings {
+	s.Lock()
+	defer s.Unlock()
+	return &Settings{
+		Name:                       s.Name,
+		Spawn:                      s.Spawn,
+		Seed:                       s.Seed,
+		Time:                       s.Time,
+		TimeCycle:                  s.TimeCycle,
+		RainTime:                   s.RainTime,
+		Raining:                    s.Raining,
+		ThunderTime:                s.ThunderTime,
+		Thundering:                 s.Thundering,
+		WeatherCycle:               s.WeatherCycle,
+		CurrentTick:                s.CurrentTick,
+		DefaultGameMode:            s.DefaultGameMode,
+		Difficulty:                 s.Difficulty,
+		TickRange:                  s.TickRange,
+		RequiredSleepingPercentage: s.RequiredSleepingPercentage,
 	}
 }
@@ -0,0 +1,80 @@
+package world
+
+import (
+	"github.com/stcraft/dragonfly/server/block/cube"
+)
+
+// Sleeper represents an entity that is able to sleep in a bed, such as a player. The night in a World is skipped
+// once enough of the Sleepers in it are sleeping, as specified by World.RequiredSleepingPercentage.
+type Sleeper interface {
+	Entity
+	// Sleeping returns the position of the bed that the Sleeper is sleeping in. False is returned if the Sleeper is
+	// not currently sleeping.
+	Sleeping() (cube.Pos, bool)
+	// Wake wakes the Sleeper up if it is sleeping.
+	Wake()
+}
+
+const (
+	// sleepDuration is the amount of ticks that enough Sleepers must be sleeping before the night is skipped.
+	sleepDuration = 100
+	// dayDuration is the amount of ticks in a full day.
+	dayDuration = 24000
+)
+
+// RequiredSleepingPercentage returns the percentage of players in the World that must be sleeping for the night to
+// be skipped.
+func (w *World) RequiredSleepingPercentage() int {
+	if w == nil {
+		return 100
+	}
+	w.set.Lock()
+	defer w.set.Unlock()
+	return w.set.RequiredSleepingPercentage
+}
+
+// SetRequiredSleepingPercentage changes the percentage of players in the World that must be sleeping for the night
+// to be skipped. If set to 0, a single sleeping player is enough to skip the night.
+func (w *World) SetRequiredSleepingPercentage(v int) {
+	if w == nil {
+		return
+	}
+	w.set.Lock()
+	defer w.set.Unlock()
+	w.set.RequiredSleepingPercentage = max(v, 0)
+}
+
+// tickSleep checks if enough Sleepers in the World are sleeping to skip the night. If they have been sleeping for
+// long enough, the time is advanced to the next morning, the weather is cleared and all Sleepers are woken up.
+func (t ticker) tickSleep() {
+	if !t.w.conf.Dim.TimeCycle() {
+		return
+	}
+	var sleepers []Sleeper
+	sleeping := 0
+	for _, e := range t.w.Entities() {
+		if s, ok := e.(Sleeper); ok {
+			sleepers = append(sleepers, s)
+			if _, ok := s.Sleeping(); ok {
+				sleeping++
+			}
+		}
+	}
+	required := max((len(sleepers)*t.w.RequiredSleepingPercentage()+99)/100, 1)
+	if sleeping < required {
+		t.w.sleepTicks = 0
+		return
+	}
+	if t.w.sleepTicks++; t.w.sleepTicks < sleepDuration {
+		return
+	}
+	t.w.sleepTicks = 0
+
+	tim := t.w.Time()
+	t.w.SetTime(tim + dayDuration - tim%dayDuration)
+	// Stopping the rain also stops any thunder.
+	t.w.StopRaining()
+	for _, s := range sleepers {
+		s.Wake()
+	}
+}
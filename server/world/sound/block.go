@@ -114,12 +114,36 @@ type FenceGateClose struct {
 	sound
 }
 
+// PowerOn is a sound played when a redstone component, such as a lever or a button, is turned on.
+type PowerOn struct {
+	// Block is the block which is being turned on, for which a sound should be played. The sound played depends
+	// on the block type.
+	Block world.Block
+
+	sound
+}
+
+// PowerOff is a sound played when a redstone component, such as a lever or a button, is turned off.
+type PowerOff struct {
+	// Block is the block which is being turned off, for which a sound should be played. The sound played depends
+	// on the block type.
+	Block world.Block
+
+	sound
+}
+
 // DoorCrash is a sound played when a door is forced open.
 type DoorCrash struct{ sound }
 
 // Click is a clicking sound.
 type Click struct{ sound }
 
+// ClickFail is a clicking sound played when a dispenser or dropper is activated while it is empty.
+type ClickFail struct{ sound }
+
+// Launch is a sound played when a dispenser launches a projectile.
+type Launch struct{ sound }
+
 // Ignite is a sound played when using a flint & steel.
 type Ignite struct{ sound }
 
@@ -167,6 +191,9 @@ type BlastFurnaceCrackle struct{ sound }
 // SmokerCrackle is a sound played every one to five seconds from a smoker.
 type SmokerCrackle struct{ sound }
 
+// PotionBrewed is a sound played when a brewing stand finishes brewing potions.
+type PotionBrewed struct{ sound }
+
 // ComposterEmpty is a sound played when a composter has been emptied.
 type ComposterEmpty struct{ sound }
 
@@ -182,6 +209,16 @@ type ComposterReady struct{ sound }
 // LecternBookPlace is a sound played when a book is placed in a lectern.
 type LecternBookPlace struct{ sound }
 
+// EndPortalCreated is a sound played when an end portal is created by filling
+// all end portal frames around it with eyes of ender.
+type EndPortalCreated struct{ sound }
+
+// PistonExtend is a sound played when a piston extends its arm.
+type PistonExtend struct{ sound }
+
+// PistonRetract is a sound played when a piston retracts its arm.
+type PistonRetract struct{ sound }
+
 // sound implements the world.Sound interface.
 type sound struct{}
 
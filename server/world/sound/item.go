@@ -70,3 +70,41 @@ type GoatHorn struct {
 // FireCharge is a sound played when a player lights a block on fire with a fire charge, or when a dispenser or a
 // blaze shoots a fireball.
 type FireCharge struct{ sound }
+
+// ShieldBlock is a sound played when a shield blocks an attack.
+type ShieldBlock struct{ sound }
+
+// TridentThrow is a sound played when a trident is thrown.
+type TridentThrow struct{ sound }
+
+// TridentHit is a sound played when a thrown trident hits an entity.
+type TridentHit struct{ sound }
+
+// TridentHitGround is a sound played when a thrown trident hits the ground.
+type TridentHitGround struct{ sound }
+
+// TridentReturn is a sound played when a trident enchanted with loyalty starts returning to its owner.
+type TridentReturn struct{ sound }
+
+// TridentRiptide is a sound played when a player launches itself using a trident enchanted with riptide.
+type TridentRiptide struct {
+	// Level is the level of the riptide enchantment. The sound played differs depending on this field.
+	Level int
+
+	sound
+}
+
+// TridentThunder is a sound played when a trident enchanted with channeling summons a lightning bolt.
+type TridentThunder struct{ sound }
+
+// CrossbowLoad is a sound played when a crossbow is done loading.
+type CrossbowLoad struct {
+	// QuickCharge specifies if the crossbow was enchanted with quick charge. The sound played differs depending
+	// on this field.
+	QuickCharge bool
+
+	sound
+}
+
+// CrossbowShoot is a sound played when a crossbow is shot.
+type CrossbowShoot struct{ sound }
@@ -0,0 +1,438 @@
+package world
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+)
+
+// SpawnCategory is a category of entities that spawn naturally. Every category
+// has its own cap on the amount of entities, spawning rate and requirements
+// for the positions that entities may spawn at.
+type SpawnCategory uint8
+
+const (
+	// SpawnCategoryMonster holds hostile entities, such as zombies and
+	// skeletons, that spawn in the dark.
+	SpawnCategoryMonster SpawnCategory = iota
+	// SpawnCategoryCreature holds passive entities, such as cows and pigs,
+	// that spawn in well-lit areas. Creatures spawn far less often than
+	// entities of other categories.
+	SpawnCategoryCreature
+	// SpawnCategoryAmbient holds entities, such as bats, that spawn without
+	// any special interaction with the world.
+	SpawnCategoryAmbient
+	// SpawnCategoryWaterCreature holds entities, such as squids, that spawn in
+	// water.
+	SpawnCategoryWaterCreature
+)
+
+// SpawnCategories returns all SpawnCategories.
+func SpawnCategories() []SpawnCategory {
+	return []SpawnCategory{SpawnCategoryMonster, SpawnCategoryCreature, SpawnCategoryAmbient, SpawnCategoryWaterCreature}
+}
+
+// String returns the name of the SpawnCategory.
+func (c SpawnCategory) String() string {
+	switch c {
+	case SpawnCategoryMonster:
+		return "monster"
+	case SpawnCategoryCreature:
+		return "creature"
+	case SpawnCategoryAmbient:
+		return "ambient"
+	case SpawnCategoryWaterCreature:
+		return "water_creature"
+	}
+	panic("should never happen")
+}
+
+// defaultCap returns the vanilla cap of the SpawnCategory.
+func (c SpawnCategory) defaultCap() int {
+	switch c {
+	case SpawnCategoryMonster:
+		return 70
+	case SpawnCategoryCreature:
+		return 10
+	case SpawnCategoryAmbient:
+		return 15
+	}
+	return 5
+}
+
+// interval returns the amount of ticks between two attempts to spawn entities
+// of the SpawnCategory.
+func (c SpawnCategory) interval() int64 {
+	if c == SpawnCategoryCreature {
+		return 400
+	}
+	return 1
+}
+
+// SpawnEntry is an entry in the spawn table of a Biome. It describes an
+// entity that may spawn naturally in the Biome.
+type SpawnEntry struct {
+	// Category is the SpawnCategory of the entity. It determines the light
+	// level and surface that the entity requires to spawn.
+	Category SpawnCategory
+	// Weight is the weight of the entry, relative to the weights of other
+	// entries with the same Category. Entries with a higher weight are
+	// selected more often.
+	Weight int
+	// MinGroup and MaxGroup are the minimum and maximum amount of entities
+	// that spawn together in a single group.
+	MinGroup, MaxGroup int
+	// Spawnable is an optional function that performs additional checks on
+	// the position that the entity would spawn at, such as the block that
+	// the entity spawns on. If it returns false, the entity is not spawned.
+	Spawnable func(w *World, pos cube.Pos) bool
+	// New creates the Entity to spawn at the position passed.
+	New func(pos mgl64.Vec3) Entity
+}
+
+// SpawnConfig holds settings that influence the natural spawning and
+// despawning of entities in a World.
+type SpawnConfig struct {
+	// Disabled disables natural spawning and despawning of entities in the
+	// World altogether.
+	Disabled bool
+	// Entries returns the SpawnEntries of the Biome passed. If Entries is nil,
+	// no entities spawn or despawn naturally.
+	Entries func(b Biome) []SpawnEntry
+	// Caps holds the maximum amount of entities of a SpawnCategory, per 289
+	// chunks loaded around the viewers of the World. Categories that are not
+	// present in Caps use the vanilla cap. A cap of 0 or lower prevents
+	// entities of the category from spawning.
+	Caps map[SpawnCategory]int
+	// DisableDespawning prevents entities that are far away from players from
+	// despawning.
+	DisableDespawning bool
+}
+
+// enabled checks if natural spawning and despawning are enabled.
+func (conf SpawnConfig) enabled() bool {
+	return !conf.Disabled && conf.Entries != nil
+}
+
+// limit returns the cap of the SpawnCategory passed.
+func (conf SpawnConfig) limit(c SpawnCategory) int {
+	if v, ok := conf.Caps[c]; ok {
+		return v
+	}
+	return c.defaultCap()
+}
+
+// NaturalEntity is an Entity that counts towards the caps of natural spawning
+// and that may despawn when no players are nearby.
+type NaturalEntity interface {
+	Entity
+	// SpawnCategory returns the SpawnCategory that the entity counts towards.
+	SpawnCategory() SpawnCategory
+	// Persistent checks if the entity should never despawn.
+	Persistent() bool
+}
+
+const (
+	// spawnChunkRadius is the maximum radius in chunks around loaders in
+	// which entities spawn naturally.
+	spawnChunkRadius = 8
+	// spawnChunkArea is the amount of chunks in an area with a radius of
+	// spawnChunkRadius. Caps are scaled by the amount of eligible chunks
+	// relative to this number.
+	spawnChunkArea = (spawnChunkRadius*2 + 1) * (spawnChunkRadius*2 + 1)
+	// spawnMinDistance is the minimum distance from players at which entities
+	// spawn naturally.
+	spawnMinDistance = 24
+	// despawnDistance is the distance from players beyond which entities
+	// start despawning randomly.
+	despawnDistance = 32
+	// despawnInstantDistance is the distance from players beyond which
+	// entities despawn immediately. Entities also never spawn this far away.
+	despawnInstantDistance = 128
+)
+
+// tickSpawning attempts to naturally spawn entities in the chunks around the
+// loaders passed.
+func (t ticker) tickSpawning(loaders []*Loader, tick int64) {
+	conf := t.w.conf.Spawning
+	if !conf.enabled() {
+		return
+	}
+	categories := make([]SpawnCategory, 0, 4)
+	for _, c := range SpawnCategories() {
+		if tick%c.interval() != 0 || conf.limit(c) <= 0 {
+			continue
+		}
+		if c == SpawnCategoryMonster && t.w.Difficulty() == DifficultyPeaceful {
+			continue
+		}
+		categories = append(categories, c)
+	}
+	r := int32(min(t.w.tickRange(), spawnChunkRadius))
+	if len(categories) == 0 || r == 0 {
+		return
+	}
+
+	loaded := make([]ChunkPos, 0, len(loaders))
+	for _, loader := range loaders {
+		loader.mu.RLock()
+		loaded = append(loaded, loader.pos)
+		loader.mu.RUnlock()
+	}
+	eligible := make(map[ChunkPos]struct{})
+	t.w.chunkMu.Lock()
+	for pos := range t.w.chunks {
+		if t.anyWithinDistance(pos, loaded, r) {
+			eligible[pos] = struct{}{}
+		}
+	}
+	t.w.chunkMu.Unlock()
+
+	players := t.players()
+	if len(players) == 0 {
+		return
+	}
+	counts := make(map[SpawnCategory]int, len(categories))
+	for _, e := range t.w.Entities() {
+		if n, ok := e.(NaturalEntity); ok {
+			if _, ok := eligible[chunkPosFromVec3(n.Position())]; ok {
+				counts[n.SpawnCategory()]++
+			}
+		}
+	}
+	for _, c := range categories {
+		limit := conf.limit(c) * len(eligible) / spawnChunkArea
+		for pos := range eligible {
+			if counts[c] >= limit {
+				break
+			}
+			counts[c] += t.spawnInChunk(c, pos, eligible, players)
+		}
+	}
+}
+
+// spawnInChunk attempts to spawn groups of entities of the SpawnCategory
+// passed around a random position in the chunk at the ChunkPos passed. The
+// amount of entities spawned is returned.
+func (t ticker) spawnInChunk(c SpawnCategory, chunkPos ChunkPos, eligible map[ChunkPos]struct{}, players []mgl64.Vec3) (n int) {
+	r := t.w.r
+	x, z := int(chunkPos[0]<<4)+r.Intn(16), int(chunkPos[1]<<4)+r.Intn(16)
+	low, high := t.w.Range()[0], t.w.HighestBlock(x, z)+1
+	if high < low {
+		return 0
+	}
+	y := low + r.Intn(high-low+1)
+	if start := (cube.Pos{x, y, z}); len(t.w.Block(start).Model().BBox(start, t.w)) != 0 {
+		return 0
+	}
+
+	for pack := 0; pack < 3; pack++ {
+		var (
+			entry         SpawnEntry
+			selected      bool
+			size, spawned int
+			packX, packZ  = x, z
+			attempts      = 1 + r.Intn(4)
+		)
+		for i := 0; i < attempts && (!selected || spawned < size); i++ {
+			packX += r.Intn(6) - r.Intn(6)
+			packZ += r.Intn(6) - r.Intn(6)
+			pos := cube.Pos{packX, y, packZ}
+			if _, ok := eligible[chunkPosFromBlockPos(pos)]; !ok {
+				continue
+			}
+			vec := mgl64.Vec3{float64(packX) + 0.5, float64(y), float64(packZ) + 0.5}
+			if dist := nearestDistance(vec, players); dist < spawnMinDistance || dist > despawnInstantDistance {
+				continue
+			}
+			if !selected {
+				if entry, selected = t.selectSpawnEntry(c, t.w.Biome(pos)); !selected {
+					break
+				}
+				size = entry.MinGroup
+				if entry.MaxGroup > entry.MinGroup {
+					size += r.Intn(entry.MaxGroup - entry.MinGroup + 1)
+				}
+			}
+			if !t.spawnable(entry, pos) {
+				continue
+			}
+			e := entry.New(vec)
+			if !t.fits(e) {
+				_ = e.Close()
+				continue
+			}
+			t.w.AddEntity(e)
+			spawned++
+			n++
+		}
+	}
+	return n
+}
+
+// selectSpawnEntry selects a random SpawnEntry of the SpawnCategory passed
+// from the entries of the Biome passed, taking into account the weight of
+// each entry. False is returned if the Biome has no entries of the category.
+func (t ticker) selectSpawnEntry(c SpawnCategory, b Biome) (SpawnEntry, bool) {
+	var (
+		entries []SpawnEntry
+		total   int
+	)
+	for _, entry := range t.w.conf.Spawning.Entries(b) {
+		if entry.Category == c && entry.Weight > 0 && entry.New != nil {
+			entries = append(entries, entry)
+			total += entry.Weight
+		}
+	}
+	if total == 0 {
+		return SpawnEntry{}, false
+	}
+	v := t.w.r.Intn(total)
+	for _, entry := range entries {
+		if v -= entry.Weight; v < 0 {
+			return entry, true
+		}
+	}
+	panic("should never happen")
+}
+
+// spawnable checks if an entity of the SpawnEntry passed could spawn at a
+// position, based on the surface, light level and the Spawnable function of
+// the entry.
+func (t ticker) spawnable(entry SpawnEntry, pos cube.Pos) bool {
+	w := t.w
+	if entry.Category == SpawnCategoryWaterCreature {
+		for _, p := range []cube.Pos{pos, pos.Side(cube.FaceDown)} {
+			if l, ok := w.Liquid(p); !ok || l.LiquidType() != "water" {
+				return false
+			}
+		}
+	} else {
+		below := pos.Side(cube.FaceDown)
+		if !w.Block(below).Model().FaceSolid(below, cube.FaceUp, w) {
+			return false
+		}
+		for _, p := range []cube.Pos{pos, pos.Side(cube.FaceUp)} {
+			if _, ok := w.Liquid(p); ok || len(w.Block(p).Model().BBox(p, w)) != 0 {
+				return false
+			}
+		}
+	}
+	switch entry.Category {
+	case SpawnCategoryMonster:
+		if w.blockLight(pos) > 0 {
+			return false
+		}
+		sky := int(w.SkyLight(pos)) - int(w.skyDarkening())
+		if sky > w.r.Intn(8) {
+			return false
+		}
+	case SpawnCategoryCreature:
+		if w.Light(pos) <= 8 {
+			return false
+		}
+	}
+	return entry.Spawnable == nil || entry.Spawnable(w, pos)
+}
+
+// fits checks if the Entity passed fits at its position without colliding
+// with any blocks.
+func (t ticker) fits(e Entity) bool {
+	box := e.Type().BBox(e).Translate(e.Position()).Grow(-0.01)
+	minPos, maxPos := cube.PosFromVec3(box.Min()), cube.PosFromVec3(box.Max())
+	for x := minPos[0]; x <= maxPos[0]; x++ {
+		for y := minPos[1]; y <= maxPos[1]; y++ {
+			for z := minPos[2]; z <= maxPos[2]; z++ {
+				pos := cube.Pos{x, y, z}
+				for _, b := range t.w.Block(pos).Model().BBox(pos, t.w) {
+					if b.Translate(pos.Vec3()).IntersectsWith(box) {
+						return false
+					}
+				}
+			}
+		}
+	}
+	return true
+}
+
+// despawn despawns the NaturalEntities passed that are too far away from the
+// players passed. Entities beyond a distance of 128 blocks are despawned
+// immediately, while those beyond 32 blocks have a small chance of despawning
+// every tick.
+func (t ticker) despawn(entities []NaturalEntity, players []mgl64.Vec3) {
+	if len(players) == 0 || t.w.conf.Spawning.DisableDespawning {
+		return
+	}
+	for _, e := range entities {
+		if e.Persistent() || e.World() != t.w {
+			continue
+		}
+		dist := nearestDistance(e.Position(), players)
+		if dist > despawnInstantDistance || (dist > despawnDistance && t.w.r.Intn(800) == 0) {
+			_ = e.Close()
+		}
+	}
+}
+
+// players returns the positions of all players in the World that influence
+// natural spawning and despawning. Spectators are not included.
+func (t ticker) players() []mgl64.Vec3 {
+	t.w.entityMu.RLock()
+	defer t.w.entityMu.RUnlock()
+	var players []mgl64.Vec3
+	for e := range t.w.entities {
+		if p, ok := e.(interface{ GameMode() GameMode }); ok && p.GameMode().Visible() {
+			players = append(players, e.Position())
+		}
+	}
+	return players
+}
+
+// nearestDistance returns the distance from the position passed to the
+// closest position of the positions passed.
+func nearestDistance(pos mgl64.Vec3, positions []mgl64.Vec3) float64 {
+	nearest := math.MaxFloat64
+	for _, p := range positions {
+		nearest = math.Min(nearest, p.Sub(pos).Len())
+	}
+	return nearest
+}
+
+// blockLight returns the light level emitted by blocks at the position
+// passed, ignoring skylight.
+func (w *World) blockLight(pos cube.Pos) uint8 {
+	if w == nil || pos.OutOfBounds(w.Range()) {
+		return 0
+	}
+	c := w.chunk(chunkPosFromBlockPos(pos))
+	defer c.Unlock()
+	return c.SubChunk(int16(pos[1])).BlockLight(uint8(pos[0]&0xf), uint8(pos[1]&0xf), uint8(pos[2]&0xf))
+}
+
+// skyDarkening returns the amount by which the skylight is reduced due to
+// the time of day and weather, ranging from 0 at noon to 11 at midnight.
+func (w *World) skyDarkening() uint8 {
+	if !w.conf.Dim.TimeCycle() {
+		return 0
+	}
+	w.set.Lock()
+	t, raining, thundering := float64(w.set.Time%24000), w.set.Raining, w.set.Raining && w.set.Thundering
+	w.set.Unlock()
+
+	// The angle of the sun is eased slightly so that days and nights last
+	// longer than dusk and dawn.
+	f := t/24000 - 0.25
+	if f < 0 {
+		f++
+	}
+	angle := (f*2 + (0.5 - math.Cos(f*math.Pi)/2)) / 3
+	brightness := mgl64.Clamp(math.Cos(angle*math.Pi*2)*2+0.5, 0, 1)
+	if raining {
+		brightness *= 1 - 5.0/16
+	}
+	if thundering {
+		brightness *= 1 - 5.0/16
+	}
+	return uint8((1 - brightness) * 11)
+}
@@ -0,0 +1,116 @@
+package structure
+
+// javaRename holds the Bedrock Edition name, and optionally properties, of a Java Edition block with a
+// different name.
+type javaRename struct {
+	name       string
+	properties map[string]string
+}
+
+// javaRenames holds the Bedrock Edition equivalent of Java Edition blocks that have a different name in
+// Bedrock Edition, indexed by their Java Edition name. Blocks with equal names in both editions are not
+// present in this map.
+var javaRenames = map[string]javaRename{
+	"minecraft:cave_air":                     {name: "minecraft:air"},
+	"minecraft:void_air":                     {name: "minecraft:air"},
+	"minecraft:grass":                        {name: "minecraft:tallgrass", properties: map[string]string{"tall_grass_type": "default"}},
+	"minecraft:short_grass":                  {name: "minecraft:tallgrass", properties: map[string]string{"tall_grass_type": "default"}},
+	"minecraft:fern":                         {name: "minecraft:tallgrass", properties: map[string]string{"tall_grass_type": "fern"}},
+	"minecraft:terracotta":                   {name: "minecraft:hardened_clay"},
+	"minecraft:snow_block":                   {name: "minecraft:snow"},
+	"minecraft:snow":                         {name: "minecraft:snow_layer"},
+	"minecraft:spawner":                      {name: "minecraft:mob_spawner"},
+	"minecraft:note_block":                   {name: "minecraft:noteblock"},
+	"minecraft:cobweb":                       {name: "minecraft:web"},
+	"minecraft:sugar_cane":                   {name: "minecraft:reeds"},
+	"minecraft:nether_portal":                {name: "minecraft:portal"},
+	"minecraft:lily_pad":                     {name: "minecraft:waterlily"},
+	"minecraft:magma_block":                  {name: "minecraft:magma"},
+	"minecraft:jack_o_lantern":               {name: "minecraft:lit_pumpkin"},
+	"minecraft:dirt_path":                    {name: "minecraft:grass_path"},
+	"minecraft:end_stone_bricks":             {name: "minecraft:end_bricks"},
+	"minecraft:melon":                        {name: "minecraft:melon_block"},
+	"minecraft:slime_block":                  {name: "minecraft:slime"},
+	"minecraft:powered_rail":                 {name: "minecraft:golden_rail"},
+	"minecraft:bricks":                       {name: "minecraft:brick_block"},
+	"minecraft:wall_torch":                   {name: "minecraft:torch"},
+	"minecraft:oak_sign":                     {name: "minecraft:standing_sign"},
+	"minecraft:oak_wall_sign":                {name: "minecraft:wall_sign"},
+	"minecraft:nether_quartz_ore":            {name: "minecraft:quartz_ore"},
+	"minecraft:redstone_wall_torch":          {name: "minecraft:redstone_torch"},
+	"minecraft:light_gray_glazed_terracotta": {name: "minecraft:silver_glazed_terracotta"},
+	"minecraft:oak_door":                     {name: "minecraft:wooden_door"},
+	"minecraft:oak_trapdoor":                 {name: "minecraft:trapdoor"},
+	"minecraft:oak_fence_gate":               {name: "minecraft:fence_gate"},
+	"minecraft:oak_button":                   {name: "minecraft:wooden_button"},
+	"minecraft:oak_pressure_plate":           {name: "minecraft:wooden_pressure_plate"},
+	"minecraft:stone_bricks":                 {name: "minecraft:stonebrick"},
+	"minecraft:repeater":                     {name: "minecraft:unpowered_repeater"},
+	"minecraft:comparator":                   {name: "minecraft:unpowered_comparator"},
+	"minecraft:rose_bush":                    {name: "minecraft:double_plant", properties: map[string]string{"double_plant_type": "rose"}},
+	"minecraft:sunflower":                    {name: "minecraft:double_plant", properties: map[string]string{"double_plant_type": "sunflower"}},
+	"minecraft:lilac":                        {name: "minecraft:double_plant", properties: map[string]string{"double_plant_type": "syringa"}},
+	"minecraft:peony":                        {name: "minecraft:double_plant", properties: map[string]string{"double_plant_type": "paeonia"}},
+	"minecraft:tall_grass":                   {name: "minecraft:double_plant", properties: map[string]string{"double_plant_type": "grass"}},
+	"minecraft:large_fern":                   {name: "minecraft:double_plant", properties: map[string]string{"double_plant_type": "fern"}},
+	"minecraft:dandelion":                    {name: "minecraft:yellow_flower"},
+	"minecraft:water_cauldron":               {name: "minecraft:cauldron"},
+	"minecraft:lava_cauldron":                {name: "minecraft:cauldron"},
+	"minecraft:infested_stone":               {name: "minecraft:monster_egg"},
+	"minecraft:red_nether_bricks":            {name: "minecraft:red_nether_brick"},
+	"minecraft:nether_bricks":                {name: "minecraft:nether_brick"},
+}
+
+// legacyID holds the legacy Bedrock Edition name of a block with a specific legacy Java Edition ID. If meta
+// is not -1, the data value of the block is replaced with meta, for blocks that were stored under a
+// different ID in Bedrock Edition.
+type legacyID struct {
+	name string
+	meta int16
+}
+
+// legacyIDs holds the legacy Bedrock Edition names of blocks, indexed by their legacy Java Edition ID, as used
+// in MCEdit schematics. IDs that are not present in Bedrock Edition have an empty name.
+var legacyIDs = func() []legacyID {
+	names := [...]string{
+		"air", "stone", "grass", "dirt", "cobblestone", "planks", "sapling", "bedrock", "flowing_water", "water",
+		"flowing_lava", "lava", "sand", "gravel", "gold_ore", "iron_ore", "coal_ore", "log", "leaves", "sponge",
+		"glass", "lapis_ore", "lapis_block", "dispenser", "sandstone", "noteblock", "bed", "golden_rail", "detector_rail", "sticky_piston",
+		"web", "tallgrass", "deadbush", "piston", "pistonArmCollision", "wool", "", "yellow_flower", "red_flower", "brown_mushroom",
+		"red_mushroom", "gold_block", "iron_block", "double_stone_slab", "stone_slab", "brick_block", "tnt", "bookshelf", "mossy_cobblestone", "obsidian",
+		"torch", "fire", "mob_spawner", "oak_stairs", "chest", "redstone_wire", "diamond_ore", "diamond_block", "crafting_table", "wheat",
+		"farmland", "furnace", "lit_furnace", "standing_sign", "wooden_door", "ladder", "rail", "stone_stairs", "wall_sign", "lever",
+		"stone_pressure_plate", "iron_door", "wooden_pressure_plate", "redstone_ore", "lit_redstone_ore", "unlit_redstone_torch", "redstone_torch", "stone_button", "snow_layer", "ice",
+		"snow", "cactus", "clay", "reeds", "jukebox", "fence", "pumpkin", "netherrack", "soul_sand", "glowstone",
+		"portal", "lit_pumpkin", "cake", "unpowered_repeater", "powered_repeater", "stained_glass", "trapdoor", "monster_egg", "stonebrick", "brown_mushroom_block",
+		"red_mushroom_block", "iron_bars", "glass_pane", "melon_block", "pumpkin_stem", "melon_stem", "vine", "fence_gate", "brick_stairs", "stone_brick_stairs",
+		"mycelium", "waterlily", "nether_brick", "nether_brick_fence", "nether_brick_stairs", "nether_wart", "enchanting_table", "brewing_stand", "cauldron", "end_portal",
+		"end_portal_frame", "end_stone", "dragon_egg", "redstone_lamp", "lit_redstone_lamp", "double_wooden_slab", "wooden_slab", "cocoa", "sandstone_stairs", "emerald_ore",
+		"ender_chest", "tripwire_hook", "tripWire", "emerald_block", "spruce_stairs", "birch_stairs", "jungle_stairs", "command_block", "beacon", "cobblestone_wall",
+		"flower_pot", "carrots", "potatoes", "wooden_button", "skull", "anvil", "trapped_chest", "light_weighted_pressure_plate", "heavy_weighted_pressure_plate", "unpowered_comparator",
+		"powered_comparator", "daylight_detector", "redstone_block", "quartz_ore", "hopper", "quartz_block", "quartz_stairs", "activator_rail", "dropper", "stained_hardened_clay",
+		"stained_glass_pane", "leaves2", "log2", "acacia_stairs", "dark_oak_stairs", "slime", "barrier", "iron_trapdoor", "prismarine", "seaLantern",
+		"hay_block", "carpet", "hardened_clay", "coal_block", "packed_ice", "double_plant", "standing_banner", "wall_banner", "daylight_detector_inverted", "red_sandstone",
+		"red_sandstone_stairs", "double_stone_slab2", "stone_slab2", "spruce_fence_gate", "birch_fence_gate", "jungle_fence_gate", "dark_oak_fence_gate", "acacia_fence_gate", "fence", "fence",
+		"fence", "fence", "fence", "spruce_door", "birch_door", "jungle_door", "acacia_door", "dark_oak_door", "end_rod", "chorus_plant",
+		"chorus_flower", "purpur_block", "purpur_block", "purpur_stairs", "double_stone_slab2", "stone_slab2", "end_bricks", "beetroot", "grass_path", "end_gateway",
+		"repeating_command_block", "chain_command_block", "frosted_ice", "magma", "nether_wart_block", "red_nether_brick", "bone_block", "structure_void", "observer", "shulker_box",
+		"shulker_box", "shulker_box", "shulker_box", "shulker_box", "shulker_box", "shulker_box", "shulker_box", "shulker_box", "shulker_box", "shulker_box",
+		"shulker_box", "shulker_box", "shulker_box", "shulker_box", "shulker_box", "white_glazed_terracotta", "orange_glazed_terracotta", "magenta_glazed_terracotta", "light_blue_glazed_terracotta", "yellow_glazed_terracotta",
+		"lime_glazed_terracotta", "pink_glazed_terracotta", "gray_glazed_terracotta", "silver_glazed_terracotta", "cyan_glazed_terracotta", "purple_glazed_terracotta", "blue_glazed_terracotta", "brown_glazed_terracotta", "green_glazed_terracotta", "red_glazed_terracotta",
+		"black_glazed_terracotta", "concrete", "concretePowder", "", "", "structure_block",
+	}
+	ids := make([]legacyID, len(names))
+	for i, name := range names {
+		ids[i] = legacyID{name: name, meta: -1}
+	}
+	// Blocks that were split into multiple IDs in Java Edition, but are stored under a single ID with
+	// different data values in Bedrock Edition.
+	for id, meta := range map[int]int16{188: 1, 189: 2, 190: 3, 191: 5, 192: 4, 202: 2, 204: 1, 205: 1} {
+		ids[id].meta = meta
+	}
+	for id := 219; id <= 234; id++ {
+		ids[id].meta = int16(id - 219)
+	}
+	return ids
+}()
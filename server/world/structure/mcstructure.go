@@ -0,0 +1,184 @@
+package structure
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"strconv"
+
+	"github.com/df-mc/worldupgrader/blockupgrader"
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/chunk"
+)
+
+// mcstructureVersion is the format version of .mcstructure files written.
+const mcstructureVersion = 1
+
+// mcstructure is the NBT layout of a .mcstructure file.
+type mcstructure struct {
+	FormatVersion int32           `nbt:"format_version"`
+	Size          []int32         `nbt:"size"`
+	Origin        []int32         `nbt:"structure_world_origin"`
+	Structure     mcstructureData `nbt:"structure"`
+}
+
+// mcstructureData holds the blocks, palettes and entities of a .mcstructure file.
+type mcstructureData struct {
+	BlockIndices [][]int32                     `nbt:"block_indices"`
+	Entities     []map[string]any              `nbt:"entities"`
+	Palette      map[string]mcstructurePalette `nbt:"palette"`
+}
+
+// mcstructurePalette is a palette of a .mcstructure file. Only the palette named "default" is used.
+type mcstructurePalette struct {
+	BlockPalette      []blockState                 `nbt:"block_palette"`
+	BlockPositionData map[string]blockPositionData `nbt:"block_position_data"`
+}
+
+// blockState is a block state in the palette of a .mcstructure file.
+type blockState struct {
+	Name    string         `nbt:"name"`
+	States  map[string]any `nbt:"states"`
+	Version int32          `nbt:"version"`
+}
+
+// blockPositionData holds additional data of a block at a specific position in a .mcstructure file.
+type blockPositionData struct {
+	BlockEntityData map[string]any `nbt:"block_entity_data,omitempty"`
+}
+
+// ReadMCStructure reads a Structure from a Bedrock Edition .mcstructure file read from the io.Reader passed.
+// Blocks of older versions are upgraded to the current version. Blocks that do not exist in the current
+// version are left out of the Structure.
+func ReadMCStructure(r io.Reader) (*Structure, error) {
+	var m mcstructure
+	if err := nbt.NewDecoderWithEncoding(r, nbt.LittleEndian).Decode(&m); err != nil {
+		return nil, fmt.Errorf("read mcstructure: decode nbt: %w", err)
+	}
+	if len(m.Size) != 3 {
+		return nil, fmt.Errorf("read mcstructure: invalid size %v", m.Size)
+	}
+	size := [3]int{int(m.Size[0]), int(m.Size[1]), int(m.Size[2])}
+	if err := validateDimensions(size); err != nil {
+		return nil, fmt.Errorf("read mcstructure: %w", err)
+	}
+	s := New(size)
+	if len(m.Structure.BlockIndices) == 0 {
+		return s, nil
+	}
+	p := m.Structure.Palette["default"]
+
+	palette := make([]int32, len(p.BlockPalette))
+	for i, state := range p.BlockPalette {
+		palette[i] = -1
+		if b, ok := blockFromState(state); ok && !structureVoid(b) {
+			palette[i] = s.paletteIndex(b)
+		}
+	}
+	for layer, indices := range m.Structure.BlockIndices {
+		if layer > 1 {
+			break
+		} else if len(indices) != len(s.blocks) {
+			return nil, fmt.Errorf("read mcstructure: expected %v block indices in layer %v, got %v", len(s.blocks), layer, len(indices))
+		}
+		for i, index := range indices {
+			if index < 0 || int(index) >= len(palette) || palette[index] == -1 {
+				continue
+			}
+			if layer == 0 {
+				s.blocks[i] = palette[index]
+			} else if _, ok := s.palette[palette[index]].(world.Liquid); ok {
+				s.liquids[i] = palette[index]
+			}
+		}
+	}
+	for k, data := range p.BlockPositionData {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || i >= len(s.blocks) || data.BlockEntityData == nil {
+			continue
+		}
+		s.nbt[i] = data.BlockEntityData
+	}
+
+	var origin mgl64.Vec3
+	if len(m.Origin) == 3 {
+		origin = mgl64.Vec3{float64(m.Origin[0]), float64(m.Origin[1]), float64(m.Origin[2])}
+	}
+	for _, e := range m.Structure.Entities {
+		e["Pos"] = nbtconv.Vec3ToFloat32Slice(nbtconv.Vec3(e, "Pos").Sub(origin))
+		s.entities = append(s.entities, e)
+	}
+	return s, nil
+}
+
+// WriteMCStructure writes the Structure to the io.Writer passed in the Bedrock Edition .mcstructure format.
+func (s *Structure) WriteMCStructure(w io.Writer) error {
+	palette := make([]blockState, 0, len(s.palette)+1)
+	for _, b := range s.palette {
+		name, properties := b.EncodeBlock()
+		palette = append(palette, blockState{Name: name, States: properties, Version: chunk.CurrentBlockVersion})
+	}
+	void := int32(-1)
+
+	layers := [][]int32{make([]int32, len(s.blocks)), make([]int32, len(s.liquids))}
+	for i := range s.blocks {
+		layers[0][i], layers[1][i] = s.blocks[i], s.liquids[i]
+		if s.blocks[i] == -1 {
+			// Positions without a block are filled with structure voids, so that they do not replace
+			// blocks when the structure is loaded.
+			if void == -1 {
+				void = int32(len(palette))
+				palette = append(palette, blockState{Name: "minecraft:structure_void", States: map[string]any{"structure_void_type": "void"}, Version: chunk.CurrentBlockVersion})
+			}
+			layers[0][i] = void
+		}
+	}
+	positionData := make(map[string]blockPositionData, len(s.nbt))
+	for i, data := range s.nbt {
+		positionData[strconv.Itoa(i)] = blockPositionData{BlockEntityData: maps.Clone(data)}
+	}
+	m := mcstructure{
+		FormatVersion: mcstructureVersion,
+		Size:          []int32{int32(s.size[0]), int32(s.size[1]), int32(s.size[2])},
+		Origin:        []int32{0, 0, 0},
+		Structure: mcstructureData{
+			BlockIndices: layers,
+			Entities:     s.Entities(),
+			Palette: map[string]mcstructurePalette{
+				"default": {BlockPalette: palette, BlockPositionData: positionData},
+			},
+		},
+	}
+	if err := nbt.NewEncoderWithEncoding(w, nbt.LittleEndian).Encode(m); err != nil {
+		return fmt.Errorf("write mcstructure: encode nbt: %w", err)
+	}
+	return nil
+}
+
+// blockFromState returns the block with the block state passed, upgrading the block state to the current
+// version first. If the state is not valid, or if no block with the state is registered, false is returned.
+func blockFromState(state blockState) (world.Block, bool) {
+	if state.States == nil {
+		state.States = map[string]any{}
+	}
+	upgraded := blockupgrader.Upgrade(blockupgrader.BlockState{
+		Name:       state.Name,
+		Properties: state.States,
+		Version:    state.Version,
+	})
+	rid, ok := chunk.StateToRuntimeID(upgraded.Name, upgraded.Properties)
+	if !ok {
+		return nil, false
+	}
+	return world.BlockByRuntimeID(rid)
+}
+
+// structureVoid checks if the block passed is a structure void, which marks positions in a structure that
+// should not be changed when the structure is placed.
+func structureVoid(b world.Block) bool {
+	name, _ := b.EncodeBlock()
+	return name == "minecraft:structure_void"
+}
@@ -0,0 +1,366 @@
+package structure
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"github.com/stcraft/dragonfly/server/world"
+	"github.com/stcraft/dragonfly/server/world/chunk"
+)
+
+const (
+	// spongeVersion is the version of the Sponge schematic format written.
+	spongeVersion = 3
+	// javaDataVersion is the Java Edition data version written to Sponge schematics.
+	javaDataVersion = 3700
+)
+
+// ReadSchematic reads a Structure from a .schematic or .schem file read from the io.Reader passed. Both the
+// Sponge schematic format (versions 1 to 3) and the legacy MCEdit schematic format are supported, either
+// compressed using gzip or uncompressed.
+// Schematics are a Java Edition format, so blocks are converted to their Bedrock Edition equivalent on a
+// best-effort basis: Blocks that have no equivalent are left out of the Structure and block states that
+// cannot be converted are replaced with the default state of the block. The block entity and entity data of
+// schematics is Java Edition specific and is not read.
+func ReadSchematic(r io.Reader) (*Structure, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("read schematic: %w", err)
+		}
+		defer zr.Close()
+		r = zr
+	} else {
+		r = br
+	}
+	var m map[string]any
+	if err := nbt.NewDecoderWithEncoding(r, nbt.BigEndian).Decode(&m); err != nil {
+		return nil, fmt.Errorf("read schematic: decode nbt: %w", err)
+	}
+	if v, ok := m["Schematic"].(map[string]any); ok {
+		// Sponge schematics of version 3 are nested in a compound tag named Schematic.
+		m = v
+	}
+	size := [3]int{int(uint16(shortTag(m, "Width"))), int(uint16(shortTag(m, "Height"))), int(uint16(shortTag(m, "Length")))}
+	if err := validateDimensions(size); err != nil {
+		return nil, fmt.Errorf("read schematic: %w", err)
+	}
+	if byteArrayTag(m, "Blocks") != nil {
+		return readLegacySchematic(m, size)
+	}
+	return readSpongeSchematic(m, size)
+}
+
+// readSpongeSchematic reads a Structure from the decoded NBT of a Sponge schematic.
+func readSpongeSchematic(m map[string]any, size [3]int) (*Structure, error) {
+	blocks := m
+	if v, ok := m["Blocks"].(map[string]any); ok {
+		// Version 3 holds the palette and block data in a Blocks compound tag.
+		blocks = v
+	}
+	rawPalette, _ := blocks["Palette"].(map[string]any)
+	data := byteArrayTag(blocks, "Data")
+	if data == nil {
+		data = byteArrayTag(blocks, "BlockData")
+	}
+
+	s := New(size)
+	palette := make(map[int32]world.Block, len(rawPalette))
+	for state, v := range rawPalette {
+		i, _ := v.(int32)
+		if b, ok := javaBlock(state); ok {
+			palette[i] = b
+		}
+	}
+	buf := bytes.NewReader(data)
+	for i := 0; i < size[0]*size[1]*size[2]; i++ {
+		v, err := readVarInt(buf)
+		if err != nil {
+			return nil, fmt.Errorf("read schematic: block data: %w", err)
+		}
+		b, ok := palette[v]
+		if !ok {
+			continue
+		}
+		// Sponge schematics are ordered by Y, then Z, then X.
+		x, y, z := i%size[0], i/(size[0]*size[2]), (i/size[0])%size[2]
+		s.Set(x, y, z, b, nil)
+	}
+	return s, nil
+}
+
+// readLegacySchematic reads a Structure from the decoded NBT of a legacy MCEdit schematic.
+func readLegacySchematic(m map[string]any, size [3]int) (*Structure, error) {
+	if materials, _ := m["Materials"].(string); materials != "" && materials != "Alpha" {
+		return nil, fmt.Errorf("read schematic: unsupported materials %v", materials)
+	}
+	ids, data, add := byteArrayTag(m, "Blocks"), byteArrayTag(m, "Data"), byteArrayTag(m, "AddBlocks")
+	n := size[0] * size[1] * size[2]
+	if len(ids) < n || len(data) < n {
+		return nil, fmt.Errorf("read schematic: expected %v blocks, got %v", n, len(ids))
+	}
+	s := New(size)
+	for i := 0; i < n; i++ {
+		id := int(ids[i])
+		if len(add) > i>>1 {
+			// AddBlocks holds the upper 4 bits of block IDs above 255, two per byte.
+			if i&1 == 0 {
+				id |= int(add[i>>1]&0xf0) << 4
+			} else {
+				id |= int(add[i>>1]&0x0f) << 8
+			}
+		}
+		b, ok := legacyBlock(id, data[i]&0xf)
+		if !ok {
+			continue
+		}
+		// Legacy schematics are ordered by Y, then Z, then X.
+		x, y, z := i%size[0], i/(size[0]*size[2]), (i/size[0])%size[2]
+		s.Set(x, y, z, b, nil)
+	}
+	return s, nil
+}
+
+// WriteSchematic writes the Structure to the io.Writer passed in the Sponge schematic format (version 3),
+// compressed using gzip. Block states are written using their Bedrock Edition properties, so that they can
+// be read back by ReadSchematic without losing information. Java Edition may not recognise all blocks and
+// properties written. Liquids in the second layer, block entity data and entities are not written.
+func (s *Structure) WriteSchematic(w io.Writer) error {
+	palette := map[string]any{"minecraft:structure_void": int32(0)}
+	indices := make([]int32, len(s.palette))
+	for i, b := range s.palette {
+		state := javaState(b)
+		if v, ok := palette[state]; ok {
+			indices[i] = v.(int32)
+			continue
+		}
+		indices[i] = int32(len(palette))
+		palette[state] = indices[i]
+	}
+
+	data := make([]byte, 0, len(s.blocks))
+	for y := 0; y < s.size[1]; y++ {
+		for z := 0; z < s.size[2]; z++ {
+			for x := 0; x < s.size[0]; x++ {
+				i, _ := s.index(x, y, z)
+				v := int32(0)
+				if s.blocks[i] != -1 {
+					v = indices[s.blocks[i]]
+				}
+				data = appendVarInt(data, v)
+			}
+		}
+	}
+	blockData := reflect.New(reflect.ArrayOf(len(data), reflect.TypeOf(byte(0)))).Elem()
+	reflect.Copy(blockData, reflect.ValueOf(data))
+
+	zw := gzip.NewWriter(w)
+	err := nbt.NewEncoderWithEncoding(zw, nbt.BigEndian).Encode(map[string]any{
+		"Schematic": map[string]any{
+			"Version":     int32(spongeVersion),
+			"DataVersion": int32(javaDataVersion),
+			"Width":       int16(uint16(s.size[0])),
+			"Height":      int16(uint16(s.size[1])),
+			"Length":      int16(uint16(s.size[2])),
+			"Offset":      [3]int32{},
+			"Blocks": map[string]any{
+				"Palette":       palette,
+				"Data":          blockData.Interface(),
+				"BlockEntities": []any{},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("write schematic: encode nbt: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("write schematic: %w", err)
+	}
+	return nil
+}
+
+// javaBlock parses a block state in the format of Java Edition, such as "minecraft:oak_log[axis=x]", and
+// returns its Bedrock Edition equivalent.
+func javaBlock(state string) (world.Block, bool) {
+	name, props, _ := strings.Cut(state, "[")
+	props = strings.TrimSuffix(props, "]")
+
+	properties := map[string]string{}
+	for _, prop := range strings.Split(props, ",") {
+		if k, v, ok := strings.Cut(prop, "="); ok {
+			properties[k] = v
+		}
+	}
+	if r, ok := javaRenames[name]; ok {
+		name = r.name
+		for k, v := range r.properties {
+			properties[k] = v
+		}
+	}
+	rid, ok := chunk.StateToRuntimeID(name, nil)
+	if !ok {
+		return nil, false
+	}
+	def, _ := world.BlockByRuntimeID(rid)
+	if structureVoid(def) {
+		return nil, false
+	}
+	_, defaults := def.EncodeBlock()
+
+	converted := make(map[string]any, len(defaults))
+	for k, v := range defaults {
+		converted[k] = v
+	}
+	for k, v := range properties {
+		k, v = javaProperty(k, v, defaults)
+		def, ok := defaults[k]
+		if !ok {
+			continue
+		}
+		switch def.(type) {
+		case string:
+			converted[k] = v
+		case uint8:
+			switch v {
+			case "true", "1":
+				converted[k] = uint8(1)
+			case "false", "0":
+				converted[k] = uint8(0)
+			}
+		case int32:
+			if n, err := strconv.Atoi(v); err == nil {
+				converted[k] = int32(n)
+			}
+		}
+	}
+	if rid, ok = chunk.StateToRuntimeID(name, converted); !ok {
+		return def, true
+	}
+	return world.BlockByRuntimeID(rid)
+}
+
+// javaProperty converts the Java Edition block property passed to the key and value of the Bedrock Edition
+// property of a block with the default properties passed.
+func javaProperty(k, v string, defaults map[string]any) (string, string) {
+	_, cardinal := defaults["minecraft:cardinal_direction"]
+	_, facing := defaults["facing_direction"]
+	_, weirdo := defaults["weirdo_direction"]
+	switch {
+	case k == "axis":
+		return "pillar_axis", v
+	case k == "facing" && cardinal:
+		return "minecraft:cardinal_direction", v
+	case k == "facing" && weirdo:
+		return "weirdo_direction", strconv.Itoa(slices.Index([]string{"east", "west", "south", "north"}, v))
+	case k == "facing" && facing:
+		return "facing_direction", strconv.Itoa(slices.Index([]string{"down", "up", "north", "south", "west", "east"}, v))
+	case k == "half" && (v == "top" || v == "bottom"):
+		if _, ok := defaults["upside_down_bit"]; ok {
+			return "upside_down_bit", strconv.FormatBool(v == "top")
+		}
+		return "minecraft:vertical_half", v
+	case k == "type" && (v == "top" || v == "bottom"):
+		return "minecraft:vertical_half", v
+	}
+	return k, v
+}
+
+// javaState returns the block state of the block passed in the format of Java Edition, using the properties
+// of Bedrock Edition.
+func javaState(b world.Block) string {
+	name, properties := b.EncodeBlock()
+	if _, ok := javaRenames[name]; ok {
+		// The name of the block refers to a different block in Java Edition, so we need to find the Java
+		// Edition name that refers to this block.
+		for java, r := range javaRenames {
+			if r.name == name && len(r.properties) == 0 {
+				name = java
+				break
+			}
+		}
+	}
+	if len(properties) == 0 {
+		return name
+	}
+	props := make([]string, 0, len(properties))
+	for k, v := range properties {
+		props = append(props, k+"="+fmt.Sprint(v))
+	}
+	sort.Strings(props)
+	return name + "[" + strings.Join(props, ",") + "]"
+}
+
+// legacyBlock returns the block with the legacy Java Edition block ID and data value passed.
+func legacyBlock(id int, data byte) (world.Block, bool) {
+	if id <= 0 || id >= len(legacyIDs) || legacyIDs[id].name == "" {
+		return nil, false
+	}
+	l := legacyIDs[id]
+	meta := int16(data)
+	if l.meta >= 0 {
+		meta = l.meta
+	}
+	name, properties, version, ok := chunk.UpgradeLegacyBlock("minecraft:"+l.name, meta)
+	if !ok {
+		return nil, false
+	}
+	b, ok := blockFromState(blockState{Name: name, States: properties, Version: version})
+	if !ok || structureVoid(b) {
+		return nil, false
+	}
+	return b, true
+}
+
+// shortTag reads an int16 from the map passed at the key passed.
+func shortTag(m map[string]any, k string) int16 {
+	v, _ := m[k].(int16)
+	return v
+}
+
+// byteArrayTag reads a byte array from the map passed at the key passed. Byte arrays are decoded to arrays
+// of varying length, so they are converted to a slice.
+func byteArrayTag(m map[string]any, k string) []byte {
+	v := reflect.ValueOf(m[k])
+	if v.Kind() != reflect.Array || v.Type().Elem().Kind() != reflect.Uint8 {
+		return nil
+	}
+	b := make([]byte, v.Len())
+	reflect.Copy(reflect.ValueOf(b), v)
+	return b
+}
+
+// readVarInt reads a variable-length integer as used in Sponge schematic block data.
+func readVarInt(r io.ByteReader) (int32, error) {
+	var v uint32
+	for i := uint(0); i < 35; i += 7 {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		v |= uint32(b&0x7f) << i
+		if b&0x80 == 0 {
+			return int32(v), nil
+		}
+	}
+	return 0, fmt.Errorf("varint overflows int32")
+}
+
+// appendVarInt appends the variable-length integer passed to the byte slice passed.
+func appendVarInt(b []byte, v int32) []byte {
+	u := uint32(v)
+	for u >= 0x80 {
+		b = append(b, byte(u)|0x80)
+		u >>= 7
+	}
+	return append(b, byte(u))
+}
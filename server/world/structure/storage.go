@@ -0,0 +1,130 @@
+package structure
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadFile reads a Structure from the file at the path passed. The format of the file is picked by its
+// extension: Files with the .mcstructure extension are read using ReadMCStructure and files with the
+// .schematic or .schem extension are read using ReadSchematic.
+func ReadFile(path string) (*Structure, error) {
+	read, err := readerByExtension(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read structure file: %w", err)
+	}
+	defer f.Close()
+	return read(bufio.NewReader(f))
+}
+
+// WriteFile writes the Structure to a file at the path passed, creating it if it does not yet exist. The
+// format of the file is picked by its extension: Files with the .mcstructure extension are written using
+// WriteMCStructure and files with the .schematic or .schem extension are written using WriteSchematic.
+func (s *Structure) WriteFile(path string) error {
+	write, err := s.writerByExtension(path)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("write structure file: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	if err := write(w); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write structure file: %w", err)
+	}
+	return f.Close()
+}
+
+// readerByExtension returns the function used to read a Structure from a file with the path passed.
+func readerByExtension(path string) (func(r io.Reader) (*Structure, error), error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".mcstructure":
+		return ReadMCStructure, nil
+	case ".schematic", ".schem":
+		return ReadSchematic, nil
+	}
+	return nil, fmt.Errorf("unsupported structure file extension %q", filepath.Ext(path))
+}
+
+// writerByExtension returns the function used to write the Structure to a file with the path passed.
+func (s *Structure) writerByExtension(path string) (func(w io.Writer) error, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".mcstructure":
+		return s.WriteMCStructure, nil
+	case ".schematic", ".schem":
+		return s.WriteSchematic, nil
+	}
+	return nil, fmt.Errorf("unsupported structure file extension %q", filepath.Ext(path))
+}
+
+// ErrNotFound is returned by a Storage if no structure with a specific name is stored.
+var ErrNotFound = errors.New("structure not found")
+
+// Storage stores structures by name, such as the structures saved and loaded by structure blocks.
+type Storage interface {
+	// Load loads the structure with the name passed. ErrNotFound is returned if no structure with this name
+	// is stored.
+	Load(name string) (*Structure, error)
+	// Save saves the Structure passed under the name passed, overwriting any structure previously saved
+	// under the same name.
+	Save(name string, s *Structure) error
+}
+
+// DirStorage is a Storage that stores structures as .mcstructure files in a directory. Like in behaviour
+// packs, a structure named "namespace:name" is stored at "namespace/name.mcstructure" in the directory.
+// Structures without a namespace are stored in the "mystructure" namespace.
+type DirStorage string
+
+// Load ...
+func (d DirStorage) Load(name string) (*Structure, error) {
+	path, err := d.path(name)
+	if err != nil {
+		return nil, err
+	}
+	s, err := ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("load structure %v: %w", name, ErrNotFound)
+	}
+	return s, err
+}
+
+// Save ...
+func (d DirStorage) Save(name string, s *Structure) error {
+	path, err := d.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return fmt.Errorf("save structure %v: %w", name, err)
+	}
+	return s.WriteFile(path)
+}
+
+// path returns the path of the file that the structure with the name passed is stored in. An error is
+// returned if the name is empty or would point to a file outside the directory.
+func (d DirStorage) path(name string) (string, error) {
+	namespace, n, ok := strings.Cut(name, ":")
+	if !ok {
+		namespace, n = "mystructure", name
+	}
+	rel := filepath.Join(namespace, filepath.FromSlash(n)+".mcstructure")
+	if namespace == "" || n == "" || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("invalid structure name %q", name)
+	}
+	return filepath.Join(string(d), rel), nil
+}
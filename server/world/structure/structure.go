@@ -0,0 +1,350 @@
+// Package structure implements structures that may be built in a world.World using World.BuildStructure.
+// Structures may be read from and written to Bedrock Edition .mcstructure files and Sponge .schematic files,
+// read from legacy MCEdit .schematic files or captured from a region of a world.World.
+package structure
+
+import (
+	"fmt"
+	"maps"
+	"math"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/internal/nbtconv"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+const (
+	// maxAxisSize is the maximum size along any axis of a Structure read from a file.
+	maxAxisSize = 1024
+	// maxVolume is the maximum number of blocks of a Structure read from a file. It is the volume of the
+	// largest region that a structure block can save: 64x384x64 blocks.
+	maxVolume = 64 * 384 * 64
+)
+
+// Compile time check to make sure *Structure implements world.Structure.
+var _ world.Structure = (*Structure)(nil)
+
+// Structure is a world.Structure that holds the blocks, liquids, block entity data and entities of a cuboid
+// region. Blocks are stored using a palette, so that structures with many equal blocks remain small.
+// Structures are not safe for concurrent use while being edited using Set.
+type Structure struct {
+	size [3]int
+
+	palette []world.Block
+	indices map[uint32]int32
+	// blocks and liquids hold the index in the palette of the block and liquid at every position in the
+	// Structure. If no block or liquid is present at a position, the index is -1.
+	blocks, liquids []int32
+	// nbt holds the block entity data of blocks in the Structure, indexed by the index of their position.
+	nbt map[int]map[string]any
+	// entities holds the NBT data of the entities in the Structure. The positions of these entities are
+	// relative to the origin of the Structure.
+	entities []map[string]any
+}
+
+// New creates a new, empty Structure with the dimensions passed. The dimensions hold the width, height and
+// length of the Structure respectively. Building an empty Structure does not change any blocks.
+func New(dimensions [3]int) *Structure {
+	n := max(0, dimensions[0]) * max(0, dimensions[1]) * max(0, dimensions[2])
+	s := &Structure{
+		size:    dimensions,
+		indices: make(map[uint32]int32),
+		blocks:  make([]int32, n),
+		liquids: make([]int32, n),
+		nbt:     make(map[int]map[string]any),
+	}
+	for i := range s.blocks {
+		s.blocks[i], s.liquids[i] = -1, -1
+	}
+	return s
+}
+
+// validateDimensions checks if the dimensions passed, read from a file, are small enough for a Structure to
+// be created with them. An error is returned if any of the dimensions is negative or too large, or if the
+// Structure would hold too many blocks.
+func validateDimensions(dimensions [3]int) error {
+	volume := int64(1)
+	for _, n := range dimensions {
+		if n < 0 || n > maxAxisSize {
+			return fmt.Errorf("invalid dimensions %v: every dimension must be between 0 and %v", dimensions, maxAxisSize)
+		}
+		// Every dimension is at most maxAxisSize, so the volume cannot overflow before it is checked.
+		if volume *= int64(n); volume > maxVolume {
+			return fmt.Errorf("invalid dimensions %v: structure must not hold more than %v blocks", dimensions, maxVolume)
+		}
+	}
+	return nil
+}
+
+// Dimensions returns the width, height and length of the Structure.
+func (s *Structure) Dimensions() [3]int {
+	return s.size
+}
+
+// At returns the block and liquid at the position passed, relative to the origin of the Structure. Blocks
+// with block entity data, such as chests, are decoded again every time At is called, so that structures
+// built multiple times do not share data such as inventories.
+func (s *Structure) At(x, y, z int, _ func(x, y, z int) world.Block) (world.Block, world.Liquid) {
+	i, ok := s.index(x, y, z)
+	if !ok {
+		return nil, nil
+	}
+	var b world.Block
+	if s.blocks[i] != -1 {
+		b = s.palette[s.blocks[i]]
+		if data, ok := s.nbt[i]; ok {
+			if n, ok := b.(world.NBTer); ok {
+				b = n.DecodeNBT(maps.Clone(data)).(world.Block)
+			}
+		}
+	}
+	var liq world.Liquid
+	if s.liquids[i] != -1 {
+		liq, _ = s.palette[s.liquids[i]].(world.Liquid)
+	}
+	return b, liq
+}
+
+// Set sets the block and liquid at the position passed, relative to the origin of the Structure. Passing a
+// nil block or liquid leaves the block or liquid in the world unchanged when the Structure is built. The
+// block entity data of blocks implementing world.NBTer is stored as well.
+func (s *Structure) Set(x, y, z int, b world.Block, liq world.Liquid) {
+	i, ok := s.index(x, y, z)
+	if !ok {
+		return
+	}
+	s.blocks[i], s.liquids[i] = -1, -1
+	delete(s.nbt, i)
+	if b != nil {
+		s.blocks[i] = s.paletteIndex(b)
+		if n, ok := b.(world.NBTer); ok {
+			s.nbt[i] = n.EncodeNBT()
+		}
+	}
+	if liq != nil {
+		s.liquids[i] = s.paletteIndex(liq)
+	}
+}
+
+// Entities returns the NBT data of all entities in the Structure. The "Pos" fields of the entities are
+// relative to the origin of the Structure.
+func (s *Structure) Entities() []map[string]any {
+	entities := make([]map[string]any, len(s.entities))
+	for i, m := range s.entities {
+		entities[i] = maps.Clone(m)
+	}
+	return entities
+}
+
+// WithoutEntities returns a copy of the Structure without any of its entities.
+func (s *Structure) WithoutEntities() *Structure {
+	c := s.clone()
+	c.entities = nil
+	return c
+}
+
+// AddEntity adds the world.Entity passed to the Structure at the position passed, relative to the origin of
+// the Structure. Entities that cannot be saved, such as players, are not added.
+func (s *Structure) AddEntity(e world.Entity, pos mgl64.Vec3) {
+	t, ok := e.Type().(world.SaveableEntityType)
+	if !ok {
+		return
+	}
+	m := t.EncodeNBT(e)
+	m["identifier"] = t.EncodeEntity()
+	m["Pos"] = nbtconv.Vec3ToFloat32Slice(pos)
+	s.entities = append(s.entities, m)
+}
+
+// Place builds the Structure in the world.World passed with its origin at the position passed and spawns
+// all entities in the Structure. Entities of a type not registered in the world.EntityRegistry of the World
+// are not spawned.
+func (s *Structure) Place(w *world.World, pos cube.Pos) {
+	w.BuildStructure(pos, s)
+
+	reg := w.EntityRegistry()
+	for _, m := range s.Entities() {
+		name, _ := m["identifier"].(string)
+		t, ok := reg.Lookup(name)
+		if !ok {
+			continue
+		}
+		st, ok := t.(world.SaveableEntityType)
+		if !ok {
+			continue
+		}
+		m["Pos"] = nbtconv.Vec3ToFloat32Slice(nbtconv.Vec3(m, "Pos").Add(pos.Vec3()))
+		if e := st.DecodeNBT(m); e != nil {
+			w.AddEntity(e)
+		}
+	}
+}
+
+// Capture captures all blocks and liquids in the region between the corners a and b of the world.World
+// passed, both inclusive, into a new Structure. If entities is true, the entities within the region that
+// may be saved are captured as well.
+func Capture(w *world.World, a, b cube.Pos, entities bool) *Structure {
+	origin := cube.Pos{min(a[0], b[0]), min(a[1], b[1]), min(a[2], b[2])}
+	end := cube.Pos{max(a[0], b[0]), max(a[1], b[1]), max(a[2], b[2])}
+	s := New([3]int{end[0] - origin[0] + 1, end[1] - origin[1] + 1, end[2] - origin[2] + 1})
+
+	for x := 0; x < s.size[0]; x++ {
+		for y := 0; y < s.size[1]; y++ {
+			for z := 0; z < s.size[2]; z++ {
+				pos := origin.Add(cube.Pos{x, y, z})
+				liq, _ := w.Liquid(pos)
+				if bl := w.Block(pos); bl == liq {
+					// The liquid is on the first layer, so we don't need to set it twice.
+					s.Set(x, y, z, bl, nil)
+				} else {
+					s.Set(x, y, z, bl, liq)
+				}
+			}
+		}
+	}
+	if entities {
+		box := cube.Box(float64(origin[0]), float64(origin[1]), float64(origin[2]), float64(end[0]+1), float64(end[1]+1), float64(end[2]+1))
+		for _, e := range w.EntitiesWithin(box, nil) {
+			s.AddEntity(e, e.Position().Sub(origin.Vec3()))
+		}
+	}
+	return s
+}
+
+// Rotate returns a copy of the Structure that is rotated clockwise by 90 degrees, seen from above, the number
+// of times passed. Negative values rotate the Structure counterclockwise. The facing direction of blocks and
+// the rotation of entities are rotated as well.
+func (s *Structure) Rotate(times int) *Structure {
+	c := s.clone()
+	for i := 0; i < ((times%4)+4)%4; i++ {
+		c = c.transform([3]int{c.size[2], c.size[1], c.size[0]}, func(x, y, z int) (int, int, int) {
+			return c.size[2] - 1 - z, y, x
+		}, func(v mgl64.Vec3) mgl64.Vec3 {
+			return mgl64.Vec3{float64(c.size[2]) - v[2], v[1], v[0]}
+		}, transformer{
+			direction:   cube.Direction.RotateRight,
+			axis:        cube.Axis.RotateRight,
+			orientation: cube.Orientation.RotateRight,
+			yaw:         func(yaw float64) float64 { return yaw + 90 },
+		})
+	}
+	return c
+}
+
+// Mirror returns a copy of the Structure that is mirrored along the axis passed. Mirroring along cube.X
+// flips the Structure from east to west and mirroring along cube.Z flips the Structure from north to south.
+// Mirroring along cube.Y is not supported and returns an unchanged copy of the Structure.
+func (s *Structure) Mirror(axis cube.Axis) *Structure {
+	c := s.clone()
+	switch axis {
+	case cube.X:
+		return c.transform(c.size, func(x, y, z int) (int, int, int) {
+			return c.size[0] - 1 - x, y, z
+		}, func(v mgl64.Vec3) mgl64.Vec3 {
+			return mgl64.Vec3{float64(c.size[0]) - v[0], v[1], v[2]}
+		}, transformer{
+			direction: func(d cube.Direction) cube.Direction {
+				if d == cube.East || d == cube.West {
+					return d.Opposite()
+				}
+				return d
+			},
+			axis: func(a cube.Axis) cube.Axis { return a },
+			orientation: func(o cube.Orientation) cube.Orientation {
+				return cube.OrientationFromYaw(360 - o.Yaw())
+			},
+			yaw: func(yaw float64) float64 { return -yaw },
+		})
+	case cube.Z:
+		return c.transform(c.size, func(x, y, z int) (int, int, int) {
+			return x, y, c.size[2] - 1 - z
+		}, func(v mgl64.Vec3) mgl64.Vec3 {
+			return mgl64.Vec3{v[0], v[1], float64(c.size[2]) - v[2]}
+		}, transformer{
+			direction: func(d cube.Direction) cube.Direction {
+				if d == cube.North || d == cube.South {
+					return d.Opposite()
+				}
+				return d
+			},
+			axis: func(a cube.Axis) cube.Axis { return a },
+			orientation: func(o cube.Orientation) cube.Orientation {
+				return cube.OrientationFromYaw(540 - o.Yaw())
+			},
+			yaw: func(yaw float64) float64 { return 180 - yaw },
+		})
+	}
+	return c
+}
+
+// transform returns a new Structure with the size passed, in which every block of s is moved to the position
+// returned by pos and transformed using t. Entities are moved to the position returned by vec.
+func (s *Structure) transform(size [3]int, pos func(x, y, z int) (int, int, int), vec func(v mgl64.Vec3) mgl64.Vec3, t transformer) *Structure {
+	n := New(size)
+	palette := make([]int32, len(s.palette))
+	for i, b := range s.palette {
+		palette[i] = n.paletteIndex(t.block(b))
+	}
+	for x := 0; x < s.size[0]; x++ {
+		for y := 0; y < s.size[1]; y++ {
+			for z := 0; z < s.size[2]; z++ {
+				i, _ := s.index(x, y, z)
+				j, _ := n.index(pos(x, y, z))
+				if s.blocks[i] != -1 {
+					n.blocks[j] = palette[s.blocks[i]]
+				}
+				if s.liquids[i] != -1 {
+					n.liquids[j] = palette[s.liquids[i]]
+				}
+				if data, ok := s.nbt[i]; ok {
+					n.nbt[j] = data
+				}
+			}
+		}
+	}
+	for _, m := range s.Entities() {
+		m["Pos"] = nbtconv.Vec3ToFloat32Slice(vec(nbtconv.Vec3(m, "Pos")))
+		if yaw, ok := m["Yaw"].(float32); ok {
+			m["Yaw"] = float32(math.Mod(t.yaw(float64(yaw)), 360))
+		}
+		n.entities = append(n.entities, m)
+	}
+	return n
+}
+
+// clone returns a copy of the Structure.
+func (s *Structure) clone() *Structure {
+	return &Structure{
+		size:     s.size,
+		palette:  append([]world.Block(nil), s.palette...),
+		indices:  maps.Clone(s.indices),
+		blocks:   append([]int32(nil), s.blocks...),
+		liquids:  append([]int32(nil), s.liquids...),
+		nbt:      maps.Clone(s.nbt),
+		entities: s.Entities(),
+	}
+}
+
+// paletteIndex returns the index of the block passed in the palette of the Structure, adding the block to
+// the palette if it was not yet present. Blocks are stored in the palette without their block entity data.
+func (s *Structure) paletteIndex(b world.Block) int32 {
+	rid := world.BlockRuntimeID(b)
+	if i, ok := s.indices[rid]; ok {
+		return i
+	}
+	i := int32(len(s.palette))
+	b, _ = world.BlockByRuntimeID(rid)
+	s.palette = append(s.palette, b)
+	s.indices[rid] = i
+	return i
+}
+
+// index returns the index in the block slices of the position passed. False is returned if the position is
+// not within the dimensions of the Structure.
+func (s *Structure) index(x, y, z int) (int, bool) {
+	if x < 0 || y < 0 || z < 0 || x >= s.size[0] || y >= s.size[1] || z >= s.size[2] {
+		return 0, false
+	}
+	return (x*s.size[1]+y)*s.size[2] + z, true
+}
@@ -0,0 +1,64 @@
+package structure
+
+import (
+	"reflect"
+
+	"github.com/stcraft/dragonfly/server/block/cube"
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+var (
+	directionType   = reflect.TypeOf(cube.Direction(0))
+	faceType        = reflect.TypeOf(cube.Face(0))
+	axisType        = reflect.TypeOf(cube.Axis(0))
+	orientationType = reflect.TypeOf(cube.Orientation(0))
+)
+
+// transformer transforms the facing direction of blocks and the yaw of entities when a Structure is rotated
+// or mirrored.
+type transformer struct {
+	direction   func(d cube.Direction) cube.Direction
+	axis        func(a cube.Axis) cube.Axis
+	orientation func(o cube.Orientation) cube.Orientation
+	yaw         func(yaw float64) float64
+}
+
+// block returns the block passed with all exported fields of the types cube.Direction, cube.Face, cube.Axis
+// and cube.Orientation transformed. Most blocks store the direction they are facing in fields of one of
+// these types, so that transforming them is enough to rotate or mirror the block. Blocks that store their
+// direction differently, or blocks that are not implemented, are returned unchanged.
+func (t transformer) block(b world.Block) world.Block {
+	v := reflect.ValueOf(b)
+	if v.Kind() != reflect.Struct {
+		return b
+	}
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	t.value(c)
+	return c.Interface().(world.Block)
+}
+
+// value transforms the settable reflect.Value passed, recursively transforming the fields of structs.
+func (t transformer) value(v reflect.Value) {
+	switch v.Type() {
+	case directionType:
+		v.Set(reflect.ValueOf(t.direction(v.Interface().(cube.Direction))))
+	case faceType:
+		if f := v.Interface().(cube.Face); f.Axis() != cube.Y {
+			v.Set(reflect.ValueOf(t.direction(f.Direction()).Face()))
+		}
+	case axisType:
+		v.Set(reflect.ValueOf(t.axis(v.Interface().(cube.Axis))))
+	case orientationType:
+		v.Set(reflect.ValueOf(t.orientation(v.Interface().(cube.Orientation))))
+	default:
+		if v.Kind() != reflect.Struct {
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.CanSet() {
+				t.value(f)
+			}
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"slices"
 	"time"
 
+	"github.com/go-gl/mathgl/mgl64"
 	"github.com/stcraft/dragonfly/server/block/cube"
 	"github.com/stcraft/dragonfly/server/internal/sliceutil"
 	"golang.org/x/exp/maps"
@@ -35,6 +36,10 @@ func (t ticker) tickLoop() {
 
 // tick performs a tick on the World and updates the time, weather, blocks and entities that require updates.
 func (t ticker) tick() {
+	// Edits and tasks are handled even if nobody is viewing the World, so that they finish in time.
+	t.tickEdits()
+	t.tickTasks()
+
 	viewers, loaders := t.w.allViewers()
 
 	t.w.set.Lock()
@@ -70,9 +75,25 @@ func (t ticker) tick() {
 	}
 
 	t.tickEntities(tick)
+	t.tickSleep()
+	t.tickSpawning(loaders, tick)
 	t.tickBlocksRandomly(loaders, tick)
 	t.tickScheduledBlocks(tick)
 	t.performNeighbourUpdates()
+	t.pruneRedstoneToggles(tick)
+}
+
+// tickTasks runs all functions scheduled using World.Exec. Functions scheduled while running these are run
+// during the next tick.
+func (t ticker) tickTasks() {
+	t.w.taskMu.Lock()
+	tasks := t.w.tasks
+	t.w.tasks = nil
+	t.w.taskMu.Unlock()
+
+	for _, f := range tasks {
+		f()
+	}
 }
 
 // tickScheduledBlocks executes scheduled block updates in chunks that are currently loaded.
@@ -215,6 +236,9 @@ func (t ticker) tickEntities(tick int64) {
 	var (
 		entitiesToMove []entityToMove
 		entitiesToTick []TickerEntity
+		despawnable    []NaturalEntity
+		players        []mgl64.Vec3
+		despawning     = t.w.conf.Spawning.enabled()
 	)
 
 	t.w.chunkMu.Lock()
@@ -229,12 +253,24 @@ func (t ticker) tickEntities(tick int64) {
 
 		c.Lock()
 		v := len(c.viewers)
+		if _, ok := e.(TickerEntity); ok && v > 0 {
+			if _, saved := e.Type().(SaveableEntityType); saved {
+				// Ticking the entity changes its data, so the Column must be saved again.
+				c.modified = true
+			}
+		}
 		c.Unlock()
 
 		if v > 0 {
 			if ticker, ok := e.(TickerEntity); ok {
 				entitiesToTick = append(entitiesToTick, ticker)
 			}
+			if n, ok := e.(NaturalEntity); ok && despawning {
+				despawnable = append(despawnable, n)
+			}
+		}
+		if p, ok := e.(interface{ GameMode() GameMode }); ok && despawning && p.GameMode().Visible() {
+			players = append(players, e.Position())
 		}
 
 		if lastPos != chunkPos {
@@ -249,6 +285,7 @@ func (t ticker) tickEntities(tick int64) {
 			if old, ok := t.w.chunks[lastPos]; ok {
 				old.Lock()
 				old.Entities = sliceutil.DeleteVal(old.Entities, e)
+				old.modified = true
 				viewers = slices.Clone(old.viewers)
 				old.Unlock()
 			}
@@ -261,6 +298,7 @@ func (t ticker) tickEntities(tick int64) {
 	for _, move := range entitiesToMove {
 		move.after.Lock()
 		move.after.Entities = append(move.after.Entities, move.e)
+		move.after.modified = true
 		viewersAfter := move.after.viewers
 		move.after.Unlock()
 
@@ -287,6 +325,9 @@ func (t ticker) tickEntities(tick int64) {
 			ticker.Tick(t.w, tick)
 		}
 	}
+	if despawning {
+		t.despawn(despawnable, players)
+	}
 }
 
 // randUint4 is a structure used to generate random uint4s.
@@ -32,6 +32,8 @@ type Viewer interface {
 	ViewEntityTeleport(e Entity, pos mgl64.Vec3)
 	// ViewFurnaceUpdate updates a furnace for the associated session based on previous times.
 	ViewFurnaceUpdate(prevCookTime, cookTime, prevRemainingFuelTime, remainingFuelTime, prevMaxFuelTime, maxFuelTime time.Duration)
+	// ViewBrewingUpdate updates a brewing stand for the associated session based on previous times and fuel.
+	ViewBrewingUpdate(prevBrewTime, brewTime time.Duration, prevFuelAmount, fuelAmount, prevFuelTotal, fuelTotal int32)
 	// ViewChunk views the chunk passed at a particular position. It is called for every chunk loaded using
 	// the world.Loader.
 	ViewChunk(pos ChunkPos, c *chunk.Chunk, blockEntities map[cube.Pos]Block)
@@ -101,3 +103,4 @@ func (NopViewer) ViewWorldSpawn(cube.Pos)                                    {}
 func (NopViewer) ViewWeather(bool, bool)                                     {}
 func (NopViewer) ViewFurnaceUpdate(time.Duration, time.Duration, time.Duration, time.Duration, time.Duration, time.Duration) {
 }
+func (NopViewer) ViewBrewingUpdate(time.Duration, time.Duration, int32, int32, int32, int32) {}
@@ -62,6 +62,13 @@ func (w weather) ThunderingAt(pos cube.Pos) bool {
 	return a && w.w.highestObstructingBlock(pos[0], pos[2]) < pos[1]
 }
 
+// Thundering checks if it is currently thundering in the World.
+func (w weather) Thundering() bool {
+	w.w.set.Lock()
+	defer w.w.set.Unlock()
+	return w.w.set.Thundering && w.w.set.Raining
+}
+
 // StartRaining makes it rain in the World. The time.Duration passed will determine how long it will rain.
 func (w weather) StartRaining(dur time.Duration) {
 	w.w.set.Lock()
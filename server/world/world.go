@@ -46,6 +46,12 @@ type World struct {
 	closing chan struct{}
 	running sync.WaitGroup
 
+	// saveMu is held while chunks of the World are being written to the Provider, so that saving and unloading
+	// chunks never interleave.
+	saveMu sync.Mutex
+	// savingDisabled specifies if saving was disabled using SetSaving. While set, chunks are not unloaded.
+	savingDisabled atomic.Bool
+
 	chunkMu sync.Mutex
 	// chunks holds a cache of chunks currently loaded. These chunks are cleared from this map after some time
 	// of not being used.
@@ -65,8 +71,26 @@ type World struct {
 	scheduledUpdates map[cube.Pos]int64
 	neighbourUpdates []neighbourUpdate
 
+	toggleMu sync.Mutex
+	// redstoneToggles holds the ticks at which the toggles of redstone components recorded using
+	// RecordRedstoneToggle expire, indexed by the position of the component. Entries are removed once all
+	// toggles have expired.
+	redstoneToggles map[cube.Pos][]int64
+
 	viewersMu sync.Mutex
 	viewers   map[*Loader]Viewer
+
+	editMu sync.Mutex
+	// edits holds all Edits that are spread over multiple ticks and are not yet done.
+	edits []*Edit
+
+	taskMu sync.Mutex
+	// tasks holds functions scheduled using Exec that are run at the start of the next tick.
+	tasks []func()
+
+	// sleepTicks is the amount of consecutive ticks that enough Sleepers in
+	// the World have been sleeping to skip the night.
+	sleepTicks int
 }
 
 // New creates a new initialised world. The world may be used right away, but it will not be saved or loaded
@@ -103,6 +127,29 @@ func (w *World) Range() cube.Range {
 	return w.ra
 }
 
+// Exec schedules the function passed to be run at the start of the next tick of the World, on the goroutine
+// that ticks it. Functions are run in the order that they were scheduled in, so that, for example, two entities
+// travelling to the World cannot both create an exit portal at the same time. Exec does not wait for the
+// function to be run.
+func (w *World) Exec(f func()) {
+	w.taskMu.Lock()
+	defer w.taskMu.Unlock()
+	w.tasks = append(w.tasks, f)
+}
+
+// PortalDestination returns the World that a portal leading to the Dimension
+// passed takes entities to. If no destination World was configured, the World
+// itself is returned.
+func (w *World) PortalDestination(dim Dimension) *World {
+	if w.conf.PortalDestination == nil {
+		return w
+	}
+	if res := w.conf.PortalDestination(dim); res != nil {
+		return res
+	}
+	return w
+}
+
 // EntityRegistry returns the EntityRegistry that was passed to the World's
 // Config upon construction.
 func (w *World) EntityRegistry() EntityRegistry {
@@ -228,7 +275,7 @@ type SetOpts struct {
 // updated adequately.
 //
 // SetBlock should be avoided in situations where performance is critical when needing to set a lot of blocks
-// to the world. BuildStructure may be used instead.
+// to the world. BuildStructure or one of the region edits, such as Fill, may be used instead.
 func (w *World) SetBlock(pos cube.Pos, b Block, opts *SetOpts) {
 	if w == nil || pos.OutOfBounds(w.Range()) {
 		// Fast way out.
@@ -274,6 +321,14 @@ func (w *World) SetBlock(pos cube.Pos, b Block, opts *SetOpts) {
 				c.SetBlock(x, y, z, 1, before)
 				secondLayer = l
 			}
+		} else if li := c.Block(x, y, z, 1); li != airRID {
+			// The block previously at this position held a liquid. This liquid is removed if the new block is
+			// not able to hold it, such as when a waterlogged block is replaced with a full block.
+			l, _ := BlockByRuntimeID(li)
+			if !liquidDisplacingBlocks[rid] || !b.(LiquidDisplacer).CanDisplace(l.(Liquid)) {
+				c.SetBlock(x, y, z, 1, airRID)
+				secondLayer = air()
+			}
 		}
 		c.Unlock()
 
@@ -690,6 +745,7 @@ func (w *World) AddEntity(e Entity) {
 
 	c := w.chunk(chunkPos)
 	c.Entities = append(c.Entities, e)
+	c.modified = true
 	viewers := slices.Clone(c.viewers)
 	c.Unlock()
 
@@ -738,6 +794,7 @@ func (w *World) RemoveEntity(e Entity) {
 		return
 	}
 	c.Entities = sliceutil.DeleteVal(c.Entities, e)
+	c.modified = true
 	viewers := slices.Clone(c.viewers)
 	c.Unlock()
 
@@ -1007,6 +1064,62 @@ func (w *World) Close() {
 	w.o.Do(w.close)
 }
 
+// Save writes all chunks currently loaded that were changed since they were last saved, and the Settings of
+// the World, to its Provider. The World keeps ticking while it is saved: chunks are stored one by one, only
+// blocking changes to the chunk being stored. Save also writes data if saving was disabled using SetSaving, so
+// that a consistent copy of the files of the Provider may be made by disabling saving, calling Save and
+// copying the files once it returns. Save does nothing if the World is read-only or closed.
+func (w *World) Save() error {
+	if w == nil || w.conf.ReadOnly {
+		return nil
+	}
+	w.saveMu.Lock()
+	defer w.saveMu.Unlock()
+
+	select {
+	case <-w.closing:
+		// The World is closing or closed, which saves all chunks by itself.
+		return nil
+	default:
+	}
+
+	w.chunkMu.Lock()
+	toSave := maps.Clone(w.chunks)
+	w.chunkMu.Unlock()
+
+	var errs []error
+	for pos, c := range toSave {
+		c.Lock()
+		if err := w.storeColumn(pos, c); err != nil {
+			errs = append(errs, err)
+		}
+		c.Unlock()
+	}
+	if w.advance {
+		// The Settings are cloned so that the tick does not have to wait for the provider to save them.
+		w.provider().SaveSettings(w.set.clone())
+	}
+	return errors.Join(errs...)
+}
+
+// SetSaving enables or disables saving of the World. While saving is disabled, chunks are no longer unloaded
+// from memory, so that no data is written to the Provider unless Save or Close is called. This may be used to
+// make a backup of the files of the Provider while the World is running. SetSaving blocks until any chunks that
+// are being saved are written.
+func (w *World) SetSaving(enabled bool) {
+	if w == nil {
+		return
+	}
+	w.saveMu.Lock()
+	defer w.saveMu.Unlock()
+	w.savingDisabled.Store(!enabled)
+}
+
+// Saving returns whether saving of the World is enabled. Saving is enabled unless disabled using SetSaving.
+func (w *World) Saving() bool {
+	return w != nil && !w.savingDisabled.Load()
+}
+
 // close stops the World from ticking, saves all chunks to the Provider and updates the world's settings.
 func (w *World) close() {
 	// Let user code run anything that needs to be finished before the World is closed.
@@ -1016,8 +1129,13 @@ func (w *World) close() {
 	close(w.closing)
 	w.running.Wait()
 
+	w.finishEdits()
+
 	w.conf.Log.Debugf("Saving chunks in memory to disk...")
 
+	w.saveMu.Lock()
+	defer w.saveMu.Unlock()
+
 	w.chunkMu.Lock()
 	w.lastChunk = nil
 	toSave := maps.Clone(w.chunks)
@@ -1297,11 +1415,8 @@ func (w *World) spreadLight(pos ChunkPos) {
 // the provider.
 func (w *World) saveChunk(pos ChunkPos, c *Column) {
 	c.Lock()
-	if !w.conf.ReadOnly && (len(c.BlockEntities) > 0 || len(c.Entities) > 0 || c.modified) {
-		c.Compact()
-		if err := w.provider().StoreColumn(pos, w.conf.Dim, c); err != nil {
-			w.conf.Log.Errorf("save chunk: %v", err)
-		}
+	if err := w.storeColumn(pos, c); err != nil {
+		w.conf.Log.Errorf("save chunk: %v", err)
 	}
 	ent := c.Entities
 	c.Entities = nil
@@ -1312,6 +1427,31 @@ func (w *World) saveChunk(pos ChunkPos, c *Column) {
 	}
 }
 
+// storeColumn compacts the Column passed and writes it to the provider if it was modified since it was last
+// stored. Block entities implementing ChangeTracker that changed mark the Column as modified. The Column must
+// be locked.
+func (w *World) storeColumn(pos ChunkPos, c *Column) error {
+	if w.conf.ReadOnly {
+		return nil
+	}
+	for _, b := range c.BlockEntities {
+		// Changed is called for every block entity, so that none of them are reported as changed again the
+		// next time the Column is stored.
+		if t, ok := b.(ChangeTracker); ok && t.Changed() {
+			c.modified = true
+		}
+	}
+	if !c.modified {
+		return nil
+	}
+	c.Compact()
+	if err := w.provider().StoreColumn(pos, w.conf.Dim, c); err != nil {
+		return err
+	}
+	c.modified = false
+	return nil
+}
+
 // chunkCacheJanitor runs until the world is running, cleaning chunks that are no longer in use from the cache.
 func (w *World) chunkCacheJanitor() {
 	t := time.NewTicker(time.Minute * 5)
@@ -1322,6 +1462,13 @@ func (w *World) chunkCacheJanitor() {
 	for {
 		select {
 		case <-t.C:
+			w.saveMu.Lock()
+			if w.savingDisabled.Load() {
+				// Unloading chunks means writing them to the provider, which must not happen while saving is
+				// disabled.
+				w.saveMu.Unlock()
+				continue
+			}
 			w.chunkMu.Lock()
 			for pos, c := range w.chunks {
 				c.Lock()
@@ -1341,6 +1488,7 @@ func (w *World) chunkCacheJanitor() {
 				w.saveChunk(pos, c)
 				delete(chunksToRemove, pos)
 			}
+			w.saveMu.Unlock()
 		case <-w.closing:
 			w.running.Done()
 			return
@@ -1352,6 +1500,9 @@ func (w *World) chunkCacheJanitor() {
 // by the mutex present in the chunk.Chunk held.
 type Column struct {
 	sync.Mutex
+	// modified specifies if the blocks, block entities or entities of the Column were changed since it was
+	// last stored to the provider. Entities that are ticked and block entities implementing ChangeTracker
+	// that changed also mark the Column as modified.
 	modified bool
 
 	*chunk.Chunk
@@ -0,0 +1,229 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/stcraft/dragonfly/server/world"
+)
+
+// WorldSpec specifies how a world is loaded by Server.LoadWorld.
+type WorldSpec struct {
+	// Name is the name under which the world is loaded. It is used to obtain
+	// the world using Server.World after it has been loaded.
+	Name string
+	// Dir is the directory in which the world is stored. Dir may be anywhere on
+	// disk. If left empty, the world is stored in a directory named after the
+	// world in Config.WorldsFolder.
+	Dir string
+	// Dimension is the dimension of the world. If left as nil, Dimension will
+	// be set to world.Overworld.
+	Dimension world.Dimension
+	// Generator is the world.Generator used to generate new chunks in the
+	// world. If left as nil, the generator returned by Config.Generator for
+	// the Dimension is used.
+	Generator world.Generator
+	// ReadOnly specifies if the world should be opened in read only mode, so
+	// that its data is never saved.
+	ReadOnly bool
+	// Provider is the world.Provider used to load and save the data of the
+	// world. If set, Dir is ignored. If left as nil, the provider is created
+	// using Config.WorldProvider.
+	Provider world.Provider
+}
+
+// worldDir returns the directory in which the world with the WorldSpec passed
+// is stored. An error is returned if the spec has no Dir and its name is not a
+// valid world name.
+func (srv *Server) worldDir(spec WorldSpec) (string, error) {
+	if spec.Dir != "" {
+		return spec.Dir, nil
+	}
+	if err := validateWorldName(spec.Name); err != nil {
+		return "", err
+	}
+	return filepath.Join(srv.conf.WorldsFolder, spec.Name), nil
+}
+
+// validateWorldName checks if the name passed may be used as the name of a
+// world. Worlds are stored in a directory named after them in
+// Config.WorldsFolder, so a name must be a single, non-empty directory name
+// that can never refer to the WorldsFolder itself or to a directory outside of
+// it.
+func validateWorldName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("name must not be empty")
+	case name == "." || name == ".." || strings.ContainsAny(name, `/\`) || !filepath.IsLocal(name):
+		return fmt.Errorf("name %q must be a single directory name", name)
+	}
+	return nil
+}
+
+// DiscoverWorlds returns the names of all worlds stored in Config.WorldsFolder,
+// whether they are loaded or not. A directory is considered a world if it
+// holds a level.dat file. The names returned may be passed to
+// Server.LoadWorld.
+func (srv *Server) DiscoverWorlds() ([]string, error) {
+	entries, err := os.ReadDir(srv.conf.WorldsFolder)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("discover worlds: %w", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(srv.conf.WorldsFolder, entry.Name(), "level.dat")); err == nil {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// loadDiscoveredWorlds loads all worlds returned by Server.DiscoverWorlds that
+// are not yet loaded. Errors are logged instead of returned, so that a single
+// broken world does not prevent the server from starting.
+func (srv *Server) loadDiscoveredWorlds() {
+	names, err := srv.DiscoverWorlds()
+	if err != nil {
+		srv.conf.Log.Errorf("Error discovering worlds: %v", err)
+		return
+	}
+	for _, name := range names {
+		if srv.WorldExists(name) {
+			continue
+		}
+		if _, err := srv.LoadWorld(WorldSpec{Name: name, ReadOnly: srv.conf.ReadOnly}); err != nil {
+			srv.conf.Log.Errorf("Error loading world %v: %v", name, err)
+		}
+	}
+}
+
+// CreateWorld creates a new world using the WorldSpec passed and loads it. An
+// error is returned if a world is already stored in the directory of the
+// WorldSpec or if a world with the same name is already loaded.
+func (srv *Server) CreateWorld(spec WorldSpec) (*world.World, error) {
+	if err := validateWorldName(spec.Name); err != nil {
+		return nil, fmt.Errorf("create world: %w", err)
+	}
+	if spec.Provider == nil {
+		dir, err := srv.worldDir(spec)
+		if err != nil {
+			return nil, fmt.Errorf("create world %v: %w", spec.Name, err)
+		}
+		if _, err := os.Stat(dir); err == nil {
+			return nil, fmt.Errorf("create world %v: directory %v already exists", spec.Name, dir)
+		}
+	}
+	return srv.LoadWorld(spec)
+}
+
+// CopyWorld copies the world stored in the directory named src in
+// Config.WorldsFolder to a new directory named dst, so that it may be loaded
+// under the name dst. This may be used to create a fresh copy of a template
+// world, such as an arena for a single match. The world copied must not be
+// loaded, so that its data is not changed while it is copied.
+func (srv *Server) CopyWorld(src, dst string) error {
+	if err := validateWorldName(src); err != nil {
+		return fmt.Errorf("copy world: %w", err)
+	}
+	if err := validateWorldName(dst); err != nil {
+		return fmt.Errorf("copy world %v: %w", src, err)
+	}
+	if srv.WorldExists(src) {
+		return fmt.Errorf("copy world %v: world is loaded and must be unloaded first", src)
+	}
+	srcDir, dstDir := filepath.Join(srv.conf.WorldsFolder, src), filepath.Join(srv.conf.WorldsFolder, dst)
+	if _, err := os.Stat(dstDir); err == nil {
+		return fmt.Errorf("copy world %v: directory %v already exists", src, dstDir)
+	}
+	if err := copyDir(srcDir, dstDir); err != nil {
+		// Don't leave a partially copied world behind.
+		_ = os.RemoveAll(dstDir)
+		return fmt.Errorf("copy world %v: %w", src, err)
+	}
+	return nil
+}
+
+// DeleteWorld unloads the world with the name passed if it is loaded and
+// removes its directory from disk. Players in the world are moved to the
+// overworld. The default worlds of the server may not be deleted.
+func (srv *Server) DeleteWorld(name string) error {
+	if err := validateWorldName(name); err != nil {
+		return fmt.Errorf("delete world: %w", err)
+	}
+	if slices.Contains([]string{"overworld", "nether", "end"}, name) {
+		return fmt.Errorf("delete world %v: default worlds cannot be deleted", name)
+	}
+	srv.wmu.Lock()
+	dir, stored := srv.dirs[name]
+	_, loaded := srv.worlds[name]
+	srv.wmu.Unlock()
+
+	if !loaded {
+		dir, stored = filepath.Join(srv.conf.WorldsFolder, name), true
+		if _, err := os.Stat(dir); err != nil {
+			return fmt.Errorf("delete world %v: %w", name, err)
+		}
+	} else {
+		srv.UnloadWorld(name)
+	}
+	if !stored {
+		// The world had a custom provider and was not stored in a directory.
+		return nil
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("delete world %v: %w", name, err)
+	}
+	return nil
+}
+
+// copyDir recursively copies the directory src and all files in it to dst.
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0777)
+		}
+		if d.Name() == "LOCK" {
+			// The leveldb lock file is recreated when the database is opened.
+			return nil
+		}
+		return copyFile(path, target)
+	})
+}
+
+// copyFile copies the file at src to dst.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}